@@ -35,6 +35,7 @@ import (
 	"github.com/americanbinary/vpp/plugins/controller"
 	controller_api "github.com/americanbinary/vpp/plugins/controller/api"
 	"github.com/americanbinary/vpp/plugins/devicemanager"
+	"github.com/americanbinary/vpp/plugins/fileconfig"
 	contivgrpc "github.com/americanbinary/vpp/plugins/grpc"
 	"github.com/americanbinary/vpp/plugins/idalloc"
 	"github.com/americanbinary/vpp/plugins/ipam"
@@ -100,6 +101,7 @@ type ContivAgent struct {
 	Controller    *controller.Controller
 	ContivConf    *contivconf.ContivConf
 	ContivGRPC    *contivgrpc.Plugin
+	FileConfig    *fileconfig.Plugin
 	NodeSync      *nodesync.NodeSync
 	PodManager    *podmanager.PodManager
 	IDAlloc       *idalloc.API
@@ -165,6 +167,8 @@ func main() {
 		})
 	}))
 
+	fileConfig := &fileconfig.DefaultPlugin
+
 	nodeSyncPlugin := &nodesync.DefaultPlugin
 
 	podManager := &podmanager.DefaultPlugin
@@ -181,7 +185,9 @@ func main() {
 	ipamPlugin := ipam.NewPlugin(ipam.UseDeps(func(deps *ipam.Deps) {
 		deps.RemoteDB = &etcd.DefaultPlugin
 		deps.ContivConf = contivConf
+		deps.IDAlloc = idAllocPlugin
 		deps.NodeSync = nodeSyncPlugin
+		deps.PodManager = podManager
 	}))
 
 	ipNetPlugin := ipnet.NewPlugin(ipnet.UseDeps(func(deps *ipnet.Deps) {
@@ -247,7 +253,9 @@ func main() {
 		}
 		deps.ExtSources = []controller.ExternalConfigSource{
 			contivGRPC,
+			fileConfig,
 		}
+		deps.Stats = statsCollector
 	}))
 
 	contivConf.ContivAgentDeps = &contivconf.ContivAgentDeps{
@@ -256,11 +264,15 @@ func main() {
 	nodeSyncPlugin.EventLoop = controller
 	podManager.EventLoop = controller
 	ipamPlugin.EventLoop = controller
+	ipamPlugin.Stats = statsCollector
 	ipNetPlugin.EventLoop = controller
 	contivGRPC.EventLoop = controller
+	fileConfig.EventLoop = controller
 	deviceManager.EventLoop = controller
 	bgpReflector.EventLoop = controller
 	servicePlugin.ConfigRetriever = controller
+	servicePlugin.EventLoop = controller
+	policyPlugin.ServiceACL = servicePlugin
 	sfcPlugin.ConfigRetriever = controller
 
 	// initialize the agent
@@ -289,6 +301,7 @@ func main() {
 		Controller:          controller,
 		ContivConf:          contivConf,
 		ContivGRPC:          contivGRPC,
+		FileConfig:          fileConfig,
 		NodeSync:            nodeSyncPlugin,
 		PodManager:          podManager,
 		IPAM:                ipamPlugin,
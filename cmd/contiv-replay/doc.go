@@ -0,0 +1,32 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Contiv-replay replays the event history recorded by the Controller of a contiv-agent
+// (file or Bolt sink) offline, against the chain of Contiv event handlers with mocked
+// dependencies (no VPP, KVScheduler, Docker or remote DB).
+//
+// The initial startup resync can be run against the Kubernetes state given
+// by -kube-state, i.e. a JSON-encoded payload of a DBResync event (or a persisted
+// record of a DBResync event). Otherwise the first recorded DBResync is used.
+//
+// Transactions produced for every replayed event are written into the output file
+// (JSON-encoded), so that they can be compared with the transactions recorded
+// by the live agent.
+//
+// Usage:
+//
+//	contiv-replay -history <event-history.log> -node <node-name> \
+//	    [-history-sink file|bolt] [-kube-state <db-resync.json>] \
+//	    [-contiv-config <contiv.conf>] [-output <replayed-events.json>]
+package main
@@ -0,0 +1,305 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/namsral/flag"
+
+	"go.ligato.io/cn-infra/v2/config"
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	"github.com/americanbinary/vpp/mock/dockerclient"
+	"github.com/americanbinary/vpp/mock/govpp"
+	"github.com/americanbinary/vpp/mock/idalloc"
+	"github.com/americanbinary/vpp/mock/ifplugin"
+	"github.com/americanbinary/vpp/mock/servicelabel"
+	"github.com/americanbinary/vpp/plugins/contivconf"
+	contivconf_config "github.com/americanbinary/vpp/plugins/contivconf/config"
+	"github.com/americanbinary/vpp/plugins/controller"
+	controller_api "github.com/americanbinary/vpp/plugins/controller/api"
+	"github.com/americanbinary/vpp/plugins/controller/replay"
+	"github.com/americanbinary/vpp/plugins/ipam"
+	"github.com/americanbinary/vpp/plugins/ipnet"
+	"github.com/americanbinary/vpp/plugins/nodesync"
+	"github.com/americanbinary/vpp/plugins/podmanager"
+	"github.com/americanbinary/vpp/plugins/policy"
+	"github.com/americanbinary/vpp/plugins/service"
+	"github.com/americanbinary/vpp/plugins/sfc"
+)
+
+const (
+	defaultContivCfgFile = "/etc/contiv/contiv.conf"
+	defaultOutputFile    = "replayed-events.json"
+)
+
+var (
+	historyFile   = flag.String("history", "", "event history persisted by the Controller (required)")
+	historySink   = flag.String("history-sink", controller.FileHistorySink, "sink which persisted the event history (file or bolt)")
+	kubeStateFile = flag.String("kube-state", "", "Kubernetes state to run the initial startup resync against (JSON-encoded DBResync payload or record)")
+	nodeName      = flag.String("node", "", "name of the node where the events were recorded (required)")
+	contivCfgFile = flag.String("contiv-config", defaultContivCfgFile, "location of the Contiv config file used by the agent")
+	outputFile    = flag.String("output", defaultOutputFile, "file to write replayed events into")
+)
+
+var logger logging.Logger // global logger
+
+// init initializes the global logger
+func init() {
+	logger = logrus.NewLogger("contiv-replay")
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logging.InfoLevel)
+}
+
+// main replays persisted event history against the chain of Contiv event handlers
+// with mocked dependencies (no VPP, KVScheduler, Docker or remote DB) and writes
+// the resulting per-event transactions into the output file.
+// Other plugin configuration files can be passed using the same flags as for
+// the contiv-agent (e.g. -service-config, -policy-config, -sfc-config).
+func main() {
+	replayer := replay.NewReplayer(logger)
+	chain := newHandlerChain(replayer)
+
+	flag.Parse()
+	if *historyFile == "" || *nodeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(replayer, chain); err != nil {
+		logger.Error(err)
+		os.Exit(1)
+	}
+}
+
+// handlerChain groups plugins of the replayed event handler chain.
+type handlerChain struct {
+	serviceLabel *servicelabel.MockServiceLabel
+
+	contivConf *contivconf.ContivConf
+	nodeSync   *nodesync.NodeSync
+	podManager *podmanager.PodManager
+	ipam       *ipam.IPAM
+	ipNet      *ipnet.IPNet
+	service    *service.Plugin
+	sfc        *sfc.Plugin
+	policy     *policy.Plugin
+}
+
+// newHandlerChain constructs the event handlers in the same order
+// as in the contiv-agent, with external dependencies replaced by mocks.
+// Replayer is used in place of the Controller.
+func newHandlerChain(replayer *replay.Replayer) *handlerChain {
+	serviceLabel := servicelabel.NewMockServiceLabel()
+	goVPP := &offlineGoVPP{mock: govpp.NewMockGoVPP()}
+	remoteDB := &offlineDB{}
+
+	contivConf := contivconf.NewPlugin(contivconf.UseDeps(func(deps *contivconf.Deps) {
+		deps.ServiceLabel = serviceLabel
+		deps.ContivAgentDeps = &contivconf.ContivAgentDeps{
+			EventLoop: replayer,
+		}
+	}))
+
+	nodeSyncPlugin := nodesync.NewPlugin(nodesync.UseDeps(func(deps *nodesync.Deps) {
+		deps.ServiceLabel = serviceLabel
+		deps.EventLoop = replayer
+		deps.DB = remoteDB
+	}))
+
+	dockerClient := dockerclient.NewMockDockerClient()
+	dockerClient.Connect()
+	podManager := podmanager.NewPlugin(podmanager.UseDeps(func(deps *podmanager.Deps) {
+		deps.EventLoop = replayer
+		deps.GRPC = nil
+		deps.UnitTestDeps = &podmanager.UnitTestDeps{
+			DockerClient: dockerClient,
+		}
+	}))
+
+	ipamPlugin := ipam.NewPlugin(ipam.UseDeps(func(deps *ipam.Deps) {
+		deps.RemoteDB = remoteDB
+		deps.ContivConf = contivConf
+		deps.NodeSync = nodeSyncPlugin
+		deps.PodManager = podManager
+		deps.ServiceLabel = serviceLabel
+		deps.EventLoop = replayer
+		deps.HTTPHandlers = nil
+	}))
+
+	ipNetPlugin := ipnet.NewPlugin(ipnet.UseDeps(func(deps *ipnet.Deps) {
+		deps.RemoteDB = remoteDB
+		deps.GoVPP = goVPP
+		deps.VPPIfPlugin = ifplugin.NewMockVppPlugin()
+		deps.ContivConf = contivConf
+		deps.IPAM = ipamPlugin
+		deps.NodeSync = nodeSyncPlugin
+		deps.PodManager = podManager
+		deps.ServiceLabel = serviceLabel
+		deps.EventLoop = replayer
+		deps.HTTPHandlers = nil
+		deps.UnitTestDeps = &ipnet.UnitTestDeps{}
+	}))
+
+	servicePlugin := service.NewPlugin(service.UseDeps(func(deps *service.Deps) {
+		deps.ServiceLabel = serviceLabel
+		deps.ContivConf = contivConf
+		deps.IPAM = ipamPlugin
+		deps.IPNet = ipNetPlugin
+		deps.NodeSync = nodeSyncPlugin
+		deps.PodManager = podManager
+		deps.GoVPP = goVPP
+		deps.Stats = nil
+		deps.ConfigRetriever = replayer
+		deps.EventLoop = replayer
+		deps.HTTPHandlers = nil
+	}))
+
+	sfcPlugin := sfc.NewPlugin(sfc.UseDeps(func(deps *sfc.Deps) {
+		deps.ServiceLabel = serviceLabel
+		deps.ContivConf = contivConf
+		deps.IPAM = ipamPlugin
+		deps.IPNet = ipNetPlugin
+		deps.NodeSync = nodeSyncPlugin
+		deps.PodManager = podManager
+		deps.GoVPP = goVPP
+		deps.Stats = nil
+		deps.ConfigRetriever = replayer
+	}))
+
+	policyPlugin := policy.NewPlugin(policy.UseDeps(func(deps *policy.Deps) {
+		deps.ServiceLabel = serviceLabel
+		deps.ContivConf = contivConf
+		deps.IPAM = ipamPlugin
+		deps.IPNet = ipNetPlugin
+		deps.PodManager = podManager
+		deps.GoVPP = nil
+		deps.VPPACLPlugin = nil
+		deps.Stats = nil
+		deps.HTTPHandlers = nil
+	}))
+
+	return &handlerChain{
+		serviceLabel: serviceLabel,
+		contivConf:   contivConf,
+		nodeSync:     nodeSyncPlugin,
+		podManager:   podManager,
+		ipam:         ipamPlugin,
+		ipNet:        ipNetPlugin,
+		service:      servicePlugin,
+		sfc:          sfcPlugin,
+		policy:       policyPlugin,
+	}
+}
+
+// init initializes the plugins of the chain and returns them as event handlers.
+func (c *handlerChain) init(nodeName string, contivCfg *contivconf_config.Config) ([]controller_api.EventHandler, error) {
+	c.serviceLabel.SetAgentLabel(nodeName)
+	idAlloc := idalloc.NewMockIDAllocator(nodeName)
+	c.ipam.IDAlloc = idAlloc
+	c.ipNet.IDAlloc = idAlloc
+	c.sfc.IDAlloc = idAlloc
+	c.contivConf.UnitTestDeps = &contivconf.UnitTestDeps{
+		Config: contivCfg,
+	}
+	plugins := []interface {
+		controller_api.EventHandler
+		Init() error
+	}{
+		c.contivConf,
+		c.nodeSync,
+		c.podManager,
+		c.ipam,
+		c.ipNet,
+		c.service,
+		c.sfc,
+		c.policy,
+	}
+	var handlers []controller_api.EventHandler
+	for _, plugin := range plugins {
+		if err := plugin.Init(); err != nil {
+			return nil, fmt.Errorf("failed to init plugin %s: %v", plugin.String(), err)
+		}
+		handlers = append(handlers, plugin)
+	}
+	return handlers, nil
+}
+
+// run replays the event history and writes the replayed events into the output file.
+func run(replayer *replay.Replayer, chain *handlerChain) error {
+	contivCfg := &contivconf_config.Config{}
+	if err := config.ParseConfigFromYamlFile(*contivCfgFile, contivCfg); err != nil {
+		return fmt.Errorf("failed to parse Contiv config file: %v", err)
+	}
+	eventHandlers, err := chain.init(*nodeName, contivCfg)
+	if err != nil {
+		return err
+	}
+	replayer.SetEventHandlers(eventHandlers)
+
+	records, err := readEventHistory()
+	if err != nil {
+		return fmt.Errorf("failed to read event history: %v", err)
+	}
+	var kubeState controller_api.KubeStateData
+	if *kubeStateFile != "" {
+		if kubeState, err = readKubeState(); err != nil {
+			return fmt.Errorf("failed to read Kubernetes state: %v", err)
+		}
+	}
+	logger.Infof("Replaying %d recorded events", len(records))
+
+	replayed := replayer.Replay(kubeState, records)
+
+	output, err := os.Create(*outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	if err := replay.WriteReplayedEvents(output, replayed); err != nil {
+		return err
+	}
+	logger.Infof("Replayed events written into %s", *outputFile)
+	return nil
+}
+
+// readEventHistory reads the event history persisted by the selected sink.
+func readEventHistory() ([]*controller.PersistedEventRecord, error) {
+	switch *historySink {
+	case controller.FileHistorySink:
+		history, err := os.Open(*historyFile)
+		if err != nil {
+			return nil, err
+		}
+		defer history.Close()
+		return replay.ReadEventHistory(history)
+	case controller.BoltHistorySink:
+		return controller.ReadBoltEventHistory(*historyFile)
+	default:
+		return nil, fmt.Errorf("unsupported event history sink: %s", *historySink)
+	}
+}
+
+// readKubeState reads Kubernetes state for the initial startup resync.
+func readKubeState() (controller_api.KubeStateData, error) {
+	input, err := os.Open(*kubeStateFile)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	return replay.ReadKubeState(input)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.ligato.io/cn-infra/v2/datasync"
+	"go.ligato.io/cn-infra/v2/db/keyval"
+	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
+
+	"github.com/americanbinary/vpp/mock/govpp"
+)
+
+// errRemoteDBNotAvailable is returned by all operations of the offline remote DB.
+var errRemoteDBNotAvailable = errors.New("remote database is not available in the replay")
+
+// offlineGoVPP implements govppmux.API with channels that do not connect to VPP.
+// Methods other than those for channel creation are not supported.
+type offlineGoVPP struct {
+	govppmux.API
+	mock *govpp.MockGoVPP
+}
+
+// NewAPIChannel returns a new mock API channel.
+func (g *offlineGoVPP) NewAPIChannel() (govppapi.Channel, error) {
+	return g.mock.NewAPIChannel()
+}
+
+// NewAPIChannelBuffered returns a new mock API channel.
+func (g *offlineGoVPP) NewAPIChannelBuffered(reqChanBufSize, replyChanBufSize int) (govppapi.Channel, error) {
+	return g.mock.NewAPIChannelBuffered(reqChanBufSize, replyChanBufSize)
+}
+
+// offlineDB is a remote DB which never gets connected.
+// The state otherwise read from the remote DB is given by the replayed resync events.
+type offlineDB struct {
+	keyval.KvProtoPlugin
+}
+
+// OnConnect never calls the callback - the DB never gets connected.
+func (db *offlineDB) OnConnect(callback func() error) {
+}
+
+// Disabled returns false.
+func (db *offlineDB) Disabled() bool {
+	return false
+}
+
+// String returns the plugin name.
+func (db *offlineDB) String() string {
+	return "offline-remote-db"
+}
+
+// Close is a no-op.
+func (db *offlineDB) Close() error {
+	return nil
+}
+
+// NewBrokerWithAtomic returns broker which fails all atomic operations.
+func (db *offlineDB) NewBrokerWithAtomic(keyPrefix string) keyval.BytesBrokerWithAtomic {
+	return &offlineBroker{}
+}
+
+// offlineBroker fails every operation used by the replayed plugins.
+type offlineBroker struct {
+	keyval.BytesBrokerWithAtomic
+}
+
+// PutIfNotExists returns errRemoteDBNotAvailable.
+func (b *offlineBroker) PutIfNotExists(key string, data []byte) (succeeded bool, err error) {
+	return false, errRemoteDBNotAvailable
+}
+
+// CompareAndSwap returns errRemoteDBNotAvailable.
+func (b *offlineBroker) CompareAndSwap(key string, oldData, newData []byte, opts ...datasync.PutOption) (succeeded bool, err error) {
+	return false, errRemoteDBNotAvailable
+}
+
+// CompareAndDelete returns errRemoteDBNotAvailable.
+func (b *offlineBroker) CompareAndDelete(key string, data []byte, opts ...datasync.DelOption) (succeeded bool, err error) {
+	return false, errRemoteDBNotAvailable
+}
+
+// GetValue returns errRemoteDBNotAvailable.
+func (b *offlineBroker) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	return nil, false, 0, errRemoteDBNotAvailable
+}
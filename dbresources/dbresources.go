@@ -27,6 +27,7 @@ import (
 	nodeconfig "github.com/americanbinary/vpp/plugins/crd/handler/nodeconfig/model"
 	sfcmodel "github.com/americanbinary/vpp/plugins/crd/handler/servicefunctionchain/model"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
 	nsmodel "github.com/americanbinary/vpp/plugins/ksr/model/namespace"
 	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
@@ -82,6 +83,11 @@ func GetDBResources() []*DBResource {
 			ProtoMessageName: proto.MessageName((*policymodel.Policy)(nil)),
 			KeyPrefix:        policymodel.KeyPrefix(),
 		},
+		{
+			Keyword:          policymodel.ClusterPolicyKeyword,
+			ProtoMessageName: proto.MessageName((*policymodel.ClusterPolicy)(nil)),
+			KeyPrefix:        policymodel.ClusterPolicyKeyPrefix(),
+		},
 		{
 			Keyword:          svcmodel.ServiceKeyword,
 			ProtoMessageName: proto.MessageName((*svcmodel.Service)(nil)),
@@ -92,6 +98,11 @@ func GetDBResources() []*DBResource {
 			ProtoMessageName: proto.MessageName((*epmodel.Endpoints)(nil)),
 			KeyPrefix:        epmodel.KeyPrefix(),
 		},
+		{
+			Keyword:          epslicemodel.EndpointSliceKeyword,
+			ProtoMessageName: proto.MessageName((*epslicemodel.EndpointSlice)(nil)),
+			KeyPrefix:        epslicemodel.KeyPrefix(),
+		},
 		{
 			Keyword:          customnetmodel.Keyword,
 			ProtoMessageName: proto.MessageName((*customnetmodel.CustomNetwork)(nil)),
@@ -112,6 +123,11 @@ func GetDBResources() []*DBResource {
 			ProtoMessageName: proto.MessageName((*ipalloc.CustomIPAllocation)(nil)),
 			KeyPrefix:        ipalloc.KeyPrefix(),
 		},
+		{
+			Keyword:          ipalloc.ReservationKeyword,
+			ProtoMessageName: proto.MessageName((*ipalloc.IPReservation)(nil)),
+			KeyPrefix:        ipalloc.ReservationKeyPrefix(),
+		},
 		{
 			Keyword:          idallocation.Keyword,
 			ProtoMessageName: proto.MessageName((*idallocation.AllocationPool)(nil)),
@@ -5,12 +5,14 @@ go 1.20
 require (
 	git.fd.io/govpp.git v0.3.1
 	github.com/apparentlymart/go-cidr v0.0.0-20170616213631-2bd8b58cf427
+	github.com/boltdb/bolt v1.3.2-0.20180302180052-fd01fc79c553
 	github.com/containernetworking/cni v0.7.1
 	github.com/containernetworking/plugins v0.7.5
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/fsouza/go-dockerclient v1.2.2
 	github.com/ghodss/yaml v1.0.0
 	github.com/go-errors/errors v1.0.1
-	github.com/golang/protobuf v1.3.3
+	github.com/golang/protobuf v1.5.0
 	github.com/namsral/flag v1.7.4-pre
 	github.com/onsi/gomega v1.7.0
 	github.com/pkg/errors v0.9.1
@@ -23,15 +25,14 @@ require (
 	github.com/vishvananda/netlink v1.0.1-0.20190319163122-f504738125a5
 	go.ligato.io/cn-infra/v2 v2.5.0-alpha.0.20200313154441-b0d4c1b11c73
 	go.ligato.io/vpp-agent/v3 v3.1.0
-	golang.org/x/net v0.0.0-20200226121028-0de0cce0169b
-	golang.org/x/sys v0.0.0-20200317113312-5766fd39f98d
+	golang.org/x/net v0.0.0-20211209124913-491a49abca63
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1
 	google.golang.org/grpc v1.27.1
-	k8s.io/api v0.17.1
+	k8s.io/api v0.21.14
 	k8s.io/apiextensions-apiserver v0.0.0
-	k8s.io/apimachinery v0.17.1
-	k8s.io/client-go v11.0.0+incompatible
+	k8s.io/apimachinery v0.21.14
+	k8s.io/client-go v0.21.14
 	k8s.io/kubelet v0.0.0
-	k8s.io/kubernetes v1.17.1
 )
 
 require (
@@ -39,7 +40,6 @@ require (
 	github.com/Microsoft/go-winio v0.4.12 // indirect
 	github.com/Nvveen/Gotty v0.0.0-20120604004816-cd527374f1e5 // indirect
 	github.com/beorn7/perks v1.0.0 // indirect
-	github.com/boltdb/bolt v1.3.2-0.20180302180052-fd01fc79c553 // indirect
 	github.com/bshuster-repo/logrus-logstash-hook v0.4.1 // indirect
 	github.com/containerd/continuity v0.0.0-20181203112020-004b46473808 // indirect
 	github.com/coreos/etcd v3.3.13+incompatible // indirect
@@ -51,21 +51,21 @@ require (
 	github.com/docker/go-units v0.4.0 // indirect
 	github.com/docker/libnetwork v0.8.0-dev.2.0.20190624125649-f0e46a78ea34 // indirect
 	github.com/evalphobia/logrus_fluent v0.4.0 // indirect
-	github.com/evanphx/json-patch v4.5.0+incompatible // indirect
+	github.com/evanphx/json-patch v4.9.0+incompatible // indirect
 	github.com/fluent/fluent-logger-golang v1.4.0 // indirect
-	github.com/fsnotify/fsnotify v1.4.7 // indirect
 	github.com/ftrvxmtrx/fd v0.0.0-20150925145434-c6d800382fff // indirect
-	github.com/gogo/protobuf v1.2.2-0.20190723190241-65acae22fc9d // indirect
-	github.com/google/go-cmp v0.3.1 // indirect
-	github.com/google/gofuzz v1.0.0 // indirect
-	github.com/googleapis/gnostic v0.2.0 // indirect
+	github.com/go-logr/logr v0.4.0 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/google/go-cmp v0.5.5 // indirect
+	github.com/google/gofuzz v1.1.0 // indirect
+	github.com/googleapis/gnostic v0.4.1 // indirect
 	github.com/gorilla/mux v1.7.1 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.0.1-0.20190118093823-f849b5445de4 // indirect
 	github.com/grpc-ecosystem/go-grpc-prometheus v1.2.0 // indirect
 	github.com/hashicorp/golang-lru v0.5.1 // indirect
 	github.com/imdario/mergo v0.3.7 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
-	github.com/json-iterator/go v1.1.8 // indirect
+	github.com/json-iterator/go v1.1.10 // indirect
 	github.com/konsorten/go-windows-terminal-sequences v1.0.2 // indirect
 	github.com/lunixbochs/struc v0.0.0-20190916212049-a5c72983bc42 // indirect
 	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
@@ -83,44 +83,49 @@ require (
 	github.com/tinylib/msgp v1.1.0 // indirect
 	github.com/vishvananda/netns v0.0.0-20180720170159-13995c7128cc // indirect
 	golang.org/x/crypto v0.0.0-20191206172530-e9b2fee46413 // indirect
-	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45 // indirect
-	golang.org/x/text v0.3.2 // indirect
-	golang.org/x/time v0.0.0-20190921001708-c4c64cad1fd0 // indirect
-	google.golang.org/appengine v1.5.0 // indirect
-	google.golang.org/genproto v0.0.0-20190819201941-24fa4b261c55 // indirect
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d // indirect
+	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d // indirect
+	golang.org/x/text v0.3.6 // indirect
+	golang.org/x/time v0.0.0-20210220033141-f8bda1e9f3ba // indirect
+	google.golang.org/appengine v1.6.5 // indirect
+	google.golang.org/genproto v0.0.0-20201110150050-8816d57aaa9a // indirect
+	google.golang.org/protobuf v1.26.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
-	gopkg.in/yaml.v2 v2.2.4 // indirect
-	k8s.io/klog v1.0.0 // indirect
-	k8s.io/kube-openapi v0.0.0-20191107075043-30be4d16710a // indirect
-	k8s.io/utils v0.0.0-20191114184206-e782cd3c129f // indirect
-	sigs.k8s.io/yaml v1.1.0 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+	k8s.io/klog/v2 v2.9.0 // indirect
+	k8s.io/kube-openapi v0.0.0-20211110012726-3cc51fd1e909 // indirect
+	k8s.io/utils v0.0.0-20211116205334-6203023598ed // indirect
+	sigs.k8s.io/structured-merge-diff/v4 v4.2.1 // indirect
+	sigs.k8s.io/yaml v1.2.0 // indirect
 )
 
 replace (
-	k8s.io/api => k8s.io/api v0.17.1
-	k8s.io/apiextensions-apiserver => k8s.io/apiextensions-apiserver v0.17.1
-	k8s.io/apimachinery => k8s.io/apimachinery v0.17.1
-	k8s.io/apiserver => k8s.io/apiserver v0.17.1
-	k8s.io/cli-runtime => k8s.io/cli-runtime v0.17.1
-	k8s.io/client-go => k8s.io/client-go v0.17.1
-	k8s.io/cloud-provider => k8s.io/cloud-provider v0.17.1
-	k8s.io/cluster-bootstrap => k8s.io/cluster-bootstrap v0.17.1
-	k8s.io/code-generator => k8s.io/code-generator v0.17.1
-	k8s.io/component-base => k8s.io/component-base v0.17.1
-	k8s.io/cri-api => k8s.io/cri-api v0.17.1
-	k8s.io/csi-translation-lib => k8s.io/csi-translation-lib v0.17.1
-	k8s.io/kube-aggregator => k8s.io/kube-aggregator v0.17.1
-	k8s.io/kube-controller-manager => k8s.io/kube-controller-manager v0.17.1
-	k8s.io/kube-proxy => k8s.io/kube-proxy v0.17.1
-	k8s.io/kube-scheduler => k8s.io/kube-scheduler v0.17.1
-	k8s.io/kubectl => k8s.io/kubectl v0.17.1
-	k8s.io/kubelet => k8s.io/kubelet v0.17.1
-	k8s.io/legacy-cloud-providers => k8s.io/legacy-cloud-providers v0.17.1
-	k8s.io/metrics => k8s.io/metrics v0.17.1
-	k8s.io/node-api => k8s.io/node-api v0.17.1
-	k8s.io/sample-apiserver => k8s.io/sample-apiserver v0.17.1
-	k8s.io/sample-cli-plugin => k8s.io/sample-cli-plugin v0.17.1
-	k8s.io/sample-controller => k8s.io/sample-controller v0.17.1
+	k8s.io/api => k8s.io/api v0.21.14
+	k8s.io/apiextensions-apiserver => k8s.io/apiextensions-apiserver v0.21.14
+	k8s.io/apimachinery => k8s.io/apimachinery v0.21.14
+	k8s.io/apiserver => k8s.io/apiserver v0.21.14
+	k8s.io/cli-runtime => k8s.io/cli-runtime v0.21.14
+	k8s.io/client-go => k8s.io/client-go v0.21.14
+	k8s.io/cloud-provider => k8s.io/cloud-provider v0.21.14
+	k8s.io/cluster-bootstrap => k8s.io/cluster-bootstrap v0.21.14
+	k8s.io/code-generator => k8s.io/code-generator v0.21.14
+	k8s.io/component-base => k8s.io/component-base v0.21.14
+	k8s.io/component-helpers => k8s.io/component-helpers v0.21.14
+	k8s.io/controller-manager => k8s.io/controller-manager v0.21.14
+	k8s.io/cri-api => k8s.io/cri-api v0.21.14
+	k8s.io/csi-translation-lib => k8s.io/csi-translation-lib v0.21.14
+	k8s.io/kube-aggregator => k8s.io/kube-aggregator v0.21.14
+	k8s.io/kube-controller-manager => k8s.io/kube-controller-manager v0.21.14
+	k8s.io/kube-proxy => k8s.io/kube-proxy v0.21.14
+	k8s.io/kube-scheduler => k8s.io/kube-scheduler v0.21.14
+	k8s.io/kubectl => k8s.io/kubectl v0.21.14
+	k8s.io/kubelet => k8s.io/kubelet v0.21.14
+	k8s.io/legacy-cloud-providers => k8s.io/legacy-cloud-providers v0.21.14
+	k8s.io/metrics => k8s.io/metrics v0.21.14
+	k8s.io/mount-utils => k8s.io/mount-utils v0.21.14
+	k8s.io/sample-apiserver => k8s.io/sample-apiserver v0.21.14
+	k8s.io/sample-cli-plugin => k8s.io/sample-cli-plugin v0.21.14
+	k8s.io/sample-controller => k8s.io/sample-controller v0.21.14
 )
 
 // fix compatibility issue in containernetworking
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/golang/protobuf/proto"
 	"go.ligato.io/cn-infra/v2/logging"
 
 	"github.com/americanbinary/vpp/mock/localclient"
@@ -80,8 +81,9 @@ type MockACLEngine struct {
 	IPNet      ipnet.API     /* for GetIfName(), GetVxlanBVIIfName() */
 	ContivConf ContivConfAPI /* for GetMainInterfaceName() */
 
-	pods      map[podmodel.ID]*PodConfig
-	aclConfig *ACLConfig
+	pods            map[podmodel.ID]*PodConfig
+	aclConfig       *ACLConfig
+	countersEnabled bool
 }
 
 // PodConfig encapsulates pod configuration.
@@ -94,6 +96,7 @@ type PodConfig struct {
 type ACLConfig struct {
 	byName  map[string]*vpp_acl.ACL
 	byIf    map[string]*InterfaceACLs
+	hits    map[string][]uint64 // hit counter for every rule
 	changes int
 }
 
@@ -119,6 +122,7 @@ func NewACLConfig() *ACLConfig {
 	return &ACLConfig{
 		byName: make(map[string]*vpp_acl.ACL),
 		byIf:   make(map[string]*InterfaceACLs),
+		hits:   make(map[string][]uint64),
 	}
 }
 
@@ -263,6 +267,26 @@ func (mae *MockACLEngine) GetACLByName(aclName string) *vpp_acl.ACL {
 	return acl
 }
 
+// EnableCounters enables or disables counting of the rule hits.
+func (mae *MockACLEngine) EnableCounters(enable bool) error {
+	mae.Lock()
+	defer mae.Unlock()
+	mae.countersEnabled = enable
+	return nil
+}
+
+// GetACLRuleHits returns the number of packets matched by each rule of the given ACL
+// during the simulated connections (with the counters enabled).
+func (mae *MockACLEngine) GetACLRuleHits(aclName string) ([]uint64, error) {
+	mae.Lock()
+	defer mae.Unlock()
+	hits, hasACL := mae.aclConfig.hits[aclName]
+	if !hasACL {
+		return nil, fmt.Errorf("cannot find ACL: %s", aclName)
+	}
+	return append([]uint64{}, hits...), nil
+}
+
 // GetNumOfACLChanges returns the number of ACL changes (Put+Delete).
 func (mae *MockACLEngine) GetNumOfACLChanges() int {
 	return mae.aclConfig.changes
@@ -507,7 +531,7 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 		return ACLActionPermit
 	}
 
-	for _, rule := range acl.Rules {
+	for ruleIdx, rule := range acl.Rules {
 		if rule.MacipRule != nil {
 			// unsupported
 			mae.Log.WithField("acl", *acl).Error("MAC-IP rules are not supported")
@@ -558,6 +582,12 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 			}
 		}
 
+		// check IP protocol number (set for SCTP and other protocols matched without ports)
+		if ipRule.Ip.Protocol != 0 && ipRule.Ip.Protocol != ipProtocolNumber(protocol) {
+			// not matching
+			continue
+		}
+
 		// check L4
 		switch protocol {
 		case renderer.TCP:
@@ -624,7 +654,7 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 				}
 			}
 
-		case renderer.OTHER:
+		case renderer.SCTP, renderer.OTHER:
 			if ipRule.Tcp != nil || ipRule.Udp != nil {
 				// not matching
 				continue
@@ -636,6 +666,9 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 			"rule": *rule,
 			"acl":  acl.Name,
 		}).Debug("Connection matched by ACL rule")
+		if hits, hasHits := mae.aclConfig.hits[acl.Name]; hasHits && mae.countersEnabled {
+			hits[ruleIdx]++
+		}
 		switch rule.Action {
 		case vpp_acl.ACL_Rule_DENY:
 			return ACLActionDeny
@@ -651,6 +684,21 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 	return ACLActionDeny /* deny is the default action */
 }
 
+// ipProtocolNumber returns IANA-assigned IP protocol number for the given protocol type.
+// Connections simulated with the protocol OTHER do not carry any specific protocol
+// number and therefore match only rules without protocol number.
+func ipProtocolNumber(protocol renderer.ProtocolType) uint32 {
+	switch protocol {
+	case renderer.TCP:
+		return 6
+	case renderer.UDP:
+		return 17
+	case renderer.SCTP:
+		return 132
+	}
+	return 0
+}
+
 // GetACLs returns ACLs assigned to the given interface.
 func (ac *ACLConfig) GetACLs(ifName string) *InterfaceACLs {
 	acls, hasACL := ac.byIf[ifName]
@@ -667,6 +715,7 @@ func (ac *ACLConfig) DelACL(aclName string) error {
 		return fmt.Errorf("cannot find ACL: %s", aclName)
 	}
 	delete(ac.byName, aclName)
+	delete(ac.hits, aclName)
 	for _, aclCfg := range ac.byIf {
 		if aclCfg.inbound != nil && aclCfg.inbound.Name == aclName {
 			aclCfg.inbound = nil
@@ -688,13 +737,19 @@ func (ac *ACLConfig) PutACL(acl *vpp_acl.ACL) error {
 		(len(acl.Interfaces.Ingress) == 0 && len(acl.Interfaces.Egress) == 0) {
 		return errors.New("ACL with empty interfaces")
 	}
-	_, hasACL := ac.byName[acl.Name]
+	hits := make([]uint64, len(acl.Rules))
+	origACL, hasACL := ac.byName[acl.Name]
 	if hasACL {
+		if sameACLRules(origACL, acl) {
+			// counters are preserved when only interfaces change
+			hits = ac.hits[acl.Name]
+		}
 		// del origin ACL first
 		ac.DelACL(acl.Name)
 		ac.changes--
 	}
 	ac.byName[acl.Name] = acl
+	ac.hits[acl.Name] = hits
 	for _, ifName := range acl.Interfaces.Ingress {
 		if _, hasACLCfg := ac.byIf[ifName]; !hasACLCfg {
 			ac.byIf[ifName] = &InterfaceACLs{}
@@ -710,3 +765,16 @@ func (ac *ACLConfig) PutACL(acl *vpp_acl.ACL) error {
 	ac.changes++
 	return nil
 }
+
+// sameACLRules returns true if both ACLs have the same list of rules.
+func sameACLRules(acl1, acl2 *vpp_acl.ACL) bool {
+	if len(acl1.Rules) != len(acl2.Rules) {
+		return false
+	}
+	for i := range acl1.Rules {
+		if !proto.Equal(acl1.Rules[i], acl2.Rules[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package idalloc
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/americanbinary/vpp/plugins/idalloc/idallocation"
+)
+
+// MockIDAllocator is a mock implementation of the IDAllocator plugin, keeping
+// the allocation pools in memory.
+type MockIDAllocator struct {
+	owner string
+	pools map[string]*idallocation.AllocationPool
+}
+
+// NewMockIDAllocator is a constructor for MockIDAllocator.
+// Allocations are made on behalf of the given owner (agent label).
+func NewMockIDAllocator(owner string) *MockIDAllocator {
+	return &MockIDAllocator{
+		owner: owner,
+		pools: make(map[string]*idallocation.AllocationPool),
+	}
+}
+
+// InitPool initializes ID allocation pool with given name and ID range.
+func (m *MockIDAllocator) InitPool(name string, poolRange *idallocation.AllocationPool_Range) (err error) {
+	if pool, exists := m.pools[name]; exists {
+		if !proto.Equal(pool.Range, poolRange) {
+			return fmt.Errorf("ID pool %s already exists with different specification", name)
+		}
+		return nil
+	}
+	m.pools[name] = &idallocation.AllocationPool{
+		Name:          name,
+		Range:         poolRange,
+		IdAllocations: map[string]*idallocation.AllocationPool_Allocation{},
+	}
+	return nil
+}
+
+// GetOrAllocateID returns allocated ID in given pool for given label. If the ID was
+// not already allocated, allocates the lowest available ID.
+func (m *MockIDAllocator) GetOrAllocateID(poolName string, idLabel string) (id uint32, err error) {
+	pool, exists := m.pools[poolName]
+	if !exists {
+		return 0, fmt.Errorf("ID pool %s does not exist", poolName)
+	}
+	if alloc, allocated := pool.IdAllocations[idLabel]; allocated {
+		return alloc.Id, nil
+	}
+	used := make(map[uint32]bool)
+	for _, alloc := range pool.IdAllocations {
+		used[alloc.Id] = true
+	}
+	for _, reserved := range pool.Range.Reserved {
+		used[reserved] = true
+	}
+	for id = pool.Range.MinId; id <= pool.Range.MaxId; id++ {
+		if !used[id] {
+			pool.IdAllocations[idLabel] = &idallocation.AllocationPool_Allocation{
+				Id:    id,
+				Owner: m.owner,
+			}
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("no more space left in pool %s", poolName)
+}
+
+// ReleaseID releases existing allocation owned by this mock for given pool and label.
+func (m *MockIDAllocator) ReleaseID(poolName string, idLabel string) (err error) {
+	if pool, exists := m.pools[poolName]; exists {
+		if alloc, allocated := pool.IdAllocations[idLabel]; allocated && alloc.Owner == m.owner {
+			delete(pool.IdAllocations, idLabel)
+		}
+	}
+	return nil
+}
+
+// ForceReleaseID releases existing allocation for given pool and label regardless of the owner.
+func (m *MockIDAllocator) ForceReleaseID(poolName string, idLabel string) (err error) {
+	if pool, exists := m.pools[poolName]; exists {
+		delete(pool.IdAllocations, idLabel)
+	}
+	return nil
+}
+
+// SetAllocation allows to simulate allocation made by another agent.
+func (m *MockIDAllocator) SetAllocation(poolName string, idLabel string, id uint32, owner string) {
+	if pool, exists := m.pools[poolName]; exists {
+		pool.IdAllocations[idLabel] = &idallocation.AllocationPool_Allocation{
+			Id:    id,
+			Owner: owner,
+		}
+	}
+}
+
+// GetPool returns a copy of the pool data (e.g. to be reflected in a KubeStateChange event),
+// nil if the pool does not exist.
+func (m *MockIDAllocator) GetPool(poolName string) *idallocation.AllocationPool {
+	pool, exists := m.pools[poolName]
+	if !exists {
+		return nil
+	}
+	return proto.Clone(pool).(*idallocation.AllocationPool)
+}
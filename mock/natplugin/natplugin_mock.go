@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 
@@ -30,10 +31,14 @@ import (
 
 	"go.ligato.io/cn-infra/v2/datasync/syncbase"
 	"go.ligato.io/cn-infra/v2/logging"
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
 	vpp_nat "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/nat"
 )
 
-const dnat44Prefix = "config/vpp/nat/v2/dnat44/"
+const (
+	dnat44Prefix = "config/vpp/nat/v2/dnat44/"
+	aclPrefix    = "config/vpp/acls/v2/acl/"
+)
 
 // MockNatPlugin simulates the VPP/NAT plugin.
 type MockNatPlugin struct {
@@ -53,6 +58,9 @@ type MockNatPlugin struct {
 	nat44Dnat        map[string]*vpp_nat.DNat44 // label -> DNAT config
 	staticMappings   *StaticMappings
 	identityMappings *IdentityMappings
+
+	/* ACLs (enforcing service source ranges) */
+	acls map[string]*vpp_acl.ACL // name -> ACL config
 }
 
 // NewMockNatPlugin is a constructor for MockNatPlugin.
@@ -81,6 +89,7 @@ func (mnt *MockNatPlugin) resetNat44Dnat() {
 func (mnt *MockNatPlugin) Reset() {
 	mnt.resetNat44Global()
 	mnt.resetNat44Dnat()
+	mnt.acls = make(map[string]*vpp_acl.ACL)
 }
 
 // ApplyTxn applies transaction created by the service configurator.
@@ -133,6 +142,14 @@ func (mnt *MockNatPlugin) ApplyTxn(txn *localclient.Txn, latestRevs *syncbase.Pr
 				// shallow copy the configuration
 				mnt.nat44Dnat[dnatConfig.Label] = dnatConfig
 
+			} else if strings.HasPrefix(key, aclPrefix) {
+				// put ACL config
+				acl, isACL := value.(*vpp_acl.ACL)
+				if !isACL {
+					return errors.New("failed to cast ACL config value")
+				}
+				mnt.acls[acl.Name] = acl
+
 			} else {
 				return errors.New("non-NAT changed in txn")
 			}
@@ -238,6 +255,23 @@ func (mnt *MockNatPlugin) ApplyTxn(txn *localclient.Txn, latestRevs *syncbase.Pr
 				}
 			}
 
+		} else if strings.HasPrefix(key, aclPrefix) {
+			name := strings.TrimPrefix(key, aclPrefix)
+			if value != nil {
+				// put ACL config
+				acl, isACL := value.(*vpp_acl.ACL)
+				if !isACL {
+					return errors.New("failed to cast ACL config value")
+				}
+				mnt.acls[acl.Name] = acl
+			} else {
+				// remove ACL config
+				if _, hasACL := mnt.acls[name]; !hasACL {
+					return errors.New("attempt to remove ACL which does not exist")
+				}
+				delete(mnt.acls, name)
+			}
+
 		} else {
 			return errors.New("non-NAT changed in txn")
 		}
@@ -360,7 +394,8 @@ func (mnt *MockNatPlugin) dnatToStaticMappings(dnat *vpp_nat.DNat44) (*StaticMap
 			if local.LocalPort > uint32(^uint16(0)) {
 				return nil, errors.New("invalid local port number")
 			}
-			if (staticMapping.ExternalPort != 0 && (local.Probability == 0 || local.Probability > uint32(^uint8(0)))) ||
+			// probability 0 is used for a single local and for drained locals
+			if (staticMapping.ExternalPort != 0 && local.Probability > uint32(^uint8(0))) ||
 				(staticMapping.ExternalPort == 0 && local.Probability != 0) {
 				return nil, errors.New("invalid local probability")
 			}
@@ -437,6 +472,28 @@ func (mnt *MockNatPlugin) DumpNat44DNat() (dnats []*vpp_nat.DNat44) {
 	return dnats
 }
 
+// GetACL returns the current configuration of the given ACL (nil if not configured).
+func (mnt *MockNatPlugin) GetACL(name string) *vpp_acl.ACL {
+	return mnt.acls[name]
+}
+
+// GetIngressACLs returns names of all ACLs applied on the ingress of the given
+// interface.
+func (mnt *MockNatPlugin) GetIngressACLs(ifName string) (names []string) {
+	for name, acl := range mnt.acls {
+		if acl.Interfaces == nil {
+			continue
+		}
+		for _, ingressIf := range acl.Interfaces.Ingress {
+			if ingressIf == ifName {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // IsForwardingEnabled returns true if the forwarding is enabled.
 func (mnt *MockNatPlugin) IsForwardingEnabled() bool {
 	return mnt.forwarding
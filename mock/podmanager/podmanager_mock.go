@@ -45,6 +45,15 @@ func (m *MockPodManager) GetLocalPods() podmanager.LocalPods {
 	return m.localPods
 }
 
+// ListRunningPods returns all pods added via AddPod() method.
+func (m *MockPodManager) ListRunningPods() (podmanager.LocalPods, error) {
+	runningPods := make(podmanager.LocalPods)
+	for podID, pod := range m.localPods {
+		runningPods[podID] = pod
+	}
+	return runningPods, nil
+}
+
 // AddPod allows to simulate AddPod event.
 func (m *MockPodManager) AddPod(pod *podmanager.LocalPod) *podmanager.AddPod {
 	m.localPods[pod.ID] = pod
@@ -99,3 +99,23 @@ func (mpc *MockPolicyCache) LookupNamespace(namespace nsmodel.ID) (found bool, d
 func (mpc *MockPolicyCache) ListAllNamespaces() (namespaces []nsmodel.ID) {
 	return nil
 }
+
+// LookupClusterPolicy is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupClusterPolicy(name string) (found bool, data *policymodel.ClusterPolicy) {
+	return false, nil
+}
+
+// LookupClusterPoliciesByPod is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupClusterPoliciesByPod(pod podmodel.ID) (policies []string) {
+	return nil
+}
+
+// ListAllClusterPolicies is not implemented by the mock.
+func (mpc *MockPolicyCache) ListAllClusterPolicies() (policies []string) {
+	return nil
+}
+
+// LookupPodsByClusterSelector is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupPodsByClusterSelector(nsSelector, podSelector *policymodel.Policy_LabelSelector) (pods []podmodel.ID) {
+	return nil
+}
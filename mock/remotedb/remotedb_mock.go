@@ -0,0 +1,121 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotedb
+
+import (
+	"bytes"
+
+	"go.ligato.io/cn-infra/v2/datasync"
+	"go.ligato.io/cn-infra/v2/db/keyval"
+)
+
+// MockRemoteDB is a mock implementation of the remote DB with atomic operations
+// (nodesync.KVDBWithAtomic), always connected and storing data in memory.
+// Only the atomic operations of the broker are supported.
+type MockRemoteDB struct {
+	keyval.KvProtoPlugin
+	data map[string][]byte
+}
+
+// NewMockRemoteDB is a constructor for MockRemoteDB.
+func NewMockRemoteDB() *MockRemoteDB {
+	return &MockRemoteDB{
+		data: make(map[string][]byte),
+	}
+}
+
+// OnConnect calls the callback immediately - the DB is always connected.
+func (db *MockRemoteDB) OnConnect(callback func() error) {
+	callback()
+}
+
+// Disabled returns false.
+func (db *MockRemoteDB) Disabled() bool {
+	return false
+}
+
+// String returns the plugin name.
+func (db *MockRemoteDB) String() string {
+	return "mock-remote-db"
+}
+
+// Close is a no-op.
+func (db *MockRemoteDB) Close() error {
+	return nil
+}
+
+// NewBrokerWithAtomic returns broker with atomic operations over keys with the given prefix.
+func (db *MockRemoteDB) NewBrokerWithAtomic(keyPrefix string) keyval.BytesBrokerWithAtomic {
+	return &mockBroker{db: db, prefix: keyPrefix}
+}
+
+// Get returns data stored under the given key (including the broker prefix).
+func (db *MockRemoteDB) Get(key string) (data []byte, found bool) {
+	data, found = db.data[key]
+	return data, found
+}
+
+// Put stores data under the given key (including the broker prefix).
+func (db *MockRemoteDB) Put(key string, data []byte) {
+	db.data[key] = data
+}
+
+// Keys returns all keys stored in the DB.
+func (db *MockRemoteDB) Keys() (keys []string) {
+	for key := range db.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// mockBroker implements the atomic operations of keyval.BytesBrokerWithAtomic.
+type mockBroker struct {
+	keyval.BytesBrokerWithAtomic
+	db     *MockRemoteDB
+	prefix string
+}
+
+// PutIfNotExists puts the data only if the key does not exist yet.
+func (b *mockBroker) PutIfNotExists(key string, data []byte) (succeeded bool, err error) {
+	if _, exists := b.db.data[b.prefix+key]; exists {
+		return false, nil
+	}
+	b.db.data[b.prefix+key] = data
+	return true, nil
+}
+
+// CompareAndSwap replaces the data only if the current value equals oldData.
+func (b *mockBroker) CompareAndSwap(key string, oldData, newData []byte, opts ...datasync.PutOption) (succeeded bool, err error) {
+	if current, exists := b.db.data[b.prefix+key]; !exists || !bytes.Equal(current, oldData) {
+		return false, nil
+	}
+	b.db.data[b.prefix+key] = newData
+	return true, nil
+}
+
+// CompareAndDelete removes the key only if the current value equals data.
+func (b *mockBroker) CompareAndDelete(key string, data []byte, opts ...datasync.DelOption) (succeeded bool, err error) {
+	if current, exists := b.db.data[b.prefix+key]; !exists || !bytes.Equal(current, data) {
+		return false, nil
+	}
+	delete(b.db.data, b.prefix+key)
+	return true, nil
+}
+
+// GetValue returns the data stored under the key.
+func (b *mockBroker) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	data, found = b.db.data[b.prefix+key]
+	return data, found, 0, nil
+}
@@ -104,18 +104,25 @@ type IPNeighborScanConfig struct {
 // The string fields are then parsed to *net.IPNet and returned as such in IPAMConfig
 // structure.
 type IPAMConfig struct {
-	UseExternalIPAM               bool       `json:"useExternalIPAM,omitempty"`
-	ContivCIDR                    string     `json:"contivCIDR,omitempty"`
-	ServiceCIDR                   string     `json:"serviceCIDR,omitempty"`
-	NodeInterconnectDHCP          bool       `json:"nodeInterconnectDHCP,omitempty"`
-	PodSubnetCIDR                 string     `json:"podSubnetCIDR,omitempty"`
-	PodSubnetOneNodePrefixLen     uint8      `json:"podSubnetOneNodePrefixLen,omitempty"`
-	VPPHostSubnetCIDR             string     `json:"vppHostSubnetCIDR,omitempty"`
-	VPPHostSubnetOneNodePrefixLen uint8      `json:"vppHostSubnetOneNodePrefixLen,omitempty"`
-	NodeInterconnectCIDR          string     `json:"nodeInterconnectCIDR,omitempty"`
-	VxlanCIDR                     string     `json:"vxlanCIDR,omitempty"`
-	DefaultGateway                string     `json:"defaultGateway,omitempty"`
-	SRv6                          SRv6Config `json:"srv6"`
+	UseExternalIPAM                    bool       `json:"useExternalIPAM,omitempty"`
+	ContivCIDR                         string     `json:"contivCIDR,omitempty"`
+	ServiceCIDR                        string     `json:"serviceCIDR,omitempty"`
+	NodeInterconnectDHCP               bool       `json:"nodeInterconnectDHCP,omitempty"`
+	PodSubnetCIDR                      string     `json:"podSubnetCIDR,omitempty"`
+	PodSubnetOneNodePrefixLen          uint8      `json:"podSubnetOneNodePrefixLen,omitempty"`
+	AdditionalPodSubnetCIDRs           []string   `json:"additionalPodSubnetCIDRs,omitempty"`
+	SecondaryPodSubnetCIDR             string     `json:"secondaryPodSubnetCIDR,omitempty"` // dual-stack only
+	SecondaryPodSubnetOneNodePrefixLen uint8      `json:"secondaryPodSubnetOneNodePrefixLen,omitempty"`
+	DynamicPodSubnetAllocation         bool       `json:"dynamicPodSubnetAllocation,omitempty"`
+	IPLeakGCInterval                   uint32     `json:"ipLeakGCInterval,omitempty"`        // in seconds, 0 = disabled
+	IPLeakGracePeriod                  uint32     `json:"ipLeakGracePeriod,omitempty"`       // in seconds
+	IPUsageWarningThreshold            uint8      `json:"ipUsageWarningThreshold,omitempty"` // in percent, 0 = disabled
+	VPPHostSubnetCIDR                  string     `json:"vppHostSubnetCIDR,omitempty"`
+	VPPHostSubnetOneNodePrefixLen      uint8      `json:"vppHostSubnetOneNodePrefixLen,omitempty"`
+	NodeInterconnectCIDR               string     `json:"nodeInterconnectCIDR,omitempty"`
+	VxlanCIDR                          string     `json:"vxlanCIDR,omitempty"`
+	DefaultGateway                     string     `json:"defaultGateway,omitempty"`
+	SRv6                               SRv6Config `json:"srv6"`
 }
 
 // SRv6Config is part of IPAM configuration that configures SID prefixes of SRv6 components
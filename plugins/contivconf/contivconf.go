@@ -85,9 +85,14 @@ const (
 	defaultIPNeighborStaleThreshold = 4
 
 	// default IPAM configuration
+	defaultIPLeakGCInterval                       = 60  // in seconds
+	defaultIPLeakGracePeriod                      = 300 // in seconds
+	defaultIPUsageWarningThreshold                = 90  // in percent
 	defaultServiceCIDR                            = "10.96.0.0/12"
 	defaultPodSubnetCIDR                          = "10.1.0.0/16"
 	defaultPodSubnetOneNodePrefixLen              = 24
+	defaultSecondaryPodSubnetOneNodePrefixLenIPv4 = 24
+	defaultSecondaryPodSubnetOneNodePrefixLenIPv6 = 112
 	defaultVPPHostSubnetCIDR                      = "172.30.0.0/16"
 	defaultVPPHostSubnetOneNodePrefixLen          = 24
 	defaultVxlanCIDR                              = "192.168.30.0/24"
@@ -321,6 +326,9 @@ func (c *ContivConf) Init() (err error) {
 			VPPHostSubnetCIDR:             defaultVPPHostSubnetCIDR,
 			VPPHostSubnetOneNodePrefixLen: defaultVPPHostSubnetOneNodePrefixLen,
 			VxlanCIDR:                     defaultVxlanCIDR,
+			IPLeakGCInterval:              defaultIPLeakGCInterval,
+			IPLeakGracePeriod:             defaultIPLeakGracePeriod,
+			IPUsageWarningThreshold:       defaultIPUsageWarningThreshold,
 			SRv6: config.SRv6Config{
 				ServicePolicyBSIDSubnetCIDR:            defaultSrv6ServicePolicyBSIDSubnetCIDR,
 				ServicePodLocalSIDSubnetCIDR:           defaultSrv6ServicePodLocalSIDSubnetCIDR,
@@ -373,11 +381,16 @@ func (c *ContivConf) Init() (err error) {
 
 	// parse IPAM subnets
 	c.ipamConfig = &IPAMConfig{
-		UseExternalIPAM:      c.config.IPAMConfig.UseExternalIPAM,
-		NodeInterconnectDHCP: c.config.IPAMConfig.NodeInterconnectDHCP,
+		UseExternalIPAM:            c.config.IPAMConfig.UseExternalIPAM,
+		DynamicPodSubnetAllocation: c.config.IPAMConfig.DynamicPodSubnetAllocation,
+		IPLeakGCInterval:           time.Duration(c.config.IPAMConfig.IPLeakGCInterval) * time.Second,
+		IPLeakGracePeriod:          time.Duration(c.config.IPAMConfig.IPLeakGracePeriod) * time.Second,
+		IPUsageWarningThreshold:    c.config.IPAMConfig.IPUsageWarningThreshold,
+		NodeInterconnectDHCP:       c.config.IPAMConfig.NodeInterconnectDHCP,
 		CustomIPAMSubnets: CustomIPAMSubnets{
-			PodSubnetOneNodePrefixLen:     c.config.IPAMConfig.PodSubnetOneNodePrefixLen,
-			VPPHostSubnetOneNodePrefixLen: c.config.IPAMConfig.VPPHostSubnetOneNodePrefixLen,
+			PodSubnetOneNodePrefixLen:          c.config.IPAMConfig.PodSubnetOneNodePrefixLen,
+			SecondaryPodSubnetOneNodePrefixLen: c.config.IPAMConfig.SecondaryPodSubnetOneNodePrefixLen,
+			VPPHostSubnetOneNodePrefixLen:      c.config.IPAMConfig.VPPHostSubnetOneNodePrefixLen,
 		},
 		SRv6Settings: SRv6Settings{
 			SFCIDLengthUsedInSidForServiceFunction: c.config.IPAMConfig.SRv6.SFCIDLengthUsedInSidForServiceFunction,
@@ -403,6 +416,37 @@ func (c *ContivConf) Init() (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to parse PodSubnetCIDR: %v", err)
 	}
+	for _, podSubnet := range c.config.IPAMConfig.AdditionalPodSubnetCIDRs {
+		_, podSubnetCIDR, err := net.ParseCIDR(podSubnet)
+		if err != nil {
+			return fmt.Errorf("failed to parse AdditionalPodSubnetCIDRs: %v", err)
+		}
+		if (podSubnetCIDR.IP.To4() == nil) != (c.ipamConfig.PodSubnetCIDR.IP.To4() == nil) {
+			return fmt.Errorf("additional pod subnet %v is not from the same IP family as PodSubnetCIDR", podSubnetCIDR)
+		}
+		otherSubnets := []*net.IPNet{c.ipamConfig.PodSubnetCIDR}
+		otherSubnets = append(otherSubnets, c.ipamConfig.AdditionalPodSubnetCIDRs...)
+		for _, otherSubnet := range otherSubnets {
+			if otherSubnet.Contains(podSubnetCIDR.IP) || podSubnetCIDR.Contains(otherSubnet.IP) {
+				return fmt.Errorf("additional pod subnet %v overlaps with pod subnet %v", podSubnetCIDR, otherSubnet)
+			}
+		}
+		c.ipamConfig.AdditionalPodSubnetCIDRs = append(c.ipamConfig.AdditionalPodSubnetCIDRs, podSubnetCIDR)
+	}
+	if c.config.IPAMConfig.SecondaryPodSubnetCIDR != "" {
+		_, c.ipamConfig.SecondaryPodSubnetCIDR, err = net.ParseCIDR(c.config.IPAMConfig.SecondaryPodSubnetCIDR)
+		if err != nil {
+			return fmt.Errorf("failed to parse SecondaryPodSubnetCIDR: %v", err)
+		}
+		c.ipamConfig.DualStack = true
+		if c.ipamConfig.SecondaryPodSubnetOneNodePrefixLen == 0 {
+			if c.ipamConfig.SecondaryPodSubnetCIDR.IP.To4() != nil {
+				c.ipamConfig.SecondaryPodSubnetOneNodePrefixLen = defaultSecondaryPodSubnetOneNodePrefixLenIPv4
+			} else {
+				c.ipamConfig.SecondaryPodSubnetOneNodePrefixLen = defaultSecondaryPodSubnetOneNodePrefixLenIPv6
+			}
+		}
+	}
 	_, c.ipamConfig.VPPHostSubnetCIDR, err = net.ParseCIDR(c.config.IPAMConfig.VPPHostSubnetCIDR)
 	if err != nil {
 		return fmt.Errorf("failed to parse VPPHostSubnetCIDR: %v", err)
@@ -483,6 +527,29 @@ func (c *ContivConf) Init() (err error) {
 			c.ipamConfig.UseIPv6 = false
 		}
 	}
+	// dual-stack: the secondary pod subnet has to be of the other IP family than the primary one,
+	// supported only by the internal IPAM with VXLAN or no-overlay transport
+	if c.ipamConfig.DualStack {
+		if isIPv6AddrString(c.config.IPAMConfig.SecondaryPodSubnetCIDR) == c.ipamConfig.UseIPv6 {
+			return fmt.Errorf("secondary pod subnet %v has to be from the other IP family than the primary pod subnet",
+				c.ipamConfig.SecondaryPodSubnetCIDR)
+		}
+		if c.ipamConfig.UseExternalIPAM {
+			return errors.New("dual-stack pod addressing cannot be combined with external IPAM")
+		}
+		if c.config.RoutingConfig.NodeToNodeTransport == SRv6Transport {
+			return errors.New("dual-stack pod addressing is not supported with SRv6 node-to-node transport")
+		}
+	}
+	// dynamic allocation of pod subnets is supported only by the internal IPAM with VXLAN or no-overlay transport
+	if c.ipamConfig.DynamicPodSubnetAllocation {
+		if c.ipamConfig.UseExternalIPAM {
+			return errors.New("dynamic pod subnet allocation cannot be combined with external IPAM")
+		}
+		if c.config.RoutingConfig.NodeToNodeTransport == SRv6Transport {
+			return errors.New("dynamic pod subnet allocation is not supported with SRv6 node-to-node transport")
+		}
+	}
 	// disable GSO for SRv6 - not yet supported by VPP
 	if c.ipamConfig.UseIPv6 && c.config.RoutingConfig.NodeToNodeTransport == SRv6Transport && c.config.EnableGSO {
 		c.Log.Warnf("GSO not supported for SRv6, disabling")
@@ -619,7 +686,15 @@ func (c *ContivConf) Update(event controller.Event, txn controller.UpdateOperati
 	return "", nil
 }
 
-// Revert is NOOP.
+// CanRevert returns true for changes of the node-specific config - Update does not
+// change the internal state, configuration is re-loaded only once the follow-up
+// NodeConfigChange is processed (which is not done for dry-run).
+func (c *ContivConf) CanRevert(event controller.Event) bool {
+	_, isKSChange := event.(*controller.KubeStateChange)
+	return isKSChange && c.HandlesEvent(event)
+}
+
+// Revert is NOOP - there are no internal changes to revert.
 func (c *ContivConf) Revert(event controller.Event) error {
 	return nil
 }
@@ -15,6 +15,7 @@ package contivconf
 import (
 	"fmt"
 	"net"
+	"time"
 
 	stn_grpc "github.com/americanbinary/vpp/cmd/contiv-stn/model/stn"
 	"github.com/americanbinary/vpp/plugins/contivconf/config"
@@ -103,8 +104,31 @@ type IPAMConfig struct {
 	UseExternalIPAM bool
 
 	// UseIPv6 is true if IPv6 networking should be used instead of IPv4.
+	// With dual-stack, UseIPv6 reflects the IP family of the primary pod subnet
+	// (and of the node-to-node connectivity).
 	UseIPv6 bool
 
+	// DualStack is true if pods are assigned one IP address of each IP family
+	// (from PodSubnetCIDR and from SecondaryPodSubnetCIDR).
+	DualStack bool
+
+	// DynamicPodSubnetAllocation is true if per-node pod subnets should be leased
+	// from a pool shared by all nodes (stored in the KV DB) instead of being derived
+	// from node IDs.
+	DynamicPodSubnetAllocation bool
+
+	// IPLeakGCInterval is the period of the reconciliation which removes pods
+	// without a running sandbox and releases their IP addresses (0 = disabled).
+	IPLeakGCInterval time.Duration
+
+	// IPLeakGracePeriod is how long a pod has to remain without a running sandbox
+	// before it is removed by the reconciliation.
+	IPLeakGracePeriod time.Duration
+
+	// IPUsageWarningThreshold is the fill level (in percent) of a node's IP address
+	// block above which IPAM warns about the upcoming exhaustion (0 = disabled).
+	IPUsageWarningThreshold uint8
+
 	// CIDR to use for all IP address allocations.
 	// If defined (non-nil), the manually selected subnets (CustomIPAMSubnets, see below)
 	// should be ignored - i.e. this field takes precedence.
@@ -192,6 +216,20 @@ type CustomIPAMSubnets struct {
 	// Prefix length of subnet used for all PODs within 1 node.
 	PodSubnetOneNodePrefixLen uint8
 
+	// Additional (non-contiguous) subnets for PODs, dissected into per-node
+	// subnets the same way as PodSubnetCIDR. Node starts allocating from
+	// the next subnet in the order once its subnets from the preceding ones
+	// are exhausted.
+	AdditionalPodSubnetCIDRs []*net.IPNet
+
+	// Subnet of the other IP family than PodSubnetCIDR (dual-stack only, nil otherwise),
+	// from which every pod gets its second IP address. Dissected into per-node
+	// subnets using SecondaryPodSubnetOneNodePrefixLen.
+	SecondaryPodSubnetCIDR *net.IPNet
+
+	// Prefix length of the secondary subnet used for all PODs within 1 node.
+	SecondaryPodSubnetOneNodePrefixLen uint8
+
 	// Subnet used across all nodes for VPP to host Linux stack interconnect.
 	VPPHostSubnetCIDR *net.IPNet
 
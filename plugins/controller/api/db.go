@@ -17,6 +17,7 @@ package api
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
@@ -160,6 +161,51 @@ func (ev *DBResync) Done(error) {
 	return
 }
 
+// Payload returns serializable content of the DBResync event, i.e. the snapshot
+// of the Kubernetes state data and the external configuration.
+func (ev *DBResync) Payload() (*EventPayload, error) {
+	payload := NewEventPayload(DBResyncPayloadType)
+	payload.SetArg(localPayloadArg, strconv.FormatBool(ev.Local))
+	for resource, kvs := range ev.KubeState {
+		for key, value := range kvs {
+			label := kubeStatePayloadPrefix + key
+			payload.SetArg(label, resource)
+			if err := payload.SetValue(label, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for key, value := range ev.ExternalConfig {
+		if err := payload.SetValue(externalConfigPayloadPrefix+key, value); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+// DBResyncFromPayload re-creates DBResync event from the payload.
+func DBResyncFromPayload(payload *EventPayload) (*DBResync, error) {
+	ev := NewDBResync()
+	ev.Local = payload.GetArg(localPayloadArg) == strconv.FormatBool(true)
+	for label := range payload.Values {
+		value, err := payload.GetValue(label)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasPrefix(label, kubeStatePayloadPrefix):
+			resource := payload.GetArg(label)
+			if _, hasResource := ev.KubeState[resource]; !hasResource {
+				ev.KubeState[resource] = make(KeyValuePairs)
+			}
+			ev.KubeState[resource][strings.TrimPrefix(label, kubeStatePayloadPrefix)] = value
+		case strings.HasPrefix(label, externalConfigPayloadPrefix):
+			ev.ExternalConfig[strings.TrimPrefix(label, externalConfigPayloadPrefix)] = value
+		}
+	}
+	return ev, nil
+}
+
 /***************************** Kube State Change ******************************/
 
 // KubeStateChange is an Update event that represents change for one key from
@@ -215,6 +261,38 @@ func (ev *KubeStateChange) Done(error) {
 	return
 }
 
+// Payload returns serializable content of the KubeStateChange event.
+func (ev *KubeStateChange) Payload() (*EventPayload, error) {
+	payload := NewEventPayload(KubeStateChangePayloadType)
+	payload.SetArg(keyPayloadArg, ev.Key)
+	payload.SetArg(resourcePayloadArg, ev.Resource)
+	if err := payload.SetValue(prevValuePayloadLabel, ev.PrevValue); err != nil {
+		return nil, err
+	}
+	if err := payload.SetValue(newValuePayloadLabel, ev.NewValue); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// KubeStateChangeFromPayload re-creates KubeStateChange event from the payload.
+func KubeStateChangeFromPayload(payload *EventPayload) (*KubeStateChange, error) {
+	prevValue, err := payload.GetValue(prevValuePayloadLabel)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := payload.GetValue(newValuePayloadLabel)
+	if err != nil {
+		return nil, err
+	}
+	return &KubeStateChange{
+		Key:       payload.GetArg(keyPayloadArg),
+		Resource:  payload.GetArg(resourcePayloadArg),
+		PrevValue: prevValue,
+		NewValue:  newValue,
+	}, nil
+}
+
 // protoToString converts proto message to string
 func protoToString(msg proto.Message) string {
 	if msg == nil {
@@ -327,6 +405,26 @@ func (ev *ExternalConfigChange) Wait() error {
 	return <-ev.result
 }
 
+// Payload returns serializable content of the ExternalConfigChange event.
+func (ev *ExternalConfigChange) Payload() (*EventPayload, error) {
+	payload, err := kvPairsToPayload(ExternalConfigChangePayloadType, ev.UpdatedKVs)
+	if err != nil {
+		return nil, err
+	}
+	payload.SetArg(sourcePayloadArg, ev.Source)
+	return payload, nil
+}
+
+// ExternalConfigChangeFromPayload re-creates (non-blocking) ExternalConfigChange
+// event from the payload.
+func ExternalConfigChangeFromPayload(payload *EventPayload) (*ExternalConfigChange, error) {
+	ev := NewExternalConfigChange(payload.GetArg(sourcePayloadArg), false)
+	if err := kvPairsFromPayload(payload, ev.UpdatedKVs); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
 /*************************** External Config Resync ***************************/
 
 // ExternalConfigResync is a Resync event triggered by external config source.
@@ -384,3 +482,77 @@ func (ev *ExternalConfigResync) Done(err error) {
 func (ev *ExternalConfigResync) Wait() error {
 	return <-ev.result
 }
+
+// Payload returns serializable content of the ExternalConfigResync event.
+func (ev *ExternalConfigResync) Payload() (*EventPayload, error) {
+	payload, err := kvPairsToPayload(ExternalConfigResyncPayloadType, ev.ExternalConfig)
+	if err != nil {
+		return nil, err
+	}
+	payload.SetArg(sourcePayloadArg, ev.Source)
+	return payload, nil
+}
+
+// ExternalConfigResyncFromPayload re-creates (non-blocking) ExternalConfigResync
+// event from the payload.
+func ExternalConfigResyncFromPayload(payload *EventPayload) (*ExternalConfigResync, error) {
+	ev := NewExternalConfigResync(payload.GetArg(sourcePayloadArg), false)
+	if err := kvPairsFromPayload(payload, ev.ExternalConfig); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+/******************************* Payload helpers ******************************/
+
+const (
+	// DBResyncPayloadType identifies payload of the DBResync event.
+	DBResyncPayloadType = "DBResync"
+
+	// KubeStateChangePayloadType identifies payload of the KubeStateChange event.
+	KubeStateChangePayloadType = "KubeStateChange"
+
+	// ExternalConfigChangePayloadType identifies payload of the ExternalConfigChange event.
+	ExternalConfigChangePayloadType = "ExternalConfigChange"
+
+	// ExternalConfigResyncPayloadType identifies payload of the ExternalConfigResync event.
+	ExternalConfigResyncPayloadType = "ExternalConfigResync"
+
+	// labels used in the payloads of the events defined in this file
+	keyPayloadArg               = "key"
+	resourcePayloadArg          = "resource"
+	sourcePayloadArg            = "source"
+	localPayloadArg             = "local"
+	prevValuePayloadLabel       = "prev-value"
+	newValuePayloadLabel        = "new-value"
+	kubeStatePayloadPrefix      = "kube-state:"
+	externalConfigPayloadPrefix = "external-config:"
+)
+
+// kvPairsToPayload stores key-value pairs into a new payload.
+// Values are stored under their keys, deleted values are stored as nil.
+func kvPairsToPayload(eventType string, kvs KeyValuePairs) (*EventPayload, error) {
+	payload := NewEventPayload(eventType)
+	for key, value := range kvs {
+		if value == nil {
+			payload.Values[key] = nil
+			continue
+		}
+		if err := payload.SetValue(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+// kvPairsFromPayload reads key-value pairs stored by kvPairsToPayload.
+func kvPairsFromPayload(payload *EventPayload, kvs KeyValuePairs) error {
+	for key := range payload.Values {
+		value, err := payload.GetValue(key)
+		if err != nil {
+			return err
+		}
+		kvs[key] = value
+	}
+	return nil
+}
@@ -14,6 +14,12 @@
 
 package api
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // EventLoop defines method for accessing the main event loop.
 type EventLoop interface {
 	// PushEvent adds the given event into the queue for processing.
@@ -82,9 +88,27 @@ type EventHandler interface {
 	// Revert is called to revert already executed internal changes (in the plugin
 	// itself, not in VPP/Linux network stack) for a RevertOnFailure event that
 	// has failed in the processing.
+	// Revert is also called after the dry-run of events for which the handler
+	// implements RevertCapableHandler.
 	Revert(event Event) error
 }
 
+// RevertCapableHandler *can* be implemented by event handlers that are able
+// to revert internal changes made by Update also for events other than those
+// of the type RevertOnFailure.
+// Only events for which all the interested handlers are able to revert can be
+// processed in the dry-run mode.
+type RevertCapableHandler interface {
+	EventHandler
+
+	// CanRevert should return true if Revert undoes all the internal changes
+	// made by Update for the given event, without leaving any side-effects
+	// behind (e.g. IDs allocated outside of the handler).
+	// Follow-up events pushed by Update during the dry-run are not processed
+	// by the Controller, only listed in the result of the dry-run.
+	CanRevert(event Event) bool
+}
+
 // EventMethodType is either Resync or Update.
 type EventMethodType int
 
@@ -106,6 +130,39 @@ const (
 	Update
 )
 
+// eventMethodTypeNames maps event method types to their names.
+var eventMethodTypeNames = map[EventMethodType]string{
+	FullResync:       "FullResync",
+	DownstreamResync: "DownstreamResync",
+	UpstreamResync:   "UpstreamResync",
+	Update:           "Update",
+}
+
+// String returns name of the event method type.
+func (t EventMethodType) String() string {
+	if name, known := eventMethodTypeNames[t]; known {
+		return name
+	}
+	return fmt.Sprintf("EventMethodType(%d)", int(t))
+}
+
+// ParseEventMethodType converts event method type from string - either from
+// the name (case-insensitive) or from the numeric value.
+func ParseEventMethodType(str string) (EventMethodType, error) {
+	for methodType, name := range eventMethodTypeNames {
+		if strings.EqualFold(name, str) {
+			return methodType, nil
+		}
+	}
+	if value, err := strconv.Atoi(str); err == nil {
+		methodType := EventMethodType(value)
+		if _, known := eventMethodTypeNames[methodType]; known {
+			return methodType, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown event method type: %s", str)
+}
+
 // UpdateDirectionType is either Forward or Reverse.
 type UpdateDirectionType int
 
@@ -14,7 +14,11 @@
 
 package api
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
 
 // HealingResyncType is either Periodic or AfterError.
 type HealingResyncType int
@@ -69,3 +73,47 @@ func (ev *HealingResync) IsBlocking() bool {
 func (ev *HealingResync) Done(error) {
 	return
 }
+
+// Priority is HealingPriority.
+func (ev *HealingResync) Priority() EventPriority {
+	return HealingPriority
+}
+
+// IsDuplicateOf returns true if both this and the given event are periodic
+// healing resyncs.
+func (ev *HealingResync) IsDuplicateOf(event Event) bool {
+	other, isHealingResync := event.(*HealingResync)
+	return isHealingResync && ev.Type == Periodic && other.Type == Periodic
+}
+
+// Payload returns serializable content of the HealingResync event.
+func (ev *HealingResync) Payload() (*EventPayload, error) {
+	payload := NewEventPayload(HealingResyncPayloadType)
+	payload.SetArg(healingTypePayloadArg, strconv.Itoa(int(ev.Type)))
+	if ev.Error != nil {
+		payload.SetArg(healingErrorPayloadArg, ev.Error.Error())
+	}
+	return payload, nil
+}
+
+// HealingResyncFromPayload re-creates HealingResync event from the payload.
+func HealingResyncFromPayload(payload *EventPayload) (*HealingResync, error) {
+	healingType, err := strconv.Atoi(payload.GetArg(healingTypePayloadArg))
+	if err != nil {
+		return nil, err
+	}
+	ev := &HealingResync{Type: HealingResyncType(healingType)}
+	if errStr := payload.GetArg(healingErrorPayloadArg); errStr != "" {
+		ev.Error = errors.New(errStr)
+	}
+	return ev, nil
+}
+
+const (
+	// HealingResyncPayloadType identifies payload of the HealingResync event.
+	HealingResyncPayloadType = "HealingResync"
+
+	// labels used in the payload of the HealingResync event
+	healingTypePayloadArg  = "type"
+	healingErrorPayloadArg = "error"
+)
@@ -0,0 +1,91 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+)
+
+// SerializableEvent *can* be implemented by events whose content can be persisted
+// together with the event record and later used to re-create the event
+// (e.g. to replay recorded events offline).
+type SerializableEvent interface {
+	Event
+
+	// Payload returns serializable content of the event.
+	Payload() (*EventPayload, error)
+}
+
+// EventPayload is a serializable content of an event.
+type EventPayload struct {
+	// EventType identifies the type of the event for the de-serialization
+	// (event names may not be constant for a given type).
+	EventType string
+
+	// Args are plain (string) event arguments.
+	Args map[string]string `json:",omitempty"`
+
+	// Values are proto messages carried by the event, stored under event-specific
+	// labels.
+	Values map[string]*any.Any `json:",omitempty"`
+}
+
+// NewEventPayload is a constructor for EventPayload.
+func NewEventPayload(eventType string) *EventPayload {
+	return &EventPayload{
+		EventType: eventType,
+		Args:      make(map[string]string),
+		Values:    make(map[string]*any.Any),
+	}
+}
+
+// SetArg stores plain event argument.
+func (p *EventPayload) SetArg(label, value string) {
+	p.Args[label] = value
+}
+
+// GetArg returns plain event argument (empty string if not set).
+func (p *EventPayload) GetArg(label string) string {
+	return p.Args[label]
+}
+
+// SetValue stores proto message carried by the event. Nil value is not stored.
+func (p *EventPayload) SetValue(label string, value proto.Message) error {
+	if value == nil {
+		return nil
+	}
+	anyValue, err := ptypes.MarshalAny(value)
+	if err != nil {
+		return err
+	}
+	p.Values[label] = anyValue
+	return nil
+}
+
+// GetValue returns proto message carried by the event (nil if not set).
+// The message type has to be registered with the proto package.
+func (p *EventPayload) GetValue(label string) (proto.Message, error) {
+	anyValue, hasValue := p.Values[label]
+	if !hasValue || anyValue == nil {
+		return nil, nil
+	}
+	var value ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(anyValue, &value); err != nil {
+		return nil, err
+	}
+	return value.Message, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "strconv"
+
+// EventPriority is a priority class of an event.
+// Events waiting for processing are dequeued from the class with the highest
+// priority first, events of the same class are processed in the FIFO order.
+// Events waiting for too long are dequeued first, regardless of the class.
+type EventPriority int
+
+const (
+	// CNIPriority is the highest priority, used for events blocking CNI requests.
+	CNIPriority EventPriority = iota
+
+	// TopologyPriority is used for changes of the cluster topology and of the node
+	// configuration. It is the default priority for events not implementing
+	// the PrioritizedEvent interface.
+	TopologyPriority
+
+	// HealingPriority is the lowest priority, used for healing resyncs.
+	HealingPriority
+)
+
+// EventPriorities lists all event priority classes, ordered from the highest
+// to the lowest priority.
+var EventPriorities = []EventPriority{CNIPriority, TopologyPriority, HealingPriority}
+
+var eventPriorityNames = map[EventPriority]string{
+	CNIPriority:      "cni",
+	TopologyPriority: "topology",
+	HealingPriority:  "healing",
+}
+
+// String returns human-readable name of the event priority class.
+func (p EventPriority) String() string {
+	if name, known := eventPriorityNames[p]; known {
+		return name
+	}
+	return strconv.Itoa(int(p))
+}
+
+// PrioritizedEvent *can* be implemented by events to select a priority class
+// other than the default TopologyPriority.
+type PrioritizedEvent interface {
+	Event
+
+	// Priority returns the priority class of the event.
+	Priority() EventPriority
+}
+
+// GetEventPriority returns the priority class of the given event.
+func GetEventPriority(event Event) EventPriority {
+	if prioritized, isPrioritized := event.(PrioritizedEvent); isPrioritized {
+		return prioritized.Priority()
+	}
+	return TopologyPriority
+}
+
+// CoalescableEvent *can* be implemented by non-blocking events for which it is
+// enough to process only one of the duplicate instances waiting in the event
+// queue. A new event which is a duplicate of the last event queued with the same
+// priority is dropped (Done is not called for the dropped event).
+type CoalescableEvent interface {
+	Event
+
+	// IsDuplicateOf returns true if the event is equivalent to the given
+	// (already queued) event.
+	IsDuplicateOf(event Event) bool
+}
+
+// ObsoletableEvent *can* be implemented by non-blocking events which may become
+// obsolete while waiting in the event queue (e.g. when the state for which
+// the event was triggered has been changed by another event in the meantime).
+// Obsolete event is not processed by any handler.
+type ObsoletableEvent interface {
+	Event
+
+	// IsObsolete is called by the Controller right before the event is processed.
+	IsObsolete() bool
+}
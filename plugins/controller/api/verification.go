@@ -43,3 +43,16 @@ func (ev *VerificationResync) IsBlocking() bool {
 func (ev *VerificationResync) Done(error) {
 	return
 }
+
+// Priority is HealingPriority.
+func (ev *VerificationResync) Priority() EventPriority {
+	return HealingPriority
+}
+
+// VerificationResyncPayloadType identifies payload of the VerificationResync event.
+const VerificationResyncPayloadType = "VerificationResync"
+
+// Payload returns (empty) serializable content of the VerificationResync event.
+func (ev *VerificationResync) Payload() (*EventPayload, error) {
+	return NewEventPayload(VerificationResyncPayloadType), nil
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+// ConfigOperation is a type of change of a configuration value.
+type ConfigOperation string
+
+const (
+	// CreateValue is used for a newly added configuration value.
+	CreateValue ConfigOperation = "create"
+
+	// UpdateValue is used for a modified configuration value.
+	UpdateValue ConfigOperation = "update"
+
+	// DeleteValue is used for a removed configuration value.
+	DeleteValue ConfigOperation = "delete"
+)
+
+// ConfigChange is a change of a single configuration value that would be requested
+// from KVScheduler (i.e. after the merge with the external configuration).
+type ConfigChange struct {
+	Key       string
+	Operation ConfigOperation
+	PrevValue proto.Message `json:",omitempty"` // nil for CreateValue
+	NewValue  proto.Message `json:",omitempty"` // nil for DeleteValue
+}
+
+// DryRunResult describes what an Update event would change if it was applied.
+type DryRunResult struct {
+	Name        string
+	Description string
+
+	// Handlers lists Update operations executed for the event, followed by
+	// Revert operations that undid the changes of the handlers' internal state.
+	Handlers []*EventHandlingRecord
+
+	// Changes of the configuration for vpp-agent, ordered by keys.
+	// Empty if any of the handlers has failed to process the event.
+	Changes []*ConfigChange
+
+	// FollowUpEvents lists (as strings) follow-up events pushed by the handlers
+	// during the dry-run. Follow-up events are not processed, i.e. changes
+	// they would produce are not included.
+	FollowUpEvents []string `json:",omitempty"`
+}
+
+// dryRunEvent wraps an Update event that should be processed in the dry-run mode.
+type dryRunEvent struct {
+	api.Event
+
+	result *DryRunResult
+	done   chan error
+}
+
+var (
+	// ErrDryRunFromEventLoop is returned when DryRun is called from within the event loop.
+	ErrDryRunFromEventLoop = errors.New("dry-run cannot be requested from within the event loop")
+
+	// ErrDryRunNotSupported is returned (wrapped) by DryRun for events that cannot
+	// be processed in the dry-run mode.
+	ErrDryRunNotSupported = errors.New("dry-run is not supported")
+)
+
+// IsBlocking returns true - the caller waits for the result of the dry-run.
+func (ev *dryRunEvent) IsBlocking() bool {
+	return true
+}
+
+// Done delivers the result of the dry-run back to the caller.
+func (ev *dryRunEvent) Done(err error) {
+	ev.done <- err
+}
+
+// DryRun processes the given Update event against a scratch transaction,
+// computes the resulting changes of the vpp-agent configuration without
+// committing them and reverts changes of the handlers' internal state.
+// The event is refused with ErrDryRunNotSupported unless all the handlers
+// interested in the event are able to revert it (see api.RevertCapableHandler).
+// Follow-up events pushed by the handlers are not processed, only listed
+// in the result.
+// The method blocks until the event is processed by the event loop and cannot
+// be called from within the event loop.
+func (c *Controller) DryRun(event api.Event) (*DryRunResult, error) {
+	if getGID() == c.evLoopGID {
+		return nil, ErrDryRunFromEventLoop
+	}
+	dryRun := &dryRunEvent{
+		Event: event,
+		done:  make(chan error, 1),
+	}
+	if err := c.PushEvent(dryRun); err != nil {
+		return nil, err
+	}
+	select {
+	case <-c.ctx.Done():
+		return nil, ErrClosedController
+	case err := <-dryRun.done:
+		if err != nil {
+			return nil, err
+		}
+		return dryRun.result, nil
+	}
+}
+
+// processDryRun executes the dry-run of an Update event.
+// State of the Controller (kube state data, configuration) is not changed.
+// Returned is non-nil error only if the event loop should abort.
+func (c *Controller) processDryRun(dryRun *dryRunEvent) error {
+	var eventHandlers []api.EventHandler
+	event := dryRun.Event
+
+	// 1. check that the event can be dry-run
+	updateEvent, isUpdate := event.(api.UpdateEvent)
+	if event.Method() != api.Update || !isUpdate {
+		dryRun.Done(fmt.Errorf("%w for non-update events (%s)", ErrDryRunNotSupported, event.GetName()))
+		return nil
+	}
+	if _, isExtChangeEv := event.(*api.ExternalConfigChange); isExtChangeEv {
+		dryRun.Done(fmt.Errorf("%w for external config changes", ErrDryRunNotSupported))
+		return nil
+	}
+	if ksChange, isKSChange := event.(*api.KubeStateChange); isKSChange {
+		resourceData, knownResource := c.kubeStateData[ksChange.Resource]
+		if !knownResource {
+			dryRun.Done(fmt.Errorf("%w for unknown resource: %s", ErrDryRunNotSupported, ksChange.Resource))
+			return nil
+		}
+		if ksChange.PrevValue == nil {
+			// previous value can be omitted by the caller
+			ksChange.PrevValue = resourceData[ksChange.Key]
+		}
+	}
+
+	// 2. get the order in which the event handlers should be executed
+	if updateEvent.Direction() == api.Forward {
+		eventHandlers = c.EventHandlers
+	} else {
+		eventHandlers = c.revEventHandlers
+	}
+	eventHandlers = filterHandlersForEvent(event, eventHandlers)
+
+	// 3. check that the changes of the handlers' internal state can be reverted
+	//    - the live state must not be left modified by the dry-run
+	var nonRevertible []string
+	for _, handler := range eventHandlers {
+		revertCapable, isRevertCapable := handler.(api.RevertCapableHandler)
+		if !isRevertCapable || !revertCapable.CanRevert(event) {
+			nonRevertible = append(nonRevertible, handler.String())
+		}
+	}
+	if len(nonRevertible) > 0 {
+		dryRun.Done(fmt.Errorf("%w for event %s - changes cannot be reverted by: %s",
+			ErrDryRunNotSupported, event.GetName(), strings.Join(nonRevertible, ", ")))
+		return nil
+	}
+	c.Log.Infof("Dry-run of the event: %s (handlers: %s)", event.GetName(),
+		evHandlersToStr(eventHandlers))
+
+	// 4. execute Update against a scratch transaction
+	result := &DryRunResult{
+		Name:        event.GetName(),
+		Description: event.String(),
+	}
+	c.txn = newTransaction(c.Scheduler)
+	c.dryRun = dryRun // follow-up events are intercepted by PushEvent
+	dryRun.result = result
+	var (
+		idx    int
+		wasErr error
+	)
+	for idx = 0; idx < len(eventHandlers); idx++ {
+		var errStr string
+		handler := eventHandlers[idx]
+		updateStart := time.Now()
+		change, err := handler.Update(event, c.txn)
+		updateDuration := time.Since(updateStart)
+		if err != nil {
+			errStr = err.Error()
+			wasErr = err
+		}
+		result.Handlers = append(result.Handlers, &EventHandlingRecord{
+			Handler:  handler.String(),
+			Change:   change,
+			Error:    err,
+			ErrorStr: errStr,
+			Duration: updateDuration,
+		})
+		if err != nil {
+			break
+		}
+	}
+
+	// 5. compute changes of the vpp-agent configuration (nothing is committed)
+	if wasErr == nil {
+		result.Changes = c.dryRunChanges(c.txn)
+	}
+	c.txn = nil
+
+	// 6. revert changes of the handlers' internal state
+	var fatalErr error
+	for idx = idx - 1; idx >= 0; idx-- {
+		var errStr string
+		handler := eventHandlers[idx]
+		revertStart := time.Now()
+		err := handler.Revert(event)
+		revertDuration := time.Since(revertStart)
+		if err != nil {
+			errStr = err.Error()
+		}
+		result.Handlers = append(result.Handlers, &EventHandlingRecord{
+			Handler:  handler.String(),
+			Revert:   true,
+			Error:    err,
+			ErrorStr: errStr,
+			Duration: revertDuration,
+		})
+		if _, isFatalErr := err.(*api.FatalError); isFatalErr {
+			fatalErr = err
+			break
+		}
+	}
+	if _, isFatalErr := wasErr.(*api.FatalError); isFatalErr {
+		fatalErr = wasErr
+	}
+	c.dryRun = nil
+
+	dryRun.Done(nil)
+	return fatalErr
+}
+
+// dryRunChanges compares values from the transaction with the configuration
+// currently requested from KVScheduler.
+func (c *Controller) dryRunChanges(txn *kvSchedulerTxn) (changes []*ConfigChange) {
+	for key, txnValue := range txn.values {
+		prevValue := c.mergeWithExternalConfig(key, c.internalConfig[key])
+		newValue := c.mergeWithExternalConfig(key, txnValue)
+		change := &ConfigChange{
+			Key:       key,
+			PrevValue: prevValue,
+			NewValue:  newValue,
+		}
+		switch {
+		case prevValue == nil && newValue == nil:
+			continue
+		case prevValue == nil:
+			change.Operation = CreateValue
+		case newValue == nil:
+			change.Operation = DeleteValue
+		case proto.Equal(prevValue, newValue):
+			continue
+		default:
+			change.Operation = UpdateValue
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// mergeWithExternalConfig merges the given internal value with the external
+// configuration from the first source with a match.
+func (c *Controller) mergeWithExternalConfig(key string, value proto.Message) proto.Message {
+	for source := range c.externalConfig {
+		if extVal, hasExtVal := c.externalConfig[source][key]; hasExtVal {
+			return c.mergeValues(value, extVal)
+		}
+	}
+	return value
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/logging"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+)
+
+const testResource = "test-pods"
+
+// dryRunHandler mirrors changed pods into the configuration.
+// The pods are also cached in the internal state of the handler.
+type dryRunHandler struct {
+	name      string
+	canRevert bool
+	fail      bool
+	pods      map[string]*podmodel.Pod
+	eventLoop api.EventLoop // follow-up event is pushed for every pod if set
+}
+
+func newDryRunHandler(name string, canRevert bool) *dryRunHandler {
+	return &dryRunHandler{name: name, canRevert: canRevert, pods: make(map[string]*podmodel.Pod)}
+}
+
+func (h *dryRunHandler) String() string                    { return h.name }
+func (h *dryRunHandler) HandlesEvent(event api.Event) bool { return true }
+
+func (h *dryRunHandler) Resync(event api.Event, kubeStateData api.KubeStateData, resyncCount int, txn api.ResyncOperations) error {
+	return nil
+}
+
+func (h *dryRunHandler) Update(event api.Event, txn api.UpdateOperations) (changeDescription string, err error) {
+	if h.fail {
+		return "", errors.New("update failed")
+	}
+	ksChange := event.(*api.KubeStateChange)
+	key := h.name + "/" + ksChange.Key
+	if ksChange.NewValue == nil {
+		delete(h.pods, ksChange.Key)
+		txn.Delete(key)
+		return "remove pod", nil
+	}
+	h.pods[ksChange.Key] = ksChange.NewValue.(*podmodel.Pod)
+	txn.Put(key, ksChange.NewValue)
+	if h.eventLoop != nil {
+		err = h.eventLoop.PushEvent(&testQueueEvent{key: ksChange.Key})
+	}
+	return "put pod", err
+}
+
+func (h *dryRunHandler) CanRevert(event api.Event) bool {
+	return h.canRevert
+}
+
+func (h *dryRunHandler) Revert(event api.Event) error {
+	ksChange := event.(*api.KubeStateChange)
+	if ksChange.PrevValue == nil {
+		delete(h.pods, ksChange.Key)
+	} else {
+		h.pods[ksChange.Key] = ksChange.PrevValue.(*podmodel.Pod)
+	}
+	return nil
+}
+
+func newDryRunController(handlers ...api.EventHandler) *Controller {
+	c := &Controller{
+		Deps: Deps{
+			PluginDeps: infra.PluginDeps{
+				Log: logging.ForPlugin("controller"),
+			},
+			EventHandlers: handlers,
+		},
+		kubeStateData:  api.KubeStateData{testResource: make(api.KeyValuePairs)},
+		externalConfig: make(map[string]api.KeyValuePairs),
+		internalConfig: make(api.KeyValuePairs),
+		evLoopGID:      getGID(), // test runs the event loop
+	}
+	for i := len(handlers) - 1; i >= 0; i-- {
+		c.revEventHandlers = append(c.revEventHandlers, handlers[i])
+	}
+	return c
+}
+
+func dryRun(c *Controller, event api.Event) (*DryRunResult, error) {
+	ev := &dryRunEvent{Event: event, done: make(chan error, 1)}
+	Expect(c.processDryRun(ev)).To(Succeed())
+	if err := <-ev.done; err != nil {
+		return nil, err
+	}
+	return ev.result, nil
+}
+
+func TestDryRun(t *testing.T) {
+	RegisterTestingT(t)
+
+	handler1 := newDryRunHandler("h1", true)
+	handler2 := newDryRunHandler("h2", true)
+	c := newDryRunController(handler1, handler2)
+	handler2.eventLoop = c
+
+	pod1 := &podmodel.Pod{Name: "pod1", Namespace: "default"}
+	pod1Updated := &podmodel.Pod{Name: "pod1", Namespace: "default", IpAddress: "10.1.1.1"}
+	pod2 := &podmodel.Pod{Name: "pod2", Namespace: "default"}
+	c.kubeStateData[testResource]["pod1"] = pod1
+	c.internalConfig["h1/pod1"] = pod1
+	c.internalConfig["h2/pod1"] = pod1
+	handler1.pods["pod1"] = pod1
+	handler2.pods["pod1"] = pod1
+
+	// update of pod1 (previous value is filled by the Controller), new pod2
+	for _, ksChange := range []*api.KubeStateChange{
+		{Resource: testResource, Key: "pod1", NewValue: pod1Updated},
+		{Resource: testResource, Key: "pod2", NewValue: pod2},
+	} {
+		result, err := dryRun(c, ksChange)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Changes).To(HaveLen(2))
+		for _, change := range result.Changes {
+			Expect(change.NewValue).To(Equal(ksChange.NewValue))
+			if ksChange.Key == "pod1" {
+				Expect(change.Operation).To(Equal(UpdateValue))
+				Expect(change.PrevValue).To(Equal(pod1))
+			} else {
+				Expect(change.Operation).To(Equal(CreateValue))
+			}
+		}
+
+		// update in the forward order, revert in the reverse order
+		Expect(result.Handlers).To(HaveLen(4))
+		Expect(result.Handlers[0].Handler).To(Equal("h1"))
+		Expect(result.Handlers[1].Handler).To(Equal("h2"))
+		Expect(result.Handlers[2].Handler).To(Equal("h2"))
+		Expect(result.Handlers[2].Revert).To(BeTrue())
+		Expect(result.Handlers[3].Handler).To(Equal("h1"))
+		Expect(result.Handlers[3].Revert).To(BeTrue())
+
+		// follow-up event was not queued, only listed
+		Expect(result.FollowUpEvents).To(Equal([]string{"Test Event " + ksChange.Key}))
+		Expect(c.followUpEventQueue).To(BeNil())
+		Expect(c.dryRun).To(BeNil())
+	}
+
+	// nothing has changed
+	Expect(handler1.pods).To(Equal(map[string]*podmodel.Pod{"pod1": pod1}))
+	Expect(handler2.pods).To(Equal(map[string]*podmodel.Pod{"pod1": pod1}))
+	Expect(c.internalConfig).To(HaveLen(2))
+	Expect(c.internalConfig["h1/pod1"]).To(Equal(pod1))
+	Expect(c.kubeStateData[testResource]).To(HaveLen(1))
+	Expect(c.kubeStateData[testResource]["pod1"]).To(Equal(pod1))
+	Expect(c.txn).To(BeNil())
+}
+
+func TestDryRunFailedUpdate(t *testing.T) {
+	RegisterTestingT(t)
+
+	handler1 := newDryRunHandler("h1", true)
+	handler2 := newDryRunHandler("h2", true)
+	handler2.fail = true
+	c := newDryRunController(handler1, handler2)
+
+	pod := &podmodel.Pod{Name: "pod1", Namespace: "default"}
+	result, err := dryRun(c, &api.KubeStateChange{Resource: testResource, Key: "pod1", NewValue: pod})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result.Changes).To(BeEmpty())
+	Expect(result.Handlers).To(HaveLen(3))
+	Expect(result.Handlers[1].ErrorStr).To(Equal("update failed"))
+	Expect(result.Handlers[2].Handler).To(Equal("h1"))
+	Expect(result.Handlers[2].Revert).To(BeTrue())
+	Expect(handler1.pods).To(BeEmpty())
+}
+
+func TestDryRunNotSupported(t *testing.T) {
+	RegisterTestingT(t)
+
+	handler1 := newDryRunHandler("h1", true)
+	handler2 := newDryRunHandler("h2", false)
+	c := newDryRunController(handler1, handler2)
+	pod := &podmodel.Pod{Name: "pod1", Namespace: "default"}
+
+	// handler unable to revert
+	_, err := dryRun(c, &api.KubeStateChange{Resource: testResource, Key: "pod1", NewValue: pod})
+	Expect(errors.Is(err, ErrDryRunNotSupported)).To(BeTrue())
+	Expect(err.Error()).To(ContainSubstring("h2"))
+	Expect(handler1.pods).To(BeEmpty())
+
+	// unknown resource
+	handler2.canRevert = true
+	_, err = dryRun(c, &api.KubeStateChange{Resource: "unknown", Key: "pod1", NewValue: pod})
+	Expect(errors.Is(err, ErrDryRunNotSupported)).To(BeTrue())
+
+	// resync
+	_, err = dryRun(c, api.NewDBResync())
+	Expect(errors.Is(err, ErrDryRunNotSupported)).To(BeTrue())
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+const (
+	// FileHistorySink selects the append-only file backend for the persisted
+	// event history.
+	FileHistorySink = "file"
+
+	// BoltHistorySink selects the Bolt backend for the persisted event history.
+	BoltHistorySink = "bolt"
+
+	// default locations of the persisted event history
+	defaultFileHistorySinkPath = "/var/bolt/event-history.log"
+	defaultBoltHistorySinkPath = "/var/bolt/event-history.db"
+)
+
+// EventHistorySink persists records of processed events, so that the history
+// survives restarts of the agent.
+type EventHistorySink interface {
+	// Append persists the record of a finalized event.
+	// <payload> is nil if the event is not serializable.
+	Append(record *EventRecord, payload *api.EventPayload) error
+
+	// Query returns persisted records matching the filter, ordered from the oldest
+	// to the newest. The first <offset> matching records are skipped and at most
+	// <limit> records are returned (limit <= 0 means no limit).
+	// <total> is the number of all persisted records matching the filter.
+	Query(filter *EventHistoryFilter, offset, limit int) (records []*PersistedEventRecord, total int, err error)
+
+	// Close releases resources allocated by the sink.
+	Close() error
+}
+
+// PersistedEventRecord is an EventRecord as stored by EventHistorySink.
+// Attributes used for filtering are kept alongside the JSON-encoded record.
+type PersistedEventRecord struct {
+	// Index is the position of the record in the persisted history,
+	// unique across agent restarts (unlike EventRecord.SeqNum).
+	Index uint64
+
+	// AgentStart is the start time of the agent run that processed the event.
+	AgentStart time.Time
+
+	SeqNum    uint64
+	Name      string
+	Method    api.EventMethodType
+	Handlers  []string
+	WithError bool
+
+	// Record is the JSON-encoded EventRecord (including the recorded transaction).
+	Record json.RawMessage
+
+	// Payload is the content of the event, needed to replay the event
+	// (nil if the event is not serializable).
+	Payload *api.EventPayload `json:",omitempty"`
+}
+
+// EventHistoryFilter selects event records from the history.
+// Zero-value filter matches every record.
+type EventHistoryFilter struct {
+	Name       string               // event name (as returned by Event.GetName())
+	Handler    string               // name of the handler that processed the event
+	Method     *api.EventMethodType // event method type
+	ErrorsOnly bool                 // only events processed with error
+}
+
+// newPersistedEventRecord prepares record of the event for persisting.
+func newPersistedEventRecord(agentStart time.Time, record *EventRecord, payload *api.EventPayload) (*PersistedEventRecord, error) {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var handlers []string
+	for _, handlerRec := range record.Handlers {
+		if !handlerRec.Revert {
+			handlers = append(handlers, handlerRec.Handler)
+		}
+	}
+	return &PersistedEventRecord{
+		AgentStart: agentStart,
+		SeqNum:     record.SeqNum,
+		Name:       record.Name,
+		Method:     record.Method,
+		Handlers:   handlers,
+		WithError:  eventRecordWithError(record),
+		Record:     encoded,
+		Payload:    payload,
+	}, nil
+}
+
+// eventRecordWithError returns true if the processing of the event has failed.
+func eventRecordWithError(record *EventRecord) bool {
+	if record.TxnError != nil || record.TxnErrorStr != "" {
+		return true
+	}
+	for _, handlerRec := range record.Handlers {
+		if handlerRec.Error != nil || handlerRec.ErrorStr != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesRecord returns true if the in-memory event record is selected
+// by the filter.
+func (f *EventHistoryFilter) MatchesRecord(record *EventRecord) bool {
+	var handlers []string
+	for _, handlerRec := range record.Handlers {
+		if !handlerRec.Revert {
+			handlers = append(handlers, handlerRec.Handler)
+		}
+	}
+	return f.matches(record.Name, record.Method, handlers, eventRecordWithError(record))
+}
+
+// MatchesPersisted returns true if the persisted event record is selected
+// by the filter.
+func (f *EventHistoryFilter) MatchesPersisted(record *PersistedEventRecord) bool {
+	return f.matches(record.Name, record.Method, record.Handlers, record.WithError)
+}
+
+// matches implements filtering for both in-memory and persisted records.
+func (f *EventHistoryFilter) matches(name string, method api.EventMethodType, handlers []string, withError bool) bool {
+	if f == nil {
+		return true
+	}
+	if f.Name != "" && f.Name != name {
+		return false
+	}
+	if f.Method != nil && *f.Method != method {
+		return false
+	}
+	if f.ErrorsOnly && !withError {
+		return false
+	}
+	if f.Handler != "" {
+		for _, handler := range handlers {
+			if handler == f.Handler {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// isEmpty returns true if the filter selects every record.
+func (f *EventHistoryFilter) isEmpty() bool {
+	return f == nil || (f.Name == "" && f.Handler == "" && f.Method == nil && !f.ErrorsOnly)
+}
+
+// newEventHistorySink creates sink for the persisted event history based
+// on the configuration.
+func newEventHistorySink(config *Config, agentStart time.Time) (EventHistorySink, error) {
+	switch config.EventHistorySink {
+	case "":
+		return nil, nil
+	case FileHistorySink:
+		path := config.EventHistorySinkPath
+		if path == "" {
+			path = defaultFileHistorySinkPath
+		}
+		maxSize := int64(config.EventHistorySinkMaxSize) * 1024 * 1024
+		return newFileHistorySink(path, maxSize, agentStart)
+	case BoltHistorySink:
+		path := config.EventHistorySinkPath
+		if path == "" {
+			path = defaultBoltHistorySinkPath
+		}
+		maxSize := int64(config.EventHistorySinkMaxSize) * 1024 * 1024
+		return newBoltHistorySink(path, maxSize, agentStart)
+	default:
+		return nil, fmt.Errorf("unsupported event history sink: %s", config.EventHistorySink)
+	}
+}
+
+// recordPager collects a page of records matching a filter while counting
+// the total number of matches.
+type recordPager struct {
+	filter  *EventHistoryFilter
+	offset  int
+	limit   int
+	total   int
+	records []*PersistedEventRecord
+}
+
+// add processes the next persisted record.
+// Returns true if the record was selected into the page.
+func (p *recordPager) add(record *PersistedEventRecord) (selected bool) {
+	if !p.filter.MatchesPersisted(record) {
+		return false
+	}
+	if p.total >= p.offset && (p.limit <= 0 || len(p.records) < p.limit) {
+		p.records = append(p.records, record)
+		selected = true
+	}
+	p.total++
+	return selected
+}
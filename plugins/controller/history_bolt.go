@@ -0,0 +1,276 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+const (
+	// bucket with persisted event records, keyed by big-endian encoded index
+	eventHistoryBucket = "event-history"
+
+	// bucket with attributes of the persisted records used for filtering
+	// (PersistedEventRecord without Record & Payload), keyed the same way
+	eventHistoryAttrsBucket = "event-history-attrs"
+
+	// how long to wait for the Bolt file lock to be released
+	boltHistoryLockTimeout = 5 * time.Second
+)
+
+// boltHistorySink persists event records into a Bolt database.
+//
+// Attributes used for filtering are stored in a separate bucket and loaded
+// into memory together with the size of every record, so that queries only
+// decode the records that are returned.
+//
+// Once the total size of the stored records exceeds the maximum size, the oldest
+// records are removed. Bolt re-uses the freed pages for new records, i.e. the
+// database file does not grow (significantly) beyond the maximum size.
+type boltHistorySink struct {
+	sync.Mutex
+
+	db         *bolt.DB
+	maxSize    int64 // <= 0 means unlimited
+	size       int64 // total size of the stored records
+	agentStart time.Time
+
+	entries []*boltHistoryEntry // ordered by index
+}
+
+// boltHistoryEntry indexes a single record stored in the Bolt database.
+type boltHistoryEntry struct {
+	attrs *PersistedEventRecord // record without Record & Payload (used for filtering)
+	size  int
+}
+
+// newBoltHistorySink opens (or creates) Bolt database with the persisted event history.
+func newBoltHistorySink(path string, maxSize int64, agentStart time.Time) (EventHistorySink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: boltHistoryLockTimeout})
+	if err != nil {
+		return nil, err
+	}
+	sink := &boltHistorySink{
+		db:         db,
+		maxSize:    maxSize,
+		agentStart: agentStart,
+	}
+	if err = db.Update(sink.loadIndex); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+// loadIndex creates the buckets (if needed) and loads attributes of all
+// stored records into memory.
+// Attributes missing for records stored by older versions of the agent
+// are re-built from the records.
+func (s *boltHistorySink) loadIndex(tx *bolt.Tx) error {
+	records, err := tx.CreateBucketIfNotExists([]byte(eventHistoryBucket))
+	if err != nil {
+		return err
+	}
+	attrsBucket, err := tx.CreateBucketIfNotExists([]byte(eventHistoryAttrsBucket))
+	if err != nil {
+		return err
+	}
+	// collect first, bucket must not be modified while iterating over it
+	var missingAttrs []*PersistedEventRecord
+	cursor := records.Cursor()
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		attrs := &PersistedEventRecord{}
+		if encoded := attrsBucket.Get(key); encoded != nil {
+			err = json.Unmarshal(encoded, attrs)
+		} else {
+			err = json.Unmarshal(value, attrs)
+			attrs = recordAttributes(attrs)
+			missingAttrs = append(missingAttrs, attrs)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode event record %d: %v",
+				binary.BigEndian.Uint64(key), err)
+		}
+		s.entries = append(s.entries, &boltHistoryEntry{attrs: attrs, size: len(value)})
+		s.size += int64(len(value))
+	}
+	for _, attrs := range missingAttrs {
+		if err := putBoltHistoryAttrs(attrsBucket, attrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Append stores the record of a finalized event under the next free index
+// and removes the oldest records if the maximum size was exceeded.
+func (s *boltHistorySink) Append(record *EventRecord, payload *api.EventPayload) error {
+	s.Lock()
+	defer s.Unlock()
+
+	persisted, err := newPersistedEventRecord(s.agentStart, record, payload)
+	if err != nil {
+		return err
+	}
+	var (
+		size    int
+		trimmed int
+	)
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		records := tx.Bucket([]byte(eventHistoryBucket))
+		attrsBucket := tx.Bucket([]byte(eventHistoryAttrsBucket))
+		seq, err := records.NextSequence()
+		if err != nil {
+			return err
+		}
+		persisted.Index = seq - 1 // sequence starts at 1
+		encoded, err := json.Marshal(persisted)
+		if err != nil {
+			return err
+		}
+		if err = records.Put(boltHistoryKey(persisted.Index), encoded); err != nil {
+			return err
+		}
+		if err = putBoltHistoryAttrs(attrsBucket, recordAttributes(persisted)); err != nil {
+			return err
+		}
+		size = len(encoded)
+
+		// remove the oldest records to fit into the maximum size
+		// (the new record is always kept)
+		totalSize := s.size + int64(size)
+		for ; s.maxSize > 0 && totalSize > s.maxSize && trimmed < len(s.entries); trimmed++ {
+			key := boltHistoryKey(s.entries[trimmed].attrs.Index)
+			if err := records.Delete(key); err != nil {
+				return err
+			}
+			if err := attrsBucket.Delete(key); err != nil {
+				return err
+			}
+			totalSize -= int64(s.entries[trimmed].size)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// update the in-memory index only once the transaction was committed
+	for _, entry := range s.entries[:trimmed] {
+		s.size -= int64(entry.size)
+	}
+	s.entries = append(s.entries[trimmed:], &boltHistoryEntry{
+		attrs: recordAttributes(persisted),
+		size:  size,
+	})
+	s.size += int64(size)
+	return nil
+}
+
+// Query returns records matching the filter. Only the records of the requested
+// page are read from the database and decoded.
+func (s *boltHistorySink) Query(filter *EventHistoryFilter, offset, limit int) (records []*PersistedEventRecord, total int, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	pager := &recordPager{filter: filter, offset: offset, limit: limit}
+	for _, entry := range s.entries {
+		pager.add(entry.attrs)
+	}
+	err = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(eventHistoryBucket))
+		for _, attrs := range pager.records {
+			value := bucket.Get(boltHistoryKey(attrs.Index))
+			if value == nil {
+				return fmt.Errorf("event record %d is missing", attrs.Index)
+			}
+			record := &PersistedEventRecord{}
+			if err := json.Unmarshal(value, record); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, pager.total, nil
+}
+
+// Close closes the Bolt database.
+func (s *boltHistorySink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.db.Close()
+}
+
+// ReadBoltEventHistory reads all records of the event history persisted
+// in the Bolt database, ordered from the oldest to the newest.
+// The database is opened read-only, but it cannot be read while it is open
+// by a running agent (copy the database file first).
+func ReadBoltEventHistory(path string) (records []*PersistedEventRecord, err error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: boltHistoryLockTimeout, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(eventHistoryBucket))
+		if bucket == nil {
+			return fmt.Errorf("bucket %s not found", eventHistoryBucket)
+		}
+		return bucket.ForEach(func(key, value []byte) error {
+			record := &PersistedEventRecord{}
+			if err := json.Unmarshal(value, record); err != nil {
+				return fmt.Errorf("failed to decode event record %d: %v",
+					binary.BigEndian.Uint64(key), err)
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	return records, err
+}
+
+// putBoltHistoryAttrs stores attributes of a persisted record.
+func putBoltHistoryAttrs(bucket *bolt.Bucket, attrs *PersistedEventRecord) error {
+	encoded, err := json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(boltHistoryKey(attrs.Index), encoded)
+}
+
+// boltHistoryKey returns key under which the record with the given index is stored.
+// Big-endian encoding keeps the records ordered by the index.
+func boltHistoryKey(index uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, index)
+	return key
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+// eventHistorySinkQueueSize is the maximum number of event records waiting
+// to be persisted.
+const eventHistorySinkQueueSize = 1000
+
+var (
+	// errHistorySinkQueueFull is returned by bufferedHistorySink.Append when
+	// the writer cannot keep up with the rate of processed events.
+	errHistorySinkQueueFull = errors.New("queue of records waiting to be persisted is full")
+
+	// errHistorySinkClosed is returned by bufferedHistorySink when used after Close.
+	errHistorySinkClosed = errors.New("event history sink is closed")
+)
+
+// bufferedHistorySink moves persisting of event records out of the event loop.
+// Records are queued by Append and written into the wrapped sink by a separate
+// go routine. Query waits for the queued records to be written first,
+// therefore the already appended records are always returned.
+type bufferedHistorySink struct {
+	sync.RWMutex // protects <closed>
+
+	sink    EventHistorySink
+	onError func(record *EventRecord, err error)
+	queue   chan *historySinkRequest
+	closed  bool
+	done    chan struct{}
+}
+
+// historySinkRequest is either a record to persist or a request to signal
+// (by closing <flushed>) that all the previously queued records were written.
+type historySinkRequest struct {
+	record  *EventRecord
+	payload *api.EventPayload
+	flushed chan struct{}
+}
+
+// newBufferedHistorySink wraps the sink with a buffered writer.
+// <onError> is called (from the writer go routine) for every record that failed
+// to be persisted.
+func newBufferedHistorySink(sink EventHistorySink, queueSize int,
+	onError func(record *EventRecord, err error)) *bufferedHistorySink {
+	s := &bufferedHistorySink{
+		sink:    sink,
+		onError: onError,
+		queue:   make(chan *historySinkRequest, queueSize),
+		done:    make(chan struct{}),
+	}
+	go s.writer()
+	return s
+}
+
+// Append queues the record to be persisted. The record is dropped (and error
+// returned) if the queue is full.
+func (s *bufferedHistorySink) Append(record *EventRecord, payload *api.EventPayload) error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return errHistorySinkClosed
+	}
+	select {
+	case s.queue <- &historySinkRequest{record: record, payload: payload}:
+		return nil
+	default:
+		return errHistorySinkQueueFull
+	}
+}
+
+// Query waits for the queued records to be persisted and then queries
+// the wrapped sink.
+func (s *bufferedHistorySink) Query(filter *EventHistoryFilter, offset, limit int) (records []*PersistedEventRecord, total int, err error) {
+	s.RLock()
+	if s.closed {
+		s.RUnlock()
+		return nil, 0, errHistorySinkClosed
+	}
+	flushed := make(chan struct{})
+	s.queue <- &historySinkRequest{flushed: flushed}
+	s.RUnlock()
+
+	<-flushed
+	return s.sink.Query(filter, offset, limit)
+}
+
+// Close persists the queued records, stops the writer and closes the wrapped sink.
+func (s *bufferedHistorySink) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return errHistorySinkClosed
+	}
+	s.closed = true
+	close(s.queue)
+	s.Unlock()
+
+	<-s.done
+	return s.sink.Close()
+}
+
+// writer persists queued records until the queue is closed.
+func (s *bufferedHistorySink) writer() {
+	defer close(s.done)
+	for req := range s.queue {
+		if req.flushed != nil {
+			close(req.flushed)
+			continue
+		}
+		if err := s.sink.Append(req.record, req.payload); err != nil && s.onError != nil {
+			s.onError(req.record, err)
+		}
+	}
+}
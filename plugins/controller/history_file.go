@@ -0,0 +1,271 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+// rotatedFileSuffix is appended to the path of the history file when it gets
+// rotated after reaching the maximum size.
+const rotatedFileSuffix = ".1"
+
+// fileHistorySink persists event records into a local append-only file,
+// one JSON-encoded PersistedEventRecord per line.
+//
+// Once the file reaches the maximum size, it is rotated: the file is renamed
+// to "<path>.1" (replacing the previously rotated file) and a new file is started.
+// The persisted history therefore takes at most twice the maximum size.
+//
+// Attributes used for filtering are indexed in memory together with the location
+// of every record, so that queries only read the records that are returned.
+type fileHistorySink struct {
+	sync.Mutex
+
+	path       string
+	maxSize    int64 // <= 0 means unlimited
+	file       *os.File
+	size       int64 // size of the current file
+	agentStart time.Time
+	nextIndex  uint64
+
+	rotated []*fileHistoryEntry // records of the rotated file
+	current []*fileHistoryEntry // records of the current file
+}
+
+// fileHistoryEntry indexes a single record stored in a history file.
+type fileHistoryEntry struct {
+	attrs  *PersistedEventRecord // record without Record & Payload (used for filtering)
+	offset int64
+	size   int
+}
+
+// newFileHistorySink opens (or creates) the file with the persisted event history.
+func newFileHistorySink(path string, maxSize int64, agentStart time.Time) (EventHistorySink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	sink := &fileHistorySink{
+		path:       path,
+		maxSize:    maxSize,
+		agentStart: agentStart,
+	}
+
+	// index the rotated file (if any)
+	rotated, _, err := indexHistoryFile(path + rotatedFileSuffix)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sink.rotated = rotated
+
+	// index the current file and learn the index of the next record
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	current, validSize, err := indexHistoryFile(path)
+	if err == nil {
+		// drop incomplete record left behind by a crash during write
+		err = file.Truncate(validSize)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	sink.file = file
+	sink.size = validSize
+	sink.current = current
+	if last := sink.lastEntry(); last != nil {
+		sink.nextIndex = last.attrs.Index + 1
+	}
+	return sink, nil
+}
+
+// Append writes the record of a finalized event at the end of the file.
+func (s *fileHistorySink) Append(record *EventRecord, payload *api.EventPayload) error {
+	s.Lock()
+	defer s.Unlock()
+
+	persisted, err := newPersistedEventRecord(s.agentStart, record, payload)
+	if err != nil {
+		return err
+	}
+	persisted.Index = s.nextIndex
+	encoded, err := json.Marshal(persisted)
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(encoded)) > s.maxSize {
+		if err = s.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err = s.file.Write(encoded); err != nil {
+		return err
+	}
+	s.current = append(s.current, &fileHistoryEntry{
+		attrs:  recordAttributes(persisted),
+		offset: s.size,
+		size:   len(encoded),
+	})
+	s.size += int64(len(encoded))
+	s.nextIndex++
+	return nil
+}
+
+// Query returns records matching the filter. Only the records of the requested
+// page are read from the disk.
+func (s *fileHistorySink) Query(filter *EventHistoryFilter, offset, limit int) (records []*PersistedEventRecord, total int, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	pager := &recordPager{filter: filter, offset: offset, limit: limit}
+	var rotatedPage, currentPage []*fileHistoryEntry
+	for _, entry := range s.rotated {
+		if pager.add(entry.attrs) {
+			rotatedPage = append(rotatedPage, entry)
+		}
+	}
+	for _, entry := range s.current {
+		if pager.add(entry.attrs) {
+			currentPage = append(currentPage, entry)
+		}
+	}
+
+	records, err = readHistoryEntries(s.path+rotatedFileSuffix, rotatedPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	currentRecords, err := readHistoryEntries(s.path, currentPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(records, currentRecords...), pager.total, nil
+}
+
+// Close closes the underlying file.
+func (s *fileHistorySink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.file.Close()
+}
+
+// rotate replaces the rotated file with the current one and starts a new file.
+func (s *fileHistorySink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(s.path, s.path+rotatedFileSuffix); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_RDWR|os.O_APPEND|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	s.size = 0
+	s.rotated = s.current
+	s.current = nil
+	return nil
+}
+
+// lastEntry returns the most recently appended record (nil if there is none).
+func (s *fileHistorySink) lastEntry() *fileHistoryEntry {
+	if len(s.current) > 0 {
+		return s.current[len(s.current)-1]
+	}
+	if len(s.rotated) > 0 {
+		return s.rotated[len(s.rotated)-1]
+	}
+	return nil
+}
+
+// recordAttributes returns copy of the record with only the attributes
+// used for filtering.
+func recordAttributes(record *PersistedEventRecord) *PersistedEventRecord {
+	attrs := *record
+	attrs.Record = nil
+	attrs.Payload = nil
+	return &attrs
+}
+
+// indexHistoryFile reads the history file and indexes every record stored inside.
+// Incomplete last line (e.g. after a crash during write) is skipped.
+// Returned is also the size of the file content with complete records.
+func indexHistoryFile(path string) (entries []*fileHistoryEntry, validSize int64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return entries, validSize, nil
+		}
+		if err != nil {
+			return entries, validSize, err
+		}
+		record := &PersistedEventRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return entries, validSize, fmt.Errorf("failed to decode event record from %s:%d: %v",
+				path, lineNum, err)
+		}
+		entries = append(entries, &fileHistoryEntry{
+			attrs:  recordAttributes(record),
+			offset: validSize,
+			size:   len(line),
+		})
+		validSize += int64(len(line))
+	}
+}
+
+// readHistoryEntries reads the given indexed records from the history file.
+func readHistoryEntries(path string, entries []*fileHistoryEntry) (records []*PersistedEventRecord, err error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	for _, entry := range entries {
+		line := make([]byte, entry.size)
+		if _, err := file.ReadAt(line, entry.offset); err != nil {
+			return nil, err
+		}
+		record := &PersistedEventRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, fmt.Errorf("failed to decode event record from %s: %v", path, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
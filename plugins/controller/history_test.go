@@ -0,0 +1,449 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	. "github.com/onsi/gomega"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+func testEventRecord(seqNum uint64, name string, method api.EventMethodType, handlers ...string) *EventRecord {
+	record := &EventRecord{
+		SeqNum: seqNum,
+		Name:   name,
+		Method: method,
+	}
+	for _, handler := range handlers {
+		record.Handlers = append(record.Handlers, &EventHandlingRecord{Handler: handler})
+	}
+	return record
+}
+
+func withHandlerError(record *EventRecord, err error) *EventRecord {
+	handlerRec := record.Handlers[len(record.Handlers)-1]
+	handlerRec.Error = err
+	handlerRec.ErrorStr = err.Error()
+	return record
+}
+
+func newTestHistoryDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "event-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestEventHistoryFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	update := api.Update
+	record := testEventRecord(1, "Pod Added", api.Update, "ipam", "ipnet")
+	failed := withHandlerError(testEventRecord(2, "Pod Added", api.Update, "ipam", "ipnet"),
+		errors.New("failed to connect pod"))
+	resync := testEventRecord(3, "Periodic Healing", api.DownstreamResync, "ipnet")
+
+	var filter *EventHistoryFilter
+	Expect(filter.MatchesRecord(record)).To(BeTrue())
+	filter = &EventHistoryFilter{}
+	Expect(filter.MatchesRecord(record)).To(BeTrue())
+	Expect(filter.MatchesRecord(resync)).To(BeTrue())
+
+	filter = &EventHistoryFilter{Name: "Pod Added"}
+	Expect(filter.MatchesRecord(record)).To(BeTrue())
+	Expect(filter.MatchesRecord(resync)).To(BeFalse())
+
+	filter = &EventHistoryFilter{Handler: "ipam"}
+	Expect(filter.MatchesRecord(record)).To(BeTrue())
+	Expect(filter.MatchesRecord(resync)).To(BeFalse())
+
+	filter = &EventHistoryFilter{Method: &update}
+	Expect(filter.MatchesRecord(record)).To(BeTrue())
+	Expect(filter.MatchesRecord(resync)).To(BeFalse())
+
+	filter = &EventHistoryFilter{ErrorsOnly: true}
+	Expect(filter.MatchesRecord(record)).To(BeFalse())
+	Expect(filter.MatchesRecord(failed)).To(BeTrue())
+
+	// the same results for the persisted records
+	for _, rec := range []*EventRecord{record, failed, resync} {
+		persisted, err := newPersistedEventRecord(time.Now(), rec, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.MatchesPersisted(persisted)).To(Equal(filter.MatchesRecord(rec)))
+	}
+}
+
+func TestPersistedRecordErrors(t *testing.T) {
+	RegisterTestingT(t)
+
+	record := withHandlerError(testEventRecord(1, "Pod Added", api.Update, "ipnet"),
+		errors.New("failed to connect pod"))
+	record.TxnError = errors.New("failed to apply txn")
+	record.TxnErrorStr = record.TxnError.Error()
+
+	persisted, err := newPersistedEventRecord(time.Now(), record, nil)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(persisted.WithError).To(BeTrue())
+
+	// errors are preserved in the encoded record as strings
+	decoded := &EventRecord{}
+	Expect(json.Unmarshal(persisted.Record, decoded)).To(Succeed())
+	Expect(decoded.TxnErrorStr).To(Equal("failed to apply txn"))
+	Expect(decoded.Handlers).To(HaveLen(1))
+	Expect(decoded.Handlers[0].ErrorStr).To(Equal("failed to connect pod"))
+	Expect(eventRecordWithError(decoded)).To(BeTrue())
+}
+
+func testHistorySink(t *testing.T, open func(agentStart time.Time) (EventHistorySink, error)) {
+	start := time.Now()
+	sink, err := open(start)
+	Expect(err).ToNot(HaveOccurred())
+
+	for i := 0; i < 10; i++ {
+		record := testEventRecord(uint64(i), fmt.Sprintf("event-%d", i%2), api.Update, "ipnet")
+		if i%3 == 0 {
+			record = withHandlerError(record, errors.New("error"))
+		}
+		Expect(sink.Append(record, nil)).To(Succeed())
+	}
+
+	// query all
+	records, total, err := sink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(10))
+	Expect(records).To(HaveLen(10))
+	for i, record := range records {
+		Expect(record.Index).To(BeEquivalentTo(i))
+		Expect(record.SeqNum).To(BeEquivalentTo(i))
+		Expect(record.Record).ToNot(BeEmpty())
+	}
+
+	// paging
+	records, total, err = sink.Query(nil, 3, 4)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(10))
+	Expect(records).To(HaveLen(4))
+	Expect(records[0].Index).To(BeEquivalentTo(3))
+	Expect(records[3].Index).To(BeEquivalentTo(6))
+
+	// filtering
+	records, total, err = sink.Query(&EventHistoryFilter{Name: "event-1"}, 1, 2)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(5))
+	Expect(records).To(HaveLen(2))
+	Expect(records[0].Index).To(BeEquivalentTo(3))
+	Expect(records[1].Index).To(BeEquivalentTo(5))
+
+	records, total, err = sink.Query(&EventHistoryFilter{ErrorsOnly: true}, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(4))
+	Expect(records).To(HaveLen(4))
+	for _, record := range records {
+		Expect(record.WithError).To(BeTrue())
+	}
+
+	// records survive re-opening of the sink, indexing continues
+	Expect(sink.Close()).To(Succeed())
+	restart := start.Add(time.Minute)
+	sink, err = open(restart)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sink.Append(testEventRecord(0, "event-0", api.FullResync, "ipnet"), nil)).To(Succeed())
+
+	records, total, err = sink.Query(nil, 9, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(11))
+	Expect(records).To(HaveLen(2))
+	Expect(records[0].Index).To(BeEquivalentTo(9))
+	Expect(records[0].AgentStart.Equal(start)).To(BeTrue())
+	Expect(records[1].Index).To(BeEquivalentTo(10))
+	Expect(records[1].SeqNum).To(BeEquivalentTo(0))
+	Expect(records[1].AgentStart.Equal(restart)).To(BeTrue())
+	Expect(sink.Close()).To(Succeed())
+}
+
+func TestFileHistorySink(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.log")
+	testHistorySink(t, func(agentStart time.Time) (EventHistorySink, error) {
+		return newFileHistorySink(path, 0, agentStart)
+	})
+}
+
+func TestBoltHistorySink(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.db")
+	testHistorySink(t, func(agentStart time.Time) (EventHistorySink, error) {
+		return newBoltHistorySink(path, 0, agentStart)
+	})
+}
+
+func TestBufferedHistorySink(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.db")
+	testHistorySink(t, func(agentStart time.Time) (EventHistorySink, error) {
+		sink, err := newBoltHistorySink(path, 0, agentStart)
+		if err != nil {
+			return nil, err
+		}
+		return newBufferedHistorySink(sink, eventHistorySinkQueueSize, func(record *EventRecord, err error) {
+			t.Errorf("failed to persist record %d: %v", record.SeqNum, err)
+		}), nil
+	})
+}
+
+// blockedHistorySink blocks Append until unblocked.
+type blockedHistorySink struct {
+	EventHistorySink
+	unblock chan struct{}
+}
+
+func (s *blockedHistorySink) Append(record *EventRecord, payload *api.EventPayload) error {
+	<-s.unblock
+	return s.EventHistorySink.Append(record, payload)
+}
+
+func TestBufferedHistorySinkQueueFull(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.log")
+	fileSink, err := newFileHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	blocked := &blockedHistorySink{EventHistorySink: fileSink, unblock: make(chan struct{})}
+	sink := newBufferedHistorySink(blocked, 2, nil)
+
+	// the first record is taken by the writer, the next two fill the queue
+	Expect(sink.Append(testEventRecord(0, "event", api.Update), nil)).To(Succeed())
+	Eventually(func() int { return len(sink.queue) }).Should(BeZero())
+	Expect(sink.Append(testEventRecord(1, "event", api.Update), nil)).To(Succeed())
+	Expect(sink.Append(testEventRecord(2, "event", api.Update), nil)).To(Succeed())
+	Expect(sink.Append(testEventRecord(3, "event", api.Update), nil)).To(Equal(errHistorySinkQueueFull))
+
+	// queued records are persisted before the sink is closed
+	close(blocked.unblock)
+	Expect(sink.Close()).To(Succeed())
+	Expect(sink.Append(testEventRecord(4, "event", api.Update), nil)).To(Equal(errHistorySinkClosed))
+
+	fileSink, err = newFileHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	defer fileSink.Close()
+	records, total, err := fileSink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(3))
+	for i, record := range records {
+		Expect(record.SeqNum).To(BeEquivalentTo(i))
+	}
+}
+
+func TestBoltHistorySinkTrimming(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	// learn the size of a single stored record
+	path := filepath.Join(dir, "event-history.db")
+	sink, err := newBoltHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sink.Append(testEventRecord(0, "event", api.Update), nil)).To(Succeed())
+	recordSize := sink.(*boltHistorySink).size
+	Expect(sink.Close()).To(Succeed())
+	Expect(os.Remove(path)).To(Succeed())
+
+	// the database fits 3 records
+	maxSize := 3*recordSize + recordSize/2
+	sink, err = newBoltHistorySink(path, maxSize, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	for i := 0; i < 8; i++ {
+		Expect(sink.Append(testEventRecord(uint64(i), "event", api.Update), nil)).To(Succeed())
+	}
+
+	records, total, err := sink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(3))
+	for i, record := range records {
+		Expect(record.Index).To(BeEquivalentTo(i + 5))
+	}
+
+	// trimmed records are removed from the database, not only from the index
+	Expect(sink.Close()).To(Succeed())
+	sink, err = newBoltHistorySink(path, maxSize, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	defer sink.Close()
+	Expect(sink.(*boltHistorySink).size).To(BeNumerically("<=", maxSize))
+	records, total, err = sink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(3))
+	Expect(records[0].Index).To(BeEquivalentTo(5))
+}
+
+func TestReadBoltEventHistory(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.db")
+	sink, err := newBoltHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	for i := 0; i < 3; i++ {
+		Expect(sink.Append(testEventRecord(uint64(i), "event", api.Update), nil)).To(Succeed())
+	}
+
+	// the database is locked while open by the sink
+	Expect(sink.Close()).To(Succeed())
+
+	records, err := ReadBoltEventHistory(path)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(records).To(HaveLen(3))
+	for i, record := range records {
+		Expect(record.Index).To(BeEquivalentTo(i))
+		Expect(record.Record).ToNot(BeEmpty())
+	}
+
+	_, err = ReadBoltEventHistory(filepath.Join(dir, "missing.db"))
+	Expect(err).To(HaveOccurred())
+}
+
+func TestBoltHistorySinkIndexRebuild(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.db")
+	sink, err := newBoltHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sink.Append(testEventRecord(0, "event-0", api.Update, "ipnet"), nil)).To(Succeed())
+	Expect(sink.Append(testEventRecord(1, "event-1", api.Update, "ipam"), nil)).To(Succeed())
+	Expect(sink.Close()).To(Succeed())
+
+	// simulate database written by a version without the attributes bucket
+	db, err := bolt.Open(path, 0644, nil)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket([]byte(eventHistoryAttrsBucket))
+	})).To(Succeed())
+	Expect(db.Close()).To(Succeed())
+
+	sink, err = newBoltHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	defer sink.Close()
+	records, total, err := sink.Query(&EventHistoryFilter{Handler: "ipam"}, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(1))
+	Expect(records[0].Name).To(Equal("event-1"))
+	Expect(records[0].Record).ToNot(BeEmpty())
+}
+
+func TestFileHistorySinkIncompleteRecord(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "event-history.log")
+	sink, err := newFileHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sink.Append(testEventRecord(0, "event", api.Update), nil)).To(Succeed())
+	Expect(sink.Close()).To(Succeed())
+
+	// simulate crash in the middle of writing a record
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	Expect(err).ToNot(HaveOccurred())
+	_, err = file.WriteString(`{"Index":1,"Name":"trunc`)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(file.Close()).To(Succeed())
+
+	sink, err = newFileHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	defer sink.Close()
+	Expect(sink.Append(testEventRecord(1, "event", api.Update), nil)).To(Succeed())
+
+	records, total, err := sink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(2))
+	Expect(records[1].Index).To(BeEquivalentTo(1))
+	Expect(records[1].SeqNum).To(BeEquivalentTo(1))
+}
+
+func TestFileHistorySinkRotation(t *testing.T) {
+	RegisterTestingT(t)
+	dir := newTestHistoryDir(t)
+	defer os.RemoveAll(dir)
+
+	// learn the size of a single encoded record
+	path := filepath.Join(dir, "event-history.log")
+	sink, err := newFileHistorySink(path, 0, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sink.Append(testEventRecord(0, "event", api.Update), nil)).To(Succeed())
+	Expect(sink.Close()).To(Succeed())
+	info, err := os.Stat(path)
+	Expect(err).ToNot(HaveOccurred())
+	recordSize := info.Size()
+	Expect(os.Remove(path)).To(Succeed())
+
+	// each file fits 3 records
+	maxSize := 3*recordSize + recordSize/2
+	sink, err = newFileHistorySink(path, maxSize, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	for i := 0; i < 8; i++ {
+		Expect(sink.Append(testEventRecord(uint64(i), "event", api.Update), nil)).To(Succeed())
+	}
+
+	// 6 records were rotated twice, only the last 2 files remain
+	records, total, err := sink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(5))
+	Expect(records).To(HaveLen(5))
+	for i, record := range records {
+		Expect(record.Index).To(BeEquivalentTo(i + 3))
+	}
+	for _, file := range []string{path, path + rotatedFileSuffix} {
+		info, err := os.Stat(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Size()).To(BeNumerically("<=", maxSize))
+	}
+
+	// both files are indexed after re-opening
+	Expect(sink.Close()).To(Succeed())
+	sink, err = newFileHistorySink(path, maxSize, time.Now())
+	Expect(err).ToNot(HaveOccurred())
+	defer sink.Close()
+	Expect(sink.Append(testEventRecord(0, "event", api.Update), nil)).To(Succeed())
+
+	records, total, err = sink.Query(nil, 0, 0)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(total).To(Equal(6))
+	Expect(records[0].Index).To(BeEquivalentTo(3))
+	Expect(records[5].Index).To(BeEquivalentTo(8))
+}
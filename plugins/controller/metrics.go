@@ -0,0 +1,163 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+const (
+	// histograms with latencies of the event processing
+	handlerDurationMetric   = "eventHandlerDuration"
+	txnCommitDurationMetric = "eventTxnCommitDuration"
+
+	// counters of the data plane drift detected by resyncs
+	resyncDriftedValuesMetric = "resyncDriftedValues"
+	resyncsWithDriftMetric    = "resyncsWithDrift"
+
+	nodeLabel      = "node"
+	handlerLabel   = "handler"
+	eventLabel     = "event"
+	operationLabel = "operation"
+
+	// operations executed by event handlers
+	resyncOperation = "Resync"
+	updateOperation = "Update"
+	revertOperation = "Revert"
+)
+
+// histogram buckets (in seconds) - from 1ms up to ~16s
+var latencyBuckets = prometheus.ExponentialBuckets(0.001, 2, 15)
+
+// eventMetrics collects latency histograms of the event processing
+// and counters of the resync drift.
+type eventMetrics struct {
+	handlerDuration     *prometheus.HistogramVec
+	txnCommitDuration   *prometheus.HistogramVec
+	resyncDriftedValues *prometheus.CounterVec
+	resyncsWithDrift    *prometheus.CounterVec
+}
+
+// registerEventMetrics creates histograms for the event processing latencies
+// and counters of the resync drift, and exports them through the stats collector.
+func (c *Controller) registerEventMetrics() {
+	if c.Stats == nil {
+		return
+	}
+	constLabels := prometheus.Labels{
+		nodeLabel: c.ServiceLabel.GetAgentLabel(),
+	}
+	metrics := &eventMetrics{
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        handlerDurationMetric,
+			Help:        "Time (in seconds) spent by event handlers in Resync/Update/Revert",
+			ConstLabels: constLabels,
+			Buckets:     latencyBuckets,
+		}, []string{handlerLabel, eventLabel, operationLabel}),
+		txnCommitDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        txnCommitDurationMetric,
+			Help:        "Time (in seconds) spent by KVScheduler committing event transactions",
+			ConstLabels: constLabels,
+			Buckets:     latencyBuckets,
+		}, []string{eventLabel}),
+		resyncDriftedValues: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        resyncDriftedValuesMetric,
+			Help:        "Total count of values added/modified/removed by healing and verification resyncs",
+			ConstLabels: constLabels,
+		}, []string{eventLabel, operationLabel}),
+		resyncsWithDrift: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        resyncsWithDriftMetric,
+			Help:        "Total count of healing and verification resyncs which found the data plane out-of-sync",
+			ConstLabels: constLabels,
+		}, []string{eventLabel}),
+	}
+	collectors := []prometheus.Collector{metrics.handlerDuration, metrics.txnCommitDuration,
+		metrics.resyncDriftedValues, metrics.resyncsWithDrift}
+	for _, collector := range collectors {
+		if err := c.Stats.RegisterCollector(collector); err != nil {
+			c.Log.Warnf("Failed to register metrics of the event processing: %v", err)
+			return
+		}
+	}
+	c.metrics = metrics
+}
+
+// recordHandlerDuration records how long it took the handler to execute the given
+// operation for the event. Warning is logged if the duration exceeds the configured
+// threshold.
+func (c *Controller) recordHandlerDuration(evRecord *EventRecord, event api.Event,
+	handlerRec *EventHandlingRecord, operation string, duration time.Duration) {
+
+	handlerRec.Duration = duration
+	if c.metrics != nil {
+		c.metrics.handlerDuration.WithLabelValues(handlerRec.Handler, eventType(event), operation).
+			Observe(duration.Seconds())
+	}
+	threshold := c.slowHandlerThreshold()
+	if threshold > 0 && duration > threshold {
+		c.Log.Warnf("Handler %s took %v (threshold: %v) to execute %s of the event %s: %s",
+			handlerRec.Handler, duration.Round(time.Millisecond), threshold, operation,
+			eventSeqNumToStr(evRecord.SeqNum), evRecord.Description)
+	}
+}
+
+// slowHandlerThreshold returns the configured duration of Resync/Update/Revert
+// above which a warning is logged (zero if disabled).
+func (c *Controller) slowHandlerThreshold() time.Duration {
+	return time.Duration(c.config.SlowHandlerThreshold) * time.Millisecond
+}
+
+// recordTxnCommitDuration records how long it took KVScheduler to commit
+// the transaction of the event.
+func (c *Controller) recordTxnCommitDuration(evRecord *EventRecord, event api.Event, duration time.Duration) {
+	evRecord.TxnDuration = duration
+	if c.metrics != nil {
+		c.metrics.txnCommitDuration.WithLabelValues(eventType(event)).Observe(duration.Seconds())
+	}
+}
+
+// recordResyncDiff counts values changed by the resync and logs a warning
+// if the data plane was out-of-sync.
+func (c *Controller) recordResyncDiff(evRecord *EventRecord, event api.Event) {
+	diff := evRecord.ResyncDiff
+	if diff.InSync() {
+		return
+	}
+	counts := diff.countByOperation()
+	c.Log.Warnf("Resync %s has found the data plane out-of-sync: %d added, %d modified, %d removed value(s)",
+		eventSeqNumToStr(evRecord.SeqNum), counts[resyncAdded], counts[resyncModified], counts[resyncRemoved])
+	if c.metrics != nil {
+		evType := eventType(event)
+		c.metrics.resyncsWithDrift.WithLabelValues(evType).Inc()
+		for operation, count := range counts {
+			c.metrics.resyncDriftedValues.WithLabelValues(evType, operation).Add(float64(count))
+		}
+	}
+}
+
+// eventType returns name of the event type, used as a metric label instead
+// of the event name, which may not be constant for a given type.
+func eventType(event api.Event) string {
+	evType := reflect.TypeOf(event)
+	if evType.Kind() == reflect.Ptr {
+		evType = evType.Elem()
+	}
+	return evType.Name()
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/logging"
+
+	"github.com/americanbinary/vpp/mock/servicelabel"
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+// testStats registers collectors into a private prometheus registry.
+type testStats struct {
+	registry *prometheus.Registry
+}
+
+func (s *testStats) RegisterGaugeFunc(name string, help string, valueFunc func() float64) {}
+
+func (s *testStats) RegisterCollector(collector prometheus.Collector) error {
+	return s.registry.Register(collector)
+}
+
+func newMetricsController(slowHandlerThreshold uint32) (*Controller, *testStats) {
+	stats := &testStats{registry: prometheus.NewRegistry()}
+	serviceLabel := servicelabel.NewMockServiceLabel()
+	serviceLabel.SetAgentLabel("node1")
+	c := &Controller{
+		Deps: Deps{
+			PluginDeps: infra.PluginDeps{
+				Log: logging.ForPlugin("controller"),
+			},
+			ServiceLabel: serviceLabel,
+			Stats:        stats,
+		},
+		config: &Config{SlowHandlerThreshold: slowHandlerThreshold},
+	}
+	c.registerEventMetrics()
+	return c, stats
+}
+
+// histogramSamples returns the number of observations of the histogram with the given labels.
+func histogramSamples(stats *testStats, name string, labels map[string]string) uint64 {
+	families, err := stats.registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			metricLabels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				metricLabels[label.GetName()] = label.GetValue()
+			}
+			for label, value := range labels {
+				if metricLabels[label] != value {
+					continue metrics
+				}
+			}
+			return metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestEventType(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(eventType(api.NewDBResync())).To(Equal("DBResync"))
+	Expect(eventType(&api.KubeStateChange{})).To(Equal("KubeStateChange"))
+	Expect(eventType(&api.HealingResync{})).To(Equal("HealingResync"))
+}
+
+func TestSlowHandlerThreshold(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, _ := newMetricsController(0)
+	Expect(c.slowHandlerThreshold()).To(BeZero())
+	c, _ = newMetricsController(250)
+	Expect(c.slowHandlerThreshold()).To(Equal(250 * time.Millisecond))
+}
+
+func TestRecordDurations(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, stats := newMetricsController(100)
+	Expect(c.metrics).ToNot(BeNil())
+
+	event := &api.KubeStateChange{Resource: "pod", Key: "pod1"}
+	evRecord := &EventRecord{SeqNum: 1, Name: event.GetName(), Description: event.String()}
+	handlerRec := &EventHandlingRecord{Handler: "ipnet"}
+
+	// above the threshold (only logged)
+	c.recordHandlerDuration(evRecord, event, handlerRec, updateOperation, 200*time.Millisecond)
+	Expect(handlerRec.Duration).To(Equal(200 * time.Millisecond))
+	c.recordHandlerDuration(evRecord, event, handlerRec, updateOperation, time.Millisecond)
+	Expect(handlerRec.Duration).To(Equal(time.Millisecond))
+
+	Expect(histogramSamples(stats, handlerDurationMetric, map[string]string{
+		nodeLabel:      "node1",
+		handlerLabel:   "ipnet",
+		eventLabel:     "KubeStateChange",
+		operationLabel: updateOperation,
+	})).To(BeEquivalentTo(2))
+	Expect(histogramSamples(stats, handlerDurationMetric, map[string]string{
+		operationLabel: revertOperation,
+	})).To(BeZero())
+
+	c.recordTxnCommitDuration(evRecord, event, 5*time.Millisecond)
+	Expect(evRecord.TxnDuration).To(Equal(5 * time.Millisecond))
+	Expect(histogramSamples(stats, txnCommitDurationMetric, map[string]string{
+		eventLabel: "KubeStateChange",
+	})).To(BeEquivalentTo(1))
+}
+
+func TestRecordResyncDiff(t *testing.T) {
+	RegisterTestingT(t)
+
+	c, _ := newMetricsController(0)
+	event := &api.HealingResync{Type: api.Periodic}
+
+	// in-sync resync is not counted
+	c.recordResyncDiff(&EventRecord{SeqNum: 1, ResyncDiff: &ResyncDiff{}}, event)
+	Expect(testutil.ToFloat64(c.metrics.resyncsWithDrift.WithLabelValues("HealingResync"))).To(BeZero())
+
+	drift := &ResyncDiff{Changes: []*ResyncChange{
+		{Key: "a", Operation: resyncAdded},
+		{Key: "b", Operation: resyncRemoved},
+		{Key: "c", Operation: resyncRemoved},
+	}}
+	c.recordResyncDiff(&EventRecord{SeqNum: 2, ResyncDiff: drift}, event)
+	c.recordResyncDiff(&EventRecord{SeqNum: 3, ResyncDiff: drift}, event)
+	Expect(testutil.ToFloat64(c.metrics.resyncsWithDrift.WithLabelValues("HealingResync"))).To(BeEquivalentTo(2))
+	Expect(testutil.ToFloat64(c.metrics.resyncDriftedValues.WithLabelValues("HealingResync", resyncAdded))).
+		To(BeEquivalentTo(2))
+	Expect(testutil.ToFloat64(c.metrics.resyncDriftedValues.WithLabelValues("HealingResync", resyncRemoved))).
+		To(BeEquivalentTo(4))
+}
+
+func TestRecordWithoutMetrics(t *testing.T) {
+	RegisterTestingT(t)
+
+	// metrics are not exported without the stats collector
+	c := &Controller{
+		Deps: Deps{
+			PluginDeps: infra.PluginDeps{
+				Log: logging.ForPlugin("controller"),
+			},
+		},
+		config: &Config{},
+	}
+	c.registerEventMetrics()
+	Expect(c.metrics).To(BeNil())
+
+	handlerRec := &EventHandlingRecord{Handler: "ipnet"}
+	c.recordHandlerDuration(&EventRecord{}, api.NewDBResync(), handlerRec, resyncOperation, time.Second)
+	Expect(handlerRec.Duration).To(Equal(time.Second))
+}
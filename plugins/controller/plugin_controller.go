@@ -34,12 +34,17 @@ import (
 	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
 
 	"github.com/americanbinary/vpp/plugins/controller/api"
+	"github.com/americanbinary/vpp/plugins/statscollector"
 )
 
 const (
-	// how many events can be buffered at most
+	// how many events can be buffered at most (for each priority class)
 	eventQueueSize = 1000
 
+	// how long an event may wait in the queue before it is processed regardless
+	// of its priority (prevents starvation of the lower-priority events)
+	eventQueueAgingBound = 10 * time.Second
+
 	// how often the event history gets trimmed to remove records too old to keep
 	eventHistoryTrimmingPeriod = 1 * time.Minute
 
@@ -84,6 +89,9 @@ const (
 	// (with the exception of permanently recorded init period)
 	defaultEventHistoryAgeLimit = 24 * 60 // in minutes
 
+	// by default, the persisted event history is trimmed once it reaches 64MB
+	defaultEventHistorySinkMaxSize = 64 // in MB
+
 	// by default, events from the first hour of runtime are permanently recorded
 	// in memory
 	defaultPermanentlyRecordedInitPeriod = 60 // in minutes
@@ -156,11 +164,12 @@ type Controller struct {
 	kubeStateData  api.KubeStateData
 	externalConfig map[string]api.KeyValuePairs // ext. source label -> config snapshot
 	internalConfig api.KeyValuePairs
+	internalOwners map[string]string // key -> handler which requested the value
 
 	evLoopGID            string // ID of the go routine running the event loop
 	revEventHandlers     []api.EventHandler
 	delayedEvents        []*QueuedEvent // events delayed until after the first resync
-	eventQueue           chan *QueuedEvent
+	eventQueue           *eventQueue
 	followUpEventQueue   chan *QueuedEvent // events sent from within the event loop
 	startupResyncCheck   chan struct{}
 	eventHistoryTrimming chan struct{}
@@ -172,13 +181,17 @@ type Controller struct {
 
 	historyLock  sync.Mutex
 	eventHistory []*EventRecord
+	historySink  EventHistorySink // nil if the history is not persisted
 	startTime    time.Time
 
+	metrics *eventMetrics // nil if metrics are not exported
+
 	wg     sync.WaitGroup
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	txn *kvSchedulerTxn // transaction associated to the event currently being processed
+	txn    *kvSchedulerTxn // transaction associated to the event currently being processed
+	dryRun *dryRunEvent    // dry-run currently being processed (nil otherwise)
 }
 
 // Deps lists dependencies of the Controller.
@@ -196,6 +209,9 @@ type Deps struct {
 	RemoteDB keyval.KvProtoPlugin
 
 	ExtSources []ExternalConfigSource
+
+	// Stats (optional) is used to export metrics of the event queue.
+	Stats statscollector.API
 }
 
 // Config holds the Controller configuration.
@@ -223,8 +239,16 @@ type Config struct {
 	EventHistoryAgeLimit          uint32 `json:"eventHistoryAgeLimit"`
 	PermanentlyRecordedInitPeriod uint32 `json:"permanentlyRecordedInitPeriod"`
 
+	// persisted event history ("file", "bolt" or empty to disable)
+	EventHistorySink        string `json:"eventHistorySink"`
+	EventHistorySinkPath    string `json:"eventHistorySinkPath"`
+	EventHistorySinkMaxSize uint32 `json:"eventHistorySinkMaxSize"` // in MB
+
 	// verification mode
 	EnableVerification bool `json:"enableVerification"`
+
+	// handler latency (warning is logged if exceeded, zero to disable)
+	SlowHandlerThreshold uint32 `json:"slowHandlerThreshold"` // in milliseconds
 }
 
 // EventRecord is a record of a processed event, added into the history of events,
@@ -239,17 +263,21 @@ type EventRecord struct {
 	Description     string
 	Method          api.EventMethodType
 	Handlers        []*EventHandlingRecord
-	TxnError        error
+	TxnError        error         `json:"-"` // persisted as TxnErrorStr
+	TxnErrorStr     string        // string representation of the transaction error (if any)
+	TxnDuration     time.Duration // time it took KVScheduler to commit the transaction
 	Txn             *scheduler.RecordedTxn
+	ResyncDiff      *ResyncDiff // changes made by a healing or verification resync (nil for other events)
 }
 
 // EventHandlingRecord is a record of an event being handled by a given handler.
 type EventHandlingRecord struct {
 	Handler  string
 	Revert   bool
-	Change   string // change description for update events
-	Error    error  // nil if none
-	ErrorStr string // string representation of the error (if any)
+	Change   string        // change description for update events
+	Error    error         `json:"-"` // nil if none, persisted as ErrorStr
+	ErrorStr string        // string representation of the error (if any)
+	Duration time.Duration // time spent in Resync/Update/Revert
 }
 
 // QueuedEvent wraps event for the event queue.
@@ -257,6 +285,8 @@ type QueuedEvent struct {
 	event           api.Event
 	isFollowUp      bool
 	followUpToEvent uint64 // event sequence number
+	priority        api.EventPriority
+	enqueuedAt      time.Time
 }
 
 // ExternalConfigSource defines API that a source of external configuration
@@ -286,11 +316,12 @@ func (c *Controller) Init() error {
 	// initialize attributes
 	c.startTime = time.Now()
 	c.ctx, c.cancel = context.WithCancel(context.Background())
-	c.eventQueue = make(chan *QueuedEvent, eventQueueSize)
+	c.eventQueue = newEventQueue(eventQueueSize, eventQueueAgingBound)
 	c.followUpEventQueue = make(chan *QueuedEvent, eventQueueSize)
 	c.startupResyncCheck = make(chan struct{}, 1)
 	c.eventHistoryTrimming = make(chan struct{}, 1)
 	c.internalConfig = make(api.KeyValuePairs)
+	c.internalOwners = make(map[string]string)
 	c.externalConfig = make(map[string]api.KeyValuePairs)
 	for i := len(c.EventHandlers) - 1; i >= 0; i-- {
 		c.revEventHandlers = append(c.revEventHandlers, c.EventHandlers[i])
@@ -311,6 +342,7 @@ func (c *Controller) Init() error {
 		RecordEventHistory:            defaultRecordEventHistory,
 		EventHistoryAgeLimit:          defaultEventHistoryAgeLimit,
 		PermanentlyRecordedInitPeriod: defaultPermanentlyRecordedInitPeriod,
+		EventHistorySinkMaxSize:       defaultEventHistorySinkMaxSize,
 		EnableVerification:            defaultEnableVerification,
 	}
 
@@ -322,6 +354,24 @@ func (c *Controller) Init() error {
 	}
 	c.Log.Infof("Controller configuration: %+v", *c.config)
 
+	// open sink for the persisted event history
+	if c.config.RecordEventHistory {
+		sink, err := newEventHistorySink(c.config, c.startTime)
+		if err != nil {
+			err = fmt.Errorf("failed to open event history sink: %v", err)
+			c.Log.Error(err)
+			return err
+		}
+		if sink != nil {
+			// persist records outside of the event loop
+			c.historySink = newBufferedHistorySink(sink, eventHistorySinkQueueSize,
+				func(record *EventRecord, err error) {
+					c.Log.Warnf("Failed to persist record of the event %s: %v",
+						eventSeqNumToStr(record.SeqNum), err)
+				})
+		}
+	}
+
 	// register controller with status check
 	if c.StatusCheck != nil {
 		c.StatusCheck.Register(c.PluginName, nil)
@@ -345,6 +395,10 @@ func (c *Controller) Init() error {
 
 	// register REST API handlers
 	c.registerHandlers()
+
+	// export metrics of the event queue and of the event processing
+	c.registerQueueGauges()
+	c.registerEventMetrics()
 	return nil
 }
 
@@ -364,6 +418,9 @@ func (c *Controller) AfterInit() error {
 }
 
 // PushEvent adds the given event into the queue for processing.
+// Events are queued by their priority classes (see api.PrioritizedEvent).
+// Non-blocking event which is a duplicate of an already queued event is dropped
+// (see api.CoalescableEvent).
 func (c *Controller) PushEvent(event api.Event) error {
 	callerGID := getGID()
 	if callerGID == c.evLoopGID {
@@ -372,6 +429,11 @@ func (c *Controller) PushEvent(event api.Event) error {
 		if event.IsBlocking() {
 			panic("deadlock detected - blocking event sent from within the event loop")
 		}
+		if c.dryRun != nil {
+			// follow-up events are not processed for dry-run
+			c.dryRun.result.FollowUpEvents = append(c.dryRun.result.FollowUpEvents, event.String())
+			return nil
+		}
 		select {
 		case <-c.ctx.Done():
 			return ErrClosedController
@@ -388,11 +450,14 @@ func (c *Controller) PushEvent(event api.Event) error {
 	select {
 	case <-c.ctx.Done():
 		return ErrClosedController
-	case c.eventQueue <- &QueuedEvent{event: event}:
-		return nil
 	default:
-		return ErrEventQueueFull
 	}
+	coalesced, err := c.eventQueue.push(&QueuedEvent{event: event})
+	if coalesced {
+		c.Log.Debugf("Event %s was coalesced with an already queued duplicate",
+			event.GetName())
+	}
+	return err
 }
 
 // GetConfig returns value for the given key in the controller's transaction. If data for
@@ -475,7 +540,11 @@ func (c *Controller) eventLoop() {
 				return
 			}
 
-		case event := <-c.eventQueue:
+		case <-c.eventQueue.signal:
+			event := c.eventQueue.pop()
+			if event == nil {
+				continue
+			}
 			exit := c.receiveEvent(event)
 			if exit {
 				return
@@ -597,6 +666,18 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 	)
 	event := qe.event
 
+	// 0. dry-run of an update event is processed separately (nothing gets applied)
+	if dryRun, isDryRun := event.(*dryRunEvent); isDryRun {
+		return c.processDryRun(dryRun)
+	}
+
+	// 0. event which has become obsolete while waiting in the queue is skipped
+	if obsoletable, isObsoletable := event.(api.ObsoletableEvent); isObsoletable && obsoletable.IsObsolete() {
+		c.Log.Infof("Skipping obsolete event: %s", event.String())
+		event.Done(nil)
+		return nil
+	}
+
 	// 1. prepare for resync
 	if event.Method() != api.Update {
 		c.resyncCount++ // first resync has resyncCount == 1
@@ -690,6 +771,14 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		Method:          event.Method(),
 	}
 	c.evSeqNum++
+	var evPayload *api.EventPayload
+	if serializable, isSerializable := event.(api.SerializableEvent); isSerializable && c.historySink != nil {
+		var err error
+		evPayload, err = serializable.Payload()
+		if err != nil {
+			c.Log.Warnf("Failed to serialize event %s: %v", eventSeqNumToStr(evRecord.SeqNum), err)
+		}
+	}
 
 	// 6. print information about the new event
 	c.printNewEvent(evRecord, eventHandlers)
@@ -708,15 +797,20 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 
 		// execute Update/Resync
 		var (
-			change string
-			errStr string
+			change    string
+			errStr    string
+			operation string
 		)
+		c.txn.owner = handler.String()
+		handlingStart := time.Now()
 		if isUpdate {
+			operation = updateOperation
 			change, err = handler.Update(event, c.txn)
 			if change != "" {
 				changes[handler.String()] = change
 			}
 		} else {
+			operation = resyncOperation
 			var beforeDataDesc, afterDataDesc string
 			handlerData, withInternalData := handler.(WithInternalData)
 			if isVerification && withInternalData {
@@ -731,6 +825,7 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 				}
 			}
 		}
+		handlingDuration := time.Since(handlingStart)
 		if err != nil {
 			errStr = err.Error()
 			wasErr = err
@@ -740,13 +835,15 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		}
 
 		// record operation
-		evRecord.Handlers = append(evRecord.Handlers, &EventHandlingRecord{
+		handlerRec := &EventHandlingRecord{
 			Handler:  handler.String(),
 			Revert:   false,
 			Change:   change,
 			Error:    err,
 			ErrorStr: errStr,
-		})
+		}
+		evRecord.Handlers = append(evRecord.Handlers, handlerRec)
+		c.recordHandlerDuration(evRecord, event, handlerRec, operation, handlingDuration)
 
 		// check if error allows to continue
 		if err != nil {
@@ -758,6 +855,8 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		}
 	}
 
+	c.txn.owner = ""
+
 	// 8. merge internal (Contiv-generated) values with external configuration
 	if !fatalErr && !abortErr {
 		if isUpdate {
@@ -839,12 +938,15 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		}
 
 		// commit transaction to vpp-agent
+		commitStart := time.Now()
 		txnSeqNum, err := c.txn.Commit(ctx)
+		c.recordTxnCommitDuration(evRecord, event, time.Since(commitStart))
 		c.Log.Debugf("Transaction commit result: err=%v", err)
 
 		// handle transaction error
 		evRecord.TxnError = err
 		if err != nil {
+			evRecord.TxnErrorStr = err.Error()
 			wasErr = err
 			if !withRevert && withHealing {
 				if c.onlyExtConfigFailed(err.(*scheduler.TransactionError), c.txn.values) {
@@ -862,6 +964,10 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 			if isVerification && len(evRecord.Txn.Executed) > 0 {
 				c.Log.Error("The dataplane configuration was not in-sync (see the resync transaction above)")
 			}
+			if detectsDrift(event) {
+				evRecord.ResyncDiff = buildResyncDiff(evRecord.Txn, c.resyncValueOwners())
+				c.recordResyncDiff(evRecord, event)
+			}
 		}
 
 		// update Controller's view of internal configuration
@@ -870,13 +976,16 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 				for key, value := range c.txn.values {
 					if value == nil {
 						delete(c.internalConfig, key)
+						delete(c.internalOwners, key)
 					} else {
 						c.internalConfig[key] = value
+						c.internalOwners[key] = c.txn.owners[key]
 					}
 				}
 			}
 		} else if event.Method() != api.DownstreamResync {
 			c.internalConfig = c.txn.values
+			c.internalOwners = c.txn.owners
 		}
 	}
 
@@ -886,7 +995,9 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		for idx = idx - 1; idx >= 0; idx-- {
 			var errStr string
 			handler := eventHandlers[idx]
+			revertStart := time.Now()
 			err := handler.Revert(event)
+			revertDuration := time.Since(revertStart)
 			if err != nil {
 				errStr = err.Error()
 				wasErr = err
@@ -894,12 +1005,14 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 			}
 
 			// record Revert operation
-			evRecord.Handlers = append(evRecord.Handlers, &EventHandlingRecord{
+			handlerRec := &EventHandlingRecord{
 				Handler:  handler.String(),
 				Revert:   true,
 				Error:    err,
 				ErrorStr: errStr,
-			})
+			}
+			evRecord.Handlers = append(evRecord.Handlers, handlerRec)
+			c.recordHandlerDuration(evRecord, event, handlerRec, revertOperation, revertDuration)
 
 			// check if error allows to continue
 			if err != nil {
@@ -917,6 +1030,12 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		c.historyLock.Lock()
 		c.eventHistory = append(c.eventHistory, evRecord)
 		c.historyLock.Unlock()
+		if c.historySink != nil {
+			if err := c.historySink.Append(evRecord, evPayload); err != nil {
+				c.Log.Warnf("Failed to persist record of the event %s: %v",
+					eventSeqNumToStr(evRecord.SeqNum), err)
+			}
+		}
 	}
 	event.Done(wasErr)
 	c.txn = nil
@@ -961,21 +1080,21 @@ func (c *Controller) onlyExtConfigFailed(txnErr *scheduler.TransactionError, txn
 	return true
 }
 
-// getEventHistory returns history of events run within the specified
-// time window, or the full recorded history if the timestamps are zero values.
-// The method assumes that historyLock is being held.
-func (c *Controller) getEventHistory(since, until time.Time) (history []*EventRecord) {
+// getEventHistory returns those records from the given history which were run
+// within the specified time window, or the full history if the timestamps
+// are zero values.
+func getEventHistory(history []*EventRecord, since, until time.Time) []*EventRecord {
 	if !since.IsZero() && !until.IsZero() && until.Before(since) {
 		// invalid time window
-		return
+		return nil
 	}
 
 	lastBefore := -1
-	firstAfter := len(c.eventHistory)
+	firstAfter := len(history)
 
 	if !since.IsZero() {
-		for ; lastBefore+1 < len(c.eventHistory); lastBefore++ {
-			if !c.eventHistory[lastBefore+1].ProcessingEnd.Before(since) {
+		for ; lastBefore+1 < len(history); lastBefore++ {
+			if !history[lastBefore+1].ProcessingEnd.Before(since) {
 				break
 			}
 		}
@@ -983,13 +1102,13 @@ func (c *Controller) getEventHistory(since, until time.Time) (history []*EventRe
 
 	if !until.IsZero() {
 		for ; firstAfter > 0; firstAfter-- {
-			if !c.eventHistory[firstAfter-1].ProcessingStart.After(until) {
+			if !history[firstAfter-1].ProcessingStart.After(until) {
 				break
 			}
 		}
 	}
 
-	return c.eventHistory[lastBefore+1 : firstAfter]
+	return history[lastBefore+1 : firstAfter]
 }
 
 // Close stops event loop and database watching.
@@ -1003,6 +1122,13 @@ func (c *Controller) Close() error {
 	c.dbWatcher.close()
 	c.cancel()
 	c.wg.Wait()
+
+	// close the persisted event history
+	if c.historySink != nil {
+		if sinkErr := c.historySink.Close(); sinkErr != nil {
+			c.Log.Warnf("Failed to close event history sink: %v", sinkErr)
+		}
+	}
 	return err
 }
 
@@ -0,0 +1,192 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+// eventQueue buffers events waiting for processing, with a separate lane (FIFO)
+// for every event priority class.
+type eventQueue struct {
+	sync.Mutex
+
+	lanes      map[api.EventPriority][]*QueuedEvent
+	capacity   int           // max. number of events waiting in a single lane
+	agingBound time.Duration // max. wait time before an event is served regardless of its priority
+	coalesced  uint64        // number of dropped duplicate events
+	signal     chan struct{} // signals that the queue is not empty
+}
+
+// newEventQueue is a constructor for eventQueue.
+func newEventQueue(capacity int, agingBound time.Duration) *eventQueue {
+	return &eventQueue{
+		lanes:      make(map[api.EventPriority][]*QueuedEvent),
+		capacity:   capacity,
+		agingBound: agingBound,
+		signal:     make(chan struct{}, 1),
+	}
+}
+
+// push adds the event at the back of the lane for the event priority class.
+// Non-blocking event which is a duplicate of the event at the back of the lane
+// is dropped. Events queued further ahead are not considered, otherwise
+// the dropped event would be effectively moved in front of the events queued
+// in between.
+func (q *eventQueue) push(qe *QueuedEvent) (coalesced bool, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	qe.priority = api.GetEventPriority(qe.event)
+	lane := q.lanes[qe.priority]
+	if coalescable, isCoalescable := qe.event.(api.CoalescableEvent); isCoalescable &&
+		!qe.event.IsBlocking() && len(lane) > 0 {
+		if coalescable.IsDuplicateOf(lane[len(lane)-1].event) {
+			q.coalesced++
+			return true, nil
+		}
+	}
+	if len(lane) >= q.capacity {
+		return false, ErrEventQueueFull
+	}
+	qe.enqueuedAt = time.Now()
+	q.lanes[qe.priority] = append(lane, qe)
+	q.notify()
+	return false, nil
+}
+
+// pop removes and returns the oldest event from the lane with the highest
+// priority. To prevent starvation of the lower-priority lanes, an event waiting
+// for longer than the aging bound is returned first, regardless of its priority
+// (the one waiting the longest if there are multiple).
+// Returns nil if the queue is empty.
+func (q *eventQueue) pop() *QueuedEvent {
+	q.Lock()
+	defer q.Unlock()
+
+	priority, found := q.agedLane()
+	if !found {
+		for _, lanePriority := range api.EventPriorities {
+			if len(q.lanes[lanePriority]) > 0 {
+				priority, found = lanePriority, true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	lane := q.lanes[priority]
+	qe := lane[0]
+	lane[0] = nil
+	q.lanes[priority] = lane[1:]
+	if q.len() > 0 {
+		// more events are waiting
+		q.notify()
+	}
+	return qe
+}
+
+// agedLane returns the lane with the event that has been waiting for longer
+// than the aging bound the longest (call with the lock acquired).
+func (q *eventQueue) agedLane() (priority api.EventPriority, found bool) {
+	if q.agingBound <= 0 {
+		return priority, false
+	}
+	var oldest time.Time
+	for _, lanePriority := range api.EventPriorities {
+		lane := q.lanes[lanePriority]
+		if len(lane) == 0 || time.Since(lane[0].enqueuedAt) < q.agingBound {
+			continue
+		}
+		if !found || lane[0].enqueuedAt.Before(oldest) {
+			priority, oldest, found = lanePriority, lane[0].enqueuedAt, true
+		}
+	}
+	return priority, found
+}
+
+// depth returns the number of events waiting in the lane of the given priority.
+func (q *eventQueue) depth(priority api.EventPriority) int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.lanes[priority])
+}
+
+// waitTime returns for how long the oldest event of the given priority has been
+// waiting for processing (zero if the lane is empty).
+func (q *eventQueue) waitTime(priority api.EventPriority) time.Duration {
+	q.Lock()
+	defer q.Unlock()
+	lane := q.lanes[priority]
+	if len(lane) == 0 {
+		return 0
+	}
+	return time.Since(lane[0].enqueuedAt)
+}
+
+// coalescedCount returns the number of duplicate events dropped so far.
+func (q *eventQueue) coalescedCount() uint64 {
+	q.Lock()
+	defer q.Unlock()
+	return q.coalesced
+}
+
+// len returns the total number of queued events (call with the lock acquired).
+func (q *eventQueue) len() (count int) {
+	for _, lane := range q.lanes {
+		count += len(lane)
+	}
+	return count
+}
+
+// notify signals that the queue is not empty (call with the lock acquired).
+func (q *eventQueue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+		// already signaled
+	}
+}
+
+// registerQueueGauges exports depth and wait time of the event queue lanes
+// through the stats collector.
+func (c *Controller) registerQueueGauges() {
+	if c.Stats == nil {
+		return
+	}
+	for _, priority := range api.EventPriorities {
+		priority := priority
+		c.Stats.RegisterGaugeFunc(fmt.Sprintf("%sEventQueueDepth", priority),
+			fmt.Sprintf("Number of %s events waiting for processing", priority),
+			func() float64 {
+				return float64(c.eventQueue.depth(priority))
+			})
+		c.Stats.RegisterGaugeFunc(fmt.Sprintf("%sEventQueueWaitTime", priority),
+			fmt.Sprintf("How long (in seconds) the oldest queued %s event has been waiting for processing", priority),
+			func() float64 {
+				return c.eventQueue.waitTime(priority).Seconds()
+			})
+	}
+	c.Stats.RegisterGaugeFunc("coalescedEvents",
+		"Total count of duplicate events dropped from the event queue",
+		func() float64 {
+			return float64(c.eventQueue.coalescedCount())
+		})
+}
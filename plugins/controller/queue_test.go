@@ -0,0 +1,113 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+// testQueueEvent is a coalescable event with a selectable priority.
+type testQueueEvent struct {
+	key      string
+	priority api.EventPriority
+}
+
+func (ev *testQueueEvent) GetName() string             { return "Test Event" }
+func (ev *testQueueEvent) String() string              { return "Test Event " + ev.key }
+func (ev *testQueueEvent) Method() api.EventMethodType { return api.Update }
+func (ev *testQueueEvent) IsBlocking() bool            { return false }
+func (ev *testQueueEvent) Done(error)                  {}
+func (ev *testQueueEvent) Priority() api.EventPriority { return ev.priority }
+func (ev *testQueueEvent) IsDuplicateOf(event api.Event) bool {
+	other, isTestEvent := event.(*testQueueEvent)
+	return isTestEvent && other.key == ev.key
+}
+
+func pushTestEvent(queue *eventQueue, key string, priority api.EventPriority) (coalesced bool) {
+	coalesced, err := queue.push(&QueuedEvent{event: &testQueueEvent{key: key, priority: priority}})
+	Expect(err).ToNot(HaveOccurred())
+	return coalesced
+}
+
+func popTestEvent(queue *eventQueue) string {
+	qe := queue.pop()
+	Expect(qe).ToNot(BeNil())
+	return qe.event.(*testQueueEvent).key
+}
+
+func TestEventQueueCoalescing(t *testing.T) {
+	RegisterTestingT(t)
+	queue := newEventQueue(10, 0)
+
+	// duplicate at the back of the lane is dropped
+	Expect(pushTestEvent(queue, "a", api.TopologyPriority)).To(BeFalse())
+	Expect(pushTestEvent(queue, "a", api.TopologyPriority)).To(BeTrue())
+
+	// duplicate queued further ahead is kept to preserve the ordering
+	Expect(pushTestEvent(queue, "b", api.TopologyPriority)).To(BeFalse())
+	Expect(pushTestEvent(queue, "a", api.TopologyPriority)).To(BeFalse())
+
+	// duplicate in another lane is kept
+	Expect(pushTestEvent(queue, "a", api.HealingPriority)).To(BeFalse())
+
+	Expect(queue.coalescedCount()).To(BeEquivalentTo(1))
+	Expect(queue.depth(api.TopologyPriority)).To(Equal(3))
+	Expect(popTestEvent(queue)).To(Equal("a"))
+	Expect(popTestEvent(queue)).To(Equal("b"))
+	Expect(popTestEvent(queue)).To(Equal("a"))
+}
+
+func TestEventQueuePriorities(t *testing.T) {
+	RegisterTestingT(t)
+	queue := newEventQueue(10, time.Minute)
+
+	pushTestEvent(queue, "healing", api.HealingPriority)
+	pushTestEvent(queue, "topology", api.TopologyPriority)
+	pushTestEvent(queue, "cni", api.CNIPriority)
+
+	Expect(popTestEvent(queue)).To(Equal("cni"))
+	Expect(popTestEvent(queue)).To(Equal("topology"))
+	Expect(popTestEvent(queue)).To(Equal("healing"))
+	Expect(queue.pop()).To(BeNil())
+}
+
+func TestEventQueueAging(t *testing.T) {
+	RegisterTestingT(t)
+	queue := newEventQueue(10, time.Minute)
+
+	pushTestEvent(queue, "healing1", api.HealingPriority)
+	pushTestEvent(queue, "healing2", api.HealingPriority)
+	pushTestEvent(queue, "topology1", api.TopologyPriority)
+	pushTestEvent(queue, "topology2", api.TopologyPriority)
+	pushTestEvent(queue, "cni", api.CNIPriority)
+
+	// let the first healing and topology events exceed the aging bound
+	queue.lanes[api.HealingPriority][0].enqueuedAt = time.Now().Add(-3 * time.Minute)
+	queue.lanes[api.TopologyPriority][0].enqueuedAt = time.Now().Add(-2 * time.Minute)
+
+	// aged events go first, the oldest one first
+	Expect(popTestEvent(queue)).To(Equal("healing1"))
+	Expect(popTestEvent(queue)).To(Equal("topology1"))
+
+	// then back to the ordering by priority
+	Expect(popTestEvent(queue)).To(Equal("cni"))
+	Expect(popTestEvent(queue)).To(Equal("topology2"))
+	Expect(popTestEvent(queue)).To(Equal("healing2"))
+}
@@ -0,0 +1,516 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay re-executes a recorded sequence of events against a chain of
+// event handlers offline, i.e. without VPP and without the KVScheduler.
+//
+// The input is the event history persisted by the Controller (see
+// controller.EventHistorySink). Events are dispatched to the handlers the same way
+// as by the Controller (handler ordering, Update/Resync/Revert stages), but
+// transactions are committed into a mock transaction tracker. The resulting
+// per-event transactions can be then compared with the transactions recorded
+// by the live agent.
+//
+// Replayer implements EventLoop and ConfigRetriever and it is injected into
+// the handlers in place of the Controller. Follow-up events pushed by the handlers
+// are therefore re-generated during the replay, rather than decoded from the history.
+//
+// Only events that implement api.SerializableEvent are recorded with a payload
+// and can be replayed. Decoders for events defined by the Controller and PodManager
+// are registered by default, others can be added with Replayer.RegisterDecoder.
+//
+// The contiv-replay command (cmd/contiv-replay) replays the history against the chain
+// of Contiv plugins with mocked dependencies.
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"go.ligato.io/cn-infra/v2/logging"
+	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
+
+	"github.com/americanbinary/vpp/mock/localclient"
+	mockcontroller "github.com/americanbinary/vpp/mock/localclient/controller"
+	"github.com/americanbinary/vpp/plugins/controller"
+	"github.com/americanbinary/vpp/plugins/controller/api"
+	"github.com/americanbinary/vpp/plugins/podmanager"
+)
+
+// EventDecoder re-creates event from its payload.
+type EventDecoder func(payload *api.EventPayload) (api.Event, error)
+
+// Replayer re-executes recorded events against a chain of event handlers.
+type Replayer struct {
+	log              logging.Logger
+	eventHandlers    []api.EventHandler
+	revEventHandlers []api.EventHandler
+	decoders         map[string]EventDecoder // payload type -> decoder
+
+	txnTracker    *localclient.TxnTracker
+	txn           *mockcontroller.MockControllerTxn // transaction being prepared
+	config        api.KeyValuePairs                 // configuration committed so far
+	kubeStateData api.KubeStateData
+	resyncCount   int
+	followUps     []api.Event // events pushed by handlers
+}
+
+// ReplayedEvent is a record of a replayed event.
+// Index and SeqNum are not set for the initial resync against the given
+// state data and for follow-up events.
+type ReplayedEvent struct {
+	InitialResync bool   // true for the startup resync of the replay
+	FollowUp      bool   // true for events pushed by the handlers during the replay
+	Index         uint64 // index of the persisted record
+	SeqNum        uint64 // sequence number of the event in the original run
+	Name          string
+	Description   string
+	Method        api.EventMethodType
+	Skipped       bool   // true if the event could not be replayed
+	SkipReason    string `json:",omitempty"`
+	Handlers      []*controller.EventHandlingRecord
+	TxnError      string          `json:",omitempty"`
+	Txn           []*TxnOperation // sorted by keys
+}
+
+// TxnOperation is a single operation of a replayed transaction.
+type TxnOperation struct {
+	Key    string
+	Delete bool
+	Value  json.RawMessage `json:",omitempty"` // JSON-encoded proto message (nil for delete)
+}
+
+// NewReplayer creates a new instance of Replayer.
+// Replayer implements EventLoop and ConfigRetriever, it should be therefore
+// injected into the handlers in place of the Controller.
+// The chain of handlers is set with SetEventHandlers.
+func NewReplayer(log logging.Logger) *Replayer {
+	r := &Replayer{
+		log:           log,
+		decoders:      make(map[string]EventDecoder),
+		txnTracker:    localclient.NewTxnTracker(nil),
+		config:        make(api.KeyValuePairs),
+		kubeStateData: api.NewKubeStateData(),
+	}
+
+	// decoders for the events of the Controller and PodManager
+	r.RegisterDecoder(api.KubeStateChangePayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return api.KubeStateChangeFromPayload(payload)
+	})
+	r.RegisterDecoder(api.ExternalConfigChangePayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return api.ExternalConfigChangeFromPayload(payload)
+	})
+	r.RegisterDecoder(api.ExternalConfigResyncPayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return api.ExternalConfigResyncFromPayload(payload)
+	})
+	r.RegisterDecoder(api.HealingResyncPayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return api.HealingResyncFromPayload(payload)
+	})
+	r.RegisterDecoder(api.VerificationResyncPayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return &api.VerificationResync{}, nil
+	})
+	r.RegisterDecoder(podmanager.AddPodPayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return podmanager.AddPodFromPayload(payload)
+	})
+	r.RegisterDecoder(podmanager.DeletePodPayloadType, func(payload *api.EventPayload) (api.Event, error) {
+		return podmanager.DeletePodFromPayload(payload)
+	})
+	return r
+}
+
+// SetEventHandlers sets the chain of event handlers to replay events against
+// (in the same order as passed to the Controller).
+func (r *Replayer) SetEventHandlers(eventHandlers []api.EventHandler) {
+	r.eventHandlers = eventHandlers
+	r.revEventHandlers = nil
+	for i := len(eventHandlers) - 1; i >= 0; i-- {
+		r.revEventHandlers = append(r.revEventHandlers, eventHandlers[i])
+	}
+}
+
+// RegisterDecoder registers decoder for event payloads of the given type.
+func (r *Replayer) RegisterDecoder(payloadType string, decoder EventDecoder) {
+	r.decoders[payloadType] = decoder
+}
+
+// Replay executes the recorded events in the order of their indexes.
+//
+// Records may come from multiple runs of the agent (distinguished by AgentStart).
+// The first DBResync recorded in a run is replayed as the startup resync
+// of the run (against the recorded state data). The startup resync of the first
+// run is replaced by the resync against <kubeState>, unless <kubeState> is nil.
+// If the replayed history does not start with a startup resync and <kubeState>
+// is nil, the initial startup resync is executed against empty state data.
+// Note that the handlers are not re-created between the agent runs, i.e. the replay
+// relies on the startup resync to rebuild the internal state of the handlers.
+//
+// Events pushed by the handlers into the event loop during the replay are
+// replayed as follow-up events, right after the event that generated them.
+func (r *Replayer) Replay(kubeState api.KubeStateData, records []*controller.PersistedEventRecord) []*ReplayedEvent {
+	var replayed []*ReplayedEvent
+
+	records = append([]*controller.PersistedEventRecord{}, records...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Index < records[j].Index
+	})
+	dbResyncName := api.NewDBResync().GetName()
+	withStartupResync := len(records) > 0 && records[0].Name == dbResyncName
+
+	// initial startup resync
+	r.resyncCount = 0
+	if kubeState != nil || !withStartupResync {
+		startupResync := api.NewDBResync()
+		if kubeState != nil {
+			startupResync.KubeState = kubeState
+		}
+		replayed = r.replay(startupResync, &ReplayedEvent{InitialResync: true}, replayed)
+	}
+
+	for i, record := range records {
+		replayedEv := &ReplayedEvent{
+			Index:  record.Index,
+			SeqNum: record.SeqNum,
+			Name:   record.Name,
+			Method: record.Method,
+		}
+		newRun := i == 0 || !record.AgentStart.Equal(records[i-1].AgentStart)
+
+		// re-create the event
+		var event api.Event
+		switch {
+		case isFollowUpRecord(record):
+			replayedEv.Skipped = true
+			replayedEv.SkipReason = "follow-up event is re-generated by the replayed handlers"
+		case record.Name == dbResyncName && newRun && i == 0 && kubeState != nil:
+			replayedEv.Skipped = true
+			replayedEv.SkipReason = "startup resync is replaced by the resync against the given state data"
+		case record.Name == dbResyncName:
+			if newRun {
+				// startup resync of a (re)started agent
+				r.resyncCount = 0
+				replayedEv.InitialResync = i == 0
+			}
+			dbResync := api.NewDBResync()
+			if record.Payload != nil {
+				var err error
+				dbResync, err = api.DBResyncFromPayload(record.Payload)
+				if err != nil {
+					replayedEv.Skipped = true
+					replayedEv.SkipReason = fmt.Sprintf("failed to decode event: %v", err)
+					break
+				}
+			} else {
+				// DB resync recorded without payload is replayed against
+				// the current view of the state data
+				dbResync.KubeState = r.kubeStateData
+			}
+			event = dbResync
+		case record.Payload == nil:
+			replayedEv.Skipped = true
+			replayedEv.SkipReason = "event was recorded without payload"
+		default:
+			decoder, hasDecoder := r.decoders[record.Payload.EventType]
+			if !hasDecoder {
+				replayedEv.Skipped = true
+				replayedEv.SkipReason = fmt.Sprintf("missing decoder for event payload type %s",
+					record.Payload.EventType)
+				break
+			}
+			var err error
+			event, err = decoder(record.Payload)
+			if err != nil {
+				replayedEv.Skipped = true
+				replayedEv.SkipReason = fmt.Sprintf("failed to decode event: %v", err)
+			}
+		}
+		if replayedEv.Skipped {
+			replayed = append(replayed, replayedEv)
+			continue
+		}
+		replayed = r.replay(event, replayedEv, replayed)
+	}
+	return replayed
+}
+
+// isFollowUpRecord returns true if the recorded event was generated by a handler
+// from within the event loop.
+func isFollowUpRecord(record *controller.PersistedEventRecord) bool {
+	var eventRecord struct {
+		IsFollowUp bool
+	}
+	if err := json.Unmarshal(record.Record, &eventRecord); err != nil {
+		return false
+	}
+	return eventRecord.IsFollowUp
+}
+
+// PushEvent is called by the event handlers to generate follow-up events.
+// The events are queued and replayed right after the event being processed.
+func (r *Replayer) PushEvent(event api.Event) error {
+	r.followUps = append(r.followUps, event)
+	return nil
+}
+
+// GetConfig returns value for the given key from the transaction being prepared
+// or from the configuration produced by the previously replayed events.
+func (r *Replayer) GetConfig(key string) proto.Message {
+	var value proto.Message
+	if r.txn != nil {
+		value = r.txn.Get(key)
+	}
+	if value == nil {
+		value = r.config[key]
+	}
+	if value == nil {
+		return nil
+	}
+	return proto.Clone(value)
+}
+
+// replay replays the event followed by the follow-up events pushed by the handlers.
+func (r *Replayer) replay(event api.Event, replayedEv *ReplayedEvent, replayed []*ReplayedEvent) []*ReplayedEvent {
+	replayed = append(replayed, r.replayEvent(event, replayedEv))
+	for len(r.followUps) > 0 {
+		followUp := r.followUps[0]
+		r.followUps = r.followUps[1:]
+		replayed = append(replayed, r.replayEvent(followUp, &ReplayedEvent{FollowUp: true}))
+	}
+	return replayed
+}
+
+// replayEvent dispatches the event to the event handlers and commits
+// the resulting transaction.
+func (r *Replayer) replayEvent(event api.Event, replayed *ReplayedEvent) *ReplayedEvent {
+	var (
+		wasErr        error
+		isUpdate      bool
+		withRevert    bool
+		updateEvent   api.UpdateEvent
+		eventHandlers []api.EventHandler
+	)
+	replayed.Name = event.GetName()
+	replayed.Description = event.String()
+	replayed.Method = event.Method()
+
+	// update the view of the Kubernetes state and select event handlers
+	if event.Method() == api.Update {
+		updateEvent, isUpdate = event.(api.UpdateEvent)
+		if !isUpdate {
+			replayed.Skipped = true
+			replayed.SkipReason = "invalid update event"
+			return replayed
+		}
+		withRevert = updateEvent.TransactionType() == api.RevertOnFailure
+		if ksChange, isKSChange := event.(*api.KubeStateChange); isKSChange {
+			if _, hasResource := r.kubeStateData[ksChange.Resource]; !hasResource {
+				r.kubeStateData[ksChange.Resource] = make(api.KeyValuePairs)
+			}
+			if ksChange.NewValue == nil {
+				delete(r.kubeStateData[ksChange.Resource], ksChange.Key)
+			} else {
+				r.kubeStateData[ksChange.Resource][ksChange.Key] = ksChange.NewValue
+			}
+		}
+		if updateEvent.Direction() == api.Forward {
+			eventHandlers = r.eventHandlers
+		} else {
+			eventHandlers = r.revEventHandlers
+		}
+	} else {
+		r.resyncCount++
+		if dbResync, isDBResync := event.(*api.DBResync); isDBResync {
+			r.kubeStateData = dbResync.KubeState
+		}
+		if event.Method() != api.DownstreamResync {
+			eventHandlers = r.eventHandlers
+		}
+	}
+	var filteredHandlers []api.EventHandler
+	for _, handler := range eventHandlers {
+		if handler.HandlesEvent(event) {
+			filteredHandlers = append(filteredHandlers, handler)
+		}
+	}
+	eventHandlers = filteredHandlers
+
+	// execute Update/Resync
+	txn := r.txnTracker.NewControllerTxn(!isUpdate)
+	r.txn = txn
+	defer func() { r.txn = nil }()
+	var (
+		idx      int
+		fatalErr bool
+	)
+	for idx = 0; idx < len(eventHandlers); idx++ {
+		var (
+			err    error
+			change string
+			errStr string
+		)
+		handler := eventHandlers[idx]
+		if isUpdate {
+			change, err = handler.Update(event, txn)
+		} else {
+			err = handler.Resync(event, r.kubeStateData, r.resyncCount, txn)
+		}
+		if err != nil {
+			errStr = err.Error()
+			wasErr = err
+		}
+		replayed.Handlers = append(replayed.Handlers, &controller.EventHandlingRecord{
+			Handler:  handler.String(),
+			Change:   change,
+			Error:    err,
+			ErrorStr: errStr,
+		})
+		if err != nil {
+			_, fatalErr = err.(*api.FatalError)
+			_, abortErr := err.(*api.AbortEventError)
+			if withRevert || fatalErr || abortErr {
+				break
+			}
+		}
+	}
+
+	// commit the transaction into the mock
+	emptyTxn := len(txn.Values) == 0
+	if (!emptyTxn || !isUpdate) && (wasErr == nil || !withRevert) {
+		ctx := scheduler.WithDescription(context.Background(), event.GetName())
+		if !isUpdate {
+			ctx = scheduler.WithResync(ctx, scheduler.FullResync, r.resyncCount == 1)
+		}
+		if _, err := txn.Commit(ctx); err != nil {
+			replayed.TxnError = err.Error()
+		}
+		r.applyTxn(txn.Values, !isUpdate)
+		replayed.Txn = r.txnOperations(txn.Values)
+	}
+
+	// revert internal changes of handlers for failed RevertOnFailure event
+	if wasErr != nil && withRevert && !fatalErr {
+		for idx = idx - 1; idx >= 0; idx-- {
+			var errStr string
+			handler := eventHandlers[idx]
+			err := handler.Revert(event)
+			if err != nil {
+				errStr = err.Error()
+			}
+			replayed.Handlers = append(replayed.Handlers, &controller.EventHandlingRecord{
+				Handler:  handler.String(),
+				Revert:   true,
+				Error:    err,
+				ErrorStr: errStr,
+			})
+		}
+	}
+	return replayed
+}
+
+// applyTxn updates the configuration committed so far.
+func (r *Replayer) applyTxn(values api.KeyValuePairs, isResync bool) {
+	if isResync {
+		r.config = make(api.KeyValuePairs)
+	}
+	for key, value := range values {
+		if value == nil {
+			delete(r.config, key)
+			continue
+		}
+		r.config[key] = value
+	}
+}
+
+// txnOperations converts transaction values into a list of operations sorted
+// by keys.
+func (r *Replayer) txnOperations(values api.KeyValuePairs) (ops []*TxnOperation) {
+	marshaller := &jsonpb.Marshaler{}
+	for key, value := range values {
+		op := &TxnOperation{Key: key, Delete: value == nil}
+		if value != nil {
+			encoded, err := marshaller.MarshalToString(value)
+			if err != nil {
+				r.log.Warnf("Failed to marshal value for key %s: %v", key, err)
+				encoded = fmt.Sprintf("%q", proto.CompactTextString(value))
+			}
+			op.Value = json.RawMessage(encoded)
+		}
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Key < ops[j].Key
+	})
+	return ops
+}
+
+// ReadEventHistory reads event records persisted by the file-based event
+// history sink (one JSON-encoded record per line).
+func ReadEventHistory(reader io.Reader) (records []*controller.PersistedEventRecord, err error) {
+	bufReader := bufio.NewReader(reader)
+	for {
+		line, err := bufReader.ReadBytes('\n')
+		if len(line) > 0 && err != io.EOF {
+			record := &controller.PersistedEventRecord{}
+			if err := json.Unmarshal(line, record); err != nil {
+				return records, err
+			}
+			records = append(records, record)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+}
+
+// ReadKubeState reads Kubernetes state data to run the initial startup resync
+// of the replay against. Accepted is the JSON-encoded payload of a DBResync event,
+// or a persisted record of a DBResync event with the payload (e.g. selected from
+// the event history via the REST API).
+func ReadKubeState(reader io.Reader) (api.KubeStateData, error) {
+	var input struct {
+		api.EventPayload
+		Payload *api.EventPayload // set for a persisted event record
+	}
+	if err := json.NewDecoder(reader).Decode(&input); err != nil {
+		return nil, err
+	}
+	payload := &input.EventPayload
+	if input.Payload != nil {
+		payload = input.Payload
+	}
+	if payload.EventType != api.DBResyncPayloadType {
+		return nil, fmt.Errorf("expected payload of the DBResync event, got: %q", payload.EventType)
+	}
+	dbResync, err := api.DBResyncFromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	return dbResync.KubeState, nil
+}
+
+// WriteReplayedEvents writes replayed events into the given writer, JSON-encoded.
+func WriteReplayedEvents(writer io.Writer, replayed []*ReplayedEvent) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(replayed)
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	"github.com/americanbinary/vpp/plugins/controller"
+	"github.com/americanbinary/vpp/plugins/controller/api"
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+)
+
+const (
+	configPrefix = "config/"
+	ackSuffix    = "/ack"
+)
+
+// podConfigured is a follow-up event pushed by testHandler for every added pod.
+type podConfigured struct {
+	key string
+}
+
+func (ev *podConfigured) GetName() string                            { return "Pod Configured" }
+func (ev *podConfigured) String() string                             { return "Pod Configured " + ev.key }
+func (ev *podConfigured) Method() api.EventMethodType                { return api.Update }
+func (ev *podConfigured) TransactionType() api.UpdateTransactionType { return api.BestEffort }
+func (ev *podConfigured) Direction() api.UpdateDirectionType         { return api.Forward }
+func (ev *podConfigured) IsBlocking() bool                           { return false }
+func (ev *podConfigured) Done(error)                                 {}
+
+// testHandler renders config for every pod from the Kubernetes state data
+// and acknowledges the config with a follow-up event.
+type testHandler struct {
+	replayer     *Replayer
+	resyncCounts []int
+}
+
+func (h *testHandler) String() string {
+	return "test-handler"
+}
+
+func (h *testHandler) HandlesEvent(event api.Event) bool {
+	return true
+}
+
+func (h *testHandler) Resync(event api.Event, kubeStateData api.KubeStateData, resyncCount int, txn api.ResyncOperations) error {
+	h.resyncCounts = append(h.resyncCounts, resyncCount)
+	for key, pod := range kubeStateData[podmodel.PodKeyword] {
+		txn.Put(configPrefix+key, pod)
+	}
+	return nil
+}
+
+func (h *testHandler) Update(event api.Event, txn api.UpdateOperations) (changeDescription string, err error) {
+	switch ev := event.(type) {
+	case *api.KubeStateChange:
+		if ev.NewValue == nil {
+			txn.Delete(configPrefix + ev.Key)
+			return "pod config removed", nil
+		}
+		txn.Put(configPrefix+ev.Key, ev.NewValue)
+		return "pod config added", h.replayer.PushEvent(&podConfigured{key: ev.Key})
+	case *podConfigured:
+		config := h.replayer.GetConfig(configPrefix + ev.key)
+		if config == nil {
+			return "", nil
+		}
+		txn.Put(configPrefix+ev.key+ackSuffix, config)
+		return "pod config acknowledged", nil
+	}
+	return "", nil
+}
+
+func (h *testHandler) Revert(event api.Event) error {
+	return nil
+}
+
+func newTestReplayer() (*Replayer, *testHandler) {
+	replayer := NewReplayer(logrus.DefaultLogger())
+	handler := &testHandler{replayer: replayer}
+	replayer.SetEventHandlers([]api.EventHandler{handler})
+	return replayer, handler
+}
+
+func testPod(name string) *podmodel.Pod {
+	return &podmodel.Pod{Name: name, Namespace: "default"}
+}
+
+func podKey(name string) string {
+	return podmodel.Key(name, "default")
+}
+
+func addPod(name string) *api.KubeStateChange {
+	return &api.KubeStateChange{
+		Key:      podKey(name),
+		Resource: podmodel.PodKeyword,
+		NewValue: testPod(name),
+	}
+}
+
+func dbResync(pods ...string) *api.DBResync {
+	resync := api.NewDBResync()
+	for _, pod := range pods {
+		resync.KubeState[podmodel.PodKeyword][podKey(pod)] = testPod(pod)
+	}
+	return resync
+}
+
+// testRecord creates persisted record of the event (with payload if withPayload is true).
+func testRecord(index uint64, agentStart time.Time, seqNum uint64, event api.Event, withPayload, isFollowUp bool) *controller.PersistedEventRecord {
+	record := &controller.PersistedEventRecord{
+		Index:      index,
+		AgentStart: agentStart,
+		SeqNum:     seqNum,
+		Name:       event.GetName(),
+		Method:     event.Method(),
+	}
+	if withPayload {
+		payload, err := event.(api.SerializableEvent).Payload()
+		Expect(err).ToNot(HaveOccurred())
+		record.Payload = payload
+	}
+	encoded, err := json.Marshal(&controller.EventRecord{
+		SeqNum:     seqNum,
+		Name:       event.GetName(),
+		Method:     event.Method(),
+		IsFollowUp: isFollowUp,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	record.Record = encoded
+	return record
+}
+
+func txnKeys(replayed *ReplayedEvent) (keys []string) {
+	for _, op := range replayed.Txn {
+		keys = append(keys, op.Key)
+	}
+	return keys
+}
+
+func TestDBResyncPayload(t *testing.T) {
+	RegisterTestingT(t)
+
+	resync := dbResync("pod1", "pod2")
+	resync.Local = true
+	payload, err := resync.Payload()
+	Expect(err).ToNot(HaveOccurred())
+
+	decoded, err := api.DBResyncFromPayload(payload)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(decoded.Local).To(BeTrue())
+	Expect(decoded.KubeState[podmodel.PodKeyword]).To(HaveLen(2))
+	Expect(decoded.KubeState[podmodel.PodKeyword][podKey("pod1")].(*podmodel.Pod).Name).To(Equal("pod1"))
+	Expect(decoded.KubeState[podmodel.PodKeyword][podKey("pod2")].(*podmodel.Pod).Name).To(Equal("pod2"))
+}
+
+func TestReadKubeState(t *testing.T) {
+	RegisterTestingT(t)
+
+	// payload of the DBResync event
+	payload, err := dbResync("pod1", "pod2").Payload()
+	Expect(err).ToNot(HaveOccurred())
+	encoded, err := json.Marshal(payload)
+	Expect(err).ToNot(HaveOccurred())
+	kubeState, err := ReadKubeState(bytes.NewReader(encoded))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(kubeState[podmodel.PodKeyword]).To(HaveLen(2))
+	Expect(kubeState[podmodel.PodKeyword][podKey("pod1")].(*podmodel.Pod).Name).To(Equal("pod1"))
+
+	// persisted record of the DBResync event
+	encoded, err = json.Marshal(testRecord(0, time.Now(), 0, dbResync("pod3"), true, false))
+	Expect(err).ToNot(HaveOccurred())
+	kubeState, err = ReadKubeState(bytes.NewReader(encoded))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(kubeState[podmodel.PodKeyword]).To(HaveLen(1))
+	Expect(kubeState[podmodel.PodKeyword][podKey("pod3")].(*podmodel.Pod).Name).To(Equal("pod3"))
+
+	// payload of a different event
+	encoded, err = json.Marshal(testRecord(1, time.Now(), 1, addPod("pod4"), true, false))
+	Expect(err).ToNot(HaveOccurred())
+	_, err = ReadKubeState(bytes.NewReader(encoded))
+	Expect(err).To(HaveOccurred())
+}
+
+func TestReplayWithKubeState(t *testing.T) {
+	RegisterTestingT(t)
+	replayer, handler := newTestReplayer()
+
+	start := time.Now()
+	kubeState := dbResync("pod1").KubeState
+	records := []*controller.PersistedEventRecord{
+		testRecord(0, start, 0, dbResync("pod2"), true, false),
+		testRecord(1, start, 1, addPod("pod3"), true, false),
+		testRecord(2, start, 2, &podConfigured{key: podKey("pod3")}, false, true),
+		testRecord(3, start, 3, addPod("pod4"), false, false),
+	}
+	replayed := replayer.Replay(kubeState, records)
+	Expect(replayed).To(HaveLen(6))
+
+	// startup resync against the given state replaces the recorded one
+	Expect(replayed[0].InitialResync).To(BeTrue())
+	Expect(replayed[0].Skipped).To(BeFalse())
+	Expect(txnKeys(replayed[0])).To(Equal([]string{configPrefix + podKey("pod1")}))
+	Expect(replayed[1].Index).To(BeEquivalentTo(0))
+	Expect(replayed[1].Skipped).To(BeTrue())
+
+	// update followed by the re-generated follow-up event
+	Expect(replayed[2].Index).To(BeEquivalentTo(1))
+	Expect(replayed[2].Skipped).To(BeFalse())
+	Expect(txnKeys(replayed[2])).To(Equal([]string{configPrefix + podKey("pod3")}))
+	Expect(replayed[3].FollowUp).To(BeTrue())
+	Expect(replayed[3].Name).To(Equal("Pod Configured"))
+	Expect(txnKeys(replayed[3])).To(Equal([]string{configPrefix + podKey("pod3") + ackSuffix}))
+
+	// recorded follow-up and event without payload are skipped
+	Expect(replayed[4].Index).To(BeEquivalentTo(2))
+	Expect(replayed[4].Skipped).To(BeTrue())
+	Expect(replayed[5].Index).To(BeEquivalentTo(3))
+	Expect(replayed[5].Skipped).To(BeTrue())
+
+	Expect(handler.resyncCounts).To(Equal([]int{1}))
+	Expect(replayer.GetConfig(configPrefix + podKey("pod1"))).ToNot(BeNil())
+	Expect(replayer.GetConfig(configPrefix + podKey("pod2"))).To(BeNil())
+	Expect(replayer.GetConfig(configPrefix + podKey("pod3") + ackSuffix)).ToNot(BeNil())
+}
+
+func TestReplayAgentRestart(t *testing.T) {
+	RegisterTestingT(t)
+	replayer, handler := newTestReplayer()
+
+	// both runs start with the sequence number 0
+	firstRun := time.Now()
+	secondRun := firstRun.Add(time.Minute)
+	records := []*controller.PersistedEventRecord{
+		testRecord(0, firstRun, 0, dbResync("pod1"), true, false),
+		testRecord(1, firstRun, 1, addPod("pod2"), true, false),
+		testRecord(2, firstRun, 2, &api.VerificationResync{}, true, false),
+		testRecord(3, secondRun, 0, dbResync("pod3"), true, false),
+	}
+	replayed := replayer.Replay(nil, records)
+	Expect(replayed).To(HaveLen(5))
+
+	// the startup resync of the first run is replayed as recorded
+	Expect(replayed[0].InitialResync).To(BeTrue())
+	Expect(replayed[0].Index).To(BeEquivalentTo(0))
+	Expect(txnKeys(replayed[0])).To(Equal([]string{configPrefix + podKey("pod1")}))
+	Expect(replayed[2].FollowUp).To(BeTrue())
+	Expect(txnKeys(replayed[3])).To(Equal([]string{
+		configPrefix + podKey("pod1"), configPrefix + podKey("pod2")}))
+
+	// the startup resync of the second run is not skipped
+	Expect(replayed[4].Index).To(BeEquivalentTo(3))
+	Expect(replayed[4].Skipped).To(BeFalse())
+	Expect(replayed[4].InitialResync).To(BeFalse())
+	Expect(txnKeys(replayed[4])).To(Equal([]string{configPrefix + podKey("pod3")}))
+
+	// resync counter is reset by the agent restart
+	Expect(handler.resyncCounts).To(Equal([]int{1, 2, 1}))
+	Expect(replayer.GetConfig(configPrefix + podKey("pod1"))).To(BeNil())
+	Expect(replayer.GetConfig(configPrefix + podKey("pod3"))).ToNot(BeNil())
+}
+
+func TestReplayWithoutStartupResync(t *testing.T) {
+	RegisterTestingT(t)
+	replayer, handler := newTestReplayer()
+
+	records := []*controller.PersistedEventRecord{
+		testRecord(5, time.Now(), 5, addPod("pod1"), true, false),
+	}
+	replayed := replayer.Replay(nil, records)
+	Expect(replayed).To(HaveLen(3))
+
+	// initial resync is executed against empty state data
+	Expect(replayed[0].InitialResync).To(BeTrue())
+	Expect(replayed[0].Txn).To(BeEmpty())
+	Expect(replayed[1].Index).To(BeEquivalentTo(5))
+	Expect(replayed[1].SeqNum).To(BeEquivalentTo(5))
+	Expect(replayed[2].FollowUp).To(BeTrue())
+	Expect(handler.resyncCounts).To(Equal([]int{1}))
+}
+
+func TestReadEventHistory(t *testing.T) {
+	RegisterTestingT(t)
+
+	start := time.Now()
+	history := &bytes.Buffer{}
+	for i := 0; i < 3; i++ {
+		encoded, err := json.Marshal(testRecord(uint64(i), start, uint64(i), addPod("pod"), true, false))
+		Expect(err).ToNot(HaveOccurred())
+		history.Write(encoded)
+		if i < 2 {
+			history.WriteByte('\n')
+		}
+	}
+
+	// incomplete last record is ignored
+	records, err := ReadEventHistory(history)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(records).To(HaveLen(2))
+	Expect(records[1].Index).To(BeEquivalentTo(1))
+	Expect(records[1].AgentStart.Equal(start)).To(BeTrue())
+	Expect(records[1].Payload.EventType).To(Equal(api.KubeStateChangePayloadType))
+}
@@ -15,12 +15,17 @@
 package controller
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/unrolled/render"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
 )
 
 const (
@@ -44,8 +49,39 @@ const (
 	firstArg  = "first"
 	lastArg   = "last"
 
+	// event-history filters (applied together with any of the arguments above):
+	//   * name (event name)
+	//   * handler (name of the handler that processed the event)
+	//   * method (event method type, e.g. "Update", "FullResync")
+	//   * errors-only (only events processed with error)
+	nameArg       = "name"
+	handlerArg    = "handler"
+	methodArg     = "method"
+	errorsOnlyArg = "errors-only"
+
+	// event-history arguments for the persisted history (spans agent restarts):
+	//   * persisted (read records from the event history sink)
+	//   * offset (number of matching records to skip)
+	//   * limit (max. number of records to return)
+	persistedArg = "persisted"
+	offsetArg    = "offset"
+	limitArg     = "limit"
+
 	// resyncURL is URL used to trigger DB resync.
 	resyncURL = urlPrefix + "resync"
+
+	// dryRunURL is URL used to dry-run KubeStateChange event, which is expected
+	// in the request body serialized as api.EventPayload.
+	dryRunURL = urlPrefix + "dry-run"
+
+	// resyncDiffsURL is URL used to obtain diffs of healing and verification resyncs
+	// from the (in-memory) event history.
+	resyncDiffsURL = urlPrefix + "resync-diffs"
+
+	// resync-diffs arguments:
+	//   * drift-only (only resyncs which have changed something)
+	//   * last (max. number of latest diffs to return)
+	driftOnlyArg = "drift-only"
 )
 
 // errorString wraps string representation of an error that, unlike the original
@@ -54,6 +90,13 @@ type errorString struct {
 	Error string
 }
 
+// EventHistoryPage is a page of records read from the persisted event history.
+type EventHistoryPage struct {
+	Total   int // number of all persisted records matching the filter
+	Offset  int
+	Records []*PersistedEventRecord
+}
+
 // registerHandlers registers all supported REST APIs.
 func (c *Controller) registerHandlers() {
 	if c.HTTPHandlers == nil {
@@ -62,20 +105,19 @@ func (c *Controller) registerHandlers() {
 	}
 	c.HTTPHandlers.RegisterHTTPHandler(eventHistoryURL, c.eventHistoryGetHandler, "GET")
 	c.HTTPHandlers.RegisterHTTPHandler(resyncURL, c.resyncReqHandler, "POST")
+	c.HTTPHandlers.RegisterHTTPHandler(dryRunURL, c.dryRunReqHandler, "POST")
+	c.HTTPHandlers.RegisterHTTPHandler(resyncDiffsURL, c.resyncDiffsGetHandler, "GET")
 }
 
 // eventHistoryGetHandler is the GET handler for "event-history" API.
 func (c *Controller) eventHistoryGetHandler(formatter *render.Render) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		c.historyLock.Lock()
-		defer c.historyLock.Unlock()
-
 		timeParams := make(map[string]time.Time)
 		intParams := make(map[string]int)
 		args := req.URL.Query()
 
 		// parse optional integer parameters
-		for _, intParam := range []string{seqNumArg, fromArg, toArg, firstArg, lastArg} {
+		for _, intParam := range []string{seqNumArg, fromArg, toArg, firstArg, lastArg, offsetArg, limitArg} {
 			if param, withParam := args[intParam]; withParam && len(param) == 1 {
 				value, err := strconv.Atoi(param[0])
 				if err != nil {
@@ -98,10 +140,55 @@ func (c *Controller) eventHistoryGetHandler(formatter *render.Render) http.Handl
 			}
 		}
 
+		// parse optional filters
+		filter, err := parseEventHistoryFilter(args)
+		if err != nil {
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		}
+
+		// handle *persisted* argument
+		if persisted, err := parseBoolArg(args, persistedArg); err != nil {
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		} else if persisted {
+			if c.historySink == nil {
+				err := errors.New("event history is not persisted")
+				formatter.JSON(w, http.StatusNotFound, errorString{err.Error()})
+				return
+			}
+			offset := intParams[offsetArg]
+			records, total, err := c.historySink.Query(filter, offset, intParams[limitArg])
+			if err != nil {
+				formatter.JSON(w, http.StatusInternalServerError, errorString{err.Error()})
+				return
+			}
+			formatter.JSON(w, http.StatusOK, EventHistoryPage{
+				Total:   total,
+				Offset:  offset,
+				Records: records,
+			})
+			return
+		}
+
+		c.historyLock.Lock()
+		defer c.historyLock.Unlock()
+
+		// apply filters on the in-memory history
+		history := c.eventHistory
+		if !filter.isEmpty() {
+			history = nil
+			for _, event := range c.eventHistory {
+				if filter.MatchesRecord(event) {
+					history = append(history, event)
+				}
+			}
+		}
+
 		// handle seq-num argument
 		if seqNum, hasSeqNum := intParams[seqNumArg]; hasSeqNum {
 			var evRecord *EventRecord
-			for _, event := range c.eventHistory {
+			for _, event := range history {
 				if event.SeqNum == uint64(seqNum) {
 					evRecord = event
 					break
@@ -120,7 +207,7 @@ func (c *Controller) eventHistoryGetHandler(formatter *render.Render) http.Handl
 		since, hasSince := timeParams[sinceArg]
 		until, hasUntil := timeParams[untilArg]
 		if hasSince || hasUntil {
-			evHistory := c.getEventHistory(since, until)
+			evHistory := getEventHistory(history, since, until)
 			formatter.JSON(w, http.StatusOK, evHistory)
 			return
 		}
@@ -130,7 +217,7 @@ func (c *Controller) eventHistoryGetHandler(formatter *render.Render) http.Handl
 		to, hasTo := intParams[toArg]
 		if hasFrom && hasTo {
 			var evHistory []*EventRecord
-			for _, event := range c.eventHistory {
+			for _, event := range history {
 				if event.SeqNum >= uint64(from) && event.SeqNum <= uint64(to) {
 					evHistory = append(evHistory, event)
 				}
@@ -141,26 +228,26 @@ func (c *Controller) eventHistoryGetHandler(formatter *render.Render) http.Handl
 
 		// handle *first* argument
 		if first, hasFirst := intParams[firstArg]; hasFirst {
-			historyLen := len(c.eventHistory)
+			historyLen := len(history)
 			if historyLen < first {
 				first = historyLen
 			}
-			formatter.JSON(w, http.StatusOK, c.eventHistory[:first])
+			formatter.JSON(w, http.StatusOK, history[:first])
 			return
 		}
 
 		// handle *last* argument
 		if last, hasLast := intParams[lastArg]; hasLast {
-			historyLen := len(c.eventHistory)
+			historyLen := len(history)
 			if historyLen < last {
 				last = historyLen
 			}
-			formatter.JSON(w, http.StatusOK, c.eventHistory[historyLen-last:])
+			formatter.JSON(w, http.StatusOK, history[historyLen-last:])
 			return
 		}
 
 		// full history
-		formatter.JSON(w, http.StatusOK, c.eventHistory)
+		formatter.JSON(w, http.StatusOK, history)
 	}
 }
 
@@ -176,6 +263,70 @@ func (c *Controller) resyncReqHandler(formatter *render.Render) http.HandlerFunc
 	}
 }
 
+// dryRunReqHandler is the POST handler for "dry-run" API.
+func (c *Controller) dryRunReqHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		payload := &api.EventPayload{}
+		if err := json.NewDecoder(req.Body).Decode(payload); err != nil {
+			err = fmt.Errorf("failed to decode event payload: %v", err)
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		}
+		if payload.EventType != api.KubeStateChangePayloadType {
+			err := fmt.Errorf("dry-run is supported only for %s events", api.KubeStateChangePayloadType)
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		}
+		ksChange, err := api.KubeStateChangeFromPayload(payload)
+		if err == nil && (ksChange.Resource == "" || ksChange.Key == "") {
+			err = errors.New("resource and key of the changed value must be specified")
+		}
+		if err != nil {
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		}
+		result, err := c.DryRun(ksChange)
+		if errors.Is(err, ErrDryRunNotSupported) {
+			formatter.JSON(w, http.StatusUnprocessableEntity, errorString{err.Error()})
+			return
+		}
+		if err != nil {
+			formatter.JSON(w, http.StatusInternalServerError, errorString{err.Error()})
+			return
+		}
+		formatter.JSON(w, http.StatusOK, result)
+	}
+}
+
+// resyncDiffsGetHandler is the GET handler for "resync-diffs" API.
+func (c *Controller) resyncDiffsGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		args := req.URL.Query()
+		driftOnly, err := parseBoolArg(args, driftOnlyArg)
+		if err != nil {
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		}
+		last := -1
+		if param := args.Get(lastArg); param != "" {
+			last, err = strconv.Atoi(param)
+			if err != nil {
+				formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+				return
+			}
+		}
+
+		c.historyLock.Lock()
+		diffs := getResyncDiffs(c.eventHistory, driftOnly)
+		c.historyLock.Unlock()
+
+		if last >= 0 && len(diffs) > last {
+			diffs = diffs[len(diffs)-last:]
+		}
+		formatter.JSON(w, http.StatusOK, diffs)
+	}
+}
+
 // stringToTime converts Unix timestamp from string to time.Time.
 func stringToTime(s string) (time.Time, error) {
 	sec, err := strconv.ParseInt(s, 10, 64)
@@ -184,3 +335,37 @@ func stringToTime(s string) (time.Time, error) {
 	}
 	return time.Unix(sec, 0), nil
 }
+
+// parseEventHistoryFilter builds filter for event history from the URL arguments.
+func parseEventHistoryFilter(args url.Values) (*EventHistoryFilter, error) {
+	filter := &EventHistoryFilter{
+		Name:    args.Get(nameArg),
+		Handler: args.Get(handlerArg),
+	}
+	if method := args.Get(methodArg); method != "" {
+		methodType, err := api.ParseEventMethodType(method)
+		if err != nil {
+			return nil, err
+		}
+		filter.Method = &methodType
+	}
+	errorsOnly, err := parseBoolArg(args, errorsOnlyArg)
+	if err != nil {
+		return nil, err
+	}
+	filter.ErrorsOnly = errorsOnly
+	return filter, nil
+}
+
+// parseBoolArg parses optional boolean argument. Argument given without a value
+// is evaluated as true.
+func parseBoolArg(args url.Values, arg string) (bool, error) {
+	param, withParam := args[arg]
+	if !withParam {
+		return false, nil
+	}
+	if len(param) == 0 || param[0] == "" {
+		return true, nil
+	}
+	return strconv.ParseBool(param[0])
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sort"
+	"time"
+
+	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
+	"go.ligato.io/vpp-agent/v3/proto/ligato/kvscheduler"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+const (
+	// operations executed by a resync to bring the data plane in-sync
+	resyncAdded    = "added"
+	resyncModified = "modified"
+	resyncRemoved  = "removed"
+)
+
+// ResyncDiff lists values which KVScheduler had to add, modify or remove during
+// a healing or verification resync, i.e. where the data plane has drifted away
+// from the state requested by the Contiv plugins and the external configuration.
+type ResyncDiff struct {
+	Changes []*ResyncChange
+}
+
+// ResyncChange describes change of a single value made by a resync.
+type ResyncChange struct {
+	Key       string
+	Operation string // added, modified or removed
+	Handler   string // handler or external config source which requested the value (empty for removed values)
+	IsDerived bool   // true for values derived from other values (handler is not known)
+	Error     string // error returned by the operation (if any)
+}
+
+// InSync returns true if the resync did not have to change anything.
+func (d *ResyncDiff) InSync() bool {
+	return len(d.Changes) == 0
+}
+
+// countByOperation returns the number of changes for every operation.
+func (d *ResyncDiff) countByOperation() map[string]int {
+	counts := make(map[string]int)
+	for _, change := range d.Changes {
+		counts[change.Operation]++
+	}
+	return counts
+}
+
+// ResyncDiffRecord associates resync diff with the event that produced it.
+type ResyncDiffRecord struct {
+	SeqNum          uint64
+	ProcessingStart time.Time
+	Name            string
+	Method          api.EventMethodType
+	Diff            *ResyncDiff
+}
+
+// detectsDrift returns true for resyncs which are expected to find the data plane
+// in-sync - Healing and Verification resyncs. Other resyncs (e.g. DB resync
+// after re-connect or resync of an external config source) bring changes
+// of the requested state, which are not drift.
+func detectsDrift(event api.Event) bool {
+	switch event.(type) {
+	case *api.HealingResync, *api.VerificationResync:
+		return true
+	}
+	return false
+}
+
+// buildResyncDiff builds the diff from the operations executed by KVScheduler
+// in the resync transaction. <owners> maps keys to handlers (or external config
+// sources) which requested the values.
+func buildResyncDiff(txn *scheduler.RecordedTxn, owners map[string]string) *ResyncDiff {
+	diff := &ResyncDiff{}
+	if txn == nil {
+		return diff
+	}
+	changes := make(map[string]*ResyncChange)
+	for _, op := range txn.Executed {
+		if op.NOOP {
+			continue
+		}
+		var operation string
+		switch op.Operation {
+		case kvscheduler.TxnOperation_CREATE:
+			operation = resyncAdded
+		case kvscheduler.TxnOperation_UPDATE:
+			operation = resyncModified
+		case kvscheduler.TxnOperation_DELETE:
+			operation = resyncRemoved
+		default:
+			continue
+		}
+		change, hasChange := changes[op.Key]
+		if hasChange {
+			// value re-created or removed and added back
+			if change.Operation != operation {
+				change.Operation = resyncModified
+			}
+		} else {
+			change = &ResyncChange{
+				Key:       op.Key,
+				Operation: operation,
+				Handler:   owners[op.Key],
+				IsDerived: op.IsDerived,
+			}
+			changes[op.Key] = change
+			diff.Changes = append(diff.Changes, change)
+		}
+		if op.NewErr != nil {
+			change.Error = op.NewErr.Error()
+		}
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Key < diff.Changes[j].Key
+	})
+	return diff
+}
+
+// resyncValueOwners returns map of keys to handlers (or external config sources)
+// which requested the values in the resync transaction.
+// Downstream resync does not run any handlers, therefore owners of the internal
+// configuration are taken from the Controller's view of the internal config,
+// overridden by handlers which have (re)built values in this transaction.
+func (c *Controller) resyncValueOwners() map[string]string {
+	owners := make(map[string]string)
+	for source := range c.externalConfig {
+		for key := range c.externalConfig[source] {
+			owners[key] = source
+		}
+	}
+	for key, handler := range c.internalOwners {
+		owners[key] = handler
+	}
+	for key, handler := range c.txn.owners {
+		// internal configuration takes precedence (external value is only merged into it)
+		owners[key] = handler
+	}
+	return owners
+}
+
+// getResyncDiffs returns diffs of resyncs from the given event history.
+// Resyncs without any change are included only if <driftOnly> is false.
+func getResyncDiffs(history []*EventRecord, driftOnly bool) (diffs []*ResyncDiffRecord) {
+	for _, record := range history {
+		if record.ResyncDiff == nil || (driftOnly && record.ResyncDiff.InSync()) {
+			continue
+		}
+		diffs = append(diffs, &ResyncDiffRecord{
+			SeqNum:          record.SeqNum,
+			ProcessingStart: record.ProcessingStart,
+			Name:            record.Name,
+			Method:          record.Method,
+			Diff:            record.ResyncDiff,
+		})
+	}
+	return diffs
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
+	"go.ligato.io/vpp-agent/v3/proto/ligato/kvscheduler"
+
+	"github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+func TestDetectsDrift(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(detectsDrift(&api.HealingResync{Type: api.Periodic})).To(BeTrue())
+	Expect(detectsDrift(&api.HealingResync{Type: api.AfterError})).To(BeTrue())
+	Expect(detectsDrift(&api.VerificationResync{})).To(BeTrue())
+
+	// resync after re-connect to the DB and external config changes are not drift
+	Expect(detectsDrift(api.NewDBResync())).To(BeFalse())
+	Expect(detectsDrift(api.NewExternalConfigResync("grpc", false))).To(BeFalse())
+	Expect(detectsDrift(api.NewExternalConfigChange("grpc", false))).To(BeFalse())
+}
+
+func TestBuildResyncDiff(t *testing.T) {
+	RegisterTestingT(t)
+
+	txn := &scheduler.RecordedTxn{
+		Executed: scheduler.RecordedTxnOps{
+			{Operation: kvscheduler.TxnOperation_CREATE, Key: "c"},
+			{Operation: kvscheduler.TxnOperation_UPDATE, Key: "a", NOOP: true},
+			{Operation: kvscheduler.TxnOperation_DELETE, Key: "b"},
+			{Operation: kvscheduler.TxnOperation_CREATE, Key: "b", NewErr: errors.New("failed")},
+			{Operation: kvscheduler.TxnOperation_CREATE, Key: "d", IsDerived: true},
+		},
+	}
+	owners := map[string]string{"b": "ipnet", "c": "grpc"}
+	diff := buildResyncDiff(txn, owners)
+	Expect(diff.InSync()).To(BeFalse())
+	Expect(diff.Changes).To(Equal([]*ResyncChange{
+		{Key: "b", Operation: resyncModified, Handler: "ipnet", Error: "failed"},
+		{Key: "c", Operation: resyncAdded, Handler: "grpc"},
+		{Key: "d", Operation: resyncAdded, IsDerived: true},
+	}))
+
+	Expect(buildResyncDiff(&scheduler.RecordedTxn{}, owners).InSync()).To(BeTrue())
+	Expect(buildResyncDiff(nil, owners).InSync()).To(BeTrue())
+}
+
+func TestResyncValueOwners(t *testing.T) {
+	RegisterTestingT(t)
+
+	c := &Controller{
+		externalConfig: map[string]api.KeyValuePairs{
+			"grpc": {"a": nil, "b": nil},
+		},
+		internalOwners: map[string]string{"b": "ipnet", "c": "policy"},
+	}
+
+	// downstream resync - no handler has built the transaction
+	c.txn = newTransaction(nil)
+	Expect(c.resyncValueOwners()).To(Equal(map[string]string{
+		"a": "grpc", "b": "ipnet", "c": "policy",
+	}))
+
+	// upstream resync - handlers which re-built the values take precedence
+	c.txn.owner = "service"
+	c.txn.Delete("c")
+	Expect(c.resyncValueOwners()).To(Equal(map[string]string{
+		"a": "grpc", "b": "ipnet", "c": "service",
+	}))
+}
+
+func TestGetResyncDiffs(t *testing.T) {
+	RegisterTestingT(t)
+
+	inSync := &ResyncDiff{}
+	drift := &ResyncDiff{Changes: []*ResyncChange{{Key: "a", Operation: resyncRemoved}}}
+	history := []*EventRecord{
+		{SeqNum: 1, Name: "Healing Resync", ResyncDiff: inSync},
+		{SeqNum: 2, Name: "Pod Update"},
+		{SeqNum: 3, Name: "Verification Resync", ResyncDiff: drift},
+	}
+
+	diffs := getResyncDiffs(history, false)
+	Expect(diffs).To(HaveLen(2))
+	Expect(diffs[0].SeqNum).To(BeEquivalentTo(1))
+	Expect(diffs[1].Diff).To(Equal(drift))
+
+	diffs = getResyncDiffs(history, true)
+	Expect(diffs).To(HaveLen(1))
+	Expect(diffs[0].SeqNum).To(BeEquivalentTo(3))
+}
@@ -33,6 +33,11 @@ type kvSchedulerTxn struct {
 
 	// injected by Controller to merge external with internal configuration
 	merged api.KeyValuePairs
+
+	// handler currently building the transaction and handlers which have
+	// set the values (key -> handler)
+	owner  string
+	owners map[string]string
 }
 
 // newTransaction creates new transaction to be executed via KVScheduler.
@@ -41,6 +46,7 @@ func newTransaction(kvScheduler scheduler_api.KVScheduler) *kvSchedulerTxn {
 		kvScheduler: kvScheduler,
 		values:      make(api.KeyValuePairs),
 		merged:      make(api.KeyValuePairs),
+		owners:      make(map[string]string),
 	}
 }
 
@@ -69,10 +75,13 @@ func (txn *kvSchedulerTxn) Put(key string, value proto.Message) {
 		panic(fmt.Sprintf("Put nil value for key '%s'", key))
 	}
 	txn.values[key] = value
+	txn.owners[key] = txn.owner
 }
 
+// Delete adds request to the transaction to delete an existing value.
 func (txn *kvSchedulerTxn) Delete(key string) {
 	txn.values[key] = nil
+	txn.owners[key] = txn.owner
 }
 
 // Get is used to obtain value already prepared to be applied by this transaction.
@@ -15,6 +15,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -251,7 +252,8 @@ func (c *CrdController) createCRD(FullName, Group, Version, Plural, Name string)
 			Validation: validation,
 		},
 	}
-	_, err := c.APIClient.ApiextensionsV1beta1().CustomResourceDefinitions().Create(crd)
+	_, err := c.APIClient.ApiextensionsV1beta1().CustomResourceDefinitions().Create(context.TODO(), crd,
+		meta.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		return nil
 	}
@@ -34,6 +34,7 @@ import (
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Handler implements the Handler interface for CRD<->KVDB Reflector.
@@ -187,7 +188,7 @@ func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
 		customConfig.Status.Status = v1.StatusFailure
 		customConfig.Status.Message = opRetval.Error()
 	}
-	_, err := h.CrdClient.ContivppV1().CustomConfigurations(customConfig.Namespace).Update(customConfig)
+	_, err := h.CrdClient.ContivppV1().CustomConfigurations(customConfig.Namespace).Update(context.TODO(), customConfig, meta.UpdateOptions{})
 	return err
 }
 
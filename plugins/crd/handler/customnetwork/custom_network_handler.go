@@ -19,12 +19,14 @@
 package customnetwork
 
 import (
+	"context"
 	"errors"
 	"github.com/americanbinary/vpp/plugins/crd/handler/customnetwork/model"
 	"github.com/americanbinary/vpp/plugins/crd/handler/kvdbreflector"
 	"github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Handler implements the Handler interface for CRD<->KVDB Reflector.
@@ -84,7 +86,7 @@ func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
 		customNet.Status.Status = v1.StatusFailure
 		customNet.Status.Message = opRetval.Error()
 	}
-	_, err := h.CrdClient.ContivppV1().CustomNetworks(customNet.Namespace).Update(customNet)
+	_, err := h.CrdClient.ContivppV1().CustomNetworks(customNet.Namespace).Update(context.TODO(), customNet, meta.UpdateOptions{})
 	return err
 }
 
@@ -0,0 +1,325 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalconfigrollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/jsonpb"
+	"go.ligato.io/cn-infra/v2/logging"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/americanbinary/vpp/plugins/crd/api"
+	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
+	"github.com/americanbinary/vpp/plugins/grpc/rpc"
+	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
+)
+
+// DefaultGrpcPort is the port on which Contiv agents expose the gRPC API by default.
+const DefaultGrpcPort = 9111
+
+// Handler implements the Handler interface for ExternalConfigRollout CRD.
+// Every rollout is executed only once - subsequent changes in the resource
+// spec are ignored and a new resource should be created instead.
+// Rollouts run in separate go routines, i.e. they do not block processing
+// of other resources by the CRD controller.
+type Handler struct {
+	Log       logging.Logger
+	CrdClient *crdClientSet.Clientset
+	K8sCache  api.K8sCache
+
+	// Ctx is cancelled when contiv-crd is shutting down - running rollouts
+	// are aborted and left in the InProgress phase (context.Background() if nil).
+	Ctx context.Context
+
+	// GrpcPort is the port of the gRPC API of Contiv agents (DefaultGrpcPort if zero).
+	GrpcPort int
+
+	// NodeClient is used to apply the configuration on nodes (via the gRPC API
+	// of Contiv agents if nil).
+	NodeClient NodeClient
+
+	sync.Mutex
+	processed map[types.UID]struct{} // rollouts processed by this instance
+}
+
+// Init initializes the handler.
+func (h *Handler) Init() error {
+	if h.Ctx == nil {
+		h.Ctx = context.Background()
+	}
+	if h.GrpcPort == 0 {
+		h.GrpcPort = DefaultGrpcPort
+	}
+	if h.NodeClient == nil {
+		h.NodeClient = &grpcNodeClient{resolveAddr: h.nodeAddress}
+	}
+	h.processed = make(map[types.UID]struct{})
+	return nil
+}
+
+// ObjectCreated starts the rollout if it has not been executed yet.
+func (h *Handler) ObjectCreated(obj interface{}) error {
+	rollout, ok := obj.(*v1.ExternalConfigRollout)
+	if !ok {
+		return errors.New("failed to cast into ExternalConfigRollout struct")
+	}
+	return h.processRollout(rollout)
+}
+
+// ObjectUpdated starts the rollout if it has not been executed yet.
+// Updates of already executed rollouts are ignored.
+func (h *Handler) ObjectUpdated(oldObj, newObj interface{}) error {
+	rollout, ok := newObj.(*v1.ExternalConfigRollout)
+	if !ok {
+		return errors.New("failed to cast into ExternalConfigRollout struct")
+	}
+	return h.processRollout(rollout)
+}
+
+// ObjectDeleted does nothing - the configuration is not reverted when
+// the rollout resource is removed (a rollout with the "delete" operation
+// should be used instead).
+func (h *Handler) ObjectDeleted(obj interface{}) error {
+	return nil
+}
+
+// PublishStatus does nothing - status is published by the handler as the rollout
+// progresses.
+func (h *Handler) PublishStatus(obj interface{}, opRetval error) error {
+	return nil
+}
+
+// processRollout starts the rollout unless it was already processed.
+// Rollout found in the InProgress phase was interrupted (by restart of contiv-crd)
+// and therefore gets rolled back.
+func (h *Handler) processRollout(rolloutObj *v1.ExternalConfigRollout) error {
+	h.Lock()
+	if _, processed := h.processed[rolloutObj.UID]; processed {
+		h.Unlock()
+		return nil
+	}
+	h.Unlock()
+
+	switch rolloutObj.Status.Phase {
+	case "":
+		return h.runRollout(rolloutObj)
+	case v1.RolloutInProgress:
+		h.markProcessed(rolloutObj)
+		h.Log.Warnf("Rollout %s/%s was interrupted, rolling back", rolloutObj.Namespace, rolloutObj.Name)
+		data, err := parseConfig(rolloutObj.Spec.Config)
+		if err != nil {
+			return h.failRollout(rolloutObj, err)
+		}
+		r := h.newRollout(rolloutObj, data, rolloutObj.Status.DeepCopy())
+		r.status.Message = "rollout was interrupted"
+		go r.rollback()
+	default:
+		// already finalized
+		h.markProcessed(rolloutObj)
+	}
+	return nil
+}
+
+// runRollout validates the rollout spec and starts applying the configuration
+// to the nodes wave by wave.
+func (h *Handler) runRollout(rolloutObj *v1.ExternalConfigRollout) error {
+	spec := rolloutObj.Spec
+	nodes := spec.Nodes
+	if len(nodes) == 0 {
+		nodes = h.allNodes()
+		if len(nodes) == 0 {
+			// K8s state may not be synchronized yet - let the controller retry
+			return fmt.Errorf("no nodes known for rollout %s/%s", rolloutObj.Namespace, rolloutObj.Name)
+		}
+	}
+	h.markProcessed(rolloutObj)
+
+	if spec.Operation != "" && spec.Operation != putOperation && spec.Operation != deleteOperation {
+		return h.failRollout(rolloutObj, fmt.Errorf("unsupported operation: %s", spec.Operation))
+	}
+	data, err := parseConfig(spec.Config)
+	if err != nil {
+		return h.failRollout(rolloutObj, err)
+	}
+	waves, err := planWaves(nodes, spec.CanaryNodes, spec.WaveSize)
+	if err != nil {
+		return h.failRollout(rolloutObj, err)
+	}
+
+	h.Log.Infof("Starting rollout %s/%s in %d wave(s)", rolloutObj.Namespace, rolloutObj.Name, len(waves))
+	r := h.newRollout(rolloutObj, data, initStatus(waves))
+	go func() {
+		r.run()
+		h.Log.Infof("Rollout %s/%s has finished with phase %s", rolloutObj.Namespace, rolloutObj.Name, r.status.Phase)
+	}()
+	return nil
+}
+
+// newRollout prepares rollout of the configuration from the given resource.
+func (h *Handler) newRollout(rolloutObj *v1.ExternalConfigRollout, data *rpc.DataRequest,
+	status *v1.ExternalConfigRolloutStatus) *rollout {
+
+	operation := rolloutObj.Spec.Operation
+	if operation == "" {
+		operation = putOperation
+	}
+	timeout := defaultNodeTimeout
+	if rolloutObj.Spec.NodeTimeout > 0 {
+		timeout = time.Duration(rolloutObj.Spec.NodeTimeout) * time.Second
+	}
+	return &rollout{
+		ctx:       h.Ctx,
+		client:    h.NodeClient,
+		operation: operation,
+		data:      data,
+		timeout:   timeout,
+		status:    status,
+		publish: func(status *v1.ExternalConfigRolloutStatus) {
+			if err := h.publishStatus(rolloutObj, status); err != nil {
+				h.Log.Errorf("Failed to publish status of rollout %s/%s: %v",
+					rolloutObj.Namespace, rolloutObj.Name, err)
+			}
+		},
+	}
+}
+
+// failRollout marks rollout which could not be started as failed.
+func (h *Handler) failRollout(rolloutObj *v1.ExternalConfigRollout, err error) error {
+	h.Log.Errorf("Rollout %s/%s has failed: %v", rolloutObj.Namespace, rolloutObj.Name, err)
+	return h.publishStatus(rolloutObj, &v1.ExternalConfigRolloutStatus{
+		Phase:   v1.RolloutFailed,
+		Message: err.Error(),
+	})
+}
+
+// publishStatus updates status of the rollout resource.
+func (h *Handler) publishStatus(rolloutObj *v1.ExternalConfigRollout, status *v1.ExternalConfigRolloutStatus) error {
+	client := h.CrdClient.ContivppV1().ExternalConfigRollouts(rolloutObj.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := client.Get(h.Ctx, rolloutObj.Name, meta_v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		latest.Status = *status.DeepCopy()
+		_, err = client.Update(h.Ctx, latest, meta_v1.UpdateOptions{})
+		return err
+	})
+}
+
+// markProcessed records that the rollout was processed by this instance.
+func (h *Handler) markProcessed(rolloutObj *v1.ExternalConfigRollout) {
+	h.Lock()
+	defer h.Unlock()
+	h.processed[rolloutObj.UID] = struct{}{}
+}
+
+// allNodes returns names of all nodes in the cluster.
+func (h *Handler) allNodes() (nodes []string) {
+	for _, node := range h.K8sCache.RetrieveAllK8sNodes() {
+		nodes = append(nodes, node.Name)
+	}
+	return nodes
+}
+
+// nodeAddress returns address of the gRPC server of the Contiv agent running
+// on the given node.
+func (h *Handler) nodeAddress(nodeName string) (string, error) {
+	node, err := h.K8sCache.RetrieveK8sNode(nodeName)
+	if err != nil {
+		return "", err
+	}
+	for _, adr := range node.Addresses {
+		if adr.Type == nodemodel.NodeAddress_NodeInternalIP {
+			return net.JoinHostPort(adr.Address, strconv.Itoa(h.GrpcPort)), nil
+		}
+	}
+	return "", fmt.Errorf("node %s has no internal IP address", nodeName)
+}
+
+// parseConfig parses YAML-formatted DataRequest.
+func parseConfig(config string) (*rpc.DataRequest, error) {
+	jsonData, err := yaml.YAMLToJSON([]byte(config))
+	if err != nil {
+		return nil, err
+	}
+	data := &rpc.DataRequest{}
+	if err = jsonpb.UnmarshalString(string(jsonData), data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration: %v", err)
+	}
+	return data, nil
+}
+
+// Validation generates OpenAPIV3 validator for ExternalConfigRollout CRD
+func Validation() *apiextv1beta1.CustomResourceValidation {
+	validation := &apiextv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextv1beta1.JSONSchemaProps{
+			Required: []string{"spec"},
+			Type:     "object",
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"spec": {
+					Type:     "object",
+					Required: []string{"config"},
+					Properties: map[string]apiextv1beta1.JSONSchemaProps{
+						"operation": {
+							Type: "string",
+							Enum: []apiextv1beta1.JSON{
+								{Raw: []byte(`"put"`)},
+								{Raw: []byte(`"delete"`)},
+							},
+						},
+						"config": {
+							Type: "string",
+						},
+						"nodes": {
+							Type: "array",
+							Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextv1beta1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						},
+						"canaryNodes": {
+							Type: "array",
+							Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextv1beta1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						},
+						"waveSize": {
+							Type: "integer",
+						},
+						"nodeTimeout": {
+							Type: "integer",
+						},
+					},
+				},
+			},
+		},
+	}
+	return validation
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalconfigrollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"google.golang.org/grpc"
+
+	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	contivgrpc "github.com/americanbinary/vpp/plugins/grpc"
+	"github.com/americanbinary/vpp/plugins/grpc/rpc"
+)
+
+const (
+	// operations supported by the rollout
+	putOperation    = "put"
+	deleteOperation = "delete"
+
+	// time limit for applying the configuration on a single node, unless
+	// specified otherwise by the rollout
+	defaultNodeTimeout = time.Minute
+)
+
+// NodeClient applies external configuration on a single node.
+type NodeClient interface {
+	// Get returns current values of the configuration items from the data
+	// on the node. Items not configured on the node are omitted.
+	Get(ctx context.Context, node string, data *rpc.DataRequest) (*rpc.DataRequest, error)
+
+	// Apply executes the given operation with the configuration on the node.
+	// The method should not return until the node has finished applying
+	// the configuration.
+	Apply(ctx context.Context, node string, operation string, data *rpc.DataRequest) error
+}
+
+// NodeAddressResolver returns address of the gRPC server of a Contiv agent
+// running on the given node.
+type NodeAddressResolver func(node string) (address string, err error)
+
+// grpcNodeClient applies external configuration using the gRPC API of Contiv
+// agents (DataChangeService).
+type grpcNodeClient struct {
+	resolveAddr NodeAddressResolver
+}
+
+// Get connects to the gRPC server of the node and calls Get.
+func (c *grpcNodeClient) Get(ctx context.Context, node string, data *rpc.DataRequest) (*rpc.DataRequest, error) {
+	conn, err := c.connect(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return rpc.NewDataChangeServiceClient(conn).Get(ctx, data)
+}
+
+// Apply connects to the gRPC server of the node and calls Put or Del, which
+// returns only once the corresponding transaction has finalized.
+func (c *grpcNodeClient) Apply(ctx context.Context, node string, operation string, data *rpc.DataRequest) error {
+	conn, err := c.connect(ctx, node)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := rpc.NewDataChangeServiceClient(conn)
+	switch operation {
+	case putOperation:
+		_, err = client.Put(ctx, data)
+	case deleteOperation:
+		_, err = client.Del(ctx, data)
+	default:
+		err = fmt.Errorf("unsupported operation: %s", operation)
+	}
+	return err
+}
+
+// connect opens connection to the gRPC server of the given node.
+func (c *grpcNodeClient) connect(ctx context.Context, node string) (*grpc.ClientConn, error) {
+	address, err := c.resolveAddr(node)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", address, err)
+	}
+	return conn, nil
+}
+
+// planWaves splits the nodes into waves - the canary nodes go first,
+// the remaining nodes (in the alphabetical order) follow in waves of the given
+// size (all in one wave if the size is zero). If the canary nodes are not
+// specified, the first node is used as the canary.
+func planWaves(nodes, canaryNodes []string, waveSize uint32) ([][]string, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes to apply the configuration to")
+	}
+	targets := make(map[string]struct{})
+	var remaining []string
+	for _, node := range nodes {
+		if _, duplicate := targets[node]; duplicate {
+			continue
+		}
+		targets[node] = struct{}{}
+		remaining = append(remaining, node)
+	}
+	sort.Strings(remaining)
+
+	if len(canaryNodes) == 0 {
+		canaryNodes = remaining[:1]
+	}
+	var canary []string
+	isCanary := make(map[string]struct{})
+	for _, node := range canaryNodes {
+		if _, isTarget := targets[node]; !isTarget {
+			return nil, fmt.Errorf("canary node %s is not among the target nodes", node)
+		}
+		if _, duplicate := isCanary[node]; duplicate {
+			continue
+		}
+		isCanary[node] = struct{}{}
+		canary = append(canary, node)
+	}
+	waves := [][]string{canary}
+
+	var wave []string
+	for _, node := range remaining {
+		if _, skip := isCanary[node]; skip {
+			continue
+		}
+		wave = append(wave, node)
+		if waveSize > 0 && uint32(len(wave)) == waveSize {
+			waves = append(waves, wave)
+			wave = nil
+		}
+	}
+	if len(wave) > 0 {
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// rollout applies external configuration to multiple nodes in waves
+// and reverts already updated nodes if any wave fails.
+// Before a wave is applied, prior values of the configuration items are read
+// from the nodes of the wave and published with the status. Rollback then
+// restores these values, even if the rollout was interrupted.
+type rollout struct {
+	ctx       context.Context // rollout is aborted (without rollback) when cancelled
+	client    NodeClient
+	operation string
+	data      *rpc.DataRequest
+	timeout   time.Duration
+
+	// status is updated as the rollout progresses and published after every
+	// step using the given callback
+	status  *v1.ExternalConfigRolloutStatus
+	publish func(status *v1.ExternalConfigRolloutStatus)
+}
+
+// initStatus prepares status for a rollout of the given waves.
+func initStatus(waves [][]string) *v1.ExternalConfigRolloutStatus {
+	status := &v1.ExternalConfigRolloutStatus{
+		Phase: v1.RolloutInProgress,
+	}
+	for waveIdx, wave := range waves {
+		for _, node := range wave {
+			status.Nodes = append(status.Nodes, v1.NodeRolloutStatus{
+				Node:  node,
+				Wave:  uint32(waveIdx),
+				State: v1.NodePending,
+			})
+		}
+	}
+	return status
+}
+
+// run executes the rollout wave by wave, starting with the canary nodes.
+func (r *rollout) run() {
+	r.publish(r.status)
+	for wave := uint32(0); ; wave++ {
+		nodes := r.nodesOfWave(wave)
+		if len(nodes) == 0 {
+			break
+		}
+		failed := r.forEachNode(nodes, r.snapshotNode, "", v1.NodeFailed)
+		if len(failed) > 0 {
+			// nothing was applied in this wave
+			for _, idx := range nodes {
+				r.status.Nodes[idx].PriorConfig = ""
+			}
+		} else {
+			// persist the snapshots before the nodes get changed
+			r.publish(r.status)
+			failed = r.forEachNode(nodes, r.applyOnNode, v1.NodeApplied, v1.NodeFailed)
+		}
+		if r.ctx.Err() != nil {
+			// aborted - left InProgress to be rolled back once restarted
+			return
+		}
+		if len(failed) > 0 {
+			r.status.Message = fmt.Sprintf("wave %d failed on node(s) %v", wave, failed)
+			r.rollback()
+			return
+		}
+		r.publish(r.status)
+	}
+	r.status.Phase = v1.RolloutSucceeded
+	r.status.Message = ""
+	r.publish(r.status)
+}
+
+// rollback restores the prior configuration on all nodes where the rollout
+// has (or might have) changed the configuration, i.e. nodes with a snapshot.
+func (r *rollout) rollback() {
+	var nodes []int
+	for idx, node := range r.status.Nodes {
+		if node.PriorConfig != "" {
+			nodes = append(nodes, idx)
+		}
+	}
+	failed := r.forEachNode(nodes, r.revertNode, v1.NodeReverted, v1.NodeRevertFailed)
+	if r.ctx.Err() != nil {
+		return
+	}
+	if len(failed) > 0 {
+		r.status.Phase = v1.RolloutFailed
+		r.status.Message += fmt.Sprintf(", revert failed on node(s) %v", failed)
+	} else {
+		r.status.Phase = v1.RolloutRolledBack
+	}
+	r.publish(r.status)
+}
+
+// nodesOfWave returns indexes (into status.Nodes) of nodes from the given wave.
+func (r *rollout) nodesOfWave(wave uint32) (nodes []int) {
+	for idx, node := range r.status.Nodes {
+		if node.Wave == wave {
+			nodes = append(nodes, idx)
+		}
+	}
+	return nodes
+}
+
+// snapshotNode reads prior values of the configuration items from the node
+// into its status.
+func (r *rollout) snapshotNode(ctx context.Context, nodeStatus *v1.NodeRolloutStatus) error {
+	prior, err := r.client.Get(ctx, nodeStatus.Node, r.data)
+	if err != nil {
+		return fmt.Errorf("failed to read prior configuration: %v", err)
+	}
+	encoded, err := (&jsonpb.Marshaler{}).MarshalToString(prior)
+	if err != nil {
+		return err
+	}
+	nodeStatus.PriorConfig = encoded
+	return nil
+}
+
+// applyOnNode executes the operation of the rollout on the node.
+func (r *rollout) applyOnNode(ctx context.Context, nodeStatus *v1.NodeRolloutStatus) error {
+	return r.client.Apply(ctx, nodeStatus.Node, r.operation, r.data)
+}
+
+// revertNode restores the prior configuration of the node - items added
+// by the rollout are removed and the snapshotted values are put back.
+func (r *rollout) revertNode(ctx context.Context, nodeStatus *v1.NodeRolloutStatus) error {
+	prior := &rpc.DataRequest{}
+	if err := jsonpb.UnmarshalString(nodeStatus.PriorConfig, prior); err != nil {
+		return fmt.Errorf("failed to unmarshal prior configuration: %v", err)
+	}
+	priorConfig := contivgrpc.BuildConfig(prior, false)
+	if r.operation == putOperation {
+		added := contivgrpc.BuildConfig(r.data, false)
+		for key := range priorConfig {
+			delete(added, key)
+		}
+		if len(added) > 0 {
+			err := r.client.Apply(ctx, nodeStatus.Node, deleteOperation, contivgrpc.BuildDataRequest(added))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(priorConfig) == 0 {
+		return nil
+	}
+	return r.client.Apply(ctx, nodeStatus.Node, putOperation, prior)
+}
+
+// forEachNode executes the action on the given nodes in parallel and updates
+// their state accordingly (okState of empty string leaves the state unchanged).
+// Returns names of the nodes where the action has failed.
+func (r *rollout) forEachNode(nodes []int, action func(ctx context.Context, nodeStatus *v1.NodeRolloutStatus) error,
+	okState, failedState string) (failed []string) {
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+	for _, idx := range nodes {
+		wg.Add(1)
+		go func(nodeStatus *v1.NodeRolloutStatus) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+			err := action(ctx, nodeStatus)
+			cancel()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				nodeStatus.State = failedState
+				nodeStatus.Error = err.Error()
+				failed = append(failed, nodeStatus.Node)
+				return
+			}
+			if okState != "" {
+				nodeStatus.State = okState
+			}
+		}(&r.status.Nodes[idx])
+	}
+	wg.Wait()
+	sort.Strings(failed)
+	return failed
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalconfigrollout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
+	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
+	contivgrpc "github.com/americanbinary/vpp/plugins/grpc"
+	"github.com/americanbinary/vpp/plugins/grpc/rpc"
+)
+
+// mockNodeClient records operations applied on nodes, maintains configuration
+// of every node and fails for the selected nodes and operations.
+// Every failure is triggered only once. Failed operations are still applied
+// (i.e. partially applied configuration).
+type mockNodeClient struct {
+	sync.Mutex
+	applied  []string                            // "<operation>:<node>"
+	failures map[string]struct{}                 // "<operation>:<node>"
+	config   map[string]controller.KeyValuePairs // node -> configured items
+}
+
+func newMockNodeClient(failures ...string) *mockNodeClient {
+	client := &mockNodeClient{
+		failures: make(map[string]struct{}),
+		config:   make(map[string]controller.KeyValuePairs),
+	}
+	for _, failure := range failures {
+		client.failures[failure] = struct{}{}
+	}
+	return client
+}
+
+func (c *mockNodeClient) configure(node string, data *rpc.DataRequest) {
+	c.config[node] = contivgrpc.BuildConfig(data, false)
+}
+
+func (c *mockNodeClient) Get(ctx context.Context, node string, data *rpc.DataRequest) (*rpc.DataRequest, error) {
+	c.Lock()
+	defer c.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, fail := c.failures["get:"+node]; fail {
+		return nil, errors.New("failed to get")
+	}
+	config := make(controller.KeyValuePairs)
+	for key := range contivgrpc.BuildConfig(data, true) {
+		if value, configured := c.config[node][key]; configured {
+			config[key] = value
+		}
+	}
+	return contivgrpc.BuildDataRequest(config), nil
+}
+
+func (c *mockNodeClient) Apply(ctx context.Context, node string, operation string, data *rpc.DataRequest) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	op := operation + ":" + node
+	c.applied = append(c.applied, op)
+	if c.config[node] == nil {
+		c.config[node] = make(controller.KeyValuePairs)
+	}
+	for key, value := range contivgrpc.BuildConfig(data, operation == deleteOperation) {
+		if value == nil {
+			delete(c.config[node], key)
+		} else {
+			c.config[node][key] = value
+		}
+	}
+	if _, fail := c.failures[op]; fail {
+		delete(c.failures, op)
+		return errors.New("failed to apply")
+	}
+	return nil
+}
+
+func nodeStates(status *v1.ExternalConfigRolloutStatus) map[string]string {
+	states := make(map[string]string)
+	for _, node := range status.Nodes {
+		states[node.Node] = node.State
+	}
+	return states
+}
+
+func testRoute(dstNetwork string, weight uint32) *vpp_l3.Route {
+	return &vpp_l3.Route{
+		DstNetwork:        dstNetwork,
+		NextHopAddr:       "192.168.16.1",
+		OutgoingInterface: "GigabitEthernet0/8/0",
+		Weight:            weight,
+	}
+}
+
+func testData(routes ...*vpp_l3.Route) *rpc.DataRequest {
+	return &rpc.DataRequest{StaticRoutes: routes}
+}
+
+func newTestRollout(ctx context.Context, client NodeClient, status *v1.ExternalConfigRolloutStatus,
+	data *rpc.DataRequest) (*rollout, *int) {
+	published := new(int)
+	return &rollout{
+		ctx:       ctx,
+		client:    client,
+		operation: putOperation,
+		data:      data,
+		timeout:   time.Second,
+		status:    status,
+		publish: func(status *v1.ExternalConfigRolloutStatus) {
+			*published++
+		},
+	}, published
+}
+
+func runTestRollout(client NodeClient, nodes []string, waveSize uint32, data *rpc.DataRequest) (*v1.ExternalConfigRolloutStatus, int) {
+	waves, err := planWaves(nodes, nil, waveSize)
+	Expect(err).To(BeNil())
+	r, published := newTestRollout(context.Background(), client, initStatus(waves), data)
+	r.run()
+	return r.status, *published
+}
+
+func TestPlanWaves(t *testing.T) {
+	RegisterTestingT(t)
+
+	// default canary + waves of 2
+	waves, err := planWaves([]string{"node5", "node1", "node3", "node2", "node4", "node1"}, nil, 2)
+	Expect(err).To(BeNil())
+	Expect(waves).To(Equal([][]string{{"node1"}, {"node2", "node3"}, {"node4", "node5"}}))
+
+	// explicit canaries + single wave
+	waves, err = planWaves([]string{"node1", "node2", "node3"}, []string{"node3", "node2"}, 0)
+	Expect(err).To(BeNil())
+	Expect(waves).To(Equal([][]string{{"node3", "node2"}, {"node1"}}))
+
+	// canary only
+	waves, err = planWaves([]string{"node1"}, nil, 0)
+	Expect(err).To(BeNil())
+	Expect(waves).To(Equal([][]string{{"node1"}}))
+
+	// invalid canary
+	_, err = planWaves([]string{"node1", "node2"}, []string{"node3"}, 0)
+	Expect(err).ToNot(BeNil())
+
+	// no nodes
+	_, err = planWaves(nil, nil, 0)
+	Expect(err).ToNot(BeNil())
+}
+
+func TestRolloutSuccess(t *testing.T) {
+	RegisterTestingT(t)
+
+	client := newMockNodeClient()
+	data := testData(testRoute("10.20.0.0/16", 0))
+	status, published := runTestRollout(client, []string{"node1", "node2", "node3"}, 1, data)
+	Expect(status.Phase).To(Equal(v1.RolloutSucceeded))
+	Expect(nodeStates(status)).To(Equal(map[string]string{
+		"node1": v1.NodeApplied,
+		"node2": v1.NodeApplied,
+		"node3": v1.NodeApplied,
+	}))
+	Expect(client.applied).To(Equal([]string{"put:node1", "put:node2", "put:node3"}))
+	Expect(published).To(Equal(8)) // initial + (snapshot + applied) x 3 waves + final
+	for _, node := range status.Nodes {
+		Expect(node.PriorConfig).To(Equal("{}"))
+	}
+}
+
+func TestRolloutCanaryFailure(t *testing.T) {
+	RegisterTestingT(t)
+
+	client := newMockNodeClient("put:node1")
+	prior := testData(testRoute("10.20.0.0/16", 5))
+	client.configure("node1", prior)
+
+	data := testData(testRoute("10.20.0.0/16", 10), testRoute("10.30.0.0/16", 0))
+	status, _ := runTestRollout(client, []string{"node1", "node2", "node3"}, 0, data)
+	Expect(status.Phase).To(Equal(v1.RolloutRolledBack))
+	Expect(nodeStates(status)).To(Equal(map[string]string{
+		"node1": v1.NodeReverted,
+		"node2": v1.NodePending,
+		"node3": v1.NodePending,
+	}))
+
+	// added route is removed, the modified one is restored
+	Expect(client.applied).To(Equal([]string{"put:node1", "delete:node1", "put:node1"}))
+	restored := contivgrpc.BuildConfig(prior, false)
+	Expect(client.config["node1"]).To(HaveLen(len(restored)))
+	for key, value := range restored {
+		Expect(proto.Equal(client.config["node1"][key].(proto.Message), value.(proto.Message))).To(BeTrue())
+	}
+}
+
+func TestRolloutWaveFailure(t *testing.T) {
+	RegisterTestingT(t)
+
+	client := newMockNodeClient("put:node3", "delete:node2")
+	data := testData(testRoute("10.20.0.0/16", 0))
+	status, _ := runTestRollout(client, []string{"node1", "node2", "node3", "node4"}, 2, data)
+	Expect(status.Phase).To(Equal(v1.RolloutFailed))
+	Expect(nodeStates(status)).To(Equal(map[string]string{
+		"node1": v1.NodeReverted,
+		"node2": v1.NodeRevertFailed,
+		"node3": v1.NodeReverted,
+		"node4": v1.NodePending,
+	}))
+	Expect(client.applied).ToNot(ContainElement("put:node4"))
+	Expect(client.config["node1"]).To(BeEmpty())
+	Expect(client.config["node3"]).To(BeEmpty())
+}
+
+func TestRolloutSnapshotFailure(t *testing.T) {
+	RegisterTestingT(t)
+
+	client := newMockNodeClient("get:node3")
+	data := testData(testRoute("10.20.0.0/16", 0))
+	status, _ := runTestRollout(client, []string{"node1", "node2", "node3"}, 0, data)
+	Expect(status.Phase).To(Equal(v1.RolloutRolledBack))
+
+	// second wave is not applied at all
+	Expect(nodeStates(status)).To(Equal(map[string]string{
+		"node1": v1.NodeReverted,
+		"node2": v1.NodePending,
+		"node3": v1.NodeFailed,
+	}))
+	Expect(client.applied).To(Equal([]string{"put:node1", "delete:node1"}))
+}
+
+func TestRolloutInterrupted(t *testing.T) {
+	RegisterTestingT(t)
+
+	client := newMockNodeClient()
+	data := testData(testRoute("10.20.0.0/16", 0))
+	waves, err := planWaves([]string{"node1", "node2", "node3"}, nil, 1)
+	Expect(err).To(BeNil())
+
+	// interrupted after node2 was snapshotted
+	status := initStatus(waves)
+	status.Nodes[0].State = v1.NodeApplied
+	status.Nodes[0].PriorConfig = "{}"
+	status.Nodes[1].PriorConfig = "{}"
+	client.configure("node1", data)
+	client.configure("node2", data)
+
+	r, published := newTestRollout(context.Background(), client, status, data)
+	r.rollback()
+	Expect(*published).To(Equal(1))
+	Expect(status.Phase).To(Equal(v1.RolloutRolledBack))
+	Expect(nodeStates(status)).To(Equal(map[string]string{
+		"node1": v1.NodeReverted,
+		"node2": v1.NodeReverted,
+		"node3": v1.NodePending,
+	}))
+	Expect(client.config["node1"]).To(BeEmpty())
+	Expect(client.config["node2"]).To(BeEmpty())
+}
+
+func TestRolloutAborted(t *testing.T) {
+	RegisterTestingT(t)
+
+	client := newMockNodeClient()
+	waves, err := planWaves([]string{"node1", "node2"}, nil, 0)
+	Expect(err).To(BeNil())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// left in progress, to be rolled back after restart
+	r, published := newTestRollout(ctx, client, initStatus(waves), testData(testRoute("10.20.0.0/16", 0)))
+	r.run()
+	Expect(*published).To(Equal(1))
+	Expect(r.status.Phase).To(Equal(v1.RolloutInProgress))
+	Expect(client.applied).To(BeEmpty())
+}
+
+func TestPublishStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	// API server storing a single rollout resource
+	const rolloutPath = "/apis/contivpp.io/v1/namespaces/default/externalconfigrollouts/rollout1"
+	stored := &v1.ExternalConfigRollout{
+		TypeMeta:   meta_v1.TypeMeta{APIVersion: "contivpp.io/v1", Kind: "ExternalConfigRollout"},
+		ObjectMeta: meta_v1.ObjectMeta{Name: "rollout1", Namespace: "default", ResourceVersion: "1"},
+	}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path != rolloutPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPut {
+			updated := &v1.ExternalConfigRollout{}
+			if err := json.NewDecoder(r.Body).Decode(updated); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			stored = updated
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stored)
+	}))
+	defer server.Close()
+
+	crdClient, err := crdClientSet.NewForConfig(&rest.Config{Host: server.URL})
+	Expect(err).To(BeNil())
+	h := &Handler{CrdClient: crdClient, Ctx: context.Background()}
+
+	status := &v1.ExternalConfigRolloutStatus{Phase: v1.RolloutSucceeded, Message: "done"}
+	Expect(h.publishStatus(stored.DeepCopy(), status)).To(BeNil())
+	Expect(requests).To(Equal([]string{"GET " + rolloutPath, "PUT " + rolloutPath}))
+	Expect(stored.Status.Phase).To(Equal(v1.RolloutSucceeded))
+	Expect(stored.Status.Message).To(Equal("done"))
+}
@@ -19,6 +19,7 @@
 package externalinterface
 
 import (
+	"context"
 	"errors"
 
 	"github.com/americanbinary/vpp/plugins/crd/handler/externalinterface/model"
@@ -26,6 +27,7 @@ import (
 	"github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Handler implements the Handler interface for CRD<->KVDB Reflector.
@@ -85,7 +87,7 @@ func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
 		extIface.Status.Status = v1.StatusFailure
 		extIface.Status.Message = opRetval.Error()
 	}
-	_, err := h.CrdClient.ContivppV1().ExternalInterfaces(extIface.Namespace).Update(extIface)
+	_, err := h.CrdClient.ContivppV1().ExternalInterfaces(extIface.Namespace).Update(context.TODO(), extIface, meta.UpdateOptions{})
 	return err
 }
 
@@ -19,12 +19,15 @@
 package nodeconfig
 
 import (
+	"context"
 	"errors"
 
 	"github.com/americanbinary/vpp/plugins/crd/handler/kvdbreflector"
 	"github.com/americanbinary/vpp/plugins/crd/handler/nodeconfig/model"
 	"github.com/americanbinary/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
 	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Handler implements the Handler interface for CRD<->KVDB Reflector.
@@ -84,7 +87,7 @@ func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
 		nodeConfig.Status.State = v1.StatusFailure
 		nodeConfig.Status.Message = opRetval.Error()
 	}
-	_, err := h.CrdClient.NodeconfigV1().NodeConfigs(nodeConfig.Namespace).Update(nodeConfig)
+	_, err := h.CrdClient.NodeconfigV1().NodeConfigs(nodeConfig.Namespace).Update(context.TODO(), nodeConfig, meta.UpdateOptions{})
 	return err
 }
 
@@ -1,6 +1,7 @@
 package telemetry
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -97,13 +98,15 @@ func (cr *CRDReport) GenerateCRDReport() {
 
 	if shouldCreate {
 		cr.Log.Debug("Create '%s' namespace '%s, and value: %v", name, namespace, crdTelemetryReportCopy)
-		_, err = cr.CrdClient.TelemetryV1().TelemetryReports(namespace).Create(crdTelemetryReportCopy)
+		_, err = cr.CrdClient.TelemetryV1().TelemetryReports(namespace).Create(context.TODO(), crdTelemetryReportCopy,
+			meta.CreateOptions{})
 		if err != nil {
 			cr.Log.Errorf("Could not create '%s'  err: %v, namespace '%s'", name, err, namespace)
 		}
 	} else {
 		cr.Log.Debug("Update '%s' namespace '%s, and value: %v", name, namespace, crdTelemetryReportCopy)
-		_, err := cr.CrdClient.TelemetryV1().TelemetryReports(namespace).Update(crdTelemetryReportCopy)
+		_, err := cr.CrdClient.TelemetryV1().TelemetryReports(namespace).Update(context.TODO(), crdTelemetryReportCopy,
+			meta.UpdateOptions{})
 		if err != nil {
 			cr.Log.Errorf("Could not update '%s'  err: %v, namespace '%s'", name, err, namespace)
 		}
@@ -53,6 +53,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ServiceFunctionChainList{},
 		&CustomConfiguration{},
 		&CustomConfigurationList{},
+		&ExternalConfigRollout{},
+		&ExternalConfigRolloutList{},
+		&ClusterNetworkPolicy{},
+		&ClusterNetworkPolicyList{},
 	)
 
 	// register the type in the scheme
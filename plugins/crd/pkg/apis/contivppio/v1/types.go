@@ -205,3 +205,218 @@ type CustomConfigurationList struct {
 
 	Items []CustomConfiguration `json:"items"`
 }
+
+// ExternalConfigRollout is used to apply external vpp-agent configuration
+// (via the gRPC API of Contiv agents) across multiple nodes. The configuration
+// is first applied to canary nodes, then to the remaining nodes in waves. If any
+// node fails to apply the configuration, the change is reverted on all nodes
+// updated so far.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExternalConfigRollout struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the specification for the rollout.
+	Spec ExternalConfigRolloutSpec `json:"spec"`
+	// Status informs about the progress of the rollout on every node.
+	Status ExternalConfigRolloutStatus `json:"status,omitempty"`
+}
+
+// ExternalConfigRolloutSpec is the spec for external config rollout resource.
+type ExternalConfigRolloutSpec struct {
+	// Operation is either "put" (create/update configuration, default) or "delete".
+	Operation string `json:"operation,omitempty"`
+
+	// Config is YAML-formatted DataRequest, as defined by the gRPC API
+	// of Contiv agents (plugins/grpc/rpc/rpc.proto).
+	Config string `json:"config"`
+
+	// Nodes is a list of nodes to apply the configuration to.
+	// If empty, the configuration is applied to all nodes of the cluster.
+	Nodes []string `json:"nodes,omitempty"`
+
+	// CanaryNodes are updated first (in parallel), before any other node.
+	// If empty, the first node (in the alphabetical order) is used as the canary.
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+
+	// WaveSize is the maximum number of (non-canary) nodes updated in parallel.
+	// If zero, all the remaining nodes are updated in a single wave.
+	WaveSize uint32 `json:"waveSize,omitempty"`
+
+	// NodeTimeout is the time limit in seconds for applying the configuration
+	// on a single node (60 seconds if not set).
+	NodeTimeout uint32 `json:"nodeTimeout,omitempty"`
+}
+
+// Phases of the external config rollout.
+const (
+	// RolloutInProgress is set while the configuration is being applied.
+	RolloutInProgress = "InProgress"
+	// RolloutSucceeded is set when all the nodes have applied the configuration.
+	RolloutSucceeded = "Succeeded"
+	// RolloutRolledBack is set when the rollout failed and all the updated nodes
+	// were successfully reverted.
+	RolloutRolledBack = "RolledBack"
+	// RolloutFailed is set when the rollout is invalid or when the rollback
+	// of some node has failed.
+	RolloutFailed = "Failed"
+)
+
+// States of the rollout on a single node.
+const (
+	// NodePending is the state of a node not yet updated.
+	NodePending = "Pending"
+	// NodeApplied is the state of a node which has applied the configuration.
+	NodeApplied = "Applied"
+	// NodeFailed is the state of a node which has failed to apply the configuration.
+	NodeFailed = "Failed"
+	// NodeReverted is the state of a node where the configuration was reverted.
+	NodeReverted = "Reverted"
+	// NodeRevertFailed is the state of a node where the revert has failed.
+	NodeRevertFailed = "RevertFailed"
+)
+
+// ExternalConfigRolloutStatus informs about the progress of the rollout.
+type ExternalConfigRolloutStatus struct {
+	// Phase of the rollout (empty if the rollout has not started yet).
+	Phase string `json:"phase,omitempty"`
+	// Message with details (e.g. reason of the rollback).
+	Message string `json:"message,omitempty"`
+	// Nodes contains the state of the rollout on every node.
+	Nodes []NodeRolloutStatus `json:"nodes,omitempty"`
+}
+
+// NodeRolloutStatus is the state of the rollout on a single node.
+type NodeRolloutStatus struct {
+	// Node name.
+	Node string `json:"node"`
+	// Wave in which the node is updated (0 for canary nodes).
+	Wave uint32 `json:"wave"`
+	// State of the rollout on the node.
+	State string `json:"state"`
+	// Error returned by the node (for Failed and RevertFailed states).
+	Error string `json:"error,omitempty"`
+	// PriorConfig is a JSON-encoded DataRequest with values of the rolled-out
+	// configuration items on the node before the rollout (restored on rollback).
+	PriorConfig string `json:"priorConfig,omitempty"`
+}
+
+// ExternalConfigRolloutList is a list of ExternalConfigRollout resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExternalConfigRolloutList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+
+	Items []ExternalConfigRollout `json:"items"`
+}
+
+// ClusterNetworkPolicy is a cluster-wide network policy applied ahead of the namespace-scoped
+// Kubernetes network policies. Policies are organized into tiers, evaluated in the ascending
+// order of the tier number, and within a tier in the ascending order of the priority.
+// Rules of a cluster policy may explicitly allow, deny or pass traffic - the first matching
+// Allow/Deny rule decides, Pass skips the remaining cluster policies of the same tier and
+// continues the evaluation with the next tier (and eventually with the namespace policies).
+// +genclient
+// +genclient:noStatus
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterNetworkPolicy struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the specification for the cluster network policy.
+	Spec ClusterNetworkPolicySpec `json:"spec"`
+}
+
+// ClusterNetworkPolicySpec is the spec for cluster network policy resource.
+type ClusterNetworkPolicySpec struct {
+	// Tier of the policy, lower tiers are evaluated first.
+	Tier uint32 `json:"tier,omitempty"`
+
+	// Priority of the policy within the tier, lower values are evaluated first.
+	Priority uint32 `json:"priority,omitempty"`
+
+	// NamespaceSelector selects namespaces of the pods to which the policy applies.
+	// If not set, pods from all namespaces are selected.
+	NamespaceSelector *meta_v1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// PodSelector selects pods (from the selected namespaces) to which the policy applies.
+	// If not set, all pods from the selected namespaces are selected.
+	PodSelector *meta_v1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Ingress is a list of rules applied to the traffic entering the selected pods.
+	Ingress []ClusterNetworkPolicyRule `json:"ingress,omitempty"`
+
+	// Egress is a list of rules applied to the traffic leaving the selected pods.
+	Egress []ClusterNetworkPolicyRule `json:"egress,omitempty"`
+}
+
+// Actions of cluster network policy rules.
+const (
+	// ClusterPolicyAllow allows the matching traffic, regardless of the policies evaluated later.
+	ClusterPolicyAllow = "Allow"
+	// ClusterPolicyDeny denies the matching traffic, regardless of the policies evaluated later.
+	ClusterPolicyDeny = "Deny"
+	// ClusterPolicyPass delegates the decision to the next tier (or to the namespace policies).
+	ClusterPolicyPass = "Pass"
+)
+
+// ClusterNetworkPolicyRule is a single rule of a cluster network policy.
+type ClusterNetworkPolicyRule struct {
+	// Action is one of: Allow, Deny, Pass.
+	Action string `json:"action"`
+
+	// Ports restricts the rule to the given L4 ports. If empty, the rule matches all ports.
+	Ports []ClusterNetworkPolicyPort `json:"ports,omitempty"`
+
+	// Peers restricts the rule to the given remote peers. If empty, the rule matches
+	// all sources (ingress) / destinations (egress).
+	Peers []ClusterNetworkPolicyPeer `json:"peers,omitempty"`
+}
+
+// ClusterNetworkPolicyPort describes L4 port (range) matched by a rule.
+type ClusterNetworkPolicyPort struct {
+	// Protocol is one of: TCP (default), UDP, SCTP.
+	Protocol string `json:"protocol,omitempty"`
+
+	// Port number, 0 matches all ports of the protocol.
+	Port int32 `json:"port,omitempty"`
+
+	// EndPort, if set, turns the rule into a port range <Port, EndPort>.
+	EndPort int32 `json:"endPort,omitempty"`
+}
+
+// ClusterNetworkPolicyPeer describes a remote peer matched by a rule.
+// Either selectors or IPBlock should be set.
+type ClusterNetworkPolicyPeer struct {
+	// NamespaceSelector selects namespaces of the peer pods (all namespaces if not set).
+	NamespaceSelector *meta_v1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// PodSelector selects the peer pods inside the selected namespaces (all pods if not set).
+	PodSelector *meta_v1.LabelSelector `json:"podSelector,omitempty"`
+
+	// IPBlock selects a range of IP addresses.
+	IPBlock *ClusterNetworkPolicyIPBlock `json:"ipBlock,omitempty"`
+}
+
+// ClusterNetworkPolicyIPBlock describes a range of IP addresses.
+type ClusterNetworkPolicyIPBlock struct {
+	// CIDR is the matched IP network.
+	CIDR string `json:"cidr"`
+
+	// Except is a list of sub-networks excluded from the CIDR.
+	Except []string `json:"except,omitempty"`
+}
+
+// ClusterNetworkPolicyList is a list of ClusterNetworkPolicy resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterNetworkPolicyList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+
+	Items []ClusterNetworkPolicy `json:"items"`
+}
@@ -24,8 +24,10 @@ import (
 
 type ContivppV1Interface interface {
 	RESTClient() rest.Interface
+	ClusterNetworkPoliciesGetter
 	CustomConfigurationsGetter
 	CustomNetworksGetter
+	ExternalConfigRolloutsGetter
 	ExternalInterfacesGetter
 	ServiceFunctionChainsGetter
 }
@@ -35,6 +37,10 @@ type ContivppV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *ContivppV1Client) ClusterNetworkPolicies() ClusterNetworkPolicyInterface {
+	return newClusterNetworkPolicies(c)
+}
+
 func (c *ContivppV1Client) CustomConfigurations(namespace string) CustomConfigurationInterface {
 	return newCustomConfigurations(c, namespace)
 }
@@ -43,6 +49,10 @@ func (c *ContivppV1Client) CustomNetworks(namespace string) CustomNetworkInterfa
 	return newCustomNetworks(c, namespace)
 }
 
+func (c *ContivppV1Client) ExternalConfigRollouts(namespace string) ExternalConfigRolloutInterface {
+	return newExternalConfigRollouts(c, namespace)
+}
+
 func (c *ContivppV1Client) ExternalInterfaces(namespace string) ExternalInterfaceInterface {
 	return newExternalInterfaces(c, namespace)
 }
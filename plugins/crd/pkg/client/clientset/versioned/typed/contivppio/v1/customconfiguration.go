@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -35,14 +36,14 @@ type CustomConfigurationsGetter interface {
 
 // CustomConfigurationInterface has methods to work with CustomConfiguration resources.
 type CustomConfigurationInterface interface {
-	Create(*v1.CustomConfiguration) (*v1.CustomConfiguration, error)
-	Update(*v1.CustomConfiguration) (*v1.CustomConfiguration, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.CustomConfiguration, error)
-	List(opts metav1.ListOptions) (*v1.CustomConfigurationList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CustomConfiguration, err error)
+	Create(ctx context.Context, customConfiguration *v1.CustomConfiguration, opts metav1.CreateOptions) (*v1.CustomConfiguration, error)
+	Update(ctx context.Context, customConfiguration *v1.CustomConfiguration, opts metav1.UpdateOptions) (*v1.CustomConfiguration, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CustomConfiguration, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CustomConfigurationList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CustomConfiguration, err error)
 	CustomConfigurationExpansion
 }
 
@@ -61,20 +62,20 @@ func newCustomConfigurations(c *ContivppV1Client, namespace string) *customConfi
 }
 
 // Get takes name of the customConfiguration, and returns the corresponding customConfiguration object, and an error if there is any.
-func (c *customConfigurations) Get(name string, options metav1.GetOptions) (result *v1.CustomConfiguration, err error) {
+func (c *customConfigurations) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CustomConfiguration, err error) {
 	result = &v1.CustomConfiguration{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("customconfigurations").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of CustomConfigurations that match those selectors.
-func (c *customConfigurations) List(opts metav1.ListOptions) (result *v1.CustomConfigurationList, err error) {
+func (c *customConfigurations) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CustomConfigurationList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -85,13 +86,13 @@ func (c *customConfigurations) List(opts metav1.ListOptions) (result *v1.CustomC
 		Resource("customconfigurations").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested customConfigurations.
-func (c *customConfigurations) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *customConfigurations) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -102,71 +103,74 @@ func (c *customConfigurations) Watch(opts metav1.ListOptions) (watch.Interface,
 		Resource("customconfigurations").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a customConfiguration and creates it.  Returns the server's representation of the customConfiguration, and an error, if there is any.
-func (c *customConfigurations) Create(customConfiguration *v1.CustomConfiguration) (result *v1.CustomConfiguration, err error) {
+func (c *customConfigurations) Create(ctx context.Context, customConfiguration *v1.CustomConfiguration, opts metav1.CreateOptions) (result *v1.CustomConfiguration, err error) {
 	result = &v1.CustomConfiguration{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("customconfigurations").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(customConfiguration).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a customConfiguration and updates it. Returns the server's representation of the customConfiguration, and an error, if there is any.
-func (c *customConfigurations) Update(customConfiguration *v1.CustomConfiguration) (result *v1.CustomConfiguration, err error) {
+func (c *customConfigurations) Update(ctx context.Context, customConfiguration *v1.CustomConfiguration, opts metav1.UpdateOptions) (result *v1.CustomConfiguration, err error) {
 	result = &v1.CustomConfiguration{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("customconfigurations").
 		Name(customConfiguration.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(customConfiguration).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the customConfiguration and deletes it. Returns an error if one occurs.
-func (c *customConfigurations) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *customConfigurations) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("customconfigurations").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *customConfigurations) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *customConfigurations) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("customconfigurations").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched customConfiguration.
-func (c *customConfigurations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CustomConfiguration, err error) {
+func (c *customConfigurations) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CustomConfiguration, err error) {
 	result = &v1.CustomConfiguration{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("customconfigurations").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -35,14 +36,14 @@ type CustomNetworksGetter interface {
 
 // CustomNetworkInterface has methods to work with CustomNetwork resources.
 type CustomNetworkInterface interface {
-	Create(*v1.CustomNetwork) (*v1.CustomNetwork, error)
-	Update(*v1.CustomNetwork) (*v1.CustomNetwork, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.CustomNetwork, error)
-	List(opts metav1.ListOptions) (*v1.CustomNetworkList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CustomNetwork, err error)
+	Create(ctx context.Context, customNetwork *v1.CustomNetwork, opts metav1.CreateOptions) (*v1.CustomNetwork, error)
+	Update(ctx context.Context, customNetwork *v1.CustomNetwork, opts metav1.UpdateOptions) (*v1.CustomNetwork, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CustomNetwork, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CustomNetworkList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CustomNetwork, err error)
 	CustomNetworkExpansion
 }
 
@@ -61,20 +62,20 @@ func newCustomNetworks(c *ContivppV1Client, namespace string) *customNetworks {
 }
 
 // Get takes name of the customNetwork, and returns the corresponding customNetwork object, and an error if there is any.
-func (c *customNetworks) Get(name string, options metav1.GetOptions) (result *v1.CustomNetwork, err error) {
+func (c *customNetworks) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CustomNetwork, err error) {
 	result = &v1.CustomNetwork{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("customnetworks").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of CustomNetworks that match those selectors.
-func (c *customNetworks) List(opts metav1.ListOptions) (result *v1.CustomNetworkList, err error) {
+func (c *customNetworks) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CustomNetworkList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -85,13 +86,13 @@ func (c *customNetworks) List(opts metav1.ListOptions) (result *v1.CustomNetwork
 		Resource("customnetworks").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested customNetworks.
-func (c *customNetworks) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *customNetworks) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -102,71 +103,74 @@ func (c *customNetworks) Watch(opts metav1.ListOptions) (watch.Interface, error)
 		Resource("customnetworks").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a customNetwork and creates it.  Returns the server's representation of the customNetwork, and an error, if there is any.
-func (c *customNetworks) Create(customNetwork *v1.CustomNetwork) (result *v1.CustomNetwork, err error) {
+func (c *customNetworks) Create(ctx context.Context, customNetwork *v1.CustomNetwork, opts metav1.CreateOptions) (result *v1.CustomNetwork, err error) {
 	result = &v1.CustomNetwork{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("customnetworks").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(customNetwork).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a customNetwork and updates it. Returns the server's representation of the customNetwork, and an error, if there is any.
-func (c *customNetworks) Update(customNetwork *v1.CustomNetwork) (result *v1.CustomNetwork, err error) {
+func (c *customNetworks) Update(ctx context.Context, customNetwork *v1.CustomNetwork, opts metav1.UpdateOptions) (result *v1.CustomNetwork, err error) {
 	result = &v1.CustomNetwork{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("customnetworks").
 		Name(customNetwork.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(customNetwork).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the customNetwork and deletes it. Returns an error if one occurs.
-func (c *customNetworks) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *customNetworks) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("customnetworks").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *customNetworks) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *customNetworks) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("customnetworks").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched customNetwork.
-func (c *customNetworks) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CustomNetwork, err error) {
+func (c *customNetworks) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CustomNetwork, err error) {
 	result = &v1.CustomNetwork{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("customnetworks").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -35,14 +36,14 @@ type ExternalInterfacesGetter interface {
 
 // ExternalInterfaceInterface has methods to work with ExternalInterface resources.
 type ExternalInterfaceInterface interface {
-	Create(*v1.ExternalInterface) (*v1.ExternalInterface, error)
-	Update(*v1.ExternalInterface) (*v1.ExternalInterface, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.ExternalInterface, error)
-	List(opts metav1.ListOptions) (*v1.ExternalInterfaceList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ExternalInterface, err error)
+	Create(ctx context.Context, externalInterface *v1.ExternalInterface, opts metav1.CreateOptions) (*v1.ExternalInterface, error)
+	Update(ctx context.Context, externalInterface *v1.ExternalInterface, opts metav1.UpdateOptions) (*v1.ExternalInterface, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ExternalInterface, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ExternalInterfaceList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ExternalInterface, err error)
 	ExternalInterfaceExpansion
 }
 
@@ -61,20 +62,20 @@ func newExternalInterfaces(c *ContivppV1Client, namespace string) *externalInter
 }
 
 // Get takes name of the externalInterface, and returns the corresponding externalInterface object, and an error if there is any.
-func (c *externalInterfaces) Get(name string, options metav1.GetOptions) (result *v1.ExternalInterface, err error) {
+func (c *externalInterfaces) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ExternalInterface, err error) {
 	result = &v1.ExternalInterface{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("externalinterfaces").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of ExternalInterfaces that match those selectors.
-func (c *externalInterfaces) List(opts metav1.ListOptions) (result *v1.ExternalInterfaceList, err error) {
+func (c *externalInterfaces) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ExternalInterfaceList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -85,13 +86,13 @@ func (c *externalInterfaces) List(opts metav1.ListOptions) (result *v1.ExternalI
 		Resource("externalinterfaces").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested externalInterfaces.
-func (c *externalInterfaces) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *externalInterfaces) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -102,71 +103,74 @@ func (c *externalInterfaces) Watch(opts metav1.ListOptions) (watch.Interface, er
 		Resource("externalinterfaces").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a externalInterface and creates it.  Returns the server's representation of the externalInterface, and an error, if there is any.
-func (c *externalInterfaces) Create(externalInterface *v1.ExternalInterface) (result *v1.ExternalInterface, err error) {
+func (c *externalInterfaces) Create(ctx context.Context, externalInterface *v1.ExternalInterface, opts metav1.CreateOptions) (result *v1.ExternalInterface, err error) {
 	result = &v1.ExternalInterface{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("externalinterfaces").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(externalInterface).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a externalInterface and updates it. Returns the server's representation of the externalInterface, and an error, if there is any.
-func (c *externalInterfaces) Update(externalInterface *v1.ExternalInterface) (result *v1.ExternalInterface, err error) {
+func (c *externalInterfaces) Update(ctx context.Context, externalInterface *v1.ExternalInterface, opts metav1.UpdateOptions) (result *v1.ExternalInterface, err error) {
 	result = &v1.ExternalInterface{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("externalinterfaces").
 		Name(externalInterface.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(externalInterface).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the externalInterface and deletes it. Returns an error if one occurs.
-func (c *externalInterfaces) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *externalInterfaces) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("externalinterfaces").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *externalInterfaces) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *externalInterfaces) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("externalinterfaces").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched externalInterface.
-func (c *externalInterfaces) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ExternalInterface, err error) {
+func (c *externalInterfaces) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ExternalInterface, err error) {
 	result = &v1.ExternalInterface{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("externalinterfaces").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
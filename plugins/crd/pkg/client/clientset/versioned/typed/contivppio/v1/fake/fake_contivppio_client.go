@@ -26,6 +26,10 @@ type FakeContivppV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeContivppV1) ClusterNetworkPolicies() v1.ClusterNetworkPolicyInterface {
+	return &FakeClusterNetworkPolicies{c}
+}
+
 func (c *FakeContivppV1) CustomConfigurations(namespace string) v1.CustomConfigurationInterface {
 	return &FakeCustomConfigurations{c, namespace}
 }
@@ -34,6 +38,10 @@ func (c *FakeContivppV1) CustomNetworks(namespace string) v1.CustomNetworkInterf
 	return &FakeCustomNetworks{c, namespace}
 }
 
+func (c *FakeContivppV1) ExternalConfigRollouts(namespace string) v1.ExternalConfigRolloutInterface {
+	return &FakeExternalConfigRollouts{c, namespace}
+}
+
 func (c *FakeContivppV1) ExternalInterfaces(namespace string) v1.ExternalInterfaceInterface {
 	return &FakeExternalInterfaces{c, namespace}
 }
@@ -17,6 +17,8 @@
 package fake
 
 import (
+	"context"
+
 	contivppiov1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -37,7 +39,7 @@ var customconfigurationsResource = schema.GroupVersionResource{Group: "contivpp.
 var customconfigurationsKind = schema.GroupVersionKind{Group: "contivpp.io", Version: "v1", Kind: "CustomConfiguration"}
 
 // Get takes name of the customConfiguration, and returns the corresponding customConfiguration object, and an error if there is any.
-func (c *FakeCustomConfigurations) Get(name string, options v1.GetOptions) (result *contivppiov1.CustomConfiguration, err error) {
+func (c *FakeCustomConfigurations) Get(ctx context.Context, name string, options v1.GetOptions) (result *contivppiov1.CustomConfiguration, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(customconfigurationsResource, c.ns, name), &contivppiov1.CustomConfiguration{})
 
@@ -48,7 +50,7 @@ func (c *FakeCustomConfigurations) Get(name string, options v1.GetOptions) (resu
 }
 
 // List takes label and field selectors, and returns the list of CustomConfigurations that match those selectors.
-func (c *FakeCustomConfigurations) List(opts v1.ListOptions) (result *contivppiov1.CustomConfigurationList, err error) {
+func (c *FakeCustomConfigurations) List(ctx context.Context, opts v1.ListOptions) (result *contivppiov1.CustomConfigurationList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(customconfigurationsResource, customconfigurationsKind, c.ns, opts), &contivppiov1.CustomConfigurationList{})
 
@@ -70,14 +72,14 @@ func (c *FakeCustomConfigurations) List(opts v1.ListOptions) (result *contivppio
 }
 
 // Watch returns a watch.Interface that watches the requested customConfigurations.
-func (c *FakeCustomConfigurations) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeCustomConfigurations) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(customconfigurationsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a customConfiguration and creates it.  Returns the server's representation of the customConfiguration, and an error, if there is any.
-func (c *FakeCustomConfigurations) Create(customConfiguration *contivppiov1.CustomConfiguration) (result *contivppiov1.CustomConfiguration, err error) {
+func (c *FakeCustomConfigurations) Create(ctx context.Context, customConfiguration *contivppiov1.CustomConfiguration, opts v1.CreateOptions) (result *contivppiov1.CustomConfiguration, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(customconfigurationsResource, c.ns, customConfiguration), &contivppiov1.CustomConfiguration{})
 
@@ -88,7 +90,7 @@ func (c *FakeCustomConfigurations) Create(customConfiguration *contivppiov1.Cust
 }
 
 // Update takes the representation of a customConfiguration and updates it. Returns the server's representation of the customConfiguration, and an error, if there is any.
-func (c *FakeCustomConfigurations) Update(customConfiguration *contivppiov1.CustomConfiguration) (result *contivppiov1.CustomConfiguration, err error) {
+func (c *FakeCustomConfigurations) Update(ctx context.Context, customConfiguration *contivppiov1.CustomConfiguration, opts v1.UpdateOptions) (result *contivppiov1.CustomConfiguration, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(customconfigurationsResource, c.ns, customConfiguration), &contivppiov1.CustomConfiguration{})
 
@@ -99,7 +101,7 @@ func (c *FakeCustomConfigurations) Update(customConfiguration *contivppiov1.Cust
 }
 
 // Delete takes name of the customConfiguration and deletes it. Returns an error if one occurs.
-func (c *FakeCustomConfigurations) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeCustomConfigurations) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(customconfigurationsResource, c.ns, name), &contivppiov1.CustomConfiguration{})
 
@@ -107,15 +109,15 @@ func (c *FakeCustomConfigurations) Delete(name string, options *v1.DeleteOptions
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeCustomConfigurations) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(customconfigurationsResource, c.ns, listOptions)
+func (c *FakeCustomConfigurations) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(customconfigurationsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &contivppiov1.CustomConfigurationList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched customConfiguration.
-func (c *FakeCustomConfigurations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *contivppiov1.CustomConfiguration, err error) {
+func (c *FakeCustomConfigurations) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *contivppiov1.CustomConfiguration, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(customconfigurationsResource, c.ns, name, pt, data, subresources...), &contivppiov1.CustomConfiguration{})
 
@@ -17,6 +17,8 @@
 package fake
 
 import (
+	"context"
+
 	contivppiov1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -37,7 +39,7 @@ var customnetworksResource = schema.GroupVersionResource{Group: "contivpp.io", V
 var customnetworksKind = schema.GroupVersionKind{Group: "contivpp.io", Version: "v1", Kind: "CustomNetwork"}
 
 // Get takes name of the customNetwork, and returns the corresponding customNetwork object, and an error if there is any.
-func (c *FakeCustomNetworks) Get(name string, options v1.GetOptions) (result *contivppiov1.CustomNetwork, err error) {
+func (c *FakeCustomNetworks) Get(ctx context.Context, name string, options v1.GetOptions) (result *contivppiov1.CustomNetwork, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(customnetworksResource, c.ns, name), &contivppiov1.CustomNetwork{})
 
@@ -48,7 +50,7 @@ func (c *FakeCustomNetworks) Get(name string, options v1.GetOptions) (result *co
 }
 
 // List takes label and field selectors, and returns the list of CustomNetworks that match those selectors.
-func (c *FakeCustomNetworks) List(opts v1.ListOptions) (result *contivppiov1.CustomNetworkList, err error) {
+func (c *FakeCustomNetworks) List(ctx context.Context, opts v1.ListOptions) (result *contivppiov1.CustomNetworkList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(customnetworksResource, customnetworksKind, c.ns, opts), &contivppiov1.CustomNetworkList{})
 
@@ -70,14 +72,14 @@ func (c *FakeCustomNetworks) List(opts v1.ListOptions) (result *contivppiov1.Cus
 }
 
 // Watch returns a watch.Interface that watches the requested customNetworks.
-func (c *FakeCustomNetworks) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeCustomNetworks) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(customnetworksResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a customNetwork and creates it.  Returns the server's representation of the customNetwork, and an error, if there is any.
-func (c *FakeCustomNetworks) Create(customNetwork *contivppiov1.CustomNetwork) (result *contivppiov1.CustomNetwork, err error) {
+func (c *FakeCustomNetworks) Create(ctx context.Context, customNetwork *contivppiov1.CustomNetwork, opts v1.CreateOptions) (result *contivppiov1.CustomNetwork, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(customnetworksResource, c.ns, customNetwork), &contivppiov1.CustomNetwork{})
 
@@ -88,7 +90,7 @@ func (c *FakeCustomNetworks) Create(customNetwork *contivppiov1.CustomNetwork) (
 }
 
 // Update takes the representation of a customNetwork and updates it. Returns the server's representation of the customNetwork, and an error, if there is any.
-func (c *FakeCustomNetworks) Update(customNetwork *contivppiov1.CustomNetwork) (result *contivppiov1.CustomNetwork, err error) {
+func (c *FakeCustomNetworks) Update(ctx context.Context, customNetwork *contivppiov1.CustomNetwork, opts v1.UpdateOptions) (result *contivppiov1.CustomNetwork, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(customnetworksResource, c.ns, customNetwork), &contivppiov1.CustomNetwork{})
 
@@ -99,7 +101,7 @@ func (c *FakeCustomNetworks) Update(customNetwork *contivppiov1.CustomNetwork) (
 }
 
 // Delete takes name of the customNetwork and deletes it. Returns an error if one occurs.
-func (c *FakeCustomNetworks) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeCustomNetworks) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(customnetworksResource, c.ns, name), &contivppiov1.CustomNetwork{})
 
@@ -107,15 +109,15 @@ func (c *FakeCustomNetworks) Delete(name string, options *v1.DeleteOptions) erro
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeCustomNetworks) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(customnetworksResource, c.ns, listOptions)
+func (c *FakeCustomNetworks) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(customnetworksResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &contivppiov1.CustomNetworkList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched customNetwork.
-func (c *FakeCustomNetworks) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *contivppiov1.CustomNetwork, err error) {
+func (c *FakeCustomNetworks) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *contivppiov1.CustomNetwork, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(customnetworksResource, c.ns, name, pt, data, subresources...), &contivppiov1.CustomNetwork{})
 
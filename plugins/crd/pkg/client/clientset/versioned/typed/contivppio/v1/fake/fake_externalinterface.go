@@ -17,6 +17,8 @@
 package fake
 
 import (
+	"context"
+
 	contivppiov1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -37,7 +39,7 @@ var externalinterfacesResource = schema.GroupVersionResource{Group: "contivpp.io
 var externalinterfacesKind = schema.GroupVersionKind{Group: "contivpp.io", Version: "v1", Kind: "ExternalInterface"}
 
 // Get takes name of the externalInterface, and returns the corresponding externalInterface object, and an error if there is any.
-func (c *FakeExternalInterfaces) Get(name string, options v1.GetOptions) (result *contivppiov1.ExternalInterface, err error) {
+func (c *FakeExternalInterfaces) Get(ctx context.Context, name string, options v1.GetOptions) (result *contivppiov1.ExternalInterface, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(externalinterfacesResource, c.ns, name), &contivppiov1.ExternalInterface{})
 
@@ -48,7 +50,7 @@ func (c *FakeExternalInterfaces) Get(name string, options v1.GetOptions) (result
 }
 
 // List takes label and field selectors, and returns the list of ExternalInterfaces that match those selectors.
-func (c *FakeExternalInterfaces) List(opts v1.ListOptions) (result *contivppiov1.ExternalInterfaceList, err error) {
+func (c *FakeExternalInterfaces) List(ctx context.Context, opts v1.ListOptions) (result *contivppiov1.ExternalInterfaceList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(externalinterfacesResource, externalinterfacesKind, c.ns, opts), &contivppiov1.ExternalInterfaceList{})
 
@@ -70,14 +72,14 @@ func (c *FakeExternalInterfaces) List(opts v1.ListOptions) (result *contivppiov1
 }
 
 // Watch returns a watch.Interface that watches the requested externalInterfaces.
-func (c *FakeExternalInterfaces) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeExternalInterfaces) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(externalinterfacesResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a externalInterface and creates it.  Returns the server's representation of the externalInterface, and an error, if there is any.
-func (c *FakeExternalInterfaces) Create(externalInterface *contivppiov1.ExternalInterface) (result *contivppiov1.ExternalInterface, err error) {
+func (c *FakeExternalInterfaces) Create(ctx context.Context, externalInterface *contivppiov1.ExternalInterface, opts v1.CreateOptions) (result *contivppiov1.ExternalInterface, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(externalinterfacesResource, c.ns, externalInterface), &contivppiov1.ExternalInterface{})
 
@@ -88,7 +90,7 @@ func (c *FakeExternalInterfaces) Create(externalInterface *contivppiov1.External
 }
 
 // Update takes the representation of a externalInterface and updates it. Returns the server's representation of the externalInterface, and an error, if there is any.
-func (c *FakeExternalInterfaces) Update(externalInterface *contivppiov1.ExternalInterface) (result *contivppiov1.ExternalInterface, err error) {
+func (c *FakeExternalInterfaces) Update(ctx context.Context, externalInterface *contivppiov1.ExternalInterface, opts v1.UpdateOptions) (result *contivppiov1.ExternalInterface, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(externalinterfacesResource, c.ns, externalInterface), &contivppiov1.ExternalInterface{})
 
@@ -99,7 +101,7 @@ func (c *FakeExternalInterfaces) Update(externalInterface *contivppiov1.External
 }
 
 // Delete takes name of the externalInterface and deletes it. Returns an error if one occurs.
-func (c *FakeExternalInterfaces) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeExternalInterfaces) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(externalinterfacesResource, c.ns, name), &contivppiov1.ExternalInterface{})
 
@@ -107,15 +109,15 @@ func (c *FakeExternalInterfaces) Delete(name string, options *v1.DeleteOptions)
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeExternalInterfaces) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(externalinterfacesResource, c.ns, listOptions)
+func (c *FakeExternalInterfaces) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(externalinterfacesResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &contivppiov1.ExternalInterfaceList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched externalInterface.
-func (c *FakeExternalInterfaces) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *contivppiov1.ExternalInterface, err error) {
+func (c *FakeExternalInterfaces) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *contivppiov1.ExternalInterface, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(externalinterfacesResource, c.ns, name, pt, data, subresources...), &contivppiov1.ExternalInterface{})
 
@@ -17,6 +17,8 @@
 package fake
 
 import (
+	"context"
+
 	contivppiov1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -37,7 +39,7 @@ var servicefunctionchainsResource = schema.GroupVersionResource{Group: "contivpp
 var servicefunctionchainsKind = schema.GroupVersionKind{Group: "contivpp.io", Version: "v1", Kind: "ServiceFunctionChain"}
 
 // Get takes name of the serviceFunctionChain, and returns the corresponding serviceFunctionChain object, and an error if there is any.
-func (c *FakeServiceFunctionChains) Get(name string, options v1.GetOptions) (result *contivppiov1.ServiceFunctionChain, err error) {
+func (c *FakeServiceFunctionChains) Get(ctx context.Context, name string, options v1.GetOptions) (result *contivppiov1.ServiceFunctionChain, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(servicefunctionchainsResource, c.ns, name), &contivppiov1.ServiceFunctionChain{})
 
@@ -48,7 +50,7 @@ func (c *FakeServiceFunctionChains) Get(name string, options v1.GetOptions) (res
 }
 
 // List takes label and field selectors, and returns the list of ServiceFunctionChains that match those selectors.
-func (c *FakeServiceFunctionChains) List(opts v1.ListOptions) (result *contivppiov1.ServiceFunctionChainList, err error) {
+func (c *FakeServiceFunctionChains) List(ctx context.Context, opts v1.ListOptions) (result *contivppiov1.ServiceFunctionChainList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(servicefunctionchainsResource, servicefunctionchainsKind, c.ns, opts), &contivppiov1.ServiceFunctionChainList{})
 
@@ -70,14 +72,14 @@ func (c *FakeServiceFunctionChains) List(opts v1.ListOptions) (result *contivppi
 }
 
 // Watch returns a watch.Interface that watches the requested serviceFunctionChains.
-func (c *FakeServiceFunctionChains) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeServiceFunctionChains) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(servicefunctionchainsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a serviceFunctionChain and creates it.  Returns the server's representation of the serviceFunctionChain, and an error, if there is any.
-func (c *FakeServiceFunctionChains) Create(serviceFunctionChain *contivppiov1.ServiceFunctionChain) (result *contivppiov1.ServiceFunctionChain, err error) {
+func (c *FakeServiceFunctionChains) Create(ctx context.Context, serviceFunctionChain *contivppiov1.ServiceFunctionChain, opts v1.CreateOptions) (result *contivppiov1.ServiceFunctionChain, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(servicefunctionchainsResource, c.ns, serviceFunctionChain), &contivppiov1.ServiceFunctionChain{})
 
@@ -88,7 +90,7 @@ func (c *FakeServiceFunctionChains) Create(serviceFunctionChain *contivppiov1.Se
 }
 
 // Update takes the representation of a serviceFunctionChain and updates it. Returns the server's representation of the serviceFunctionChain, and an error, if there is any.
-func (c *FakeServiceFunctionChains) Update(serviceFunctionChain *contivppiov1.ServiceFunctionChain) (result *contivppiov1.ServiceFunctionChain, err error) {
+func (c *FakeServiceFunctionChains) Update(ctx context.Context, serviceFunctionChain *contivppiov1.ServiceFunctionChain, opts v1.UpdateOptions) (result *contivppiov1.ServiceFunctionChain, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(servicefunctionchainsResource, c.ns, serviceFunctionChain), &contivppiov1.ServiceFunctionChain{})
 
@@ -99,7 +101,7 @@ func (c *FakeServiceFunctionChains) Update(serviceFunctionChain *contivppiov1.Se
 }
 
 // Delete takes name of the serviceFunctionChain and deletes it. Returns an error if one occurs.
-func (c *FakeServiceFunctionChains) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeServiceFunctionChains) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(servicefunctionchainsResource, c.ns, name), &contivppiov1.ServiceFunctionChain{})
 
@@ -107,15 +109,15 @@ func (c *FakeServiceFunctionChains) Delete(name string, options *v1.DeleteOption
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeServiceFunctionChains) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(servicefunctionchainsResource, c.ns, listOptions)
+func (c *FakeServiceFunctionChains) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(servicefunctionchainsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &contivppiov1.ServiceFunctionChainList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched serviceFunctionChain.
-func (c *FakeServiceFunctionChains) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *contivppiov1.ServiceFunctionChain, err error) {
+func (c *FakeServiceFunctionChains) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *contivppiov1.ServiceFunctionChain, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(servicefunctionchainsResource, c.ns, name, pt, data, subresources...), &contivppiov1.ServiceFunctionChain{})
 
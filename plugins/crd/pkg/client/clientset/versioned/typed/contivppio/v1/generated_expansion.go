@@ -16,10 +16,14 @@
 
 package v1
 
+type ClusterNetworkPolicyExpansion interface{}
+
 type CustomConfigurationExpansion interface{}
 
 type CustomNetworkExpansion interface{}
 
+type ExternalConfigRolloutExpansion interface{}
+
 type ExternalInterfaceExpansion interface{}
 
 type ServiceFunctionChainExpansion interface{}
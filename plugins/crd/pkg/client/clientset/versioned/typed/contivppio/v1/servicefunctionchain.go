@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -35,14 +36,14 @@ type ServiceFunctionChainsGetter interface {
 
 // ServiceFunctionChainInterface has methods to work with ServiceFunctionChain resources.
 type ServiceFunctionChainInterface interface {
-	Create(*v1.ServiceFunctionChain) (*v1.ServiceFunctionChain, error)
-	Update(*v1.ServiceFunctionChain) (*v1.ServiceFunctionChain, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.ServiceFunctionChain, error)
-	List(opts metav1.ListOptions) (*v1.ServiceFunctionChainList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ServiceFunctionChain, err error)
+	Create(ctx context.Context, serviceFunctionChain *v1.ServiceFunctionChain, opts metav1.CreateOptions) (*v1.ServiceFunctionChain, error)
+	Update(ctx context.Context, serviceFunctionChain *v1.ServiceFunctionChain, opts metav1.UpdateOptions) (*v1.ServiceFunctionChain, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ServiceFunctionChain, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ServiceFunctionChainList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ServiceFunctionChain, err error)
 	ServiceFunctionChainExpansion
 }
 
@@ -61,20 +62,20 @@ func newServiceFunctionChains(c *ContivppV1Client, namespace string) *serviceFun
 }
 
 // Get takes name of the serviceFunctionChain, and returns the corresponding serviceFunctionChain object, and an error if there is any.
-func (c *serviceFunctionChains) Get(name string, options metav1.GetOptions) (result *v1.ServiceFunctionChain, err error) {
+func (c *serviceFunctionChains) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ServiceFunctionChain, err error) {
 	result = &v1.ServiceFunctionChain{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("servicefunctionchains").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of ServiceFunctionChains that match those selectors.
-func (c *serviceFunctionChains) List(opts metav1.ListOptions) (result *v1.ServiceFunctionChainList, err error) {
+func (c *serviceFunctionChains) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ServiceFunctionChainList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -85,13 +86,13 @@ func (c *serviceFunctionChains) List(opts metav1.ListOptions) (result *v1.Servic
 		Resource("servicefunctionchains").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested serviceFunctionChains.
-func (c *serviceFunctionChains) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *serviceFunctionChains) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -102,71 +103,74 @@ func (c *serviceFunctionChains) Watch(opts metav1.ListOptions) (watch.Interface,
 		Resource("servicefunctionchains").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a serviceFunctionChain and creates it.  Returns the server's representation of the serviceFunctionChain, and an error, if there is any.
-func (c *serviceFunctionChains) Create(serviceFunctionChain *v1.ServiceFunctionChain) (result *v1.ServiceFunctionChain, err error) {
+func (c *serviceFunctionChains) Create(ctx context.Context, serviceFunctionChain *v1.ServiceFunctionChain, opts metav1.CreateOptions) (result *v1.ServiceFunctionChain, err error) {
 	result = &v1.ServiceFunctionChain{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("servicefunctionchains").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(serviceFunctionChain).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a serviceFunctionChain and updates it. Returns the server's representation of the serviceFunctionChain, and an error, if there is any.
-func (c *serviceFunctionChains) Update(serviceFunctionChain *v1.ServiceFunctionChain) (result *v1.ServiceFunctionChain, err error) {
+func (c *serviceFunctionChains) Update(ctx context.Context, serviceFunctionChain *v1.ServiceFunctionChain, opts metav1.UpdateOptions) (result *v1.ServiceFunctionChain, err error) {
 	result = &v1.ServiceFunctionChain{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("servicefunctionchains").
 		Name(serviceFunctionChain.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(serviceFunctionChain).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the serviceFunctionChain and deletes it. Returns an error if one occurs.
-func (c *serviceFunctionChains) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *serviceFunctionChains) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("servicefunctionchains").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *serviceFunctionChains) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *serviceFunctionChains) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("servicefunctionchains").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched serviceFunctionChain.
-func (c *serviceFunctionChains) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ServiceFunctionChain, err error) {
+func (c *serviceFunctionChains) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ServiceFunctionChain, err error) {
 	result = &v1.ServiceFunctionChain{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("servicefunctionchains").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
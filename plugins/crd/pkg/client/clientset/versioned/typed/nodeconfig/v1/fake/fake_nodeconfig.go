@@ -17,6 +17,8 @@
 package fake
 
 import (
+	"context"
+
 	nodeconfigv1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -37,7 +39,7 @@ var nodeconfigsResource = schema.GroupVersionResource{Group: "nodeconfig.contiv.
 var nodeconfigsKind = schema.GroupVersionKind{Group: "nodeconfig.contiv.vpp", Version: "v1", Kind: "NodeConfig"}
 
 // Get takes name of the nodeConfig, and returns the corresponding nodeConfig object, and an error if there is any.
-func (c *FakeNodeConfigs) Get(name string, options v1.GetOptions) (result *nodeconfigv1.NodeConfig, err error) {
+func (c *FakeNodeConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *nodeconfigv1.NodeConfig, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(nodeconfigsResource, c.ns, name), &nodeconfigv1.NodeConfig{})
 
@@ -48,7 +50,7 @@ func (c *FakeNodeConfigs) Get(name string, options v1.GetOptions) (result *nodec
 }
 
 // List takes label and field selectors, and returns the list of NodeConfigs that match those selectors.
-func (c *FakeNodeConfigs) List(opts v1.ListOptions) (result *nodeconfigv1.NodeConfigList, err error) {
+func (c *FakeNodeConfigs) List(ctx context.Context, opts v1.ListOptions) (result *nodeconfigv1.NodeConfigList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(nodeconfigsResource, nodeconfigsKind, c.ns, opts), &nodeconfigv1.NodeConfigList{})
 
@@ -70,14 +72,14 @@ func (c *FakeNodeConfigs) List(opts v1.ListOptions) (result *nodeconfigv1.NodeCo
 }
 
 // Watch returns a watch.Interface that watches the requested nodeConfigs.
-func (c *FakeNodeConfigs) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeNodeConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(nodeconfigsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a nodeConfig and creates it.  Returns the server's representation of the nodeConfig, and an error, if there is any.
-func (c *FakeNodeConfigs) Create(nodeConfig *nodeconfigv1.NodeConfig) (result *nodeconfigv1.NodeConfig, err error) {
+func (c *FakeNodeConfigs) Create(ctx context.Context, nodeConfig *nodeconfigv1.NodeConfig, opts v1.CreateOptions) (result *nodeconfigv1.NodeConfig, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(nodeconfigsResource, c.ns, nodeConfig), &nodeconfigv1.NodeConfig{})
 
@@ -88,7 +90,7 @@ func (c *FakeNodeConfigs) Create(nodeConfig *nodeconfigv1.NodeConfig) (result *n
 }
 
 // Update takes the representation of a nodeConfig and updates it. Returns the server's representation of the nodeConfig, and an error, if there is any.
-func (c *FakeNodeConfigs) Update(nodeConfig *nodeconfigv1.NodeConfig) (result *nodeconfigv1.NodeConfig, err error) {
+func (c *FakeNodeConfigs) Update(ctx context.Context, nodeConfig *nodeconfigv1.NodeConfig, opts v1.UpdateOptions) (result *nodeconfigv1.NodeConfig, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(nodeconfigsResource, c.ns, nodeConfig), &nodeconfigv1.NodeConfig{})
 
@@ -100,7 +102,7 @@ func (c *FakeNodeConfigs) Update(nodeConfig *nodeconfigv1.NodeConfig) (result *n
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-func (c *FakeNodeConfigs) UpdateStatus(nodeConfig *nodeconfigv1.NodeConfig) (*nodeconfigv1.NodeConfig, error) {
+func (c *FakeNodeConfigs) UpdateStatus(ctx context.Context, nodeConfig *nodeconfigv1.NodeConfig, opts v1.UpdateOptions) (*nodeconfigv1.NodeConfig, error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateSubresourceAction(nodeconfigsResource, "status", c.ns, nodeConfig), &nodeconfigv1.NodeConfig{})
 
@@ -111,7 +113,7 @@ func (c *FakeNodeConfigs) UpdateStatus(nodeConfig *nodeconfigv1.NodeConfig) (*no
 }
 
 // Delete takes name of the nodeConfig and deletes it. Returns an error if one occurs.
-func (c *FakeNodeConfigs) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeNodeConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(nodeconfigsResource, c.ns, name), &nodeconfigv1.NodeConfig{})
 
@@ -119,15 +121,15 @@ func (c *FakeNodeConfigs) Delete(name string, options *v1.DeleteOptions) error {
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeNodeConfigs) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(nodeconfigsResource, c.ns, listOptions)
+func (c *FakeNodeConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(nodeconfigsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &nodeconfigv1.NodeConfigList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched nodeConfig.
-func (c *FakeNodeConfigs) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *nodeconfigv1.NodeConfig, err error) {
+func (c *FakeNodeConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *nodeconfigv1.NodeConfig, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(nodeconfigsResource, c.ns, name, pt, data, subresources...), &nodeconfigv1.NodeConfig{})
 
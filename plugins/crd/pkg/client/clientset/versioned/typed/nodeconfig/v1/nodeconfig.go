@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
@@ -35,15 +36,15 @@ type NodeConfigsGetter interface {
 
 // NodeConfigInterface has methods to work with NodeConfig resources.
 type NodeConfigInterface interface {
-	Create(*v1.NodeConfig) (*v1.NodeConfig, error)
-	Update(*v1.NodeConfig) (*v1.NodeConfig, error)
-	UpdateStatus(*v1.NodeConfig) (*v1.NodeConfig, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.NodeConfig, error)
-	List(opts metav1.ListOptions) (*v1.NodeConfigList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.NodeConfig, err error)
+	Create(ctx context.Context, nodeConfig *v1.NodeConfig, opts metav1.CreateOptions) (*v1.NodeConfig, error)
+	Update(ctx context.Context, nodeConfig *v1.NodeConfig, opts metav1.UpdateOptions) (*v1.NodeConfig, error)
+	UpdateStatus(ctx context.Context, nodeConfig *v1.NodeConfig, opts metav1.UpdateOptions) (*v1.NodeConfig, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.NodeConfig, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.NodeConfigList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NodeConfig, err error)
 	NodeConfigExpansion
 }
 
@@ -62,20 +63,20 @@ func newNodeConfigs(c *NodeconfigV1Client, namespace string) *nodeConfigs {
 }
 
 // Get takes name of the nodeConfig, and returns the corresponding nodeConfig object, and an error if there is any.
-func (c *nodeConfigs) Get(name string, options metav1.GetOptions) (result *v1.NodeConfig, err error) {
+func (c *nodeConfigs) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.NodeConfig, err error) {
 	result = &v1.NodeConfig{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("nodeconfigs").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of NodeConfigs that match those selectors.
-func (c *nodeConfigs) List(opts metav1.ListOptions) (result *v1.NodeConfigList, err error) {
+func (c *nodeConfigs) List(ctx context.Context, opts metav1.ListOptions) (result *v1.NodeConfigList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -86,13 +87,13 @@ func (c *nodeConfigs) List(opts metav1.ListOptions) (result *v1.NodeConfigList,
 		Resource("nodeconfigs").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested nodeConfigs.
-func (c *nodeConfigs) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *nodeConfigs) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -103,87 +104,90 @@ func (c *nodeConfigs) Watch(opts metav1.ListOptions) (watch.Interface, error) {
 		Resource("nodeconfigs").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a nodeConfig and creates it.  Returns the server's representation of the nodeConfig, and an error, if there is any.
-func (c *nodeConfigs) Create(nodeConfig *v1.NodeConfig) (result *v1.NodeConfig, err error) {
+func (c *nodeConfigs) Create(ctx context.Context, nodeConfig *v1.NodeConfig, opts metav1.CreateOptions) (result *v1.NodeConfig, err error) {
 	result = &v1.NodeConfig{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("nodeconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(nodeConfig).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a nodeConfig and updates it. Returns the server's representation of the nodeConfig, and an error, if there is any.
-func (c *nodeConfigs) Update(nodeConfig *v1.NodeConfig) (result *v1.NodeConfig, err error) {
+func (c *nodeConfigs) Update(ctx context.Context, nodeConfig *v1.NodeConfig, opts metav1.UpdateOptions) (result *v1.NodeConfig, err error) {
 	result = &v1.NodeConfig{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("nodeconfigs").
 		Name(nodeConfig.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(nodeConfig).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-
-func (c *nodeConfigs) UpdateStatus(nodeConfig *v1.NodeConfig) (result *v1.NodeConfig, err error) {
+func (c *nodeConfigs) UpdateStatus(ctx context.Context, nodeConfig *v1.NodeConfig, opts metav1.UpdateOptions) (result *v1.NodeConfig, err error) {
 	result = &v1.NodeConfig{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("nodeconfigs").
 		Name(nodeConfig.Name).
 		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(nodeConfig).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the nodeConfig and deletes it. Returns an error if one occurs.
-func (c *nodeConfigs) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *nodeConfigs) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("nodeconfigs").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *nodeConfigs) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *nodeConfigs) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("nodeconfigs").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched nodeConfig.
-func (c *nodeConfigs) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.NodeConfig, err error) {
+func (c *nodeConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NodeConfig, err error) {
 	result = &v1.NodeConfig{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("nodeconfigs").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
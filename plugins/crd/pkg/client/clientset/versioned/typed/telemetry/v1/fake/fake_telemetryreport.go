@@ -17,6 +17,8 @@
 package fake
 
 import (
+	"context"
+
 	telemetryv1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/telemetry/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -37,7 +39,7 @@ var telemetryreportsResource = schema.GroupVersionResource{Group: "telemetry.con
 var telemetryreportsKind = schema.GroupVersionKind{Group: "telemetry.contiv.vpp", Version: "v1", Kind: "TelemetryReport"}
 
 // Get takes name of the telemetryReport, and returns the corresponding telemetryReport object, and an error if there is any.
-func (c *FakeTelemetryReports) Get(name string, options v1.GetOptions) (result *telemetryv1.TelemetryReport, err error) {
+func (c *FakeTelemetryReports) Get(ctx context.Context, name string, options v1.GetOptions) (result *telemetryv1.TelemetryReport, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(telemetryreportsResource, c.ns, name), &telemetryv1.TelemetryReport{})
 
@@ -48,7 +50,7 @@ func (c *FakeTelemetryReports) Get(name string, options v1.GetOptions) (result *
 }
 
 // List takes label and field selectors, and returns the list of TelemetryReports that match those selectors.
-func (c *FakeTelemetryReports) List(opts v1.ListOptions) (result *telemetryv1.TelemetryReportList, err error) {
+func (c *FakeTelemetryReports) List(ctx context.Context, opts v1.ListOptions) (result *telemetryv1.TelemetryReportList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(telemetryreportsResource, telemetryreportsKind, c.ns, opts), &telemetryv1.TelemetryReportList{})
 
@@ -70,14 +72,14 @@ func (c *FakeTelemetryReports) List(opts v1.ListOptions) (result *telemetryv1.Te
 }
 
 // Watch returns a watch.Interface that watches the requested telemetryReports.
-func (c *FakeTelemetryReports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeTelemetryReports) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(telemetryreportsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a telemetryReport and creates it.  Returns the server's representation of the telemetryReport, and an error, if there is any.
-func (c *FakeTelemetryReports) Create(telemetryReport *telemetryv1.TelemetryReport) (result *telemetryv1.TelemetryReport, err error) {
+func (c *FakeTelemetryReports) Create(ctx context.Context, telemetryReport *telemetryv1.TelemetryReport, opts v1.CreateOptions) (result *telemetryv1.TelemetryReport, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(telemetryreportsResource, c.ns, telemetryReport), &telemetryv1.TelemetryReport{})
 
@@ -88,7 +90,7 @@ func (c *FakeTelemetryReports) Create(telemetryReport *telemetryv1.TelemetryRepo
 }
 
 // Update takes the representation of a telemetryReport and updates it. Returns the server's representation of the telemetryReport, and an error, if there is any.
-func (c *FakeTelemetryReports) Update(telemetryReport *telemetryv1.TelemetryReport) (result *telemetryv1.TelemetryReport, err error) {
+func (c *FakeTelemetryReports) Update(ctx context.Context, telemetryReport *telemetryv1.TelemetryReport, opts v1.UpdateOptions) (result *telemetryv1.TelemetryReport, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(telemetryreportsResource, c.ns, telemetryReport), &telemetryv1.TelemetryReport{})
 
@@ -100,7 +102,7 @@ func (c *FakeTelemetryReports) Update(telemetryReport *telemetryv1.TelemetryRepo
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-func (c *FakeTelemetryReports) UpdateStatus(telemetryReport *telemetryv1.TelemetryReport) (*telemetryv1.TelemetryReport, error) {
+func (c *FakeTelemetryReports) UpdateStatus(ctx context.Context, telemetryReport *telemetryv1.TelemetryReport, opts v1.UpdateOptions) (*telemetryv1.TelemetryReport, error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateSubresourceAction(telemetryreportsResource, "status", c.ns, telemetryReport), &telemetryv1.TelemetryReport{})
 
@@ -111,7 +113,7 @@ func (c *FakeTelemetryReports) UpdateStatus(telemetryReport *telemetryv1.Telemet
 }
 
 // Delete takes name of the telemetryReport and deletes it. Returns an error if one occurs.
-func (c *FakeTelemetryReports) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeTelemetryReports) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(telemetryreportsResource, c.ns, name), &telemetryv1.TelemetryReport{})
 
@@ -119,15 +121,15 @@ func (c *FakeTelemetryReports) Delete(name string, options *v1.DeleteOptions) er
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeTelemetryReports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(telemetryreportsResource, c.ns, listOptions)
+func (c *FakeTelemetryReports) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(telemetryreportsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &telemetryv1.TelemetryReportList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched telemetryReport.
-func (c *FakeTelemetryReports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *telemetryv1.TelemetryReport, err error) {
+func (c *FakeTelemetryReports) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *telemetryv1.TelemetryReport, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(telemetryreportsResource, c.ns, name, pt, data, subresources...), &telemetryv1.TelemetryReport{})
 
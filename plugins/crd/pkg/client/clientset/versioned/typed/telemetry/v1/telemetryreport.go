@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/telemetry/v1"
@@ -35,15 +36,15 @@ type TelemetryReportsGetter interface {
 
 // TelemetryReportInterface has methods to work with TelemetryReport resources.
 type TelemetryReportInterface interface {
-	Create(*v1.TelemetryReport) (*v1.TelemetryReport, error)
-	Update(*v1.TelemetryReport) (*v1.TelemetryReport, error)
-	UpdateStatus(*v1.TelemetryReport) (*v1.TelemetryReport, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.TelemetryReport, error)
-	List(opts metav1.ListOptions) (*v1.TelemetryReportList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.TelemetryReport, err error)
+	Create(ctx context.Context, telemetryReport *v1.TelemetryReport, opts metav1.CreateOptions) (*v1.TelemetryReport, error)
+	Update(ctx context.Context, telemetryReport *v1.TelemetryReport, opts metav1.UpdateOptions) (*v1.TelemetryReport, error)
+	UpdateStatus(ctx context.Context, telemetryReport *v1.TelemetryReport, opts metav1.UpdateOptions) (*v1.TelemetryReport, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.TelemetryReport, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.TelemetryReportList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.TelemetryReport, err error)
 	TelemetryReportExpansion
 }
 
@@ -62,20 +63,20 @@ func newTelemetryReports(c *TelemetryV1Client, namespace string) *telemetryRepor
 }
 
 // Get takes name of the telemetryReport, and returns the corresponding telemetryReport object, and an error if there is any.
-func (c *telemetryReports) Get(name string, options metav1.GetOptions) (result *v1.TelemetryReport, err error) {
+func (c *telemetryReports) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.TelemetryReport, err error) {
 	result = &v1.TelemetryReport{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("telemetryreports").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of TelemetryReports that match those selectors.
-func (c *telemetryReports) List(opts metav1.ListOptions) (result *v1.TelemetryReportList, err error) {
+func (c *telemetryReports) List(ctx context.Context, opts metav1.ListOptions) (result *v1.TelemetryReportList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -86,13 +87,13 @@ func (c *telemetryReports) List(opts metav1.ListOptions) (result *v1.TelemetryRe
 		Resource("telemetryreports").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested telemetryReports.
-func (c *telemetryReports) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *telemetryReports) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -103,87 +104,90 @@ func (c *telemetryReports) Watch(opts metav1.ListOptions) (watch.Interface, erro
 		Resource("telemetryreports").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a telemetryReport and creates it.  Returns the server's representation of the telemetryReport, and an error, if there is any.
-func (c *telemetryReports) Create(telemetryReport *v1.TelemetryReport) (result *v1.TelemetryReport, err error) {
+func (c *telemetryReports) Create(ctx context.Context, telemetryReport *v1.TelemetryReport, opts metav1.CreateOptions) (result *v1.TelemetryReport, err error) {
 	result = &v1.TelemetryReport{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("telemetryreports").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(telemetryReport).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a telemetryReport and updates it. Returns the server's representation of the telemetryReport, and an error, if there is any.
-func (c *telemetryReports) Update(telemetryReport *v1.TelemetryReport) (result *v1.TelemetryReport, err error) {
+func (c *telemetryReports) Update(ctx context.Context, telemetryReport *v1.TelemetryReport, opts metav1.UpdateOptions) (result *v1.TelemetryReport, err error) {
 	result = &v1.TelemetryReport{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("telemetryreports").
 		Name(telemetryReport.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(telemetryReport).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-
-func (c *telemetryReports) UpdateStatus(telemetryReport *v1.TelemetryReport) (result *v1.TelemetryReport, err error) {
+func (c *telemetryReports) UpdateStatus(ctx context.Context, telemetryReport *v1.TelemetryReport, opts metav1.UpdateOptions) (result *v1.TelemetryReport, err error) {
 	result = &v1.TelemetryReport{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("telemetryreports").
 		Name(telemetryReport.Name).
 		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(telemetryReport).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the telemetryReport and deletes it. Returns an error if one occurs.
-func (c *telemetryReports) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *telemetryReports) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("telemetryreports").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *telemetryReports) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *telemetryReports) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("telemetryreports").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched telemetryReport.
-func (c *telemetryReports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.TelemetryReport, err error) {
+func (c *telemetryReports) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.TelemetryReport, err error) {
 	result = &v1.TelemetryReport{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("telemetryreports").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	time "time"
 
 	contivppiov1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -59,13 +60,13 @@ func NewFilteredCustomNetworkInformer(client versioned.Interface, namespace stri
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.ContivppV1().CustomNetworks(namespace).List(options)
+				return client.ContivppV1().CustomNetworks(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.ContivppV1().CustomNetworks(namespace).Watch(options)
+				return client.ContivppV1().CustomNetworks(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&contivppiov1.CustomNetwork{},
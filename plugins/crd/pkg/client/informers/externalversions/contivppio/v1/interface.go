@@ -22,10 +22,14 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterNetworkPolicies returns a ClusterNetworkPolicyInformer.
+	ClusterNetworkPolicies() ClusterNetworkPolicyInformer
 	// CustomConfigurations returns a CustomConfigurationInformer.
 	CustomConfigurations() CustomConfigurationInformer
 	// CustomNetworks returns a CustomNetworkInformer.
 	CustomNetworks() CustomNetworkInformer
+	// ExternalConfigRollouts returns a ExternalConfigRolloutInformer.
+	ExternalConfigRollouts() ExternalConfigRolloutInformer
 	// ExternalInterfaces returns a ExternalInterfaceInformer.
 	ExternalInterfaces() ExternalInterfaceInformer
 	// ServiceFunctionChains returns a ServiceFunctionChainInformer.
@@ -43,6 +47,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterNetworkPolicies returns a ClusterNetworkPolicyInformer.
+func (v *version) ClusterNetworkPolicies() ClusterNetworkPolicyInformer {
+	return &clusterNetworkPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // CustomConfigurations returns a CustomConfigurationInformer.
 func (v *version) CustomConfigurations() CustomConfigurationInformer {
 	return &customConfigurationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -53,6 +62,11 @@ func (v *version) CustomNetworks() CustomNetworkInformer {
 	return &customNetworkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ExternalConfigRollouts returns a ExternalConfigRolloutInformer.
+func (v *version) ExternalConfigRollouts() ExternalConfigRolloutInformer {
+	return &externalConfigRolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ExternalInterfaces returns a ExternalInterfaceInformer.
 func (v *version) ExternalInterfaces() ExternalInterfaceInformer {
 	return &externalInterfaceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
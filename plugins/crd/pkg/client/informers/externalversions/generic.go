@@ -53,10 +53,14 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=contivpp.io, Version=v1
+	case v1.SchemeGroupVersion.WithResource("clusternetworkpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().ClusterNetworkPolicies().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("customconfigurations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().CustomConfigurations().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("customnetworks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().CustomNetworks().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("externalconfigrollouts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().ExternalConfigRollouts().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("externalinterfaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().ExternalInterfaces().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("servicefunctionchains"):
@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	time "time"
 
 	nodeconfigv1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
@@ -59,13 +60,13 @@ func NewFilteredNodeConfigInformer(client versioned.Interface, namespace string,
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.NodeconfigV1().NodeConfigs(namespace).List(options)
+				return client.NodeconfigV1().NodeConfigs(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.NodeconfigV1().NodeConfigs(namespace).Watch(options)
+				return client.NodeconfigV1().NodeConfigs(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&nodeconfigv1.NodeConfig{},
@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"context"
 	time "time"
 
 	telemetryv1 "github.com/americanbinary/vpp/plugins/crd/pkg/apis/telemetry/v1"
@@ -59,13 +60,13 @@ func NewFilteredTelemetryReportInformer(client versioned.Interface, namespace st
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.TelemetryV1().TelemetryReports(namespace).List(options)
+				return client.TelemetryV1().TelemetryReports(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.TelemetryV1().TelemetryReports(namespace).Watch(options)
+				return client.TelemetryV1().TelemetryReports(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&telemetryv1.TelemetryReport{},
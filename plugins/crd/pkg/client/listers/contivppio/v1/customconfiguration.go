@@ -24,8 +24,10 @@ import (
 )
 
 // CustomConfigurationLister helps list CustomConfigurations.
+// All objects returned here must be treated as read-only.
 type CustomConfigurationLister interface {
 	// List lists all CustomConfigurations in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.CustomConfiguration, err error)
 	// CustomConfigurations returns an object that can list and get CustomConfigurations.
 	CustomConfigurations(namespace string) CustomConfigurationNamespaceLister
@@ -56,10 +58,13 @@ func (s *customConfigurationLister) CustomConfigurations(namespace string) Custo
 }
 
 // CustomConfigurationNamespaceLister helps list and get CustomConfigurations.
+// All objects returned here must be treated as read-only.
 type CustomConfigurationNamespaceLister interface {
 	// List lists all CustomConfigurations in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.CustomConfiguration, err error)
 	// Get retrieves the CustomConfiguration from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1.CustomConfiguration, error)
 	CustomConfigurationNamespaceListerExpansion
 }
@@ -24,8 +24,10 @@ import (
 )
 
 // CustomNetworkLister helps list CustomNetworks.
+// All objects returned here must be treated as read-only.
 type CustomNetworkLister interface {
 	// List lists all CustomNetworks in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.CustomNetwork, err error)
 	// CustomNetworks returns an object that can list and get CustomNetworks.
 	CustomNetworks(namespace string) CustomNetworkNamespaceLister
@@ -56,10 +58,13 @@ func (s *customNetworkLister) CustomNetworks(namespace string) CustomNetworkName
 }
 
 // CustomNetworkNamespaceLister helps list and get CustomNetworks.
+// All objects returned here must be treated as read-only.
 type CustomNetworkNamespaceLister interface {
 	// List lists all CustomNetworks in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.CustomNetwork, err error)
 	// Get retrieves the CustomNetwork from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1.CustomNetwork, error)
 	CustomNetworkNamespaceListerExpansion
 }
@@ -16,6 +16,10 @@
 
 package v1
 
+// ClusterNetworkPolicyListerExpansion allows custom methods to be added to
+// ClusterNetworkPolicyLister.
+type ClusterNetworkPolicyListerExpansion interface{}
+
 // CustomConfigurationListerExpansion allows custom methods to be added to
 // CustomConfigurationLister.
 type CustomConfigurationListerExpansion interface{}
@@ -32,6 +36,14 @@ type CustomNetworkListerExpansion interface{}
 // CustomNetworkNamespaceLister.
 type CustomNetworkNamespaceListerExpansion interface{}
 
+// ExternalConfigRolloutListerExpansion allows custom methods to be added to
+// ExternalConfigRolloutLister.
+type ExternalConfigRolloutListerExpansion interface{}
+
+// ExternalConfigRolloutNamespaceListerExpansion allows custom methods to be added to
+// ExternalConfigRolloutNamespaceLister.
+type ExternalConfigRolloutNamespaceListerExpansion interface{}
+
 // ExternalInterfaceListerExpansion allows custom methods to be added to
 // ExternalInterfaceLister.
 type ExternalInterfaceListerExpansion interface{}
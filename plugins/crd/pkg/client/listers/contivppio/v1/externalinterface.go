@@ -24,8 +24,10 @@ import (
 )
 
 // ExternalInterfaceLister helps list ExternalInterfaces.
+// All objects returned here must be treated as read-only.
 type ExternalInterfaceLister interface {
 	// List lists all ExternalInterfaces in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.ExternalInterface, err error)
 	// ExternalInterfaces returns an object that can list and get ExternalInterfaces.
 	ExternalInterfaces(namespace string) ExternalInterfaceNamespaceLister
@@ -56,10 +58,13 @@ func (s *externalInterfaceLister) ExternalInterfaces(namespace string) ExternalI
 }
 
 // ExternalInterfaceNamespaceLister helps list and get ExternalInterfaces.
+// All objects returned here must be treated as read-only.
 type ExternalInterfaceNamespaceLister interface {
 	// List lists all ExternalInterfaces in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.ExternalInterface, err error)
 	// Get retrieves the ExternalInterface from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1.ExternalInterface, error)
 	ExternalInterfaceNamespaceListerExpansion
 }
@@ -24,8 +24,10 @@ import (
 )
 
 // ServiceFunctionChainLister helps list ServiceFunctionChains.
+// All objects returned here must be treated as read-only.
 type ServiceFunctionChainLister interface {
 	// List lists all ServiceFunctionChains in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.ServiceFunctionChain, err error)
 	// ServiceFunctionChains returns an object that can list and get ServiceFunctionChains.
 	ServiceFunctionChains(namespace string) ServiceFunctionChainNamespaceLister
@@ -56,10 +58,13 @@ func (s *serviceFunctionChainLister) ServiceFunctionChains(namespace string) Ser
 }
 
 // ServiceFunctionChainNamespaceLister helps list and get ServiceFunctionChains.
+// All objects returned here must be treated as read-only.
 type ServiceFunctionChainNamespaceLister interface {
 	// List lists all ServiceFunctionChains in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.ServiceFunctionChain, err error)
 	// Get retrieves the ServiceFunctionChain from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1.ServiceFunctionChain, error)
 	ServiceFunctionChainNamespaceListerExpansion
 }
@@ -24,8 +24,10 @@ import (
 )
 
 // NodeConfigLister helps list NodeConfigs.
+// All objects returned here must be treated as read-only.
 type NodeConfigLister interface {
 	// List lists all NodeConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.NodeConfig, err error)
 	// NodeConfigs returns an object that can list and get NodeConfigs.
 	NodeConfigs(namespace string) NodeConfigNamespaceLister
@@ -56,10 +58,13 @@ func (s *nodeConfigLister) NodeConfigs(namespace string) NodeConfigNamespaceList
 }
 
 // NodeConfigNamespaceLister helps list and get NodeConfigs.
+// All objects returned here must be treated as read-only.
 type NodeConfigNamespaceLister interface {
 	// List lists all NodeConfigs in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.NodeConfig, err error)
 	// Get retrieves the NodeConfig from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1.NodeConfig, error)
 	NodeConfigNamespaceListerExpansion
 }
@@ -24,8 +24,10 @@ import (
 )
 
 // TelemetryReportLister helps list TelemetryReports.
+// All objects returned here must be treated as read-only.
 type TelemetryReportLister interface {
 	// List lists all TelemetryReports in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.TelemetryReport, err error)
 	// TelemetryReports returns an object that can list and get TelemetryReports.
 	TelemetryReports(namespace string) TelemetryReportNamespaceLister
@@ -56,10 +58,13 @@ func (s *telemetryReportLister) TelemetryReports(namespace string) TelemetryRepo
 }
 
 // TelemetryReportNamespaceLister helps list and get TelemetryReports.
+// All objects returned here must be treated as read-only.
 type TelemetryReportNamespaceLister interface {
 	// List lists all TelemetryReports in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1.TelemetryReport, err error)
 	// Get retrieves the TelemetryReport from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1.TelemetryReport, error)
 	TelemetryReportNamespaceListerExpansion
 }
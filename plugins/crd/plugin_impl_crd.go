@@ -49,6 +49,7 @@ import (
 	"github.com/americanbinary/vpp/plugins/crd/controller"
 	"github.com/americanbinary/vpp/plugins/crd/handler/customconfiguration"
 	"github.com/americanbinary/vpp/plugins/crd/handler/customnetwork"
+	"github.com/americanbinary/vpp/plugins/crd/handler/externalconfigrollout"
 	"github.com/americanbinary/vpp/plugins/crd/handler/externalinterface"
 	"github.com/americanbinary/vpp/plugins/crd/handler/kvdbreflector"
 	"github.com/americanbinary/vpp/plugins/crd/handler/nodeconfig"
@@ -88,15 +89,16 @@ type Plugin struct {
 	pendingResync  datasync.ResyncEvent
 	pendingChanges []datasync.ChangeEvent
 
-	telemetryController            *controller.CrdController
-	nodeConfigController           *controller.CrdController
-	customNetworkController        *controller.CrdController
-	externalInterfaceController    *controller.CrdController
-	serviceFunctionChainController *controller.CrdController
-	customConfigController         *controller.CrdController
-	cache                          *cache.ContivTelemetryCache
-	processor                      api.ContivTelemetryProcessor
-	verbose                        bool
+	telemetryController             *controller.CrdController
+	nodeConfigController            *controller.CrdController
+	customNetworkController         *controller.CrdController
+	externalInterfaceController     *controller.CrdController
+	serviceFunctionChainController  *controller.CrdController
+	customConfigController          *controller.CrdController
+	externalConfigRolloutController *controller.CrdController
+	cache                           *cache.ContivTelemetryCache
+	processor                       api.ContivTelemetryProcessor
+	verbose                         bool
 
 	crdClient     *crdClientSet.Clientset
 	apiclientset  *apiextcs.Clientset
@@ -313,11 +315,35 @@ func (p *Plugin) initializeCRDs() error {
 		},
 	}
 
+	externalConfigRolloutInformer := p.sharedFactory.Contivpp().V1().ExternalConfigRollouts().Informer()
+	externalConfigRolloutLog := p.Log.NewLogger("externalConfigRolloutHandler")
+	p.externalConfigRolloutController = &controller.CrdController{
+		Deps: controller.Deps{
+			Log:       p.Log.NewLogger("externalConfigRolloutController"),
+			APIClient: p.apiclientset,
+			Informer:  externalConfigRolloutInformer,
+			EventHandler: &externalconfigrollout.Handler{
+				Log:       externalConfigRolloutLog,
+				CrdClient: p.crdClient,
+				K8sCache:  p.cache.K8sCache,
+				Ctx:       p.ctx,
+			},
+		},
+		Spec: controller.CrdSpec{
+			TypeName:   reflect.TypeOf(v1.ExternalConfigRollout{}).Name(),
+			Group:      contivppio.GroupName,
+			Version:    "v1",
+			Plural:     "externalconfigrollouts",
+			Validation: externalconfigrollout.Validation(),
+		},
+	}
+
 	p.nodeConfigController.Init()
 	p.customNetworkController.Init()
 	p.externalInterfaceController.Init()
 	p.serviceFunctionChainController.Init()
 	p.customConfigController.Init()
+	p.externalConfigRolloutController.Init()
 
 	if p.verbose {
 		p.customNetworkController.Log.SetLevel(logging.DebugLevel)
@@ -326,6 +352,8 @@ func (p *Plugin) initializeCRDs() error {
 		p.serviceFunctionChainController.Log.SetLevel(logging.DebugLevel)
 		p.customConfigController.Log.SetLevel(logging.DebugLevel)
 		customConfigLog.SetLevel(logging.DebugLevel)
+		p.externalConfigRolloutController.Log.SetLevel(logging.DebugLevel)
+		externalConfigRolloutLog.SetLevel(logging.DebugLevel)
 	}
 
 	return nil
@@ -448,6 +476,7 @@ func (p *Plugin) onEtcdConnect() error {
 		go p.externalInterfaceController.Run(p.ctx.Done())
 		go p.serviceFunctionChainController.Run(p.ctx.Done())
 		go p.customConfigController.Run(p.ctx.Done())
+		go p.externalConfigRolloutController.Run(p.ctx.Done())
 	}()
 	return nil
 }
@@ -28,7 +28,7 @@ find "${CRDGEN_DEPS_DIR}" -type d -empty -delete
 
 # Create symlink to generator
 # TODO get version from the go.mod file
-module="k8s.io/code-generator@v0.0.0-20191004115455-8e001e5d1894"
+module="k8s.io/code-generator@v0.21.14"
 echo "setting up symplink for $module"
 go list -f "${CRDGEN_DEPS_DIR}/{{ .Path }}" -m $module | xargs -L1 dirname | sort | uniq | xargs mkdir -p
 go list -f "{{ .Dir }} ${CRDGEN_DEPS_DIR}/{{ .Path }}" -m $module | xargs -L1 -- ln -s
@@ -28,8 +28,7 @@ import (
 	docker "github.com/fsouza/go-dockerclient"
 	"k8s.io/apimachinery/pkg/util/rand"
 	devicepluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
-	"k8s.io/kubernetes/pkg/kubelet/apis/podresources"
-	podresourcesapi "k8s.io/kubernetes/pkg/kubelet/apis/podresources/v1alpha1"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1alpha1"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
@@ -61,7 +60,7 @@ const (
 	// grpc endpoints for communication with kubelet
 	devicePluginSocketName      = "contiv-vpp.sock"
 	devicePluginEndpoint        = devicepluginapi.DevicePluginPath + devicePluginSocketName
-	kubeletPodResourcesEndpoint = "unix:///var/lib/kubelet/pod-resources/kubelet.sock"
+	kubeletPodResourcesEndpoint = "/var/lib/kubelet/pod-resources/kubelet.sock"
 
 	// labels attached to (not only sandbox) container to identify the pod it belongs to
 	k8sLabelForPodName      = "io.kubernetes.pod.name"
@@ -132,7 +131,7 @@ func (d *DeviceManager) Init() (err error) {
 	}
 
 	// connect to kubelet pod resources server endpoint
-	d.podResClient, d.podResClientConn, err = podresources.GetClient(kubeletPodResourcesEndpoint,
+	d.podResClient, d.podResClientConn, err = d.connectPodResources(kubeletPodResourcesEndpoint,
 		grpcClientTimeout, defaultPodResourcesMaxSize)
 	if err != nil {
 		d.Log.Warn("Unable to connect to kubelet endpoint %s: %v", kubeletPodResourcesEndpoint, err)
@@ -570,6 +569,23 @@ func (d *DeviceManager) startDevicePluginServer() error {
 	return nil
 }
 
+// connectPodResources connects to the kubelet pod resources server endpoint.
+func (d *DeviceManager) connectPodResources(endpoint string, timeout time.Duration, maxMsgSize int) (
+	podresourcesapi.PodResourcesListerClient, *grpc.ClientConn, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, endpoint, grpc.WithInsecure(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return podresourcesapi.NewPodResourcesListerClient(conn), conn, nil
+}
+
 // registerDevicePlugin connects to Kubelet and registers our device plugin within it.
 func (d *DeviceManager) registerDevicePlugin(kubeletEndpoint, pluginEndpoint, resourceName string) error {
 
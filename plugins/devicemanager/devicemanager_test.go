@@ -0,0 +1,85 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devicemanager
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1alpha1"
+)
+
+// mockPodResourcesServer is a kubelet pod resources server with a fixed list of pods.
+type mockPodResourcesServer struct {
+	pods []*podresourcesapi.PodResources
+}
+
+func (s *mockPodResourcesServer) List(context.Context, *podresourcesapi.ListPodResourcesRequest) (
+	*podresourcesapi.ListPodResourcesResponse, error) {
+	return &podresourcesapi.ListPodResourcesResponse{PodResources: s.pods}, nil
+}
+
+func TestConnectPodResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "pod-resources")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+	endpoint := filepath.Join(dir, "kubelet.sock")
+
+	// kubelet pod resources server listening on a unix socket
+	listener, err := net.Listen("unix", endpoint)
+	Expect(err).To(BeNil())
+	server := grpc.NewServer()
+	podresourcesapi.RegisterPodResourcesListerServer(server, &mockPodResourcesServer{
+		pods: []*podresourcesapi.PodResources{
+			{
+				Name:      "pod1",
+				Namespace: "default",
+				Containers: []*podresourcesapi.ContainerResources{
+					{
+						Name: "container1",
+						Devices: []*podresourcesapi.ContainerDevices{
+							{ResourceName: memifResourceName, DeviceIds: []string{"memif1"}},
+						},
+					},
+				},
+			},
+		},
+	})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	d := &DeviceManager{}
+	client, conn, err := d.connectPodResources(endpoint, grpcClientTimeout, defaultPodResourcesMaxSize)
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.List(ctx, &podresourcesapi.ListPodResourcesRequest{})
+	Expect(err).To(BeNil())
+	Expect(resp.PodResources).To(HaveLen(1))
+	Expect(resp.PodResources[0].Name).To(Equal("pod1"))
+	Expect(resp.PodResources[0].Containers[0].Devices[0].DeviceIds).To(Equal([]string{"memif1"}))
+}
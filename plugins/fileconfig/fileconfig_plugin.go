@@ -0,0 +1,393 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconfig
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"go.ligato.io/cn-infra/v2/infra"
+
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
+	contivgrpc "github.com/americanbinary/vpp/plugins/grpc"
+	"github.com/americanbinary/vpp/plugins/grpc/rpc"
+)
+
+const (
+	// by default, the configuration is read from this directory
+	defaultDirectory = "/var/contiv/external-config"
+
+	// by default, changes are applied once files stop changing for 1 second
+	defaultReloadDelay = 1
+
+	// how often the existence of the (missing) configuration directory is checked
+	dirCheckPeriod = 5 * time.Second
+
+	// how long to wait before retrying to send changes that the event loop
+	// has refused (e.g. because the event queue was full)
+	pushRetryDelay = time.Second
+)
+
+// supported file extensions
+var fileExtensions = []string{".yaml", ".yml", ".json"}
+
+// Plugin implements external configuration source (for the Controller) backed
+// by a watched directory of YAML/JSON files. Every file contains rpc.DataRequest
+// (the same structure as used by the Contiv GRPC API) with vpp-agent configuration.
+// Configuration from all the files is merged together.
+type Plugin struct {
+	Deps
+
+	config *Config
+
+	sync.Mutex
+	files       map[string]controller.KeyValuePairs // file name -> config from the file
+	snapshot    controller.KeyValuePairs            // configuration last announced to the Controller
+	snapshotGen uint64                              // incremented with every change of the snapshot
+
+	watcher  *fsnotify.Watcher
+	watching bool // true if the directory exists and is being watched
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// Deps lists dependencies of the Plugin.
+type Deps struct {
+	infra.PluginDeps
+	EventLoop controller.EventLoop
+}
+
+// Config holds the Plugin configuration.
+type Config struct {
+	// Directory with configuration files (watched once it gets created
+	// if it does not exist).
+	Directory string `json:"directory"`
+
+	// ReloadDelay is the time to wait for the files to stop changing before
+	// the changes are applied.
+	ReloadDelay uint32 `json:"reloadDelay"` // in seconds
+}
+
+// Init loads the plugin configuration and starts watching the configuration
+// directory.
+func (p *Plugin) Init() error {
+	p.config = &Config{
+		Directory:   defaultDirectory,
+		ReloadDelay: defaultReloadDelay,
+	}
+	if _, err := p.Cfg.LoadValue(p.config); err != nil {
+		return err
+	}
+	p.files = make(map[string]controller.KeyValuePairs)
+	p.snapshot = make(controller.KeyValuePairs)
+	p.quit = make(chan struct{})
+
+	var err error
+	p.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if p.watching, err = p.watchDir(); err != nil {
+		p.watcher.Close()
+		return err
+	}
+	if !p.watching {
+		p.Log.Infof("Directory %s does not exist, waiting for it to be created",
+			p.config.Directory)
+	}
+	p.wg.Add(1)
+	go p.watchDirectory()
+	return nil
+}
+
+// GetConfigSnapshot returns full configuration snapshot read from the files.
+func (p *Plugin) GetConfigSnapshot() (controller.KeyValuePairs, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	snapshot, err := p.reload()
+	if err != nil {
+		return nil, err
+	}
+	p.snapshot = snapshot
+	p.snapshotGen++
+	return copyConfig(snapshot), nil
+}
+
+// Close stops watching the configuration directory.
+func (p *Plugin) Close() error {
+	if p.watcher == nil {
+		return nil
+	}
+	close(p.quit)
+	err := p.watcher.Close()
+	p.wg.Wait()
+	return err
+}
+
+// watchDir starts watching the configuration directory. Returns false if
+// the directory does not exist.
+func (p *Plugin) watchDir() (watching bool, err error) {
+	if _, err := os.Stat(p.config.Directory); os.IsNotExist(err) {
+		return false, nil
+	}
+	if err := p.watcher.Add(p.config.Directory); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// watchDirectory waits for changes in the configuration directory and propagates
+// them into the Controller. While the directory does not exist, it is periodically
+// checked if it was created.
+func (p *Plugin) watchDirectory() {
+	defer p.wg.Done()
+
+	reloadDelay := time.Duration(p.config.ReloadDelay) * time.Second
+	dirCheck := time.NewTicker(dirCheckPeriod)
+	defer dirCheck.Stop()
+
+	var reloadTimer <-chan time.Time
+	for {
+		select {
+		case <-p.quit:
+			return
+
+		case <-dirCheck.C:
+			if p.watching {
+				continue
+			}
+			watching, err := p.watchDir()
+			if err != nil {
+				p.Log.Warnf("Failed to watch directory %s: %v", p.config.Directory, err)
+				continue
+			}
+			if watching {
+				p.Log.Infof("Directory %s was created, loading external configuration",
+					p.config.Directory)
+				p.watching = true
+				reloadTimer = time.After(reloadDelay)
+			}
+
+		case event, ok := <-p.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if filepath.Clean(event.Name) == filepath.Clean(p.config.Directory) &&
+				event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				// the directory itself was removed, wait for it to be re-created
+				p.watcher.Remove(p.config.Directory)
+				p.watching = false
+			}
+			// wait for the files to stop changing (editors may write a file
+			// in multiple steps, ConfigMaps are updated via symlink swap)
+			reloadTimer = time.After(reloadDelay)
+
+		case err, ok := <-p.watcher.Errors:
+			if !ok {
+				return
+			}
+			p.Log.Warnf("Error while watching directory %s: %v", p.config.Directory, err)
+
+		case <-reloadTimer:
+			reloadTimer = nil
+			if retry := p.applyChanges(); retry {
+				reloadTimer = time.After(pushRetryDelay)
+			}
+		}
+	}
+}
+
+// applyChanges reloads the configuration from the files and sends the changes
+// to the Controller.
+// The snapshot of the announced configuration is updated only once the changes
+// are accepted by the event loop, otherwise true is returned and the caller
+// should retry later.
+func (p *Plugin) applyChanges() (retry bool) {
+	p.Lock()
+	snapshot, err := p.reload()
+	if err != nil {
+		p.Unlock()
+		p.Log.Errorf("Failed to reload external configuration from %s: %v", p.config.Directory, err)
+		return false
+	}
+	changes := diffConfig(p.snapshot, snapshot)
+	snapshotGen := p.snapshotGen
+	p.Unlock()
+
+	if len(changes) == 0 {
+		return false
+	}
+	p.Log.Infof("External configuration from files has changed (%d key(s))", len(changes))
+	event := controller.NewExternalConfigChange(p.String(), true)
+	event.UpdatedKVs = changes
+	if err = p.EventLoop.PushEvent(event); err != nil {
+		p.Log.Warnf("Failed to send external configuration changes from files (will retry): %v", err)
+		return true
+	}
+
+	// Controller has applied the changes to its view of the external config
+	// even if the transaction fails (failures are healed by resync),
+	// unless a resync has already read a newer snapshot in the meantime
+	p.Lock()
+	if p.snapshotGen == snapshotGen {
+		p.snapshot = snapshot
+		p.snapshotGen++
+	}
+	p.Unlock()
+
+	if err = event.Wait(); err != nil {
+		p.Log.Errorf("Failed to apply external configuration from files: %v", err)
+	}
+	return false
+}
+
+// reload reads all the configuration files from the directory and returns
+// the merged configuration. If a file cannot be parsed, the previously loaded
+// content of the file is used instead (if any). Call with the lock acquired.
+func (p *Plugin) reload() (controller.KeyValuePairs, error) {
+	entries, err := ioutil.ReadDir(p.config.Directory)
+	if os.IsNotExist(err) {
+		p.files = make(map[string]controller.KeyValuePairs)
+		return make(controller.KeyValuePairs), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]controller.KeyValuePairs)
+	for _, entry := range entries {
+		if entry.IsDir() || !isConfigFile(entry.Name()) {
+			continue
+		}
+		fileName := entry.Name()
+		config, err := readConfigFile(filepath.Join(p.config.Directory, fileName))
+		if err != nil {
+			p.Log.Errorf("Failed to read configuration file %s: %v", fileName, err)
+			if prevConfig, hasPrev := p.files[fileName]; hasPrev {
+				files[fileName] = prevConfig
+			}
+			continue
+		}
+		files[fileName] = config
+	}
+	p.files = files
+	return p.mergeFiles(), nil
+}
+
+// mergeFiles merges configuration from all the files. Files are processed
+// in the alphabetical order, for duplicate keys the last file wins.
+func (p *Plugin) mergeFiles() controller.KeyValuePairs {
+	var fileNames []string
+	for fileName := range p.files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	merged := make(controller.KeyValuePairs)
+	keySource := make(map[string]string)
+	for _, fileName := range fileNames {
+		for key, value := range p.files[fileName] {
+			if prevFile, duplicate := keySource[key]; duplicate {
+				p.Log.Warnf("Key %s is defined in both %s and %s, using the latter",
+					key, prevFile, fileName)
+			}
+			merged[key] = value
+			keySource[key] = fileName
+		}
+	}
+	return merged
+}
+
+// isConfigFile returns true if the file has one of the supported extensions.
+func isConfigFile(fileName string) bool {
+	if strings.HasPrefix(fileName, ".") {
+		// hidden file (e.g. editor swap file)
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, supported := range fileExtensions {
+		if ext == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// readConfigFile reads vpp-agent configuration from a YAML/JSON file.
+func readConfigFile(path string) (controller.KeyValuePairs, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(content)
+}
+
+// parseConfig parses rpc.DataRequest from YAML or JSON and returns it as key-value
+// pairs.
+func parseConfig(content []byte) (controller.KeyValuePairs, error) {
+	data := &rpc.DataRequest{}
+	if len(bytes.TrimSpace(content)) > 0 {
+		jsonContent, err := yaml.YAMLToJSON(content)
+		if err != nil {
+			return nil, err
+		}
+		if err = jsonpb.Unmarshal(bytes.NewReader(jsonContent), data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data request: %v", err)
+		}
+	}
+	return contivgrpc.BuildConfig(data, false), nil
+}
+
+// diffConfig returns changes to apply to get from the old to the new configuration
+// (removed keys are represented by nil values).
+func diffConfig(oldConfig, newConfig controller.KeyValuePairs) controller.KeyValuePairs {
+	changes := make(controller.KeyValuePairs)
+	for key, newValue := range newConfig {
+		if oldValue, hasOld := oldConfig[key]; !hasOld || !proto.Equal(oldValue, newValue) {
+			changes[key] = newValue
+		}
+	}
+	for key := range oldConfig {
+		if _, hasNew := newConfig[key]; !hasNew {
+			changes[key] = nil
+		}
+	}
+	return changes
+}
+
+// copyConfig returns a shallow copy of the configuration.
+func copyConfig(config controller.KeyValuePairs) controller.KeyValuePairs {
+	configCopy := make(controller.KeyValuePairs, len(config))
+	for key, value := range config {
+		configCopy[key] = value
+	}
+	return configCopy
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconfig
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/vpp-agent/v3/pkg/models"
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
+
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
+)
+
+const yamlConfig = `
+StaticRoutes:
+  - dstNetwork: 10.20.0.0/16
+    nextHopAddr: 192.168.16.1
+    outgoingInterface: GigabitEthernet0/8/0
+`
+
+const jsonConfig = `{
+  "StaticRoutes": [
+    {
+      "dstNetwork": "10.20.0.0/16",
+      "nextHopAddr": "192.168.16.1",
+      "outgoingInterface": "GigabitEthernet0/8/0"
+    }
+  ]
+}`
+
+var route = &vpp_l3.Route{
+	DstNetwork:        "10.20.0.0/16",
+	NextHopAddr:       "192.168.16.1",
+	OutgoingInterface: "GigabitEthernet0/8/0",
+}
+
+func TestParseConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, content := range []string{yamlConfig, jsonConfig} {
+		config, err := parseConfig([]byte(content))
+		Expect(err).To(BeNil())
+		Expect(config).To(HaveLen(1))
+		Expect(config).To(HaveKey(models.Key(route)))
+		Expect(proto.Equal(config[models.Key(route)], route)).To(BeTrue())
+	}
+
+	// empty file
+	config, err := parseConfig([]byte("\n"))
+	Expect(err).To(BeNil())
+	Expect(config).To(BeEmpty())
+
+	// invalid content
+	_, err = parseConfig([]byte("StaticRoutes: 42"))
+	Expect(err).ToNot(BeNil())
+}
+
+func TestDiffConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	route2 := &vpp_l3.Route{
+		DstNetwork:  "10.30.0.0/16",
+		NextHopAddr: "192.168.16.1",
+	}
+	modifiedRoute := &vpp_l3.Route{
+		DstNetwork:        "10.30.0.0/16",
+		NextHopAddr:       "192.168.16.1",
+		OutgoingInterface: "loop0",
+	}
+	oldConfig := controller.KeyValuePairs{
+		models.Key(route):  route,
+		models.Key(route2): route2,
+	}
+
+	// no change
+	Expect(diffConfig(oldConfig, oldConfig)).To(BeEmpty())
+
+	// modified + deleted value
+	changes := diffConfig(oldConfig, controller.KeyValuePairs{
+		models.Key(modifiedRoute): modifiedRoute,
+	})
+	Expect(changes).To(HaveLen(2))
+	Expect(changes[models.Key(route)]).To(BeNil())
+	Expect(changes[models.Key(modifiedRoute)]).To(Equal(modifiedRoute))
+}
+
+func TestIsConfigFile(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(isConfigFile("routes.yaml")).To(BeTrue())
+	Expect(isConfigFile("acls.YML")).To(BeTrue())
+	Expect(isConfigFile("config.json")).To(BeTrue())
+	Expect(isConfigFile(".routes.yaml.swp")).To(BeFalse())
+	Expect(isConfigFile(".hidden.yaml")).To(BeFalse())
+	Expect(isConfigFile("README.md")).To(BeFalse())
+}
+
+// testEventLoop refuses the first <refuse> events, the remaining events are
+// recorded and immediately marked as processed.
+type testEventLoop struct {
+	refuse int
+	events []*controller.ExternalConfigChange
+}
+
+func (el *testEventLoop) PushEvent(event controller.Event) error {
+	if el.refuse > 0 {
+		el.refuse--
+		return errors.New("event queue is full")
+	}
+	ev := event.(*controller.ExternalConfigChange)
+	el.events = append(el.events, ev)
+	ev.Done(nil)
+	return nil
+}
+
+func TestApplyChangesRetry(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "fileconfig")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "routes.yaml"), []byte(yamlConfig), 0644)
+	Expect(err).To(BeNil())
+
+	eventLoop := &testEventLoop{refuse: 1}
+	p := &Plugin{
+		Deps: Deps{
+			PluginDeps: infra.PluginDeps{
+				PluginName: "fileconfig",
+				Log:        logging.ForPlugin("fileconfig"),
+			},
+			EventLoop: eventLoop,
+		},
+		config:   &Config{Directory: dir},
+		files:    make(map[string]controller.KeyValuePairs),
+		snapshot: make(controller.KeyValuePairs),
+	}
+
+	// refused event -> snapshot is not advanced, retry is requested
+	Expect(p.applyChanges()).To(BeTrue())
+	Expect(eventLoop.events).To(BeEmpty())
+	Expect(p.snapshot).To(BeEmpty())
+
+	// retry -> changes are sent again
+	Expect(p.applyChanges()).To(BeFalse())
+	Expect(eventLoop.events).To(HaveLen(1))
+	Expect(eventLoop.events[0].UpdatedKVs).To(HaveKey(models.Key(route)))
+	Expect(p.snapshot).To(HaveLen(1))
+	Expect(proto.Equal(p.snapshot[models.Key(route)], route)).To(BeTrue())
+
+	// nothing more to send
+	Expect(p.applyChanges()).To(BeFalse())
+	Expect(eventLoop.events).To(HaveLen(1))
+}
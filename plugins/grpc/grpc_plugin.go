@@ -27,6 +27,15 @@ import (
 
 	"go.ligato.io/vpp-agent/v3/pkg/models"
 	"go.ligato.io/vpp-agent/v3/plugins/orchestrator"
+	linux_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/linux/interfaces"
+	linux_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/linux/l3"
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
+	vpp_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/interfaces"
+	vpp_ipsec "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/ipsec"
+	vpp_l2 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l2"
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
+	vpp_nat "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/nat"
+	vpp_punt "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/punt"
 )
 
 //go:generate protoc --proto_path=rpc --proto_path=$GOPATH/src/github.com/ligato/vpp-agent/proto --go_out=plugins=grpc,paths=source_relative:rpc rpc/rpc.proto
@@ -126,7 +135,7 @@ func (p *Plugin) Close() error {
 // Put propagates request from GRPC client to add/modify some external configuration items.
 func (svc *ChangeSvc) Put(ctx context.Context, data *rpc.DataRequest) (*rpc.PutResponse, error) {
 	// prepare configuration changes
-	config := buildConfig(data, false)
+	config := BuildConfig(data, false)
 
 	// persist changes
 	for key, value := range config {
@@ -150,7 +159,7 @@ func (svc *ChangeSvc) Put(ctx context.Context, data *rpc.DataRequest) (*rpc.PutR
 // Del propagates request from GRPC client to remove some external configuration items.
 func (svc *ChangeSvc) Del(ctx context.Context, data *rpc.DataRequest) (*rpc.DelResponse, error) {
 	// prepare configuration changes
-	config := buildConfig(data, true)
+	config := BuildConfig(data, true)
 
 	// persist changes
 	for key := range config {
@@ -171,10 +180,26 @@ func (svc *ChangeSvc) Del(ctx context.Context, data *rpc.DataRequest) (*rpc.DelR
 	return &rpc.DelResponse{}, err
 }
 
+// Get returns values of the requested external configuration items currently
+// stored in the local DB. Items which are not configured are omitted.
+func (svc *ChangeSvc) Get(ctx context.Context, data *rpc.DataRequest) (*rpc.DataRequest, error) {
+	snapshot, err := svc.plugin.GetConfigSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	config := make(controller.KeyValuePairs)
+	for key := range BuildConfig(data, true) {
+		if value, configured := snapshot[key]; configured {
+			config[key] = value
+		}
+	}
+	return BuildDataRequest(config), nil
+}
+
 // Resync re-synchronizes configuration between the GRPC client and vpp-agent.
 func (svc *ResyncSvc) Resync(ctx context.Context, data *rpc.DataRequest) (*rpc.ResyncResponse, error) {
 	// prepare configuration changes
-	config := buildConfig(data, false)
+	config := BuildConfig(data, false)
 
 	// resync local DB
 	err := svc.resyncDB(config)
@@ -221,7 +246,9 @@ func (svc *ResyncSvc) resyncDB(resyncCfg controller.KeyValuePairs) error {
 	return nil
 }
 
-func buildConfig(data *rpc.DataRequest, delete bool) controller.KeyValuePairs {
+// BuildConfig converts data request into key-value pairs of vpp-agent configuration.
+// With <delete> set to true, values are nil (i.e. represent removal of the keys).
+func BuildConfig(data *rpc.DataRequest, delete bool) controller.KeyValuePairs {
 	extConfig := make(controller.KeyValuePairs)
 	for _, item := range data.AccessLists {
 		key := models.Key(item)
@@ -303,3 +330,51 @@ func buildConfig(data *rpc.DataRequest, delete bool) controller.KeyValuePairs {
 	}
 	return extConfig
 }
+
+// BuildDataRequest converts key-value pairs of vpp-agent configuration into data request.
+// It is the inverse of BuildConfig - values of types not supported by BuildConfig
+// are skipped.
+func BuildDataRequest(config controller.KeyValuePairs) *rpc.DataRequest {
+	data := &rpc.DataRequest{}
+	for _, value := range config {
+		switch item := value.(type) {
+		case *vpp_acl.ACL:
+			data.AccessLists = append(data.AccessLists, item)
+		case *vpp_interfaces.Interface:
+			data.Interfaces = append(data.Interfaces, item)
+		case *vpp_l2.BridgeDomain:
+			data.BridgeDomains = append(data.BridgeDomains, item)
+		case *vpp_l2.FIBEntry:
+			data.FIBs = append(data.FIBs, item)
+		case *vpp_l2.XConnectPair:
+			data.XCons = append(data.XCons, item)
+		case *vpp_l3.Route:
+			data.StaticRoutes = append(data.StaticRoutes, item)
+		case *vpp_l3.ARPEntry:
+			data.ArpEntries = append(data.ArpEntries, item)
+		case *vpp_l3.ProxyARP:
+			data.ProxyArp = item
+		case *vpp_l3.IPScanNeighbor:
+			data.IPScanNeighbor = item
+		case *vpp_ipsec.SecurityAssociation:
+			data.SAs = append(data.SAs, item)
+		case *vpp_ipsec.SecurityPolicyDatabase:
+			data.SPDs = append(data.SPDs, item)
+		case *vpp_punt.IPRedirect:
+			data.IPRedirectPunts = append(data.IPRedirectPunts, item)
+		case *vpp_punt.ToHost:
+			data.ToHostPunts = append(data.ToHostPunts, item)
+		case *vpp_nat.Nat44Global:
+			data.NatGlobal = item
+		case *vpp_nat.DNat44:
+			data.DNATs = append(data.DNATs, item)
+		case *linux_interfaces.Interface:
+			data.LinuxInterfaces = append(data.LinuxInterfaces, item)
+		case *linux_l3.ARPEntry:
+			data.LinuxArpEntries = append(data.LinuxArpEntries, item)
+		case *linux_l3.Route:
+			data.LinuxRoutes = append(data.LinuxRoutes, item)
+		}
+	}
+	return data
+}
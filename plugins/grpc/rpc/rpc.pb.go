@@ -374,62 +374,63 @@ func init() {
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor_77a6da22d6a3feb1) }
 
 var fileDescriptor_77a6da22d6a3feb1 = []byte{
-	// 879 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x96, 0xdf, 0x6f, 0xdb, 0x36,
-	0x10, 0x80, 0x31, 0xa4, 0x2d, 0x16, 0xba, 0x6d, 0x5a, 0xb6, 0x4b, 0x98, 0x64, 0x1b, 0xb2, 0x60,
-	0xc5, 0xd2, 0x6d, 0x90, 0x31, 0x3b, 0x41, 0xdb, 0xa1, 0x03, 0x26, 0xdb, 0xf9, 0x61, 0x20, 0x30,
-	0x04, 0x3a, 0x18, 0x86, 0xed, 0x61, 0xa0, 0x68, 0xda, 0x21, 0xa0, 0x91, 0x1c, 0x49, 0x65, 0xf1,
-	0xdb, 0xfe, 0xf4, 0x81, 0x94, 0x2c, 0x53, 0xb2, 0xd2, 0x07, 0x19, 0x36, 0xef, 0xfb, 0xee, 0xa8,
-	0x13, 0x49, 0x19, 0x6c, 0x6b, 0x45, 0x23, 0xa5, 0xa5, 0x95, 0x70, 0x4b, 0x2b, 0x7a, 0x80, 0x32,
-	0xbe, 0x20, 0x56, 0x76, 0xef, 0x94, 0xea, 0x12, 0x9a, 0xb9, 0xab, 0x08, 0xd7, 0x23, 0xe9, 0xdc,
-	0x5d, 0x65, 0xe4, 0x4d, 0x10, 0xe1, 0xc2, 0x32, 0x3d, 0x27, 0x94, 0x99, 0xf5, 0xd7, 0x12, 0x3b,
-	0x6a, 0xc7, 0x8c, 0x22, 0xa2, 0x24, 0xbe, 0x09, 0x88, 0xac, 0xd7, 0x4d, 0x35, 0x9f, 0x2d, 0xd8,
-	0x5f, 0x33, 0xf9, 0x37, 0xe1, 0x2b, 0x64, 0xaf, 0x8e, 0xcc, 0x79, 0x5a, 0x06, 0xbe, 0xac, 0x07,
-	0xee, 0xa9, 0x14, 0x82, 0x51, 0xdb, 0xa6, 0xf5, 0xbb, 0x44, 0xab, 0x32, 0xb0, 0x5f, 0x0f, 0x68,
-	0x99, 0x5b, 0xd6, 0xee, 0xdc, 0xe9, 0x79, 0x4b, 0x27, 0x04, 0xb1, 0xee, 0x2a, 0x23, 0x07, 0x41,
-	0x44, 0xe5, 0xc2, 0xfa, 0x8f, 0x96, 0x09, 0x72, 0x65, 0x18, 0x2d, 0x3e, 0xcb, 0xe8, 0x6e, 0xbd,
-	0x58, 0xd6, 0x2f, 0xc7, 0xbf, 0x2b, 0xc7, 0x33, 0x2e, 0xf2, 0xfb, 0x4f, 0x75, 0xf7, 0xdb, 0x3a,
-	0xa8, 0x2c, 0x49, 0x33, 0x87, 0x95, 0x5f, 0x1a, 0xb7, 0x5b, 0x50, 0xb5, 0x4e, 0x1c, 0x36, 0x43,
-	0x41, 0x2f, 0x8e, 0xff, 0x7b, 0x0a, 0x3a, 0x23, 0x62, 0x09, 0x66, 0xff, 0xe4, 0xcc, 0x58, 0x78,
-	0x06, 0x3a, 0x31, 0xa5, 0xcc, 0x98, 0x6b, 0x6e, 0xac, 0x41, 0xe0, 0x68, 0xeb, 0xa4, 0xd3, 0x7b,
-	0x15, 0x15, 0x29, 0xa2, 0x3b, 0xa5, 0x22, 0xb7, 0x70, 0xe2, 0xe1, 0x35, 0x0e, 0x39, 0xa7, 0x5d,
-	0x48, 0xfd, 0x2f, 0xd1, 0x33, 0x2e, 0x16, 0x06, 0xed, 0xb4, 0x68, 0xe9, 0x3c, 0x8a, 0x07, 0x17,
-	0x38, 0xe4, 0xe0, 0xaf, 0x00, 0x8c, 0xab, 0x3b, 0x47, 0xaf, 0xbd, 0x75, 0x14, 0x5a, 0xeb, 0xbe,
-	0x44, 0x15, 0x88, 0x03, 0x07, 0xfe, 0x04, 0x1e, 0x4f, 0x15, 0x11, 0x06, 0x7d, 0xe1, 0xe5, 0xc3,
-	0x07, 0x64, 0xc7, 0xe0, 0x82, 0x74, 0x45, 0x71, 0x9e, 0xb1, 0xe1, 0x2d, 0xe1, 0xc2, 0xa0, 0xdd,
-	0x7a, 0x51, 0xdf, 0xa4, 0xa8, 0x6a, 0x6e, 0x05, 0xe2, 0xc0, 0x81, 0x67, 0x60, 0xfb, 0x37, 0x3d,
-	0xbf, 0xf1, 0x08, 0xda, 0xf3, 0x09, 0xf6, 0xc2, 0xc2, 0x59, 0x3f, 0x5a, 0xc5, 0xf1, 0x9a, 0x84,
-	0x57, 0x60, 0x67, 0x42, 0xec, 0xe9, 0x69, 0x70, 0xcb, 0xc8, 0xcb, 0x5f, 0x87, 0xb2, 0x5b, 0x74,
-	0x75, 0x0c, 0x37, 0x35, 0x38, 0x01, 0x2f, 0xfd, 0x50, 0x3c, 0x9b, 0x69, 0x66, 0x4c, 0x22, 0x65,
-	0x66, 0xd0, 0xfe, 0x66, 0xfb, 0xaa, 0x5c, 0x01, 0x88, 0x37, 0x55, 0x18, 0x83, 0x67, 0x03, 0xbf,
-	0x27, 0x47, 0x7e, 0x4b, 0x1a, 0x74, 0xb2, 0xd9, 0xcd, 0xac, 0x17, 0x85, 0x0c, 0xae, 0x1b, 0xf0,
-	0x07, 0xf0, 0xe8, 0x62, 0x3c, 0x30, 0xe8, 0x6d, 0x4b, 0x3b, 0x7a, 0xd1, 0xc5, 0x78, 0x70, 0x2e,
-	0xac, 0x5e, 0x62, 0x0f, 0xb9, 0xa7, 0xf6, 0xfb, 0x50, 0x0a, 0x83, 0xbe, 0x6f, 0xad, 0xe3, 0x62,
-	0x6e, 0x8f, 0x27, 0x84, 0x6b, 0x5c, 0x90, 0xf0, 0x3d, 0x78, 0x3a, 0xb5, 0xc4, 0x72, 0x8a, 0xdd,
-	0xea, 0x35, 0xa8, 0xe7, 0xcd, 0xd7, 0x8d, 0xb6, 0xfb, 0x20, 0xae, 0x91, 0xf0, 0x1d, 0x00, 0xb1,
-	0x56, 0xae, 0x3c, 0x67, 0x06, 0xf5, 0x5b, 0x1f, 0x57, 0x8c, 0x93, 0x62, 0x7e, 0x01, 0x0a, 0xfb,
-	0xe0, 0xf3, 0x44, 0xcb, 0xfb, 0x65, 0xac, 0x15, 0x3a, 0x3d, 0xfa, 0xac, 0x45, 0x2b, 0xc2, 0x38,
-	0xc1, 0x15, 0x08, 0xcf, 0xc1, 0xf3, 0x71, 0x32, 0xa5, 0x44, 0x4c, 0x18, 0x5f, 0xdc, 0xa6, 0x52,
-	0xa3, 0x33, 0xaf, 0x7e, 0xd5, 0x50, 0xeb, 0x10, 0x6e, 0x48, 0xf0, 0x23, 0x78, 0x34, 0x4d, 0x46,
-	0x06, 0x7d, 0xf4, 0xd3, 0x3d, 0xa9, 0x2d, 0x6b, 0x7f, 0xba, 0x4c, 0x19, 0xcd, 0x35, 0xb7, 0xcb,
-	0x44, 0x66, 0x9c, 0x2e, 0xdd, 0x16, 0x4e, 0x89, 0x61, 0xd8, 0x5b, 0xf0, 0x1d, 0xd8, 0x9a, 0xc6,
-	0x06, 0xfd, 0xe2, 0xe5, 0x37, 0x0f, 0xcb, 0xb1, 0x31, 0x92, 0x72, 0x62, 0xb9, 0x14, 0xd8, 0x19,
-	0xf0, 0x1c, 0xec, 0x8c, 0x13, 0xcc, 0x66, 0x5c, 0xbb, 0xf6, 0xe7, 0xc2, 0x1a, 0x14, 0x6f, 0x3e,
-	0x22, 0x7f, 0xf8, 0xad, 0x39, 0xdc, 0x74, 0xe0, 0x07, 0xd0, 0xb9, 0x91, 0x57, 0xd2, 0x94, 0x29,
-	0x06, 0x9b, 0x3d, 0xf7, 0x29, 0x0a, 0x06, 0x87, 0x2c, 0xfc, 0x19, 0x80, 0xf3, 0x7b, 0xca, 0x94,
-	0x9b, 0x93, 0x41, 0x43, 0x6f, 0x1e, 0x6c, 0x98, 0x15, 0x82, 0x03, 0x1a, 0x7e, 0x00, 0xdb, 0x13,
-	0x62, 0x2f, 0x33, 0x99, 0x92, 0x0c, 0x5d, 0xfa, 0xb6, 0x1f, 0xb6, 0x6e, 0x87, 0x02, 0xc1, 0x6b,
-	0x1a, 0xfe, 0x08, 0x1e, 0x8f, 0x26, 0xf1, 0x8d, 0x41, 0x57, 0xbe, 0xe2, 0x6e, 0x53, 0x1b, 0x79,
-	0x0f, 0x17, 0x10, 0xbc, 0x06, 0x3b, 0xd7, 0xee, 0xa0, 0x08, 0x76, 0x72, 0xe2, 0xbd, 0xe3, 0xc6,
-	0x39, 0xd2, 0x76, 0x7c, 0x35, 0x55, 0x38, 0x2c, 0xb3, 0x05, 0xab, 0xf4, 0x0f, 0x9f, 0x6d, 0xbf,
-	0x9e, 0x2d, 0x5c, 0xa7, 0x4d, 0x03, 0xbe, 0x07, 0x1d, 0x3f, 0x54, 0x6e, 0x8f, 0x3f, 0xeb, 0xb7,
-	0x51, 0x25, 0x28, 0x36, 0x48, 0x88, 0x1e, 0x3f, 0x03, 0x9d, 0x24, 0xb7, 0x98, 0x19, 0x25, 0x85,
-	0x61, 0xee, 0xe7, 0x88, 0x65, 0xd5, 0xcf, 0x17, 0xe0, 0x39, 0x66, 0x66, 0x29, 0xe8, 0x6a, 0xa4,
-	0xc7, 0xc1, 0x4b, 0xb7, 0xdc, 0x86, 0xb7, 0x44, 0x2c, 0xd8, 0x94, 0xe9, 0x3b, 0x4e, 0x19, 0x7c,
-	0x0b, 0xb6, 0x92, 0xdc, 0xc2, 0x17, 0x91, 0xfb, 0xdb, 0x11, 0xbc, 0x50, 0x0e, 0x8a, 0x91, 0xa0,
-	0x80, 0x43, 0x47, 0x2c, 0x7b, 0x10, 0x0d, 0x8a, 0xf7, 0x46, 0x45, 0xa9, 0x62, 0x02, 0xab, 0x52,
-	0x5d, 0xf0, 0xa4, 0x18, 0x68, 0x49, 0xf1, 0xca, 0x8f, 0xd4, 0x27, 0x9c, 0x3e, 0xf1, 0xaf, 0xba,
-	0xfe, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x5d, 0x39, 0xb4, 0xae, 0x13, 0x09, 0x00, 0x00,
+	// 888 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x96, 0xdf, 0x6e, 0xdb, 0x36,
+	0x14, 0xc6, 0x51, 0xa4, 0x2d, 0x16, 0xba, 0x6d, 0x5a, 0xb6, 0x4b, 0x98, 0x74, 0x1b, 0xbc, 0x60,
+	0xc5, 0xd2, 0x6d, 0x90, 0x31, 0x3b, 0x41, 0xdb, 0xa1, 0x03, 0x26, 0xdb, 0xf9, 0x63, 0x20, 0x30,
+	0x04, 0x3a, 0x18, 0x8a, 0xed, 0x62, 0xa0, 0x64, 0xda, 0x21, 0xa0, 0x91, 0x1c, 0x49, 0x65, 0xf1,
+	0xdd, 0x1e, 0x62, 0x0f, 0x3c, 0x90, 0x54, 0x64, 0x4a, 0x56, 0x76, 0x21, 0x43, 0x3a, 0xdf, 0xef,
+	0x3b, 0x87, 0x3c, 0x22, 0x29, 0x83, 0x6d, 0x25, 0xb3, 0x48, 0x2a, 0x61, 0x04, 0xdc, 0x52, 0x32,
+	0x3b, 0x40, 0x39, 0x5b, 0x12, 0x23, 0x7a, 0x37, 0x52, 0xf6, 0x48, 0x96, 0xdb, 0xcb, 0xcb, 0x75,
+	0x25, 0x5d, 0xd8, 0xab, 0x54, 0xde, 0x04, 0x0a, 0xe3, 0x86, 0xaa, 0x05, 0xc9, 0xa8, 0x5e, 0xdf,
+	0x96, 0x58, 0xb7, 0x1d, 0xd3, 0x92, 0xf0, 0x92, 0xf8, 0x3a, 0x20, 0xf2, 0x7e, 0x2f, 0x55, 0x6c,
+	0xbe, 0xa4, 0x7f, 0xcc, 0xc5, 0x9f, 0x84, 0xdd, 0x21, 0x7b, 0x75, 0x64, 0xc1, 0xd2, 0x52, 0xf8,
+	0xa2, 0x2e, 0xdc, 0x66, 0x82, 0x73, 0x9a, 0x99, 0x36, 0xdb, 0xa0, 0x47, 0x94, 0x2c, 0x85, 0xfd,
+	0xba, 0xa0, 0x44, 0x61, 0x68, 0xbb, 0xe7, 0x46, 0x2d, 0x5a, 0x3a, 0xc1, 0x89, 0xb1, 0x57, 0xa9,
+	0x1c, 0x04, 0x8a, 0x2c, 0xb8, 0x71, 0x3f, 0x2d, 0x03, 0x64, 0x52, 0xd3, 0xcc, 0xff, 0x96, 0xea,
+	0x6e, 0xbd, 0x58, 0x3e, 0x28, 0xe3, 0xdf, 0x96, 0xf1, 0x9c, 0xf1, 0xe2, 0xf6, 0xff, 0xba, 0xfb,
+	0x4d, 0x1d, 0x94, 0x86, 0xa4, 0x39, 0xd5, 0xd5, 0x4d, 0x63, 0xba, 0x9e, 0xaa, 0x75, 0xe2, 0x75,
+	0x53, 0x0a, 0x7a, 0x71, 0xf8, 0xcf, 0x13, 0xd0, 0x19, 0x13, 0x43, 0x30, 0xfd, 0xab, 0xa0, 0xda,
+	0xc0, 0x13, 0xd0, 0x89, 0xb3, 0x8c, 0x6a, 0x7d, 0xc9, 0xb4, 0xd1, 0x08, 0x74, 0xb7, 0x8e, 0x3a,
+	0xfd, 0x97, 0x91, 0x4f, 0x11, 0xdd, 0x48, 0x19, 0xd9, 0x85, 0x13, 0x8f, 0x2e, 0x71, 0xc8, 0x59,
+	0xdb, 0x99, 0x50, 0x7f, 0x13, 0x35, 0x67, 0x7c, 0xa9, 0xd1, 0x4e, 0x8b, 0x2d, 0x5d, 0x44, 0xf1,
+	0xf0, 0x0c, 0x87, 0x1c, 0xfc, 0x05, 0x80, 0x49, 0x35, 0x73, 0xf4, 0xca, 0xb9, 0xba, 0xa1, 0x6b,
+	0xdd, 0x97, 0xa8, 0x02, 0x71, 0xe0, 0x81, 0x3f, 0x82, 0x47, 0x33, 0x49, 0xb8, 0x46, 0x9f, 0x3b,
+	0xf3, 0xeb, 0x7b, 0xcc, 0x96, 0xc1, 0x9e, 0xb4, 0x45, 0x71, 0x91, 0xd3, 0xd1, 0x35, 0x61, 0x5c,
+	0xa3, 0xdd, 0x7a, 0x51, 0xd7, 0xa4, 0xa8, 0x6a, 0x6e, 0x05, 0xe2, 0xc0, 0x03, 0x4f, 0xc0, 0xf6,
+	0xaf, 0x6a, 0x71, 0xe5, 0x10, 0xb4, 0xe7, 0x12, 0xec, 0x85, 0x85, 0xf3, 0x41, 0x74, 0xa7, 0xe3,
+	0x35, 0x09, 0x2f, 0xc0, 0xce, 0x94, 0x98, 0xe3, 0xe3, 0x60, 0xca, 0xc8, 0x99, 0xbf, 0x0a, 0xcd,
+	0x76, 0xd1, 0xd5, 0x31, 0xdc, 0xb4, 0xc1, 0x29, 0x78, 0xe1, 0x42, 0xf1, 0x7c, 0xae, 0xa8, 0xd6,
+	0x89, 0x10, 0xb9, 0x46, 0xfb, 0x9b, 0xed, 0xab, 0x72, 0x05, 0x20, 0xde, 0xb4, 0xc2, 0x18, 0x3c,
+	0x1d, 0xba, 0x3d, 0x39, 0x76, 0x5b, 0x52, 0xa3, 0xa3, 0xcd, 0x6e, 0xe6, 0xfd, 0x28, 0x64, 0x70,
+	0xdd, 0x01, 0xbf, 0x07, 0x0f, 0xcf, 0x26, 0x43, 0x8d, 0xde, 0xb6, 0xb4, 0xa3, 0x1f, 0x9d, 0x4d,
+	0x86, 0xa7, 0xdc, 0xa8, 0x15, 0x76, 0x90, 0x7d, 0x6b, 0x9f, 0x46, 0x82, 0x6b, 0xf4, 0x5d, 0x6b,
+	0x9d, 0x4f, 0x23, 0xbf, 0xc7, 0x13, 0xc2, 0x14, 0xf6, 0x24, 0x7c, 0x0f, 0x9e, 0xcc, 0x0c, 0x31,
+	0x2c, 0xc3, 0x76, 0xf5, 0x6a, 0xd4, 0x77, 0xce, 0x57, 0x8d, 0xb6, 0x3b, 0x11, 0xd7, 0x48, 0xf8,
+	0x0e, 0x80, 0x58, 0x49, 0x5b, 0x9e, 0x51, 0x8d, 0x06, 0xad, 0xaf, 0x2b, 0xc6, 0x89, 0x1f, 0x5f,
+	0x80, 0xc2, 0x01, 0xf8, 0x2c, 0x51, 0xe2, 0x76, 0x15, 0x2b, 0x89, 0x8e, 0xbb, 0x0f, 0x5a, 0x6c,
+	0x5e, 0xc6, 0x09, 0xae, 0x40, 0x78, 0x0a, 0x9e, 0x4d, 0x92, 0x59, 0x46, 0xf8, 0x94, 0xb2, 0xe5,
+	0x75, 0x2a, 0x14, 0x3a, 0x71, 0xd6, 0x2f, 0x1b, 0xd6, 0x3a, 0x84, 0x1b, 0x26, 0xf8, 0x11, 0x3c,
+	0x9c, 0x25, 0x63, 0x8d, 0x3e, 0xba, 0xe1, 0x1e, 0x85, 0x66, 0x7f, 0xba, 0xcc, 0x68, 0x56, 0x28,
+	0x66, 0x56, 0x89, 0xc8, 0x59, 0xb6, 0xb2, 0x5b, 0x38, 0x25, 0x9a, 0x62, 0xe7, 0x82, 0xef, 0xc0,
+	0xd6, 0x2c, 0xd6, 0xe8, 0x67, 0x67, 0x7e, 0x73, 0xbf, 0x39, 0xd6, 0x5a, 0x64, 0x8c, 0x18, 0x26,
+	0x38, 0xb6, 0x0e, 0x78, 0x0a, 0x76, 0x26, 0x09, 0xa6, 0x73, 0xa6, 0x6c, 0xfb, 0x0b, 0x6e, 0x34,
+	0x8a, 0x37, 0x5f, 0x91, 0x3b, 0xfc, 0xd6, 0x1c, 0x6e, 0x7a, 0xe0, 0x07, 0xd0, 0xb9, 0x12, 0x17,
+	0x42, 0x97, 0x29, 0x86, 0x9b, 0x3d, 0x77, 0x29, 0x3c, 0x83, 0x43, 0x16, 0xfe, 0x04, 0xc0, 0xe9,
+	0x6d, 0x46, 0xa5, 0x1d, 0x93, 0x46, 0x23, 0xe7, 0x3c, 0xd8, 0x70, 0x56, 0x08, 0x0e, 0x68, 0xf8,
+	0x01, 0x6c, 0x4f, 0x89, 0x39, 0xcf, 0x45, 0x4a, 0x72, 0x74, 0xde, 0x7d, 0xd0, 0x1c, 0x77, 0xb5,
+	0x1d, 0x3c, 0x82, 0xd7, 0x34, 0xfc, 0x01, 0x3c, 0x1a, 0x4f, 0xe3, 0x2b, 0x8d, 0x2e, 0x5c, 0xc5,
+	0xdd, 0xa6, 0x6d, 0xec, 0x7c, 0xd8, 0x43, 0xf0, 0x12, 0xec, 0x5c, 0xda, 0x83, 0x22, 0xd8, 0xc9,
+	0x89, 0xf3, 0x1d, 0x36, 0xce, 0x91, 0xb6, 0xe3, 0xab, 0x69, 0x85, 0xa3, 0x32, 0x5b, 0xb0, 0x4a,
+	0x7f, 0x73, 0xd9, 0xf6, 0xeb, 0xd9, 0xc2, 0x75, 0xda, 0x74, 0xc0, 0xf7, 0xa0, 0xe3, 0x42, 0xe5,
+	0xf6, 0xf8, 0xbd, 0x3e, 0x8d, 0x2a, 0x81, 0x93, 0x71, 0x88, 0x1e, 0x3e, 0x05, 0x9d, 0xa4, 0x30,
+	0x98, 0x6a, 0x29, 0xb8, 0xa6, 0xf6, 0x71, 0x4c, 0xf3, 0xea, 0xf1, 0x39, 0x78, 0x86, 0xa9, 0x5e,
+	0xf1, 0xec, 0x2e, 0xd2, 0xff, 0xf7, 0x01, 0x78, 0x61, 0xd7, 0xdb, 0xe8, 0x9a, 0xf0, 0x25, 0x9d,
+	0x51, 0x75, 0xc3, 0x32, 0x0a, 0xdf, 0x82, 0xad, 0xa4, 0x30, 0xf0, 0x79, 0x64, 0xff, 0x77, 0x04,
+	0x5f, 0x94, 0x03, 0x1f, 0x09, 0x2a, 0x58, 0x74, 0x4c, 0xf3, 0x7b, 0xd1, 0xa0, 0xba, 0x45, 0xcf,
+	0xe9, 0xfd, 0x59, 0x83, 0x48, 0x7f, 0xec, 0x47, 0xe5, 0x07, 0x7b, 0x37, 0xaa, 0x1e, 0x78, 0xec,
+	0x03, 0x2d, 0x29, 0x5e, 0xba, 0x48, 0x7d, 0x72, 0xe9, 0x63, 0xf7, 0x59, 0x1c, 0xfc, 0x37, 0x00,
+	0x2c, 0x29, 0xa2, 0x46, 0x3f, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -448,6 +449,9 @@ type DataChangeServiceClient interface {
 	Put(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*PutResponse, error)
 	// Removes one or multiple configuration items
 	Del(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DelResponse, error)
+	// Returns currently configured values of the requested configuration items
+	// (items are identified by their keys, other attributes are ignored)
+	Get(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DataRequest, error)
 }
 
 type dataChangeServiceClient struct {
@@ -476,12 +480,24 @@ func (c *dataChangeServiceClient) Del(ctx context.Context, in *DataRequest, opts
 	return out, nil
 }
 
+func (c *dataChangeServiceClient) Get(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DataRequest, error) {
+	out := new(DataRequest)
+	err := c.cc.Invoke(ctx, "/rpc.DataChangeService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataChangeServiceServer is the server API for DataChangeService service.
 type DataChangeServiceServer interface {
 	// Creates or updates one or multiple configuration items
 	Put(context.Context, *DataRequest) (*PutResponse, error)
 	// Removes one or multiple configuration items
 	Del(context.Context, *DataRequest) (*DelResponse, error)
+	// Returns currently configured values of the requested configuration items
+	// (items are identified by their keys, other attributes are ignored)
+	Get(context.Context, *DataRequest) (*DataRequest, error)
 }
 
 // UnimplementedDataChangeServiceServer can be embedded to have forward compatible implementations.
@@ -494,6 +510,9 @@ func (*UnimplementedDataChangeServiceServer) Put(ctx context.Context, req *DataR
 func (*UnimplementedDataChangeServiceServer) Del(ctx context.Context, req *DataRequest) (*DelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Del not implemented")
 }
+func (*UnimplementedDataChangeServiceServer) Get(ctx context.Context, req *DataRequest) (*DataRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
 
 func RegisterDataChangeServiceServer(s *grpc.Server, srv DataChangeServiceServer) {
 	s.RegisterService(&_DataChangeService_serviceDesc, srv)
@@ -535,6 +554,24 @@ func _DataChangeService_Del_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataChangeService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataChangeServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.DataChangeService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataChangeServiceServer).Get(ctx, req.(*DataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _DataChangeService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpc.DataChangeService",
 	HandlerType: (*DataChangeServiceServer)(nil),
@@ -547,6 +584,10 @@ var _DataChangeService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Del",
 			Handler:    _DataChangeService_Del_Handler,
 		},
+		{
+			MethodName: "Get",
+			Handler:    _DataChangeService_Get_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
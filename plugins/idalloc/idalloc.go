@@ -125,8 +125,30 @@ func (a *IDAllocator) Update(event controller.Event, txn controller.UpdateOperat
 	return
 }
 
-// Revert is NOOP - never called.
+// CanRevert returns true for changes of allocation pools - Update only replaces
+// the cached pool, which can be restored from the previous value carried by the event.
+func (a *IDAllocator) CanRevert(event controller.Event) bool {
+	ksChange, isKSChange := event.(*controller.KubeStateChange)
+	return isKSChange && ksChange.Resource == idallocation.Keyword
+}
+
+// Revert restores the previous state of the changed allocation pool
+// (called after the dry-run of the change).
 func (a *IDAllocator) Revert(event controller.Event) error {
+	ksChange, isKSChange := event.(*controller.KubeStateChange)
+	if !isKSChange || ksChange.Resource != idallocation.Keyword {
+		return nil
+	}
+	if ksChange.NewValue != nil {
+		pool := ksChange.NewValue.(*idallocation.AllocationPool)
+		delete(a.poolCache, pool.Name)
+		delete(a.poolMeta, pool.Name)
+	}
+	if ksChange.PrevValue != nil {
+		pool := ksChange.PrevValue.(*idallocation.AllocationPool)
+		a.poolCache[pool.Name] = pool
+		a.poolMeta[pool.Name] = a.buildPoolMetadata(pool)
+	}
 	return nil
 }
 
@@ -244,6 +266,19 @@ func (a *IDAllocator) GetOrAllocateID(poolName string, idLabel string) (id uint3
 // ReleaseID releases existing allocation for given pool and label.
 // NOOP if the pool or allocation does not exist.
 func (a *IDAllocator) ReleaseID(poolName string, idLabel string) (err error) {
+	return a.releaseID(poolName, idLabel, false)
+}
+
+// ForceReleaseID releases existing allocation for given pool and label even if
+// it is owned by another agent (e.g. if the owner has left the cluster).
+// NOOP if the pool or allocation does not exist.
+func (a *IDAllocator) ForceReleaseID(poolName string, idLabel string) (err error) {
+	return a.releaseID(poolName, idLabel, true)
+}
+
+// releaseID releases existing allocation for given pool and label. Allocation
+// owned by another agent is released from the db only if <force> is true.
+func (a *IDAllocator) releaseID(poolName string, idLabel string, force bool) (err error) {
 
 	pool := a.poolCache[poolName]
 	if pool == nil {
@@ -254,10 +289,13 @@ func (a *IDAllocator) ReleaseID(poolName string, idLabel string) (err error) {
 		return
 	}
 	alloc := pool.IdAllocations[idLabel]
+	if alloc == nil {
+		return
+	}
 
 	succeeded := false
 	for i := 0; i < maxIDAllocationAttempts; i++ {
-		succeeded, err = a.tryToReleaseID(pool, poolMeta, idLabel)
+		succeeded, err = a.tryToReleaseID(pool, poolMeta, idLabel, force)
 		if err != nil {
 			break
 		}
@@ -336,15 +374,15 @@ func (a *IDAllocator) tryToAllocateID(pool *idallocation.AllocationPool, poolMet
 }
 
 // tryToReleaseID attempts to release an ID for given pool and label.
-func (a *IDAllocator) tryToReleaseID(pool *idallocation.AllocationPool, poolMeta *poolMetadata, idLabel string) (
-	succeeded bool, err error) {
+func (a *IDAllocator) tryToReleaseID(pool *idallocation.AllocationPool, poolMeta *poolMetadata, idLabel string,
+	force bool) (succeeded bool, err error) {
 
 	alloc, exists := pool.IdAllocations[idLabel]
 	if !exists {
 		// already released
 		return true, nil
 	}
-	if alloc.Owner != a.ServiceLabel.GetAgentLabel() {
+	if alloc.Owner != a.ServiceLabel.GetAgentLabel() && !force {
 		// we do not own this allocation, do not write into db
 		delete(pool.IdAllocations, idLabel)
 		return true, nil
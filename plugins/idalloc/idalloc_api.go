@@ -33,4 +33,9 @@ type API interface {
 	// ReleaseID releases existing allocation for given pool and label.
 	// NOOP if the allocation does not exist.
 	ReleaseID(poolName string, idLabel string) (err error)
+
+	// ForceReleaseID releases existing allocation for given pool and label even if
+	// it is owned by another agent (e.g. if the owner has left the cluster).
+	// NOOP if the allocation does not exist.
+	ForceReleaseID(poolName string, idLabel string) (err error)
 }
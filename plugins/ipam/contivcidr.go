@@ -46,12 +46,15 @@ func dissectContivCIDR(ipamConfig *contivconf.IPAMConfig) (subnets *contivconf.C
 	vxlanCIDR, _ := cidr.Subnet(contivCIDR, nodePrefixLength, 257)
 
 	subnets = &contivconf.CustomIPAMSubnets{
-		PodSubnetCIDR:                 podSubnetCIDR,
-		PodSubnetOneNodePrefixLen:     podSubnetOneNodePrefixLen,
-		VPPHostSubnetCIDR:             vppHostSubnetCIDR,
-		VPPHostSubnetOneNodePrefixLen: vppHostSubnetOneNodePrefixLen,
-		VxlanCIDR:                     vxlanCIDR,
-		NodeInterconnectCIDR:          ipamConfig.NodeInterconnectCIDR,
+		PodSubnetCIDR:                      podSubnetCIDR,
+		PodSubnetOneNodePrefixLen:          podSubnetOneNodePrefixLen,
+		AdditionalPodSubnetCIDRs:           ipamConfig.AdditionalPodSubnetCIDRs,
+		SecondaryPodSubnetCIDR:             ipamConfig.SecondaryPodSubnetCIDR,
+		SecondaryPodSubnetOneNodePrefixLen: ipamConfig.SecondaryPodSubnetOneNodePrefixLen,
+		VPPHostSubnetCIDR:                  vppHostSubnetCIDR,
+		VPPHostSubnetOneNodePrefixLen:      vppHostSubnetOneNodePrefixLen,
+		VxlanCIDR:                          vxlanCIDR,
+		NodeInterconnectCIDR:               ipamConfig.NodeInterconnectCIDR,
 	}
 
 	if subnets.NodeInterconnectCIDR == nil && ipamConfig.NodeInterconnectDHCP == false {
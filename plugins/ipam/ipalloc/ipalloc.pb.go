@@ -141,28 +141,96 @@ func (m *CustomIPAllocation) GetCustomInterfaces() []*CustomPodInterface {
 	return nil
 }
 
+// IPReservation represents pod IP address reserved either statically for a single pod
+// or under a name, which can be used by subsequent instances of the same pod (e.g. of a StatefulSet).
+type IPReservation struct {
+	IpAddress            string   `protobuf:"bytes,1,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PodName              string   `protobuf:"bytes,3,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
+	PodNamespace         string   `protobuf:"bytes,4,opt,name=pod_namespace,json=podNamespace,proto3" json:"pod_namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IPReservation) Reset()         { *m = IPReservation{} }
+func (m *IPReservation) String() string { return proto.CompactTextString(m) }
+func (*IPReservation) ProtoMessage()    {}
+func (*IPReservation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20954971669de07a, []int{2}
+}
+
+func (m *IPReservation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IPReservation.Unmarshal(m, b)
+}
+func (m *IPReservation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IPReservation.Marshal(b, m, deterministic)
+}
+func (m *IPReservation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IPReservation.Merge(m, src)
+}
+func (m *IPReservation) XXX_Size() int {
+	return xxx_messageInfo_IPReservation.Size(m)
+}
+func (m *IPReservation) XXX_DiscardUnknown() {
+	xxx_messageInfo_IPReservation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IPReservation proto.InternalMessageInfo
+
+func (m *IPReservation) GetIpAddress() string {
+	if m != nil {
+		return m.IpAddress
+	}
+	return ""
+}
+
+func (m *IPReservation) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *IPReservation) GetPodName() string {
+	if m != nil {
+		return m.PodName
+	}
+	return ""
+}
+
+func (m *IPReservation) GetPodNamespace() string {
+	if m != nil {
+		return m.PodNamespace
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*CustomPodInterface)(nil), "ipalloc.CustomPodInterface")
 	proto.RegisterType((*CustomIPAllocation)(nil), "ipalloc.CustomIPAllocation")
+	proto.RegisterType((*IPReservation)(nil), "ipalloc.IPReservation")
 }
 
 func init() { proto.RegisterFile("ipalloc.proto", fileDescriptor_20954971669de07a) }
 
 var fileDescriptor_20954971669de07a = []byte{
-	// 237 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0x41, 0x4a, 0x03, 0x31,
-	0x14, 0x86, 0x19, 0x5b, 0x9c, 0xf6, 0x69, 0xb1, 0xbe, 0x55, 0x44, 0x84, 0x52, 0x37, 0xe3, 0xa6,
-	0x0b, 0x3d, 0x41, 0x11, 0xc1, 0x6e, 0xa4, 0xcc, 0x05, 0x86, 0x98, 0x3c, 0x21, 0xd8, 0xe6, 0x3d,
-	0x92, 0xa8, 0x97, 0xf0, 0x0c, 0x9e, 0x55, 0x26, 0xcd, 0x14, 0xc1, 0x5d, 0xfe, 0xef, 0x27, 0xe1,
-	0xcb, 0x0f, 0x33, 0x27, 0x7a, 0xb7, 0x63, 0xb3, 0x92, 0xc0, 0x89, 0xb1, 0x2e, 0x71, 0xf9, 0x5d,
-	0x01, 0x3e, 0x7e, 0xc4, 0xc4, 0xfb, 0x2d, 0xdb, 0x8d, 0x4f, 0x14, 0xde, 0xb4, 0x21, 0x44, 0x18,
-	0x7b, 0xbd, 0x27, 0x55, 0x2d, 0xaa, 0x66, 0xda, 0xe6, 0x33, 0x2a, 0xa8, 0x3d, 0xa5, 0x2f, 0x0e,
-	0xef, 0xea, 0x24, 0xe3, 0x21, 0xe2, 0x0d, 0x80, 0x93, 0x4e, 0x5b, 0x1b, 0x28, 0x46, 0x35, 0xca,
-	0xe5, 0xd4, 0xc9, 0xfa, 0x00, 0xf0, 0x0e, 0xe6, 0x91, 0xc2, 0xa7, 0x33, 0xd4, 0x91, 0xb7, 0xc2,
-	0xce, 0x27, 0x35, 0x5e, 0x54, 0xcd, 0xa4, 0xbd, 0x28, 0xfc, 0xa9, 0xe0, 0xe5, 0xcf, 0x51, 0x67,
-	0xb3, 0x5d, 0xf7, 0x82, 0x3a, 0x39, 0xf6, 0x78, 0x05, 0x13, 0x61, 0xdb, 0xfd, 0x51, 0xaa, 0x85,
-	0xed, 0x4b, 0x6f, 0x75, 0x0b, 0xb3, 0xa1, 0x8a, 0xa2, 0x0d, 0x15, 0xb7, 0xf3, 0xd2, 0x67, 0x86,
-	0xcf, 0x70, 0x69, 0xf2, 0xab, 0x9d, 0x1b, 0xbe, 0xd8, 0x7b, 0x8e, 0x9a, 0xb3, 0xfb, 0xeb, 0xd5,
-	0xb0, 0xcc, 0xff, 0x19, 0xda, 0xf9, 0xe1, 0xd6, 0x11, 0xc4, 0xd7, 0xd3, 0xbc, 0xdf, 0xc3, 0x6f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x2e, 0x45, 0xdf, 0xe5, 0x50, 0x01, 0x00, 0x00,
+	// 266 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0xc1, 0x4a, 0xc4, 0x30,
+	0x10, 0x86, 0xc9, 0xb6, 0xd8, 0xdd, 0xd1, 0xe2, 0x9a, 0x53, 0x45, 0x84, 0xa5, 0x5e, 0xea, 0x65,
+	0x0f, 0xfa, 0x04, 0x8b, 0x08, 0xf6, 0x22, 0xa5, 0x2f, 0x50, 0x62, 0x33, 0x42, 0x70, 0x37, 0x13,
+	0x9a, 0xa8, 0x77, 0xcf, 0x3e, 0x83, 0xcf, 0x2a, 0x4d, 0x9b, 0xa5, 0xba, 0x87, 0xbd, 0x65, 0xfe,
+	0x21, 0xe1, 0xfb, 0xfe, 0x40, 0xaa, 0x8c, 0xd8, 0x6e, 0xa9, 0x5d, 0x9b, 0x8e, 0x1c, 0xf1, 0x64,
+	0x1c, 0xf3, 0x6f, 0x06, 0xfc, 0xe1, 0xdd, 0x3a, 0xda, 0x55, 0x24, 0x4b, 0xed, 0xb0, 0x7b, 0x15,
+	0x2d, 0x72, 0x0e, 0xb1, 0x16, 0x3b, 0xcc, 0xd8, 0x8a, 0x15, 0x8b, 0xda, 0x9f, 0x79, 0x06, 0x89,
+	0x46, 0xf7, 0x49, 0xdd, 0x5b, 0x36, 0xf3, 0x71, 0x18, 0xf9, 0x35, 0x80, 0x32, 0x8d, 0x90, 0xb2,
+	0x43, 0x6b, 0xb3, 0xc8, 0x2f, 0x17, 0xca, 0x6c, 0x86, 0x80, 0xdf, 0xc2, 0xd2, 0x62, 0xf7, 0xa1,
+	0x5a, 0x6c, 0x50, 0x4b, 0x43, 0x4a, 0xbb, 0x2c, 0x5e, 0xb1, 0x62, 0x5e, 0x9f, 0x8f, 0xf9, 0xe3,
+	0x18, 0xe7, 0x3f, 0x7b, 0x9c, 0xb2, 0xda, 0xf4, 0x80, 0xc2, 0x29, 0xd2, 0xfc, 0x12, 0xe6, 0x86,
+	0x64, 0x33, 0x41, 0x4a, 0x0c, 0xc9, 0xe7, 0x9e, 0xea, 0x06, 0xd2, 0xb0, 0xb2, 0x46, 0xb4, 0x38,
+	0xb2, 0x9d, 0x8d, 0x7b, 0x9f, 0xf1, 0x27, 0xb8, 0x68, 0xfd, 0xab, 0x8d, 0x0a, 0x8a, 0x3d, 0x67,
+	0x54, 0x9c, 0xde, 0x5d, 0xad, 0x43, 0x33, 0x87, 0x35, 0xd4, 0xcb, 0xe1, 0xd6, 0x3e, 0xb0, 0xf9,
+	0x17, 0x83, 0xb4, 0xac, 0x6a, 0xec, 0xc1, 0x07, 0xb6, 0xbf, 0xf2, 0xec, 0xbf, 0x7c, 0x68, 0x72,
+	0x36, 0x69, 0x72, 0xaa, 0x13, 0x1d, 0xd1, 0x89, 0x0f, 0x75, 0x5e, 0x4e, 0xfc, 0x27, 0xde, 0xff,
+	0x0e, 0x00, 0x91, 0x51, 0xcb, 0x69, 0xd5, 0x01, 0x00, 0x00,
 }
@@ -42,3 +42,17 @@ func ParseKey(key string) (podName, podNamespace string) {
 	}
 	return
 }
+
+// ReservationKeyword defines the keyword identifying pod IP reservations.
+const ReservationKeyword = "ip-reservation"
+
+// ReservationKeyPrefix returns prefix where all pod IP reservations are persisted.
+func ReservationKeyPrefix() string {
+	return ReservationKeyword + "/"
+}
+
+// ReservationKey returns the key under which reservation of the given IP address
+// should be stored in the data-store.
+func ReservationKey(ipAddress string) string {
+	return ReservationKeyPrefix() + ipAddress
+}
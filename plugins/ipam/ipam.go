@@ -26,17 +26,21 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	"github.com/americanbinary/vpp/plugins/contivconf/config"
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	customnetmodel "github.com/americanbinary/vpp/plugins/crd/handler/customnetwork/model"
 	extifmodel "github.com/americanbinary/vpp/plugins/crd/handler/externalinterface/model"
+	"github.com/americanbinary/vpp/plugins/idalloc"
+	"github.com/americanbinary/vpp/plugins/idalloc/idallocation"
 	"github.com/americanbinary/vpp/plugins/ipam/ipalloc"
 	"github.com/americanbinary/vpp/plugins/ksr"
 	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"github.com/americanbinary/vpp/plugins/nodesync"
+	"github.com/americanbinary/vpp/plugins/podmanager"
 	"github.com/apparentlymart/go-cidr/cidr"
 	cnisb "github.com/containernetworking/cni/pkg/types/current"
 	"github.com/go-errors/errors"
@@ -63,13 +67,21 @@ const (
 type IPAM struct {
 	Deps
 
-	mutex    sync.RWMutex
-	dbBroker keyval.ProtoBroker
+	mutex          sync.RWMutex
+	dbBroker       keyval.ProtoBroker
+	atomicDBBroker keyval.BytesBrokerWithAtomic
+	serializer     keyval.SerializerJSON
 
 	excludedIPsfromNodeSubnet []net.IP // IPs from the NodeInterconnect Subnet that should not be assigned
 
 	/********** POD related variables **********/
 	podNetworks map[string]*podNetworkInfo
+	// index of the pod subnet leased by this node (dynamic pod subnet allocation only)
+	podSubnetIndex uint32
+	// node ID -> index of the pod subnet leased by the node (dynamic pod subnet allocation only)
+	podSubnetLeases map[uint32]uint32
+	// true once the lease of this node has been reflected back from the KV DB
+	podSubnetLeaseReflected bool
 
 	/********** maps to convert between Pod and the assigned IP **********/
 	// pool of assigned POD IP addresses
@@ -80,6 +92,25 @@ type IPAM struct {
 	remotePodToIP map[podmodel.ID]*podIPInfo
 	// IP information about external interfaces
 	extIfToIPNet map[string][]extIfIPInfo
+	// reserved IP address -> reservation (static pod IPs and named IP reservations)
+	reservations map[string]*ipalloc.IPReservation
+	// IP address -> when the pod holding the static reservation was found gone
+	staleReservations map[string]time.Time
+
+	/********** garbage collection of leaked pod IPs **********/
+	// pod -> IPs allocated for the pod which no longer exists (within the grace period)
+	leakedIPs map[podmodel.ID]*leakedPodIPs
+	// total count of leaked IPs released by the garbage collection
+	reclaimedIPCount int
+	// closed to stop the periodic garbage collection
+	stopGC chan struct{}
+	wg     sync.WaitGroup
+
+	/********** utilization of IP address blocks **********/
+	// gauges with the utilization, nil if metrics are not exported
+	usageMetrics *ipUsageMetrics
+	// subnets of blocks with utilization above the warning threshold (already reported)
+	usageExceeded map[string]bool
 
 	/********** VSwitch related variables **********/
 	// IP subnet used across all nodes for VPP to host Linux stack interconnect
@@ -111,6 +142,31 @@ type podNetworkInfo struct {
 	podSubnetGatewayIP net.IP
 	// counter denoting last assigned pod IP address
 	lastPodIPAssigned int
+	// additional pools of pod IP addresses in the order of preference, used
+	// once the subnet of this node from podSubnetAllNodes is exhausted
+	additionalPools []*podSubnetPool
+	// index (node ID or pod subnet lease) used to dissect pod subnets of this node
+	subnetIndex uint32
+	// pool of pod IP addresses of the other IP family (dual-stack only, nil otherwise),
+	// every pod is allocated one IP address from the pool in addition to the main one
+	secondaryPool *podSubnetPool
+	// gateway IP address of the secondary pod subnet of this node (dual-stack only)
+	secondaryGatewayIP net.IP
+}
+
+// podSubnetPool is an additional pool of pod IP addresses (non-contiguous with
+// the main pod subnet), dissected into per-node subnets the same way as the main
+// pod subnet.
+type podSubnetPool struct {
+	// IP subnet of the pool for all PODs across all nodes
+	subnetAllNodes *net.IPNet
+	// prefix length of the per-node subnets dissected from the pool
+	oneNodePrefixLen uint8
+	// IP subnet from the pool for PODs on this node (given by nodeID),
+	// nil until the pool is activated (additional pools only)
+	subnetThisNode *net.IPNet
+	// counter denoting last assigned pod IP address from the pool
+	lastIPAssigned int
 }
 
 // podIPAllocation represents allocation of an IP address from the IPAM pool.
@@ -125,6 +181,7 @@ type podIPAllocation struct {
 // podIPInfo holds the IP address allocation info related to a pod.
 type podIPInfo struct {
 	mainIP      net.IP            // IP address of the main interface
+	secondaryIP net.IP            // IP address of the main interface from the other IP family (dual-stack only)
 	customIfIPs map[string]net.IP // custom interface name + network to IP address map
 }
 
@@ -137,8 +194,167 @@ type extIfIPInfo struct {
 
 // String provides human-readable representation of podNetworkInfo
 func (i *podNetworkInfo) String() string {
-	return fmt.Sprintf("<podSubnetAllNodes=%v, podSubnetThisNode=%v, podSubnetGatewayIP=%v, lastPodIPAssigned=%d>",
+	str := fmt.Sprintf("podSubnetAllNodes=%v, podSubnetThisNode=%v, podSubnetGatewayIP=%v, lastPodIPAssigned=%d",
 		i.podSubnetAllNodes, i.podSubnetThisNode, i.podSubnetGatewayIP, i.lastPodIPAssigned)
+	if len(i.additionalPools) > 0 {
+		str += fmt.Sprintf(", additionalPools=%v", i.additionalPools)
+	}
+	if i.secondaryPool != nil {
+		str += fmt.Sprintf(", secondaryPool=%v, secondaryGatewayIP=%v", i.secondaryPool, i.secondaryGatewayIP)
+	}
+	return "<" + str + ">"
+}
+
+// String provides human-readable representation of podSubnetPool
+func (p *podSubnetPool) String() string {
+	return fmt.Sprintf("<subnetAllNodes=%v, subnetThisNode=%v, lastIPAssigned=%d>",
+		p.subnetAllNodes, p.subnetThisNode, p.lastIPAssigned)
+}
+
+// pools returns the additional pools followed by the secondary pool (dual-stack only).
+func (i *podNetworkInfo) pools() []*podSubnetPool {
+	pools := i.additionalPools
+	if i.secondaryPool != nil {
+		pools = append(pools[:len(pools):len(pools)], i.secondaryPool)
+	}
+	return pools
+}
+
+// subnetsAllNodes returns all pod subnets (across all nodes) of the network,
+// starting with the main pod subnet and ending with the secondary pod subnet
+// (dual-stack only).
+func (i *podNetworkInfo) subnetsAllNodes() []*net.IPNet {
+	subnets := []*net.IPNet{i.podSubnetAllNodes}
+	for _, pool := range i.pools() {
+		subnets = append(subnets, pool.subnetAllNodes)
+	}
+	return subnets
+}
+
+// subnetsThisNode returns all pod subnets of this node in the network,
+// starting with the subnet from the main pod subnet and ending with the subnet
+// from the secondary pod subnet (dual-stack only).
+// Additional pools not activated yet are skipped.
+func (i *podNetworkInfo) subnetsThisNode() []*net.IPNet {
+	subnets := []*net.IPNet{i.podSubnetThisNode}
+	for _, pool := range i.pools() {
+		if pool.subnetThisNode != nil {
+			subnets = append(subnets, pool.subnetThisNode)
+		}
+	}
+	return subnets
+}
+
+// subnetsForIndex returns all pod subnets of the network dissected for the node
+// with the given index (node ID or pod subnet lease), in the same order
+// as returned by subnetsAllNodes.
+func (i *podNetworkInfo) subnetsForIndex(index uint32) (subnets []*net.IPNet, err error) {
+	oneNodePrefixLen, _ := i.podSubnetThisNode.Mask.Size()
+	subnet, err := dissectSubnetForNode(i.podSubnetAllNodes, uint8(oneNodePrefixLen), index)
+	if err != nil {
+		return nil, err
+	}
+	subnets = append(subnets, subnet)
+	for _, pool := range i.pools() {
+		subnet, err := dissectSubnetForNode(pool.subnetAllNodes, pool.oneNodePrefixLen, index)
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, subnet)
+	}
+	return subnets, nil
+}
+
+// poolSubnetThisNode returns the subnet of this node from the given pool,
+// dissected even if the pool has not been activated yet.
+func (i *podNetworkInfo) poolSubnetThisNode(pool *podSubnetPool) *net.IPNet {
+	if pool.subnetThisNode != nil {
+		return pool.subnetThisNode
+	}
+	subnet, err := dissectSubnetForNode(pool.subnetAllNodes, pool.oneNodePrefixLen, i.subnetIndex)
+	if err != nil {
+		return nil
+	}
+	return subnet
+}
+
+// activatePool starts using the subnet of this node from the given pool
+// for pod IP allocations. Returns false if the pool was already activated.
+func (i *podNetworkInfo) activatePool(pool *podSubnetPool) (activated bool, err error) {
+	if pool.subnetThisNode != nil {
+		return false, nil
+	}
+	pool.subnetThisNode, err = dissectSubnetForNode(pool.subnetAllNodes, pool.oneNodePrefixLen, i.subnetIndex)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isLocalIP returns true if the IP address belongs to any pod subnet of this node,
+// including subnets from additional pools not activated yet.
+func (i *podNetworkInfo) isLocalIP(ip net.IP) bool {
+	if i.podSubnetThisNode.Contains(ip) {
+		return true
+	}
+	for _, pool := range i.pools() {
+		if subnet := i.poolSubnetThisNode(pool); subnet != nil && subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPodIP returns true if the IP address belongs to any pod subnet of the network
+// (from any node).
+func (i *podNetworkInfo) isPodIP(ip net.IP) bool {
+	for _, subnet := range i.subnetsAllNodes() {
+		if subnet != nil && subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// markAsAssigned updates the counter of the last assigned IP address for the pod
+// subnet of this node which the given (already allocated) IP belongs to.
+// Additional pool with the IP is activated if it was not already.
+func (i *podNetworkInfo) markAsAssigned(ip net.IP) {
+	updateCounter := func(subnet *net.IPNet, lastAssigned *int) {
+		addr := new(big.Int).SetBytes(ip)
+		diff := int(addr.Sub(addr, new(big.Int).SetBytes(subnet.IP)).Int64())
+		if *lastAssigned < diff {
+			*lastAssigned = diff
+		}
+	}
+	if i.podSubnetThisNode.Contains(ip) {
+		updateCounter(i.podSubnetThisNode, &i.lastPodIPAssigned)
+		return
+	}
+	for _, pool := range i.pools() {
+		if subnet := i.poolSubnetThisNode(pool); subnet != nil && subnet.Contains(ip) {
+			pool.subnetThisNode = subnet
+			updateCounter(pool.subnetThisNode, &pool.lastIPAssigned)
+			return
+		}
+	}
+}
+
+// isSecondaryIP returns true if the IP address belongs to the secondary pod subnet
+// of the network (from any node).
+func (i *podNetworkInfo) isSecondaryIP(ip net.IP) bool {
+	return i.secondaryPool != nil && i.secondaryPool.subnetAllNodes.Contains(ip)
+}
+
+// secondaryPodIP returns IP address of the given pod from the secondary pod subnet
+// of the network (dual-stack only), nil if the pod has none (yet).
+func (i *podNetworkInfo) secondaryPodIP(pod *podmodel.Pod) net.IP {
+	for _, ipStr := range pod.IpAddresses {
+		if ip := parseIP(ipStr); ip != nil && i.isSecondaryIP(ip) {
+			return ip
+		}
+	}
+	return nil
 }
 
 // String provides human-readable representation of podIPAllocation
@@ -151,10 +367,13 @@ func (a *podIPAllocation) String() string {
 
 // String provides human-readable representation of podIPInfo
 func (i *podIPInfo) String() string {
-	if len(i.customIfIPs) == 0 {
+	if len(i.customIfIPs) == 0 && i.secondaryIP == nil {
 		return fmt.Sprintf("<IP=%s>", i.mainIP)
 	}
-	return fmt.Sprintf("<mainIP=%s, customIPs=%+v>", i.mainIP, i.customIfIPs)
+	if i.secondaryIP == nil {
+		return fmt.Sprintf("<mainIP=%s, customIPs=%+v>", i.mainIP, i.customIfIPs)
+	}
+	return fmt.Sprintf("<mainIP=%s, secondaryIP=%s, customIPs=%+v>", i.mainIP, i.secondaryIP, i.customIfIPs)
 }
 
 // String provides human-readable representation of extIfIPInfo
@@ -167,43 +386,85 @@ type Deps struct {
 	infra.PluginDeps
 	NodeSync     nodesync.API
 	ContivConf   contivconf.API
+	IDAlloc      idalloc.API // used only with dynamic pod subnet allocation
+	PodManager   podmanager.API
+	Stats        StatsCollector // optional, used to export IPAM metrics
 	ServiceLabel servicelabel.ReaderAPI
 	EventLoop    controller.EventLoop
 	HTTPHandlers rest.HTTPHandlers
 	RemoteDB     nodesync.KVDBWithAtomic
 }
 
-// Init initializes the REST handlers of the plugin.
+// Init initializes the REST handlers and metrics of the plugin.
 func (i *IPAM) Init() (err error) {
+	i.stopGC = make(chan struct{})
 
 	// register REST handlers
 	i.registerRESTHandlers()
 
+	// export metrics
+	i.registerGauges()
+	i.registerIPUsageMetrics()
+
+	return nil
+}
+
+// AfterInit starts the periodic garbage collection of leaked pod IP addresses.
+func (i *IPAM) AfterInit() error {
+	ipamConfig := i.ContivConf.GetIPAMConfig()
+	if ipamConfig.IPLeakGCInterval > 0 && !ipamConfig.UseExternalIPAM {
+		i.wg.Add(1)
+		go i.periodicPodIPGC(ipamConfig.IPLeakGCInterval)
+	}
 	return nil
 }
 
 // HandlesEvent selects:
 //   - any Resync event
 //   - NodeUpdate for the current node if external IPAM is in use (may trigger PodCIDRChange)
+//   - NodeUpdate for a node which has left the cluster if pod subnets are leased dynamically
+//   - change of pod subnet leases if pod subnets are leased dynamically (may trigger PodSubnetLeaseChange)
 //   - VNI allocation
 //   - custom network update
+//   - change of IP reservations
+//   - periodic garbage collection of leaked pod IPs
+//   - DeletePod for a leaked pod
 func (i *IPAM) HandlesEvent(event controller.Event) bool {
 	if event.Method() != controller.Update {
 		return true
 	}
 
+	if _, isGC := event.(*PodIPGarbageCollection); isGC {
+		return true
+	}
+
+	if deletePod, isDeletePod := event.(*podmanager.DeletePod); isDeletePod {
+		return deletePod.Leaked
+	}
+
 	if i.ContivConf.GetIPAMConfig().UseExternalIPAM {
 		if nodeUpdate, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
 			return nodeUpdate.NodeName == i.ServiceLabel.GetAgentLabel()
 		}
 	}
 
+	if i.ContivConf.GetIPAMConfig().DynamicPodSubnetAllocation {
+		if nodeUpdate, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
+			return nodeUpdate.NewState == nil && nodeUpdate.PrevState != nil &&
+				nodeUpdate.NodeName != i.ServiceLabel.GetAgentLabel()
+		}
+	}
+
 	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange {
 		switch ksChange.Resource {
+		case idallocation.Keyword:
+			return i.ContivConf.GetIPAMConfig().DynamicPodSubnetAllocation
 		case customnetmodel.Keyword:
 			return true
 		case ipalloc.Keyword:
 			return true
+		case ipalloc.ReservationKeyword:
+			return true
 		case podmodel.PodKeyword:
 			return true
 		case extifmodel.Keyword:
@@ -263,7 +524,18 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 			return err
 		}
 	}
-	if err := i.initializePodNetwork(kubeStateData, subnets, nodeID); err != nil {
+	podSubnetIndex := nodeID
+	if ipamConfig.DynamicPodSubnetAllocation {
+		podSubnetIndex, err = i.leasePodSubnet(subnets, nodeID)
+		if err != nil {
+			return err
+		}
+		i.podSubnetLeases = leasesFromKubeState(kubeStateData, i.NodeSync.GetAllNodes())
+		i.podSubnetLeaseReflected = i.podSubnetLeases[nodeID] == podSubnetIndex
+		i.podSubnetLeases[nodeID] = podSubnetIndex
+	}
+	i.podSubnetIndex = podSubnetIndex
+	if err := i.initializePodNetwork(kubeStateData, subnets, podSubnetIndex); err != nil {
 		return err
 	}
 	if err := i.initializeVPPHostNetwork(subnets, nodeID); err != nil {
@@ -286,32 +558,37 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 
 	// resync allocated IP addresses (main pod interfaces)
 	podNw := i.podNetworks[defaultPodNetworkName]
-	networkPrefix := new(big.Int).SetBytes(podNw.podSubnetThisNode.IP)
 
 	for _, podProto := range kubeStateData[podmodel.PodKeyword] {
 		pod := podProto.(*podmodel.Pod)
 		podID := podmodel.ID{Name: pod.Name, Namespace: pod.Namespace}
 		podIPAddress := net.ParseIP(pod.IpAddress)
+		secondaryIPAddress := podNw.secondaryPodIP(pod)
 		// ignore pods without IP address
 		if podIPAddress != nil {
-			if podNw.podSubnetThisNode.Contains(podIPAddress) { // local pod
-				// register address as already allocated
-				addr := new(big.Int).SetBytes(podIPAddress)
+			if podNw.isLocalIP(podIPAddress) { // local pod
+				// register addresses as already allocated
 				i.assignedPodIPs[podIPAddress.String()] = &podIPAllocation{
 					pod:    podID,
 					mainIP: true,
 				}
 				i.podToIP[podID] = &podIPInfo{
 					mainIP:      podIPAddress,
+					secondaryIP: secondaryIPAddress,
 					customIfIPs: map[string]net.IP{},
 				}
-				diff := int(addr.Sub(addr, networkPrefix).Int64())
-				if podNw.lastPodIPAssigned < diff {
-					podNw.lastPodIPAssigned = diff
+				podNw.markAsAssigned(podIPAddress)
+				if secondaryIPAddress != nil {
+					i.assignedPodIPs[secondaryIPAddress.String()] = &podIPAllocation{
+						pod:    podID,
+						mainIP: true,
+					}
+					podNw.markAsAssigned(secondaryIPAddress)
 				}
-			} else if podNw.podSubnetAllNodes.Contains(podIPAddress) { // remote pod
+			} else if podNw.isPodIP(podIPAddress) { // remote pod
 				i.remotePodToIP[podID] = &podIPInfo{
 					mainIP:      podIPAddress,
+					secondaryIP: secondaryIPAddress,
 					customIfIPs: map[string]net.IP{},
 				}
 				// NOTE: ignoring pods outside of all pod subnets (across all nodes and networks)
@@ -325,6 +602,9 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 		i.updateExtIfIPInfo(extIfProto.(*extifmodel.ExternalInterface), false)
 	}
 
+	// IP reservations
+	i.resyncReservations(kubeStateData)
+
 	// resync custom interface IP allocations
 	for _, ipAllocProto := range kubeStateData[ipalloc.Keyword] {
 		ipAlloc := ipAllocProto.(*ipalloc.CustomIPAllocation)
@@ -335,15 +615,13 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 				i.Log.Warnf("Missing subnet information for the pod network %s, skipping", customAlloc.Network)
 				continue
 			}
-			networkPrefix := new(big.Int).SetBytes(podNw.podSubnetThisNode.IP)
 
 			podID := podmodel.ID{Name: ipAlloc.PodName, Namespace: ipAlloc.PodNamespace}
 			podIPAddress := net.ParseIP(customAlloc.IpAddress)
 			// ignore pods without IP address
 			if podIPAddress != nil {
-				if podNw.podSubnetThisNode.Contains(podIPAddress) { // local pod
+				if podNw.isLocalIP(podIPAddress) { // local pod
 					// register address as already allocated
-					addr := new(big.Int).SetBytes(podIPAddress)
 					i.assignedPodIPs[podIPAddress.String()] = &podIPAllocation{
 						pod:             podID,
 						mainIP:          false,
@@ -356,11 +634,8 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 						}
 					}
 					i.podToIP[podID].customIfIPs[customIfID(customAlloc.Name, customAlloc.Network)] = podIPAddress
-					diff := int(addr.Sub(addr, networkPrefix).Int64())
-					if podNw.lastPodIPAssigned < diff {
-						podNw.lastPodIPAssigned = diff
-					}
-				} else if podNw.podSubnetAllNodes.Contains(podIPAddress) { // remote pod
+					podNw.markAsAssigned(podIPAddress)
+				} else if podNw.isPodIP(podIPAddress) { // remote pod
 					i.remotePodToIP[podID].customIfIPs[customIfID(customAlloc.Name, customAlloc.Network)] = podIPAddress
 				}
 				// NOTE: ignoring pods outside of all pod subnets (across all nodes and networks)
@@ -369,20 +644,26 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 	}
 
 	i.Log.Infof("IPAM state after startup RESYNC: "+
-		"podNetworks=%+v, excludedIPsfromNodeSubnet=%v, hostInterconnectSubnetAllNodes=%v, "+
+		"podNetworks=%+v, podSubnetIndex=%d, podSubnetLeases=%v, "+
+		"excludedIPsfromNodeSubnet=%v, hostInterconnectSubnetAllNodes=%v, "+
 		"hostInterconnectSubnetThisNode=%v, hostInterconnectIPInVpp=%v, hostInterconnectIPInLinux=%v, "+
 		"nodeInterconnectSubnet=%v, vxlanSubnet=%v, serviceCIDR=%v, "+
-		"assignedPodIPs=%+v, podToIP=%v, remotePodToIP=%+v, extIfToIPNet=%+v",
-		i.podNetworks, i.excludedIPsfromNodeSubnet, i.hostInterconnectSubnetAllNodes,
+		"assignedPodIPs=%+v, podToIP=%v, remotePodToIP=%+v, extIfToIPNet=%+v, reservations=%v",
+		i.podNetworks, i.podSubnetIndex, i.podSubnetLeases,
+		i.excludedIPsfromNodeSubnet, i.hostInterconnectSubnetAllNodes,
 		i.hostInterconnectSubnetThisNode, i.hostInterconnectIPInVpp, i.hostInterconnectIPInLinux,
 		i.nodeInterconnectSubnet, i.vxlanSubnet, i.serviceCIDR,
-		i.assignedPodIPs, i.podToIP, i.remotePodToIP, i.extIfToIPNet)
+		i.assignedPodIPs, i.podToIP, i.remotePodToIP, i.extIfToIPNet, i.reservations)
+
+	i.checkIPUsage()
 	return
 }
 
 // initializePodNetwork initializes pod network -related variables.
+// Pod subnets of this node are dissected using <podSubnetIndex>, which is either
+// the node ID or the index of the leased pod subnet.
 func (i *IPAM) initializePodNetwork(kubeStateData controller.KubeStateData, config *contivconf.CustomIPAMSubnets,
-	nodeID uint32) (err error) {
+	podSubnetIndex uint32) (err error) {
 
 	// init pod IP maps
 	i.assignedPodIPs = make(map[string]*podIPAllocation)
@@ -417,9 +698,9 @@ func (i *IPAM) initializePodNetwork(kubeStateData controller.KubeStateData, conf
 			return
 		}
 	} else {
-		// pod subnet based on node ID
+		// pod subnet based on node ID (or leased pod subnet)
 		podNw.podSubnetThisNode, err = dissectSubnetForNode(
-			podNw.podSubnetAllNodes, config.PodSubnetOneNodePrefixLen, nodeID)
+			podNw.podSubnetAllNodes, config.PodSubnetOneNodePrefixLen, podSubnetIndex)
 		if err != nil {
 			return
 		}
@@ -430,6 +711,37 @@ func (i *IPAM) initializePodNetwork(kubeStateData controller.KubeStateData, conf
 		return nil
 	}
 	podNw.lastPodIPAssigned = 1
+	podNw.subnetIndex = podSubnetIndex
+
+	// additional pod subnets, dissected the same way as the main pod subnet,
+	// but only once the preceding subnets of this node are exhausted (see allocateIP)
+	oneNodePrefixLen, _ := podNw.podSubnetThisNode.Mask.Size()
+	for _, podSubnet := range config.AdditionalPodSubnetCIDRs {
+		podNw.additionalPools = append(podNw.additionalPools, &podSubnetPool{
+			subnetAllNodes:   podSubnet,
+			oneNodePrefixLen: uint8(oneNodePrefixLen),
+			lastIPAssigned:   1,
+		})
+	}
+
+	// secondary pod subnet of the other IP family (dual-stack)
+	if config.SecondaryPodSubnetCIDR != nil {
+		pool := &podSubnetPool{
+			subnetAllNodes:   config.SecondaryPodSubnetCIDR,
+			oneNodePrefixLen: config.SecondaryPodSubnetOneNodePrefixLen,
+			lastIPAssigned:   1,
+		}
+		pool.subnetThisNode, err = dissectSubnetForNode(
+			config.SecondaryPodSubnetCIDR, pool.oneNodePrefixLen, podSubnetIndex)
+		if err != nil {
+			return err
+		}
+		podNw.secondaryGatewayIP, err = cidr.Host(pool.subnetThisNode, podGatewaySeqID)
+		if err != nil {
+			return err
+		}
+		podNw.secondaryPool = pool
+	}
 
 	return nil
 }
@@ -476,11 +788,43 @@ func (i *IPAM) initializeVPPHostNetwork(config *contivconf.CustomIPAMSubnets, no
 	return
 }
 
-// Update handles NodeUpdate event in case that external IPAM is in use.
+// Update handles NodeUpdate event in case that external IPAM is in use or pod subnets
+// are leased dynamically, Kubernetes state changes and the garbage collection of leaked
+// pod IPs.
 func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (changeDescription string, err error) {
 
+	if gc, isGC := event.(*PodIPGarbageCollection); isGC {
+		now := time.Now()
+		for _, pod := range i.detectLeakedPods(now, gc.RunningPods) {
+			// un-configure the pod first, IPs are released afterwards
+			err := i.EventLoop.PushEvent(podmanager.NewDeletePodEventForLeakedPod(pod, i.PodManager))
+			if err != nil {
+				i.Log.Warnf("Failed to remove leaked pod %v: %v", pod.ID, err)
+			}
+		}
+		if removed := i.collectStaleReservations(now); removed > 0 {
+			return fmt.Sprintf("removed %d stale IP reservations", removed), nil
+		}
+		return "", nil
+	}
+
+	if deletePod, isDeletePod := event.(*podmanager.DeletePod); isDeletePod {
+		// leaked pod has been un-configured
+		if reclaimed := i.releaseLeakedPodIPs(deletePod.Pod); len(reclaimed) > 0 {
+			return fmt.Sprintf("reclaimed %d leaked pod IPs", len(reclaimed)), nil
+		}
+		return "", nil
+	}
+
 	if nodeUpdate, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
-		if nodeUpdate.NodeName == i.ServiceLabel.GetAgentLabel() {
+		if nodeUpdate.NewState == nil && i.ContivConf.GetIPAMConfig().DynamicPodSubnetAllocation {
+			// node has left the cluster, release its pod subnet
+			if err := i.releasePodSubnet(nodeUpdate.PrevState); err != nil {
+				i.Log.Warn(err)
+			}
+			return "release pod subnet lease", nil
+		}
+		if nodeUpdate.NodeName == i.ServiceLabel.GetAgentLabel() && i.ContivConf.GetIPAMConfig().UseExternalIPAM {
 			if nodeUpdate.NewState.PodCIDR != nodeUpdate.PrevState.PodCIDR {
 				i.EventLoop.PushEvent(&PodCIDRChange{
 					LocalPodCIDR: nodeUpdate.NewState.PodCIDR,
@@ -492,6 +836,17 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 
 	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange {
 		switch ksChange.Resource {
+		case idallocation.Keyword:
+			newPool, _ := ksChange.NewValue.(*idallocation.AllocationPool)
+			prevPool, _ := ksChange.PrevValue.(*idallocation.AllocationPool)
+			if (newPool != nil && newPool.Name == podSubnetPoolName) ||
+				(newPool == nil && prevPool != nil && prevPool.Name == podSubnetPoolName) {
+				if err := i.updatePodSubnetLeases(newPool); err != nil {
+					// this node could be allocating pod IPs from a subnet leased by another node
+					i.Log.Error(err)
+					return "", controller.NewFatalError(err)
+				}
+			}
 		case ipalloc.Keyword:
 			if newIPAlloc, newOK := ksChange.NewValue.(*ipalloc.CustomIPAllocation); newOK {
 				podID := podmodel.ID{Name: newIPAlloc.PodName, Namespace: newIPAlloc.PodNamespace}
@@ -513,6 +868,11 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 					// NOTE: ignoring pods outside of all pod subnets (across all nodes and networks)
 				}
 			}
+		case ipalloc.ReservationKeyword:
+			prevReservation, _ := ksChange.PrevValue.(*ipalloc.IPReservation)
+			newReservation, _ := ksChange.NewValue.(*ipalloc.IPReservation)
+			i.updateReservation(prevReservation, newReservation)
+			i.checkIPUsage()
 		case podmodel.PodKeyword:
 			oldPod, _ := ksChange.PrevValue.(*podmodel.Pod)
 			newPod, _ := ksChange.NewValue.(*podmodel.Pod)
@@ -524,21 +884,28 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 				updatedPodID := podmodel.ID{Name: newPod.Name, Namespace: newPod.Namespace}
 				// ignore changes with no IP Address
 				if newIPAddress := net.ParseIP(newPod.IpAddress); newIPAddress != nil {
-					if podNw.podSubnetThisNode.Contains(newIPAddress) { // local pod
+					secondaryIPAddress := podNw.secondaryPodIP(newPod)
+					if podNw.isLocalIP(newIPAddress) { // local pod
 						if pod, exists := i.podToIP[updatedPodID]; exists {
 							pod.mainIP = newIPAddress
+							if secondaryIPAddress != nil {
+								pod.secondaryIP = secondaryIPAddress
+							}
 						} else {
 							i.podToIP[updatedPodID] = &podIPInfo{
 								mainIP:      newIPAddress,
+								secondaryIP: secondaryIPAddress,
 								customIfIPs: map[string]net.IP{},
 							}
 						}
-					} else if podNw.podSubnetAllNodes.Contains(newIPAddress) { // remote pod
+					} else if podNw.isPodIP(newIPAddress) { // remote pod
 						if pod, exists := i.remotePodToIP[updatedPodID]; exists {
 							pod.mainIP = newIPAddress
+							pod.secondaryIP = secondaryIPAddress
 						} else {
 							i.remotePodToIP[updatedPodID] = &podIPInfo{
 								mainIP:      newIPAddress,
+								secondaryIP: secondaryIPAddress,
 								customIfIPs: map[string]net.IP{},
 							}
 						}
@@ -575,6 +942,7 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 					if err != nil {
 						return "", err
 					}
+					i.checkIPUsage()
 				}
 			}
 		}
@@ -585,7 +953,7 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 // isLocalPodInterface determines from pod interface IP address whether interface (and pod) is located on this node
 func (i *IPAM) isLocalPodInterface(intefaceIPAddress net.IP) bool {
 	for _, nw := range i.podNetworks {
-		if nw.podSubnetThisNode.Contains(intefaceIPAddress) {
+		if nw.isLocalIP(intefaceIPAddress) {
 			return true
 		}
 	}
@@ -596,7 +964,7 @@ func (i *IPAM) isLocalPodInterface(intefaceIPAddress net.IP) bool {
 // from any custom/default network
 func (i *IPAM) isInAnyPodSubnet(intefaceIPAddress net.IP) bool {
 	for _, nw := range i.podNetworks {
-		if nw.podSubnetAllNodes.Contains(intefaceIPAddress) {
+		if nw.isPodIP(intefaceIPAddress) {
 			return true
 		}
 	}
@@ -710,15 +1078,70 @@ func (i *IPAM) PodSubnetThisNode(network string) *net.IPNet {
 	return newIPNet(podNw.podSubnetThisNode)
 }
 
+// PodSubnetsAllNodes returns all POD subnets (across all nodes) of the given pod network,
+// starting with the main POD subnet (as returned by PodSubnetAllNodes).
+func (i *IPAM) PodSubnetsAllNodes(network string) (subnets []*net.IPNet) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	podNw := i.getPodNetwork(network)
+	for _, subnet := range podNw.subnetsAllNodes() {
+		subnets = append(subnets, newIPNet(subnet))
+	}
+	return subnets
+}
+
+// PodSubnetsThisNode returns all POD networks of the current node for the given pod network,
+// starting with the POD network from the main POD subnet (as returned by PodSubnetThisNode).
+// POD networks from additional pod subnets not activated yet are not included.
+func (i *IPAM) PodSubnetsThisNode(network string) (subnets []*net.IPNet) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	podNw := i.getPodNetwork(network)
+	for _, subnet := range podNw.subnetsThisNode() {
+		subnets = append(subnets, newIPNet(subnet))
+	}
+	return subnets
+}
+
+// PodSubnetsOtherNode returns all POD networks of another node identified by network name and nodeID,
+// starting with the POD network from the main POD subnet (as returned by PodSubnetOtherNode).
+// With dynamic pod subnet allocation, the list is empty until the node leases a pod subnet.
+func (i *IPAM) PodSubnetsOtherNode(network string, nodeID uint32) (subnets []*net.IPNet, err error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	podNw := i.getPodNetwork(network)
+	index, leased := i.podSubnetIndexOfNode(podNw, nodeID)
+	if !leased {
+		// pod subnet not leased by the node yet
+		return nil, nil
+	}
+	nodeSubnets, err := podNw.subnetsForIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	for _, subnet := range nodeSubnets {
+		subnets = append(subnets, newIPNet(subnet))
+	}
+	return subnets, nil
+}
+
 // PodSubnetOtherNode returns the POD network of another node identified by by network name and nodeID.
+// With dynamic pod subnet allocation, the POD network is resolved through the table of pod subnet leases.
 func (i *IPAM) PodSubnetOtherNode(network string, nodeID uint32) (*net.IPNet, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
 
 	podNw := i.getPodNetwork(network)
+	index, leased := i.podSubnetIndexOfNode(podNw, nodeID)
+	if !leased {
+		return nil, fmt.Errorf("node ID=%d has not leased any pod subnet", nodeID)
+	}
 	oneNodePrefixLen, _ := podNw.podSubnetThisNode.Mask.Size()
 	podSubnetThisNode, err := dissectSubnetForNode(
-		podNw.podSubnetAllNodes, uint8(oneNodePrefixLen), nodeID)
+		podNw.podSubnetAllNodes, uint8(oneNodePrefixLen), index)
 	if err != nil {
 		return nil, err
 	}
@@ -734,34 +1157,61 @@ func (i *IPAM) PodGatewayIP(network string) net.IP {
 	return newIP(podNw.podSubnetGatewayIP)
 }
 
+// PodGatewayIPs returns gateway IP addresses of the POD subnets of this node,
+// one per IP family (i.e. two with dual-stack), starting with PodGatewayIP.
+func (i *IPAM) PodGatewayIPs(network string) (gwIPs []net.IP) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	podNw := i.getPodNetwork(network)
+	gwIPs = append(gwIPs, newIP(podNw.podSubnetGatewayIP))
+	if podNw.secondaryPool != nil {
+		gwIPs = append(gwIPs, newIP(podNw.secondaryGatewayIP))
+	}
+	return gwIPs
+}
+
 // NodeIDFromPodIP returns node ID from provided main POD IP address.
 func (i *IPAM) NodeIDFromPodIP(podIP net.IP) (uint32, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
 
 	podNw := i.podNetworks[defaultPodNetworkName]
-	if !podNw.podSubnetAllNodes.Contains(podIP) {
-		return 0, fmt.Errorf("pod IP %v not from pod subnet %v", podIP, podNw.podSubnetAllNodes)
+	var podSubnetAllNodes *net.IPNet
+	var oneNodePrefixLen int
+	if podNw.podSubnetAllNodes.Contains(podIP) {
+		podSubnetAllNodes = podNw.podSubnetAllNodes
+		oneNodePrefixLen, _ = podNw.podSubnetThisNode.Mask.Size()
+	} else {
+		for _, pool := range podNw.pools() {
+			if pool.subnetAllNodes.Contains(podIP) {
+				podSubnetAllNodes = pool.subnetAllNodes
+				oneNodePrefixLen = int(pool.oneNodePrefixLen)
+				break
+			}
+		}
+	}
+	if podSubnetAllNodes == nil {
+		return 0, fmt.Errorf("pod IP %v not from pod subnets %v", podIP, podNw.subnetsAllNodes())
 	}
 
-	subnet := podNw.podSubnetAllNodes.IP
-	if !isIPv6Net(podNw.podSubnetAllNodes) {
+	subnet := podSubnetAllNodes.IP
+	if !isIPv6Net(podSubnetAllNodes) {
 		podIP = podIP.To4()
 		subnet = subnet.To4()
 	}
 	ip := new(big.Int).SetBytes(podIP)
-	podSubnetAllNodes := new(big.Int).SetBytes(subnet)
+	subnetPrefix := new(big.Int).SetBytes(subnet)
 
-	addrLen := addrLenFromNet(podNw.podSubnetThisNode)
-	oneNodePrefixLen, _ := podNw.podSubnetThisNode.Mask.Size()
+	addrLen := addrLenFromNet(podSubnetAllNodes)
 
 	// zero pod subnet prefix for all nodes
-	ip.Xor(ip, podSubnetAllNodes)
+	ip.Xor(ip, subnetPrefix)
 
 	// shift right to get rid of the node addressing part
 	ip.Rsh(ip, uint(addrLen-oneNodePrefixLen))
 
-	return uint32(ip.Uint64()), nil
+	return i.nodeIDFromPodSubnetIndex(uint32(ip.Uint64()))
 }
 
 // NatLoopbackIP returns the IP address of a virtual loopback, used to route traffic
@@ -795,14 +1245,30 @@ func (i *IPAM) AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string
 		return i.allocateExternalPodIP(podID, ipamType, ipamData)
 	}
 
+	// pod is (re-)added, it is not leaked (anymore)
+	delete(i.leakedIPs, podID)
+
 	// check whether IP is already allocated
 	allocation, found := i.podToIP[podID]
 	if found && allocation.mainIP != nil {
+		if err := i.allocateSecondaryPodIP(podID, allocation); err != nil {
+			return nil, err
+		}
 		return allocation.mainIP, nil
 	}
 
-	// allocate an IP
-	ip, err := i.allocateIP(i.podNetworks[defaultPodNetworkName])
+	// allocate an IP (requested by the pod or any free)
+	request, err := i.getPodIPRequest(podID)
+	if err != nil {
+		i.Log.Errorf("Unable to allocate main pod IP: %v", err)
+		return nil, err
+	}
+	var ip net.IP
+	if request != nil {
+		ip, err = i.allocateRequestedIP(podID, request)
+	} else {
+		ip, err = i.allocateIP(i.podNetworks[defaultPodNetworkName])
+	}
 	if err != nil {
 		i.Log.Errorf("Unable to allocate main pod IP: %v", err)
 		return nil, err
@@ -819,11 +1285,58 @@ func (i *IPAM) AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string
 		}
 	}
 	i.podToIP[podID].mainIP = ip
+	if err = i.allocateSecondaryPodIP(podID, i.podToIP[podID]); err != nil {
+		// do not leave the pod with the main IP only
+		i.releaseMainPodIP(podID)
+		return nil, err
+	}
 	i.logAssignedPodIPPool()
+	i.checkIPUsage()
 
 	return ip, nil
 }
 
+// releaseMainPodIP releases the main IP address of the given local pod (allocated
+// by the IPAM itself), leaving IP addresses of custom interfaces allocated.
+// The method expects the mutex to be already acquired.
+func (i *IPAM) releaseMainPodIP(podID podmodel.ID) {
+	allocation, found := i.podToIP[podID]
+	if !found || allocation.mainIP == nil {
+		return
+	}
+	i.Log.Infof("Released IP %v for pod ID %v", allocation.mainIP, podID)
+	delete(i.assignedPodIPs, allocation.mainIP.String())
+	allocation.mainIP = nil
+	if len(allocation.customIfIPs) == 0 {
+		delete(i.podToIP, podID)
+	}
+}
+
+// allocateSecondaryPodIP allocates IP address from the secondary pod subnet (of the other
+// IP family) for the main interface of the given pod. No-op without dual-stack or if
+// the address is already allocated.
+// The method expects the mutex to be already acquired.
+func (i *IPAM) allocateSecondaryPodIP(podID podmodel.ID, allocation *podIPInfo) error {
+	podNw := i.podNetworks[defaultPodNetworkName]
+	if podNw.secondaryPool == nil || allocation.secondaryIP != nil {
+		return nil
+	}
+	pool := podNw.secondaryPool
+	ip, found := i.allocateIPFromSubnet(pool.subnetThisNode, &pool.lastIPAssigned)
+	if !found {
+		err := fmt.Errorf("no IP address is free for allocation in the secondary pod subnet %v",
+			pool.subnetThisNode)
+		i.Log.Errorf("Unable to allocate secondary pod IP: %v", err)
+		return err
+	}
+	i.assignedPodIPs[ip.String()] = &podIPAllocation{
+		pod:    podID,
+		mainIP: true,
+	}
+	allocation.secondaryIP = ip
+	return nil
+}
+
 // AllocatePodCustomIfIP tries to allocate custom IP address for the given interface of a given pod.
 func (i *IPAM) AllocatePodCustomIfIP(podID podmodel.ID, ifName, network string,
 	isServiceEndpoint bool) (net.IP, error) {
@@ -865,6 +1378,7 @@ func (i *IPAM) AllocatePodCustomIfIP(podID podmodel.ID, ifName, network string,
 	}
 	i.podToIP[podID].customIfIPs[customIfID(ifName, network)] = ip
 	i.logAssignedPodIPPool()
+	i.checkIPUsage()
 
 	return ip, nil
 }
@@ -928,6 +1442,25 @@ func (i IPAM) getDBBroker() (keyval.ProtoBroker, error) {
 	return i.dbBroker, nil
 }
 
+// getAtomicDBBroker returns broker with atomic operations for accessing remote database,
+// error if database is not connected.
+func (i *IPAM) getAtomicDBBroker() (keyval.BytesBrokerWithAtomic, error) {
+	// return error if ETCD is not connected
+	dbIsConnected := false
+	i.RemoteDB.OnConnect(func() error {
+		dbIsConnected = true
+		return nil
+	})
+	if !dbIsConnected {
+		return nil, fmt.Errorf("remote database is not connected")
+	}
+	// return existing broker if possible
+	if i.atomicDBBroker == nil {
+		i.atomicDBBroker = i.RemoteDB.NewBrokerWithAtomic(servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel))
+	}
+	return i.atomicDBBroker, nil
+}
+
 // getPodNetwork returns pod network information for the given pod network name.
 func (i *IPAM) getPodNetwork(network string) *podNetworkInfo {
 	podNw := i.podNetworks[defaultPodNetworkName]
@@ -961,16 +1494,53 @@ func (i *IPAM) allocateExternalPodIP(podID podmodel.ID, ipamType string, ipamDat
 	}
 
 	i.Log.Infof("Assigned new pod IP %v for POD ID %v", ip, podID)
+	i.checkIPUsage()
 
 	return ip, nil
 }
 
 // allocateIP allocates a new IP from the pod IP pool of the given network.
+// Additional pod subnets are activated and used (in the configured order) only once
+// the pod subnet of this node from the main pod subnet is exhausted.
 func (i *IPAM) allocateIP(podNw *podNetworkInfo) (net.IP, error) {
-	last := podNw.lastPodIPAssigned + 1
+	if ip, found := i.allocateIPFromSubnet(podNw.podSubnetThisNode, &podNw.lastPodIPAssigned); found {
+		return ip, nil
+	}
+	for _, pool := range podNw.additionalPools {
+		if err := i.activatePodSubnet(podNw, pool); err != nil {
+			return nil, err
+		}
+		if ip, found := i.allocateIPFromSubnet(pool.subnetThisNode, &pool.lastIPAssigned); found {
+			return ip, nil
+		}
+	}
+	if podNw != i.podNetworks[defaultPodNetworkName] {
+		return nil, fmt.Errorf("no IP address is free for allocation in the subnet %v "+
+			"(additional pod subnets are used only by the default pod network)", podNw.podSubnetThisNode)
+	}
+	return nil, fmt.Errorf("no IP address is free for allocation in the subnets %v", podNw.subnetsThisNode())
+}
+
+// activatePodSubnet starts using the subnet of this node from the given additional
+// pool of the default pod network. Other plugins are notified via PodSubnetActivated.
+func (i *IPAM) activatePodSubnet(podNw *podNetworkInfo, pool *podSubnetPool) error {
+	activated, err := podNw.activatePool(pool)
+	if err != nil || !activated {
+		return err
+	}
+	i.Log.Infof("Preceding pod subnets of this node are exhausted, activated additional pod subnet %v",
+		pool.subnetThisNode)
+	i.EventLoop.PushEvent(&PodSubnetActivated{Subnet: newIPNet(pool.subnetThisNode)})
+	return nil
+}
+
+// allocateIPFromSubnet allocates a new IP from the given pod subnet of this node.
+// <lastAssigned> is the counter denoting last assigned IP address from the subnet.
+func (i *IPAM) allocateIPFromSubnet(subnet *net.IPNet, lastAssigned *int) (net.IP, bool) {
+	last := *lastAssigned + 1
 	// iterate over all possible IP addresses for pod network prefix
 	// start from the last assigned and take first available IP
-	prefixBits, totalBits := podNw.podSubnetThisNode.Mask.Size()
+	prefixBits, totalBits := subnet.Mask.Size()
 	// get the maximum sequence ID available in the provided range; the last valid unicast IP is used as "NAT-loopback"
 	podBitSize := uint(totalBits - prefixBits)
 	// IPAM currently support up to 2^63 pods
@@ -979,23 +1549,23 @@ func (i *IPAM) allocateIP(podNw *podNetworkInfo) (net.IP, error) {
 	}
 	maxSeqID := (1 << podBitSize) - 2
 	for j := last; j < maxSeqID; j++ {
-		ipForAssign, success := i.tryToAllocateIP(j, podNw.podSubnetThisNode)
+		ipForAssign, success := i.tryToAllocateIP(j, subnet)
 		if success {
-			podNw.lastPodIPAssigned = j
-			return ipForAssign, nil
+			*lastAssigned = j
+			return ipForAssign, true
 		}
 	}
 
-	// iterate from the range start until lastPodIPAssigned
+	// iterate from the range start until lastAssigned
 	for j := 1; j < last; j++ { // zero ending IP is reserved for network => skip seqID=0
-		ipForAssign, success := i.tryToAllocateIP(j, podNw.podSubnetThisNode)
+		ipForAssign, success := i.tryToAllocateIP(j, subnet)
 		if success {
-			podNw.lastPodIPAssigned = j
-			return ipForAssign, nil
+			*lastAssigned = j
+			return ipForAssign, true
 		}
 	}
 
-	return nil, fmt.Errorf("no IP address is free for allocation in the subnet %v", podNw.podSubnetThisNode)
+	return nil, false
 }
 
 // tryToAllocatePodIP checks whether the IP at the given index is available.
@@ -1010,6 +1580,9 @@ func (i *IPAM) tryToAllocateIP(index int, networkPrefix *net.IPNet) (assignedIP
 	if _, found := i.assignedPodIPs[ip.String()]; found {
 		return nil, false // ignore already assigned IP addresses
 	}
+	if i.isReservedIP(ip) {
+		return nil, false // ignore IP addresses reserved for other pods
+	}
 
 	i.Log.Infof("Assigned new pod IP %s", ip)
 
@@ -1031,6 +1604,31 @@ func (i *IPAM) GetPodIP(podID podmodel.ID) *net.IPNet {
 	return nil
 }
 
+// GetPodIPs returns all IP addresses allocated for the main interface of the pod,
+// one per IP family (i.e. two with dual-stack), starting with the IP returned by GetPodIP.
+// Searches for both local and remote pods.
+// Returns nil if the pod does not have allocated IP address.
+func (i *IPAM) GetPodIPs(podID podmodel.ID) (ips []*net.IPNet) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	allocation, found := i.getPodIPInfo(podID)
+	if !found {
+		return nil
+	}
+	for _, ip := range []net.IP{allocation.mainIP, allocation.secondaryIP} {
+		if ip == nil {
+			continue
+		}
+		addrLen := net.IPv6len * 8
+		if ip.To4() != nil {
+			addrLen = net.IPv4len * 8
+		}
+		ips = append(ips, &net.IPNet{IP: ip, Mask: net.CIDRMask(addrLen, addrLen)})
+	}
+	return ips
+}
+
 // GetExternalInterfaceIP returns the allocated external interface IP.
 // Returns nil if the interface does not have allocated IP address.
 func (i *IPAM) GetExternalInterfaceIP(vppInterface string, nodeID uint32) *net.IPNet {
@@ -1083,6 +1681,14 @@ func (i *IPAM) ReleasePodIPs(podID podmodel.ID) error {
 	i.mutex.Lock()
 	defer i.mutex.Unlock()
 
+	err := i.releasePodIPs(podID)
+	i.checkIPUsage()
+	return err
+}
+
+// releasePodIPs releases all IP addresses allocated for the given pod.
+// The method expects the mutex to be already acquired.
+func (i *IPAM) releasePodIPs(podID podmodel.ID) error {
 	allocation, found := i.podToIP[podID]
 	if !found {
 		i.Log.Warnf("Unable to find IP for pod %v", podID)
@@ -1098,6 +1704,10 @@ func (i *IPAM) ReleasePodIPs(podID podmodel.ID) error {
 	}
 
 	delete(i.assignedPodIPs, allocation.mainIP.String())
+	if allocation.secondaryIP != nil {
+		i.Log.Infof("Released secondary IP %v for pod ID %v", allocation.secondaryIP, podID)
+		delete(i.assignedPodIPs, allocation.secondaryIP.String())
+	}
 	for _, ip := range allocation.customIfIPs {
 		i.Log.Infof("Released custom interface IP %v for pod ID %v", ip, podID)
 		delete(i.assignedPodIPs, ip.String())
@@ -1126,20 +1736,32 @@ func (i *IPAM) ReleasePodIPs(podID podmodel.ID) error {
 func (i *IPAM) GetIPAMConfigForJSON() *config.IPAMConfig {
 	c := i.ContivConf.GetIPAMConfigForJSON()
 	res := &config.IPAMConfig{
-		UseExternalIPAM:      c.UseExternalIPAM,
-		ContivCIDR:           c.ContivCIDR,
-		ServiceCIDR:          c.ServiceCIDR,
-		DefaultGateway:       c.DefaultGateway,
-		NodeInterconnectDHCP: c.NodeInterconnectDHCP,
-		NodeInterconnectCIDR: i.nodeInterconnectSubnet.String(),
-		PodSubnetCIDR:        i.PodSubnetAllNodes(defaultPodNetworkName).String(),
-		VPPHostSubnetCIDR:    i.HostInterconnectSubnetAllNodes().String(),
+		UseExternalIPAM:            c.UseExternalIPAM,
+		DynamicPodSubnetAllocation: c.DynamicPodSubnetAllocation,
+		ContivCIDR:                 c.ContivCIDR,
+		ServiceCIDR:                c.ServiceCIDR,
+		DefaultGateway:             c.DefaultGateway,
+		NodeInterconnectDHCP:       c.NodeInterconnectDHCP,
+		NodeInterconnectCIDR:       i.nodeInterconnectSubnet.String(),
+		PodSubnetCIDR:              i.PodSubnetAllNodes(defaultPodNetworkName).String(),
+		VPPHostSubnetCIDR:          i.HostInterconnectSubnetAllNodes().String(),
 	}
 	if i.vxlanSubnet != nil {
 		res.VxlanCIDR = i.vxlanSubnet.String()
 	}
 	s, _ := i.PodSubnetThisNode(defaultPodNetworkName).Mask.Size()
 	res.PodSubnetOneNodePrefixLen = uint8(s)
+	i.mutex.RLock()
+	podNw := i.podNetworks[defaultPodNetworkName]
+	for _, pool := range podNw.additionalPools {
+		res.AdditionalPodSubnetCIDRs = append(res.AdditionalPodSubnetCIDRs, pool.subnetAllNodes.String())
+	}
+	if podNw.secondaryPool != nil {
+		res.SecondaryPodSubnetCIDR = podNw.secondaryPool.subnetAllNodes.String()
+		s, _ = podNw.secondaryPool.subnetThisNode.Mask.Size()
+		res.SecondaryPodSubnetOneNodePrefixLen = uint8(s)
+	}
+	i.mutex.RUnlock()
 
 	s, _ = i.HostInterconnectSubnetThisNode().Mask.Size()
 	res.VPPHostSubnetOneNodePrefixLen = uint8(s)
@@ -1341,8 +1963,12 @@ func (i *IPAM) computeSID(ip net.IP, prefixNetwork *net.IPNet) net.IP {
 		newIPWithPositionableMask(ip, prefixNetworkMaskSize, 128-prefixNetworkMaskSize))
 }
 
-// Close is NOOP.
+// Close stops the periodic garbage collection of leaked pod IPs.
 func (i *IPAM) Close() error {
+	if i.stopGC != nil {
+		close(i.stopGC)
+	}
+	i.wg.Wait()
 	return nil
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/americanbinary/vpp/plugins/contivconf/config"
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	"github.com/americanbinary/vpp/plugins/podmanager"
 )
 
 // API defines methods provided by IPAM for use by other plugins.
@@ -61,12 +62,34 @@ type API interface {
 	PodSubnetThisNode(network string) *net.IPNet
 
 	// PodSubnetOtherNode returns the POD network of another node identified by network name and nodeID.
+	// With dynamic pod subnet allocation, returns error if the node has not leased any pod subnet yet.
 	PodSubnetOtherNode(network string, nodeID uint32) (*net.IPNet, error)
 
+	// PodSubnetsAllNodes returns all POD subnets (the main one followed by the additional
+	// pod subnets and, with dual-stack, by the secondary pod subnet) that are base subnets
+	// for all PODs of all nodes for given pod network.
+	PodSubnetsAllNodes(network string) []*net.IPNet
+
+	// PodSubnetsThisNode returns all POD networks for the current node (one from each
+	// subnet returned by PodSubnetsAllNodes). POD networks from the additional pod subnets
+	// are included only once activated (see PodSubnetActivated).
+	PodSubnetsThisNode(network string) []*net.IPNet
+
+	// PodSubnetsOtherNode returns all POD networks of another node identified by network
+	// name and nodeID (one from each subnet returned by PodSubnetsAllNodes).
+	// With dynamic pod subnet allocation, the list is empty until the node leases a pod subnet
+	// (see PodSubnetLeaseChange).
+	PodSubnetsOtherNode(network string, nodeID uint32) ([]*net.IPNet, error)
+
 	// PodGatewayIP returns gateway IP address of the POD subnet of this node.
 	PodGatewayIP(network string) net.IP
 
-	// NodeIDFromPodIP returns node ID from provided main POD IP address.
+	// PodGatewayIPs returns gateway IP addresses of the POD subnets of this node,
+	// one per IP family (i.e. two with dual-stack), starting with PodGatewayIP.
+	PodGatewayIPs(network string) []net.IP
+
+	// NodeIDFromPodIP returns node ID from provided main POD IP address
+	// (of any IP family with dual-stack).
 	NodeIDFromPodIP(podIP net.IP) (uint32, error)
 
 	// NatLoopbackIP returns the IP address of a virtual loopback, used to route
@@ -78,12 +101,23 @@ type API interface {
 	ServiceNetwork() *net.IPNet
 
 	// AllocatePodIP tries to allocate IP address for the given pod.
+	// Pod may request a specific IP address (annotation contivpp.io/ip-address)
+	// and/or a named IP reservation (annotation contivpp.io/ip-reservation) from the pod
+	// subnet of this node. Requested IP addresses are reserved in the KV DB.
+	// With dual-stack, the pod is also allocated an IP address from the secondary
+	// pod subnet (of the other IP family), available via GetPodIPs.
 	AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string) (net.IP, error)
 
 	// GetPodIP returns the allocated (main) pod IP, together with the mask. Searches for
 	// both local and remote pods. Returns nil if the pod does not have allocated IP address.
 	GetPodIP(podID podmodel.ID) *net.IPNet
 
+	// GetPodIPs returns all IP addresses allocated for the main interface of the pod,
+	// one per IP family (i.e. two with dual-stack), starting with the IP returned
+	// by GetPodIP. Searches for both local and remote pods. Returns nil if the pod
+	// does not have allocated IP address.
+	GetPodIPs(podID podmodel.ID) []*net.IPNet
+
 	// GetExternalInterfaceIP returns the allocated external interface IP.
 	// Returns nil if the interface does not have allocated IP address.
 	GetExternalInterfaceIP(vppInterface string, nodeID uint32) *net.IPNet
@@ -187,3 +221,249 @@ func (ev *PodCIDRChange) IsBlocking() bool {
 func (ev *PodCIDRChange) Done(error) {
 	return
 }
+
+// IsDuplicateOf returns true if the given event is PodCIDRChange with the same
+// CIDR.
+func (ev *PodCIDRChange) IsDuplicateOf(event controller.Event) bool {
+	other, isPodCIDRChange := event.(*PodCIDRChange)
+	return isPodCIDRChange && ev.LocalPodCIDR.String() == other.LocalPodCIDR.String()
+}
+
+// PodSubnetLeaseChange is triggered when pod subnets leased by another node change
+// (used only with dynamic pod subnet allocation).
+type PodSubnetLeaseChange struct {
+	NodeID      uint32
+	PrevSubnets []*net.IPNet // nil if the node had no lease
+	NewSubnets  []*net.IPNet // nil if the lease was released
+}
+
+// GetName returns name of the PodSubnetLeaseChange event.
+func (ev *PodSubnetLeaseChange) GetName() string {
+	return "Pod Subnet Lease Change"
+}
+
+// String describes PodSubnetLeaseChange event.
+func (ev *PodSubnetLeaseChange) String() string {
+	return fmt.Sprintf("%s\n"+
+		"* node ID: %d\n"+
+		"* prev-subnets: %v\n"+
+		"* new-subnets: %v", ev.GetName(), ev.NodeID, ev.PrevSubnets, ev.NewSubnets)
+}
+
+// Method is Update.
+func (ev *PodSubnetLeaseChange) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *PodSubnetLeaseChange) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *PodSubnetLeaseChange) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *PodSubnetLeaseChange) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *PodSubnetLeaseChange) Done(error) {
+	return
+}
+
+// PodSubnetActivated is triggered when the pod subnet of this node from an additional
+// pod subnet starts to be used, i.e. once the preceding pod subnets of this node
+// are exhausted (default pod network only).
+type PodSubnetActivated struct {
+	Subnet *net.IPNet
+}
+
+// GetName returns name of the PodSubnetActivated event.
+func (ev *PodSubnetActivated) GetName() string {
+	return "Pod Subnet Activated"
+}
+
+// String describes PodSubnetActivated event.
+func (ev *PodSubnetActivated) String() string {
+	return fmt.Sprintf("%s\n"+
+		"* subnet: %v", ev.GetName(), ev.Subnet)
+}
+
+// Method is Update.
+func (ev *PodSubnetActivated) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *PodSubnetActivated) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *PodSubnetActivated) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *PodSubnetActivated) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *PodSubnetActivated) Done(error) {
+	return
+}
+
+// IPReservationConflict is triggered when a pod requests (via annotations) IP address
+// or named IP reservation which is reserved for another pod.
+// The event is informative only - the pod IP allocation fails.
+type IPReservationConflict struct {
+	PodID       podmodel.ID // pod which requested the reserved IP address
+	IP          net.IP
+	Reservation string      // empty for static IP reserved for a single pod
+	Owner       podmodel.ID // pod holding the reservation
+	Reason      string
+}
+
+// GetName returns name of the IPReservationConflict event.
+func (ev *IPReservationConflict) GetName() string {
+	return "IP Reservation Conflict"
+}
+
+// String describes IPReservationConflict event.
+func (ev *IPReservationConflict) String() string {
+	return fmt.Sprintf("%s\n"+
+		"* pod: %v\n"+
+		"* IP: %v\n"+
+		"* reservation: %s\n"+
+		"* owner: %v\n"+
+		"* reason: %s", ev.GetName(), ev.PodID, ev.IP, ev.Reservation, ev.Owner, ev.Reason)
+}
+
+// Method is Update.
+func (ev *IPReservationConflict) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *IPReservationConflict) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *IPReservationConflict) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *IPReservationConflict) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *IPReservationConflict) Done(error) {
+	return
+}
+
+// PodIPGarbageCollection is triggered periodically by IPAM to remove pods that no longer
+// run on this node (e.g. when CNI DEL request was never received), release their IP addresses
+// and to remove static IP reservations of pods that no longer exist.
+type PodIPGarbageCollection struct {
+	// pods with a running sandbox on this node (listed just before the event was pushed)
+	RunningPods podmanager.LocalPods
+}
+
+// GetName returns name of the PodIPGarbageCollection event.
+func (ev *PodIPGarbageCollection) GetName() string {
+	return "Pod IP Garbage Collection"
+}
+
+// String describes PodIPGarbageCollection event.
+func (ev *PodIPGarbageCollection) String() string {
+	return ev.GetName()
+}
+
+// Method is Update.
+func (ev *PodIPGarbageCollection) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *PodIPGarbageCollection) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *PodIPGarbageCollection) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *PodIPGarbageCollection) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *PodIPGarbageCollection) Done(error) {
+	return
+}
+
+// IsDuplicateOf returns true if the given event is also PodIPGarbageCollection.
+func (ev *PodIPGarbageCollection) IsDuplicateOf(event controller.Event) bool {
+	_, isGC := event.(*PodIPGarbageCollection)
+	return isGC
+}
+
+// IPUsageThresholdExceeded is triggered when the utilization of an IP address block
+// of this node crosses the configured warning threshold.
+// The event is informative only - it warns about the upcoming exhaustion of the block.
+type IPUsageThresholdExceeded struct {
+	Network   string // pod network name
+	Subnet    string
+	Allocated uint64
+	Capacity  uint64
+	Threshold uint8 // in percent
+}
+
+// GetName returns name of the IPUsageThresholdExceeded event.
+func (ev *IPUsageThresholdExceeded) GetName() string {
+	return "IP Usage Threshold Exceeded"
+}
+
+// String describes IPUsageThresholdExceeded event.
+func (ev *IPUsageThresholdExceeded) String() string {
+	return fmt.Sprintf("%s\n"+
+		"* network: %s\n"+
+		"* subnet: %s\n"+
+		"* allocated: %d/%d\n"+
+		"* threshold: %d%%", ev.GetName(), ev.Network, ev.Subnet, ev.Allocated, ev.Capacity, ev.Threshold)
+}
+
+// Method is Update.
+func (ev *IPUsageThresholdExceeded) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *IPUsageThresholdExceeded) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *IPUsageThresholdExceeded) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *IPUsageThresholdExceeded) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *IPUsageThresholdExceeded) Done(error) {
+	return
+}
@@ -19,24 +19,36 @@ import (
 	"net"
 	"strconv"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	. "github.com/americanbinary/vpp/mock/datasync"
+	. "github.com/americanbinary/vpp/mock/eventloop"
+	. "github.com/americanbinary/vpp/mock/idalloc"
 	. "github.com/americanbinary/vpp/mock/nodesync"
+	. "github.com/americanbinary/vpp/mock/podmanager"
+	. "github.com/americanbinary/vpp/mock/remotedb"
 	. "github.com/americanbinary/vpp/mock/servicelabel"
 
 	"go.ligato.io/cn-infra/v2/infra"
 	"go.ligato.io/cn-infra/v2/logging"
 	"go.ligato.io/cn-infra/v2/logging/logrus"
+	"go.ligato.io/cn-infra/v2/servicelabel"
 
 	"bytes"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	"github.com/americanbinary/vpp/plugins/contivconf/config"
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	nodeconfigcrd "github.com/americanbinary/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
+	"github.com/americanbinary/vpp/plugins/idalloc/idallocation"
+	"github.com/americanbinary/vpp/plugins/ipam/ipalloc"
+	"github.com/americanbinary/vpp/plugins/ipam/restapi"
+	"github.com/americanbinary/vpp/plugins/ksr"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"github.com/americanbinary/vpp/plugins/nodesync"
+	"github.com/americanbinary/vpp/plugins/podmanager"
 )
 
 //TODO maybe check multiple hosts IPAMs for no interconnection between them and that hostID is not hardwired
@@ -135,8 +147,13 @@ func newIPAM(cfg *config.Config, nodeID uint32) (i *IPAM, err error) {
 			PluginDeps: infra.PluginDeps{
 				Log: logging.ForPlugin("ipam"),
 			},
-			NodeSync:   nodeSync,
-			ContivConf: conf,
+			NodeSync:     nodeSync,
+			ContivConf:   conf,
+			ServiceLabel: serviceLabel,
+			RemoteDB:     NewMockRemoteDB(),
+			IDAlloc:      NewMockIDAllocator(nodeName),
+			EventLoop:    &MockEventLoop{},
+			PodManager:   NewMockPodManager(),
 		},
 	}
 	err = i.Init()
@@ -150,6 +167,16 @@ func newIPAM(cfg *config.Config, nodeID uint32) (i *IPAM, err error) {
 	return i, nil
 }
 
+// allocatePodIP allocates IP address for the given pod, k8s metadata of the pod
+// are reflected first if they are not already.
+func allocatePodIP(i *IPAM, podID podmodel.ID) (net.IP, error) {
+	podManager := i.PodManager.(*MockPodManager)
+	if _, reflected := podManager.GetPods()[podID]; !reflected {
+		podManager.AddRemotePod(&podmanager.Pod{ID: podID})
+	}
+	return i.AllocatePodIP(podID, "", "")
+}
+
 // TestStaticGetters tests exposed IPAM API that provides data that doesn't change in time (and are not dynamically
 // recomputed based on new input in form of API function parameters)
 func TestStaticGetters(t *testing.T) {
@@ -270,7 +297,7 @@ func TestDynamicGettersIPv6(t *testing.T) {
 // TestBasicAllocateReleasePodAddress test simple happy path scenario for getting 1 pod address and releasing it
 func TestBasicAllocateReleasePodAddress(t *testing.T) {
 	i := setup(t, newDefaultConfig())
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, err := allocatePodIP(i, podID[0])
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
@@ -289,7 +316,7 @@ func TestBasicAllocateReleasePodAddressIPv6(t *testing.T) {
 	i := setup(t, customConfig)
 	Expect(i).NotTo(BeNil())
 
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, err := allocatePodIP(i, podID[0])
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
@@ -309,7 +336,7 @@ func TestWideIPv6PodSubenet(t *testing.T) {
 	i := setup(t, customConfig)
 	Expect(i).NotTo(BeNil())
 
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, err := allocatePodIP(i, podID[0])
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
@@ -322,13 +349,13 @@ func TestWideIPv6PodSubenet(t *testing.T) {
 // TestAlreadyAllocated tests that repeated allocation for a given podID returns the same IP
 func TestAlreadyAllocatedAddress(t *testing.T) {
 	i := setup(t, newDefaultConfig())
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, err := allocatePodIP(i, podID[0])
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
 		"Pod IP address is not from pod network")
 
-	repeated, err := i.AllocatePodIP(podID[0], "", "")
+	repeated, err := allocatePodIP(i, podID[0])
 	Expect(err).To(BeNil())
 	Expect(repeated).NotTo(BeNil())
 	Expect(bytes.Compare(repeated, ip)).To(BeZero())
@@ -340,14 +367,14 @@ func TestAlreadyAllocatedAddress(t *testing.T) {
 // TestAssigniningIncrementalIPs test whether released IPs are reused only once all the range is exhausted
 func TestAssigniningIncrementalIPs(t *testing.T) {
 	i := setup(t, newDefaultConfig())
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, err := allocatePodIP(i, podID[0])
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(ip.String()).To(BeEquivalentTo("1.2.128.10"))
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
 		"Pod IP address is not from pod network")
 
-	second, err := i.AllocatePodIP(podID[1], "", "")
+	second, err := allocatePodIP(i, podID[1])
 	Expect(err).To(BeNil())
 	Expect(second).NotTo(BeNil())
 	Expect(second.String()).To(BeEquivalentTo("1.2.128.11"))
@@ -358,7 +385,7 @@ func TestAssigniningIncrementalIPs(t *testing.T) {
 	Expect(err).To(BeNil())
 
 	// check that second is not reused
-	third, err := i.AllocatePodIP(podID[2], "", "")
+	third, err := allocatePodIP(i, podID[2])
 	Expect(err).To(BeNil())
 	Expect(third).NotTo(BeNil())
 	Expect(third.String()).To(BeEquivalentTo("1.2.128.12"))
@@ -366,14 +393,14 @@ func TestAssigniningIncrementalIPs(t *testing.T) {
 		"Pod IP address is not from pod network")
 
 	// exhaust the range
-	assigned, err := i.AllocatePodIP(podID[3], "", "")
+	assigned, err := allocatePodIP(i, podID[3])
 	Expect(err).To(BeNil())
 	Expect(assigned).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(assigned)).To(BeTrue(),
 		"Pod IP address is not from pod network")
 
 	// expect released IP to be reused
-	reused, err := i.AllocatePodIP(podID[1], "", "")
+	reused, err := allocatePodIP(i, podID[1])
 	Expect(err).To(BeNil())
 	Expect(reused).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(reused)).To(BeTrue(),
@@ -476,6 +503,176 @@ func TestPodSubnetThisNodeSubnets(t *testing.T) {
 	Expect(outOfRange).To(BeNil())
 }
 
+// TestAdditionalPodSubnets verifies that pod IP addresses are allocated from the additional pod subnets
+// (in the given order) once the pod subnet of this node is exhausted
+func TestAdditionalPodSubnets(t *testing.T) {
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.AdditionalPodSubnetCIDRs = []string{"5.6.7.0/25", "6.7.8.0/25"}
+	i := setup(t, customConfig)
+	eventLoop := i.EventLoop.(*MockEventLoop)
+
+	// additional pod subnets are not used until the main one is exhausted
+	Expect(i.PodSubnetsThisNode(defaultPodNetworkName)).To(HaveLen(1))
+	Expect(i.PodSubnetsAllNodes(defaultPodNetworkName)).To(HaveLen(3))
+	exhaustPodIPAddresses(i, 4) // 4 free IP addresses in every subnet
+	Expect(i.PodSubnetsThisNode(defaultPodNetworkName)).To(HaveLen(1))
+	Expect(eventLoop.EventQueue).To(BeEmpty())
+
+	releaseAllPodAddresses(i, 4)
+	maxIPCount := 3 * 4
+	allocatedIPs, allocatedPodIDs := exhaustPodIPAddresses(i, maxIPCount)
+	thisNodeSubnets := i.PodSubnetsThisNode(defaultPodNetworkName)
+	Expect(thisNodeSubnets).To(HaveLen(3))
+	Expect(*thisNodeSubnets[0]).To(BeEquivalentTo(expectedPodSubnetThisNode))
+	Expect(*thisNodeSubnets[1]).To(BeEquivalentTo(network("5.6.7." + str(int(nodeID1<<3)) + "/29")))
+	Expect(*thisNodeSubnets[2]).To(BeEquivalentTo(network("6.7.8." + str(int(nodeID1<<3)) + "/29")))
+	for j, ip := range allocatedIPs {
+		Expect(thisNodeSubnets[j/4].Contains(net.ParseIP(ip))).To(BeTrue(),
+			"IP %v is not allocated from the expected subnet %v", ip, thisNodeSubnets[j/4])
+	}
+	assertCorrectIPExhaustion(i, maxIPCount)
+
+	// activation of every additional pod subnet is announced
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+	Expect(*eventLoop.EventQueue[0].(*PodSubnetActivated).Subnet).To(BeEquivalentTo(*thisNodeSubnets[1]))
+	Expect(*eventLoop.EventQueue[1].(*PodSubnetActivated).Subnet).To(BeEquivalentTo(*thisNodeSubnets[2]))
+
+	// IP addresses from additional pod subnets belong to this node
+	podID, found := i.GetPodFromIP(net.ParseIP(allocatedIPs[5]))
+	Expect(found).To(BeTrue())
+	Expect(podID).To(BeEquivalentTo(allocatedPodIDs[5]))
+	id, err := i.NodeIDFromPodIP(net.ParseIP(allocatedIPs[9]))
+	Expect(err).To(BeNil())
+	Expect(id).To(BeEquivalentTo(nodeID1))
+
+	// additional pod subnets of another node
+	otherNodeSubnets, err := i.PodSubnetsOtherNode(defaultPodNetworkName, nodeID2)
+	Expect(err).To(BeNil())
+	Expect(otherNodeSubnets).To(HaveLen(3))
+	Expect(*otherNodeSubnets[2]).To(BeEquivalentTo(network("6.7.8." + str(int(nodeID2<<3)) + "/29")))
+	id, err = i.NodeIDFromPodIP(net.ParseIP("6.7.8." + str(int(nodeID2<<3)+2)))
+	Expect(err).To(BeNil())
+	Expect(id).To(BeEquivalentTo(nodeID2))
+
+	// released IP from the main pod subnet is preferred
+	releaseSomePodAddresses(i, allocatedPodIDs[:1])
+	ip, err := allocatePodIP(i, podmodel.ID{Namespace: "default", Name: "new-pod"})
+	Expect(err).To(BeNil())
+	Expect(ip.String()).To(BeEquivalentTo(allocatedIPs[0]))
+
+	// after restart, only the additional pod subnets with allocated IPs are activated
+	resyncEv := controller.NewDBResync()
+	resyncEv.KubeState[podmodel.PodKeyword][podmodel.Key("pod1", "default")] = &podmodel.Pod{
+		Name:      "pod1",
+		Namespace: "default",
+		IpAddress: allocatedIPs[10],
+	}
+	Expect(i.Resync(resyncEv, resyncEv.KubeState, 1, nil)).To(Succeed())
+	thisNodeSubnets = i.PodSubnetsThisNode(defaultPodNetworkName)
+	Expect(thisNodeSubnets).To(HaveLen(2))
+	Expect(*thisNodeSubnets[1]).To(BeEquivalentTo(network("6.7.8." + str(int(nodeID1<<3)) + "/29")))
+	podID, found = i.GetPodFromIP(net.ParseIP(allocatedIPs[10]))
+	Expect(found).To(BeTrue())
+	Expect(podID.Name).To(BeEquivalentTo("pod1"))
+}
+
+func TestDualStackPodIPs(t *testing.T) {
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.SecondaryPodSubnetCIDR = "fd00:1::/64"
+	customConfig.IPAMConfig.SecondaryPodSubnetOneNodePrefixLen = 120
+	i := setup(t, customConfig)
+
+	// the secondary pod subnet is the last one
+	thisNodeSubnets := i.PodSubnetsThisNode(defaultPodNetworkName)
+	Expect(thisNodeSubnets).To(HaveLen(2))
+	Expect(*thisNodeSubnets[0]).To(BeEquivalentTo(expectedPodSubnetThisNode))
+	Expect(*thisNodeSubnets[1]).To(BeEquivalentTo(network("fd00:1::100/120")))
+	Expect(i.PodSubnetsAllNodes(defaultPodNetworkName)).To(HaveLen(2))
+	gwIPs := i.PodGatewayIPs(defaultPodNetworkName)
+	Expect(gwIPs).To(HaveLen(2))
+	Expect(gwIPs[0]).To(BeEquivalentTo(expectedPodSubnetThisNodeGatewayIP))
+	Expect(gwIPs[1].String()).To(BeEquivalentTo("fd00:1::101"))
+
+	// one IP address of each family is allocated for the pod
+	ip, err := allocatePodIP(i, podID[0])
+	Expect(err).To(BeNil())
+	Expect(ip.String()).To(BeEquivalentTo("1.2." + str(b10000000) + "." + str(int(nodeID1<<3)+2)))
+	podIPs := i.GetPodIPs(podID[0])
+	Expect(podIPs).To(HaveLen(2))
+	Expect(podIPs[0].String()).To(BeEquivalentTo(ip.String() + "/32"))
+	Expect(podIPs[1].String()).To(BeEquivalentTo("fd00:1::102/128"))
+	Expect(i.GetPodIP(podID[0]).IP).To(BeEquivalentTo(ip))
+	foundPodID, found := i.GetPodFromIP(net.ParseIP("fd00:1::102"))
+	Expect(found).To(BeTrue())
+	Expect(foundPodID).To(BeEquivalentTo(podID[0]))
+	id, err := i.NodeIDFromPodIP(net.ParseIP("fd00:1::102"))
+	Expect(err).To(BeNil())
+	Expect(id).To(BeEquivalentTo(nodeID1))
+
+	// repeated allocation returns the same addresses
+	ip2, err := allocatePodIP(i, podID[0])
+	Expect(err).To(BeNil())
+	Expect(ip2).To(BeEquivalentTo(ip))
+	Expect(i.GetPodIPs(podID[0])).To(BeEquivalentTo(podIPs))
+
+	// secondary IP addresses cannot be requested statically
+	podManager := i.PodManager.(*MockPodManager)
+	podManager.AddRemotePod(&podmanager.Pod{ID: podID[1],
+		Annotations: map[string]string{ipAddressAnnotation: "fd00:1::110"}})
+	_, err = allocatePodIP(i, podID[1])
+	Expect(err).ToNot(BeNil())
+
+	// secondary pod subnet of another node
+	otherNodeSubnets, err := i.PodSubnetsOtherNode(defaultPodNetworkName, nodeID2)
+	Expect(err).To(BeNil())
+	Expect(otherNodeSubnets).To(HaveLen(2))
+	Expect(*otherNodeSubnets[1]).To(BeEquivalentTo(network("fd00:1::500/120")))
+	id, err = i.NodeIDFromPodIP(net.ParseIP("fd00:1::502"))
+	Expect(err).To(BeNil())
+	Expect(id).To(BeEquivalentTo(nodeID2))
+
+	// both IP addresses of a remote pod are learned from Kubernetes
+	remotePod := &podmodel.Pod{
+		Name:        podID[2].Name,
+		Namespace:   podID[2].Namespace,
+		IpAddress:   "1.2." + str(b10000000) + "." + str(int(nodeID2<<3)+2),
+		IpAddresses: []string{"1.2." + str(b10000000) + "." + str(int(nodeID2<<3)+2), "fd00:1::502"},
+	}
+	ksChange := &controller.KubeStateChange{
+		Key:      podmodel.Key(remotePod.Name, remotePod.Namespace),
+		Resource: podmodel.PodKeyword,
+		NewValue: remotePod,
+	}
+	Expect(i.HandlesEvent(ksChange)).To(BeTrue())
+	_, err = i.Update(ksChange, nil)
+	Expect(err).To(BeNil())
+	podIPs = i.GetPodIPs(podID[2])
+	Expect(podIPs).To(HaveLen(2))
+	Expect(podIPs[1].String()).To(BeEquivalentTo("fd00:1::502/128"))
+
+	// both IP addresses are released
+	Expect(i.ReleasePodIPs(podID[0])).To(BeNil())
+	_, found = i.GetPodFromIP(net.ParseIP("fd00:1::102"))
+	Expect(found).To(BeFalse())
+	Expect(i.GetPodIPs(podID[0])).To(BeNil())
+}
+
+func TestDualStackSecondaryPodIPExhausted(t *testing.T) {
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.SecondaryPodSubnetCIDR = "fd00:1::/64"
+	customConfig.IPAMConfig.SecondaryPodSubnetOneNodePrefixLen = 126
+	i := setup(t, customConfig)
+
+	// no IP address left in the secondary pod subnet (except for the gateway)
+	_, err := allocatePodIP(i, podID[0])
+	Expect(err).ToNot(BeNil())
+
+	// the main IP is not left allocated
+	Expect(i.GetPodIPs(podID[0])).To(BeNil())
+	_, found := i.GetPodFromIP(net.ParseIP("1.2." + str(b10000000) + "." + str(int(nodeID1<<3)+2)))
+	Expect(found).To(BeFalse())
+}
+
 // TestMoreThan256Node verifies that IPAM support nodeID that is bigger than 8-bit value
 func TestMoreThan256Node(t *testing.T) {
 	RegisterTestingT(t)
@@ -571,6 +768,475 @@ func TestNodeCIDRIPv6(t *testing.T) {
 
 }
 
+// TestDynamicPodSubnetAllocation tests leasing of per-node pod subnets from the shared pool.
+func TestDynamicPodSubnetAllocation(t *testing.T) {
+	RegisterTestingT(t)
+
+	const (
+		thisNodeID  uint32 = 7
+		otherNodeID uint32 = 5
+	)
+
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.DynamicPodSubnetAllocation = true
+	i, err := newIPAM(customConfig, thisNodeID)
+	Expect(err).To(BeNil())
+	idAlloc := i.IDAlloc.(*MockIDAllocator)
+	eventLoop := i.EventLoop.(*MockEventLoop)
+
+	// this node has leased the first pod subnet, regardless of the node ID
+	Expect(*i.PodSubnetThisNode(defaultPodNetworkName)).To(BeEquivalentTo(expectedPodSubnetThisNode))
+	id, err := i.NodeIDFromPodIP(net.ParseIP("1.2." + str(b10000000) + ".9"))
+	Expect(err).To(BeNil())
+	Expect(id).To(BeEquivalentTo(thisNodeID))
+
+	// other node has not leased any pod subnet yet
+	subnets, err := i.PodSubnetsOtherNode(defaultPodNetworkName, otherNodeID)
+	Expect(err).To(BeNil())
+	Expect(subnets).To(BeEmpty())
+	_, err = i.PodSubnetOtherNode(defaultPodNetworkName, otherNodeID)
+	Expect(err).NotTo(BeNil())
+
+	// other node leases a pod subnet
+	prevPool := idAlloc.GetPool(podSubnetPoolName)
+	idAlloc.SetAllocation(podSubnetPoolName, podSubnetLeaseLabel(otherNodeID, "node2"), 2, "node2")
+	leaseUpdate := &controller.KubeStateChange{
+		Key:       idallocation.Key(podSubnetPoolName),
+		Resource:  idallocation.Keyword,
+		PrevValue: prevPool,
+		NewValue:  idAlloc.GetPool(podSubnetPoolName),
+	}
+	Expect(i.HandlesEvent(leaseUpdate)).To(BeTrue())
+	_, err = i.Update(leaseUpdate, nil)
+	Expect(err).To(BeNil())
+
+	otherNodeSubnet := network("1.2." + str(b10000000) + ".16/29")
+	ipNet, err := i.PodSubnetOtherNode(defaultPodNetworkName, otherNodeID)
+	Expect(err).To(BeNil())
+	Expect(*ipNet).To(BeEquivalentTo(otherNodeSubnet))
+	id, err = i.NodeIDFromPodIP(net.ParseIP("1.2." + str(b10000000) + ".17"))
+	Expect(err).To(BeNil())
+	Expect(id).To(BeEquivalentTo(otherNodeID))
+
+	Expect(eventLoop.EventQueue).To(HaveLen(1))
+	leaseChange := eventLoop.EventQueue[0].(*PodSubnetLeaseChange)
+	Expect(leaseChange.NodeID).To(BeEquivalentTo(otherNodeID))
+	Expect(leaseChange.PrevSubnets).To(BeEmpty())
+	Expect(leaseChange.NewSubnets).To(HaveLen(1))
+	Expect(*leaseChange.NewSubnets[0]).To(BeEquivalentTo(otherNodeSubnet))
+
+	// the ID of the other node is reused by a new node before the lease is released
+	idAlloc.SetAllocation(podSubnetPoolName, podSubnetLeaseLabel(otherNodeID, "node3"), 3, "node3")
+
+	// other node leaves the cluster -> its lease is released by the remaining node
+	// with the lowest ID
+	nodeSync := i.NodeSync.(*MockNodeSync)
+	nodeSync.UpdateNode(&nodesync.Node{ID: 1, Name: "node1"})
+	nodeLeft := &nodesync.NodeUpdate{
+		NodeName:  "node2",
+		PrevState: &nodesync.Node{ID: otherNodeID, Name: "node2"},
+	}
+	Expect(i.HandlesEvent(nodeLeft)).To(BeTrue())
+	_, err = i.Update(nodeLeft, nil)
+	Expect(err).To(BeNil())
+	Expect(idAlloc.GetPool(podSubnetPoolName).IdAllocations).To(HaveKey(podSubnetLeaseLabel(otherNodeID, "node2")))
+	nodeSync.DeleteNode("node1")
+	_, err = i.Update(nodeLeft, nil)
+	Expect(err).To(BeNil())
+	Expect(idAlloc.GetPool(podSubnetPoolName).IdAllocations).ToNot(HaveKey(podSubnetLeaseLabel(otherNodeID, "node2")))
+	Expect(idAlloc.GetPool(podSubnetPoolName).IdAllocations).To(HaveKey(podSubnetLeaseLabel(otherNodeID, "node3")))
+	Expect(idAlloc.GetPool(podSubnetPoolName).IdAllocations).To(HaveKey(podSubnetLeaseLabel(thisNodeID, nodeName)))
+	Expect(idAlloc.ForceReleaseID(podSubnetPoolName, podSubnetLeaseLabel(otherNodeID, "node3"))).To(Succeed())
+
+	leaseUpdate = &controller.KubeStateChange{
+		Key:       idallocation.Key(podSubnetPoolName),
+		Resource:  idallocation.Keyword,
+		PrevValue: leaseUpdate.NewValue,
+		NewValue:  idAlloc.GetPool(podSubnetPoolName),
+	}
+	_, err = i.Update(leaseUpdate, nil)
+	Expect(err).To(BeNil())
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+	leaseChange = eventLoop.EventQueue[1].(*PodSubnetLeaseChange)
+	Expect(leaseChange.PrevSubnets).To(HaveLen(1))
+	Expect(*leaseChange.PrevSubnets[0]).To(BeEquivalentTo(otherNodeSubnet))
+	Expect(leaseChange.NewSubnets).To(BeEmpty())
+	_, err = i.PodSubnetOtherNode(defaultPodNetworkName, otherNodeID)
+	Expect(err).NotTo(BeNil())
+
+	// lost lease of this node is a fatal error
+	Expect(idAlloc.ForceReleaseID(podSubnetPoolName, podSubnetLeaseLabel(thisNodeID, nodeName))).To(Succeed())
+	leaseUpdate = &controller.KubeStateChange{
+		Key:       idallocation.Key(podSubnetPoolName),
+		Resource:  idallocation.Keyword,
+		PrevValue: leaseUpdate.NewValue,
+		NewValue:  idAlloc.GetPool(podSubnetPoolName),
+	}
+	_, err = i.Update(leaseUpdate, nil)
+	Expect(err).To(BeAssignableToTypeOf(&controller.FatalError{}))
+}
+
+// TestPodIPReservations tests allocation of pod IPs requested via annotations.
+func TestPodIPReservations(t *testing.T) {
+	RegisterTestingT(t)
+
+	// the pod subnet gets full, do not warn about its utilization
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.IPUsageWarningThreshold = 100
+	customConfig.IPAMConfig.IPLeakGracePeriod = 60
+	i, err := newIPAM(customConfig, nodeID1)
+	Expect(err).To(BeNil())
+	podManager := i.PodManager.(*MockPodManager)
+	eventLoop := i.EventLoop.(*MockEventLoop)
+
+	podIP := func(lastByte int) net.IP {
+		return net.IPv4(1, 2, b10000000, byte(lastByte)).To4()
+	}
+	addPod := func(podID podmodel.ID, annotations map[string]string) {
+		podManager.AddRemotePod(&podmanager.Pod{ID: podID, Annotations: annotations})
+	}
+	remoteDB := i.RemoteDB.(*MockRemoteDB)
+	dbKey := func(ip string) string {
+		return servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel) + ipalloc.ReservationKey(ip)
+	}
+	dbReservation := func(ip string) *ipalloc.IPReservation {
+		data, found := remoteDB.Get(dbKey(ip))
+		if !found {
+			return nil
+		}
+		reservation := &ipalloc.IPReservation{}
+		Expect(i.serializer.Unmarshal(data, reservation)).To(Succeed())
+		return reservation
+	}
+	reflectReservation := func(reservation *ipalloc.IPReservation) {
+		data, err := i.serializer.Marshal(reservation)
+		Expect(err).To(BeNil())
+		remoteDB.Put(dbKey(reservation.IpAddress), data)
+		ksChange := &controller.KubeStateChange{
+			Key:      ipalloc.ReservationKey(reservation.IpAddress),
+			Resource: ipalloc.ReservationKeyword,
+			NewValue: reservation,
+		}
+		Expect(i.HandlesEvent(ksChange)).To(BeTrue())
+		_, err = i.Update(ksChange, nil)
+		Expect(err).To(BeNil())
+	}
+
+	// static IP reserved for pod1, named reservation held by pod2
+	reflectReservation(&ipalloc.IPReservation{
+		IpAddress:    podIP(12).String(),
+		PodName:      podID[0].Name,
+		PodNamespace: podID[0].Namespace,
+	})
+	reflectReservation(&ipalloc.IPReservation{
+		IpAddress:    podIP(11).String(),
+		Name:         "db-0",
+		PodName:      podID[1].Name,
+		PodNamespace: podID[1].Namespace,
+	})
+
+	// pods without annotations do not get reserved IPs
+	ip, err := allocatePodIP(i, podID[3])
+	Expect(err).To(BeNil())
+	Expect(ip).To(BeEquivalentTo(podIP(10)))
+	ip, err = allocatePodIP(i, podID[2])
+	Expect(err).To(BeNil())
+	Expect(ip).To(BeEquivalentTo(podIP(13)))
+
+	// reserved IPs are assigned to the pods holding the reservations
+	addPod(podID[0], map[string]string{ipAddressAnnotation: podIP(12).String()})
+	ip, err = allocatePodIP(i, podID[0])
+	Expect(err).To(BeNil())
+	Expect(ip).To(BeEquivalentTo(podIP(12)))
+	addPod(podID[1], map[string]string{ipReservationAnnotation: "db-0"})
+	ip, err = allocatePodIP(i, podID[1])
+	Expect(err).To(BeNil())
+	Expect(ip).To(BeEquivalentTo(podIP(11)))
+	Expect(eventLoop.EventQueue).To(BeEmpty())
+
+	// static IP requested by another pod
+	otherPod := podmodel.ID{Name: "pod5", Namespace: "default"}
+	addPod(otherPod, map[string]string{ipAddressAnnotation: podIP(12).String()})
+	_, err = allocatePodIP(i, otherPod)
+	Expect(err).ToNot(BeNil())
+	Expect(eventLoop.EventQueue).To(HaveLen(1))
+	conflict := eventLoop.EventQueue[0].(*IPReservationConflict)
+	Expect(conflict.PodID).To(Equal(otherPod))
+	Expect(conflict.Owner).To(Equal(podID[0]))
+	Expect(conflict.IP.Equal(podIP(12))).To(BeTrue())
+
+	// named reservation requested by a new pod while still in use
+	otherPod = podmodel.ID{Name: "pod6", Namespace: "default"}
+	addPod(otherPod, map[string]string{ipReservationAnnotation: "db-0"})
+	_, err = i.AllocatePodIP(otherPod, "", "")
+	Expect(err).ToNot(BeNil())
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+
+	// gateway, IPs from other nodes and malformed IPs cannot be requested
+	otherPod = podmodel.ID{Name: "pod7", Namespace: "default"}
+	for _, requestedIP := range []string{podIP(9).String(), podIP(14).String(), "1.2.128.17", "not-an-ip"} {
+		addPod(otherPod, map[string]string{ipAddressAnnotation: requestedIP})
+		_, err = i.AllocatePodIP(otherPod, "", "")
+		Expect(err).ToNot(BeNil())
+	}
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+
+	// IP cannot be allocated before k8s metadata of the pod are reflected
+	_, err = i.AllocatePodIP(podmodel.ID{Name: "pod8", Namespace: "default"}, "", "")
+	Expect(err).ToNot(BeNil())
+
+	// named reservations are scoped by namespace
+	Expect(i.ReleasePodIPs(podID[3])).To(BeNil())
+	otherPod = podmodel.ID{Name: "pod2", Namespace: "kube-system"}
+	addPod(otherPod, map[string]string{ipReservationAnnotation: "db-0"})
+	ip, err = i.AllocatePodIP(otherPod, "", "")
+	Expect(err).To(BeNil())
+	Expect(ip).To(BeEquivalentTo(podIP(10)))
+	reservation := dbReservation(podIP(10).String())
+	Expect(reservation).ToNot(BeNil())
+	Expect(reservation.Name).To(Equal("db-0"))
+	Expect(reservation.PodNamespace).To(Equal("kube-system"))
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+
+	// named reservation of a pod rescheduled from another node is moved to a local IP
+	Expect(i.ReleasePodIPs(podID[2])).To(BeNil())
+	remoteIP := "1.2.128.17"
+	reflectReservation(&ipalloc.IPReservation{
+		IpAddress:    remoteIP,
+		Name:         "web-0",
+		PodName:      "web-0",
+		PodNamespace: "default",
+	})
+	otherPod = podmodel.ID{Name: "web-0", Namespace: "default"}
+	addPod(otherPod, map[string]string{ipReservationAnnotation: "web-0"})
+	ip, err = i.AllocatePodIP(otherPod, "", "")
+	Expect(err).To(BeNil())
+	Expect(ip).To(BeEquivalentTo(podIP(13)))
+	Expect(dbReservation(remoteIP)).To(BeNil())
+	reservation = dbReservation(podIP(13).String())
+	Expect(reservation).ToNot(BeNil())
+	Expect(reservation.Name).To(Equal("web-0"))
+	Expect(i.isReservedIP(net.ParseIP(remoteIP))).To(BeFalse())
+
+	// static reservation is removed once the pod is gone for longer than the grace period
+	Expect(i.ReleasePodIPs(podID[0])).To(BeNil())
+	podManager.DeletePod(podID[0])
+	now := time.Now()
+	Expect(i.collectStaleReservations(now)).To(Equal(0))
+	Expect(i.collectStaleReservations(now.Add(61 * time.Second))).To(Equal(1))
+	Expect(dbReservation(podIP(12).String())).To(BeNil())
+	Expect(i.isReservedIP(podIP(12))).To(BeFalse())
+
+	// named reservations are kept
+	Expect(i.ReleasePodIPs(podID[1])).To(BeNil())
+	podManager.DeletePod(podID[1])
+	Expect(i.collectStaleReservations(now.Add(time.Hour))).To(Equal(0))
+	Expect(i.isReservedIP(podIP(11))).To(BeTrue())
+}
+
+// TestIPUsage tests reporting of the IP address utilization and the warning about
+// the exceeded threshold.
+// TestPodIPBeforeMetadata tests allocation of pod IP address for the CNI request
+// received before the k8s metadata of the pod are reflected.
+func TestPodIPBeforeMetadata(t *testing.T) {
+	i := setup(t, newDefaultConfig())
+	podManager := i.PodManager.(*MockPodManager)
+
+	// annotations are not known yet -> dynamically allocated IP address
+	ip, err := i.AllocatePodIP(podID[0], "", "")
+	Expect(err).To(BeNil())
+	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue())
+	Expect(i.GetPodIP(podID[0]).IP).To(BeEquivalentTo(ip))
+
+	// k8s metadata arrive later, the allocated IP address is kept
+	podManager.AddRemotePod(&podmanager.Pod{ID: podID[0], IPAddress: ip.String(),
+		Annotations: map[string]string{ipAddressAnnotation: "1.2.128.100"}})
+	ksChange := &controller.KubeStateChange{
+		Key:      podmodel.Key(podID[0].Name, podID[0].Namespace),
+		Resource: podmodel.PodKeyword,
+		NewValue: &podmodel.Pod{
+			Name:      podID[0].Name,
+			Namespace: podID[0].Namespace,
+			IpAddress: ip.String(),
+		},
+	}
+	Expect(i.HandlesEvent(ksChange)).To(BeTrue())
+	_, err = i.Update(ksChange, nil)
+	Expect(err).To(BeNil())
+	sameIP, err := i.AllocatePodIP(podID[0], "", "")
+	Expect(err).To(BeNil())
+	Expect(sameIP).To(BeEquivalentTo(ip))
+	Expect(i.GetPodIP(podID[0]).IP).To(BeEquivalentTo(ip))
+}
+
+func TestIPUsage(t *testing.T) {
+	RegisterTestingT(t)
+
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.IPUsageWarningThreshold = 50
+	i, err := newIPAM(customConfig, nodeID1)
+	Expect(err).To(BeNil())
+	eventLoop := i.EventLoop.(*MockEventLoop)
+
+	blocks := i.getIPUsage()
+	Expect(blocks).To(HaveLen(2))
+	Expect(blocks[0].Network).To(Equal(defaultPodNetworkName))
+	Expect(blocks[0].Type).To(Equal(restapi.PodBlock))
+	Expect(blocks[0].Subnet).To(Equal(expectedPodSubnetThisNode.String()))
+	Expect(blocks[0].Allocated).To(BeEquivalentTo(0))
+	Expect(blocks[0].Capacity).To(BeEquivalentTo(4))
+	Expect(blocks[1].Type).To(Equal(restapi.VPPHostBlock))
+	Expect(blocks[1].Subnet).To(Equal(expectedVSwitchNetwork.String()))
+	Expect(blocks[1].Allocated).To(BeEquivalentTo(2))
+	Expect(blocks[1].Capacity).To(BeEquivalentTo(2))
+	Expect(blocks[1].ThresholdExceeded).To(BeFalse())
+
+	// utilization at the threshold
+	for _, pod := range podID[:2] {
+		_, err = allocatePodIP(i, pod)
+		Expect(err).To(BeNil())
+	}
+	blocks = i.getIPUsage()
+	Expect(blocks[0].Allocated).To(BeEquivalentTo(2))
+	Expect(blocks[0].Utilization).To(BeEquivalentTo(50))
+	Expect(blocks[0].ThresholdExceeded).To(BeFalse())
+	Expect(eventLoop.EventQueue).To(BeEmpty())
+
+	// threshold exceeded - reported only once
+	for _, pod := range podID[2:] {
+		_, err = allocatePodIP(i, pod)
+		Expect(err).To(BeNil())
+	}
+	blocks = i.getIPUsage()
+	Expect(blocks[0].Allocated).To(BeEquivalentTo(4))
+	Expect(blocks[0].ThresholdExceeded).To(BeTrue())
+	Expect(eventLoop.EventQueue).To(HaveLen(1))
+	warning := eventLoop.EventQueue[0].(*IPUsageThresholdExceeded)
+	Expect(warning.Network).To(Equal(defaultPodNetworkName))
+	Expect(warning.Subnet).To(Equal(expectedPodSubnetThisNode.String()))
+	Expect(warning.Allocated).To(BeEquivalentTo(3))
+	Expect(warning.Capacity).To(BeEquivalentTo(4))
+
+	// utilization drops below and exceeds the threshold again
+	Expect(i.ReleasePodIPs(podID[3])).To(Succeed())
+	Expect(i.ReleasePodIPs(podID[2])).To(Succeed())
+	Expect(i.getIPUsage()[0].ThresholdExceeded).To(BeFalse())
+	_, err = allocatePodIP(i, podID[2])
+	Expect(err).To(BeNil())
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+}
+
+// TestPodIPGarbageCollection tests removal of pods which no longer run on this node
+// and release of their leaked IP addresses.
+func TestPodIPGarbageCollection(t *testing.T) {
+	RegisterTestingT(t)
+
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.IPLeakGracePeriod = 60
+	i, err := newIPAM(customConfig, nodeID1)
+	Expect(err).To(BeNil())
+	podManager := i.PodManager.(*MockPodManager)
+	eventLoop := i.EventLoop.(*MockEventLoop)
+	Expect(i.HandlesEvent(&PodIPGarbageCollection{})).To(BeTrue())
+	Expect(i.HandlesEvent(&podmanager.DeletePod{Pod: podID[0]})).To(BeFalse())
+
+	ips := make(map[podmodel.ID]net.IP)
+	for _, pod := range podID {
+		ips[pod], err = allocatePodIP(i, pod)
+		Expect(err).To(BeNil())
+	}
+
+	// pod1 runs locally, pod2 is starting, pod3 was added locally but its sandbox
+	// is gone without CNI DEL, pod4 was re-created on another node (and still exists in k8s)
+	podManager.AddPod(&podmanager.LocalPod{ID: podID[0]})
+	podManager.AddPod(&podmanager.LocalPod{ID: podID[2]})
+	podManager.AddRemotePod(&podmanager.Pod{ID: podID[3], IPAddress: "1.2.128.17"})
+	runningPods := podmanager.LocalPods{
+		podID[0]: &podmanager.LocalPod{ID: podID[0]},
+	}
+
+	// leaks are detected, but the pods are not removed during the grace period
+	now := time.Now()
+	Expect(i.detectLeakedPods(now, runningPods)).To(BeEmpty())
+	allocated, leaked, reclaimed := i.podIPCounts()
+	Expect(allocated).To(Equal(4))
+	Expect(leaked).To(Equal(3))
+	Expect(reclaimed).To(Equal(0))
+
+	// pod2 is running now
+	runningPods[podID[1]] = &podmanager.LocalPod{ID: podID[1]}
+	Expect(i.detectLeakedPods(now.Add(30*time.Second), runningPods)).To(BeEmpty())
+	_, leaked, _ = i.podIPCounts()
+	Expect(leaked).To(Equal(2))
+
+	// grace period for pod3 and pod4 has expired, the pods are removed using DeletePod
+	Expect(i.detectLeakedPods(now.Add(61*time.Second), runningPods)).To(ConsistOf(
+		&podmanager.LocalPod{ID: podID[2]}, &podmanager.LocalPod{ID: podID[3]}))
+	Expect(i.GetPodIP(podID[2])).ToNot(BeNil())
+	for _, leak := range i.leakedIPs {
+		leak.since = leak.since.Add(-61 * time.Second)
+	}
+	_, err = i.Update(&PodIPGarbageCollection{RunningPods: runningPods}, nil)
+	Expect(err).To(BeNil())
+	Expect(eventLoop.EventQueue).To(HaveLen(2))
+	for _, event := range eventLoop.EventQueue {
+		deletePod := event.(*podmanager.DeletePod)
+		Expect(deletePod.Leaked).To(BeTrue())
+		Expect(deletePod.IsBlocking()).To(BeFalse())
+		Expect(i.HandlesEvent(deletePod)).To(BeTrue())
+		_, err = i.Update(deletePod, nil)
+		Expect(err).To(BeNil())
+	}
+	Expect(i.GetPodIP(podID[2])).To(BeNil())
+	Expect(i.GetPodIP(podID[3])).To(BeNil())
+	Expect(i.GetPodIP(podID[1])).ToNot(BeNil())
+	allocated, leaked, reclaimed = i.podIPCounts()
+	Expect(allocated).To(Equal(2))
+	Expect(leaked).To(Equal(0))
+	Expect(reclaimed).To(Equal(2))
+}
+
+// TestPodIPGarbageCollectionReAddedPod tests that leaked pod re-added before
+// its removal is processed is not removed.
+func TestPodIPGarbageCollectionReAddedPod(t *testing.T) {
+	RegisterTestingT(t)
+
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.IPLeakGracePeriod = 60
+	i, err := newIPAM(customConfig, nodeID1)
+	Expect(err).To(BeNil())
+	podManager := i.PodManager.(*MockPodManager)
+	eventLoop := i.EventLoop.(*MockEventLoop)
+
+	// sandbox of pod1 is gone without CNI DEL
+	podManager.AddPod(&podmanager.LocalPod{ID: podID[0], ContainerID: "sandbox1"})
+	ip, err := allocatePodIP(i, podID[0])
+	Expect(err).To(BeNil())
+	Expect(i.detectLeakedPods(time.Now().Add(-61*time.Second), nil)).To(BeEmpty())
+	_, err = i.Update(&PodIPGarbageCollection{}, nil)
+	Expect(err).To(BeNil())
+	Expect(eventLoop.EventQueue).To(HaveLen(1))
+	deletePod := eventLoop.EventQueue[0].(*podmanager.DeletePod)
+	Expect(deletePod.Leaked).To(BeTrue())
+	Expect(deletePod.ContainerID).To(Equal("sandbox1"))
+	Expect(deletePod.IsObsolete()).To(BeFalse())
+
+	// pod1 is re-added with a new sandbox before the removal gets processed
+	podManager.AddPod(&podmanager.LocalPod{ID: podID[0], ContainerID: "sandbox2"})
+	sameIP, err := i.AllocatePodIP(podID[0], "", "")
+	Expect(err).To(BeNil())
+	Expect(sameIP).To(BeEquivalentTo(ip))
+	Expect(deletePod.IsObsolete()).To(BeTrue())
+
+	// even if processed, the IP address of the re-added pod is not released
+	_, err = i.Update(deletePod, nil)
+	Expect(err).To(BeNil())
+	Expect(i.GetPodIP(podID[0]).IP).To(BeEquivalentTo(ip))
+	_, leaked, reclaimed := i.podIPCounts()
+	Expect(leaked).To(Equal(0))
+	Expect(reclaimed).To(Equal(0))
+}
+
 // TestConfigWithBadCIDR test if IPAM detects incorrect unparsable CIDR string and handles it correctly
 // (initialization returns error)
 func TestConfigWithBadCIDR(t *testing.T) {
@@ -590,6 +1256,16 @@ func TestConfigWithBadCIDR(t *testing.T) {
 	customConfig.IPAMConfig.NodeInterconnectCIDR = "1.2.3./19"
 	_, err = newIPAM(customConfig, nodeID1)
 	Expect(err).NotTo(BeNil(), "Host subnet CIDR is unparsable, but IPAM initialization didn't fail")
+
+	customConfig = newDefaultConfig()
+	customConfig.IPAMConfig.AdditionalPodSubnetCIDRs = []string{"5.6.7./25"}
+	_, err = newIPAM(customConfig, nodeID1)
+	Expect(err).NotTo(BeNil(), "Additional pod subnet CIDR is unparsable, but IPAM initialization didn't fail")
+
+	customConfig = newDefaultConfig()
+	customConfig.IPAMConfig.AdditionalPodSubnetCIDRs = []string{"1.2." + str(b10000000) + ".0/25"}
+	_, err = newIPAM(customConfig, nodeID1)
+	Expect(err).NotTo(BeNil(), "Additional pod subnet overlaps with pod subnet, but IPAM initialization didn't fail")
 }
 
 // TestConfigWithBadPrefixSizes tests if IPAM detects incorrect prefix length of subnet and network
@@ -647,7 +1323,7 @@ func TestExcludeGateway(t *testing.T) {
 func exhaustPodIPAddresses(i *IPAM, maxIPCount int) (allocatedIPs []string, allocatedPodIDS []podmodel.ID) {
 	for j := 1; j <= maxIPCount; j++ {
 		podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(j)}
-		ip, _ := i.AllocatePodIP(podID, "", "")
+		ip, _ := allocatePodIP(i, podID)
 		allocatedIPs = append(allocatedIPs, ip.To4().String())
 		allocatedPodIDS = append(allocatedPodIDS, podID)
 	}
@@ -669,7 +1345,7 @@ func releaseAllPodAddresses(i *IPAM, ipCount int) {
 
 func assertCorrectIPExhaustion(i *IPAM, maxIPCount int) {
 	podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(maxIPCount+1)}
-	_, err := i.AllocatePodIP(podID, "", "")
+	_, err := allocatePodIP(i, podID)
 	Expect(err).NotTo(BeNil(), "Pool of free IP addresses should be empty, but IPAM allocation function didn't fail")
 }
 
@@ -677,7 +1353,7 @@ func assertAllocationOfIPAddresses(i *IPAM, expectedIPs []string, network net.IP
 	freeIPsCount := len(expectedIPs)
 	for j := 1; j <= freeIPsCount; j++ {
 		podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(j) + "-secondAllocation"}
-		ip, err := i.AllocatePodIP(podID, "", "")
+		ip, err := allocatePodIP(i, podID)
 		Expect(err).To(BeNil(), "Can't successfully allocate %v. IP address", j)
 		assertAllocationOfIPAddress(ip, network)
 		Expect(expectedIPs).To(ContainElement(ip.String()), "Allocated IP is not from given IP slice")
@@ -688,7 +1364,7 @@ func assertAllocationOfAllIPAddresses(i *IPAM, maxIPCount int, network net.IPNet
 	allocated := make(map[string]bool, maxIPCount)
 	for j := 1; j <= maxIPCount; j++ {
 		podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(j)}
-		ip, err := i.AllocatePodIP(podID, "", "")
+		ip, err := allocatePodIP(i, podID)
 		Expect(err).To(BeNil(),
 			"Can't successfully allocate %v. IP address out of %v possible IP addresses", j, maxIPCount)
 		Expect(allocated[ip.String()]).To(BeFalse(), "IP address %v is allocated second time", ip)
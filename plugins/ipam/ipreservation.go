@@ -0,0 +1,486 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"go.ligato.io/cn-infra/v2/db/keyval"
+
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
+	"github.com/americanbinary/vpp/plugins/ipam/ipalloc"
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+)
+
+const (
+	contivAnnotationPrefix  = "contivpp.io/"
+	ipAddressAnnotation     = contivAnnotationPrefix + "ip-address"     // k8s annotation used to request a specific pod IP address
+	ipReservationAnnotation = contivAnnotationPrefix + "ip-reservation" // k8s annotation used to request a named IP reservation
+)
+
+// podIPRequest is a request for a specific IP address and/or a named IP reservation
+// made by a pod via annotations.
+type podIPRequest struct {
+	ip          net.IP // nil if not requested
+	reservation string // empty if not requested
+}
+
+// String provides human-readable representation of podIPRequest
+func (r *podIPRequest) String() string {
+	return fmt.Sprintf("<ip=%v, reservation=%s>", r.ip, r.reservation)
+}
+
+// parsePodIPRequest parses pod annotations for a specific IP address and/or a named
+// IP reservation. Returns nil if the pod does not request any.
+func parsePodIPRequest(annotations map[string]string) (*podIPRequest, error) {
+	request := &podIPRequest{
+		reservation: strings.TrimSpace(annotations[ipReservationAnnotation]),
+	}
+	if ipStr, hasIP := annotations[ipAddressAnnotation]; hasIP {
+		request.ip = parseIP(strings.TrimSpace(ipStr))
+		if request.ip == nil {
+			return nil, fmt.Errorf("invalid IP address in the %s annotation: %s", ipAddressAnnotation, ipStr)
+		}
+	}
+	if request.ip == nil && request.reservation == "" {
+		return nil, nil
+	}
+	return request, nil
+}
+
+// parseIP parses IP address, IPv4 addresses are returned in the 4-byte representation
+// (same as allocated by IPAM).
+func parseIP(ipStr string) net.IP {
+	ip := net.ParseIP(ipStr)
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
+// getPodIPRequest returns IP request made by the given pod via annotations, nil if none.
+// CNI request may arrive before k8s metadata of the pod are reflected - the annotations
+// are not known yet and the pod gets an IP address dynamically allocated from the pod subnet.
+func (i *IPAM) getPodIPRequest(podID podmodel.ID) (*podIPRequest, error) {
+	pod, hasPod := i.PodManager.GetPods()[podID]
+	if !hasPod {
+		i.Log.Warnf("K8s metadata of pod %v are not reflected yet, IP address requested "+
+			"via annotations (if any) cannot be honored", podID)
+		return nil, nil
+	}
+	return parsePodIPRequest(pod.Annotations)
+}
+
+// resyncReservations loads all IP reservations from the Kubernetes state data.
+func (i *IPAM) resyncReservations(kubeStateData controller.KubeStateData) {
+	i.reservations = make(map[string]*ipalloc.IPReservation)
+	for _, reservationProto := range kubeStateData[ipalloc.ReservationKeyword] {
+		i.updateReservation(nil, reservationProto.(*ipalloc.IPReservation))
+	}
+}
+
+// updateReservation updates the internal cache of IP reservations.
+func (i *IPAM) updateReservation(prevValue, newValue *ipalloc.IPReservation) {
+	if prevValue != nil {
+		delete(i.reservations, prevValue.IpAddress)
+	}
+	if newValue != nil {
+		ip := net.ParseIP(newValue.IpAddress)
+		if ip == nil {
+			i.Log.Warnf("Ignoring IP reservation with invalid IP address: %v", newValue)
+			return
+		}
+		i.reservations[ip.String()] = newValue
+	}
+}
+
+// lookupReservation returns existing reservation matching the IP request, nil if there is none.
+// Named reservations are scoped by the namespace of the pod.
+func (i *IPAM) lookupReservation(podID podmodel.ID, request *podIPRequest) *ipalloc.IPReservation {
+	if request.reservation != "" {
+		for _, reservation := range i.reservations {
+			if reservation.Name == request.reservation && reservation.PodNamespace == podID.Namespace {
+				return reservation
+			}
+		}
+	}
+	if request.ip != nil {
+		return i.reservations[request.ip.String()]
+	}
+	return nil
+}
+
+// isReservedIP returns true if the IP address is reserved (for any pod).
+func (i *IPAM) isReservedIP(ip net.IP) bool {
+	_, reserved := i.reservations[ip.String()]
+	return reserved
+}
+
+// allocateRequestedIP allocates the IP address requested by a pod via annotations.
+// The request is recorded as a reservation persisted in the KV DB, so that the same
+// IP address is assigned again to the pod after a restart or, in the case of a named
+// reservation, to another pod from the same namespace requesting the same reservation
+// (e.g. new instance of a StatefulSet pod).
+// Pod subnets are node-specific, therefore a pod holding a named reservation which gets
+// rescheduled to another node is assigned a new IP address from the pod subnet of that
+// node and the reservation is moved to it. Pods with a static IP address (ip-address
+// annotation) cannot be rescheduled to another node.
+// Requests conflicting with an existing reservation are reported via IPReservationConflict
+// event.
+func (i *IPAM) allocateRequestedIP(podID podmodel.ID, request *podIPRequest) (net.IP, error) {
+	podNw := i.podNetworks[defaultPodNetworkName]
+
+	reservation := i.lookupReservation(podID, request)
+	if reservation != nil {
+		if conflict := i.checkReservation(podID, request, reservation); conflict != nil {
+			return nil, i.reportReservationConflict(conflict)
+		}
+		ip := parseIP(reservation.IpAddress)
+		if request.ip == nil && !podNw.isLocalIP(ip) {
+			// the pod holding the named reservation has been rescheduled from another node
+			return i.moveReservation(podNw, podID, reservation)
+		}
+		if err := i.checkRequestedIP(podNw, podID, ip); err != nil {
+			return nil, err
+		}
+		if err := i.activatePodSubnetOfIP(podNw, ip); err != nil {
+			return nil, err
+		}
+		if reservation.PodName != podID.Name || reservation.PodNamespace != podID.Namespace {
+			// named reservation taken over by another pod
+			takenOver := &ipalloc.IPReservation{
+				IpAddress:    reservation.IpAddress,
+				Name:         reservation.Name,
+				PodName:      podID.Name,
+				PodNamespace: podID.Namespace,
+			}
+			if err := i.swapReservation(reservation, takenOver); err != nil {
+				return nil, err
+			}
+			reservation = takenOver
+		}
+		i.Log.Infof("Assigned reserved pod IP %s (reservation %q) for POD ID %v", ip, reservation.Name, podID)
+		return ip, nil
+	}
+
+	// create a new reservation
+	ip := request.ip
+	if ip == nil {
+		var err error
+		ip, err = i.allocateIP(podNw)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := i.checkRequestedIP(podNw, podID, ip); err != nil {
+		return nil, err
+	} else if err := i.activatePodSubnetOfIP(podNw, ip); err != nil {
+		return nil, err
+	}
+	reservation = &ipalloc.IPReservation{
+		IpAddress:    ip.String(),
+		Name:         request.reservation,
+		PodName:      podID.Name,
+		PodNamespace: podID.Namespace,
+	}
+	if err := i.putReservation(reservation); err != nil {
+		return nil, err
+	}
+	i.Log.Infof("Reserved pod IP %s (reservation %q) for POD ID %v", ip, reservation.Name, podID)
+	return ip, nil
+}
+
+// moveReservation moves named reservation of a pod rescheduled from another node
+// to a new IP address allocated from the pod subnet of this node.
+func (i *IPAM) moveReservation(podNw *podNetworkInfo, podID podmodel.ID,
+	reservation *ipalloc.IPReservation) (net.IP, error) {
+
+	for _, pod := range i.PodManager.GetPods() {
+		if pod.ID != podID && pod.IPAddress == reservation.IpAddress {
+			return nil, i.reportReservationConflict(&IPReservationConflict{
+				PodID:       podID,
+				IP:          parseIP(reservation.IpAddress),
+				Reservation: reservation.Name,
+				Owner:       podmodel.ID{Name: reservation.PodName, Namespace: reservation.PodNamespace},
+				Reason: fmt.Sprintf("reserved IP address %s is still in use by pod %v",
+					reservation.IpAddress, pod.ID),
+			})
+		}
+	}
+	ip, err := i.allocateIP(podNw)
+	if err != nil {
+		return nil, err
+	}
+	moved := &ipalloc.IPReservation{
+		IpAddress:    ip.String(),
+		Name:         reservation.Name,
+		PodName:      podID.Name,
+		PodNamespace: podID.Namespace,
+	}
+	if err := i.deleteReservation(reservation); err != nil {
+		return nil, err
+	}
+	if err := i.putReservation(moved); err != nil {
+		// try to restore the original reservation
+		if restoreErr := i.putReservation(reservation); restoreErr != nil {
+			i.Log.Errorf("Failed to restore IP reservation %v: %v", reservation, restoreErr)
+		}
+		return nil, err
+	}
+	i.Log.Infof("Moved IP reservation %q from IP %s to pod IP %s for POD ID %v",
+		reservation.Name, reservation.IpAddress, ip, podID)
+	return ip, nil
+}
+
+// reportReservationConflict reports the conflict via IPReservationConflict event
+// and returns it as an error.
+func (i *IPAM) reportReservationConflict(conflict *IPReservationConflict) error {
+	i.EventLoop.PushEvent(conflict)
+	err := fmt.Errorf("IP reservation conflict: %s", conflict.Reason)
+	i.Log.Warn(err)
+	return err
+}
+
+// checkReservation checks if the pod may use the existing reservation matching its IP request.
+// Returns IPReservationConflict event describing the conflict, nil if there is none.
+func (i *IPAM) checkReservation(podID podmodel.ID, request *podIPRequest,
+	reservation *ipalloc.IPReservation) *IPReservationConflict {
+
+	owner := podmodel.ID{Name: reservation.PodName, Namespace: reservation.PodNamespace}
+	conflict := &IPReservationConflict{
+		PodID:       podID,
+		IP:          parseIP(reservation.IpAddress),
+		Reservation: reservation.Name,
+		Owner:       owner,
+	}
+	switch {
+	case request.ip != nil && !request.ip.Equal(conflict.IP):
+		conflict.Reason = fmt.Sprintf("reservation %q holds IP address %s, not %s",
+			reservation.Name, reservation.IpAddress, request.ip)
+	case reservation.Name == "" && (request.reservation != "" || owner != podID):
+		conflict.Reason = fmt.Sprintf("IP address %s is reserved for pod %v", reservation.IpAddress, owner)
+	case reservation.Name != request.reservation:
+		conflict.Reason = fmt.Sprintf("IP address %s is reserved under the name %q",
+			reservation.IpAddress, reservation.Name)
+	case reservation.Name != "" && owner.Namespace != podID.Namespace:
+		conflict.Reason = fmt.Sprintf("reservation %q belongs to the namespace %s",
+			reservation.Name, owner.Namespace)
+	default:
+		allocation, assigned := i.assignedPodIPs[conflict.IP.String()]
+		if !assigned || allocation.pod == podID {
+			return nil
+		}
+		conflict.Reason = fmt.Sprintf("reserved IP address %s is still in use by pod %v",
+			reservation.IpAddress, allocation.pod)
+	}
+	return conflict
+}
+
+// checkRequestedIP checks if the given IP address can be assigned to the pod.
+func (i *IPAM) checkRequestedIP(podNw *podNetworkInfo, podID podmodel.ID, ip net.IP) error {
+	if allocation, assigned := i.assignedPodIPs[ip.String()]; assigned && allocation.pod != podID {
+		return fmt.Errorf("requested IP address %s is already assigned to pod %v", ip, allocation.pod)
+	}
+	if podNw.isSecondaryIP(ip) {
+		// secondary IP addresses (dual-stack) are always allocated dynamically
+		return fmt.Errorf("requested IP address %s is from the secondary pod subnet", ip)
+	}
+	subnets := []*net.IPNet{podNw.podSubnetThisNode}
+	for _, pool := range podNw.additionalPools {
+		// requested IP may be from an additional pod subnet not activated yet
+		subnets = append(subnets, podNw.poolSubnetThisNode(pool))
+	}
+	for _, subnet := range subnets {
+		if subnet == nil || !subnet.Contains(ip) {
+			continue
+		}
+		// exclude network address, gateway and the last unicast IP used as NAT-loopback
+		prefixBits, totalBits := subnet.Mask.Size()
+		maxSeqID := new(big.Int).Lsh(big.NewInt(1), uint(totalBits-prefixBits))
+		maxSeqID.Sub(maxSeqID, big.NewInt(2))
+		seqID := new(big.Int).SetBytes(ip.To16())
+		seqID.Sub(seqID, new(big.Int).SetBytes(subnet.IP.To16()))
+		if seqID.Cmp(big.NewInt(podGatewaySeqID)) <= 0 || seqID.Cmp(maxSeqID) >= 0 {
+			return fmt.Errorf("requested IP address %s is reserved by IPAM", ip)
+		}
+		return nil
+	}
+	return fmt.Errorf("requested IP address %s is not from the pod subnets of this node %v",
+		ip, subnets)
+}
+
+// activatePodSubnetOfIP activates the additional pod subnet of this node
+// which the given requested IP belongs to (if it is not active already).
+func (i *IPAM) activatePodSubnetOfIP(podNw *podNetworkInfo, ip net.IP) error {
+	for _, pool := range podNw.additionalPools {
+		if subnet := podNw.poolSubnetThisNode(pool); subnet != nil && subnet.Contains(ip) {
+			return i.activatePodSubnet(podNw, pool)
+		}
+	}
+	return nil
+}
+
+// putReservation persists a new IP reservation into the KV DB.
+// Fails if the IP address has been reserved in the meantime (e.g. by another node
+// with a stale view of the reservations).
+// The internal cache is updated immediately, not waiting for the change to be reflected.
+func (i *IPAM) putReservation(reservation *ipalloc.IPReservation) error {
+	db, data, err := i.encodeReservation(reservation)
+	if err != nil {
+		i.Log.Errorf("Unable to persist IP reservation: %v", err)
+		return err
+	}
+	succeeded, err := db.PutIfNotExists(ipalloc.ReservationKey(reservation.IpAddress), data)
+	if err != nil {
+		i.Log.Errorf("Unable to persist IP reservation: %v", err)
+		return err
+	}
+	if !succeeded {
+		i.reloadReservation(db, reservation.IpAddress)
+		return fmt.Errorf("IP address %s has been reserved concurrently", reservation.IpAddress)
+	}
+	i.updateReservation(nil, reservation)
+	return nil
+}
+
+// swapReservation replaces IP reservation in the KV DB, provided that it has not been
+// changed in the meantime.
+// The internal cache is updated immediately, not waiting for the change to be reflected.
+func (i *IPAM) swapReservation(prevValue, newValue *ipalloc.IPReservation) error {
+	db, prevData, err := i.encodeReservation(prevValue)
+	if err != nil {
+		i.Log.Errorf("Unable to update IP reservation: %v", err)
+		return err
+	}
+	_, newData, err := i.encodeReservation(newValue)
+	if err != nil {
+		i.Log.Errorf("Unable to update IP reservation: %v", err)
+		return err
+	}
+	succeeded, err := db.CompareAndSwap(ipalloc.ReservationKey(prevValue.IpAddress), prevData, newData)
+	if err != nil {
+		i.Log.Errorf("Unable to update IP reservation: %v", err)
+		return err
+	}
+	if !succeeded {
+		i.reloadReservation(db, prevValue.IpAddress)
+		return fmt.Errorf("IP reservation %v has been changed concurrently", prevValue)
+	}
+	i.updateReservation(prevValue, newValue)
+	return nil
+}
+
+// deleteReservation removes IP reservation from the KV DB, provided that it has not been
+// changed in the meantime.
+// The internal cache is updated immediately, not waiting for the change to be reflected.
+func (i *IPAM) deleteReservation(reservation *ipalloc.IPReservation) error {
+	db, data, err := i.encodeReservation(reservation)
+	if err != nil {
+		i.Log.Errorf("Unable to delete IP reservation: %v", err)
+		return err
+	}
+	succeeded, err := db.CompareAndDelete(ipalloc.ReservationKey(reservation.IpAddress), data)
+	if err != nil {
+		i.Log.Errorf("Unable to delete IP reservation: %v", err)
+		return err
+	}
+	if !succeeded {
+		i.reloadReservation(db, reservation.IpAddress)
+		return fmt.Errorf("IP reservation %v has been changed concurrently", reservation)
+	}
+	i.updateReservation(reservation, nil)
+	return nil
+}
+
+// encodeReservation returns DB broker with atomic operations and the reservation
+// encoded as stored in the KV DB.
+func (i *IPAM) encodeReservation(reservation *ipalloc.IPReservation) (keyval.BytesBrokerWithAtomic, []byte, error) {
+	db, err := i.getAtomicDBBroker()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := i.serializer.Marshal(reservation)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, data, nil
+}
+
+// reloadReservation updates the internal cache with the IP reservation as currently
+// stored in the KV DB (after a failed atomic operation).
+func (i *IPAM) reloadReservation(db keyval.BytesBrokerWithAtomic, ip string) {
+	data, found, _, err := db.GetValue(ipalloc.ReservationKey(ip))
+	if err != nil {
+		i.Log.Warnf("Unable to read IP reservation of %s: %v", ip, err)
+		return
+	}
+	prevValue := i.reservations[ip]
+	if !found {
+		i.updateReservation(prevValue, nil)
+		return
+	}
+	reservation := &ipalloc.IPReservation{}
+	if err := i.serializer.Unmarshal(data, reservation); err != nil {
+		i.Log.Warnf("Unable to decode IP reservation of %s: %v", ip, err)
+		return
+	}
+	i.updateReservation(prevValue, reservation)
+}
+
+// collectStaleReservations removes static IP reservations (made via the ip-address
+// annotation) of pods which no longer exist in Kubernetes for longer than the grace
+// period. Named reservations are kept - they are meant to outlive the pods (e.g. to be
+// taken over by the next instance of a StatefulSet pod).
+// Returns the number of removed reservations.
+func (i *IPAM) collectStaleReservations(now time.Time) (removed int) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	gracePeriod := i.ContivConf.GetIPAMConfig().IPLeakGracePeriod
+	k8sPods := i.PodManager.GetPods()
+
+	staleReservations := make(map[string]time.Time)
+	for ip, reservation := range i.reservations {
+		if reservation.Name != "" {
+			continue
+		}
+		owner := podmodel.ID{Name: reservation.PodName, Namespace: reservation.PodNamespace}
+		if _, exists := k8sPods[owner]; exists {
+			continue
+		}
+		since, known := i.staleReservations[ip]
+		if !known {
+			since = now
+		}
+		if now.Sub(since) < gracePeriod {
+			staleReservations[ip] = since
+			continue
+		}
+
+		// grace period has expired
+		if err := i.deleteReservation(reservation); err != nil {
+			// already removed or changed by another node, otherwise retried next time
+			i.Log.Warnf("Failed to remove stale IP reservation %v: %v", reservation, err)
+			continue
+		}
+		i.Log.Infof("Removed IP reservation of %s for pod %v (gone since %v)", ip, owner, since)
+		removed++
+	}
+	i.staleReservations = staleReservations
+	return removed
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/americanbinary/vpp/plugins/ipam/restapi"
+)
+
+const (
+	// gauges with the utilization of IP address blocks of this node
+	ipBlockAllocatedMetric         = "ipBlockAllocated"
+	ipBlockCapacityMetric          = "ipBlockCapacity"
+	ipBlockUtilizationMetric       = "ipBlockUtilization"
+	ipBlockThresholdExceededMetric = "ipBlockThresholdExceeded"
+
+	nodeLabel      = "node"
+	networkLabel   = "network"
+	blockTypeLabel = "type"
+	subnetLabel    = "subnet"
+
+	// IP addresses of a pod block not available for pods: network address, gateway,
+	// NAT-loopback (the last unicast IP) and broadcast
+	podBlockReservedIPs = 4
+
+	// IP addresses of the VPP-host block not available for the interconnect: network
+	// address and broadcast
+	vppHostBlockReservedIPs = 2
+
+	// IP addresses of the VPP-host block allocated for the VPP-end and the host-end
+	// of the interconnect
+	vppHostBlockAllocatedIPs = 2
+)
+
+// ipUsageMetrics contains gauges with the utilization of IP address blocks.
+type ipUsageMetrics struct {
+	allocated         *prometheus.GaugeVec
+	capacity          *prometheus.GaugeVec
+	utilization       *prometheus.GaugeVec
+	thresholdExceeded *prometheus.GaugeVec
+}
+
+// registerIPUsageMetrics creates gauges with the utilization of IP address blocks
+// of this node and exports them through the stats collector.
+func (i *IPAM) registerIPUsageMetrics() {
+	if i.Stats == nil {
+		return
+	}
+	constLabels := prometheus.Labels{
+		nodeLabel: i.ServiceLabel.GetAgentLabel(),
+	}
+	labels := []string{networkLabel, blockTypeLabel, subnetLabel}
+	metrics := &ipUsageMetrics{
+		allocated: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        ipBlockAllocatedMetric,
+			Help:        "Number of allocated IP addresses from the IP address block of this node",
+			ConstLabels: constLabels,
+		}, labels),
+		capacity: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        ipBlockCapacityMetric,
+			Help:        "Number of IP addresses available for allocation in the IP address block of this node",
+			ConstLabels: constLabels,
+		}, labels),
+		utilization: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        ipBlockUtilizationMetric,
+			Help:        "Utilization (in percent) of the IP address block of this node",
+			ConstLabels: constLabels,
+		}, labels),
+		thresholdExceeded: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        ipBlockThresholdExceededMetric,
+			Help:        "1 if the utilization of the IP address block of this node exceeds the warning threshold",
+			ConstLabels: constLabels,
+		}, labels),
+	}
+	collectors := []prometheus.Collector{metrics.allocated, metrics.capacity,
+		metrics.utilization, metrics.thresholdExceeded}
+	for _, collector := range collectors {
+		if err := i.Stats.RegisterCollector(collector); err != nil {
+			i.Log.Warnf("Failed to register metrics of the IP address utilization: %v", err)
+			return
+		}
+	}
+	i.usageMetrics = metrics
+}
+
+// getIPUsage returns utilization of all IP address blocks of this node: pod subnets
+// of the default and the custom pod networks and the VPP-host interconnect subnet.
+// The warning threshold is evaluated only for the pod subnets, the utilization
+// of the VPP-host interconnect subnet is constant.
+// The method expects the mutex to be already acquired.
+func (i *IPAM) getIPUsage() (blocks []restapi.IPBlockUsage) {
+	threshold := i.ContivConf.GetIPAMConfig().IPUsageWarningThreshold
+
+	// default pod network first, then custom networks ordered by name
+	var networks []string
+	for network := range i.podNetworks {
+		if network != defaultPodNetworkName {
+			networks = append(networks, network)
+		}
+	}
+	sort.Strings(networks)
+	if _, hasDefault := i.podNetworks[defaultPodNetworkName]; hasDefault {
+		networks = append([]string{defaultPodNetworkName}, networks...)
+	}
+
+	usedIPs := i.usedPodIPs()
+	for _, network := range networks {
+		for _, subnet := range i.podNetworks[network].subnetsThisNode() {
+			if subnet == nil {
+				continue
+			}
+			var allocated uint64
+			for _, ip := range usedIPs {
+				if subnet.Contains(ip) {
+					allocated++
+				}
+			}
+			blocks = append(blocks, newIPBlockUsage(network, restapi.PodBlock, subnet,
+				allocated, blockCapacity(subnet, podBlockReservedIPs), threshold))
+		}
+	}
+
+	if subnet := i.hostInterconnectSubnetThisNode; subnet != nil {
+		blocks = append(blocks, newIPBlockUsage("", restapi.VPPHostBlock, subnet,
+			vppHostBlockAllocatedIPs, blockCapacity(subnet, vppHostBlockReservedIPs), 0))
+	}
+	return blocks
+}
+
+// usedPodIPs returns all pod IP addresses which are allocated or reserved on this node.
+func (i *IPAM) usedPodIPs() (ips []net.IP) {
+	used := make(map[string]net.IP)
+	for ipStr := range i.assignedPodIPs {
+		used[ipStr] = net.ParseIP(ipStr)
+	}
+	for _, allocation := range i.podToIP {
+		// with external IPAM, allocations are recorded only here
+		for _, ip := range allocation.allIPs() {
+			used[ip.String()] = ip
+		}
+	}
+	for ipStr := range i.reservations {
+		used[ipStr] = net.ParseIP(ipStr)
+	}
+	for _, ip := range used {
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+// checkIPUsage updates the IP address utilization metrics and sends IPUsageThresholdExceeded
+// event for every block which has crossed the warning threshold since the last check.
+// The method expects the mutex to be already acquired.
+func (i *IPAM) checkIPUsage() {
+	threshold := i.ContivConf.GetIPAMConfig().IPUsageWarningThreshold
+	blocks := i.getIPUsage()
+
+	if i.usageMetrics != nil {
+		i.usageMetrics.allocated.Reset()
+		i.usageMetrics.capacity.Reset()
+		i.usageMetrics.utilization.Reset()
+		i.usageMetrics.thresholdExceeded.Reset()
+	}
+
+	exceeded := make(map[string]bool)
+	for _, block := range blocks {
+		subnet := block.Subnet
+		if i.usageMetrics != nil {
+			labels := []string{block.Network, block.Type, subnet}
+			i.usageMetrics.allocated.WithLabelValues(labels...).Set(float64(block.Allocated))
+			i.usageMetrics.capacity.WithLabelValues(labels...).Set(float64(block.Capacity))
+			i.usageMetrics.utilization.WithLabelValues(labels...).Set(block.Utilization)
+			var exceededVal float64
+			if block.ThresholdExceeded {
+				exceededVal = 1
+			}
+			i.usageMetrics.thresholdExceeded.WithLabelValues(labels...).Set(exceededVal)
+		}
+
+		if !block.ThresholdExceeded {
+			if i.usageExceeded[subnet] {
+				i.Log.Infof("Utilization of the IP address block %s has dropped below the threshold: %d/%d",
+					subnet, block.Allocated, block.Capacity)
+			}
+			continue
+		}
+		exceeded[subnet] = true
+		if i.usageExceeded[subnet] {
+			continue // already reported
+		}
+		i.Log.Warnf("Utilization of the IP address block %s (network %q) has exceeded %d%%: %d/%d",
+			subnet, block.Network, threshold, block.Allocated, block.Capacity)
+		i.EventLoop.PushEvent(&IPUsageThresholdExceeded{
+			Network:   block.Network,
+			Subnet:    block.Subnet,
+			Allocated: block.Allocated,
+			Capacity:  block.Capacity,
+			Threshold: threshold,
+		})
+	}
+	i.usageExceeded = exceeded
+}
+
+// newIPBlockUsage returns utilization of an IP address block, evaluated against
+// the warning threshold (0 = disabled).
+func newIPBlockUsage(network, blockType string, subnet *net.IPNet, allocated, capacity uint64,
+	threshold uint8) restapi.IPBlockUsage {
+
+	block := restapi.IPBlockUsage{
+		Network:   network,
+		Type:      blockType,
+		Subnet:    subnet.String(),
+		Allocated: allocated,
+		Capacity:  capacity,
+	}
+	if capacity > 0 {
+		block.Utilization = 100 * float64(allocated) / float64(capacity)
+	}
+	block.ThresholdExceeded = threshold > 0 && capacity > 0 && block.Utilization > float64(threshold)
+	return block
+}
+
+// blockCapacity returns the number of IP addresses in the subnet (capped the same
+// way as in allocateIPFromSubnet) minus the given count of reserved IPs.
+func blockCapacity(subnet *net.IPNet, reservedIPs uint64) uint64 {
+	prefixBits, totalBits := subnet.Mask.Size()
+	hostBits := uint(totalBits - prefixBits)
+	if hostBits >= 64 {
+		hostBits = 63
+	}
+	size := uint64(1) << hostBits
+	if size <= reservedIPs {
+		return 0
+	}
+	return size - reservedIPs
+}
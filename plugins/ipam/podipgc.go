@@ -0,0 +1,195 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	"github.com/americanbinary/vpp/plugins/podmanager"
+)
+
+// leakedPodIPs holds IP addresses allocated for a pod which no longer exists.
+type leakedPodIPs struct {
+	since time.Time // when the leak was first detected
+	ips   []net.IP
+}
+
+// StatsCollector is used to export IPAM metrics (implemented by the statscollector
+// plugin, which cannot be imported here directly due to an import cycle).
+type StatsCollector interface {
+	// RegisterGaugeFunc registers a new gauge with specific name, help string and valueFunc to report status when invoked.
+	RegisterGaugeFunc(name string, help string, valueFunc func() float64)
+
+	// RegisterCollector registers a custom collector (e.g. gauge vector) to be exposed
+	// together with the other statistics.
+	RegisterCollector(collector prometheus.Collector) error
+}
+
+// registerGauges exports counts of allocated, leaked and reclaimed pod IP addresses
+// through the stats collector.
+func (i *IPAM) registerGauges() {
+	if i.Stats == nil {
+		return
+	}
+	i.Stats.RegisterGaugeFunc("allocatedPodIPs",
+		"Number of pod IP addresses allocated on this node",
+		func() float64 {
+			allocated, _, _ := i.podIPCounts()
+			return float64(allocated)
+		})
+	i.Stats.RegisterGaugeFunc("leakedPodIPs",
+		"Number of pod IP addresses allocated for pods that no longer exist (within the grace period)",
+		func() float64 {
+			_, leaked, _ := i.podIPCounts()
+			return float64(leaked)
+		})
+	i.Stats.RegisterGaugeFunc("reclaimedPodIPs",
+		"Total count of leaked pod IP addresses released by the garbage collection",
+		func() float64 {
+			_, _, reclaimed := i.podIPCounts()
+			return float64(reclaimed)
+		})
+}
+
+// podIPCounts returns counts of allocated, leaked and reclaimed pod IP addresses.
+func (i *IPAM) podIPCounts() (allocated, leaked, reclaimed int) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	for _, leak := range i.leakedIPs {
+		leaked += len(leak.ips)
+	}
+	return len(i.assignedPodIPs), leaked, i.reclaimedIPCount
+}
+
+// periodicPodIPGC runs in a separate go routine and periodically triggers
+// the garbage collection of leaked pod IP addresses.
+// Pods with a running sandbox are listed here, outside of the event loop, and passed
+// to the event handler with the event.
+func (i *IPAM) periodicPodIPGC(interval time.Duration) {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			runningPods, err := i.PodManager.ListRunningPods()
+			if err != nil {
+				i.Log.Warnf("Failed to list running pods for pod IP garbage collection: %v", err)
+				continue
+			}
+			if err := i.EventLoop.PushEvent(&PodIPGarbageCollection{RunningPods: runningPods}); err != nil {
+				i.Log.Warnf("Failed to trigger pod IP garbage collection: %v", err)
+			}
+		case <-i.stopGC:
+			return
+		}
+	}
+}
+
+// detectLeakedPods cross-checks pod IP allocations and the locally known pods against
+// the pods with a running sandbox on this node and returns pods which have been gone
+// for longer than the grace period - these should be removed using DeletePod event,
+// so that their connectivity is un-configured before the IP addresses are released
+// (see releaseLeakedPodIPs). Expired pods are returned with the ID of the sandbox
+// container as known locally (if at all), so that the removal can be skipped if the pod
+// gets re-added meanwhile.
+func (i *IPAM) detectLeakedPods(now time.Time, runningPods podmanager.LocalPods) (expired []*podmanager.LocalPod) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	gracePeriod := i.ContivConf.GetIPAMConfig().IPLeakGracePeriod
+	localPods := i.PodManager.GetLocalPods()
+	leakedIPs := make(map[podmodel.ID]*leakedPodIPs)
+	checkPod := func(podID podmodel.ID, allocation *podIPInfo) {
+		if _, isRunning := runningPods[podID]; isRunning {
+			return
+		}
+		leak, known := i.leakedIPs[podID]
+		if !known {
+			leak = &leakedPodIPs{since: now}
+		}
+		leak.ips = nil
+		if allocation != nil {
+			leak.ips = allocation.allIPs()
+		}
+		leakedIPs[podID] = leak
+		if now.Sub(leak.since) >= gracePeriod {
+			// grace period has expired
+			pod := &podmanager.LocalPod{ID: podID}
+			if localPod, isLocal := localPods[podID]; isLocal {
+				pod.ContainerID = localPod.ContainerID
+			}
+			expired = append(expired, pod)
+		}
+	}
+	for podID, allocation := range i.podToIP {
+		checkPod(podID, allocation)
+	}
+	for podID := range localPods {
+		if _, hasAllocation := i.podToIP[podID]; !hasAllocation {
+			// CNI DEL was missed for a pod with IP already released
+			checkPod(podID, nil)
+		}
+	}
+	i.leakedIPs = leakedIPs
+	return expired
+}
+
+// releaseLeakedPodIPs releases IP addresses of a leaked pod, called once the pod
+// has been removed by DeletePod event.
+// Returns the released IP addresses.
+func (i *IPAM) releaseLeakedPodIPs(podID podmodel.ID) (reclaimed []net.IP) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	leak, isLeaked := i.leakedIPs[podID]
+	if !isLeaked {
+		return nil
+	}
+	delete(i.leakedIPs, podID)
+	if _, hasAllocation := i.podToIP[podID]; !hasAllocation {
+		return nil
+	}
+	reclaimed = i.podToIP[podID].allIPs()
+	if err := i.releasePodIPs(podID); err != nil {
+		i.Log.Warnf("Failed to release leaked IP addresses of pod %v: %v", podID, err)
+	}
+	for _, ip := range reclaimed {
+		i.Log.Infof("Reclaimed leaked IP %v of pod %v (leaked since %v)", ip, podID, leak.since)
+	}
+	i.reclaimedIPCount += len(reclaimed)
+	i.checkIPUsage()
+	return reclaimed
+}
+
+// allIPs returns all IP addresses from the allocation.
+func (i *podIPInfo) allIPs() (ips []net.IP) {
+	if i.mainIP != nil {
+		ips = append(ips, i.mainIP)
+	}
+	if i.secondaryIP != nil {
+		ips = append(ips, i.secondaryIP)
+	}
+	for _, ip := range i.customIfIPs {
+		ips = append(ips, ip)
+	}
+	return ips
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/americanbinary/vpp/plugins/contivconf"
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
+	"github.com/americanbinary/vpp/plugins/idalloc/idallocation"
+	"github.com/americanbinary/vpp/plugins/nodesync"
+)
+
+// podSubnetPoolName is the name of the ID allocation pool used to lease per-node
+// pod subnets if dynamic pod subnet allocation is enabled.
+// Allocated ID is the index of the pod subnet (as used by dissectSubnetForNode),
+// the allocation label identifies the node which holds the lease (see podSubnetLeaseLabel).
+const podSubnetPoolName = "pod-subnet"
+
+// podSubnetLeaseLabel returns the label under which the pod subnet lease of the given
+// node is stored in the allocation pool. The label combines node ID with node name,
+// because node IDs are reused by nodes joining the cluster later.
+func podSubnetLeaseLabel(nodeID uint32, nodeName string) string {
+	return strconv.FormatUint(uint64(nodeID), 10) + "/" + nodeName
+}
+
+// parsePodSubnetLeaseLabel parses node ID and node name from the label of a pod subnet lease.
+func parsePodSubnetLeaseLabel(label string) (nodeID uint32, nodeName string, err error) {
+	idStr := label
+	if sep := strings.Index(label, "/"); sep >= 0 {
+		idStr, nodeName = label[:sep], label[sep+1:]
+	}
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return 0, "", err
+	}
+	return uint32(id), nodeName, nil
+}
+
+// leasesFromPool builds the pod subnet lease table (node ID -> subnet index) from
+// the allocation pool data. Allocations with malformed labels are ignored.
+// If there are multiple leases for the same node ID (i.e. the lease of a node which
+// has left the cluster is not released yet), the lease of the node currently using
+// the ID (according to <nodes>) is preferred.
+func leasesFromPool(pool *idallocation.AllocationPool, nodes nodesync.Nodes) map[uint32]uint32 {
+	leases := make(map[uint32]uint32)
+	if pool == nil {
+		return leases
+	}
+	for label, alloc := range pool.IdAllocations {
+		nodeID, nodeName, err := parsePodSubnetLeaseLabel(label)
+		if err != nil || alloc == nil {
+			continue
+		}
+		if _, duplicate := leases[nodeID]; duplicate {
+			if node, exists := nodes[nodeName]; !exists || node.ID != nodeID {
+				continue
+			}
+		}
+		leases[nodeID] = alloc.Id
+	}
+	return leases
+}
+
+// leasesFromKubeState builds the pod subnet lease table from the Kubernetes state data.
+func leasesFromKubeState(kubeStateData controller.KubeStateData, nodes nodesync.Nodes) map[uint32]uint32 {
+	for _, poolProto := range kubeStateData[idallocation.Keyword] {
+		pool := poolProto.(*idallocation.AllocationPool)
+		if pool.Name == podSubnetPoolName {
+			return leasesFromPool(pool, nodes)
+		}
+	}
+	return leasesFromPool(nil, nodes)
+}
+
+// leasePodSubnet returns index of the pod subnet leased for this node. The lease
+// is allocated from the pool shared by all nodes if it does not exist yet.
+func (i *IPAM) leasePodSubnet(config *contivconf.CustomIPAMSubnets, nodeID uint32) (index uint32, err error) {
+	subnetPrefixLen, _ := config.PodSubnetCIDR.Mask.Size()
+	newBits := int(config.PodSubnetOneNodePrefixLen) - subnetPrefixLen
+	if newBits <= 0 {
+		return 0, fmt.Errorf("prefix length for one node (%v) must be higher "+
+			"than the cluster-wide subnet prefix length (%v)",
+			config.PodSubnetOneNodePrefixLen, subnetPrefixLen)
+	}
+
+	// the zero-ending subnet is not leased (same as with node IDs, it would require
+	// index 2^newBits - see dissectSubnetForNode)
+	maxIndex := uint32(math.MaxUint32)
+	if newBits < 32 {
+		maxIndex = 1<<uint(newBits) - 1
+	}
+	err = i.IDAlloc.InitPool(podSubnetPoolName, &idallocation.AllocationPool_Range{
+		MinId: 1,
+		MaxId: maxIndex,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize pod subnet pool: %v", err)
+	}
+	index, err = i.IDAlloc.GetOrAllocateID(podSubnetPoolName, podSubnetLeaseLabel(nodeID, i.ServiceLabel.GetAgentLabel()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to lease pod subnet: %v", err)
+	}
+	i.Log.Infof("Leased pod subnet with index %d", index)
+	return index, nil
+}
+
+// releasePodSubnet releases the pod subnet leased by a node which has left the cluster.
+// To avoid concurrent releases, the lease is released only by the remaining node
+// with the lowest node ID. The lease table is updated once the change is reflected
+// back from the KV DB.
+func (i *IPAM) releasePodSubnet(node *nodesync.Node) error {
+	thisNodeID := i.NodeSync.GetNodeID()
+	for _, otherNode := range i.NodeSync.GetAllNodes() {
+		if otherNode.ID != node.ID && otherNode.ID < thisNodeID {
+			// released by the other node
+			return nil
+		}
+	}
+	err := i.IDAlloc.ForceReleaseID(podSubnetPoolName, podSubnetLeaseLabel(node.ID, node.Name))
+	if err != nil {
+		return fmt.Errorf("failed to release pod subnet of node %s (ID=%d): %v", node.Name, node.ID, err)
+	}
+	i.Log.Infof("Released pod subnet of node %s (ID=%d)", node.Name, node.ID)
+	return nil
+}
+
+// updatePodSubnetLeases updates the lease table and notifies other plugins about
+// changed pod subnets of other nodes.
+// Returns error if the lease of this node, once reflected, has been lost (released
+// or changed in the KV DB) - the pod subnet of this node could be then leased by another node.
+func (i *IPAM) updatePodSubnetLeases(pool *idallocation.AllocationPool) error {
+	thisNodeID := i.NodeSync.GetNodeID()
+	thisNodeLabel := podSubnetLeaseLabel(thisNodeID, i.ServiceLabel.GetAgentLabel())
+	var thisNodeLease *idallocation.AllocationPool_Allocation
+	if pool != nil {
+		thisNodeLease = pool.IdAllocations[thisNodeLabel]
+	}
+	if thisNodeLease != nil && thisNodeLease.Id == i.podSubnetIndex {
+		i.podSubnetLeaseReflected = true
+	} else if i.podSubnetLeaseReflected {
+		return fmt.Errorf("pod subnet lease of this node (index %d) has been lost in the KV DB", i.podSubnetIndex)
+	}
+
+	prevLeases := i.podSubnetLeases
+	newLeases := leasesFromPool(pool, i.NodeSync.GetAllNodes())
+	newLeases[thisNodeID] = i.podSubnetIndex
+	i.podSubnetLeases = newLeases
+
+	var nodeIDs []uint32
+	for nodeID := range prevLeases {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	for nodeID := range newLeases {
+		if _, hadLease := prevLeases[nodeID]; !hadLease {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	sort.Slice(nodeIDs, func(a, b int) bool { return nodeIDs[a] < nodeIDs[b] })
+
+	for _, nodeID := range nodeIDs {
+		prevIndex, hadLease := prevLeases[nodeID]
+		newIndex, hasLease := newLeases[nodeID]
+		if hadLease == hasLease && prevIndex == newIndex {
+			continue
+		}
+		ev := &PodSubnetLeaseChange{NodeID: nodeID}
+		podNw := i.podNetworks[defaultPodNetworkName]
+		var err error
+		if hadLease {
+			ev.PrevSubnets, err = podNw.subnetsForIndex(prevIndex)
+		}
+		if hasLease && err == nil {
+			ev.NewSubnets, err = podNw.subnetsForIndex(newIndex)
+		}
+		if err != nil {
+			i.Log.Warnf("Failed to compute pod subnets of node ID=%d: %v", nodeID, err)
+			continue
+		}
+		i.EventLoop.PushEvent(ev)
+		i.Log.Infof("Sent PodSubnetLeaseChange event to the event loop for node ID=%d", nodeID)
+	}
+	return nil
+}
+
+// podSubnetIndexOfNode returns index of the pod subnet of the given node in the given
+// pod network. Unless the pod subnets are leased dynamically (only for the default
+// pod network), the index equals the node ID.
+// Returns false if the node has not leased any pod subnet yet.
+func (i *IPAM) podSubnetIndexOfNode(podNw *podNetworkInfo, nodeID uint32) (index uint32, leased bool) {
+	if !i.ContivConf.GetIPAMConfig().DynamicPodSubnetAllocation || podNw != i.podNetworks[defaultPodNetworkName] {
+		return nodeID, true
+	}
+	if nodeID == i.NodeSync.GetNodeID() {
+		return i.podSubnetIndex, true
+	}
+	index, leased = i.podSubnetLeases[nodeID]
+	return index, leased
+}
+
+// nodeIDFromPodSubnetIndex returns ID of the node which holds the pod subnet with
+// the given index (in the default pod network).
+func (i *IPAM) nodeIDFromPodSubnetIndex(index uint32) (nodeID uint32, err error) {
+	if !i.ContivConf.GetIPAMConfig().DynamicPodSubnetAllocation {
+		return index, nil
+	}
+	if index == i.podSubnetIndex {
+		return i.NodeSync.GetNodeID(), nil
+	}
+	for nodeID, leasedIndex := range i.podSubnetLeases {
+		if leasedIndex == index {
+			return nodeID, nil
+		}
+	}
+	return 0, fmt.Errorf("pod subnet with index %d is not leased by any node", index)
+}
@@ -15,9 +15,13 @@
 package ipam
 
 import (
+	"bytes"
+	"net/http"
+	"sort"
+
 	"github.com/americanbinary/vpp/plugins/ipam/restapi"
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"github.com/unrolled/render"
-	"net/http"
 )
 
 func (i *IPAM) registerRESTHandlers() {
@@ -28,6 +32,15 @@ func (i *IPAM) registerRESTHandlers() {
 
 	i.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLNodeIPAllocations, i.ipamGetHandler, "GET")
 	i.Log.Infof("IP Allocation REST handler registered: GET %v", restapi.RestURLNodeIPAllocations)
+
+	i.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLIPReservations, i.reservationsGetHandler, "GET")
+	i.Log.Infof("IP Reservation REST handler registered: GET %v", restapi.RestURLIPReservations)
+
+	i.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLIPLeaks, i.leaksGetHandler, "GET")
+	i.Log.Infof("IP Leaks REST handler registered: GET %v", restapi.RestURLIPLeaks)
+
+	i.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLIPUsage, i.usageGetHandler, "GET")
+	i.Log.Infof("IP Usage REST handler registered: GET %v", restapi.RestURLIPUsage)
 }
 
 func (i *IPAM) ipamGetHandler(formatter *render.Render) http.HandlerFunc {
@@ -42,6 +55,7 @@ func (i *IPAM) ipamGetHandler(formatter *render.Render) http.HandlerFunc {
 			allocations.Pods = append(allocations.Pods, restapi.PodIPAllocation{
 				PodID:       k,
 				MainIP:      v.mainIP,
+				SecondaryIP: v.secondaryIP,
 				CustomIfIPs: v.customIfIPs,
 			})
 		}
@@ -49,3 +63,67 @@ func (i *IPAM) ipamGetHandler(formatter *render.Render) http.HandlerFunc {
 		formatter.JSON(w, http.StatusOK, allocations)
 	}
 }
+
+func (i *IPAM) reservationsGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		i.mutex.RLock()
+		defer i.mutex.RUnlock()
+
+		i.Log.Debug("Getting IP reservations")
+
+		reservations := []restapi.IPReservation{}
+		for _, v := range i.reservations {
+			reservations = append(reservations, restapi.IPReservation{
+				IP:    parseIP(v.IpAddress),
+				Name:  v.Name,
+				PodID: podmodel.ID{Name: v.PodName, Namespace: v.PodNamespace},
+			})
+		}
+		sort.Slice(reservations, func(a, b int) bool {
+			return bytes.Compare(reservations[a].IP, reservations[b].IP) < 0
+		})
+
+		formatter.JSON(w, http.StatusOK, reservations)
+	}
+}
+
+func (i *IPAM) leaksGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		i.Log.Debug("Getting IP leaks")
+
+		leaks := restapi.IPLeaks{}
+		leaks.AllocatedIPs, leaks.LeakedIPs, leaks.ReclaimedIPs = i.podIPCounts()
+
+		i.mutex.RLock()
+		defer i.mutex.RUnlock()
+		for podID, leak := range i.leakedIPs {
+			leaks.Leaks = append(leaks.Leaks, restapi.PodIPLeak{
+				PodID: podID,
+				IPs:   leak.ips,
+				Since: leak.since,
+			})
+		}
+		sort.Slice(leaks.Leaks, func(a, b int) bool {
+			return leaks.Leaks[a].Since.Before(leaks.Leaks[b].Since)
+		})
+
+		formatter.JSON(w, http.StatusOK, leaks)
+	}
+}
+
+func (i *IPAM) usageGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		i.mutex.RLock()
+		defer i.mutex.RUnlock()
+
+		i.Log.Debug("Getting IP usage")
+
+		usage := restapi.NodeIPUsage{
+			NodeName:  i.ServiceLabel.GetAgentLabel(),
+			NodeID:    i.NodeSync.GetNodeID(),
+			Threshold: i.ContivConf.GetIPAMConfig().IPUsageWarningThreshold,
+			Blocks:    i.getIPUsage(),
+		}
+		formatter.JSON(w, http.StatusOK, usage)
+	}
+}
@@ -15,8 +15,10 @@
 package restapi
 
 import (
-	"github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"net"
+	"time"
+
+	"github.com/americanbinary/vpp/plugins/ksr/model/pod"
 )
 
 const (
@@ -25,12 +27,30 @@ const (
 
 	// RestURLNodeIPAllocations is versioned URL for the node IPAM allocations REST endpoint.
 	RestURLNodeIPAllocations = RESTPrefix + "ipam/allocations"
+
+	// RestURLIPReservations is versioned URL for the pod IP reservations REST endpoint.
+	RestURLIPReservations = RESTPrefix + "ipam/reservations"
+
+	// RestURLIPLeaks is versioned URL for the pod IP leak detection REST endpoint.
+	RestURLIPLeaks = RESTPrefix + "ipam/leaks"
+
+	// RestURLIPUsage is versioned URL for the IP address utilization REST endpoint.
+	RestURLIPUsage = RESTPrefix + "ipam/usage"
+)
+
+const (
+	// PodBlock is type of IP address block from which pod IPs are allocated.
+	PodBlock = "pod"
+
+	// VPPHostBlock is type of IP address block used for the VPP to host interconnect.
+	VPPHostBlock = "vpp-host"
 )
 
 // PodIPAllocation represents IP allocation info about a pod.
 type PodIPAllocation struct {
 	PodID       pod.ID
 	MainIP      net.IP
+	SecondaryIP net.IP // dual-stack only
 	CustomIfIPs map[string]net.IP
 }
 
@@ -38,3 +58,45 @@ type PodIPAllocation struct {
 type NodeIPAllocations struct {
 	Pods []PodIPAllocation
 }
+
+// IPReservation represents pod IP address reserved via pod annotations.
+type IPReservation struct {
+	IP    net.IP
+	Name  string // empty for static IP reserved for a single pod
+	PodID pod.ID // pod holding the reservation
+}
+
+// IPLeaks represents counts of allocated, leaked and reclaimed pod IP addresses
+// on the current node, together with the list of currently leaked IPs.
+type IPLeaks struct {
+	AllocatedIPs int
+	LeakedIPs    int
+	ReclaimedIPs int
+	Leaks        []PodIPLeak
+}
+
+// PodIPLeak represents IP addresses allocated for a pod which no longer exists.
+type PodIPLeak struct {
+	PodID pod.ID
+	IPs   []net.IP
+	Since time.Time // when the leak was first detected
+}
+
+// NodeIPUsage represents utilization of IP address blocks of the current node.
+type NodeIPUsage struct {
+	NodeName  string
+	NodeID    uint32
+	Threshold uint8 // warning threshold in percent, 0 if disabled
+	Blocks    []IPBlockUsage
+}
+
+// IPBlockUsage represents utilization of a single IP address block of the current node.
+type IPBlockUsage struct {
+	Network           string // pod network name, empty for the VPP-host interconnect
+	Type              string // PodBlock or VPPHostBlock
+	Subnet            string
+	Allocated         uint64
+	Capacity          uint64  // number of IP addresses available for allocation
+	Utilization       float64 // in percent
+	ThresholdExceeded bool
+}
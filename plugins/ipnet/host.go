@@ -291,20 +291,29 @@ func (n *IPNet) routesToHost(nextHopIP net.IP) map[string]*vpp_l3.Route {
 	return routes
 }
 
-// routePODsFromHost returns configuration for route for the host stack to direct
-// traffic destined to pods via VPP.
-func (n *IPNet) routePODsFromHost(nextHopIP net.IP) (key string, config *linux_l3.Route) {
-	route := &linux_l3.Route{
-		OutgoingInterface: hostInterconnectVETH1LogicalName,
-		Scope:             linux_l3.Route_GLOBAL,
-		DstNetwork:        n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName).String(),
-		GwAddr:            nextHopIP.String(),
-	}
-	if n.ContivConf.GetInterfaceConfig().UseTAPInterfaces {
-		route.OutgoingInterface = HostInterconnectTAPinLinuxLogicalName
+// routesPODsFromHost returns configuration for routes for the host stack to direct
+// traffic destined to pods (from all pod subnets) via VPP.
+func (n *IPNet) routesPODsFromHost(nextHopIP net.IP) map[string]*linux_l3.Route {
+	routes := make(map[string]*linux_l3.Route)
+	for _, podSubnet := range n.IPAM.PodSubnetsAllNodes(DefaultPodNetworkName) {
+		if isIPv6(podSubnet.IP) != isIPv6(nextHopIP) {
+			// the host interconnect is single-stack, the secondary pod subnet
+			// (dual-stack) is not reachable from the host
+			continue
+		}
+		route := &linux_l3.Route{
+			OutgoingInterface: hostInterconnectVETH1LogicalName,
+			Scope:             linux_l3.Route_GLOBAL,
+			DstNetwork:        podSubnet.String(),
+			GwAddr:            nextHopIP.String(),
+		}
+		if n.ContivConf.GetInterfaceConfig().UseTAPInterfaces {
+			route.OutgoingInterface = HostInterconnectTAPinLinuxLogicalName
+		}
+		key := models.Key(route)
+		routes[key] = route
 	}
-	key = models.Key(route)
-	return key, route
+	return routes
 }
 
 // routeServicesFromHost returns configuration for route for the host stack to direct
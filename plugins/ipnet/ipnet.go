@@ -107,6 +107,10 @@ type internalState struct {
 	// custom network information
 	customNetworks map[string]*customNetworkInfo // custom network name to info map
 
+	// state preceding the last processed KubeStateChange of a pod
+	// (used to revert the change after the dry-run)
+	podChangeSnapshot *podChangeSnapshot
+
 	// configuration written to etcd for other ligato-based microservices to apply
 	microserviceConfig map[string][]byte
 
@@ -143,6 +147,15 @@ type Deps struct {
 	GoVPP         GoVPP
 	HTTPHandlers  rest.HTTPHandlers
 	RemoteDB      nodesync.KVDBWithAtomic
+
+	// Dependencies to be injected for unit testing or offline replay of events
+	// to replace any access to the host and pod network namespaces with mocks.
+	*UnitTestDeps
+}
+
+// UnitTestDeps lists dependencies for unit testing.
+type UnitTestDeps struct {
+	HostLinkIPsDump HostLinkIPsDumpClb // nil = no host IPs
 }
 
 // GoVPP is the interface of govppmux plugin replicated here to avoid direct
@@ -186,6 +199,15 @@ func (n *IPNet) Init() error {
 
 	// setup callback used to access host interfaces (can be replaced in UTs with a mock)
 	n.hostLinkIPsDump = n.getHostLinkIPs
+	if n.UnitTestDeps != nil {
+		n.test = true
+		n.hostLinkIPsDump = n.UnitTestDeps.HostLinkIPsDump
+		if n.hostLinkIPsDump == nil {
+			n.hostLinkIPsDump = func() ([]net.IP, error) {
+				return nil, nil
+			}
+		}
+	}
 
 	// register REST handlers
 	n.registerRESTHandlers()
@@ -287,6 +309,8 @@ func (n *IPNet) Close() error {
 //   - custom network update
 //   - external interfaces update
 //   - NodeUpdate for other nodes
+//   - PodSubnetLeaseChange (other nodes)
+//   - PodSubnetActivated
 //   - Shutdown event
 func (n *IPNet) HandlesEvent(event controller.Event) bool {
 	if event.Method() != controller.Update {
@@ -317,6 +341,12 @@ func (n *IPNet) HandlesEvent(event controller.Event) bool {
 	if nodeUpdate, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
 		return nodeUpdate.NodeName != n.ServiceLabel.GetAgentLabel()
 	}
+	if _, isLeaseChange := event.(*ipam.PodSubnetLeaseChange); isLeaseChange {
+		return true
+	}
+	if _, isSubnetActivated := event.(*ipam.PodSubnetActivated); isSubnetActivated {
+		return true
+	}
 	if _, isShutdown := event.(*controller.Shutdown); isShutdown {
 		return true
 	}
@@ -119,6 +119,16 @@ func (ev *NodeIPv4Change) Done(error) {
 	return
 }
 
+// IsDuplicateOf returns true if the given event is NodeIPv4Change with the same
+// content.
+func (ev *NodeIPv4Change) IsDuplicateOf(event controller.Event) bool {
+	other, isNodeIPv4Change := event.(*NodeIPv4Change)
+	return isNodeIPv4Change &&
+		ev.NodeIP.Equal(other.NodeIP) &&
+		ev.DefaultGw.Equal(other.DefaultGw) &&
+		ev.NodeIPNet.String() == other.NodeIPNet.String()
+}
+
 /*************************** Pod Custom Interface Update Event ***************************/
 
 // PodCustomIfUpdate is triggered when pod custom interfaces configuration needs to be updated.
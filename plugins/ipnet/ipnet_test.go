@@ -284,6 +284,7 @@ func TestBasicStuff(t *testing.T) {
 			},
 			NodeSync:   fixture.NodeSync,
 			ContivConf: contivConf,
+			PodManager: fixture.PodManager,
 		},
 	}
 	Expect(ipam.Init()).To(BeNil())
@@ -672,6 +673,7 @@ func newTunnelTestingFixture(testName string, ipVer uint8, endFunction int) (*Tu
 			},
 			NodeSync:   fixture.NodeSync,
 			ContivConf: data.ContivConf,
+			PodManager: fixture.PodManager,
 		},
 	}
 	Expect(data.Ipam.Init()).ShouldNot(HaveOccurred())
@@ -214,9 +214,7 @@ func (n *IPNet) otherNodeNextHopIP(node *nodesync.Node) (nextHop net.IP, err err
 		fallthrough // use NoOverlayTransport for other variables
 	case contivconf.NoOverlayTransport:
 		// route traffic destined to the other node directly
-		if len(node.VppIPAddresses) > 0 {
-			nextHop = node.VppIPAddresses[0].Address
-		} else {
+		if nextHop = otherNodeVppIP(node, n.ContivConf.GetIPAMConfig().UseIPv6); nextHop == nil {
 			nextHop, err = n.otherNodeIPFromID(node.ID)
 			if err != nil {
 				n.Log.Error(err)
@@ -570,16 +568,18 @@ func (n *IPNet) routesMainToPodVRF() map[string]*vpp_l3.Route {
 	routingCfg := n.ContivConf.GetRoutingConfig()
 
 	if n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
-		// pod subnet (all nodes) routed from Main VRF via Pod VRF (to go via VXLANs)
-		r1 := &vpp_l3.Route{
-			Type:        vpp_l3.Route_INTER_VRF,
-			DstNetwork:  n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName).String(),
-			VrfId:       routingCfg.MainVRFID,
-			ViaVrfId:    routingCfg.PodVRFID,
-			NextHopAddr: anyAddrForAF(n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName).IP),
+		// pod subnets (all nodes) routed from Main VRF via Pod VRF (to go via VXLANs)
+		for _, podSubnet := range n.IPAM.PodSubnetsAllNodes(DefaultPodNetworkName) {
+			r1 := &vpp_l3.Route{
+				Type:        vpp_l3.Route_INTER_VRF,
+				DstNetwork:  podSubnet.String(),
+				VrfId:       routingCfg.MainVRFID,
+				ViaVrfId:    routingCfg.PodVRFID,
+				NextHopAddr: anyAddrForAF(podSubnet.IP),
+			}
+			r1Key := models.Key(r1)
+			routes[r1Key] = r1
 		}
-		r1Key := models.Key(r1)
-		routes[r1Key] = r1
 
 		// host network (all nodes) routed from Main VRF via Pod VRF (to go via VXLANs)
 		r2 := &vpp_l3.Route{
@@ -592,16 +592,12 @@ func (n *IPNet) routesMainToPodVRF() map[string]*vpp_l3.Route {
 		r2Key := models.Key(r2)
 		routes[r2Key] = r2
 	} else {
-		// pod subnet (this node only) routed from Main VRF to Pod VRF
-		r1 := &vpp_l3.Route{
-			Type:        vpp_l3.Route_INTER_VRF,
-			DstNetwork:  n.IPAM.PodSubnetThisNode(DefaultPodNetworkName).String(),
-			VrfId:       routingCfg.MainVRFID,
-			ViaVrfId:    routingCfg.PodVRFID,
-			NextHopAddr: anyAddrForAF(n.IPAM.PodSubnetThisNode(DefaultPodNetworkName).IP),
+		// pod subnets (this node only) routed from Main VRF to Pod VRF
+		// (additional pod subnets are added once activated, see PodSubnetActivated)
+		for _, podSubnet := range n.IPAM.PodSubnetsThisNode(DefaultPodNetworkName) {
+			r1Key, r1 := n.routeMainToPodVRF(podSubnet)
+			routes[r1Key] = r1
 		}
-		r1Key := models.Key(r1)
-		routes[r1Key] = r1
 	}
 
 	if n.ContivConf.GetIPAMConfig().UseIPv6 {
@@ -620,6 +616,19 @@ func (n *IPNet) routesMainToPodVRF() map[string]*vpp_l3.Route {
 	return routes
 }
 
+// routeMainToPodVRF returns route from Main VRF to default Pod VRF for the given pod subnet of this node.
+func (n *IPNet) routeMainToPodVRF(podSubnet *net.IPNet) (key string, config *vpp_l3.Route) {
+	routingCfg := n.ContivConf.GetRoutingConfig()
+	route := &vpp_l3.Route{
+		Type:        vpp_l3.Route_INTER_VRF,
+		DstNetwork:  podSubnet.String(),
+		VrfId:       routingCfg.MainVRFID,
+		ViaVrfId:    routingCfg.PodVRFID,
+		NextHopAddr: anyAddrForAF(podSubnet.IP),
+	}
+	return models.Key(route), route
+}
+
 // dropRoutesIntoPodVRF returns drop routes for default Pod VRF.
 func (n *IPNet) dropRoutesIntoPodVRF() map[string]*vpp_l3.Route {
 	routes := make(map[string]*vpp_l3.Route)
@@ -634,9 +643,11 @@ func (n *IPNet) dropRoutesIntoPodVRF() map[string]*vpp_l3.Route {
 
 	if n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
 		// drop packets destined to pods no longer deployed
-		r1 := n.dropRoute(routingCfg.PodVRFID, n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName))
-		r1Key := models.Key(r1)
-		routes[r1Key] = r1
+		for _, podSubnet := range n.IPAM.PodSubnetsAllNodes(DefaultPodNetworkName) {
+			r1 := n.dropRoute(routingCfg.PodVRFID, podSubnet)
+			r1Key := models.Key(r1)
+			routes[r1Key] = r1
+		}
 
 		// drop packets destined to nodes no longer deployed
 		r2 := n.dropRoute(routingCfg.PodVRFID, n.IPAM.HostInterconnectSubnetAllNodes())
@@ -668,6 +679,12 @@ func (n *IPNet) podGwLoopback(network string, vrf uint32) (key string, config *v
 			n.IPAM.PodGatewayIP(network), n.IPAM.PodSubnetThisNode(network)))},
 		Vrf: vrf,
 	}
+	// with dual-stack, the loopback is the gateway for the secondary pod subnet as well
+	if gwIPs := n.IPAM.PodGatewayIPs(network); len(gwIPs) > 1 {
+		podSubnets := n.IPAM.PodSubnetsThisNode(network)
+		secondarySubnet := podSubnets[len(podSubnets)-1]
+		lo.IpAddresses = append(lo.IpAddresses, ipNetToString(combineAddrWithNet(gwIPs[1], secondarySubnet)))
+	}
 	key = vpp_interfaces.InterfaceKey(lo.Name)
 	return key, lo
 }
@@ -1052,8 +1069,8 @@ func (n *IPNet) vxlanFibEntry(network string, otherNodeID uint32) (key string, c
 
 // otherNodeIP returns IP address of the given other node
 func (n *IPNet) otherNodeIP(node *nodesync.Node) (net.IP, error) {
-	if len(node.VppIPAddresses) > 0 {
-		return node.VppIPAddresses[0].Address, nil
+	if nodeIP := otherNodeVppIP(node, n.ContivConf.GetIPAMConfig().UseIPv6); nodeIP != nil {
+		return nodeIP, nil
 	}
 	nodeIP, err := n.otherNodeIPFromID(node.ID)
 	if err != nil {
@@ -1063,6 +1080,19 @@ func (n *IPNet) otherNodeIP(node *nodesync.Node) (net.IP, error) {
 	return nodeIP, nil
 }
 
+// otherNodeVppIP returns the first VPP IP address of the given other node from
+// the selected IP family, nil if the node has not published any.
+// With dual-stack, nodes publish IP addresses of both families and the order is not
+// guaranteed.
+func otherNodeVppIP(node *nodesync.Node, ipv6 bool) net.IP {
+	for _, vppIP := range node.VppIPAddresses {
+		if isIPv6(vppIP.Address) == ipv6 {
+			return vppIP.Address
+		}
+	}
+	return nil
+}
+
 // otherNodeIPFromID calculates the (statically selected) IP address of the given other node
 func (n *IPNet) otherNodeIPFromID(otherNodeID uint32) (net.IP, error) {
 	nodeIP, _, err := n.IPAM.NodeIPAddress(otherNodeID)
@@ -1120,7 +1150,7 @@ func (n *IPNet) srv6NodeToNodeDX6PodTunnelIngress(pod *podmodel.Pod) (config con
 		return config, errors.Wrapf(err, "srv6 node-to-node tunnel (using DX6 connecting to pod with ID %v) can't be created "+
 			"due to error from computing steering network for pod IP address %v", podmodel.GetID(pod), podIP)
 	}
-	if !n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName).Contains(podIP) {
+	if !subnetsContain(n.IPAM.PodSubnetsAllNodes(DefaultPodNetworkName), podIP) {
 		n.Log.Warnf("excluding pod %v from creating srv6 DX6 node-to-node tunnel for it because its IP address(%v) seems not to be from Pod "+
 			"subnet. It is probably system pod with other IP address range ", podmodel.GetID(pod), podIP)
 		return make(controller.KeyValuePairs, 0), nil
@@ -1143,11 +1173,29 @@ func (n *IPNet) srv6NodeToNodeDX6PodTunnelIngress(pod *podmodel.Pod) (config con
 }
 
 // srv6NodeToNodePodTunnelIngress creates start node configuration for srv6 tunnel between nodes leading to pod VRF table
-// lookup on the other side(SRv6 path steers and encapsulates traffic on start node side and decapsulates on end node side)
-func (n *IPNet) srv6NodeToNodePodTunnelIngress(otherNodeID uint32, otherNodeIP net.IP, nextHopIP net.IP, podNetwork *net.IPNet) (config controller.KeyValuePairs, err error) {
+// lookup on the other side(SRv6 path steers and encapsulates traffic on start node side and decapsulates on end node side).
+// Traffic for all given pod networks of the other node is steered into the same tunnel, pod networks of the other IP family
+// than the first one (dual-stack) are skipped, because the tunnel end (DT6) decapsulates only IPv6 traffic.
+func (n *IPNet) srv6NodeToNodePodTunnelIngress(otherNodeID uint32, otherNodeIP net.IP, nextHopIP net.IP, podNetworks []*net.IPNet) (config controller.KeyValuePairs, err error) {
 	bsid := n.IPAM.BsidForNodeToNodePodPolicy(otherNodeIP) // this can be the same value one many nodes for the same target other node because it is not part of path
 	sid := n.IPAM.SidForNodeToNodePodLocalsid(otherNodeIP)
-	return n.srv6NodeToNodeTunnelIngress(nextHopIP, podNetwork, bsid, sid, "lookupInPodVRF")
+	config = make(controller.KeyValuePairs, 0)
+	for idx, podNetwork := range podNetworks {
+		if isIPv6(podNetwork.IP) != isIPv6(podNetworks[0].IP) {
+			continue
+		}
+		nameSuffix := "lookupInPodVRF"
+		if idx > 0 {
+			// additional pod subnet
+			nameSuffix = fmt.Sprintf("%s-%d", nameSuffix, idx)
+		}
+		tunnelConfig, err := n.srv6NodeToNodeTunnelIngress(nextHopIP, podNetwork, bsid, sid, nameSuffix)
+		if err != nil {
+			return config, err
+		}
+		mergeConfiguration(config, tunnelConfig)
+	}
+	return config, nil
 }
 
 // srv6NodeToNodePodTunnelIngress creates start node configuration for srv6 tunnel between nodes leading to main VRF table
@@ -1280,13 +1328,13 @@ func (n *IPNet) srv6NodeToNodeSegmentEgress(sid net.IP) (key string, config *vpp
 // connectivityToOtherNodePods returns configuration that will route traffic to pods of another node.
 func (n *IPNet) connectivityToOtherNodePods(network string, otherNodeID uint32, nextHopIP net.IP) (config controller.KeyValuePairs, err error) {
 	config = make(controller.KeyValuePairs, 0)
-	podNetwork, err := n.IPAM.PodSubnetOtherNode(network, otherNodeID)
-	if err != nil {
-		return config, fmt.Errorf("Failed to compute pod network for node ID %v, error: %v ", otherNodeID, err)
-	}
-
 	switch n.ContivConf.GetRoutingConfig().NodeToNodeTransport {
 	case contivconf.SRv6Transport:
+		podNetworks, err := n.IPAM.PodSubnetsOtherNode(network, otherNodeID)
+		if err != nil {
+			return config, fmt.Errorf("Failed to compute pod networks for node ID %v, error: %v ", otherNodeID, err)
+		}
+
 		// get other node IP
 		otherNodeIP, computeErr := n.otherNodeIPFromID(otherNodeID)
 		if computeErr != nil {
@@ -1296,7 +1344,7 @@ func (n *IPNet) connectivityToOtherNodePods(network string, otherNodeID uint32,
 
 		// get pod tunnel config
 		if !n.ContivConf.GetRoutingConfig().UseDX6ForSrv6NodetoNodeTransport { // pod tunnel uses DT6 -> can be created just on node create/update event, because further packet routing to pod is handled by routing table on other node
-			podTunnelConfig, err := n.srv6NodeToNodePodTunnelIngress(otherNodeID, otherNodeIP, nextHopIP, podNetwork)
+			podTunnelConfig, err := n.srv6NodeToNodePodTunnelIngress(otherNodeID, otherNodeIP, nextHopIP, podNetworks)
 			if err != nil {
 				return config, fmt.Errorf("can't create configuration for node-to-node SRv6 tunnel for Pod traffic due to: %v", err)
 			}
@@ -1312,13 +1360,46 @@ func (n *IPNet) connectivityToOtherNodePods(network string, otherNodeID uint32,
 	case contivconf.NoOverlayTransport:
 		fallthrough // the same as for VXLANTransport
 	case contivconf.VXLANTransport:
-		key, route := n.routeToOtherNodeNetworks(network, podNetwork, nextHopIP)
-		config[key] = route
+		podNetworks, err := n.IPAM.PodSubnetsOtherNode(network, otherNodeID)
+		if err != nil {
+			return config, fmt.Errorf("Failed to compute pod networks for node ID %v, error: %v ", otherNodeID, err)
+		}
+		for _, podNetwork := range podNetworks {
+			podNextHopIP := nextHopIP
+			if isIPv6(podNetwork.IP) != isIPv6(nextHopIP) {
+				// secondary pod subnet (dual-stack)
+				podNextHopIP = n.otherNodeNextHopIPForFamily(otherNodeID, nextHopIP, isIPv6(podNetwork.IP))
+			}
+			key, route := n.routeToOtherNodeNetworks(network, podNetwork, podNextHopIP)
+			config[key] = route
+		}
 	}
 
 	return config, nil
 }
 
+// otherNodeNextHopIPForFamily returns next hop address from the given IP family for
+// routes towards the secondary pod subnet (dual-stack) of the other node.
+// With VXLANs, the traffic of both families is routed via the (single-stack) BVI
+// of the other node. Without overlay, an IP address of the other node from the same
+// family is used if published, otherwise the default next hop is returned.
+func (n *IPNet) otherNodeNextHopIPForFamily(otherNodeID uint32, nextHopIP net.IP, ipv6 bool) net.IP {
+	if n.ContivConf.GetRoutingConfig().NodeToNodeTransport != contivconf.NoOverlayTransport {
+		return nextHopIP
+	}
+	for _, node := range n.NodeSync.GetAllNodes() {
+		if node.ID != otherNodeID {
+			continue
+		}
+		if nodeIP := otherNodeVppIP(node, ipv6); nodeIP != nil {
+			return nodeIP
+		}
+	}
+	n.Log.Warnf("Node ID %d has no IP address from the secondary IP family, "+
+		"using the primary next hop %v", otherNodeID, nextHopIP)
+	return nextHopIP
+}
+
 // connectivityToOtherNodeHostStack returns configuration that will route traffic to the host stack of another node.
 func (n *IPNet) connectivityToOtherNodeHostStack(otherNodeID uint32, nextHopIP net.IP) (config controller.KeyValuePairs, err error) {
 	config = make(controller.KeyValuePairs, 0)
@@ -1477,18 +1558,18 @@ func nodeHasIPAddress(node *nodesync.Node) bool {
 // clone creates a deep copy of customNetworkInfo.
 func (cn *customNetworkInfo) clone() (i *customNetworkInfo) {
 	res := &customNetworkInfo{
-		config:        proto.Clone(cn.config).(*customnetmodel.CustomNetwork),
 		localPods:     map[string]*podmanager.LocalPod{},
 		pods:          map[string]*podmanager.Pod{},
 		extInterfaces: map[string]*extifmodel.ExternalInterface{},
 		interfaces:    map[string][]string{},
 	}
+	if cn.config != nil {
+		res.config = proto.Clone(cn.config).(*customnetmodel.CustomNetwork)
+	}
 	for k, v := range cn.localPods {
 		res.localPods[k] = v
 	}
-	for _, v := range cn.localInterfaces {
-		cn.localInterfaces = append(cn.localInterfaces, v)
-	}
+	res.localInterfaces = append(res.localInterfaces, cn.localInterfaces...)
 	for k, v := range cn.pods {
 		res.pods[k] = v
 	}
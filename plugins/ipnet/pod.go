@@ -110,7 +110,15 @@ func (n *IPNet) podConnectivityConfig(pod *podmanager.LocalPod) (config controll
 	key, linuxLoop := n.podLinuxLoop(pod)
 	config[key] = linuxLoop
 
+	// with dual-stack, the pod has one IP address of each IP family (the main one first)
 	podIP := n.IPAM.GetPodIP(pod.ID)
+	podIPs := n.IPAM.GetPodIPs(pod.ID)
+	var secondaryIPs []string
+	for i, secondaryIP := range podIPs {
+		if i > 0 {
+			secondaryIPs = append(secondaryIPs, secondaryIP.String())
+		}
+	}
 
 	// create VPP to POD interconnect interface
 	var vppInterfaceToPod string
@@ -119,11 +127,13 @@ func (n *IPNet) podConnectivityConfig(pod *podmanager.LocalPod) (config controll
 		key, vppTap := n.podVPPTap(pod, podIP, "", DefaultPodNetworkName)
 		config[key] = vppTap
 		key, linuxTap := n.podLinuxTAP(pod, podIP, "", false)
+		linuxTap.IpAddresses = append(linuxTap.IpAddresses, secondaryIPs...)
 		config[key] = linuxTap
 		vppInterfaceToPod = vppTap.Name
 	} else {
 		// VETH pair + AF_PACKET
 		key, veth1 := n.podVeth1(pod, podIP, "", false)
+		veth1.IpAddresses = append(veth1.IpAddresses, secondaryIPs...)
 		config[key] = veth1
 		key, veth2 := n.podVeth2(pod, "")
 		config[key] = veth2
@@ -132,25 +142,29 @@ func (n *IPNet) podConnectivityConfig(pod *podmanager.LocalPod) (config controll
 		vppInterfaceToPod = afpacket.Name
 	}
 
-	// ARP to VPP
-	key, podArp := n.podToVPPArpEntry(pod, "", "", DefaultPodNetworkName)
-	config[key] = podArp
+	for _, gwIP := range n.IPAM.PodGatewayIPs(DefaultPodNetworkName) {
+		// ARP to VPP
+		key, podArp := n.podToVPPArpEntry(pod, "", "", gwIP)
+		config[key] = podArp
 
-	// link scope route
-	key, route := n.podToVPPLinkRoute(pod, "", "", DefaultPodNetworkName)
-	config[key] = route
+		// link scope route
+		key, route := n.podToVPPLinkRoute(pod, "", "", gwIP)
+		config[key] = route
 
-	// Add default route for the container
-	key, route = n.podToVPPDefaultRoute(pod, "", "", DefaultPodNetworkName)
-	config[key] = route
+		// Add default route for the container
+		key, route = n.podToVPPDefaultRoute(pod, "", "", gwIP)
+		config[key] = route
+	}
 
-	// ARP entry for POD IP
-	key, vppArp := n.vppToPodArpEntry(pod, podIP, "", "")
-	config[key] = vppArp
+	for _, ip := range podIPs {
+		// ARP entry for POD IP
+		key, vppArp := n.vppToPodArpEntry(pod, ip, "", "")
+		config[key] = vppArp
 
-	// route to PodIP via AF_PACKET / TAP
-	key, vppRoute := n.vppToPodRoute(pod, podIP, "", "", n.ContivConf.GetRoutingConfig().PodVRFID)
-	config[key] = vppRoute
+		// route to PodIP via AF_PACKET / TAP
+		key, vppRoute := n.vppToPodRoute(pod, ip, "", "", n.ContivConf.GetRoutingConfig().PodVRFID)
+		config[key] = vppRoute
+	}
 
 	// /32 (or /128 for ipv6) route from the host to POD (only in external IPAM case)
 	if n.ContivConf.GetIPAMConfig().UseExternalIPAM {
@@ -412,11 +426,11 @@ func (n *IPNet) linuxPodL3CustomIfConfig(pod *podmanager.LocalPod, customIf *pod
 	customNwCounter[customIf.ifNet]++
 
 	// ARP entry for the GW
-	key, podArp := n.podToVPPArpEntry(pod, customIf.ifName, customIf.ifType, customIf.ifNet)
+	key, podArp := n.podToVPPArpEntry(pod, customIf.ifName, customIf.ifType, n.IPAM.PodGatewayIP(customIf.ifNet))
 	config[key] = podArp
 
 	// link scope route for the GW
-	key, route := n.podToVPPLinkRoute(pod, customIf.ifName, customIf.ifType, customIf.ifNet)
+	key, route := n.podToVPPLinkRoute(pod, customIf.ifName, customIf.ifType, n.IPAM.PodGatewayIP(customIf.ifNet))
 	config[key] = route
 
 	// route for the whole L3 subnet
@@ -984,44 +998,45 @@ func (n *IPNet) podMicroserviceIPAlloc(pod *podmanager.LocalPod, podIP *net.IPNe
 /***************************** Pod ARPs and routes *****************************/
 
 // podToVPPArpEntry returns configuration for ARP entry resolving hardware address
-// for pod gateway IP from VPP.
-func (n *IPNet) podToVPPArpEntry(pod *podmanager.LocalPod, customIfName, customIfType, customIfNw string) (
+// for the given pod gateway IP from VPP.
+func (n *IPNet) podToVPPArpEntry(pod *podmanager.LocalPod, customIfName, customIfType string, gwIP net.IP) (
 	key string, config *linux_l3.ARPEntry) {
 
 	_, linuxIfName, _ := n.podInterfaceName(pod, customIfName, customIfType)
 	arp := &linux_l3.ARPEntry{
 		Interface: linuxIfName,
-		IpAddress: n.IPAM.PodGatewayIP(customIfNw).String(),
+		IpAddress: gwIP.String(),
 		HwAddress: n.hwAddrForPod(pod, customIfName, true),
 	}
 	key = linux_l3.ArpKey(arp.Interface, arp.IpAddress)
 	return key, arp
 }
 
-// podToVPPLinkRoute returns configuration for route that puts pod's default GW behind
-// the interface connecting pod with VPP (even though the GW IP does not fall into
+// podToVPPLinkRoute returns configuration for route that puts the given pod's default GW
+// behind the interface connecting pod with VPP (even though the GW IP does not fall into
 // the pod IP address network).
-func (n *IPNet) podToVPPLinkRoute(pod *podmanager.LocalPod, customIfName, customIfType, customIfNw string) (
+func (n *IPNet) podToVPPLinkRoute(pod *podmanager.LocalPod, customIfName, customIfType string, gwIP net.IP) (
 	key string, config *linux_l3.Route) {
 	_, linuxIfName, _ := n.podInterfaceName(pod, customIfName, customIfType)
 	route := &linux_l3.Route{
 		OutgoingInterface: linuxIfName,
 		Scope:             linux_l3.Route_LINK,
-		DstNetwork:        n.IPAM.PodGatewayIP(customIfNw).String() + hostPrefixForAF(n.IPAM.PodGatewayIP(customIfNw)),
+		DstNetwork:        gwIP.String() + hostPrefixForAF(gwIP),
 	}
 	key = linux_l3.RouteKey(route.DstNetwork, route.OutgoingInterface)
 	return key, route
 }
 
-// podToVPPDefaultRoute returns configuration for the default route of the given pod.
-func (n *IPNet) podToVPPDefaultRoute(pod *podmanager.LocalPod, customIfName, customIfType, customIfNw string) (
+// podToVPPDefaultRoute returns configuration for the default route of the given pod
+// (of the IP family of the given gateway IP).
+func (n *IPNet) podToVPPDefaultRoute(pod *podmanager.LocalPod, customIfName, customIfType string, gwIP net.IP) (
 	key string, config *linux_l3.Route) {
 	_, linuxIfName, _ := n.podInterfaceName(pod, customIfName, customIfType)
 	route := &linux_l3.Route{
 		OutgoingInterface: linuxIfName,
-		DstNetwork:        anyNetAddrForAF(n.IPAM.PodGatewayIP(customIfNw)),
+		DstNetwork:        anyNetAddrForAF(gwIP),
 		Scope:             linux_l3.Route_GLOBAL,
-		GwAddr:            n.IPAM.PodGatewayIP(customIfNw).String(),
+		GwAddr:            gwIP.String(),
 	}
 	key = linux_l3.RouteKey(route.DstNetwork, route.OutgoingInterface)
 	return key, route
@@ -40,13 +40,18 @@ func (n *IPNet) ipamGetHandler(formatter *render.Render) http.HandlerFunc {
 			return
 		}
 
+		var podSubnets []string
+		for _, podSubnet := range n.IPAM.PodSubnetsThisNode(DefaultPodNetworkName) {
+			podSubnets = append(podSubnets, podSubnet.String())
+		}
 		formatter.JSON(w, http.StatusOK, restapi.NodeIPAMInfo{
-			NodeID:            n.NodeSync.GetNodeID(),
-			NodeName:          n.ServiceLabel.GetAgentLabel(),
-			NodeIP:            nodeIP.String(),
-			PodSubnetThisNode: n.IPAM.PodSubnetThisNode(DefaultPodNetworkName).String(),
-			VppHostNetwork:    n.IPAM.HostInterconnectSubnetThisNode().String(),
-			Config:            n.IPAM.GetIPAMConfigForJSON(),
+			NodeID:             n.NodeSync.GetNodeID(),
+			NodeName:           n.ServiceLabel.GetAgentLabel(),
+			NodeIP:             nodeIP.String(),
+			PodSubnetThisNode:  podSubnets[0],
+			PodSubnetsThisNode: podSubnets,
+			VppHostNetwork:     n.IPAM.HostInterconnectSubnetThisNode().String(),
+			Config:             n.IPAM.GetIPAMConfigForJSON(),
 		})
 	}
 }
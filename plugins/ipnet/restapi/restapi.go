@@ -29,10 +29,11 @@ const (
 // NodeIPAMInfo represents runtime IPAM info about the current node.
 // It is exposed by the node IPAM REST handler.
 type NodeIPAMInfo struct {
-	NodeID            uint32             `json:"nodeId"`
-	NodeName          string             `json:"nodeName"`
-	NodeIP            string             `json:"nodeIP"`
-	PodSubnetThisNode string             `json:"podSubnetThisNode"`
-	VppHostNetwork    string             `json:"vppHostNetwork"`
-	Config            *config.IPAMConfig `json:"config"`
+	NodeID             uint32             `json:"nodeId"`
+	NodeName           string             `json:"nodeName"`
+	NodeIP             string             `json:"nodeIP"`
+	PodSubnetThisNode  string             `json:"podSubnetThisNode"`
+	PodSubnetsThisNode []string           `json:"podSubnetsThisNode"` // including additional and secondary pod subnets in use
+	VppHostNetwork     string             `json:"vppHostNetwork"`
+	Config             *config.IPAMConfig `json:"config"`
 }
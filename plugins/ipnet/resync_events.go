@@ -272,6 +272,21 @@ func (n *IPNet) configureMainVPPInterface(event controller.Event, txn controller
 		ipVersion = contivconf.IPv6
 	}
 	n.NodeSync.PublishNodeIPs(nodeIPs, ipVersion)
+	if n.ContivConf.GetIPAMConfig().DualStack {
+		// publish also statically configured IP addresses of the other IP family,
+		// used as next hops for the secondary pod subnet without overlay
+		var secondaryIPs contivconf.IPsWithNetworks
+		secondaryVersion := contivconf.IPv6
+		if ipVersion == contivconf.IPv6 {
+			secondaryVersion = contivconf.IPv4
+		}
+		for _, nicIP := range nicStaticIPs {
+			if isIPv6(nicIP.Address) == (secondaryVersion == contivconf.IPv6) {
+				secondaryIPs = append(secondaryIPs, nicIP)
+			}
+		}
+		n.NodeSync.PublishNodeIPs(secondaryIPs, secondaryVersion)
+	}
 
 	// 4. Configure the main interface
 
@@ -357,14 +372,16 @@ func (n *IPNet) configureVswitchHostConnectivity(txn controller.ResyncOperations
 		txn.Put(key, route)
 	}
 
-	// configure the route from the host to PODs
-	var routeToPods *linux_l3.Route
+	// configure the routes from the host to PODs
+	var routesToPods map[string]*linux_l3.Route
 	if !n.ContivConf.InSTNMode() {
-		key, routeToPods = n.routePODsFromHost(n.IPAM.HostInterconnectIPInVPP())
+		routesToPods = n.routesPODsFromHost(n.IPAM.HostInterconnectIPInVPP())
 	} else {
-		key, routeToPods = n.routePODsFromHost(n.stnGwIPForHost())
+		routesToPods = n.routesPODsFromHost(n.stnGwIPForHost())
+	}
+	for key, route := range routesToPods {
+		txn.Put(key, route)
 	}
-	txn.Put(key, routeToPods)
 
 	// route from the host to k8s service range from the host
 	if n.ContivConf.GetRoutingConfig().RouteServiceCIDRToVPP {
@@ -22,6 +22,7 @@ import (
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	customnetmodel "github.com/americanbinary/vpp/plugins/crd/handler/customnetwork/model"
 	extifmodel "github.com/americanbinary/vpp/plugins/crd/handler/externalinterface/model"
+	"github.com/americanbinary/vpp/plugins/ipam"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"github.com/americanbinary/vpp/plugins/nodesync"
 	"github.com/americanbinary/vpp/plugins/podmanager"
@@ -32,6 +33,8 @@ import (
 //   - AddPod and DeletePod (CNI)
 //   - POD k8s state changes
 //   - NodeUpdate for other nodes
+//   - PodSubnetLeaseChange (other nodes)
+//   - PodSubnetActivated
 //   - Shutdown event
 func (n *IPNet) Update(event controller.Event, txn controller.UpdateOperations) (change string, err error) {
 
@@ -92,6 +95,7 @@ func (n *IPNet) Update(event controller.Event, txn controller.UpdateOperations)
 				}
 			}
 
+			n.podChangeSnapshot = n.snapshotPodChange(ksChange)
 			n.cacheCustomNetworkInfo(ksChange)
 			if err = n.pushPodCustomIfUpdateEventIfNeeded(ksChange); err != nil {
 				return "", err
@@ -151,6 +155,16 @@ func (n *IPNet) Update(event controller.Event, txn controller.UpdateOperations)
 		return n.processNodeUpdateEvent(nodeUpdate, txn)
 	}
 
+	// pod subnets leased by other node changed
+	if leaseChange, isLeaseChange := event.(*ipam.PodSubnetLeaseChange); isLeaseChange {
+		return n.processPodSubnetLeaseChange(leaseChange, txn)
+	}
+
+	// additional pod subnet of this node started to be used
+	if subnetActivated, isSubnetActivated := event.(*ipam.PodSubnetActivated); isSubnetActivated {
+		return n.processPodSubnetActivated(subnetActivated, txn)
+	}
+
 	// shutdown
 	if _, isShutdown := event.(*controller.Shutdown); isShutdown {
 		return n.cleanupVswitchConnectivity(txn)
@@ -242,9 +256,64 @@ func (n *IPNet) pushPodCustomIfUpdateEventIfNeeded(ksChange *controller.KubeStat
 	return nil
 }
 
-// Revert is called for AddPod.
+// podChangeSnapshot is a snapshot of the internal state changed by KubeStateChange
+// of a pod.
+type podChangeSnapshot struct {
+	event           *controller.KubeStateChange
+	podID           podmodel.ID
+	pendingCustomIf bool
+	customNetworks  map[string]*customNetworkInfo // nil if the network was not known
+}
+
+// snapshotPodChange takes a snapshot of the internal state that is about to be
+// changed by the given KubeStateChange of a pod - pending custom interfaces
+// of the pod and custom networks of the pod interfaces.
+func (n *IPNet) snapshotPodChange(ksChange *controller.KubeStateChange) *podChangeSnapshot {
+	pod, _ := ksChange.NewValue.(*podmodel.Pod)
+	if pod == nil {
+		pod = ksChange.PrevValue.(*podmodel.Pod)
+	}
+	snapshot := &podChangeSnapshot{
+		event:           ksChange,
+		podID:           podmodel.GetID(pod),
+		pendingCustomIf: n.pendingAddPodCustomIf[podmodel.GetID(pod)],
+		customNetworks:  make(map[string]*customNetworkInfo),
+	}
+	if podMeta, hasPodMeta := n.PodManager.GetPods()[snapshot.podID]; hasPodMeta {
+		for _, customIfStr := range getContivCustomIfs(podMeta.Annotations) {
+			customIf, err := parseCustomIfInfo(customIfStr)
+			if err != nil {
+				continue
+			}
+			var nwSnapshot *customNetworkInfo
+			if nw := n.customNetworks[customIf.ifNet]; nw != nil {
+				nwSnapshot = nw.clone()
+			}
+			snapshot.customNetworks[customIf.ifNet] = nwSnapshot
+		}
+	}
+	return snapshot
+}
+
+// CanRevert returns true for KubeStateChange adding or updating a pod - the changed
+// state is restored from the snapshot taken by Update.
+// Removal of a pod releases pod IPs in IPAM, which cannot be reverted.
+func (n *IPNet) CanRevert(event controller.Event) bool {
+	ksChange, isKSChange := event.(*controller.KubeStateChange)
+	return isKSChange && ksChange.Resource == podmodel.PodKeyword && ksChange.NewValue != nil
+}
+
+// Revert is called for AddPod and after the dry-run of KubeStateChange
+// adding or updating a pod. Revert of other events is NOOP.
 func (n *IPNet) Revert(event controller.Event) error {
-	addPod := event.(*podmanager.AddPod)
+	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange {
+		n.revertPodChange(ksChange)
+		return nil
+	}
+	addPod, isAddPod := event.(*podmanager.AddPod)
+	if !isAddPod {
+		return nil
+	}
 	pod := n.PodManager.GetLocalPods()[addPod.Pod]
 	n.IPAM.ReleasePodIPs(pod.ID)
 
@@ -255,6 +324,28 @@ func (n *IPNet) Revert(event controller.Event) error {
 	return nil
 }
 
+// revertPodChange restores the internal state from the snapshot taken before
+// the given KubeStateChange of a pod was processed.
+func (n *IPNet) revertPodChange(ksChange *controller.KubeStateChange) {
+	snapshot := n.podChangeSnapshot
+	if snapshot == nil || snapshot.event != ksChange {
+		return
+	}
+	n.podChangeSnapshot = nil
+	if snapshot.pendingCustomIf {
+		n.pendingAddPodCustomIf[snapshot.podID] = true
+	} else {
+		delete(n.pendingAddPodCustomIf, snapshot.podID)
+	}
+	for nwName, nw := range snapshot.customNetworks {
+		if nw == nil {
+			delete(n.customNetworks, nwName)
+		} else {
+			n.customNetworks[nwName] = nw
+		}
+	}
+}
+
 // addPod connects a Pod container to the network.
 func (n *IPNet) addPod(event *podmanager.AddPod, txn controller.UpdateOperations) (change string, err error) {
 	pod := n.PodManager.GetLocalPods()[event.Pod]
@@ -298,27 +389,35 @@ func (n *IPNet) addPod(event *podmanager.AddPod, txn controller.UpdateOperations
 
 	// 5. fill event with the attributes of the configured pod connectivity for the CNI reply
 
-	ipVersion := podmanager.IPv4
-	if isIPv6(n.IPAM.GetPodIP(pod.ID).IP) {
-		ipVersion = podmanager.IPv6
-	}
-
-	event.Interfaces = append(event.Interfaces, podmanager.PodInterface{
+	// (one IP address and default route per IP family with dual-stack)
+	podIface := podmanager.PodInterface{
 		HostName: podInterfaceHostName,
-		IPAddresses: []*podmanager.IPWithGateway{
-			{
-				Version: ipVersion,
-				Address: n.IPAM.GetPodIP(pod.ID),
-				Gateway: n.IPAM.PodGatewayIP(DefaultPodNetworkName),
-			},
-		},
-	})
-	_, anyDstNet, _ := net.ParseCIDR(anyNetAddrForAF(n.IPAM.PodGatewayIP(DefaultPodNetworkName)))
-
-	event.Routes = append(event.Routes, podmanager.Route{
-		Network: anyDstNet,
-		Gateway: n.IPAM.PodGatewayIP(DefaultPodNetworkName),
-	})
+	}
+	gwIPs := n.IPAM.PodGatewayIPs(DefaultPodNetworkName)
+	for _, podIP := range n.IPAM.GetPodIPs(pod.ID) {
+		ipVersion := podmanager.IPv4
+		if isIPv6(podIP.IP) {
+			ipVersion = podmanager.IPv6
+		}
+		var gwIP net.IP
+		for _, ip := range gwIPs {
+			if isIPv6(ip) == isIPv6(podIP.IP) {
+				gwIP = ip
+				break
+			}
+		}
+		podIface.IPAddresses = append(podIface.IPAddresses, &podmanager.IPWithGateway{
+			Version: ipVersion,
+			Address: podIP,
+			Gateway: gwIP,
+		})
+		_, anyDstNet, _ := net.ParseCIDR(anyNetAddrForAF(gwIP))
+		event.Routes = append(event.Routes, podmanager.Route{
+			Network: anyDstNet,
+			Gateway: gwIP,
+		})
+	}
+	event.Interfaces = append(event.Interfaces, podIface)
 
 	return "configure IP connectivity", nil
 }
@@ -507,6 +606,52 @@ func (n *IPNet) processNodeUpdateEvent(nodeUpdate *nodesync.NodeUpdate, txn cont
 	return change, nil
 }
 
+// processPodSubnetLeaseChange updates routes towards pods of another node whose
+// leased pod subnets have changed.
+func (n *IPNet) processPodSubnetLeaseChange(leaseChange *ipam.PodSubnetLeaseChange,
+	txn controller.UpdateOperations) (change string, err error) {
+
+	var node *nodesync.Node
+	for _, otherNode := range n.NodeSync.GetAllNodes() {
+		if otherNode.ID == leaseChange.NodeID {
+			node = otherNode
+			break
+		}
+	}
+	if !nodeHasIPAddress(node) {
+		// routes will be configured once the node gets connected (NodeUpdate)
+		return "", nil
+	}
+
+	nextHop, err := n.otherNodeNextHopIP(node)
+	if err != nil {
+		n.Log.Error(err)
+		return "", err
+	}
+	for _, podNetwork := range leaseChange.PrevSubnets {
+		key, _ := n.routeToOtherNodeNetworks(DefaultPodNetworkName, podNetwork, nextHop)
+		txn.Delete(key)
+	}
+	for _, podNetwork := range leaseChange.NewSubnets {
+		key, route := n.routeToOtherNodeNetworks(DefaultPodNetworkName, podNetwork, nextHop)
+		txn.Put(key, route)
+	}
+	return fmt.Sprintf("update routes to pod subnets of node ID=%d", leaseChange.NodeID), nil
+}
+
+// processPodSubnetActivated routes the activated pod subnet of this node from Main VRF to Pod VRF.
+// With VXLANs, the pod subnets of all nodes are routed via Pod VRF from the start.
+func (n *IPNet) processPodSubnetActivated(subnetActivated *ipam.PodSubnetActivated,
+	txn controller.UpdateOperations) (change string, err error) {
+
+	if n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
+		return "", nil
+	}
+	key, route := n.routeMainToPodVRF(subnetActivated.Subnet)
+	txn.Put(key, route)
+	return fmt.Sprintf("route activated pod subnet %v into Pod VRF", subnetActivated.Subnet), nil
+}
+
 // cleanupVswitchConnectivity cleans up base vSwitch VPP connectivity
 // configuration in the host IP stack.
 func (n *IPNet) cleanupVswitchConnectivity(txn controller.UpdateOperations) (change string, err error) {
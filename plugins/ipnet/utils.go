@@ -219,6 +219,16 @@ func addFullPrefixToIP(ip net.IP) (*net.IPNet, error) {
 	return ipnet, err
 }
 
+// subnetsContain returns true if the given IP belongs to any of the subnets.
+func subnetsContain(subnets []*net.IPNet, ip net.IP) bool {
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // mergeConfiguration merges configuration from sourceConf to destConf.
 func mergeConfiguration(destConf, sourceConf controller.KeyValuePairs) {
 	if destConf == nil {
@@ -0,0 +1,222 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksr
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	contivppio "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
+	"github.com/americanbinary/vpp/plugins/ksr/model/policy"
+)
+
+// ClusterPolicyReflector subscribes to K8s cluster to watch for changes
+// in the configuration of Contiv cluster network policies (CRD).
+// Protobuf-modelled changes are published into the selected key-value store.
+type ClusterPolicyReflector struct {
+	Reflector
+
+	// CrdClient is used to access the custom resources of Contiv.
+	CrdClient *crdClientSet.Clientset
+}
+
+// Init subscribes to K8s cluster to watch for changes in the configuration
+// of cluster network policies. The subscription does not become active until
+// Start() is called.
+func (cpr *ClusterPolicyReflector) Init(stopCh2 <-chan struct{}, wg *sync.WaitGroup) error {
+	clusterPolicyReflectorFuncs := ReflectorFunctions{
+		EventHdlrFunc: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				cpr.addClusterPolicy(obj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				cpr.deleteClusterPolicy(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				cpr.updateClusterPolicy(oldObj, newObj)
+			},
+		},
+		ProtoAllocFunc: func() proto.Message {
+			return &policy.ClusterPolicy{}
+		},
+		K8s2NodeFunc: func(k8sObj interface{}) (interface{}, string, bool) {
+			k8sPolicy, ok := k8sObj.(*contivppio.ClusterNetworkPolicy)
+			if !ok {
+				cpr.Log.Errorf("cluster policy syncDataStore: wrong object type %s, obj %+v",
+					reflect.TypeOf(k8sObj), k8sObj)
+				return nil, "", false
+			}
+			return cpr.clusterPolicyToProto(k8sPolicy), policy.ClusterPolicyKey(k8sPolicy.Name), true
+		},
+		K8sClntGetFunc: func(_ *kubernetes.Clientset) rest.Interface {
+			// Use Contiv CRD API client for cluster policies
+			return cpr.CrdClient.ContivppV1().RESTClient()
+		},
+	}
+
+	return cpr.ksrInit(stopCh2, wg, policy.ClusterPolicyKeyPrefix(), "clusternetworkpolicies",
+		&contivppio.ClusterNetworkPolicy{}, clusterPolicyReflectorFuncs)
+}
+
+// addClusterPolicy adds state data of a newly created cluster network policy
+// into the data store.
+func (cpr *ClusterPolicyReflector) addClusterPolicy(obj interface{}) {
+	cpr.Log.WithField("clusterPolicy", obj).Info("Cluster policy added")
+
+	k8sPolicy, ok := obj.(*contivppio.ClusterNetworkPolicy)
+	if !ok {
+		cpr.Log.Warn("Failed to cast newly created cluster policy object")
+		cpr.stats.ArgErrors++
+		return
+	}
+
+	policyProto := cpr.clusterPolicyToProto(k8sPolicy)
+	cpr.ksrAdd(policy.ClusterPolicyKey(k8sPolicy.GetName()), policyProto)
+}
+
+// deleteClusterPolicy deletes state data of a removed cluster network policy
+// from the data store.
+func (cpr *ClusterPolicyReflector) deleteClusterPolicy(obj interface{}) {
+	cpr.Log.WithField("clusterPolicy", obj).Info("Cluster policy deleted")
+
+	k8sPolicy, ok := obj.(*contivppio.ClusterNetworkPolicy)
+	if !ok {
+		cpr.Log.Warn("Failed to cast removed cluster policy object")
+		cpr.stats.ArgErrors++
+		return
+	}
+
+	cpr.ksrDelete(policy.ClusterPolicyKey(k8sPolicy.GetName()))
+}
+
+// updateClusterPolicy updates state data of a changed cluster network policy
+// in the data store.
+func (cpr *ClusterPolicyReflector) updateClusterPolicy(oldObj, newObj interface{}) {
+	oldK8sPolicy, ok1 := oldObj.(*contivppio.ClusterNetworkPolicy)
+	newK8sPolicy, ok2 := newObj.(*contivppio.ClusterNetworkPolicy)
+	if !ok1 || !ok2 {
+		cpr.Log.Warn("Failed to cast changed cluster policy object")
+		cpr.stats.ArgErrors++
+		return
+	}
+	cpr.Log.WithFields(map[string]interface{}{"policy-old": oldK8sPolicy, "policy-new": newK8sPolicy}).
+		Info("Cluster policy updated")
+
+	oldPolicyProto := cpr.clusterPolicyToProto(oldK8sPolicy)
+	newPolicyProto := cpr.clusterPolicyToProto(newK8sPolicy)
+	key := policy.ClusterPolicyKey(newK8sPolicy.GetName())
+	cpr.ksrUpdate(key, oldPolicyProto, newPolicyProto)
+}
+
+// clusterPolicyToProto converts cluster network policy from the k8s representation
+// into our protobuf-modelled data structure.
+func (cpr *ClusterPolicyReflector) clusterPolicyToProto(k8sPolicy *contivppio.ClusterNetworkPolicy) *policy.ClusterPolicy {
+	spec := k8sPolicy.Spec
+	policyProto := &policy.ClusterPolicy{
+		Name:     k8sPolicy.GetName(),
+		Tier:     spec.Tier,
+		Priority: spec.Priority,
+	}
+	if spec.NamespaceSelector != nil {
+		policyProto.Namespaces = labelSelectorToProto(spec.NamespaceSelector)
+	}
+	if spec.PodSelector != nil {
+		policyProto.Pods = labelSelectorToProto(spec.PodSelector)
+	}
+	policyProto.IngressRule = cpr.rulesToProto(spec.Ingress)
+	policyProto.EgressRule = cpr.rulesToProto(spec.Egress)
+	return policyProto
+}
+
+// rulesToProto converts a list of cluster policy rules from the k8s representation
+// into our protobuf-modelled data structure.
+func (cpr *ClusterPolicyReflector) rulesToProto(rules []contivppio.ClusterNetworkPolicyRule) (rulesProto []*policy.ClusterPolicy_Rule) {
+	for _, rule := range rules {
+		ruleProto := &policy.ClusterPolicy_Rule{}
+		// Action
+		switch strings.ToLower(rule.Action) {
+		case strings.ToLower(contivppio.ClusterPolicyAllow):
+			ruleProto.Action = policy.ClusterPolicy_ALLOW
+		case strings.ToLower(contivppio.ClusterPolicyDeny):
+			ruleProto.Action = policy.ClusterPolicy_DENY
+		case strings.ToLower(contivppio.ClusterPolicyPass):
+			ruleProto.Action = policy.ClusterPolicy_PASS
+		default:
+			cpr.Log.Warnf("Skipping cluster policy rule with unsupported action: '%s'", rule.Action)
+			continue
+		}
+		// Ports
+		for _, port := range rule.Ports {
+			portProto := &policy.Policy_Port{}
+			switch coreV1.Protocol(strings.ToUpper(port.Protocol)) {
+			case "", coreV1.ProtocolTCP:
+				portProto.Protocol = policy.Policy_Port_TCP
+			case coreV1.ProtocolUDP:
+				portProto.Protocol = policy.Policy_Port_UDP
+			case coreV1.ProtocolSCTP:
+				portProto.Protocol = policy.Policy_Port_SCTP
+			default:
+				// IP protocol given by number
+				protoNum, err := strconv.ParseUint(port.Protocol, 10, 8)
+				if err != nil {
+					cpr.Log.Warnf("Skipping cluster policy port with unsupported protocol: %s", port.Protocol)
+					continue
+				}
+				portProto.Protocol = policy.Policy_Port_OTHER
+				portProto.ProtocolNumber = uint32(protoNum)
+			}
+			if port.Port != 0 {
+				portProto.Port = &policy.Policy_Port_PortNameOrNumber{
+					Type:   policy.Policy_Port_PortNameOrNumber_NUMBER,
+					Number: port.Port,
+				}
+				portProto.EndPort = port.EndPort
+			}
+			ruleProto.Port = append(ruleProto.Port, portProto)
+		}
+		// Peers
+		for _, peer := range rule.Peers {
+			peerProto := &policy.ClusterPolicy_Peer{}
+			if peer.IPBlock != nil {
+				peerProto.IpBlock = &policy.Policy_Peer_IPBlock{
+					Cidr: peer.IPBlock.CIDR,
+				}
+				for _, except := range peer.IPBlock.Except {
+					peerProto.IpBlock.Except = append(peerProto.IpBlock.Except, except)
+				}
+			} else {
+				if peer.NamespaceSelector != nil {
+					peerProto.Namespaces = labelSelectorToProto(peer.NamespaceSelector)
+				}
+				if peer.PodSelector != nil {
+					peerProto.Pods = labelSelectorToProto(peer.PodSelector)
+				}
+			}
+			ruleProto.Peer = append(ruleProto.Peer, peerProto)
+		}
+		rulesProto = append(rulesProto, ruleProto)
+	}
+	return rulesProto
+}
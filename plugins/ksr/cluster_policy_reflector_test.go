@@ -0,0 +1,288 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksr
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	contivppio "github.com/americanbinary/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
+	"github.com/americanbinary/vpp/plugins/ksr/model/policy"
+	"go.ligato.io/cn-infra/v2/logging"
+)
+
+type ClusterPolicyTestVars struct {
+	k8sListWatch           *mockK8sListWatch
+	mockKvBroker           *mockKeyProtoValBroker
+	clusterPolicyReflector *ClusterPolicyReflector
+	clusterPolicyTestData  []contivppio.ClusterNetworkPolicy
+	reflectorRegistry      ReflectorRegistry
+}
+
+var clusterPolicyTestVars ClusterPolicyTestVars
+
+func TestClusterPolicyReflector(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	clusterPolicyTestVars.k8sListWatch = &mockK8sListWatch{}
+	clusterPolicyTestVars.mockKvBroker = newMockKeyProtoValBroker()
+
+	clusterPolicyTestVars.reflectorRegistry = ReflectorRegistry{
+		reflectors: make(map[string]*Reflector),
+		lock:       sync.RWMutex{},
+	}
+
+	clusterPolicyTestVars.clusterPolicyReflector = &ClusterPolicyReflector{
+		Reflector: Reflector{
+			Log:               logging.ForPlugin("cluster-policy-reflector"),
+			K8sClientset:      &kubernetes.Clientset{},
+			K8sListWatch:      clusterPolicyTestVars.k8sListWatch,
+			Broker:            clusterPolicyTestVars.mockKvBroker,
+			dsSynced:          false,
+			objType:           clusterPolicyObjType,
+			ReflectorRegistry: &clusterPolicyTestVars.reflectorRegistry,
+		},
+		CrdClient: &crdClientSet.Clientset{},
+	}
+
+	port53 := contivppio.ClusterNetworkPolicyPort{Protocol: "UDP", Port: 53}
+	clusterPolicyTestVars.clusterPolicyTestData = []contivppio.ClusterNetworkPolicy{
+		// Test data 0: deny egress to the metadata CIDR from all pods
+		{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "deny-metadata",
+			},
+			Spec: contivppio.ClusterNetworkPolicySpec{
+				Tier:     1,
+				Priority: 10,
+				Egress: []contivppio.ClusterNetworkPolicyRule{
+					{
+						Action: contivppio.ClusterPolicyDeny,
+						Peers: []contivppio.ClusterNetworkPolicyPeer{
+							{
+								IPBlock: &contivppio.ClusterNetworkPolicyIPBlock{
+									CIDR: "169.254.169.254/32",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		// Test data 1: always allow DNS towards kube-system
+		{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "allow-dns",
+			},
+			Spec: contivppio.ClusterNetworkPolicySpec{
+				Tier:     1,
+				Priority: 20,
+				Egress: []contivppio.ClusterNetworkPolicyRule{
+					{
+						Action: contivppio.ClusterPolicyAllow,
+						Ports:  []contivppio.ClusterNetworkPolicyPort{port53},
+						Peers: []contivppio.ClusterNetworkPolicyPeer{
+							{
+								NamespaceSelector: &metaV1.LabelSelector{
+									MatchLabels: map[string]string{"name": "kube-system"},
+								},
+								PodSelector: &metaV1.LabelSelector{
+									MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		// Test data 2: pass ingress of selected pods to namespace policies,
+		// stale object deleted during the resync
+		{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "pass-ingress",
+			},
+			Spec: contivppio.ClusterNetworkPolicySpec{
+				Tier: 2,
+				NamespaceSelector: &metaV1.LabelSelector{
+					MatchLabels: map[string]string{"team": "dev"},
+				},
+				Ingress: []contivppio.ClusterNetworkPolicyRule{
+					{
+						Action: contivppio.ClusterPolicyPass,
+						Ports: []contivppio.ClusterNetworkPolicyPort{
+							{Protocol: "TCP", Port: 8000, EndPort: 8080},
+						},
+					},
+					{
+						Action: "Reject", // unsupported, skipped
+					},
+				},
+			},
+		},
+	}
+
+	MockK8sCache.ListFunc = func() []interface{} {
+		return []interface{}{
+			// Updated value mock
+			&clusterPolicyTestVars.clusterPolicyTestData[0],
+			// New value mock
+			&clusterPolicyTestVars.clusterPolicyTestData[1],
+		}
+	}
+
+	// Pre-populate the mock data store with pre-existing data that is supposed
+	// to be updated during resync.
+	k8sPolicy0 := &clusterPolicyTestVars.clusterPolicyTestData[0]
+	protoPolicy0 := clusterPolicyTestVars.clusterPolicyReflector.clusterPolicyToProto(k8sPolicy0)
+	protoPolicy0.Priority = 100
+	clusterPolicyTestVars.mockKvBroker.Put(policy.ClusterPolicyKey(k8sPolicy0.GetName()), protoPolicy0)
+
+	// Pre-populate the mock data store with "stale" data that is supposed to
+	// be deleted during resync.
+	k8sPolicy2 := &clusterPolicyTestVars.clusterPolicyTestData[2]
+	protoPolicy2 := clusterPolicyTestVars.clusterPolicyReflector.clusterPolicyToProto(k8sPolicy2)
+	clusterPolicyTestVars.mockKvBroker.Put(policy.ClusterPolicyKey(k8sPolicy2.GetName()), protoPolicy2)
+
+	statsBefore := *clusterPolicyTestVars.clusterPolicyReflector.GetStats()
+
+	stopCh := make(chan struct{})
+	var wg sync.WaitGroup
+	err := clusterPolicyTestVars.clusterPolicyReflector.Init(stopCh, &wg)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	clusterPolicyTestVars.clusterPolicyReflector.startDataStoreResync()
+
+	// Wait for the initial sync to finish
+	for {
+		if clusterPolicyTestVars.clusterPolicyReflector.HasSynced() {
+			break
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	statsAfter := *clusterPolicyTestVars.clusterPolicyReflector.GetStats()
+
+	gomega.Expect(clusterPolicyTestVars.mockKvBroker.ds).Should(gomega.HaveLen(2))
+	gomega.Expect(statsBefore.Adds + 1).Should(gomega.BeNumerically("==", statsAfter.Adds))
+	gomega.Expect(statsBefore.Updates + 1).Should(gomega.BeNumerically("==", statsAfter.Updates))
+	gomega.Expect(statsBefore.Deletes + 1).Should(gomega.BeNumerically("==", statsAfter.Deletes))
+
+	clusterPolicyTestVars.mockKvBroker.ClearDs()
+	t.Run("clusterPolicyToProto", testClusterPolicyToProto)
+
+	clusterPolicyTestVars.mockKvBroker.ClearDs()
+	t.Run("addUpdateDeleteClusterPolicy", testAddUpdateDeleteClusterPolicy)
+
+	MockK8sCache.ListFunc = nil
+}
+
+func testClusterPolicyToProto(t *testing.T) {
+	reflector := clusterPolicyTestVars.clusterPolicyReflector
+
+	denyMetadata := reflector.clusterPolicyToProto(&clusterPolicyTestVars.clusterPolicyTestData[0])
+	gomega.Expect(denyMetadata.Name).To(gomega.Equal("deny-metadata"))
+	gomega.Expect(denyMetadata.Tier).To(gomega.BeEquivalentTo(1))
+	gomega.Expect(denyMetadata.Priority).To(gomega.BeEquivalentTo(10))
+	gomega.Expect(denyMetadata.Namespaces).To(gomega.BeNil())
+	gomega.Expect(denyMetadata.Pods).To(gomega.BeNil())
+	gomega.Expect(denyMetadata.IngressRule).To(gomega.BeEmpty())
+	gomega.Expect(denyMetadata.EgressRule).To(gomega.HaveLen(1))
+	gomega.Expect(denyMetadata.EgressRule[0].Action).To(gomega.Equal(policy.ClusterPolicy_DENY))
+	gomega.Expect(denyMetadata.EgressRule[0].Port).To(gomega.BeEmpty())
+	gomega.Expect(denyMetadata.EgressRule[0].Peer).To(gomega.HaveLen(1))
+	gomega.Expect(denyMetadata.EgressRule[0].Peer[0].IpBlock.Cidr).To(gomega.Equal("169.254.169.254/32"))
+
+	allowDNS := reflector.clusterPolicyToProto(&clusterPolicyTestVars.clusterPolicyTestData[1])
+	gomega.Expect(allowDNS.EgressRule).To(gomega.HaveLen(1))
+	rule := allowDNS.EgressRule[0]
+	gomega.Expect(rule.Action).To(gomega.Equal(policy.ClusterPolicy_ALLOW))
+	gomega.Expect(rule.Port).To(gomega.HaveLen(1))
+	gomega.Expect(rule.Port[0].Protocol).To(gomega.Equal(policy.Policy_Port_UDP))
+	gomega.Expect(rule.Port[0].Port.Number).To(gomega.BeEquivalentTo(53))
+	gomega.Expect(rule.Peer).To(gomega.HaveLen(1))
+	gomega.Expect(rule.Peer[0].IpBlock).To(gomega.BeNil())
+	gomega.Expect(rule.Peer[0].Namespaces.MatchLabel).To(gomega.ConsistOf(
+		&policy.Policy_Label{Key: "name", Value: "kube-system"}))
+	gomega.Expect(rule.Peer[0].Pods.MatchLabel).To(gomega.ConsistOf(
+		&policy.Policy_Label{Key: "k8s-app", Value: "kube-dns"}))
+
+	passIngress := reflector.clusterPolicyToProto(&clusterPolicyTestVars.clusterPolicyTestData[2])
+	gomega.Expect(passIngress.Namespaces.MatchLabel).To(gomega.ConsistOf(
+		&policy.Policy_Label{Key: "team", Value: "dev"}))
+	gomega.Expect(passIngress.Pods).To(gomega.BeNil())
+	gomega.Expect(passIngress.IngressRule).To(gomega.HaveLen(1)) // rule with unsupported action is skipped
+	rule = passIngress.IngressRule[0]
+	gomega.Expect(rule.Action).To(gomega.Equal(policy.ClusterPolicy_PASS))
+	gomega.Expect(rule.Peer).To(gomega.BeEmpty())
+	gomega.Expect(rule.Port).To(gomega.HaveLen(1))
+	gomega.Expect(rule.Port[0].Protocol).To(gomega.Equal(policy.Policy_Port_TCP))
+	gomega.Expect(rule.Port[0].Port.Number).To(gomega.BeEquivalentTo(8000))
+	gomega.Expect(rule.Port[0].EndPort).To(gomega.BeEquivalentTo(8080))
+}
+
+func testAddUpdateDeleteClusterPolicy(t *testing.T) {
+	reflector := clusterPolicyTestVars.clusterPolicyReflector
+	k8sPolicy := clusterPolicyTestVars.clusterPolicyTestData[0]
+	key := policy.ClusterPolicyKey(k8sPolicy.GetName())
+
+	// Take a snapshot of counters
+	adds := reflector.GetStats().Adds
+	argErrs := reflector.GetStats().ArgErrors
+
+	// Test add with wrong argument type
+	clusterPolicyTestVars.k8sListWatch.Add(k8sPolicy)
+
+	gomega.Expect(argErrs + 1).To(gomega.Equal(reflector.GetStats().ArgErrors))
+	gomega.Expect(adds).To(gomega.Equal(reflector.GetStats().Adds))
+
+	// Test add where everything should be good
+	clusterPolicyTestVars.k8sListWatch.Add(&k8sPolicy)
+
+	protoPolicy := &policy.ClusterPolicy{}
+	found, _, err := clusterPolicyTestVars.mockKvBroker.GetValue(key, protoPolicy)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(adds + 1).To(gomega.Equal(reflector.GetStats().Adds))
+	gomega.Expect(protoPolicy.Tier).To(gomega.BeEquivalentTo(1))
+
+	// Test update
+	updates := reflector.GetStats().Updates
+	newK8sPolicy := *k8sPolicy.DeepCopy()
+	newK8sPolicy.Spec.Tier = 0
+	clusterPolicyTestVars.k8sListWatch.Update(&k8sPolicy, &newK8sPolicy)
+
+	gomega.Expect(updates + 1).To(gomega.Equal(reflector.GetStats().Updates))
+	protoPolicy = &policy.ClusterPolicy{}
+	found, _, err = clusterPolicyTestVars.mockKvBroker.GetValue(key, protoPolicy)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(protoPolicy.Tier).To(gomega.BeEquivalentTo(0))
+
+	// Test delete
+	dels := reflector.GetStats().Deletes
+	clusterPolicyTestVars.k8sListWatch.Delete(&newK8sPolicy)
+
+	gomega.Expect(dels + 1).To(gomega.Equal(reflector.GetStats().Deletes))
+	found, _, err = clusterPolicyTestVars.mockKvBroker.GetValue(key, &policy.ClusterPolicy{})
+	gomega.Expect(found).To(gomega.BeFalse())
+	gomega.Expect(err).To(gomega.BeNil())
+}
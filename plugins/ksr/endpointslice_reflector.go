@@ -0,0 +1,203 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksr
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
+)
+
+const (
+	// endpointSliceGroupVersion is the API group version of reflected EndpointSlices.
+	endpointSliceGroupVersion = "discovery.k8s.io/v1"
+
+	// hostnameTopologyKey is the topology key with the name of the node hosting an endpoint.
+	hostnameTopologyKey = "kubernetes.io/hostname"
+)
+
+// EndpointSliceReflector subscribes to K8s cluster to watch for changes
+// in the configuration of k8s endpoint slices.
+// Protobuf-modelled changes are published into the selected key-value store.
+//
+// Conditions of endpoints are reflected as reported by K8s. Clusters which
+// do not report the "serving" and "terminating" conditions (older than 1.20
+// or with the EndpointSliceTerminatingCondition feature disabled) get "serving"
+// equal to "ready" and never report endpoints as terminating.
+type EndpointSliceReflector struct {
+	Reflector
+}
+
+// Init subscribes to K8s cluster to watch for changes in the configuration
+// of k8s endpoint slices. The subscription does not become active until
+// Start() is called.
+func (esr *EndpointSliceReflector) Init(stopCh2 <-chan struct{}, wg *sync.WaitGroup) error {
+	endpointSliceReflectorFuncs := ReflectorFunctions{
+		EventHdlrFunc: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				esr.addEndpointSlice(obj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				esr.deleteEndpointSlice(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				esr.updateEndpointSlice(oldObj, newObj)
+			},
+		},
+		ProtoAllocFunc: func() proto.Message {
+			return &endpointslice.EndpointSlice{}
+		},
+		K8s2NodeFunc: func(k8sObj interface{}) (interface{}, string, bool) {
+			k8sSlice, ok := k8sObj.(*discovery.EndpointSlice)
+			if !ok {
+				esr.Log.Errorf("endpoint slice syncDataStore: wrong object type %s, obj %+v",
+					reflect.TypeOf(k8sObj), k8sObj)
+				return nil, "", false
+			}
+			return esr.endpointSliceToProto(k8sSlice), endpointslice.Key(k8sSlice.Name, k8sSlice.Namespace), true
+		},
+		K8sClntGetFunc: func(cs *kubernetes.Clientset) rest.Interface {
+			return cs.DiscoveryV1().RESTClient()
+		},
+	}
+
+	return esr.ksrInit(stopCh2, wg, endpointslice.KeyPrefix(), "endpointslices",
+		&discovery.EndpointSlice{}, endpointSliceReflectorFuncs)
+}
+
+// addEndpointSlice adds state data of a newly created K8s endpoint slice into the data store.
+func (esr *EndpointSliceReflector) addEndpointSlice(obj interface{}) {
+	slice, ok := obj.(*discovery.EndpointSlice)
+	if !ok {
+		esr.Log.Warn("Failed to cast newly created endpoint slice object")
+		esr.stats.ArgErrors++
+		return
+	}
+
+	esr.Log.WithField("endpointSlice", obj).Info("addEndpointSlice")
+	sliceProto := esr.endpointSliceToProto(slice)
+	key := endpointslice.Key(slice.GetName(), slice.GetNamespace())
+	esr.ksrAdd(key, sliceProto)
+}
+
+// deleteEndpointSlice deletes state data of a removed K8s endpoint slice from the data store.
+func (esr *EndpointSliceReflector) deleteEndpointSlice(obj interface{}) {
+	slice, ok := obj.(*discovery.EndpointSlice)
+	if !ok {
+		esr.Log.Warn("Failed to cast removed endpoint slice object")
+		esr.stats.ArgErrors++
+		return
+	}
+
+	esr.Log.WithField("endpointSlice", obj).Info("deleteEndpointSlice")
+	key := endpointslice.Key(slice.GetName(), slice.GetNamespace())
+	esr.ksrDelete(key)
+}
+
+// updateEndpointSlice updates state data of a changed K8s endpoint slice in the data store.
+func (esr *EndpointSliceReflector) updateEndpointSlice(oldObj, newObj interface{}) {
+	sliceOld, ok1 := oldObj.(*discovery.EndpointSlice)
+	sliceNew, ok2 := newObj.(*discovery.EndpointSlice)
+	if !ok1 || !ok2 {
+		esr.Log.Warn("Failed to cast changed endpoint slice object")
+		esr.stats.ArgErrors++
+		return
+	}
+
+	esr.Log.WithFields(map[string]interface{}{"name": sliceNew.Name, "namespace": sliceNew.Namespace}).
+		Info("Endpoint slice updated")
+
+	sliceProtoNew := esr.endpointSliceToProto(sliceNew)
+	sliceProtoOld := esr.endpointSliceToProto(sliceOld)
+	key := endpointslice.Key(sliceNew.GetName(), sliceNew.GetNamespace())
+	esr.ksrUpdate(key, sliceProtoOld, sliceProtoNew)
+}
+
+// endpointSliceToProto converts endpoint slice data from the k8s representation
+// into our protobuf-modelled data structure.
+func (esr *EndpointSliceReflector) endpointSliceToProto(slice *discovery.EndpointSlice) *endpointslice.EndpointSlice {
+	sliceProto := &endpointslice.EndpointSlice{}
+	sliceProto.Name = slice.GetName()
+	sliceProto.Namespace = slice.GetNamespace()
+	sliceProto.ServiceName = slice.GetLabels()[discovery.LabelServiceName]
+	sliceProto.AddressType = string(slice.AddressType)
+
+	for _, ep := range slice.Endpoints {
+		sliceProto.Endpoints = append(sliceProto.Endpoints, esr.endpointToProto(&ep))
+	}
+
+	for _, port := range slice.Ports {
+		portProto := &endpointslice.EndpointPort{}
+		if port.Name != nil {
+			portProto.Name = *port.Name
+		}
+		if port.Protocol != nil {
+			portProto.Protocol = string(*port.Protocol)
+		}
+		if port.Port != nil {
+			portProto.Port = *port.Port
+		}
+		sliceProto.Ports = append(sliceProto.Ports, portProto)
+	}
+
+	return sliceProto
+}
+
+// endpointToProto converts a single endpoint from the k8s representation
+// into our protobuf-modelled data structure.
+func (esr *EndpointSliceReflector) endpointToProto(ep *discovery.Endpoint) *endpointslice.Endpoint {
+	epProto := &endpointslice.Endpoint{}
+	epProto.Addresses = ep.Addresses
+	if ep.NodeName != nil {
+		epProto.NodeName = *ep.NodeName
+	} else {
+		epProto.NodeName = ep.DeprecatedTopology[hostnameTopologyKey]
+	}
+	if ep.Hostname != nil {
+		epProto.Hostname = *ep.Hostname
+	}
+
+	if ep.TargetRef != nil {
+		epProto.TargetRef = &endpointslice.ObjectReference{
+			Kind:      ep.TargetRef.Kind,
+			Namespace: ep.TargetRef.Namespace,
+			Name:      ep.TargetRef.Name,
+			Uid:       string(ep.TargetRef.UID),
+		}
+	}
+
+	// nil ready condition should be interpreted as "unknown state", i.e. ready
+	ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+	// serving is not reported by older clusters, where it equals ready
+	serving := ready
+	if ep.Conditions.Serving != nil {
+		serving = *ep.Conditions.Serving
+	}
+	epProto.Conditions = &endpointslice.EndpointConditions{
+		Ready:       ready,
+		Serving:     serving,
+		Terminating: ep.Conditions.Terminating != nil && *ep.Conditions.Terminating,
+	}
+
+	return epProto
+}
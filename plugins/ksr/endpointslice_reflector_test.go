@@ -0,0 +1,336 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksr
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	coreV1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
+	"go.ligato.io/cn-infra/v2/logging"
+)
+
+type EndpointSliceTestVars struct {
+	k8sListWatch      *mockK8sListWatch
+	mockKvBroker      *mockKeyProtoValBroker
+	sliceReflector    *EndpointSliceReflector
+	sliceTestData     []discovery.EndpointSlice
+	reflectorRegistry ReflectorRegistry
+}
+
+var epSliceTestVars EndpointSliceTestVars
+
+func TestEndpointSliceReflector(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	epSliceTestVars.k8sListWatch = &mockK8sListWatch{}
+	epSliceTestVars.mockKvBroker = newMockKeyProtoValBroker()
+
+	epSliceTestVars.reflectorRegistry = ReflectorRegistry{
+		reflectors: make(map[string]*Reflector),
+		lock:       sync.RWMutex{},
+	}
+
+	epSliceTestVars.sliceReflector = &EndpointSliceReflector{
+		Reflector: Reflector{
+			Log:               logging.ForPlugin("endpointslice-reflector"),
+			K8sClientset:      &kubernetes.Clientset{},
+			K8sListWatch:      epSliceTestVars.k8sListWatch,
+			Broker:            epSliceTestVars.mockKvBroker,
+			dsSynced:          false,
+			objType:           endpointSliceObjType,
+			ReflectorRegistry: &epSliceTestVars.reflectorRegistry,
+		},
+	}
+
+	ready := true
+	notReady := false
+	node1 := "node1"
+	node2 := "node2"
+	portName := "http"
+	protocol := coreV1.ProtocolTCP
+	port := int32(80)
+	epSliceTestVars.sliceTestData = []discovery.EndpointSlice{
+		{
+			// Test data 0: slice with one ready, one not-ready and one
+			// terminating endpoint
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "my-nginx-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{discovery.LabelServiceName: "my-nginx"},
+			},
+			AddressType: discovery.AddressTypeIPv4,
+			Endpoints: []discovery.Endpoint{
+				{
+					Addresses:  []string{"10.1.1.3"},
+					Conditions: discovery.EndpointConditions{Ready: &ready, Serving: &ready, Terminating: &notReady},
+					TargetRef:  &coreV1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "my-nginx-1"},
+					NodeName:   &node1,
+				},
+				{
+					// reported by a cluster without the serving and terminating conditions
+					Addresses:          []string{"10.1.2.3"},
+					Conditions:         discovery.EndpointConditions{Ready: &notReady},
+					TargetRef:          &coreV1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "my-nginx-2"},
+					DeprecatedTopology: map[string]string{hostnameTopologyKey: "node2"},
+				},
+				{
+					Addresses:  []string{"10.1.2.4"},
+					Conditions: discovery.EndpointConditions{Ready: &notReady, Serving: &ready, Terminating: &ready},
+					TargetRef:  &coreV1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "my-nginx-3"},
+					NodeName:   &node2,
+				},
+			},
+			Ports: []discovery.EndpointPort{
+				{Name: &portName, Protocol: &protocol, Port: &port},
+			},
+		},
+		{
+			// Test data 1: slice with endpoint in the unknown state
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "my-httpd-fghij",
+				Namespace: "default",
+				Labels:    map[string]string{discovery.LabelServiceName: "my-httpd"},
+			},
+			AddressType: discovery.AddressTypeIPv4,
+			Endpoints: []discovery.Endpoint{
+				{
+					Addresses: []string{"10.1.1.4"},
+				},
+			},
+			Ports: []discovery.EndpointPort{
+				{Protocol: &protocol, Port: &port},
+			},
+		},
+		{
+			// Test data 2: stale slice deleted during the resync
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "my-stale-klmno",
+				Namespace: "default",
+				Labels:    map[string]string{discovery.LabelServiceName: "my-stale"},
+			},
+			AddressType: discovery.AddressTypeIPv4,
+		},
+	}
+
+	MockK8sCache.ListFunc = func() []interface{} {
+		return []interface{}{
+			// Updated value mock
+			&epSliceTestVars.sliceTestData[0],
+			// New value mock
+			&epSliceTestVars.sliceTestData[1],
+		}
+	}
+
+	// Pre-populate the mock data store with pre-existing data that is supposed
+	// to be updated during resync.
+	k8sSlice0 := &epSliceTestVars.sliceTestData[0]
+	protoSlice0 := epSliceTestVars.sliceReflector.endpointSliceToProto(k8sSlice0)
+	protoSlice0.Endpoints = protoSlice0.Endpoints[:1]
+	epSliceTestVars.mockKvBroker.Put(endpointslice.Key(k8sSlice0.GetName(), k8sSlice0.GetNamespace()), protoSlice0)
+
+	// Pre-populate the mock data store with "stale" data that is supposed to
+	// be deleted during resync.
+	k8sSlice2 := &epSliceTestVars.sliceTestData[2]
+	protoSlice2 := epSliceTestVars.sliceReflector.endpointSliceToProto(k8sSlice2)
+	epSliceTestVars.mockKvBroker.Put(endpointslice.Key(k8sSlice2.GetName(), k8sSlice2.GetNamespace()), protoSlice2)
+
+	statsBefore := *epSliceTestVars.sliceReflector.GetStats()
+
+	stopCh := make(chan struct{})
+	var wg sync.WaitGroup
+	err := epSliceTestVars.sliceReflector.Init(stopCh, &wg)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	epSliceTestVars.sliceReflector.startDataStoreResync()
+
+	// Wait for the initial sync to finish
+	for {
+		if epSliceTestVars.sliceReflector.HasSynced() {
+			break
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	statsAfter := *epSliceTestVars.sliceReflector.GetStats()
+
+	gomega.Expect(epSliceTestVars.mockKvBroker.ds).Should(gomega.HaveLen(2))
+	gomega.Expect(statsBefore.Adds + 1).Should(gomega.BeNumerically("==", statsAfter.Adds))
+	gomega.Expect(statsBefore.Updates + 1).Should(gomega.BeNumerically("==", statsAfter.Updates))
+	gomega.Expect(statsBefore.Deletes + 1).Should(gomega.BeNumerically("==", statsAfter.Deletes))
+
+	epSliceTestVars.mockKvBroker.ClearDs()
+	t.Run("endpointSliceToProto", testEndpointSliceToProto)
+
+	epSliceTestVars.mockKvBroker.ClearDs()
+	t.Run("addUpdateDeleteEndpointSlice", testAddUpdateDeleteEndpointSlice)
+
+	epSliceTestVars.mockKvBroker.ClearDs()
+	t.Run("terminatingEndpoint", testTerminatingEndpoint)
+
+	MockK8sCache.ListFunc = nil
+}
+
+func testEndpointSliceToProto(t *testing.T) {
+	reflector := epSliceTestVars.sliceReflector
+
+	nginx := reflector.endpointSliceToProto(&epSliceTestVars.sliceTestData[0])
+	gomega.Expect(nginx.Name).To(gomega.Equal("my-nginx-abcde"))
+	gomega.Expect(nginx.Namespace).To(gomega.Equal("default"))
+	gomega.Expect(nginx.ServiceName).To(gomega.Equal("my-nginx"))
+	gomega.Expect(nginx.AddressType).To(gomega.Equal("IPv4"))
+	gomega.Expect(nginx.Ports).To(gomega.ConsistOf(
+		&endpointslice.EndpointPort{Name: "http", Protocol: "TCP", Port: 80}))
+	gomega.Expect(nginx.Endpoints).To(gomega.HaveLen(3))
+
+	// ready
+	gomega.Expect(nginx.Endpoints[0].Addresses).To(gomega.Equal([]string{"10.1.1.3"}))
+	gomega.Expect(nginx.Endpoints[0].NodeName).To(gomega.Equal("node1"))
+	gomega.Expect(nginx.Endpoints[0].TargetRef.Name).To(gomega.Equal("my-nginx-1"))
+	gomega.Expect(nginx.Endpoints[0].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{Ready: true, Serving: true}))
+
+	// not ready
+	gomega.Expect(nginx.Endpoints[1].NodeName).To(gomega.Equal("node2"))
+	gomega.Expect(nginx.Endpoints[1].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{}))
+
+	// terminating
+	gomega.Expect(nginx.Endpoints[2].NodeName).To(gomega.Equal("node2"))
+	gomega.Expect(nginx.Endpoints[2].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{Serving: true, Terminating: true}))
+
+	// unknown state is interpreted as ready
+	httpd := reflector.endpointSliceToProto(&epSliceTestVars.sliceTestData[1])
+	gomega.Expect(httpd.ServiceName).To(gomega.Equal("my-httpd"))
+	gomega.Expect(httpd.Ports).To(gomega.ConsistOf(
+		&endpointslice.EndpointPort{Protocol: "TCP", Port: 80}))
+	gomega.Expect(httpd.Endpoints).To(gomega.HaveLen(1))
+	gomega.Expect(httpd.Endpoints[0].TargetRef).To(gomega.BeNil())
+	gomega.Expect(httpd.Endpoints[0].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{Ready: true, Serving: true}))
+}
+
+func testAddUpdateDeleteEndpointSlice(t *testing.T) {
+	reflector := epSliceTestVars.sliceReflector
+	k8sSlice := epSliceTestVars.sliceTestData[1]
+	key := endpointslice.Key(k8sSlice.GetName(), k8sSlice.GetNamespace())
+
+	// Take a snapshot of counters
+	adds := reflector.GetStats().Adds
+	argErrs := reflector.GetStats().ArgErrors
+
+	// Test add with wrong argument type
+	epSliceTestVars.k8sListWatch.Add(k8sSlice)
+
+	gomega.Expect(argErrs + 1).To(gomega.Equal(reflector.GetStats().ArgErrors))
+	gomega.Expect(adds).To(gomega.Equal(reflector.GetStats().Adds))
+
+	// Test add where everything should be good
+	epSliceTestVars.k8sListWatch.Add(&k8sSlice)
+
+	protoSlice := &endpointslice.EndpointSlice{}
+	found, _, err := epSliceTestVars.mockKvBroker.GetValue(key, protoSlice)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(adds + 1).To(gomega.Equal(reflector.GetStats().Adds))
+	gomega.Expect(protoSlice.Endpoints).To(gomega.HaveLen(1))
+
+	// Test update
+	updates := reflector.GetStats().Updates
+	newK8sSlice := *k8sSlice.DeepCopy()
+	notReady := false
+	newK8sSlice.Endpoints[0].Conditions.Ready = &notReady
+	epSliceTestVars.k8sListWatch.Update(&k8sSlice, &newK8sSlice)
+
+	gomega.Expect(updates + 1).To(gomega.Equal(reflector.GetStats().Updates))
+	protoSlice = &endpointslice.EndpointSlice{}
+	found, _, err = epSliceTestVars.mockKvBroker.GetValue(key, protoSlice)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(protoSlice.Endpoints[0].Conditions.Ready).To(gomega.BeFalse())
+
+	// Test delete
+	dels := reflector.GetStats().Deletes
+	epSliceTestVars.k8sListWatch.Delete(&newK8sSlice)
+
+	gomega.Expect(dels + 1).To(gomega.Equal(reflector.GetStats().Deletes))
+	found, _, err = epSliceTestVars.mockKvBroker.GetValue(key, &endpointslice.EndpointSlice{})
+	gomega.Expect(found).To(gomega.BeFalse())
+	gomega.Expect(err).To(gomega.BeNil())
+}
+
+func testTerminatingEndpoint(t *testing.T) {
+	reflector := epSliceTestVars.sliceReflector
+	k8sSlice := epSliceTestVars.sliceTestData[0].DeepCopy()
+	k8sSlice.Endpoints = k8sSlice.Endpoints[:1]
+	key := endpointslice.Key(k8sSlice.GetName(), k8sSlice.GetNamespace())
+
+	epSliceTestVars.k8sListWatch.Add(k8sSlice)
+	protoSlice := &endpointslice.EndpointSlice{}
+	found, _, _ := epSliceTestVars.mockKvBroker.GetValue(key, protoSlice)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(protoSlice.Endpoints[0].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{Ready: true, Serving: true}))
+
+	// The pod of the endpoint was deleted (the pod update is not relevant for the reflector),
+	// the slice update with ready=false arrives after it. The endpoint keeps serving
+	// while terminating.
+	updates := reflector.GetStats().Updates
+	terminatingSlice := k8sSlice.DeepCopy()
+	notReady := false
+	serving := true
+	terminating := true
+	terminatingSlice.Endpoints[0].Conditions = discovery.EndpointConditions{
+		Ready: &notReady, Serving: &serving, Terminating: &terminating}
+	epSliceTestVars.k8sListWatch.Update(k8sSlice, terminatingSlice)
+
+	gomega.Expect(updates + 1).To(gomega.Equal(reflector.GetStats().Updates))
+	protoSlice = &endpointslice.EndpointSlice{}
+	found, _, _ = epSliceTestVars.mockKvBroker.GetValue(key, protoSlice)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(protoSlice.Endpoints[0].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{Serving: true, Terminating: true}))
+
+	// The terminating pod stops serving.
+	notServingSlice := terminatingSlice.DeepCopy()
+	notServingSlice.Endpoints[0].Conditions.Serving = &notReady
+	epSliceTestVars.k8sListWatch.Update(terminatingSlice, notServingSlice)
+
+	gomega.Expect(updates + 2).To(gomega.Equal(reflector.GetStats().Updates))
+	protoSlice = &endpointslice.EndpointSlice{}
+	found, _, _ = epSliceTestVars.mockKvBroker.GetValue(key, protoSlice)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(protoSlice.Endpoints[0].Conditions).To(gomega.Equal(
+		&endpointslice.EndpointConditions{Terminating: true}))
+
+	// The pod is gone.
+	goneSlice := notServingSlice.DeepCopy()
+	goneSlice.Endpoints = nil
+	epSliceTestVars.k8sListWatch.Update(notServingSlice, goneSlice)
+
+	protoSlice = &endpointslice.EndpointSlice{}
+	found, _, _ = epSliceTestVars.mockKvBroker.GetValue(key, protoSlice)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(protoSlice.Endpoints).To(gomega.BeEmpty())
+}
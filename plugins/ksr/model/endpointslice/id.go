@@ -0,0 +1,34 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointslice
+
+// ID used to uniquely represent a K8s EndpointSlice.
+type ID struct {
+	Name      string
+	Namespace string
+}
+
+// GetID returns ID of an endpoint slice.
+func GetID(endpointSlice *EndpointSlice) ID {
+	if endpointSlice != nil {
+		return ID{Name: endpointSlice.Name, Namespace: endpointSlice.Namespace}
+	}
+	return ID{}
+}
+
+// String returns a string representation of an endpoint slice ID.
+func (id ID) String() string {
+	return id.Namespace + "/" + id.Name
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointslice
+
+import (
+	"github.com/americanbinary/vpp/plugins/ksr/model/ksrkey"
+)
+
+const (
+	// EndpointSliceKeyword defines the keyword identifying EndpointSlice data.
+	EndpointSliceKeyword = "endpointslice"
+)
+
+// KeyPrefix returns the key prefix identifying all K8s endpoint slices in the
+// data store.
+func KeyPrefix() string {
+	return ksrkey.KeyPrefix(EndpointSliceKeyword)
+}
+
+// ParseEndpointSliceFromKey parses endpoint slice and namespace ids from
+// the associated data-store key.
+func ParseEndpointSliceFromKey(key string) (endpointSlice string, namespace string, err error) {
+	return ksrkey.ParseNameFromKey(EndpointSliceKeyword, key)
+}
+
+// Key returns the key under which the given K8s endpoint slice is stored
+// in the data-store.
+func Key(name string, namespace string) string {
+	return ksrkey.Key(EndpointSliceKeyword, name, namespace)
+}
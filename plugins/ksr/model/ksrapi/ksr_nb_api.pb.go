@@ -150,7 +150,11 @@ type Stats struct {
 	// Statistics for the Node Reflector
 	NodeStats *KsrStats `protobuf:"bytes,6,opt,name=nodeStats,proto3" json:"nodeStats,omitempty"`
 	// Statistics for the SfcPod Reflector
-	SfcPodStats          *KsrStats `protobuf:"bytes,7,opt,name=sfcPodStats,proto3" json:"sfcPodStats,omitempty"`
+	SfcPodStats *KsrStats `protobuf:"bytes,7,opt,name=sfcPodStats,proto3" json:"sfcPodStats,omitempty"`
+	// Statistics for the Cluster Network Policy Reflector
+	ClusterPolicyStats *KsrStats `protobuf:"bytes,8,opt,name=clusterPolicyStats,proto3" json:"clusterPolicyStats,omitempty"`
+	// Statistics for the EndpointSlice Reflector
+	EndpointSliceStats   *KsrStats `protobuf:"bytes,9,opt,name=endpointSliceStats,proto3" json:"endpointSliceStats,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
@@ -230,6 +234,20 @@ func (m *Stats) GetSfcPodStats() *KsrStats {
 	return nil
 }
 
+func (m *Stats) GetClusterPolicyStats() *KsrStats {
+	if m != nil {
+		return m.ClusterPolicyStats
+	}
+	return nil
+}
+
+func (m *Stats) GetEndpointSliceStats() *KsrStats {
+	if m != nil {
+		return m.EndpointSliceStats
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*KsrStats)(nil), "ksrapi.KsrStats")
 	proto.RegisterType((*Stats)(nil), "ksrapi.Stats")
@@ -238,24 +256,26 @@ func init() {
 func init() { proto.RegisterFile("ksr_nb_api.proto", fileDescriptor_53ba764e9d53bcd7) }
 
 var fileDescriptor_53ba764e9d53bcd7 = []byte{
-	// 303 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xbb, 0x6b, 0xf3, 0x30,
-	0x14, 0xc5, 0x89, 0xe3, 0xe7, 0xcd, 0xc7, 0x47, 0xd0, 0xe4, 0xa1, 0x43, 0xc9, 0xd4, 0xa1, 0x78,
-	0x48, 0x3b, 0x74, 0x0d, 0xa4, 0x53, 0x97, 0xe2, 0x92, 0x39, 0x28, 0x96, 0x5a, 0x4c, 0x5c, 0x49,
-	0xe8, 0x9a, 0x42, 0xd6, 0xfe, 0xd3, 0x5d, 0x8b, 0x5e, 0x4e, 0x52, 0xd0, 0xe6, 0x7b, 0x7e, 0xe7,
-	0x88, 0x7b, 0x0f, 0x86, 0xe5, 0x11, 0xf5, 0x5e, 0x1c, 0xf6, 0x54, 0xf5, 0x8d, 0xd2, 0x72, 0x94,
-	0x24, 0x3f, 0xa2, 0xa6, 0xaa, 0x5f, 0x7d, 0x27, 0x50, 0xbe, 0xa0, 0x7e, 0x1b, 0xe9, 0x88, 0x84,
-	0x40, 0xba, 0x61, 0x0c, 0xeb, 0xd9, 0xed, 0xec, 0x2e, 0x6d, 0xed, 0x37, 0xa9, 0xa1, 0xd8, 0x29,
-	0x46, 0x47, 0x8e, 0x75, 0x62, 0xe5, 0x30, 0x1a, 0xb2, 0xe5, 0x03, 0x37, 0x64, 0xee, 0x88, 0x1f,
-	0x0d, 0x69, 0x39, 0x9e, 0x44, 0x87, 0x75, 0xea, 0x88, 0x1f, 0xc9, 0x0d, 0x54, 0x1b, 0xc6, 0x9e,
-	0xb5, 0x96, 0x1a, 0xeb, 0xcc, 0xb2, 0xb3, 0x60, 0xe8, 0x4e, 0x05, 0x9a, 0x3b, 0x3a, 0x09, 0x86,
-	0x6e, 0xf9, 0xe0, 0x69, 0xe1, 0xe8, 0x24, 0xd8, 0x97, 0xf5, 0x87, 0xa7, 0xa5, 0x7f, 0x39, 0x08,
-	0x86, 0xb6, 0x1c, 0x3d, 0xad, 0x1c, 0x9d, 0x84, 0xd5, 0x4f, 0x02, 0x99, 0x6b, 0xe0, 0x09, 0xfe,
-	0x0b, 0xfa, 0xc9, 0x51, 0xd1, 0x8e, 0x5b, 0xc5, 0x76, 0xb1, 0x58, 0x2f, 0x1b, 0xd7, 0x57, 0x13,
-	0xba, 0x6a, 0xff, 0xf8, 0xc8, 0x3d, 0x94, 0x4a, 0x32, 0x97, 0x49, 0x22, 0x99, 0xc9, 0x41, 0xd6,
-	0xb0, 0x50, 0x72, 0xe8, 0xbb, 0x93, 0x0b, 0xcc, 0x23, 0x81, 0x4b, 0x93, 0xd9, 0x8d, 0x0b, 0xa6,
-	0x64, 0x2f, 0x46, 0x74, 0xb1, 0x34, 0xb6, 0xdb, 0xb5, 0x8f, 0x3c, 0xc2, 0x3f, 0xe4, 0xfa, 0xab,
-	0x0f, 0x37, 0x65, 0x91, 0xdc, 0x95, 0x8b, 0x34, 0x50, 0x09, 0xc9, 0x7c, 0x24, 0x8f, 0x44, 0xce,
-	0x16, 0x73, 0x13, 0xbe, 0x77, 0xaf, 0xa1, 0x84, 0x22, 0x76, 0xd3, 0x85, 0xe9, 0x90, 0xdb, 0xbf,
-	0xf1, 0xe1, 0x37, 0x00, 0x00, 0xff, 0xff, 0x74, 0xed, 0x01, 0xc5, 0xa1, 0x02, 0x00, 0x00,
+	// 330 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0x3f, 0x4f, 0xc3, 0x30,
+	0x10, 0xc5, 0xd5, 0x36, 0x4d, 0x93, 0x2b, 0x42, 0x95, 0xa7, 0x0c, 0x0c, 0xa8, 0x13, 0x03, 0xca,
+	0x50, 0x18, 0x18, 0xa9, 0x54, 0x26, 0x96, 0x2a, 0x55, 0xe7, 0x2a, 0x8d, 0x4d, 0x15, 0x35, 0xc4,
+	0x96, 0xcf, 0x20, 0x75, 0xe5, 0x6b, 0xb3, 0x20, 0xff, 0x4b, 0x53, 0xc0, 0x5b, 0xee, 0x7e, 0xef,
+	0x9d, 0xfc, 0x9e, 0x14, 0x98, 0x1d, 0x51, 0xee, 0xda, 0xfd, 0xae, 0x14, 0x75, 0x2e, 0x24, 0x57,
+	0x9c, 0xc4, 0x47, 0x94, 0xa5, 0xa8, 0xe7, 0x5f, 0x43, 0x48, 0x5e, 0x51, 0x6e, 0x54, 0xa9, 0x90,
+	0x10, 0x88, 0x96, 0x94, 0x62, 0x36, 0xb8, 0x1d, 0xdc, 0x45, 0x85, 0xf9, 0x26, 0x19, 0x4c, 0xb6,
+	0x82, 0x96, 0x8a, 0x61, 0x36, 0x34, 0x6b, 0x3f, 0x6a, 0xb2, 0x62, 0x0d, 0xd3, 0x64, 0x64, 0x89,
+	0x1b, 0x35, 0x29, 0x18, 0x9e, 0xda, 0x0a, 0xb3, 0xc8, 0x12, 0x37, 0x92, 0x1b, 0x48, 0x97, 0x94,
+	0xbe, 0x48, 0xc9, 0x25, 0x66, 0x63, 0xc3, 0xce, 0x0b, 0x4d, 0xb7, 0xc2, 0xd3, 0xd8, 0xd2, 0xad,
+	0xe8, 0xd1, 0x15, 0x6b, 0x1c, 0x9d, 0x58, 0xda, 0x2d, 0xcc, 0x65, 0x79, 0x70, 0x34, 0x71, 0x97,
+	0xe5, 0xe1, 0x4c, 0x0b, 0x86, 0x8e, 0xa6, 0x96, 0x76, 0x8b, 0xf9, 0xf7, 0x08, 0xc6, 0xb6, 0x81,
+	0x27, 0xb8, 0x6e, 0xcb, 0x77, 0x86, 0xa2, 0xac, 0x98, 0xd9, 0x98, 0x2e, 0xa6, 0x8b, 0x59, 0x6e,
+	0xfb, 0xca, 0x7d, 0x57, 0xc5, 0x2f, 0x1d, 0xb9, 0x87, 0x44, 0x70, 0x6a, 0x3d, 0xc3, 0x80, 0xa7,
+	0x53, 0x90, 0x05, 0x4c, 0x05, 0x6f, 0xea, 0xea, 0x64, 0x0d, 0xa3, 0x80, 0xa1, 0x2f, 0xd2, 0x6f,
+	0x63, 0x2d, 0x15, 0xbc, 0x6e, 0x15, 0x5a, 0x5b, 0x14, 0x7a, 0xdb, 0xa5, 0x8e, 0x3c, 0xc2, 0x15,
+	0x32, 0xf9, 0x59, 0xfb, 0x4c, 0xe3, 0x80, 0xef, 0x42, 0x45, 0x72, 0x48, 0x5b, 0x4e, 0x9d, 0x25,
+	0x0e, 0x58, 0xce, 0x12, 0x9d, 0x09, 0xdf, 0xaa, 0xb5, 0x2f, 0x61, 0x12, 0xca, 0xd4, 0x13, 0x91,
+	0x67, 0x20, 0x55, 0xf3, 0x81, 0x8a, 0xc9, 0x75, 0xaf, 0x8e, 0x24, 0x60, 0xfd, 0x47, 0xab, 0x2f,
+	0xf8, 0xb4, 0x9b, 0xa6, 0x4b, 0x98, 0x86, 0x2e, 0xfc, 0xd5, 0xee, 0x63, 0xf3, 0x47, 0x3c, 0xfc,
+	0x0c, 0x00, 0xf3, 0xf0, 0xcf, 0x6f, 0x25, 0x03, 0x00, 0x00,
 }
@@ -29,10 +29,13 @@ type Namespace struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// A list of labels attached to this namespace.
 	// +optional
-	Label                []*Namespace_Label `protobuf:"bytes,3,rep,name=label,proto3" json:"label,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Label []*Namespace_Label `protobuf:"bytes,3,rep,name=label,proto3" json:"label,omitempty"`
+	// Annotations is an unstructured key value map stored with the namespace.
+	// +optional
+	Annotations          map[string]string `protobuf:"bytes,4,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Namespace) Reset()         { *m = Namespace{} }
@@ -74,6 +77,13 @@ func (m *Namespace) GetLabel() []*Namespace_Label {
 	return nil
 }
 
+func (m *Namespace) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
 // Label is a key/value pair attached to an object (namespace in this case).
 // Labels are used to organize and to select subsets of objects.
 type Namespace_Label struct {
@@ -125,20 +135,23 @@ func (m *Namespace_Label) GetValue() string {
 
 func init() {
 	proto.RegisterType((*Namespace)(nil), "namespace.Namespace")
+	proto.RegisterMapType((map[string]string)(nil), "namespace.Namespace.AnnotationsEntry")
 	proto.RegisterType((*Namespace_Label)(nil), "namespace.Namespace.Label")
 }
 
 func init() { proto.RegisterFile("namespace.proto", fileDescriptor_ecb1e126f615f5dd) }
 
 var fileDescriptor_ecb1e126f615f5dd = []byte{
-	// 133 bytes of a gzipped FileDescriptorProto
+	// 176 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcf, 0x4b, 0xcc, 0x4d,
 	0x2d, 0x2e, 0x48, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x84, 0x0b, 0x28,
-	0x35, 0x31, 0x72, 0x71, 0xfa, 0xc1, 0x78, 0x42, 0x42, 0x5c, 0x2c, 0x20, 0x29, 0x09, 0x46, 0x05,
+	0x75, 0x33, 0x71, 0x71, 0xfa, 0xc1, 0x78, 0x42, 0x42, 0x5c, 0x2c, 0x20, 0x29, 0x09, 0x46, 0x05,
 	0x46, 0x0d, 0xce, 0x20, 0x30, 0x5b, 0xc8, 0x80, 0x8b, 0x35, 0x27, 0x31, 0x29, 0x35, 0x47, 0x82,
 	0x59, 0x81, 0x59, 0x83, 0xdb, 0x48, 0x4a, 0x0f, 0x61, 0x1a, 0x5c, 0xa3, 0x9e, 0x0f, 0x48, 0x45,
-	0x10, 0x44, 0xa1, 0x94, 0x3e, 0x17, 0x2b, 0x98, 0x2f, 0x24, 0xc0, 0xc5, 0x9c, 0x9d, 0x5a, 0x09,
-	0x35, 0x0d, 0xc4, 0x14, 0x12, 0xe1, 0x62, 0x2d, 0x4b, 0xcc, 0x29, 0x4d, 0x95, 0x60, 0x02, 0x8b,
-	0x41, 0x38, 0x49, 0x6c, 0x60, 0x67, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xcb, 0x36, 0x65,
-	0x3c, 0xa9, 0x00, 0x00, 0x00,
+	0x10, 0x44, 0xa1, 0x90, 0x3b, 0x17, 0x77, 0x62, 0x5e, 0x5e, 0x7e, 0x49, 0x62, 0x49, 0x66, 0x7e,
+	0x5e, 0xb1, 0x04, 0x0b, 0x58, 0x9f, 0x2a, 0x56, 0x7d, 0x8e, 0x08, 0x75, 0xae, 0x79, 0x25, 0x45,
+	0x95, 0x41, 0xc8, 0x3a, 0xa5, 0xf4, 0xb9, 0x58, 0xc1, 0x06, 0x0b, 0x09, 0x70, 0x31, 0x67, 0xa7,
+	0x56, 0x42, 0x9d, 0x05, 0x62, 0x0a, 0x89, 0x70, 0xb1, 0x96, 0x25, 0xe6, 0x94, 0xa6, 0x4a, 0x30,
+	0x81, 0xc5, 0x20, 0x1c, 0x29, 0x3b, 0x2e, 0x01, 0x74, 0x13, 0x89, 0xd5, 0x6b, 0xc5, 0x64, 0xc1,
+	0x98, 0xc4, 0x06, 0x0e, 0x1f, 0x63, 0xc0, 0x00, 0x4b, 0x36, 0xd0, 0x59, 0x32, 0x01, 0x00, 0x00,
 }
@@ -15,6 +15,9 @@
 package policy
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/americanbinary/vpp/plugins/ksr/model/ksrkey"
 )
 
@@ -40,3 +43,33 @@ func ParsePolicyFromKey(key string) (policy string, namespace string, err error)
 func Key(name string, namespace string) string {
 	return ksrkey.Key(PolicyKeyword, name, namespace)
 }
+
+const (
+	// ClusterPolicyKeyword defines the keyword identifying cluster-wide network
+	// policy data.
+	ClusterPolicyKeyword = "clusterpolicy"
+)
+
+// ClusterPolicyKeyPrefix returns the key prefix identifying all cluster-wide
+// network policies in the data store.
+func ClusterPolicyKeyPrefix() string {
+	return ksrkey.KeyPrefix(ClusterPolicyKeyword)
+}
+
+// ParseClusterPolicyFromKey parses name of a cluster-wide network policy
+// from the associated data-store key.
+func ParseClusterPolicyFromKey(key string) (policy string, err error) {
+	if strings.HasPrefix(key, ClusterPolicyKeyPrefix()) {
+		policy = strings.TrimPrefix(key, ClusterPolicyKeyPrefix())
+		if policy != "" && !strings.Contains(policy, "/") {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("invalid format of the key %s", key)
+}
+
+// ClusterPolicyKey returns the key under which a given cluster-wide network
+// policy is stored in the data store.
+func ClusterPolicyKey(name string) string {
+	return ClusterPolicyKeyPrefix() + name
+}
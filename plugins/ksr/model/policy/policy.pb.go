@@ -1,7 +1,8 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // source: policy.proto
 
-// Package policy defines data model for Kubernetes Network Policy.
+// Package policy defines data model for Kubernetes Network Policy
+// and Contiv cluster-wide network policy.
 
 package policy
 
@@ -97,24 +98,34 @@ func (Policy_LabelSelector_LabelExpression_Operator) EnumDescriptor() ([]byte, [
 	return fileDescriptor_ac3b897852294d6a, []int{0, 1, 0, 0}
 }
 
-// The protocol (TCP or UDP) which traffic must match.
+// The protocol (TCP, UDP, SCTP or other IP protocol given by number) which
+// traffic must match.
 // If not specified, this field defaults to TCP.
 // +optional
 type Policy_Port_Protocol int32
 
 const (
-	Policy_Port_TCP Policy_Port_Protocol = 0
-	Policy_Port_UDP Policy_Port_Protocol = 1
+	Policy_Port_TCP  Policy_Port_Protocol = 0
+	Policy_Port_UDP  Policy_Port_Protocol = 1
+	Policy_Port_SCTP Policy_Port_Protocol = 2
+	// IP protocol given by protocol_number (ports are not applicable).
+	// Not produced for Kubernetes network policies, which allow only
+	// TCP, UDP and SCTP.
+	Policy_Port_OTHER Policy_Port_Protocol = 3
 )
 
 var Policy_Port_Protocol_name = map[int32]string{
 	0: "TCP",
 	1: "UDP",
+	2: "SCTP",
+	3: "OTHER",
 }
 
 var Policy_Port_Protocol_value = map[string]int32{
-	"TCP": 0,
-	"UDP": 1,
+	"TCP":   0,
+	"UDP":   1,
+	"SCTP":  2,
+	"OTHER": 3,
 }
 
 func (x Policy_Port_Protocol) String() string {
@@ -151,6 +162,39 @@ func (Policy_Port_PortNameOrNumber_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_ac3b897852294d6a, []int{0, 2, 0, 0}
 }
 
+// Action to perform with the traffic matched by a rule of a cluster policy.
+type ClusterPolicy_Action int32
+
+const (
+	// Allow the traffic, K8s network policies are not evaluated.
+	ClusterPolicy_ALLOW ClusterPolicy_Action = 0
+	// Block the traffic, K8s network policies are not evaluated.
+	ClusterPolicy_DENY ClusterPolicy_Action = 1
+	// Skip the remaining cluster policies of the same tier and continue
+	// with the next tier (or with K8s network policies after the last tier).
+	ClusterPolicy_PASS ClusterPolicy_Action = 2
+)
+
+var ClusterPolicy_Action_name = map[int32]string{
+	0: "ALLOW",
+	1: "DENY",
+	2: "PASS",
+}
+
+var ClusterPolicy_Action_value = map[string]int32{
+	"ALLOW": 0,
+	"DENY":  1,
+	"PASS":  2,
+}
+
+func (x ClusterPolicy_Action) String() string {
+	return proto.EnumName(ClusterPolicy_Action_name, int32(x))
+}
+
+func (ClusterPolicy_Action) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ac3b897852294d6a, []int{1, 0}
+}
+
 // Policy describes what network traffic is allowed for a set of Pods.
 type Policy struct {
 	// Name of the policy unique within the namespace.
@@ -443,6 +487,8 @@ func (m *Policy_LabelSelector_LabelExpression) GetValue() []string {
 // A port selector.
 type Policy_Port struct {
 	Protocol Policy_Port_Protocol `protobuf:"varint,3,opt,name=protocol,proto3,enum=policy.Policy_Port_Protocol" json:"protocol,omitempty"`
+	// IP protocol number (0-255), used only with protocol OTHER.
+	ProtocolNumber uint32 `protobuf:"varint,4,opt,name=protocol_number,json=protocolNumber,proto3" json:"protocol_number,omitempty"`
 	// If specified, the port on the given protocol.
 	// This can either be a numerical or named port on a pod.
 	// If this field is not provided, the rule matches all port names and
@@ -450,10 +496,14 @@ type Policy_Port struct {
 	// If present, only traffic on the specified protocol AND port
 	// will be matched.
 	// +optional
-	Port                 *Policy_Port_PortNameOrNumber `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
-	XXX_unrecognized     []byte                        `json:"-"`
-	XXX_sizecache        int32                         `json:"-"`
+	Port *Policy_Port_PortNameOrNumber `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	// If set, the rule matches the range of ports between the numerical port
+	// and end_port (inclusive). Not applicable to named ports.
+	// +optional
+	EndPort              int32    `protobuf:"varint,5,opt,name=end_port,json=endPort,proto3" json:"end_port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Policy_Port) Reset()         { *m = Policy_Port{} }
@@ -488,6 +538,13 @@ func (m *Policy_Port) GetProtocol() Policy_Port_Protocol {
 	return Policy_Port_TCP
 }
 
+func (m *Policy_Port) GetProtocolNumber() uint32 {
+	if m != nil {
+		return m.ProtocolNumber
+	}
+	return 0
+}
+
 func (m *Policy_Port) GetPort() *Policy_Port_PortNameOrNumber {
 	if m != nil {
 		return m.Port
@@ -495,6 +552,13 @@ func (m *Policy_Port) GetPort() *Policy_Port_PortNameOrNumber {
 	return nil
 }
 
+func (m *Policy_Port) GetEndPort() int32 {
+	if m != nil {
+		return m.EndPort
+	}
+	return 0
+}
+
 // Numerical or named port.
 type Policy_Port_PortNameOrNumber struct {
 	Type Policy_Port_PortNameOrNumber_Type `protobuf:"varint,1,opt,name=type,proto3,enum=policy.Policy_Port_PortNameOrNumber_Type" json:"type,omitempty"`
@@ -800,11 +864,249 @@ func (m *Policy_EgressRule) GetTo() []*Policy_Peer {
 	return nil
 }
 
+// ClusterPolicy is a cluster-wide network policy defined by the cluster
+// administrator. Cluster policies are evaluated before K8s network policies
+// and cannot be overridden by them.
+type ClusterPolicy struct {
+	// Name of the policy unique within the cluster.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Tier of the policy. Tiers are evaluated in the ascending order.
+	Tier uint32 `protobuf:"varint,2,opt,name=tier,proto3" json:"tier,omitempty"`
+	// Priority of the policy within the tier. Policies of the same tier are
+	// evaluated in the ascending order of priorities (and names for equal
+	// priorities).
+	Priority uint32 `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Namespaces with pods to which this policy applies.
+	// Null or empty selector matches all namespaces.
+	// +optional
+	Namespaces *Policy_LabelSelector `protobuf:"bytes,4,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// Pods (from the selected namespaces) to which this policy applies.
+	// Null or empty selector matches all pods.
+	// +optional
+	Pods *Policy_LabelSelector `protobuf:"bytes,5,opt,name=pods,proto3" json:"pods,omitempty"`
+	// List of ingress rules applied to the selected pods, evaluated in the order
+	// as listed.
+	// +optional
+	IngressRule []*ClusterPolicy_Rule `protobuf:"bytes,6,rep,name=ingress_rule,json=ingressRule,proto3" json:"ingress_rule,omitempty"`
+	// List of egress rules applied to the selected pods, evaluated in the order
+	// as listed.
+	// +optional
+	EgressRule           []*ClusterPolicy_Rule `protobuf:"bytes,7,rep,name=egress_rule,json=egressRule,proto3" json:"egress_rule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ClusterPolicy) Reset()         { *m = ClusterPolicy{} }
+func (m *ClusterPolicy) String() string { return proto.CompactTextString(m) }
+func (*ClusterPolicy) ProtoMessage()    {}
+func (*ClusterPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac3b897852294d6a, []int{1}
+}
+
+func (m *ClusterPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterPolicy.Unmarshal(m, b)
+}
+func (m *ClusterPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterPolicy.Marshal(b, m, deterministic)
+}
+func (m *ClusterPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterPolicy.Merge(m, src)
+}
+func (m *ClusterPolicy) XXX_Size() int {
+	return xxx_messageInfo_ClusterPolicy.Size(m)
+}
+func (m *ClusterPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterPolicy proto.InternalMessageInfo
+
+func (m *ClusterPolicy) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ClusterPolicy) GetTier() uint32 {
+	if m != nil {
+		return m.Tier
+	}
+	return 0
+}
+
+func (m *ClusterPolicy) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *ClusterPolicy) GetNamespaces() *Policy_LabelSelector {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *ClusterPolicy) GetPods() *Policy_LabelSelector {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+func (m *ClusterPolicy) GetIngressRule() []*ClusterPolicy_Rule {
+	if m != nil {
+		return m.IngressRule
+	}
+	return nil
+}
+
+func (m *ClusterPolicy) GetEgressRule() []*ClusterPolicy_Rule {
+	if m != nil {
+		return m.EgressRule
+	}
+	return nil
+}
+
+// Peer selects pods and/or IP addresses.
+// Namespace and pod selectors are ANDed, i.e. a peer with both selectors set
+// selects pods matching the pod selector from namespaces matching the namespace
+// selector. A peer with only pod selector selects pods from all namespaces.
+type ClusterPolicy_Peer struct {
+	// Namespaces with the selected pods.
+	// +optional
+	Namespaces *Policy_LabelSelector `protobuf:"bytes,1,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// Pods from the selected namespaces.
+	// +optional
+	Pods *Policy_LabelSelector `protobuf:"bytes,2,opt,name=pods,proto3" json:"pods,omitempty"`
+	// IPBlock selects a CIDR (with possible exceptions).
+	// If set, namespace and pod selectors must be unset.
+	// +optional
+	IpBlock              *Policy_Peer_IPBlock `protobuf:"bytes,3,opt,name=ip_block,json=ipBlock,proto3" json:"ip_block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ClusterPolicy_Peer) Reset()         { *m = ClusterPolicy_Peer{} }
+func (m *ClusterPolicy_Peer) String() string { return proto.CompactTextString(m) }
+func (*ClusterPolicy_Peer) ProtoMessage()    {}
+func (*ClusterPolicy_Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac3b897852294d6a, []int{1, 0}
+}
+
+func (m *ClusterPolicy_Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterPolicy_Peer.Unmarshal(m, b)
+}
+func (m *ClusterPolicy_Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterPolicy_Peer.Marshal(b, m, deterministic)
+}
+func (m *ClusterPolicy_Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterPolicy_Peer.Merge(m, src)
+}
+func (m *ClusterPolicy_Peer) XXX_Size() int {
+	return xxx_messageInfo_ClusterPolicy_Peer.Size(m)
+}
+func (m *ClusterPolicy_Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterPolicy_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterPolicy_Peer proto.InternalMessageInfo
+
+func (m *ClusterPolicy_Peer) GetNamespaces() *Policy_LabelSelector {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *ClusterPolicy_Peer) GetPods() *Policy_LabelSelector {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+func (m *ClusterPolicy_Peer) GetIpBlock() *Policy_Peer_IPBlock {
+	if m != nil {
+		return m.IpBlock
+	}
+	return nil
+}
+
+// Rule matches traffic if and only if the traffic matches both port-s
+// AND peer-s.
+type ClusterPolicy_Rule struct {
+	// Action to perform with the matched traffic.
+	Action ClusterPolicy_Action `protobuf:"varint,1,opt,name=action,proto3,enum=policy.ClusterPolicy_Action" json:"action,omitempty"`
+	// List of destination ports. Named ports are not supported.
+	// If the array is empty or null, then this rule matches all ports.
+	// +optional
+	Port []*Policy_Port `protobuf:"bytes,2,rep,name=port,proto3" json:"port,omitempty"`
+	// List of sources (ingress) or destinations (egress).
+	// If the array is empty or null, then this rule matches all peers.
+	// +optional
+	Peer                 []*ClusterPolicy_Peer `protobuf:"bytes,3,rep,name=peer,proto3" json:"peer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ClusterPolicy_Rule) Reset()         { *m = ClusterPolicy_Rule{} }
+func (m *ClusterPolicy_Rule) String() string { return proto.CompactTextString(m) }
+func (*ClusterPolicy_Rule) ProtoMessage()    {}
+func (*ClusterPolicy_Rule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac3b897852294d6a, []int{1, 1}
+}
+
+func (m *ClusterPolicy_Rule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterPolicy_Rule.Unmarshal(m, b)
+}
+func (m *ClusterPolicy_Rule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterPolicy_Rule.Marshal(b, m, deterministic)
+}
+func (m *ClusterPolicy_Rule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterPolicy_Rule.Merge(m, src)
+}
+func (m *ClusterPolicy_Rule) XXX_Size() int {
+	return xxx_messageInfo_ClusterPolicy_Rule.Size(m)
+}
+func (m *ClusterPolicy_Rule) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterPolicy_Rule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterPolicy_Rule proto.InternalMessageInfo
+
+func (m *ClusterPolicy_Rule) GetAction() ClusterPolicy_Action {
+	if m != nil {
+		return m.Action
+	}
+	return ClusterPolicy_ALLOW
+}
+
+func (m *ClusterPolicy_Rule) GetPort() []*Policy_Port {
+	if m != nil {
+		return m.Port
+	}
+	return nil
+}
+
+func (m *ClusterPolicy_Rule) GetPeer() []*ClusterPolicy_Peer {
+	if m != nil {
+		return m.Peer
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("policy.Policy_PolicyType", Policy_PolicyType_name, Policy_PolicyType_value)
 	proto.RegisterEnum("policy.Policy_LabelSelector_LabelExpression_Operator", Policy_LabelSelector_LabelExpression_Operator_name, Policy_LabelSelector_LabelExpression_Operator_value)
 	proto.RegisterEnum("policy.Policy_Port_Protocol", Policy_Port_Protocol_name, Policy_Port_Protocol_value)
 	proto.RegisterEnum("policy.Policy_Port_PortNameOrNumber_Type", Policy_Port_PortNameOrNumber_Type_name, Policy_Port_PortNameOrNumber_Type_value)
+	proto.RegisterEnum("policy.ClusterPolicy_Action", ClusterPolicy_Action_name, ClusterPolicy_Action_value)
 	proto.RegisterType((*Policy)(nil), "policy.Policy")
 	proto.RegisterType((*Policy_Label)(nil), "policy.Policy.Label")
 	proto.RegisterType((*Policy_LabelSelector)(nil), "policy.Policy.LabelSelector")
@@ -815,54 +1117,70 @@ func init() {
 	proto.RegisterType((*Policy_Peer_IPBlock)(nil), "policy.Policy.Peer.IPBlock")
 	proto.RegisterType((*Policy_IngressRule)(nil), "policy.Policy.IngressRule")
 	proto.RegisterType((*Policy_EgressRule)(nil), "policy.Policy.EgressRule")
+	proto.RegisterType((*ClusterPolicy)(nil), "policy.ClusterPolicy")
+	proto.RegisterType((*ClusterPolicy_Peer)(nil), "policy.ClusterPolicy.Peer")
+	proto.RegisterType((*ClusterPolicy_Rule)(nil), "policy.ClusterPolicy.Rule")
 }
 
 func init() { proto.RegisterFile("policy.proto", fileDescriptor_ac3b897852294d6a) }
 
 var fileDescriptor_ac3b897852294d6a = []byte{
-	// 703 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdd, 0x6e, 0xd3, 0x4a,
-	0x10, 0x8e, 0x1d, 0xe7, 0xa7, 0xe3, 0x9e, 0xd6, 0xda, 0x56, 0x55, 0x8e, 0x4f, 0x2e, 0xa2, 0x1c,
-	0x24, 0x02, 0x42, 0x06, 0x05, 0x15, 0x55, 0x88, 0x22, 0xb5, 0xc4, 0xa0, 0xa0, 0xd6, 0x31, 0x9b,
-	0x54, 0x20, 0x6e, 0x2c, 0xc7, 0x5d, 0xc0, 0xaa, 0x93, 0xb5, 0x36, 0x0e, 0x6a, 0x9e, 0xa5, 0xef,
-	0xc1, 0x53, 0xf0, 0x1a, 0xdc, 0xf2, 0x0c, 0x68, 0xc7, 0x8e, 0x5d, 0x42, 0x54, 0x0a, 0x57, 0x9e,
-	0x99, 0xfd, 0xbe, 0xd9, 0x99, 0xcf, 0x33, 0x0b, 0x9b, 0x31, 0x8f, 0xc2, 0x60, 0x61, 0xc5, 0x82,
-	0x27, 0x9c, 0x54, 0x53, 0xaf, 0x7d, 0xb5, 0x09, 0x55, 0x17, 0x4d, 0x42, 0x40, 0x9b, 0xfa, 0x13,
-	0xd6, 0x50, 0x5a, 0x4a, 0x67, 0x83, 0xa2, 0x4d, 0x9a, 0xb0, 0x21, 0xbf, 0xb3, 0xd8, 0x0f, 0x58,
-	0x43, 0xc5, 0x83, 0x22, 0x40, 0xee, 0x43, 0x25, 0xf2, 0xc7, 0x2c, 0x6a, 0x94, 0x5b, 0xe5, 0x8e,
-	0xde, 0xdd, 0xb5, 0xb2, 0x2b, 0xd2, 0x84, 0xd6, 0x89, 0x3c, 0xa3, 0x29, 0x84, 0x3c, 0x02, 0x2d,
-	0xe6, 0xe7, 0xb3, 0x86, 0xd6, 0x52, 0x3a, 0x7a, 0xb7, 0xb9, 0x0e, 0x3a, 0x64, 0x11, 0x0b, 0x12,
-	0x2e, 0x28, 0x22, 0xc9, 0x53, 0xd0, 0x53, 0x90, 0x97, 0x2c, 0x62, 0xd6, 0xa8, 0xb4, 0x94, 0xce,
-	0x56, 0xf7, 0xdf, 0x15, 0x62, 0xfa, 0x19, 0x2d, 0x62, 0x46, 0x21, 0xce, 0x6d, 0x72, 0x08, 0x9b,
-	0xe1, 0xf4, 0xa3, 0x60, 0xb3, 0x99, 0x27, 0xe6, 0x11, 0x6b, 0x54, 0xb1, 0x40, 0x73, 0x85, 0xdc,
-	0x4f, 0x21, 0x74, 0x1e, 0x31, 0xaa, 0x87, 0x85, 0x23, 0xaf, 0x66, 0xd7, 0xd8, 0x35, 0x64, 0xaf,
-	0x5e, 0x6d, 0x17, 0x64, 0x60, 0xb9, 0x6d, 0x3e, 0x84, 0x0a, 0x76, 0x43, 0x0c, 0x28, 0x5f, 0xb0,
-	0x45, 0x26, 0xa7, 0x34, 0xc9, 0x2e, 0x54, 0x3e, 0xfb, 0xd1, 0x7c, 0xa9, 0x64, 0xea, 0x98, 0xdf,
-	0x55, 0xf8, 0xe7, 0xa7, 0xfe, 0xc9, 0x3e, 0xe8, 0x13, 0x3f, 0x09, 0x3e, 0x79, 0xa9, 0xba, 0xca,
-	0x0d, 0xea, 0x02, 0x02, 0xd3, 0x0b, 0xdf, 0x82, 0x91, 0xd2, 0xd8, 0x65, 0x2c, 0xcb, 0x09, 0xf9,
-	0xb4, 0xa1, 0x22, 0xf7, 0xc1, 0x4d, 0x72, 0xa7, 0x9e, 0x9d, 0x73, 0xe8, 0x36, 0x66, 0x29, 0x02,
-	0xe6, 0x57, 0x05, 0xb6, 0x57, 0x40, 0x6b, 0xba, 0x7b, 0x03, 0x75, 0x1e, 0x33, 0xe1, 0x27, 0x5c,
-	0x60, 0x83, 0x5b, 0xdd, 0xfd, 0x3f, 0xb9, 0xd6, 0x1a, 0x64, 0x64, 0x9a, 0xa7, 0x29, 0x04, 0x93,
-	0x03, 0xb6, 0x14, 0xac, 0xfd, 0x1c, 0xea, 0x4b, 0x2c, 0xa9, 0x82, 0xda, 0x77, 0x8c, 0x12, 0x01,
-	0xa8, 0x3a, 0x83, 0x91, 0xd7, 0x77, 0x0c, 0x45, 0xda, 0xf6, 0xbb, 0xfe, 0x70, 0x34, 0x34, 0x54,
-	0x42, 0x60, 0xab, 0x37, 0xb0, 0x87, 0x9e, 0x3c, 0xc4, 0xa0, 0x51, 0x36, 0xbf, 0xa8, 0xa0, 0xb9,
-	0x5c, 0x24, 0xe4, 0x00, 0xea, 0xb8, 0x0d, 0x01, 0x97, 0x23, 0x2c, 0x2b, 0x6e, 0xfe, 0x32, 0x5e,
-	0x22, 0xb1, 0xdc, 0x0c, 0x43, 0x73, 0x34, 0x39, 0x90, 0xd3, 0x2c, 0x12, 0x6c, 0x5f, 0xef, 0xde,
-	0x59, 0xcb, 0xe2, 0x22, 0x71, 0xfc, 0x09, 0x1b, 0x08, 0x67, 0x3e, 0x19, 0x33, 0x9c, 0x6a, 0x91,
-	0x98, 0x57, 0x0a, 0x18, 0xab, 0x47, 0xe4, 0x10, 0x34, 0x9c, 0x71, 0x05, 0x8b, 0xb8, 0x77, 0x9b,
-	0x74, 0x16, 0xce, 0x3c, 0xd2, 0xc8, 0x1e, 0x54, 0xa7, 0x18, 0x44, 0xdd, 0x2b, 0x34, 0xf3, 0xf2,
-	0x8d, 0x2e, 0x17, 0x1b, 0xdd, 0x6e, 0x82, 0x86, 0x1b, 0x22, 0x05, 0x3b, 0x3b, 0x3d, 0xb6, 0xa9,
-	0x51, 0x22, 0x75, 0xd0, 0x9c, 0xa3, 0x53, 0xdb, 0x50, 0xda, 0x4d, 0xa8, 0x2f, 0xbb, 0x25, 0x35,
-	0x28, 0x8f, 0x5e, 0xb8, 0x46, 0x49, 0x1a, 0x67, 0x3d, 0xd7, 0x50, 0xcc, 0x6f, 0x0a, 0x68, 0x2e,
-	0x63, 0x22, 0x5f, 0x66, 0xe5, 0xd6, 0xcb, 0xfc, 0x0c, 0x20, 0x7f, 0x37, 0x66, 0x58, 0xe6, 0xef,
-	0x78, 0xd7, 0xf0, 0xe4, 0x09, 0xd4, 0xc3, 0xd8, 0x1b, 0x47, 0x3c, 0xb8, 0xc0, 0x66, 0xf4, 0xee,
-	0x7f, 0xab, 0x1a, 0x31, 0x26, 0xac, 0xbe, 0x7b, 0x2c, 0x21, 0xb4, 0x16, 0xc6, 0x68, 0x98, 0xfb,
-	0x50, 0xcb, 0x62, 0x52, 0x8b, 0x20, 0x3c, 0x17, 0xcb, 0xd7, 0x4d, 0xda, 0x52, 0x37, 0x76, 0x19,
-	0xb0, 0x38, 0xc1, 0x35, 0xd9, 0xa0, 0x99, 0x67, 0x7a, 0xa0, 0x5f, 0x7b, 0x1a, 0xc8, 0xdd, 0xfc,
-	0x67, 0xcb, 0x5d, 0xda, 0x59, 0xf3, 0x77, 0xd2, 0x7f, 0x2b, 0x81, 0x1f, 0x04, 0x9f, 0x64, 0x4b,
-	0xb7, 0xb3, 0xa6, 0x44, 0x8a, 0x00, 0xf3, 0x3d, 0x80, 0xfd, 0x17, 0xf9, 0xff, 0x07, 0x35, 0xe1,
-	0x37, 0x65, 0x57, 0x13, 0xde, 0x7e, 0x0d, 0x50, 0x3c, 0x8a, 0x44, 0x87, 0x5a, 0xcf, 0x7e, 0x79,
-	0x74, 0x76, 0x32, 0x32, 0x4a, 0xd2, 0xe9, 0x3b, 0xaf, 0xa8, 0x3d, 0x1c, 0x66, 0x5b, 0x92, 0xda,
-	0x2a, 0xd9, 0x03, 0x92, 0x1d, 0x78, 0x47, 0x4e, 0xcf, 0xcb, 0xe2, 0xe5, 0x71, 0x15, 0x07, 0xfe,
-	0xf1, 0x8f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x40, 0x91, 0x36, 0x48, 0x3c, 0x06, 0x00, 0x00,
+	// 901 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x5f, 0x6f, 0xe3, 0x44,
+	0x10, 0xaf, 0xff, 0xc4, 0x49, 0xc7, 0x4d, 0x6b, 0x2d, 0xa7, 0x93, 0xcf, 0xf4, 0xa1, 0x0a, 0x48,
+	0x2d, 0x08, 0x19, 0x08, 0x14, 0x9d, 0x80, 0x43, 0xca, 0x35, 0x06, 0x82, 0x7a, 0x8e, 0xd9, 0xa4,
+	0x3a, 0xe0, 0xc5, 0x72, 0xdd, 0x05, 0xac, 0x73, 0x62, 0x6b, 0xe3, 0xa0, 0xcb, 0xa7, 0xe0, 0x89,
+	0x27, 0xbe, 0x03, 0x12, 0xdf, 0x83, 0xaf, 0xc1, 0x2b, 0x4f, 0x7c, 0x80, 0xd3, 0xce, 0xda, 0x4e,
+	0x93, 0x8b, 0x72, 0x69, 0x9f, 0x32, 0x33, 0xfb, 0xfb, 0xed, 0x78, 0x26, 0xbf, 0x99, 0x85, 0x83,
+	0x3c, 0x4b, 0x93, 0x78, 0xe1, 0xe6, 0x3c, 0x2b, 0x32, 0x62, 0x48, 0xaf, 0xf3, 0xff, 0x01, 0x18,
+	0x01, 0x9a, 0x84, 0x80, 0x3e, 0x8d, 0x26, 0xcc, 0x56, 0x4e, 0x94, 0xb3, 0x7d, 0x8a, 0x36, 0x39,
+	0x86, 0x7d, 0xf1, 0x3b, 0xcb, 0xa3, 0x98, 0xd9, 0x2a, 0x1e, 0x2c, 0x03, 0xe4, 0x7d, 0x68, 0xa4,
+	0xd1, 0x35, 0x4b, 0x6d, 0xed, 0x44, 0x3b, 0x33, 0xbb, 0x0f, 0xdc, 0x32, 0x85, 0xbc, 0xd0, 0xbd,
+	0x14, 0x67, 0x54, 0x42, 0xc8, 0x47, 0xa0, 0xe7, 0xd9, 0xcd, 0xcc, 0xd6, 0x4f, 0x94, 0x33, 0xb3,
+	0x7b, 0xbc, 0x09, 0x3a, 0x62, 0x29, 0x8b, 0x8b, 0x8c, 0x53, 0x44, 0x92, 0xcf, 0xc1, 0x94, 0xa0,
+	0xb0, 0x58, 0xe4, 0xcc, 0x6e, 0x9c, 0x28, 0x67, 0x87, 0xdd, 0x47, 0x6b, 0x44, 0xf9, 0x33, 0x5e,
+	0xe4, 0x8c, 0x42, 0x5e, 0xdb, 0xe4, 0x09, 0x1c, 0x24, 0xd3, 0x5f, 0x38, 0x9b, 0xcd, 0x42, 0x3e,
+	0x4f, 0x99, 0x6d, 0xe0, 0x07, 0x3a, 0x6b, 0xe4, 0x81, 0x84, 0xd0, 0x79, 0xca, 0xa8, 0x99, 0x2c,
+	0x1d, 0x91, 0x9a, 0xdd, 0x62, 0x37, 0x91, 0xbd, 0x9e, 0xda, 0x5b, 0x92, 0x81, 0xd5, 0xb6, 0xf3,
+	0x21, 0x34, 0xb0, 0x1a, 0x62, 0x81, 0xf6, 0x82, 0x2d, 0xca, 0x76, 0x0a, 0x93, 0x3c, 0x80, 0xc6,
+	0x6f, 0x51, 0x3a, 0xaf, 0x3a, 0x29, 0x1d, 0xe7, 0x3f, 0x15, 0xda, 0x2b, 0xf5, 0x93, 0x73, 0x30,
+	0x27, 0x51, 0x11, 0xff, 0x1a, 0xca, 0xee, 0x2a, 0x5b, 0xba, 0x0b, 0x08, 0x94, 0x09, 0x9f, 0x83,
+	0x25, 0x69, 0xec, 0x65, 0x2e, 0x3e, 0x27, 0xc9, 0xa6, 0xb6, 0x8a, 0xdc, 0x0f, 0xb6, 0xb5, 0x5b,
+	0x7a, 0x5e, 0xcd, 0xa1, 0x47, 0x78, 0xcb, 0x32, 0xe0, 0xfc, 0xa3, 0xc0, 0xd1, 0x1a, 0x68, 0x43,
+	0x75, 0xdf, 0x43, 0x2b, 0xcb, 0x19, 0x8f, 0x8a, 0x8c, 0x63, 0x81, 0x87, 0xdd, 0xf3, 0xbb, 0xa4,
+	0x75, 0x87, 0x25, 0x99, 0xd6, 0xd7, 0x2c, 0x1b, 0x26, 0x04, 0x56, 0x35, 0xac, 0xf3, 0x15, 0xb4,
+	0x2a, 0x2c, 0x31, 0x40, 0x1d, 0xf8, 0xd6, 0x1e, 0x01, 0x30, 0xfc, 0xe1, 0x38, 0x1c, 0xf8, 0x96,
+	0x22, 0x6c, 0xef, 0x87, 0xc1, 0x68, 0x3c, 0xb2, 0x54, 0x42, 0xe0, 0xb0, 0x3f, 0xf4, 0x46, 0xa1,
+	0x38, 0xc4, 0xa0, 0xa5, 0x39, 0xbf, 0x6b, 0xa0, 0x07, 0x19, 0x2f, 0xc8, 0x63, 0x68, 0xe1, 0x34,
+	0xc4, 0x99, 0x90, 0xb0, 0xf8, 0xe2, 0xe3, 0xd7, 0xe4, 0xc5, 0x0b, 0x37, 0x28, 0x31, 0xb4, 0x46,
+	0x93, 0x53, 0x38, 0xaa, 0xec, 0x70, 0x3a, 0x9f, 0x5c, 0x33, 0x8e, 0xc2, 0x6e, 0xd3, 0xc3, 0x2a,
+	0xec, 0x63, 0x94, 0x3c, 0x16, 0xb2, 0xe7, 0x05, 0xf6, 0xc9, 0xec, 0xbe, 0xbb, 0xf1, 0xfa, 0x8c,
+	0x17, 0x7e, 0x34, 0x61, 0x43, 0x2e, 0x39, 0x14, 0x19, 0xe4, 0x11, 0xb4, 0xd8, 0xf4, 0x26, 0x44,
+	0xb6, 0xd0, 0x7e, 0x83, 0x36, 0xd9, 0xf4, 0x46, 0x80, 0x9d, 0x3f, 0x15, 0xb0, 0xd6, 0x59, 0xe4,
+	0x09, 0xe8, 0x38, 0x27, 0x0a, 0x16, 0xf2, 0xde, 0x2e, 0x99, 0x5c, 0x9c, 0x1b, 0xa4, 0x91, 0x87,
+	0x60, 0x94, 0x85, 0xa8, 0x98, 0xac, 0xf4, 0xea, 0xad, 0xa0, 0x2d, 0xb7, 0x42, 0xe7, 0x18, 0x74,
+	0x9c, 0x32, 0xd1, 0xf4, 0xab, 0x67, 0x4f, 0x3d, 0x6a, 0xed, 0x91, 0x16, 0xe8, 0x7e, 0xef, 0x99,
+	0x67, 0x29, 0x9d, 0x8f, 0xa1, 0x55, 0x75, 0x8c, 0x34, 0x41, 0x1b, 0x5f, 0x04, 0xd6, 0x9e, 0x30,
+	0xae, 0xfa, 0x81, 0xa5, 0x08, 0xdc, 0xe8, 0x62, 0x1c, 0x58, 0x2a, 0xd9, 0x87, 0xc6, 0x70, 0xfc,
+	0xad, 0x47, 0x2d, 0xcd, 0xf9, 0x57, 0x01, 0x3d, 0x60, 0x8c, 0xd7, 0x5b, 0x42, 0xd9, 0x79, 0x4b,
+	0x7c, 0x09, 0x50, 0x2f, 0xa4, 0x99, 0xad, 0xee, 0xc0, 0xbb, 0x85, 0x27, 0x9f, 0x41, 0x2b, 0xc9,
+	0xc3, 0xeb, 0x34, 0x8b, 0x5f, 0x60, 0x85, 0x66, 0xf7, 0xed, 0xf5, 0xc6, 0x31, 0xc6, 0xdd, 0x41,
+	0xf0, 0x54, 0x40, 0x68, 0x33, 0xc9, 0xd1, 0x70, 0xce, 0xa1, 0x59, 0xc6, 0x44, 0x83, 0xe2, 0xe4,
+	0x86, 0x57, 0x6b, 0x53, 0xd8, 0xa2, 0x99, 0xec, 0x65, 0xcc, 0xf2, 0x02, 0xe7, 0x6f, 0x9f, 0x96,
+	0x9e, 0x13, 0x82, 0x79, 0x6b, 0xe7, 0x90, 0xd3, 0x5a, 0x1c, 0x62, 0x48, 0xdf, 0xda, 0xf0, 0x97,
+	0x95, 0x5a, 0x38, 0x05, 0xfd, 0x67, 0x9e, 0x4d, 0x6c, 0x75, 0x33, 0x90, 0x09, 0xd1, 0x08, 0x80,
+	0xf3, 0x13, 0x80, 0x77, 0x8f, 0xfb, 0xdf, 0x01, 0xb5, 0xc8, 0xb6, 0xdd, 0xae, 0x16, 0x59, 0xe7,
+	0x3b, 0x80, 0xe5, 0xb6, 0x25, 0x26, 0x34, 0xfb, 0xde, 0xd7, 0xbd, 0xab, 0xcb, 0xb1, 0xb5, 0x27,
+	0x9c, 0x81, 0xff, 0x0d, 0xf5, 0x46, 0xa3, 0x72, 0xfc, 0xa4, 0xad, 0x92, 0x87, 0x40, 0xca, 0x83,
+	0xb0, 0xe7, 0xf7, 0xc3, 0x32, 0xae, 0x75, 0xfe, 0x6e, 0x40, 0xfb, 0x22, 0x9d, 0xcf, 0x0a, 0xc6,
+	0xb7, 0xbc, 0x3e, 0x04, 0xf4, 0x22, 0x29, 0x15, 0xd9, 0xa6, 0x68, 0x13, 0x47, 0xcc, 0x6c, 0x92,
+	0xf1, 0xa4, 0x58, 0xe0, 0x3f, 0xd6, 0xa6, 0xb5, 0xbf, 0xa6, 0x05, 0xfd, 0x8e, 0x5a, 0xa8, 0xb4,
+	0xd7, 0xd8, 0x59, 0x7b, 0x6f, 0x78, 0x65, 0x56, 0x0a, 0x74, 0x5f, 0x7f, 0x65, 0xbe, 0xd8, 0xf4,
+	0xca, 0x6c, 0x63, 0xdf, 0x7e, 0x66, 0xfe, 0xaa, 0x46, 0x66, 0xb5, 0x68, 0xe5, 0x9e, 0x45, 0xab,
+	0x3b, 0x17, 0x7d, 0xdf, 0x91, 0xf9, 0x43, 0x01, 0x1d, 0xcb, 0xfe, 0x14, 0x8c, 0x28, 0x2e, 0xc4,
+	0xe3, 0xa4, 0xac, 0xee, 0xdc, 0xd5, 0x8a, 0x7b, 0x88, 0xa1, 0x25, 0xb6, 0xd6, 0xb2, 0xfa, 0x26,
+	0x2d, 0xbb, 0xa0, 0xe7, 0x8c, 0x71, 0x5b, 0xdb, 0xd6, 0x4e, 0x39, 0x32, 0x02, 0xd7, 0x39, 0x05,
+	0x43, 0xa6, 0x12, 0x0b, 0xa9, 0x77, 0x79, 0x39, 0x7c, 0x2e, 0xb7, 0x59, 0xdf, 0xf3, 0x7f, 0x94,
+	0xfb, 0x2a, 0xe8, 0x09, 0x2d, 0x5f, 0x1b, 0xb8, 0xda, 0x3f, 0x79, 0x35, 0x00, 0x54, 0x60, 0xcf,
+	0x84, 0x49, 0x09, 0x00, 0x00,
 }
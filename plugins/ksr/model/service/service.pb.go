@@ -155,10 +155,16 @@ type Service struct {
 	// queryable and should be preserved when modifying objects.
 	// More info: http://kubernetes.io/docs/user-guide/annotations
 	// +optional
-	Annotations          map[string]string `protobuf:"bytes,15,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Annotations map[string]string `protobuf:"bytes,15,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// ClusterIPs is a list of IP addresses assigned to this service, one per
+	// IP family with dual-stack. If set, the first item equals cluster_ip.
+	// Valid values are "None", empty list or a list of valid IP addresses.
+	// More info: https://kubernetes.io/docs/concepts/services-networking/dual-stack/
+	// +optional
+	ClusterIps           []string `protobuf:"bytes,16,rep,name=cluster_ips,json=clusterIps,proto3" json:"cluster_ips,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Service) Reset()         { *m = Service{} }
@@ -291,6 +297,13 @@ func (m *Service) GetAnnotations() map[string]string {
 	return nil
 }
 
+func (m *Service) GetClusterIps() []string {
+	if m != nil {
+		return m.ClusterIps
+	}
+	return nil
+}
+
 // ServicePort contains information on service's port.
 type Service_ServicePort struct {
 	// The name of this port within the service. This must be a DNS_LABEL.
@@ -449,44 +462,44 @@ func init() {
 func init() { proto.RegisterFile("service.proto", fileDescriptor_a0b84a42fa06f626) }
 
 var fileDescriptor_a0b84a42fa06f626 = []byte{
-	// 611 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xdd, 0x4e, 0x1b, 0x3d,
-	0x10, 0xfd, 0x96, 0xfc, 0xcf, 0x92, 0x10, 0xf9, 0x6b, 0xc1, 0x4a, 0x29, 0x0a, 0xa8, 0x52, 0xd3,
-	0x8b, 0x46, 0x15, 0x48, 0x15, 0xa2, 0x55, 0x25, 0x8a, 0x50, 0xb5, 0x17, 0xa5, 0x68, 0x93, 0x72,
-	0xbb, 0x72, 0x16, 0x13, 0x2c, 0x8c, 0xbd, 0xb2, 0x1d, 0xd4, 0xbc, 0x48, 0x9f, 0xa1, 0x0f, 0xd6,
-	0x07, 0xa9, 0x3c, 0xde, 0x40, 0xa0, 0xa8, 0x6a, 0xaf, 0xd6, 0x3e, 0xe7, 0x4c, 0x66, 0xc6, 0x73,
-	0x26, 0xd0, 0xb6, 0xdc, 0xdc, 0x88, 0x9c, 0x0f, 0x0b, 0xa3, 0x9d, 0x26, 0x8d, 0xf2, 0xba, 0xf3,
-	0xbd, 0x05, 0x8d, 0x51, 0x38, 0x13, 0x02, 0x55, 0xc5, 0xae, 0x39, 0x8d, 0xfa, 0xd1, 0xa0, 0x95,
-	0xe2, 0x99, 0x6c, 0x42, 0xcb, 0x7f, 0x6d, 0xc1, 0x72, 0x4e, 0x57, 0x90, 0xb8, 0x03, 0xc8, 0x1b,
-	0xa8, 0x16, 0xda, 0x38, 0x5a, 0xe9, 0x57, 0x06, 0xf1, 0xee, 0xe6, 0x70, 0x91, 0x64, 0x74, 0xff,
-	0x7b, 0xaa, 0x8d, 0x4b, 0x51, 0x49, 0x0e, 0xa0, 0x69, 0xb9, 0xe4, 0xb9, 0xd3, 0x86, 0x56, 0x31,
-	0x6a, 0xeb, 0x91, 0xa8, 0x20, 0x38, 0x56, 0xce, 0xcc, 0xd3, 0x5b, 0x3d, 0x79, 0x0e, 0x90, 0xcb,
-	0x99, 0x75, 0xdc, 0x64, 0xa2, 0xa0, 0xb5, 0x50, 0x4c, 0x89, 0x24, 0x05, 0xd9, 0x86, 0xd5, 0xf2,
-	0x97, 0x32, 0x37, 0x2f, 0x38, 0xad, 0xa3, 0x20, 0x2e, 0xb1, 0xf1, 0xbc, 0xe0, 0x5e, 0xc2, 0xbf,
-	0x39, 0x6e, 0x14, 0x93, 0x99, 0x28, 0x2c, 0x6d, 0xf4, 0x2b, 0x5e, 0xb2, 0xc0, 0x92, 0xc2, 0x92,
-	0x17, 0xd0, 0x91, 0x93, 0x4c, 0xa8, 0xa9, 0xe1, 0xd6, 0xa2, 0xa8, 0x89, 0xa2, 0x55, 0x39, 0x49,
-	0x02, 0xe8, 0x55, 0xaf, 0xa0, 0x6b, 0xb9, 0xb5, 0x42, 0xab, 0x8c, 0x5d, 0x5c, 0x08, 0x25, 0xdc,
-	0x9c, 0xb6, 0x30, 0xdf, 0x5a, 0x89, 0x1f, 0x96, 0x30, 0x79, 0x09, 0x6b, 0x52, 0xb3, 0xf3, 0x09,
-	0x93, 0x4c, 0xe5, 0xa1, 0x74, 0x40, 0x65, 0x67, 0x19, 0x4e, 0x0a, 0xf2, 0x1e, 0x7a, 0xf7, 0x84,
-	0x56, 0xcf, 0x4c, 0xce, 0x33, 0xc3, 0xd4, 0x94, 0x5b, 0x1a, 0x63, 0x15, 0x74, 0x59, 0x31, 0x42,
-	0x41, 0x8a, 0x3c, 0x79, 0x0b, 0x1b, 0xb7, 0xad, 0x39, 0xe3, 0x8b, 0xca, 0xb3, 0x42, 0x4b, 0x91,
-	0xcf, 0xe9, 0x2a, 0xa6, 0x7b, 0xba, 0xa0, 0xc7, 0x81, 0x3d, 0x45, 0x92, 0xec, 0xc1, 0xfa, 0x25,
-	0x67, 0xd2, 0x5d, 0x66, 0xf9, 0x25, 0xcf, 0xaf, 0x32, 0xa5, 0xcf, 0x79, 0x86, 0x43, 0x6d, 0xf7,
-	0xa3, 0x41, 0x2d, 0xfd, 0x3f, 0xb0, 0x47, 0x9e, 0x3c, 0xd1, 0xe7, 0x38, 0x4b, 0xb2, 0x0f, 0xf4,
-	0x61, 0xfb, 0x99, 0x13, 0xd7, 0x5c, 0xcf, 0x1c, 0xed, 0xf4, 0xa3, 0x41, 0x3b, 0x5d, 0x7f, 0xf0,
-	0x0c, 0xe3, 0xc0, 0x92, 0x23, 0x88, 0x99, 0x52, 0xda, 0x31, 0x27, 0xb4, 0xb2, 0x74, 0x0d, 0x2d,
-	0xb0, 0xfd, 0x9b, 0x05, 0x0e, 0xef, 0x34, 0xc1, 0x05, 0xcb, 0x51, 0xbd, 0x9f, 0x2b, 0x10, 0x2f,
-	0x59, 0xeb, 0x51, 0xe3, 0xf6, 0xa0, 0x89, 0x56, 0xcf, 0xb5, 0x2c, 0x7d, 0x7b, 0x7b, 0xf7, 0xfa,
-	0xd2, 0xb6, 0xbe, 0xc3, 0x60, 0xcc, 0x04, 0x62, 0xc7, 0xcc, 0x94, 0xbb, 0xd0, 0x7c, 0xb5, 0x1f,
-	0x0d, 0xe2, 0xdd, 0xc1, 0x9f, 0x1c, 0x3d, 0x4c, 0x94, 0xfb, 0x62, 0x46, 0xce, 0x08, 0x35, 0x4d,
-	0x21, 0x04, 0x63, 0x39, 0xcf, 0xa0, 0x75, 0xf7, 0x8a, 0x35, 0xcc, 0xd1, 0x54, 0xe5, 0xd3, 0xf5,
-	0x7e, 0x44, 0x10, 0x2f, 0x05, 0x92, 0x43, 0xa8, 0xa2, 0x5b, 0x7d, 0xed, 0x9d, 0xdd, 0xd7, 0x7f,
-	0x9b, 0x70, 0xe8, 0xfd, 0x9c, 0x62, 0x28, 0xd9, 0x80, 0x86, 0x50, 0x2e, 0xbb, 0x61, 0xa1, 0xd3,
-	0x5a, 0x5a, 0x17, 0xca, 0x9d, 0x31, 0xe9, 0x17, 0xc6, 0xa2, 0x1a, 0xb9, 0x4a, 0x58, 0x98, 0x80,
-	0x9c, 0x31, 0xb9, 0xb3, 0x05, 0x55, 0xdc, 0x0a, 0x80, 0xfa, 0xc9, 0xd7, 0xcf, 0x1f, 0x8f, 0xd3,
-	0xee, 0x7f, 0xfe, 0x3c, 0x1a, 0xa7, 0xc9, 0xc9, 0xa7, 0x6e, 0xd4, 0x7b, 0x07, 0xed, 0x7b, 0xab,
-	0x48, 0xba, 0x50, 0xb9, 0xe2, 0xf3, 0xf2, 0x99, 0xfd, 0x91, 0x3c, 0x81, 0xda, 0x0d, 0x93, 0xb3,
-	0xc5, 0x5f, 0x43, 0xb8, 0x1c, 0xac, 0xec, 0x47, 0xbd, 0x0f, 0xd0, 0x7d, 0x38, 0xc4, 0x7f, 0x89,
-	0x9f, 0xd4, 0x71, 0x5a, 0x7b, 0xbf, 0x02, 0x00, 0x00, 0xff, 0xff, 0x6e, 0xb0, 0x09, 0x7c, 0xb9,
-	0x04, 0x00, 0x00,
+	// 620 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xdb, 0x6e, 0x13, 0x31,
+	0x10, 0x65, 0x9b, 0xfb, 0x6c, 0x93, 0x46, 0x06, 0x5a, 0x2b, 0x94, 0x92, 0x56, 0x48, 0x84, 0x07,
+	0x22, 0xd4, 0x4a, 0xa8, 0x2a, 0x08, 0xa9, 0x54, 0x15, 0xda, 0x07, 0x4a, 0xb5, 0x09, 0x7d, 0x5d,
+	0x39, 0x5b, 0x37, 0x5d, 0xd5, 0xb5, 0x57, 0xb6, 0x53, 0x91, 0x3f, 0xe2, 0x0f, 0xf8, 0x21, 0x3e,
+	0x04, 0x79, 0xbc, 0xb9, 0x34, 0x54, 0x08, 0x9e, 0x76, 0x7c, 0xce, 0x99, 0xcc, 0x78, 0x7c, 0x26,
+	0xd0, 0x34, 0x5c, 0xdf, 0x65, 0x29, 0xef, 0xe7, 0x5a, 0x59, 0x45, 0x6a, 0xc5, 0x71, 0xef, 0x67,
+	0x03, 0x6a, 0x03, 0x1f, 0x13, 0x02, 0x65, 0xc9, 0x6e, 0x39, 0x0d, 0xba, 0x41, 0xaf, 0x11, 0x63,
+	0x4c, 0xb6, 0xa1, 0xe1, 0xbe, 0x26, 0x67, 0x29, 0xa7, 0x6b, 0x48, 0x2c, 0x00, 0xf2, 0x16, 0xca,
+	0xb9, 0xd2, 0x96, 0x96, 0xba, 0xa5, 0x5e, 0xb8, 0xbf, 0xdd, 0x9f, 0x15, 0x19, 0xdc, 0xff, 0x9e,
+	0x2b, 0x6d, 0x63, 0x54, 0x92, 0x23, 0xa8, 0x1b, 0x2e, 0x78, 0x6a, 0x95, 0xa6, 0x65, 0xcc, 0xda,
+	0x79, 0x20, 0xcb, 0x0b, 0x4e, 0xa5, 0xd5, 0xd3, 0x78, 0xae, 0x27, 0xcf, 0x01, 0x52, 0x31, 0x31,
+	0x96, 0xeb, 0x24, 0xcb, 0x69, 0xc5, 0x37, 0x53, 0x20, 0x51, 0x4e, 0x76, 0x61, 0xbd, 0xf8, 0xa5,
+	0xc4, 0x4e, 0x73, 0x4e, 0xab, 0x28, 0x08, 0x0b, 0x6c, 0x38, 0xcd, 0xb9, 0x93, 0xf0, 0xef, 0x96,
+	0x6b, 0xc9, 0x44, 0x92, 0xe5, 0x86, 0xd6, 0xba, 0x25, 0x27, 0x99, 0x61, 0x51, 0x6e, 0xc8, 0x4b,
+	0x68, 0x89, 0x51, 0x92, 0xc9, 0xb1, 0xe6, 0xc6, 0xa0, 0xa8, 0x8e, 0xa2, 0x75, 0x31, 0x8a, 0x3c,
+	0xe8, 0x54, 0xaf, 0xa1, 0x6d, 0xb8, 0x31, 0x99, 0x92, 0x09, 0xbb, 0xba, 0xca, 0x64, 0x66, 0xa7,
+	0xb4, 0x81, 0xf5, 0x36, 0x0a, 0xfc, 0xb8, 0x80, 0xc9, 0x2b, 0xd8, 0x10, 0x8a, 0x5d, 0x8e, 0x98,
+	0x60, 0x32, 0xf5, 0xad, 0x03, 0x2a, 0x5b, 0xcb, 0x70, 0x94, 0x93, 0x0f, 0xd0, 0xb9, 0x27, 0x34,
+	0x6a, 0xa2, 0x53, 0x9e, 0x68, 0x26, 0xc7, 0xdc, 0xd0, 0x10, 0xbb, 0xa0, 0xcb, 0x8a, 0x01, 0x0a,
+	0x62, 0xe4, 0xc9, 0x3b, 0xd8, 0x9a, 0x5f, 0xcd, 0x6a, 0xd7, 0x54, 0x9a, 0xe4, 0x4a, 0x64, 0xe9,
+	0x94, 0xae, 0x63, 0xb9, 0xa7, 0x33, 0x7a, 0xe8, 0xd9, 0x73, 0x24, 0xc9, 0x01, 0x6c, 0x5e, 0x73,
+	0x26, 0xec, 0x75, 0x92, 0x5e, 0xf3, 0xf4, 0x26, 0x91, 0xea, 0x92, 0x27, 0xf8, 0xa8, 0xcd, 0x6e,
+	0xd0, 0xab, 0xc4, 0x8f, 0x3d, 0x7b, 0xe2, 0xc8, 0x33, 0x75, 0x89, 0x6f, 0x49, 0x0e, 0x81, 0xae,
+	0x5e, 0x3f, 0xb1, 0xd9, 0x2d, 0x57, 0x13, 0x4b, 0x5b, 0xdd, 0xa0, 0xd7, 0x8c, 0x37, 0x57, 0xc6,
+	0x30, 0xf4, 0x2c, 0x39, 0x81, 0x90, 0x49, 0xa9, 0x2c, 0xb3, 0x99, 0x92, 0x86, 0x6e, 0xa0, 0x05,
+	0x76, 0xff, 0xb0, 0xc0, 0xf1, 0x42, 0xe3, 0x5d, 0xb0, 0x9c, 0x45, 0x5e, 0x40, 0xb8, 0x30, 0x82,
+	0xa1, 0x6d, 0x1c, 0x0d, 0xcc, 0x9d, 0x60, 0x3a, 0xbf, 0xd6, 0x20, 0x5c, 0xf2, 0xde, 0x83, 0xce,
+	0xee, 0x40, 0x1d, 0x77, 0x21, 0x55, 0xa2, 0x30, 0xf6, 0xfc, 0xec, 0xf4, 0x85, 0xaf, 0xdd, 0x08,
+	0x30, 0x26, 0x11, 0x84, 0x96, 0xe9, 0x31, 0xb7, 0x7e, 0x3a, 0xe5, 0x6e, 0xd0, 0x0b, 0xf7, 0x7b,
+	0x7f, 0xb3, 0x7c, 0x3f, 0x92, 0xf6, 0xab, 0x1e, 0x58, 0x9d, 0xc9, 0x71, 0x0c, 0x3e, 0x19, 0xdb,
+	0x79, 0x06, 0x8d, 0xc5, 0x98, 0x2b, 0x58, 0xa3, 0x2e, 0x8b, 0xd9, 0x76, 0x7e, 0x04, 0x10, 0x2e,
+	0x25, 0x92, 0x63, 0x28, 0xa3, 0x9d, 0x5d, 0xef, 0xad, 0xfd, 0x37, 0xff, 0x5a, 0xb0, 0xef, 0x0c,
+	0x1f, 0x63, 0x2a, 0xd9, 0x82, 0x5a, 0x26, 0x6d, 0x72, 0xc7, 0xfc, 0x4d, 0x2b, 0x71, 0x35, 0x93,
+	0xf6, 0x82, 0x09, 0xb7, 0x51, 0x06, 0xd5, 0xc8, 0x95, 0xfc, 0x46, 0x79, 0xe4, 0x82, 0x89, 0xbd,
+	0x1d, 0x28, 0xe3, 0xda, 0x00, 0x54, 0xcf, 0xbe, 0x7d, 0xf9, 0x74, 0x1a, 0xb7, 0x1f, 0xb9, 0x78,
+	0x30, 0x8c, 0xa3, 0xb3, 0xcf, 0xed, 0xa0, 0xf3, 0x1e, 0x9a, 0xf7, 0x76, 0x95, 0xb4, 0xa1, 0x74,
+	0xc3, 0xa7, 0xc5, 0x98, 0x5d, 0x48, 0x9e, 0x40, 0xe5, 0x8e, 0x89, 0xc9, 0xec, 0xbf, 0xc3, 0x1f,
+	0x8e, 0xd6, 0x0e, 0x83, 0xce, 0x47, 0x68, 0xaf, 0xbe, 0xf2, 0xff, 0xe4, 0x8f, 0xaa, 0xf8, 0x5a,
+	0x07, 0xbf, 0x07, 0x00, 0x01, 0x36, 0xfc, 0x25, 0xda, 0x04, 0x00, 0x00,
 }
@@ -122,5 +122,6 @@ func (nr *NamespaceReflector) namespaceToProto(ns *coreV1.Namespace) *namespace.
 			nsProto.Label = append(nsProto.Label, &namespace.Namespace_Label{Key: key, Value: val})
 		}
 	}
+	nsProto.Annotations = ns.GetAnnotations()
 	return nsProto
 }
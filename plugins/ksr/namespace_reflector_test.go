@@ -89,6 +89,7 @@ func testAddDeleteNamespace(t *testing.T) {
 	ns.Labels = make(map[string]string)
 	ns.Labels["role"] = "mgmt"
 	ns.Labels["privileged"] = "true"
+	ns.Annotations = map[string]string{"contivpp.io/policy-audit": "deny"}
 
 	// Take a snapshot of counters
 	adds := nsTestVars.nsReflector.GetStats().Adds
@@ -113,6 +114,7 @@ func testAddDeleteNamespace(t *testing.T) {
 	gomega.Expect(nsProto.Label).To(gomega.HaveLen(2))
 	gomega.Expect(nsProto.Label).To(gomega.ContainElement(&proto.Namespace_Label{Key: "role", Value: "mgmt"}))
 	gomega.Expect(nsProto.Label).To(gomega.ContainElement(&proto.Namespace_Label{Key: "privileged", Value: "true"}))
+	gomega.Expect(nsProto.Annotations).To(gomega.Equal(ns.Annotations))
 
 	gomega.Expect(adds + 1).To(gomega.Equal(nsTestVars.nsReflector.GetStats().Adds))
 
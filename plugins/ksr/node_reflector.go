@@ -129,6 +129,7 @@ func (nr *NodeReflector) updateNode(oldObj, newObj interface{}) {
 func (nr *NodeReflector) nodeToProto(k8sNode *coreV1.Node) *node.Node {
 	nodeProto := &node.Node{}
 	nodeProto.Name = k8sNode.Name
+	nodeProto.Labels = k8sNode.GetLabels()
 
 	nodeProto.Pod_CIDR = k8sNode.Spec.PodCIDR
 	nodeProto.Provider_ID = k8sNode.Spec.ProviderID
@@ -74,6 +74,10 @@ func TestNodeReflector(t *testing.T) {
 				Generation:      1,
 				CreationTimestamp: metaV1.Date(2018, 01, 14, 18, 53, 37, 0,
 					time.FixedZone("PST", -800)),
+				Labels: map[string]string{
+					"topology.kubernetes.io/region": "region1",
+					"topology.kubernetes.io/zone":   "zone1",
+				},
 			},
 			Spec: coreV1.NodeSpec{
 				PodCIDR:    "10.20.30.40/24",
@@ -344,6 +348,7 @@ func checkNodeToProtoTranslation(t *testing.T, protoNode *node.Node, k8sNode *co
 
 	gomega.Expect(protoNode.Pod_CIDR).To(gomega.Equal(k8sNode.Spec.PodCIDR))
 	gomega.Expect(protoNode.Provider_ID).To(gomega.Equal(k8sNode.Spec.ProviderID))
+	gomega.Expect(protoNode.Labels).To(gomega.Equal(k8sNode.GetLabels()))
 
 	gomega.Expect(protoNode.NodeInfo.Architecture).To(gomega.Equal(k8sNode.Status.NodeInfo.Architecture))
 	gomega.Expect(protoNode.NodeInfo.Boot_ID).To(gomega.Equal(k8sNode.Status.NodeInfo.BootID))
@@ -17,6 +17,7 @@
 //go:generate protoc -I ./model/policy --go_out=plugins=grpc:./model/policy ./model/policy/policy.proto
 //go:generate protoc -I ./model/service --go_out=plugins=grpc:./model/service ./model/service/service.proto
 //go:generate protoc -I ./model/endpoints --go_out=plugins=grpc:./model/endpoints ./model/endpoints/endpoints.proto
+//go:generate protoc -I ./model/endpointslice --go_out=plugins=grpc:./model/endpointslice ./model/endpointslice/endpointslice.proto
 //go:generate protoc -I ./model/node --go_out=plugins=grpc:./model/node ./model/node/node.proto
 //go:generate protoc -I ./model/ksrapi --go_out=plugins=grpc:./model/ksrapi ./model/ksrapi/ksr_nb_api.proto
 //go:generate protoc -I ./model/sfc --go_out=plugins=grpc:./model/sfc ./model/sfc/sfc.proto
@@ -29,6 +30,7 @@ import (
 	"sync"
 	"time"
 
+	crdClientSet "github.com/americanbinary/vpp/plugins/crd/pkg/client/clientset/versioned"
 	"github.com/americanbinary/vpp/plugins/ksr/model/ksrapi"
 
 	"k8s.io/client-go/kubernetes"
@@ -59,14 +61,17 @@ type Plugin struct {
 
 	k8sClientConfig *rest.Config
 	k8sClientset    *kubernetes.Clientset
-
-	nsReflector        *NamespaceReflector
-	podReflector       *PodReflector
-	policyReflector    *PolicyReflector
-	serviceReflector   *ServiceReflector
-	endpointsReflector *EndpointsReflector
-	nodeReflector      *NodeReflector
-	sfcPodReflector    *SfcPodReflector
+	crdClientset    *crdClientSet.Clientset
+
+	nsReflector            *NamespaceReflector
+	podReflector           *PodReflector
+	policyReflector        *PolicyReflector
+	clusterPolicyReflector *ClusterPolicyReflector
+	serviceReflector       *ServiceReflector
+	endpointsReflector     *EndpointsReflector
+	endpointSliceReflector *EndpointSliceReflector
+	nodeReflector          *NodeReflector
+	sfcPodReflector        *SfcPodReflector
 
 	reflectorRegistry *ReflectorRegistry
 
@@ -104,14 +109,16 @@ type Deps struct {
 
 // Reflector object types
 const (
-	namespaceObjType = "Namespace"
-	podObjType       = "Pod"
-	policyObjType    = "NetworkPolicy"
-	endpointsObjType = "Endpoints"
-	serviceObjType   = "Service"
-	nodeObjType      = "Node"
-	sfcPodObjType    = "SfcPod"
-	electionPrefix   = "/contiv-ksr/election"
+	namespaceObjType     = "Namespace"
+	podObjType           = "Pod"
+	policyObjType        = "NetworkPolicy"
+	endpointsObjType     = "Endpoints"
+	serviceObjType       = "Service"
+	nodeObjType          = "Node"
+	sfcPodObjType        = "SfcPod"
+	clusterPolicyObjType = "ClusterNetworkPolicy"
+	endpointSliceObjType = "EndpointSlice"
+	electionPrefix       = "/contiv-ksr/election"
 )
 
 // Init builds K8s client-set based on the supplied kubeconfig and initializes
@@ -139,6 +146,11 @@ func (plugin *Plugin) Init() error {
 		return fmt.Errorf("failed to build kubernetes client: %s", err)
 	}
 
+	plugin.crdClientset, err = crdClientSet.NewForConfig(plugin.k8sClientConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build crd client: %s", err)
+	}
+
 	ksrPrefix := plugin.Publish.ServiceLabel.GetAgentPrefix()
 
 	plugin.etcdMonitor.broker = plugin.Publish.Deps.KvPlugin.NewBroker(ksrPrefix)
@@ -188,6 +200,17 @@ func (plugin *Plugin) Init() error {
 		return err
 	}
 
+	plugin.clusterPolicyReflector = &ClusterPolicyReflector{
+		Reflector: plugin.newReflector("-clusterPolicy", clusterPolicyObjType, broker),
+		CrdClient: plugin.crdClientset,
+	}
+	//plugin.clusterPolicyReflector.Log.SetLevel(logging.DebugLevel)
+	err = plugin.clusterPolicyReflector.Init(plugin.stopCh, &plugin.wg)
+	if err != nil {
+		plugin.Log.WithField("rwErr", err).Error("Failed to initialize Cluster Policy reflector")
+		return err
+	}
+
 	plugin.serviceReflector = &ServiceReflector{
 		Reflector: plugin.newReflector("-service", serviceObjType, broker),
 	}
@@ -208,6 +231,23 @@ func (plugin *Plugin) Init() error {
 		return err
 	}
 
+	// EndpointSlices are reflected only if the API is enabled in the cluster,
+	// otherwise the reflector would never get synced
+	if _, err := plugin.k8sClientset.Discovery().ServerResourcesForGroupVersion(endpointSliceGroupVersion); err == nil {
+		plugin.endpointSliceReflector = &EndpointSliceReflector{
+			Reflector: plugin.newReflector("-endpointSlice", endpointSliceObjType, broker),
+		}
+		//plugin.endpointSliceReflector.Log.SetLevel(logging.DebugLevel)
+		err = plugin.endpointSliceReflector.Init(plugin.stopCh, &plugin.wg)
+		if err != nil {
+			plugin.Log.WithField("rwErr", err).Error("Failed to initialize EndpointSlice reflector")
+			return err
+		}
+	} else {
+		plugin.Log.WithField("rwErr", err).Warnf("API %s is not available, EndpointSlices will not be reflected",
+			endpointSliceGroupVersion)
+	}
+
 	plugin.nodeReflector = &NodeReflector{
 		Reflector:    plugin.newReflector("-node", nodeObjType, broker),
 		rootBroker:   plugin.Publish.Deps.KvPlugin.NewBroker(""),
@@ -269,7 +309,10 @@ func (plugin *Plugin) Close() error {
 	close(plugin.stopCh)
 	plugin.cancelFunc()
 	safeclose.CloseAll(plugin.nsReflector, plugin.podReflector, plugin.policyReflector,
-		plugin.serviceReflector, plugin.endpointsReflector)
+		plugin.clusterPolicyReflector, plugin.serviceReflector, plugin.endpointsReflector)
+	if plugin.endpointSliceReflector != nil {
+		plugin.endpointSliceReflector.Close()
+	}
 	plugin.wg.Wait()
 	return nil
 }
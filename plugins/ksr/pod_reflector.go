@@ -135,6 +135,9 @@ func (pr *PodReflector) podToProto(k8sPod *coreV1.Pod) *pod.Pod {
 		}
 	}
 	podProto.IpAddress = k8sPod.Status.PodIP
+	for _, podIP := range k8sPod.Status.PodIPs {
+		podProto.IpAddresses = append(podProto.IpAddresses, podIP.IP)
+	}
 	podProto.HostIpAddress = k8sPod.Status.HostIP
 	for _, container := range k8sPod.Spec.Containers {
 		podProto.Container = append(podProto.Container, pr.containerToProto(&container))
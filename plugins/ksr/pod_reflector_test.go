@@ -118,6 +118,10 @@ func TestPodReflector(t *testing.T) {
 			Status: coreV1.PodStatus{
 				HostIP: "10.0.2.15",
 				PodIP:  "192.168.49.92",
+				PodIPs: []coreV1.PodIP{
+					{IP: "192.168.49.92"},
+					{IP: "fd00:49::92"},
+				},
 			},
 		},
 		// Test data 1: mocks a pre-existing object in the data store that is
@@ -347,6 +351,7 @@ func testAddDeletePod(t *testing.T) {
 
 	gomega.Expect(protoPod.HostIpAddress).To(gomega.Equal(k8sPod.Status.HostIP))
 	gomega.Expect(protoPod.IpAddress).To(gomega.Equal(k8sPod.Status.PodIP))
+	gomega.Expect(protoPod.IpAddresses).To(gomega.Equal([]string{"192.168.49.92", "fd00:49::92"}))
 
 	gomega.Expect(protoPod.Container[0].Name).To(gomega.Equal(k8sPod.Spec.Containers[0].Name))
 	gomega.Expect(protoPod.Container[0].Port[0].Name).
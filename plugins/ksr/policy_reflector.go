@@ -151,7 +151,7 @@ func (pr *PolicyReflector) policyToProto(k8sPolicy *networkingV1.NetworkPolicy)
 		})
 	}
 	// Pods
-	policyProto.Pods = pr.labelSelectorToProto(&k8sPolicy.Spec.PodSelector)
+	policyProto.Pods = labelSelectorToProto(&k8sPolicy.Spec.PodSelector)
 
 	// PolicyType
 	ingress := 0
@@ -212,7 +212,7 @@ func (pr *PolicyReflector) policyToProto(k8sPolicy *networkingV1.NetworkPolicy)
 
 // labelSelectorToProto converts label selector from the k8s representation into
 // our protobuf-modelled data structure.
-func (pr *PolicyReflector) labelSelectorToProto(selector *clientApiMetaV1.LabelSelector) *policy.Policy_LabelSelector {
+func labelSelectorToProto(selector *clientApiMetaV1.LabelSelector) *policy.Policy_LabelSelector {
 	selectorProto := &policy.Policy_LabelSelector{}
 	// MatchLabels
 	if selector.MatchLabels != nil {
@@ -270,6 +270,11 @@ func (pr *PolicyReflector) portsToProto(ports []networkingV1.NetworkPolicyPort)
 				portProto.Protocol = policy.Policy_Port_TCP
 			case coreV1.ProtocolUDP:
 				portProto.Protocol = policy.Policy_Port_UDP
+			case coreV1.ProtocolSCTP:
+				portProto.Protocol = policy.Policy_Port_SCTP
+			default:
+				pr.Log.Warnf("Skipping policy port with unsupported protocol: %s", *port.Protocol)
+				continue
 			}
 		}
 		// Port number/name
@@ -284,6 +289,11 @@ func (pr *PolicyReflector) portsToProto(ports []networkingV1.NetworkPolicyPort)
 				portProto.Port.Name = port.Port.StrVal
 			}
 		}
+		// End of the port range
+		if port.EndPort != nil && portProto.Port != nil &&
+			portProto.Port.Type == policy.Policy_Port_PortNameOrNumber_NUMBER {
+			portProto.EndPort = *port.EndPort
+		}
 		// append port
 		portsProto = append(portsProto, portProto)
 	}
@@ -297,10 +307,10 @@ func (pr *PolicyReflector) peersToProto(peers []networkingV1.NetworkPolicyPeer)
 		peerProto := &policy.Policy_Peer{}
 		if peer.PodSelector != nil {
 			// pod selector
-			peerProto.Pods = pr.labelSelectorToProto(peer.PodSelector)
+			peerProto.Pods = labelSelectorToProto(peer.PodSelector)
 		} else if peer.NamespaceSelector != nil {
 			// namespace selector
-			peerProto.Namespaces = pr.labelSelectorToProto(peer.NamespaceSelector)
+			peerProto.Namespaces = labelSelectorToProto(peer.NamespaceSelector)
 		} else if peer.IPBlock != nil {
 			// IP block
 			peerProto.IpBlock = &policy.Policy_Peer_IPBlock{}
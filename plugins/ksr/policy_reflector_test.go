@@ -70,6 +70,8 @@ func TestPolicyReflector(t *testing.T) {
 	}
 
 	var pprotTCP coreV1.Protocol = "TCP"
+	var pprotSCTP coreV1.Protocol = "SCTP"
+	var endPort int32 = 8080
 
 	policyTestVars.policyTestData = []networkingV1.NetworkPolicy{
 		// Test data 0: mocks a new object to be added or a "pre-existing"
@@ -100,6 +102,21 @@ func TestPolicyReflector(t *testing.T) {
 									IntVal: 6372,
 								},
 							},
+							{
+								Protocol: &pprotSCTP,
+								Port: &intstr.IntOrString{
+									Type:   intstr.Int,
+									IntVal: 3868,
+								},
+							},
+							{
+								Protocol: &pprotTCP,
+								Port: &intstr.IntOrString{
+									Type:   intstr.Int,
+									IntVal: 8000,
+								},
+								EndPort: &endPort,
+							},
 						},
 						From: []networkingV1.NetworkPolicyPeer{
 							{
@@ -969,6 +986,12 @@ func checkRulePorts(protoPorts []*policy.Policy_Port, k8sPorts []networkingV1.Ne
 			gomega.Panic()
 		}
 
+		if k8sPorts[j].EndPort == nil {
+			gomega.Expect(protoPort.EndPort).To(gomega.BeZero())
+		} else {
+			gomega.Expect(protoPort.EndPort).To(gomega.Equal(*k8sPorts[j].EndPort))
+		}
+
 		if k8sPorts[j].Protocol == nil {
 			gomega.Expect(protoPort.Protocol).To(gomega.BeNumerically("==", policy.Policy_Port_TCP))
 		} else {
@@ -115,6 +115,10 @@ func (rr *ReflectorRegistry) getStats() *ksrapi.Stats {
 			stats.PodStats = r.GetStats()
 		case policyObjType:
 			stats.PolicyStats = r.GetStats()
+		case clusterPolicyObjType:
+			stats.ClusterPolicyStats = r.GetStats()
+		case endpointSliceObjType:
+			stats.EndpointSliceStats = r.GetStats()
 		case serviceObjType:
 			stats.ServiceStats = r.GetStats()
 		case nodeObjType:
@@ -155,6 +155,7 @@ loop:
 
 	svcProto.Selector = svc.Spec.Selector
 	svcProto.ClusterIp = svc.Spec.ClusterIP
+	svcProto.ClusterIps = svc.Spec.ClusterIPs
 	svcProto.ServiceType = string(svc.Spec.Type)
 	svcProto.ExternalIps = svc.Spec.ExternalIPs
 	for _, lbIngress := range svc.Status.LoadBalancer.Ingress {
@@ -91,9 +91,10 @@ func TestServiceReflector(t *testing.T) {
 						},
 					},
 				},
-				Selector:  map[string]string{},
-				ClusterIP: "10.96.0.1",
-				Type:      "ClusterIP",
+				Selector:   map[string]string{},
+				ClusterIP:  "10.96.0.1",
+				ClusterIPs: []string{"10.96.0.1", "fd00:96::1"},
+				Type:       "ClusterIP",
 			},
 		},
 		// Test data 1: mocks an object that updates a "pre-existing" object
@@ -325,6 +326,7 @@ func testAddDeleteService(t *testing.T) {
 	gomega.Expect(svcProto.Name).To(gomega.Equal(svc.GetName()))
 	gomega.Expect(svcProto.Namespace).To(gomega.Equal(svc.GetNamespace()))
 	gomega.Expect(svcProto.ClusterIp).To(gomega.Equal(svc.Spec.ClusterIP))
+	gomega.Expect(svcProto.ClusterIps).To(gomega.Equal(svc.Spec.ClusterIPs))
 	gomega.Expect(len(svcProto.Selector)).To(gomega.Equal(len(svc.Spec.Selector)))
 	gomega.Expect(svcProto.ServiceType).Should(gomega.BeEquivalentTo(svc.Spec.Type))
 	gomega.Expect(svcProto.LoadbalancerIp).To(gomega.Equal(svc.Spec.LoadBalancerIP))
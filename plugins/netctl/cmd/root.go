@@ -28,6 +28,7 @@ import (
 var (
 	etcdConfig string
 	httpConfig string
+	ipamUsage  bool
 )
 
 func getClient() (client *remote.HTTPClient) {
@@ -101,9 +102,17 @@ var cmdVppCLI = &cobra.Command{
 var cmdNodeIPam = &cobra.Command{
 	Use:     "ipam <nodename>",
 	Short:   "Shows IPAM information for specified node.",
-	Example: "netctl ipam k8s-master",
+	Example: "netctl ipam k8s-master\nnetctl ipam --usage",
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if ipamUsage {
+			if len(args) < 1 {
+				cmdimpl.PrintAllIpamUsage(getClient(), getDb())
+			} else {
+				cmdimpl.NodeIPamUsageCmd(getClient(), getDb(), args[0])
+			}
+			return
+		}
 		if len(args) < 1 {
 			cmdimpl.PrintAllIpams(getClient(), getDb())
 		} else {
@@ -130,6 +139,23 @@ var cmdPodInfo = &cobra.Command{
 	},
 }
 
+var cmdPolicy = &cobra.Command{
+	Use:   "policy",
+	Short: "Inspect evaluation of network policies.",
+}
+
+var cmdPolicyExplain = &cobra.Command{
+	Use: "explain <src> <dst> <protocol>/<port>",
+	Short: "Explain why traffic between two pods (or a pod and an IP address) is allowed or denied " +
+		"by network policies. Pods are given as <namespace>/<pod> or <pod> from the default namespace.",
+	Example: "netctl policy explain default/frontend default/backend tcp/8080\n" +
+		"netctl policy explain frontend 8.8.8.8 udp/53",
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdimpl.PolicyExplainCmd(getClient(), getDb(), args[0], args[1], args[2])
+	},
+}
+
 // Execute will execute the command netctlcd
 func Execute() {
 	var rootCmd = &cobra.Command{Use: "netctl"}
@@ -141,9 +167,13 @@ func Execute() {
 	rootCmd.AddCommand(cmdVppDump)
 	rootCmd.AddCommand(cmdVppCLI)
 
+	cmdNodeIPam.Flags().BoolVar(&ipamUsage, "usage", false, "show utilization of IP address blocks")
 	rootCmd.AddCommand(cmdNodeIPam)
 	rootCmd.AddCommand(cmdPodInfo)
 
+	cmdPolicy.AddCommand(cmdPolicyExplain)
+	rootCmd.AddCommand(cmdPolicy)
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
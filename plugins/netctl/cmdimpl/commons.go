@@ -15,7 +15,9 @@
 package cmdimpl
 
 const (
-	kvschedulerDumpCmd = "scheduler/dump"
-	getIpamDataCmd     = "contiv/v1/ipam"
-	timeLayout         = "Mon Jan _2 15:04:05 2006"
+	kvschedulerDumpCmd  = "scheduler/dump"
+	getIpamDataCmd      = "contiv/v1/ipam"
+	getIpamUsageCmd     = "contiv/v1/ipam/usage"
+	getPolicyExplainCmd = "contiv/v1/policy/explain"
+	timeLayout          = "Mon Jan _2 15:04:05 2006"
 )
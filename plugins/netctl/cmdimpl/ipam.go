@@ -25,6 +25,7 @@ import (
 	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
 
 	"github.com/americanbinary/vpp/plugins/crd/cache/telemetrymodel"
+	ipamrestapi "github.com/americanbinary/vpp/plugins/ipam/restapi"
 	"github.com/americanbinary/vpp/plugins/ipnet"
 	"github.com/americanbinary/vpp/plugins/ipnet/restapi"
 	"github.com/americanbinary/vpp/plugins/netctl/remote"
@@ -98,3 +99,68 @@ func getTabWriterAndPrintHeader() *tabwriter.Writer {
 	fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tBVI-IP\tPOD-CIDR\tVPP-2-HOST-CIDR\tPOD-CLUSTER-CIDR\n")
 	return w
 }
+
+// PrintAllIpamUsage prints utilization of IP address blocks for all nodes
+func PrintAllIpamUsage(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) {
+	nodes := make([]string, 0)
+	for k := range getClusterNodeInfo(db) {
+		nodes = append(nodes, k)
+	}
+	sort.Strings(nodes)
+
+	w := getTabWriterAndPrintUsageHeader()
+	for _, n := range nodes {
+		nodeIpamUsageCmd(client, db, w, n)
+	}
+	w.Flush()
+}
+
+// NodeIPamUsageCmd prints out utilization of IP address blocks of a specific node
+func NodeIPamUsageCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName string) {
+	w := getTabWriterAndPrintUsageHeader()
+	nodeIpamUsageCmd(client, db, w, nodeName)
+	w.Flush()
+}
+
+func nodeIpamUsageCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, w *tabwriter.Writer, nodeName string) {
+	ip := resolveNodeOrIP(db, nodeName)
+
+	b, err := getNodeInfo(client, ip, getIpamUsageCmd)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	usage := ipamrestapi.NodeIPUsage{}
+	err = json.Unmarshal(b, &usage)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, block := range usage.Blocks {
+		network := block.Network
+		if network == "" {
+			network = "-"
+		}
+		alert := ""
+		if block.ThresholdExceeded {
+			alert = fmt.Sprintf(">= %d%%", usage.Threshold)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%.1f%%\t%s\n",
+			usage.NodeName,
+			network,
+			block.Type,
+			block.Subnet,
+			block.Allocated,
+			block.Capacity,
+			block.Utilization,
+			alert)
+	}
+}
+
+func getTabWriterAndPrintUsageHeader() *tabwriter.Writer {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "NODE-NAME\tNETWORK\tTYPE\tSUBNET\tALLOCATED\tCAPACITY\tUSAGE\tALERT\n")
+	return w
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
+
+	"github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	"github.com/americanbinary/vpp/plugins/netctl/remote"
+	"github.com/americanbinary/vpp/plugins/policy/restapi"
+)
+
+const defaultNamespace = "default"
+
+// explainEndpoint is a source or a destination of the explained traffic.
+type explainEndpoint struct {
+	ip  string
+	pod *pod.Pod // nil if the IP address does not belong to any pod
+}
+
+// PolicyExplainCmd explains why the traffic between <src> and <dst> is allowed
+// or denied by network policies. Endpoints are given either as "<namespace>/<pod>",
+// "<pod>" (from the default namespace) or as IP addresses, traffic is given as
+// "<protocol>/<port>" (e.g. tcp/80) or just "<protocol>" for protocols without ports.
+func PolicyExplainCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, src, dst, traffic string) {
+	pg := newPodGetter(client, db)
+	defer pg.db.Close()
+
+	srcEp, err := resolveExplainEndpoint(pg.pods, src)
+	if err != nil {
+		fmt.Printf("Invalid source: %v\n", err)
+		return
+	}
+	dstEp, err := resolveExplainEndpoint(pg.pods, dst)
+	if err != nil {
+		fmt.Printf("Invalid destination: %v\n", err)
+		return
+	}
+
+	query := url.Values{}
+	query.Set("src", srcEp.ip)
+	query.Set("dst", dstEp.ip)
+	protocolAndPort := strings.SplitN(traffic, "/", 2)
+	query.Set("protocol", protocolAndPort[0])
+	if len(protocolAndPort) == 2 {
+		query.Set("port", protocolAndPort[1])
+	}
+	cmd := getPolicyExplainCmd + "?" + query.Encode()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	allowed := true
+
+	// egress side is evaluated by the node of the source pod
+	fmt.Fprintf(w, "EGRESS (policies of the source %s):\n", srcEp)
+	if srcEp.pod != nil {
+		verdict, err := getPolicyVerdict(client, srcEp.pod.HostIpAddress, cmd, true)
+		if err != nil {
+			fmt.Fprintf(w, "Failed to get policy explanation: %v\n", err)
+			allowed = false
+		} else {
+			allowed = printPolicyVerdict(w, verdict) && allowed
+		}
+	} else {
+		fmt.Fprintf(w, "not evaluated - not a pod\n")
+	}
+	fmt.Fprintln(w)
+
+	// ingress side is evaluated by the node of the destination pod
+	fmt.Fprintf(w, "INGRESS (policies of the destination %s):\n", dstEp)
+	if dstEp.pod != nil {
+		verdict, err := getPolicyVerdict(client, dstEp.pod.HostIpAddress, cmd, false)
+		if err != nil {
+			fmt.Fprintf(w, "Failed to get policy explanation: %v\n", err)
+			allowed = false
+		} else {
+			allowed = printPolicyVerdict(w, verdict) && allowed
+		}
+	} else {
+		fmt.Fprintf(w, "not evaluated - not a pod\n")
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "FINAL VERDICT: %s\n", verdictString(allowed))
+	w.Flush()
+}
+
+// resolveExplainEndpoint resolves pod name or IP address into an endpoint.
+func resolveExplainEndpoint(pods []*pod.Pod, input string) (*explainEndpoint, error) {
+	if ip := net.ParseIP(input); ip != nil {
+		ep := &explainEndpoint{ip: ip.String()}
+		for _, podInfo := range pods {
+			if podHasIP(podInfo, ip) {
+				ep.pod = podInfo
+				break
+			}
+		}
+		return ep, nil
+	}
+
+	namespace, name := defaultNamespace, input
+	if nsAndName := strings.SplitN(input, "/", 2); len(nsAndName) == 2 {
+		namespace, name = nsAndName[0], nsAndName[1]
+	}
+	for _, podInfo := range pods {
+		if podInfo.Namespace == namespace && podInfo.Name == name {
+			if podInfo.IpAddress == "" {
+				return nil, fmt.Errorf("pod %s/%s has no IP address assigned", namespace, name)
+			}
+			return &explainEndpoint{ip: podInfo.IpAddress, pod: podInfo}, nil
+		}
+	}
+	return nil, fmt.Errorf("pod %s/%s not found", namespace, name)
+}
+
+// podHasIP returns true if the given IP address is assigned to the pod.
+func podHasIP(podInfo *pod.Pod, ip net.IP) bool {
+	if ip.Equal(net.ParseIP(podInfo.IpAddress)) {
+		return true
+	}
+	for _, podIP := range podInfo.IpAddresses {
+		if ip.Equal(net.ParseIP(podIP)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPolicyVerdict queries the given node for the egress or the ingress policy verdict.
+func getPolicyVerdict(client *remote.HTTPClient, hostIP, cmd string, egress bool) (*restapi.PolicyVerdict, error) {
+	b, err := getNodeInfo(client, hostIP, cmd)
+	if err != nil {
+		return nil, err
+	}
+	explanation := restapi.PolicyExplanation{}
+	if err := json.Unmarshal(b, &explanation); err != nil {
+		return nil, err
+	}
+	verdict := explanation.Ingress
+	if egress {
+		verdict = explanation.Egress
+	}
+	if verdict == nil {
+		return nil, fmt.Errorf("pod is not configured on the node %s", hostIP)
+	}
+	return verdict, nil
+}
+
+// printPolicyVerdict prints policies and rules of one side of the traffic,
+// returns the verdict.
+func printPolicyVerdict(w *tabwriter.Writer, verdict *restapi.PolicyVerdict) bool {
+	if len(verdict.Policies) == 0 {
+		fmt.Fprintf(w, "POLICIES:\tnone (not isolated)\n")
+	} else {
+		fmt.Fprintf(w, "POLICIES:\t%s\n", strings.Join(verdict.Policies, ", "))
+	}
+	if len(verdict.Rules) > 0 {
+		fmt.Fprintf(w, "ORDER\tACTION\tORIGIN\tRULE\n")
+		for idx, rule := range verdict.Rules {
+			origin := strings.Join(rule.Origins, ",")
+			if origin == "" {
+				origin = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", idx+1, rule.Action, origin, rule.Rule)
+		}
+	}
+	fmt.Fprintf(w, "VERDICT:\t%s\n", verdictString(verdict.Allowed))
+	return verdict.Allowed
+}
+
+func verdictString(allowed bool) string {
+	if allowed {
+		return "ALLOWED"
+	}
+	return "DENIED"
+}
+
+// String returns human-readable description of the endpoint.
+func (ep *explainEndpoint) String() string {
+	if ep.pod == nil {
+		return ep.ip
+	}
+	return fmt.Sprintf("pod %s/%s (%s) on node %s", ep.pod.Namespace, ep.pod.Name, ep.ip, ep.pod.HostIpAddress)
+}
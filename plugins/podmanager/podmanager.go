@@ -73,6 +73,15 @@ type Deps struct {
 	infra.PluginDeps
 	EventLoop controller.EventLoop
 	GRPC      grpc.Server
+
+	// Dependencies to be injected for unit testing or offline replay of events
+	// to replace the access to Docker and the CNI server with mocks.
+	*UnitTestDeps
+}
+
+// UnitTestDeps lists dependencies for unit testing.
+type UnitTestDeps struct {
+	DockerClient DockerClient
 }
 
 // DockerClient defines API of a Docker client needed by PodManager.
@@ -102,6 +111,12 @@ func (pm *PodManager) Init() (err error) {
 	pm.localPods = make(LocalPods)
 	pm.pods = make(Pods)
 
+	if pm.UnitTestDeps != nil {
+		// mock Docker client, CNI requests are not served
+		pm.dockerClient = pm.UnitTestDeps.DockerClient
+		return nil
+	}
+
 	// connect to Docker server
 	pm.dockerClient, err = docker.NewClientFromEnv()
 	if err != nil {
@@ -163,6 +178,30 @@ func (pm *PodManager) Resync(event controller.Event, kubeStateData controller.Ku
 		return nil
 	}
 
+	// re-construct the set of running pods
+	runningPods, err := pm.ListRunningPods()
+	if err != nil {
+		return controller.NewFatalError(err)
+	}
+	for podID, pod := range runningPods {
+		pm.localPods[podID] = pod
+		pm.Log.Debugf("Found locally running Pod: %+v", pod)
+	}
+
+	// fill-in pod metadata
+	for _, podProto := range kubeStateData[podmodel.PodKeyword] {
+		k8sPod := podProto.(*podmodel.Pod)
+		pm.updatePodInfo(k8sPod)
+	}
+
+	pm.Log.Debugf("PodManager state after resync: localPods=%s, pods=%s", pm.localPods.String(), pm.pods.String())
+	return nil
+}
+
+// ListRunningPods returns pods with a running sandbox container on this node,
+// as reported by Docker.
+// The method can be called from outside of the main event loop.
+func (pm *PodManager) ListRunningPods() (LocalPods, error) {
 	// list all sandbox containers
 	listOpts := docker.ListContainersOptions{
 		All: true,
@@ -172,11 +211,11 @@ func (pm *PodManager) Resync(event controller.Event, kubeStateData controller.Ku
 	}
 	containers, err := pm.dockerClient.ListContainers(listOpts)
 	if err != nil {
-		return controller.NewFatalError(
-			fmt.Errorf("failed to list sandbox containers: %v", err))
+		return nil, fmt.Errorf("failed to list sandbox containers: %v", err)
 	}
 
 	// inspect every sandbox to re-construct the pod metadata
+	runningPods := make(LocalPods)
 	for _, container := range containers {
 		if container.State != runningPodState {
 			pm.Log.Debugf("Ignoring non-running sandbox container: %v", container.ID)
@@ -204,22 +243,13 @@ func (pm *PodManager) Resync(event controller.Event, kubeStateData controller.Ku
 			continue
 		}
 		// add pod into the set of running pods
-		pm.localPods[podID] = &LocalPod{
+		runningPods[podID] = &LocalPod{
 			ID:               podID,
 			ContainerID:      container.ID,
 			NetworkNamespace: fmt.Sprintf("/proc/%d/ns/net", details.State.Pid),
 		}
-		pm.Log.Debugf("Found locally running Pod: %+v", pm.localPods[podID])
-	}
-
-	// fill-in pod metadata
-	for _, podProto := range kubeStateData[podmodel.PodKeyword] {
-		k8sPod := podProto.(*podmodel.Pod)
-		pm.updatePodInfo(k8sPod)
 	}
-
-	pm.Log.Debugf("PodManager state after resync: localPods=%s, pods=%s", pm.localPods.String(), pm.pods.String())
-	return nil
+	return runningPods, nil
 }
 
 // Update handles AddPod and DeletePod events.
@@ -242,7 +272,7 @@ func (pm *PodManager) Update(event controller.Event, _ controller.UpdateOperatio
 	}
 	if deletePod, isDeletePod := event.(*DeletePod); isDeletePod {
 		_, hasPod := pm.localPods[deletePod.Pod]
-		if !hasPod {
+		if !hasPod && !deletePod.Leaked {
 			pm.Log.Warnf("Unknown pod to delete: %v", deletePod.Pod)
 		} else {
 			delete(pm.localPods, deletePod.Pod)
@@ -326,6 +356,7 @@ func (pm *PodManager) updatePodInfo(k8sPod *podmodel.Pod) {
 	pm.pods[podID] = &Pod{
 		ID:          podID,
 		IPAddress:   k8sPod.IpAddress,
+		IPAddresses: k8sPod.IpAddresses,
 		Annotations: k8sPod.Annotations,
 		Labels:      k8sPod.Labels,
 	}
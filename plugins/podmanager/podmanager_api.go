@@ -36,6 +36,11 @@ type API interface {
 	// The method should be called only from within the main event loop
 	// (not thread safe) and not before the startup resync.
 	GetPods() Pods
+
+	// ListRunningPods returns pods with a running sandbox container on this node,
+	// as reported by the container runtime (regardless of the CNI requests received).
+	// The method can be called from outside of the main event loop.
+	ListRunningPods() (LocalPods, error)
 }
 
 // LocalPod represents a locally deployed pod (locally = on this node).
@@ -49,6 +54,7 @@ type LocalPod struct {
 type Pod struct {
 	ID          podmodel.ID
 	IPAddress   string
+	IPAddresses []string // all IP addresses of the pod (one per IP family with dual-stack)
 	Labels      map[string]string
 	Annotations map[string]string
 }
@@ -67,6 +73,10 @@ func (p *LocalPod) String() string {
 
 // String returns human-readable string representation of pod metadata.
 func (p *Pod) String() string {
+	if len(p.IPAddresses) > 1 {
+		return fmt.Sprintf("Pod <ID:%v, IPs:%v, Labels:%v, Annotations:%v>",
+			p.ID, p.IPAddresses, p.Labels, p.Annotations)
+	}
 	return fmt.Sprintf("Pod <ID:%v, IP:%v, Labels:%v, Annotations:%v>",
 		p.ID, p.IPAddress, p.Labels, p.Annotations)
 }
@@ -209,6 +219,38 @@ func (ev *AddPod) Wait() error {
 	return <-ev.result
 }
 
+// Priority is CNIPriority - the event blocks CNI request.
+func (ev *AddPod) Priority() controller.EventPriority {
+	return controller.CNIPriority
+}
+
+// Payload returns serializable content of the AddPod event (input arguments only).
+func (ev *AddPod) Payload() (*controller.EventPayload, error) {
+	payload := controller.NewEventPayload(AddPodPayloadType)
+	payload.SetArg(podNamePayloadArg, ev.Pod.Name)
+	payload.SetArg(podNamespacePayloadArg, ev.Pod.Namespace)
+	payload.SetArg(containerIDPayloadArg, ev.ContainerID)
+	payload.SetArg(netNamespacePayloadArg, ev.NetworkNamespace)
+	payload.SetArg(ipamTypePayloadArg, ev.IPAMType)
+	payload.SetArg(ipamDataPayloadArg, ev.IPAMData)
+	return payload, nil
+}
+
+// AddPodFromPayload re-creates AddPod event from the payload.
+func AddPodFromPayload(payload *controller.EventPayload) (*AddPod, error) {
+	return &AddPod{
+		Pod: podmodel.ID{
+			Name:      payload.GetArg(podNamePayloadArg),
+			Namespace: payload.GetArg(podNamespacePayloadArg),
+		},
+		ContainerID:      payload.GetArg(containerIDPayloadArg),
+		NetworkNamespace: payload.GetArg(netNamespacePayloadArg),
+		IPAMType:         payload.GetArg(ipamTypePayloadArg),
+		IPAMData:         payload.GetArg(ipamDataPayloadArg),
+		result:           make(chan error, 1),
+	}, nil
+}
+
 /****************************** Delete Pod Event ******************************/
 
 // DeletePod event is triggered when pod deployed on this node is being terminated.
@@ -216,6 +258,17 @@ type DeletePod struct {
 	result chan error
 
 	Pod podmodel.ID
+
+	// Leaked is true if the pod was found terminated without the CNI DEL request
+	// being received (by the garbage collection of leaked pod IPs).
+	Leaked bool
+
+	// ContainerID is the ID of the sandbox container of the leaked pod as known
+	// when the leak was detected (empty if the pod was not known locally).
+	ContainerID string
+
+	// localPods is used to re-validate the leak before the event is processed
+	localPods func() LocalPods
 }
 
 // NewDeletePodEvent is constructor for DeletePod event.
@@ -231,6 +284,19 @@ func NewDeletePodEvent(request *cni.CNIRequest) *DeletePod {
 	}
 }
 
+// NewDeletePodEventForLeakedPod is constructor for DeletePod event sent from within
+// the event loop for a pod which was found terminated without the CNI DEL request.
+// The event becomes obsolete if the pod is re-added (with a different sandbox
+// container) before the event gets processed.
+func NewDeletePodEventForLeakedPod(pod *LocalPod, podManager API) *DeletePod {
+	return &DeletePod{
+		Pod:         pod.ID,
+		Leaked:      true,
+		ContainerID: pod.ContainerID,
+		localPods:   podManager.GetLocalPods,
+	}
+}
+
 // GetName returns name of the DeletePod event.
 func (ev *DeletePod) GetName() string {
 	return fmt.Sprintf("Delete Pod %s", ev.Pod.String())
@@ -256,14 +322,26 @@ func (ev *DeletePod) Direction() controller.UpdateDirectionType {
 	return controller.Reverse
 }
 
-// IsBlocking returns true.
+// IsBlocking returns true unless the event was sent for a leaked pod.
 func (ev *DeletePod) IsBlocking() bool {
-	return true
+	return !ev.Leaked
+}
+
+// IsObsolete returns true if the event was sent for a leaked pod which has been
+// re-added since.
+func (ev *DeletePod) IsObsolete() bool {
+	if !ev.Leaked || ev.localPods == nil {
+		return false
+	}
+	pod, isLocal := ev.localPods()[ev.Pod]
+	return isLocal && pod.ContainerID != ev.ContainerID
 }
 
 // Done propagates error to the event producer.
 func (ev *DeletePod) Done(err error) {
-	ev.result <- err
+	if ev.result != nil {
+		ev.result <- err
+	}
 	return
 }
 
@@ -271,3 +349,52 @@ func (ev *DeletePod) Done(err error) {
 func (ev *DeletePod) Wait() error {
 	return <-ev.result
 }
+
+// Priority is CNIPriority - the event blocks CNI request.
+func (ev *DeletePod) Priority() controller.EventPriority {
+	return controller.CNIPriority
+}
+
+// Payload returns serializable content of the DeletePod event.
+func (ev *DeletePod) Payload() (*controller.EventPayload, error) {
+	payload := controller.NewEventPayload(DeletePodPayloadType)
+	payload.SetArg(podNamePayloadArg, ev.Pod.Name)
+	payload.SetArg(podNamespacePayloadArg, ev.Pod.Namespace)
+	if ev.Leaked {
+		payload.SetArg(leakedPayloadArg, "true")
+		payload.SetArg(containerIDPayloadArg, ev.ContainerID)
+	}
+	return payload, nil
+}
+
+// DeletePodFromPayload re-creates DeletePod event from the payload.
+func DeletePodFromPayload(payload *controller.EventPayload) (*DeletePod, error) {
+	return &DeletePod{
+		Pod: podmodel.ID{
+			Name:      payload.GetArg(podNamePayloadArg),
+			Namespace: payload.GetArg(podNamespacePayloadArg),
+		},
+		Leaked:      payload.GetArg(leakedPayloadArg) == "true",
+		ContainerID: payload.GetArg(containerIDPayloadArg),
+		result:      make(chan error, 1),
+	}, nil
+}
+
+/******************************* Event Payloads *******************************/
+
+const (
+	// AddPodPayloadType identifies payload of the AddPod event.
+	AddPodPayloadType = "AddPod"
+
+	// DeletePodPayloadType identifies payload of the DeletePod event.
+	DeletePodPayloadType = "DeletePod"
+
+	// labels used in the payloads of the podmanager events
+	podNamePayloadArg      = "pod-name"
+	podNamespacePayloadArg = "pod-namespace"
+	containerIDPayloadArg  = "container-id"
+	netNamespacePayloadArg = "network-namespace"
+	ipamTypePayloadArg     = "ipam-type"
+	ipamDataPayloadArg     = "ipam-data"
+	leakedPayloadArg       = "leaked"
+)
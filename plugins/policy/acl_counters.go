@@ -0,0 +1,90 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+
+	"git.fd.io/govpp.git/adapter"
+	govpp "git.fd.io/govpp.git/api"
+
+	"go.ligato.io/vpp-agent/v3/plugins/vpp/aclplugin/aclidx"
+	acl_api "go.ligato.io/vpp-agent/v3/plugins/vpp/binapi/vpp1908/acl"
+)
+
+// GoVPP is the interface of govppmux plugin replicated here to avoid direct
+// dependency on vppapiclient.h.
+type GoVPP interface {
+	// NewAPIChannel returns a new API channel for communication with VPP via govpp.
+	NewAPIChannel() (govpp.Channel, error)
+
+	// DumpStats returns entries of the VPP stats segment matching the given patterns.
+	DumpStats(patterns ...string) ([]adapter.StatEntry, error)
+}
+
+// VPPACLPlugin is the interface of aclplugin from vpp-agent replicated here
+// to translate ACL names into VPP indexes.
+type VPPACLPlugin interface {
+	// GetACLIndex returns the index of ACLs installed into VPP.
+	GetACLIndex() aclidx.ACLMetadataIndex
+}
+
+// aclRuleHits reads hit counters of ACL rules from the VPP stats segment.
+type aclRuleHits struct {
+	goVPP     GoVPP
+	aclPlugin VPPACLPlugin
+}
+
+// EnableCounters enables or disables counting of matches for rules of ACLs
+// applied on interfaces.
+func (h *aclRuleHits) EnableCounters(enable bool) error {
+	goVppCh, err := h.goVPP.NewAPIChannel()
+	if err != nil {
+		return err
+	}
+	defer goVppCh.Close()
+	req := &acl_api.ACLStatsIntfCountersEnable{Enable: enable}
+	reply := &acl_api.ACLStatsIntfCountersEnableReply{}
+	return goVppCh.SendRequest(req).ReceiveReply(reply)
+}
+
+// GetACLRuleHits returns the number of packets matched by each rule of the given
+// ACL, summed over all VPP threads.
+func (h *aclRuleHits) GetACLRuleHits(aclName string) (hits []uint64, err error) {
+	aclMeta, found := h.aclPlugin.GetACLIndex().LookupByName(aclName)
+	if !found {
+		return nil, fmt.Errorf("ACL %s is not installed", aclName)
+	}
+	entries, err := h.goVPP.DumpStats(fmt.Sprintf("^/acl/%d/matches$", aclMeta.Index))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("missing hit counters for ACL %s", aclName)
+	}
+	counters, isCombined := entries[0].Data.(adapter.CombinedCounterStat)
+	if !isCombined {
+		return nil, fmt.Errorf("unexpected type of hit counters for ACL %s", aclName)
+	}
+	for _, threadCounters := range counters {
+		for ruleIdx, counter := range threadCounters {
+			if ruleIdx >= len(hits) {
+				hits = append(hits, make([]uint64, ruleIdx-len(hits)+1)...)
+			}
+			hits[ruleIdx] += uint64(counter.Packets())
+		}
+	}
+	return hits, nil
+}
@@ -73,6 +73,23 @@ type PolicyCacheAPI interface {
 	// ListAllPolicies returns IDs of all policies.
 	ListAllPolicies() (policies []policymodel.ID)
 
+	// LookupClusterPolicy returns data of a given cluster policy.
+	LookupClusterPolicy(name string) (found bool, data *policymodel.ClusterPolicy)
+
+	// LookupClusterPoliciesByPod returns names of all cluster policies assigned
+	// to a given pod.
+	LookupClusterPoliciesByPod(pod podmodel.ID) (policies []string)
+
+	// ListAllClusterPolicies returns names of all cluster policies.
+	ListAllClusterPolicies() (policies []string)
+
+	// LookupPodsByClusterSelector evaluates namespace and pod label selectors
+	// of a cluster policy (or of its peer) and returns IDs of the matching pods.
+	// Unset (or empty) namespace selector matches all namespaces (including
+	// kube-system), unset (or empty) pod selector matches all pods from the selected
+	// namespaces.
+	LookupPodsByClusterSelector(nsSelector, podSelector *policymodel.Policy_LabelSelector) (pods []podmodel.ID)
+
 	// LookupNamespace returns data of a given namespace.
 	LookupNamespace(namespace nsmodel.ID) (found bool, data *nsmodel.Namespace)
 
@@ -104,6 +121,18 @@ type PolicyCacheWatcher interface {
 	// modified.
 	UpdatePolicy(oldPolicy, newPolicy *policymodel.Policy) error
 
+	// AddClusterPolicy is called by Policy Cache when a new cluster policy
+	// is created.
+	AddClusterPolicy(policy *policymodel.ClusterPolicy) error
+
+	// DelClusterPolicy is called by Policy Cache after a cluster policy
+	// was removed.
+	DelClusterPolicy(policy *policymodel.ClusterPolicy) error
+
+	// UpdateClusterPolicy is called by Policy Cache when data of a cluster
+	// policy were modified.
+	UpdateClusterPolicy(oldPolicy, newPolicy *policymodel.ClusterPolicy) error
+
 	// AddNamespace is called by Policy Cache when a new namespace is created.
 	AddNamespace(ns *nsmodel.Namespace) error
 
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"sort"
+
 	"go.ligato.io/cn-infra/v2/logging"
 
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
@@ -27,6 +29,7 @@ type PolicyCache struct {
 	configuredPolicies   *policyidx.ConfigIndex
 	configuredPods       *podidx.ConfigIndex
 	configuredNamespaces *namespaceidx.ConfigIndex
+	clusterPolicies      map[string]*policymodel.ClusterPolicy // cluster policy name -> data
 	watchers             []PolicyCacheWatcher
 }
 
@@ -48,6 +51,7 @@ func (pc *PolicyCache) reset() {
 	pc.configuredPolicies = policyidx.NewConfigIndex(pc.Log, "policies")
 	pc.configuredPods = podidx.NewConfigIndex(pc.Log, "pods")
 	pc.configuredNamespaces = namespaceidx.NewConfigIndex(pc.Log, "namespaces")
+	pc.clusterPolicies = make(map[string]*policymodel.ClusterPolicy)
 }
 
 // Update processes a K8s state data change event.
@@ -232,3 +236,65 @@ func (pc *PolicyCache) ListAllNamespaces() (namespaces []nsmodel.ID) {
 
 	return namespaces
 }
+
+// LookupClusterPolicy returns data of a given cluster policy.
+func (pc *PolicyCache) LookupClusterPolicy(name string) (found bool, data *policymodel.ClusterPolicy) {
+	data, found = pc.clusterPolicies[name]
+	return found, data
+}
+
+// LookupClusterPoliciesByPod returns names of all cluster policies assigned
+// to a given pod.
+func (pc *PolicyCache) LookupClusterPoliciesByPod(pod podmodel.ID) (policies []string) {
+	for _, name := range pc.ListAllClusterPolicies() {
+		policy := pc.clusterPolicies[name]
+		for _, podID := range pc.LookupPodsByClusterSelector(policy.Namespaces, policy.Pods) {
+			if podID == pod {
+				policies = append(policies, name)
+				break
+			}
+		}
+	}
+	return policies
+}
+
+// ListAllClusterPolicies returns names of all cluster policies (sorted).
+func (pc *PolicyCache) ListAllClusterPolicies() (policies []string) {
+	for name := range pc.clusterPolicies {
+		policies = append(policies, name)
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// LookupPodsByClusterSelector evaluates namespace and pod label selectors
+// of a cluster policy (or of its peer) and returns IDs of the matching pods.
+func (pc *PolicyCache) LookupPodsByClusterSelector(nsSelector,
+	podSelector *policymodel.Policy_LabelSelector) (pods []podmodel.ID) {
+
+	// Unlike with K8s policies, empty namespace selector includes kube-system.
+	var nsPods []string
+	if isEmptySelector(nsSelector) {
+		nsPods = pc.configuredPods.ListAll()
+	} else {
+		nsPods = utils.StringPodID(pc.LookupPodsByNsLabelSelector(nsSelector))
+	}
+	if isEmptySelector(podSelector) {
+		return utils.UnstringPodID(nsPods)
+	}
+
+	// evaluate pod selector inside every selected namespace
+	namespaces := make(map[string]struct{})
+	for _, podID := range utils.UnstringPodID(nsPods) {
+		namespaces[podID.Namespace] = struct{}{}
+	}
+	for namespace := range namespaces {
+		pods = append(pods, pc.LookupPodsByLabelSelectorInsideNs(namespace, podSelector)...)
+	}
+	return pods
+}
+
+// isEmptySelector returns true if the label selector is not set or has no requirements.
+func isEmptySelector(selector *policymodel.Policy_LabelSelector) bool {
+	return selector == nil || (len(selector.MatchLabel) == 0 && len(selector.MatchExpression) == 0)
+}
@@ -142,6 +142,41 @@ func (pc *PolicyCache) changePropagateEvent(kubeStateChange *controller.KubeStat
 				}
 			}
 		}
+
+	case policymodel.ClusterPolicyKeyword:
+		if kubeStateChange.PrevValue == nil {
+			// add cluster policy
+			policy := kubeStateChange.NewValue.(*policymodel.ClusterPolicy)
+			pc.clusterPolicies[policy.Name] = policy
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.AddClusterPolicy(policy); err != nil {
+					return err
+				}
+			}
+		} else if kubeStateChange.NewValue == nil {
+			// delete cluster policy
+			oldPolicy := kubeStateChange.PrevValue.(*policymodel.ClusterPolicy)
+			delete(pc.clusterPolicies, oldPolicy.Name)
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.DelClusterPolicy(oldPolicy); err != nil {
+					return err
+				}
+			}
+		} else {
+			// update cluster policy
+			oldPolicy := kubeStateChange.PrevValue.(*policymodel.ClusterPolicy)
+			newPolicy := kubeStateChange.NewValue.(*policymodel.ClusterPolicy)
+			delete(pc.clusterPolicies, oldPolicy.Name)
+			pc.clusterPolicies[newPolicy.Name] = newPolicy
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.UpdateClusterPolicy(oldPolicy, newPolicy); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	return nil
@@ -28,6 +28,8 @@ type DataResyncEvent struct {
 	Namespaces []*namespacemodel.Namespace
 	Pods       []*podmodel.Pod
 	Policies   []*policymodel.Policy
+
+	ClusterPolicies []*policymodel.ClusterPolicy
 }
 
 // NewDataResyncEvent creates an empty instance of DataResyncEvent.
@@ -36,6 +38,8 @@ func NewDataResyncEvent() *DataResyncEvent {
 		Namespaces: []*namespacemodel.Namespace{},
 		Pods:       []*podmodel.Pod{},
 		Policies:   []*policymodel.Policy{},
+
+		ClusterPolicies: []*policymodel.ClusterPolicy{},
 	}
 }
 
@@ -67,5 +71,12 @@ func (pc *PolicyCache) resyncParseEvent(kubeStateData controller.KubeStateData)
 		policyID := policymodel.GetID(policy).String()
 		pc.configuredPolicies.RegisterPolicy(policyID, policy)
 	}
+
+	// collect cluster policies
+	for _, policyProto := range kubeStateData[policymodel.ClusterPolicyKeyword] {
+		policy := policyProto.(*policymodel.ClusterPolicy)
+		event.ClusterPolicies = append(event.ClusterPolicies, policy)
+		pc.clusterPolicies[policy.Name] = policy
+	}
 	return event
 }
@@ -0,0 +1,60 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+const (
+	// by default, audit events are appended to this file
+	defaultAuditLogFile = "/var/log/contiv/policy-audit.log"
+
+	// by default, the audit log file is rotated once it reaches 16MB
+	defaultAuditLogMaxSize = 16
+
+	// by default, the policy audit is disabled
+	defaultAuditPeriod = 0
+
+	// by default, the last 1000 audit events are available via the REST API
+	defaultAuditBufferSize = 1000
+)
+
+// Config holds the Policy plugin configuration.
+type Config struct {
+	// file to write the policy audit events into, empty to disable
+	AuditLogFile string `json:"auditLogFile"`
+
+	// maximum size (in MB) of the audit log file, the file is rotated once
+	// the size is reached (only the last rotated file is kept), 0 = unlimited
+	AuditLogMaxSize uint32 `json:"auditLogMaxSize"`
+
+	// period (in seconds) of reading the ACL hit counters for the policy audit,
+	// 0 to disable the audit
+	AuditPeriod uint32 `json:"auditPeriod"`
+
+	// number of recent policy audit events available via the REST API
+	AuditBufferSize uint32 `json:"auditBufferSize"`
+
+	// export the number of packets matched by the rules of every policy as metrics
+	// (enables counting of the ACL rule hits in VPP, independently of the audit)
+	PolicyHitMetrics bool `json:"policyHitMetrics"`
+}
+
+// DefaultConfig returns configuration for policy plugin with default values.
+func DefaultConfig() *Config {
+	return &Config{
+		AuditLogFile:    defaultAuditLogFile,
+		AuditLogMaxSize: defaultAuditLogMaxSize,
+		AuditPeriod:     defaultAuditPeriod,
+		AuditBufferSize: defaultAuditBufferSize,
+	}
+}
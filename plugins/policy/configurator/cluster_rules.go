@@ -0,0 +1,252 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package configurator
+
+import (
+	"net"
+	"sort"
+
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+)
+
+// ruleGroup is a list of rules sharing the same action, evaluated as one unit.
+// Every match of a cluster policy translates to one group.
+type ruleGroup struct {
+	tier      uint32
+	namespace bool // rules of namespace-scoped policies, evaluated after all the tiers
+	action    MatchAction
+	rules     []*renderer.ContivRule
+}
+
+// generateClusterRules generates a list of ingress or egress rules implementing
+// a given list of cluster policies. Rules are returned in the order of evaluation,
+// with precedence decreasing towards the end of the list (but always above 0,
+// which is the precedence of the namespace rules).
+//
+// The Pass action is resolved statically: traffic matched by a pass-match
+// is intersected with all the matches of the subsequent tiers and finally
+// with the rules of namespace-scoped policies (<nsRules>), so that every
+// generated rule either allows or denies the traffic.
+func (pct *PolicyConfiguratorTxn) generateClusterRules(direction MatchType, policies ContivPolicies,
+	nsRules *ContivRules) (rules []*renderer.ContivRule) {
+
+	// Order cluster policies by tier, priority and name.
+	policies = policies.Copy()
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Tier != policies[j].Tier {
+			return policies[i].Tier < policies[j].Tier
+		}
+		if policies[i].Priority != policies[j].Priority {
+			return policies[i].Priority < policies[j].Priority
+		}
+		return policies[i].ID.Name < policies[j].ID.Name
+	})
+
+	var groups []*ruleGroup
+	for _, policy := range policies {
+		if !policyAppliesTo(policy, direction) {
+			continue
+		}
+		ruleIdx := 0
+		for _, match := range policy.Matches {
+			if match.Type != direction {
+				continue
+			}
+			group := &ruleGroup{
+				tier:   policy.Tier,
+				action: match.Action,
+				rules:  pct.generateMatchRules(direction, match, matchActionToRule(match.Action)),
+			}
+			setRuleOrigin(group.rules, policy, direction, ruleIdx)
+			ruleIdx++
+			if len(group.rules) > 0 {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	// Resolve Pass actions starting from the last group, so that the traffic
+	// passed to the next tier is evaluated against already resolved groups.
+	evaluated := namespaceRuleGroups(nsRules)
+	for i := len(groups) - 1; i >= 0; i-- {
+		group := groups[i]
+		if group.action != MatchPass {
+			evaluated = append([]*ruleGroup{group}, evaluated...)
+			continue
+		}
+		// skip the remaining groups of the same tier
+		next := 0
+		for next < len(evaluated) && !evaluated[next].namespace && evaluated[next].tier == group.tier {
+			next++
+		}
+		var passed []*ruleGroup
+		for _, nextGroup := range evaluated[next:] {
+			passedGroup := &ruleGroup{tier: group.tier, action: nextGroup.action}
+			for _, passRule := range group.rules {
+				for _, nextRule := range nextGroup.rules {
+					if rule := intersectRules(passRule, nextRule); rule != nil {
+						passedGroup.rules = append(passedGroup.rules, rule)
+					}
+				}
+			}
+			if len(passedGroup.rules) > 0 {
+				passed = append(passed, passedGroup)
+			}
+		}
+		evaluated = append(passed, evaluated...)
+	}
+
+	// Assign precedence to the rules of cluster policies.
+	var clusterGroups []*ruleGroup
+	for _, group := range evaluated {
+		if !group.namespace {
+			clusterGroups = append(clusterGroups, group)
+		}
+	}
+	for idx, group := range clusterGroups {
+		precedence := uint32(len(clusterGroups) - idx)
+		for _, rule := range group.rules {
+			ruleCopy := rule.Copy()
+			ruleCopy.Action = matchActionToRule(group.action)
+			ruleCopy.Precedence = precedence
+			rules = append(rules, ruleCopy)
+		}
+	}
+	return rules
+}
+
+// namespaceRuleGroups splits rules of namespace-scoped policies into a group
+// of allowed and a group of denied traffic. Without namespace rules, all traffic
+// is allowed.
+func namespaceRuleGroups(nsRules *ContivRules) []*ruleGroup {
+	if len(nsRules.rules) == 0 {
+		return []*ruleGroup{{
+			namespace: true,
+			action:    MatchAllow,
+			rules: []*renderer.ContivRule{{
+				Action:      renderer.ActionPermit,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				Protocol:    renderer.ANY,
+			}},
+		}}
+	}
+	allowed := &ruleGroup{namespace: true, action: MatchAllow}
+	denied := &ruleGroup{namespace: true, action: MatchDeny}
+	for _, rule := range nsRules.rules {
+		if rule.Action == renderer.ActionPermit {
+			allowed.rules = append(allowed.rules, rule)
+		} else {
+			denied.rules = append(denied.rules, rule)
+		}
+	}
+	return []*ruleGroup{allowed, denied}
+}
+
+// matchActionToRule returns rule action implementing the given match action.
+// Pass is resolved by the configurator, the returned action is only a placeholder.
+func matchActionToRule(action MatchAction) renderer.ActionType {
+	if action == MatchDeny {
+		return renderer.ActionDeny
+	}
+	return renderer.ActionPermit
+}
+
+// intersectRules returns rule matching the traffic matched by both rules,
+// or nil if the intersection is empty. The action and the origins are taken
+// from <rule2>.
+func intersectRules(rule1, rule2 *renderer.ContivRule) *renderer.ContivRule {
+	srcNetwork, nonEmpty := intersectNetworks(rule1.SrcNetwork, rule2.SrcNetwork)
+	if !nonEmpty {
+		return nil
+	}
+	destNetwork, nonEmpty := intersectNetworks(rule1.DestNetwork, rule2.DestNetwork)
+	if !nonEmpty {
+		return nil
+	}
+	rule := &renderer.ContivRule{
+		Action:      rule2.Action,
+		SrcNetwork:  srcNetwork,
+		DestNetwork: destNetwork,
+		Origins:     rule2.Origins,
+	}
+
+	// L4
+	switch {
+	case rule1.Protocol == renderer.ANY:
+		rule.Protocol, rule.ProtocolNumber = rule2.Protocol, rule2.ProtocolNumber
+		rule.DestPort, rule.DestPortEnd = rule2.DestPort, rule2.DestPortEnd
+		return rule
+	case rule2.Protocol == renderer.ANY:
+		rule.Protocol, rule.ProtocolNumber = rule1.Protocol, rule1.ProtocolNumber
+		rule.DestPort, rule.DestPortEnd = rule1.DestPort, rule1.DestPortEnd
+		return rule
+	case rule1.Protocol != rule2.Protocol:
+		return nil
+	case rule1.Protocol == renderer.OTHER:
+		if rule1.ProtocolNumber != rule2.ProtocolNumber {
+			return nil
+		}
+		rule.Protocol, rule.ProtocolNumber = renderer.OTHER, rule1.ProtocolNumber
+		return rule
+	}
+	rule.Protocol = rule1.Protocol
+	lower1, upper1 := rule1.DestPortRange()
+	lower2, upper2 := rule2.DestPortRange()
+	if lower1 == 0 {
+		lower1, upper1 = lower2, upper2
+	} else if lower2 != 0 {
+		if lower2 > lower1 {
+			lower1 = lower2
+		}
+		if upper2 < upper1 {
+			upper1 = upper2
+		}
+		if lower1 > upper1 {
+			return nil
+		}
+	}
+	rule.DestPort = lower1
+	if upper1 > lower1 {
+		rule.DestPortEnd = upper1
+	}
+	return rule
+}
+
+// intersectNetworks returns intersection of two IP networks, where empty network
+// matches all IP addresses. Since both arguments are prefixes, the intersection
+// is either one of them or empty (nonEmpty=false).
+func intersectNetworks(net1, net2 *net.IPNet) (intersection *net.IPNet, nonEmpty bool) {
+	if len(net1.IP) == 0 {
+		return net2, true
+	}
+	if len(net2.IP) == 0 {
+		return net1, true
+	}
+	if (net1.IP.To4() == nil) != (net2.IP.To4() == nil) {
+		return nil, false
+	}
+	ones1, _ := net1.Mask.Size()
+	ones2, _ := net2.Mask.Size()
+	if ones1 <= ones2 && net1.Contains(net2.IP) {
+		return net2, true
+	}
+	if ones2 <= ones1 && net2.Contains(net1.IP) {
+		return net1, true
+	}
+	return nil, false
+}
@@ -76,13 +76,25 @@ type Txn interface {
 // Traffic matched by a Contiv policy should by ALLOWED. Traffic not matched
 // by any policy from a **non-empty** set of policies assigned
 // to the source/destination pod should be DENIED.
+//
+// Cluster policies (Cluster=true) are evaluated ahead of all namespace-scoped
+// policies, ordered by Tier, then Priority (lower value first) and finally
+// by name. Their matches carry explicit actions (see MatchAction).
 type ContivPolicy struct {
 	// ID should uniquely identify policy across all namespaces.
+	// For cluster policies, namespace is empty.
 	ID policymodel.ID
 
 	// Type selects the rule types that the network policy relates to.
 	Type PolicyType
 
+	// Cluster is true for cluster-scoped policies.
+	Cluster bool
+
+	// Tier and Priority define the order of evaluation of cluster policies.
+	Tier     uint32
+	Priority uint32
+
 	// Matches is an array of Match-es: predicates that select a subset of the
 	// traffic to be ALLOWED.
 	Matches []Match
@@ -97,6 +109,10 @@ func (cp ContivPolicy) String() string {
 			matches += ", "
 		}
 	}
+	if cp.Cluster {
+		return fmt.Sprintf("ContivPolicy %s <Cluster, Tier:%d, Priority:%d, Type:%s, Matches:[%s]>",
+			cp.ID.Name, cp.Tier, cp.Priority, cp.Type, matches)
+	}
 	return fmt.Sprintf("ContivPolicy %s <Type:%s, Matches:[%s]>",
 		cp.ID, cp.Type, matches)
 }
@@ -120,6 +136,10 @@ type Match struct {
 	// If the array is non-empty, then this applies to a given traffic only
 	// if the traffic matches at least one port in the list.
 	Ports []Port
+
+	// Action to take for the matched traffic.
+	// Only cluster policies may use other action than MatchAllow.
+	Action MatchAction
 }
 
 // String converts Match into a human-readable string.
@@ -159,6 +179,10 @@ func (m Match) String() string {
 		}
 		ports += "]"
 	}
+	if m.Action != MatchAllow {
+		return fmt.Sprintf("<Type:%s, Action:%s, Pods:%s, Blocks:%s, Ports:%s>",
+			m.Type, m.Action, pods, blocks, ports)
+	}
 	return fmt.Sprintf("<Type:%s, Pods:%s, Blocks:%s, Ports:%s>",
 		m.Type, pods, blocks, ports)
 }
@@ -214,7 +238,36 @@ func (mt MatchType) String() string {
 	return "INVALID"
 }
 
-// ProtocolType is either TCP or UDP.
+// MatchAction is the action to take for the traffic selected by a Match.
+type MatchAction int
+
+const (
+	// MatchAllow allows the matched traffic.
+	MatchAllow MatchAction = iota
+
+	// MatchDeny denies the matched traffic (cluster policies only).
+	MatchDeny
+
+	// MatchPass skips the remaining cluster policies of the same tier,
+	// the matched traffic is evaluated by the next tier or, after the last
+	// tier, by namespace-scoped policies (cluster policies only).
+	MatchPass
+)
+
+// String converts MatchAction into a human-readable string.
+func (ma MatchAction) String() string {
+	switch ma {
+	case MatchAllow:
+		return "ALLOW"
+	case MatchDeny:
+		return "DENY"
+	case MatchPass:
+		return "PASS"
+	}
+	return "INVALID"
+}
+
+// ProtocolType is either TCP, UDP, SCTP or OTHER.
 type ProtocolType int
 
 const (
@@ -223,6 +276,12 @@ const (
 
 	// UDP protocol.
 	UDP
+
+	// SCTP protocol.
+	SCTP
+
+	// OTHER is an IP protocol given by number (Port.ProtocolNumber).
+	OTHER
 )
 
 // String converts ProtocolType into a human-readable string.
@@ -232,22 +291,36 @@ func (pt ProtocolType) String() string {
 		return "TCP"
 	case UDP:
 		return "UDP"
+	case SCTP:
+		return "SCTP"
+	case OTHER:
+		return "OTHER"
 	}
 	return "INVALID"
 }
 
-// Port represent a TCP or UDP port.
+// Port represent a TCP, UDP or SCTP port (or a range of ports), or an IP protocol
+// without ports (OTHER).
 // Number=0 represents all ports for a given protocol.
 type Port struct {
-	Protocol ProtocolType
-	Number   uint16
+	Protocol       ProtocolType
+	ProtocolNumber uint8 // IP protocol number, used only with OTHER
+	Number         uint16
+	EndNumber      uint16 // end of the port range (inclusive), 0 = single port
 }
 
 // String return a human-readable string representation of the Port.
 func (port Port) String() string {
+	if port.Protocol == OTHER {
+		return port.Protocol.String() + "(" + strconv.Itoa(int(port.ProtocolNumber)) + ")"
+	}
 	if port.Number == 0 {
 		return port.Protocol.String() + ":ANY"
 	}
+	if port.EndNumber > port.Number {
+		return port.Protocol.String() + ":" + strconv.Itoa(int(port.Number)) +
+			"-" + strconv.Itoa(int(port.EndNumber))
+	}
 	return port.Protocol.String() + ":" + strconv.Itoa(int(port.Number))
 }
 
@@ -19,6 +19,7 @@ package configurator
 import (
 	"net"
 	"sort"
+	"sync"
 
 	"go.ligato.io/cn-infra/v2/logging"
 
@@ -42,6 +43,10 @@ type PolicyConfigurator struct {
 	renderers         []renderer.PolicyRendererAPI
 	parallelRendering bool
 	podIPAddresses    PodIPAddresses
+
+	// rules generated for configured pods, used to explain policy decisions
+	podRulesLock sync.Mutex
+	podRules     map[podmodel.ID]*PodRules
 }
 
 // Deps lists dependencies of PolicyConfigurator.
@@ -66,6 +71,7 @@ type PolicyConfiguratorTxn struct {
 	resync         bool
 	config         map[podmodel.ID]ContivPolicies // config to render
 	podIPAddresses PodIPAddresses
+	podRules       map[podmodel.ID]*PodRules // changed pod rules, nil for removed pods
 }
 
 // ContivPolicies is a list of policies that can be ordered by policy ID.
@@ -94,6 +100,7 @@ func (pc *PolicyConfigurator) Init(parallelRendering bool) error {
 	pc.renderers = []renderer.PolicyRendererAPI{}
 	pc.parallelRendering = parallelRendering
 	pc.podIPAddresses = make(PodIPAddresses)
+	pc.podRules = make(map[podmodel.ID]*PodRules)
 	return nil
 }
 
@@ -121,6 +128,7 @@ func (pc *PolicyConfigurator) NewTxn(resync bool) Txn {
 		configurator: pc,
 		resync:       resync,
 		config:       make(map[podmodel.ID]ContivPolicies),
+		podRules:     make(map[podmodel.ID]*PodRules),
 	}
 	if resync {
 		txn.podIPAddresses = make(PodIPAddresses)
@@ -153,7 +161,10 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 	for pod, unorderedPolicies := range pct.config {
 		ingress := &ContivRules{}
 		egress := &ContivRules{}
-		var delPodConfig bool
+		var (
+			delPodConfig bool
+			policies     ContivPolicies
+		)
 
 		// Get target pod configuration.
 		podIPNet, hadIPAddr := pct.podIPAddresses[pod]
@@ -181,7 +192,7 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 			pct.podIPAddresses[pod] = podIPNet
 
 			// Sort policies to get the same outcome for the same set.
-			policies := unorderedPolicies.Copy()
+			policies = unorderedPolicies.Copy()
 			sort.Sort(policies)
 
 			// Check if this set was already processed.
@@ -221,6 +232,18 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 		for _, rTxn := range rendererTxns {
 			rTxn.Render(pod, podIPNet, ingress.CopySlice(), egress.CopySlice(), delPodConfig)
 		}
+
+		// Remember the rules for explaining.
+		pct.podRules[pod] = nil
+		if !delPodConfig {
+			pct.podRules[pod] = &PodRules{
+				Pod:      pod,
+				PodIP:    podIPNet,
+				Policies: policies,
+				Ingress:  ingress.CopySlice(),
+				Egress:   egress.CopySlice(),
+			}
+		}
 	}
 
 	// Commit all renderer transactions.
@@ -250,6 +273,7 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 
 	// Save changes to the configurator.
 	pct.configurator.podIPAddresses = pct.podIPAddresses.Copy()
+	pct.configurator.savePodRules(pct.resync, pct.podRules)
 
 	return wasError
 }
@@ -261,183 +285,59 @@ type PeerPod struct {
 }
 
 // Generate a list of ingress or egress rules implementing a given list of policies.
+// Rules of cluster policies (if any) are generated ahead of the rules of namespace
+// policies, with a higher precedence.
 func (pct *PolicyConfiguratorTxn) generateRules(direction MatchType, policies ContivPolicies) *ContivRules {
+	var nsPolicies, clusterPolicies ContivPolicies
+	for _, policy := range policies {
+		if policy.Cluster {
+			clusterPolicies = append(clusterPolicies, policy)
+		} else {
+			nsPolicies = append(nsPolicies, policy)
+		}
+	}
+
+	nsRules := pct.generateNamespaceRules(direction, nsPolicies)
+	if len(clusterPolicies) == 0 {
+		return nsRules
+	}
+	rules := &ContivRules{}
+	for _, rule := range pct.generateClusterRules(direction, clusterPolicies, nsRules) {
+		rules.Insert(rule)
+	}
+	for _, rule := range nsRules.rules {
+		rules.Insert(rule)
+	}
+	return rules
+}
+
+// generateNamespaceRules generates a list of ingress or egress rules implementing
+// a given list of namespace-scoped policies.
+func (pct *PolicyConfiguratorTxn) generateNamespaceRules(direction MatchType, policies ContivPolicies) *ContivRules {
 	rules := &ContivRules{}
 	hasPolicy := false
 	allAllowed := false
 
 	for _, policy := range policies {
-		if (policy.Type == PolicyIngress && direction == MatchEgress) ||
-			(policy.Type == PolicyEgress && direction == MatchIngress) {
+		if !policyAppliesTo(policy, direction) {
 			// Policy does not apply to this direction.
 			continue
 		}
 		hasPolicy = true
 
+		ruleIdx := 0
 		for _, match := range policy.Matches {
 			if match.Type != direction {
 				continue
 			}
-
-			// Collect IP addresses of all pod peers.
-			peers := []PeerPod{}
-			for _, peer := range match.Pods {
-				found, peerData := pct.configurator.Cache.LookupPod(peer)
-				if !found {
-					pct.Log.WithField("peer", peer).Warn("Peer pod data not found in the cache")
-					continue
-				}
-				if peerData.IpAddress == "" {
-					pct.Log.WithField("peer", peer).Debug("Peer pod has no IP address assigned")
-					continue
-				}
-				peerIPNet := utils.GetOneHostSubnet(peerData.IpAddress)
-				if peerIPNet == nil {
-					pct.Log.WithFields(logging.Fields{
-						"peer": peer,
-						"ip":   peerData.IpAddress}).Warn("Peer pod has invalid IP address assigned")
-					continue
-				}
-				peers = append(peers, PeerPod{ID: peer, IPNet: peerIPNet})
-			}
-
-			// Collect all subnets from IPBlocks.
-			allSubnets := []*net.IPNet{}
-			for _, block := range match.IPBlocks {
-				subnets := []*net.IPNet{&block.Network}
-				for _, except := range block.Except {
-					subtracted := []*net.IPNet{}
-					for _, subnet := range subnets {
-						subtracted = append(subtracted, subtractSubnet(subnet, &except)...)
-					}
-					subnets = subtracted
-				}
-				allSubnets = append(allSubnets, subnets...)
-			}
-
-			// Handle undefined set of pods and IP blocks.
-			// = match anything on L3
-			if match.Pods == nil && match.IPBlocks == nil {
-				if len(match.Ports) == 0 {
-					// = match anything on L3 & L4
-					ruleAny := &renderer.ContivRule{
-						Action:      renderer.ActionPermit,
-						SrcNetwork:  &net.IPNet{},
-						DestNetwork: &net.IPNet{},
-						Protocol:    renderer.ANY,
-						SrcPort:     0,
-						DestPort:    0,
-					}
-					rules.Insert(ruleAny)
-					allAllowed = true
-				} else {
-					// = match by L4
-					for _, port := range match.Ports {
-						rule := &renderer.ContivRule{
-							Action:      renderer.ActionPermit,
-							SrcNetwork:  &net.IPNet{},
-							DestNetwork: &net.IPNet{},
-							SrcPort:     0,
-							DestPort:    port.Number,
-						}
-						if port.Protocol == TCP {
-							rule.Protocol = renderer.TCP
-						} else {
-							rule.Protocol = renderer.UDP
-						}
-						rules.Insert(rule)
-					}
-				}
-			}
-
-			// Combine pod peers with ports.
-			for _, peer := range peers {
-				if len(match.Ports) == 0 {
-					// Match all ports.
-					// = match by L3
-					ruleAny := &renderer.ContivRule{
-						Action:      renderer.ActionPermit,
-						Protocol:    renderer.ANY,
-						SrcNetwork:  &net.IPNet{},
-						DestNetwork: &net.IPNet{},
-						SrcPort:     0,
-						DestPort:    0,
-					}
-					if direction == MatchIngress {
-						ruleAny.SrcNetwork = peer.IPNet
-					} else {
-						ruleAny.DestNetwork = peer.IPNet
-					}
-					rules.Insert(ruleAny)
-				} else {
-					// Combine each port with the peer.
-					// = match by L3 & L4
-					for _, port := range match.Ports {
-						rule := &renderer.ContivRule{
-							Action:      renderer.ActionPermit,
-							SrcNetwork:  &net.IPNet{},
-							DestNetwork: &net.IPNet{},
-							SrcPort:     0,
-							DestPort:    port.Number,
-						}
-						if direction == MatchIngress {
-							rule.SrcNetwork = peer.IPNet
-						} else {
-							rule.DestNetwork = peer.IPNet
-						}
-						if port.Protocol == TCP {
-							rule.Protocol = renderer.TCP
-						} else {
-							rule.Protocol = renderer.UDP
-						}
-						rules.Insert(rule)
-					}
-				}
+			matchRules := pct.generateMatchRules(direction, match, renderer.ActionPermit)
+			setRuleOrigin(matchRules, policy, direction, ruleIdx)
+			ruleIdx++
+			for _, rule := range matchRules {
+				rules.Insert(rule)
 			}
-
-			// Combine IPBlocks with ports.
-			for _, subnet := range allSubnets {
-				if len(match.Ports) == 0 {
-					// Handle IPBlock with no ports.
-					// = match by L3
-					ruleAny := &renderer.ContivRule{
-						Action:      renderer.ActionPermit,
-						Protocol:    renderer.ANY,
-						SrcNetwork:  &net.IPNet{},
-						DestNetwork: &net.IPNet{},
-						SrcPort:     0,
-						DestPort:    0,
-					}
-					if direction == MatchIngress {
-						ruleAny.SrcNetwork = subnet
-					} else {
-						ruleAny.DestNetwork = subnet
-					}
-					rules.Insert(ruleAny)
-				} else {
-					// Combine each port with the block.
-					// = match by L3 & L4
-					for _, port := range match.Ports {
-						rule := &renderer.ContivRule{
-							Action:      renderer.ActionPermit,
-							SrcNetwork:  &net.IPNet{},
-							DestNetwork: &net.IPNet{},
-							SrcPort:     0,
-							DestPort:    port.Number,
-						}
-						if direction == MatchIngress {
-							rule.SrcNetwork = subnet
-						} else {
-							rule.DestNetwork = subnet
-						}
-						if port.Protocol == TCP {
-							rule.Protocol = renderer.TCP
-						} else {
-							rule.Protocol = renderer.UDP
-						}
-						rules.Insert(rule)
-					}
-				}
+			if match.Pods == nil && match.IPBlocks == nil && len(match.Ports) == 0 {
+				allAllowed = true
 			}
 		}
 	}
@@ -471,6 +371,174 @@ func (pct *PolicyConfiguratorTxn) generateRules(direction MatchType, policies Co
 	return rules
 }
 
+// generateMatchRules generates rules with the given action implementing
+// a single match.
+func (pct *PolicyConfiguratorTxn) generateMatchRules(direction MatchType, match Match,
+	action renderer.ActionType) (rules []*renderer.ContivRule) {
+
+	// Collect IP addresses of all pod peers.
+	peers := []PeerPod{}
+	for _, peer := range match.Pods {
+		found, peerData := pct.configurator.Cache.LookupPod(peer)
+		if !found {
+			pct.Log.WithField("peer", peer).Warn("Peer pod data not found in the cache")
+			continue
+		}
+		if peerData.IpAddress == "" {
+			pct.Log.WithField("peer", peer).Debug("Peer pod has no IP address assigned")
+			continue
+		}
+		peerIPNet := utils.GetOneHostSubnet(peerData.IpAddress)
+		if peerIPNet == nil {
+			pct.Log.WithFields(logging.Fields{
+				"peer": peer,
+				"ip":   peerData.IpAddress}).Warn("Peer pod has invalid IP address assigned")
+			continue
+		}
+		peers = append(peers, PeerPod{ID: peer, IPNet: peerIPNet})
+
+		// with dual-stack, the peer is also reachable via the secondary IP address
+		for _, peerIP := range peerData.IpAddresses {
+			if peerIP == peerData.IpAddress {
+				continue
+			}
+			if peerIPNet := utils.GetOneHostSubnet(peerIP); peerIPNet != nil {
+				peers = append(peers, PeerPod{ID: peer, IPNet: peerIPNet})
+			}
+		}
+	}
+
+	// Collect all subnets from IPBlocks.
+	allSubnets := []*net.IPNet{}
+	for _, block := range match.IPBlocks {
+		subnets := []*net.IPNet{&block.Network}
+		for _, except := range block.Except {
+			subtracted := []*net.IPNet{}
+			for _, subnet := range subnets {
+				subtracted = append(subtracted, subtractSubnet(subnet, &except)...)
+			}
+			subnets = subtracted
+		}
+		allSubnets = append(allSubnets, subnets...)
+	}
+
+	// Handle undefined set of pods and IP blocks.
+	// = match anything on L3
+	if match.Pods == nil && match.IPBlocks == nil {
+		if len(match.Ports) == 0 {
+			// = match anything on L3 & L4
+			ruleAny := &renderer.ContivRule{
+				Action:      action,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				Protocol:    renderer.ANY,
+				SrcPort:     0,
+				DestPort:    0,
+			}
+			rules = append(rules, ruleAny)
+		} else {
+			// = match by L4
+			for _, port := range match.Ports {
+				rule := &renderer.ContivRule{
+					Action:      action,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					SrcPort:     0,
+					DestPort:    port.Number,
+					DestPortEnd: port.EndNumber,
+				}
+				setRuleProtocol(rule, port)
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	// Combine pod peers with ports.
+	for _, peer := range peers {
+		if len(match.Ports) == 0 {
+			// Match all ports.
+			// = match by L3
+			ruleAny := &renderer.ContivRule{
+				Action:      action,
+				Protocol:    renderer.ANY,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				SrcPort:     0,
+				DestPort:    0,
+			}
+			if direction == MatchIngress {
+				ruleAny.SrcNetwork = peer.IPNet
+			} else {
+				ruleAny.DestNetwork = peer.IPNet
+			}
+			rules = append(rules, ruleAny)
+		} else {
+			// Combine each port with the peer.
+			// = match by L3 & L4
+			for _, port := range match.Ports {
+				rule := &renderer.ContivRule{
+					Action:      action,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					SrcPort:     0,
+					DestPort:    port.Number,
+					DestPortEnd: port.EndNumber,
+				}
+				if direction == MatchIngress {
+					rule.SrcNetwork = peer.IPNet
+				} else {
+					rule.DestNetwork = peer.IPNet
+				}
+				setRuleProtocol(rule, port)
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	// Combine IPBlocks with ports.
+	for _, subnet := range allSubnets {
+		if len(match.Ports) == 0 {
+			// Handle IPBlock with no ports.
+			// = match by L3
+			ruleAny := &renderer.ContivRule{
+				Action:      action,
+				Protocol:    renderer.ANY,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				SrcPort:     0,
+				DestPort:    0,
+			}
+			if direction == MatchIngress {
+				ruleAny.SrcNetwork = subnet
+			} else {
+				ruleAny.DestNetwork = subnet
+			}
+			rules = append(rules, ruleAny)
+		} else {
+			// Combine each port with the block.
+			// = match by L3 & L4
+			for _, port := range match.Ports {
+				rule := &renderer.ContivRule{
+					Action:      action,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					SrcPort:     0,
+					DestPort:    port.Number,
+					DestPortEnd: port.EndNumber,
+				}
+				if direction == MatchIngress {
+					rule.SrcNetwork = subnet
+				} else {
+					rule.DestNetwork = subnet
+				}
+				setRuleProtocol(rule, port)
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
 // Copy creates a shallow copy of ContivPolicies.
 func (cp ContivPolicies) Copy() ContivPolicies {
 	cpCopy := make(ContivPolicies, len(cp))
@@ -516,7 +584,7 @@ func (cp ContivPolicies) Less(i, j int) bool {
 
 // Insert inserts the rule into the list.
 // Returns *true* if the rule was inserted, *false* if the same rule is already
-// in the list.
+// in the list (origins of the rule are then added to the existing one).
 func (cr *ContivRules) Insert(rule *renderer.ContivRule) bool {
 	// get the index at which the rule should be inserted to keep the order
 	idx := sort.Search(len(cr.orderedRules),
@@ -524,6 +592,8 @@ func (cr *ContivRules) Insert(rule *renderer.ContivRule) bool {
 			return rule.Compare(cr.orderedRules[i]) <= 0
 		})
 	if idx < len(cr.orderedRules) && rule.Compare(cr.orderedRules[idx]) == 0 {
+		// keep track of all policy rules the duplicate rule was derived from
+		cr.orderedRules[idx].AddOrigins(rule.Origins)
 		return false
 	}
 
@@ -592,3 +662,46 @@ func subtractSubnet(net1, net2 *net.IPNet) []*net.IPNet {
 
 	return result
 }
+
+// policyAppliesTo returns true if the policy relates to the given direction.
+func policyAppliesTo(policy *ContivPolicy, direction MatchType) bool {
+	return !(policy.Type == PolicyIngress && direction == MatchEgress) &&
+		!(policy.Type == PolicyEgress && direction == MatchIngress)
+}
+
+// originPolicyName returns the name of the policy as used in the origins of rules.
+func originPolicyName(policy *ContivPolicy) string {
+	if policy.Cluster {
+		return policy.ID.Name
+	}
+	return policy.ID.String()
+}
+
+// setRuleOrigin tags rules generated for the given rule of the policy with their origin.
+func setRuleOrigin(rules []*renderer.ContivRule, policy *ContivPolicy, direction MatchType, ruleIdx int) {
+	origin := &renderer.RuleOrigin{
+		Policy:    originPolicyName(policy),
+		Egress:    direction == MatchEgress,
+		RuleIndex: ruleIdx,
+	}
+	for _, rule := range rules {
+		rule.Origins = []*renderer.RuleOrigin{origin}
+	}
+}
+
+// setRuleProtocol sets L4 protocol of the rule to match the given port.
+func setRuleProtocol(rule *renderer.ContivRule, port Port) {
+	switch port.Protocol {
+	case TCP:
+		rule.Protocol = renderer.TCP
+	case UDP:
+		rule.Protocol = renderer.UDP
+	case SCTP:
+		rule.Protocol = renderer.SCTP
+	case OTHER:
+		rule.Protocol = renderer.OTHER
+		rule.ProtocolNumber = port.ProtocolNumber
+		rule.DestPort = 0 // not applicable
+		rule.DestPortEnd = 0
+	}
+}
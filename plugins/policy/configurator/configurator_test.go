@@ -1750,3 +1750,327 @@ func TestMultiplePodsSpecialCases(t *testing.T) {
 		parseIP(natLoopbackIP), parseIP(pod1IP), rendererAPI.OTHER, 0, 0)
 	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
 }
+
+func TestClusterPoliciesSinglePod(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestClusterPoliciesSinglePod")
+
+	// Prepare input data.
+	const (
+		namespace  = "default"
+		pod1Name   = "pod1"
+		pod2Name   = "pod2"
+		pod1IP     = "192.168.1.1"
+		pod2IP     = "192.168.1.2"
+		metadataIP = "169.254.169.254"
+		externalIP = "8.8.8.8"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+
+	// namespace policy: allow only HTTP towards pod2
+	policy1 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "policy1", Namespace: namespace},
+		Type: PolicyEgress,
+		Matches: []Match{
+			{
+				Type:  MatchEgress,
+				Pods:  []podmodel.ID{pod2},
+				Ports: []Port{{Protocol: TCP, Number: 80}},
+			},
+		},
+	}
+	// tier 0: pass traffic towards pod2 to the next tier, then deny metadata
+	// and always allow DNS
+	passPod2 := &ContivPolicy{
+		ID:       policymodel.ID{Name: "pass-pod2"},
+		Type:     PolicyEgress,
+		Cluster:  true,
+		Priority: 0,
+		Matches: []Match{
+			{
+				Type:     MatchEgress,
+				Action:   MatchPass,
+				Pods:     []podmodel.ID{pod2},
+				IPBlocks: []IPBlock{},
+			},
+		},
+	}
+	denyMetadata := &ContivPolicy{
+		ID:       policymodel.ID{Name: "deny-metadata"},
+		Type:     PolicyEgress,
+		Cluster:  true,
+		Priority: 1,
+		Matches: []Match{
+			{
+				Type:     MatchEgress,
+				Action:   MatchDeny,
+				Pods:     []podmodel.ID{},
+				IPBlocks: []IPBlock{{Network: parseIPNet(metadataIP + "/32")}},
+			},
+		},
+	}
+	allowDNS := &ContivPolicy{
+		ID:       policymodel.ID{Name: "allow-dns"},
+		Type:     PolicyEgress,
+		Cluster:  true,
+		Priority: 2,
+		Matches: []Match{
+			{
+				Type:   MatchEgress,
+				Action: MatchAllow,
+				Ports:  []Port{{Protocol: UDP, Number: 53}},
+			},
+		},
+	}
+	// tier 1: deny SSH
+	denySSH := &ContivPolicy{
+		ID:      policymodel.ID{Name: "deny-ssh"},
+		Type:    PolicyEgress,
+		Cluster: true,
+		Tier:    1,
+		Matches: []Match{
+			{
+				Type:   MatchEgress,
+				Action: MatchDeny,
+				Ports:  []Port{{Protocol: TCP, Number: 22}},
+			},
+		},
+	}
+	pod1Policies := []*ContivPolicy{policy1, denySSH, allowDNS, denyMetadata, passPod2}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	renderer := NewMockRenderer("A", logger)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register one renderer.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, pod1Policies)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Test with fake traffic.
+
+	// Denied by deny-metadata even for DNS (lower priority value goes first).
+	action := renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(metadataIP), rendererAPI.UDP, 123, 53)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Allowed by allow-dns, overriding the namespace policy.
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(externalIP), rendererAPI.UDP, 123, 53)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Passed by pass-pod2, then allowed by policy1.
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(pod2IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Passed by pass-pod2 (skipping allow-dns), then blocked by policy1.
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(pod2IP), rendererAPI.UDP, 123, 53)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Passed by pass-pod2, then denied by deny-ssh from the next tier.
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(pod2IP), rendererAPI.TCP, 123, 22)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Blocked by policy1.
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(externalIP), rendererAPI.TCP, 123, 443)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Not covered by any policy.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 22)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(UnmatchedTraffic))
+}
+
+func TestExplain(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestExplain")
+
+	// Prepare input data.
+	const (
+		namespace  = "default"
+		pod1Name   = "pod1"
+		pod2Name   = "pod2"
+		pod1IP     = "192.168.1.1"
+		pod2IP     = "192.168.1.2"
+		metadataIP = "169.254.169.254"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+
+	// pod1 may access only HTTP of pod2, but never the metadata
+	egressPolicy := &ContivPolicy{
+		ID:   policymodel.ID{Name: "egress-policy", Namespace: namespace},
+		Type: PolicyEgress,
+		Matches: []Match{
+			{
+				Type:  MatchEgress,
+				Pods:  []podmodel.ID{pod2},
+				Ports: []Port{{Protocol: TCP, Number: 80}},
+			},
+		},
+	}
+	denyMetadata := &ContivPolicy{
+		ID:      policymodel.ID{Name: "deny-metadata"},
+		Type:    PolicyEgress,
+		Cluster: true,
+		Matches: []Match{
+			{
+				Type:     MatchEgress,
+				Action:   MatchDeny,
+				Pods:     []podmodel.ID{},
+				IPBlocks: []IPBlock{{Network: parseIPNet(metadataIP + "/32")}},
+			},
+		},
+	}
+	// pod2 accepts HTTP from pod1 (the second rule is a duplicate)
+	ingressPolicy := &ContivPolicy{
+		ID:   policymodel.ID{Name: "ingress-policy", Namespace: namespace},
+		Type: PolicyIngress,
+		Matches: []Match{
+			{
+				Type:  MatchIngress,
+				Pods:  []podmodel.ID{pod1},
+				Ports: []Port{{Protocol: TCP, Number: 80}},
+			},
+			{
+				Type:  MatchIngress,
+				Pods:  []podmodel.ID{pod1},
+				Ports: []Port{{Protocol: TCP, Number: 80}},
+			},
+		},
+	}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+	err := configurator.RegisterRenderer(NewMockRenderer("A", logger))
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, []*ContivPolicy{egressPolicy, denyMetadata})
+	txn.Configure(pod2, []*ContivPolicy{ingressPolicy})
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Allowed by both sides.
+	egress, ingress := configurator.Explain(Traffic{
+		SrcIP:    net.ParseIP(pod1IP),
+		DstIP:    net.ParseIP(pod2IP),
+		Protocol: rendererAPI.TCP,
+		DestPort: 80,
+	})
+	gomega.Expect(egress).ToNot(gomega.BeNil())
+	gomega.Expect(egress.Pod).To(gomega.BeEquivalentTo(pod1))
+	gomega.Expect(egress.Direction).To(gomega.BeEquivalentTo(MatchEgress))
+	gomega.Expect(egress.Policies).To(gomega.HaveLen(1))
+	gomega.Expect(egress.Policies[0].ID.Name).To(gomega.BeEquivalentTo("egress-policy"))
+	gomega.Expect(egress.Allowed).To(gomega.BeTrue())
+	gomega.Expect(egress.Rules[0].OriginStrings()).To(gomega.Equal([]string{"default/egress-policy:egress[0]"}))
+	gomega.Expect(ingress).ToNot(gomega.BeNil())
+	gomega.Expect(ingress.Pod).To(gomega.BeEquivalentTo(pod2))
+	gomega.Expect(ingress.Direction).To(gomega.BeEquivalentTo(MatchIngress))
+	gomega.Expect(ingress.Policies).To(gomega.HaveLen(1))
+	gomega.Expect(ingress.Allowed).To(gomega.BeTrue())
+	gomega.Expect(ingress.Rules[0].OriginStrings()).To(gomega.Equal([]string{
+		"default/ingress-policy:ingress[0]", "default/ingress-policy:ingress[1]"}))
+
+	// Denied by both sides, by the rules isolating the pods.
+	egress, ingress = configurator.Explain(Traffic{
+		SrcIP:    net.ParseIP(pod1IP),
+		DstIP:    net.ParseIP(pod2IP),
+		Protocol: rendererAPI.TCP,
+		DestPort: 22,
+	})
+	gomega.Expect(egress.Allowed).To(gomega.BeFalse())
+	gomega.Expect(egress.Rules[0].Origins).To(gomega.BeEmpty())
+	gomega.Expect(egress.Policies).To(gomega.HaveLen(2)) /* both policies isolate pod1 */
+	gomega.Expect(egress.Policies[0].ID.Name).To(gomega.BeEquivalentTo("deny-metadata"))
+	gomega.Expect(ingress.Allowed).To(gomega.BeFalse())
+	gomega.Expect(ingress.Rules[0].Origins).To(gomega.BeEmpty())
+	gomega.Expect(ingress.Policies).To(gomega.HaveLen(1))
+
+	// Denied by the cluster policy, the destination is not a pod.
+	egress, ingress = configurator.Explain(Traffic{
+		SrcIP:    net.ParseIP(pod1IP),
+		DstIP:    net.ParseIP(metadataIP),
+		Protocol: rendererAPI.TCP,
+		DestPort: 80,
+	})
+	gomega.Expect(egress.Allowed).To(gomega.BeFalse())
+	gomega.Expect(egress.Rules[0].OriginStrings()).To(gomega.Equal([]string{"deny-metadata:egress[0]"}))
+	gomega.Expect(egress.Policies).To(gomega.HaveLen(1))
+	gomega.Expect(egress.Policies[0].ID.Name).To(gomega.BeEquivalentTo("deny-metadata"))
+	gomega.Expect(ingress).To(gomega.BeNil())
+
+	// Traffic in the opposite direction is not restricted by pod1 ingress.
+	egress, ingress = configurator.Explain(Traffic{
+		SrcIP:    net.ParseIP(pod2IP),
+		DstIP:    net.ParseIP(pod1IP),
+		Protocol: rendererAPI.UDP,
+		DestPort: 53,
+	})
+	gomega.Expect(egress.Policies).To(gomega.BeEmpty())
+	gomega.Expect(egress.Rules).To(gomega.BeEmpty())
+	gomega.Expect(egress.Allowed).To(gomega.BeTrue())
+	gomega.Expect(ingress.Policies).To(gomega.BeEmpty())
+	gomega.Expect(ingress.Allowed).To(gomega.BeTrue())
+
+	// Removed pod is no longer explained.
+	cache.AddPodConfig(pod2, "")
+	txn = configurator.NewTxn(false)
+	txn.Configure(pod2, []*ContivPolicy{})
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	_, ingress = configurator.Explain(Traffic{
+		SrcIP:    net.ParseIP(pod1IP),
+		DstIP:    net.ParseIP(pod2IP),
+		Protocol: rendererAPI.TCP,
+		DestPort: 80,
+	})
+	gomega.Expect(ingress).To(gomega.BeNil())
+}
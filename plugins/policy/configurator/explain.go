@@ -0,0 +1,205 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package configurator
+
+import (
+	"net"
+	"sort"
+
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+)
+
+// PodRules stores the rules generated for a pod by the last commit.
+type PodRules struct {
+	Pod      podmodel.ID
+	PodIP    *net.IPNet
+	Policies ContivPolicies         // ordered by ID
+	Ingress  []*renderer.ContivRule // traffic from the pod (vswitch point of view)
+	Egress   []*renderer.ContivRule // traffic to the pod (vswitch point of view)
+}
+
+// Traffic describes the first packet of a connection to explain the policy
+// decision for.
+type Traffic struct {
+	SrcIP          net.IP
+	DstIP          net.IP
+	Protocol       renderer.ProtocolType // ANY is not allowed
+	ProtocolNumber uint8                 // used only with OTHER
+	DestPort       uint16                // 0 for protocols without ports
+}
+
+// Explanation describes how the policies of a pod apply to the given traffic.
+type Explanation struct {
+	// Pod with the policies applied.
+	Pod podmodel.ID
+
+	// Direction of the traffic from the pod point of view.
+	Direction MatchType
+
+	// Policies the matching rules were derived from, in the order of evaluation
+	// (cluster policies first). If the traffic is denied by the rule isolating
+	// the pod (not derived from any policy), all the policies applied to the pod
+	// for the given direction are listed - none of them allows the traffic.
+	Policies ContivPolicies
+
+	// Rules matching the traffic, in the order of evaluation.
+	// The first rule decides, the rest is listed only for information.
+	Rules []*renderer.ContivRule
+
+	// Allowed is the final verdict. Traffic of pods without policies is allowed.
+	Allowed bool
+}
+
+// Explain evaluates the rules generated for the source and the destination pod
+// of the given traffic. Explanation is nil for a side which is not a pod configured
+// by this configurator (e.g. pod deployed on another node).
+func (pc *PolicyConfigurator) Explain(traffic Traffic) (egress, ingress *Explanation) {
+	pc.podRulesLock.Lock()
+	defer pc.podRulesLock.Unlock()
+
+	if srcPod := pc.lookupPodRulesByIP(traffic.SrcIP); srcPod != nil {
+		egress = explainPodRules(srcPod, MatchEgress, traffic)
+	}
+	if dstPod := pc.lookupPodRulesByIP(traffic.DstIP); dstPod != nil {
+		ingress = explainPodRules(dstPod, MatchIngress, traffic)
+	}
+	return egress, ingress
+}
+
+// savePodRules saves rules changed by a transaction.
+func (pc *PolicyConfigurator) savePodRules(resync bool, changes map[podmodel.ID]*PodRules) {
+	pc.podRulesLock.Lock()
+	defer pc.podRulesLock.Unlock()
+
+	if resync {
+		pc.podRules = make(map[podmodel.ID]*PodRules)
+	}
+	for pod, rules := range changes {
+		if rules == nil {
+			delete(pc.podRules, pod)
+			continue
+		}
+		pc.podRules[pod] = rules
+	}
+}
+
+// lookupPodRulesByIP returns rules of the pod with the given IP address
+// (including secondary addresses of dual-stack pods).
+func (pc *PolicyConfigurator) lookupPodRulesByIP(ip net.IP) *PodRules {
+	for pod, rules := range pc.podRules {
+		if rules.PodIP.IP.Equal(ip) {
+			return rules
+		}
+		found, podData := pc.Cache.LookupPod(pod)
+		if !found {
+			continue
+		}
+		for _, podIP := range podData.IpAddresses {
+			if ip.Equal(net.ParseIP(podIP)) {
+				return rules
+			}
+		}
+	}
+	return nil
+}
+
+// explainPodRules evaluates rules of the given pod for the traffic in the given
+// direction (from the pod point of view).
+func explainPodRules(podRules *PodRules, direction MatchType, traffic Traffic) *Explanation {
+	explanation := &Explanation{
+		Pod:       podRules.Pod,
+		Direction: direction,
+		Allowed:   true,
+	}
+
+	// Direction in policies is from the pod point of view, whereas rules
+	// are evaluated from the vswitch perspective.
+	rules := podRules.Egress
+	if direction == MatchEgress {
+		rules = podRules.Ingress
+	}
+
+	// Rules with higher precedence go first and, among the rules of the same
+	// precedence, PERMIT rules go before DENY rules (see ContivRule semantics).
+	ordered := make([]*renderer.ContivRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Precedence != ordered[j].Precedence {
+			return ordered[i].Precedence > ordered[j].Precedence
+		}
+		return ordered[i].Action == renderer.ActionPermit && ordered[j].Action == renderer.ActionDeny
+	})
+	origins := make(map[string]struct{}) // policies of the matching rules
+	for _, rule := range ordered {
+		if ruleMatchesTraffic(rule, traffic) {
+			explanation.Rules = append(explanation.Rules, rule)
+			for _, origin := range rule.Origins {
+				origins[origin.Policy] = struct{}{}
+			}
+		}
+	}
+	if len(explanation.Rules) > 0 {
+		explanation.Allowed = explanation.Rules[0].Action == renderer.ActionPermit
+	}
+	isolated := len(explanation.Rules) > 0 && len(explanation.Rules[0].Origins) == 0
+
+	// cluster policies are evaluated first, ordered by tier, priority and name
+	for _, policy := range podRules.Policies {
+		if !policyAppliesTo(policy, direction) {
+			continue
+		}
+		if _, hasMatchingRule := origins[originPolicyName(policy)]; hasMatchingRule || isolated {
+			explanation.Policies = append(explanation.Policies, policy)
+		}
+	}
+	sort.SliceStable(explanation.Policies, func(i, j int) bool {
+		policy1, policy2 := explanation.Policies[i], explanation.Policies[j]
+		if policy1.Cluster != policy2.Cluster {
+			return policy1.Cluster
+		}
+		if policy1.Tier != policy2.Tier {
+			return policy1.Tier < policy2.Tier
+		}
+		if policy1.Priority != policy2.Priority {
+			return policy1.Priority < policy2.Priority
+		}
+		return policy1.Cluster && policy1.ID.Name < policy2.ID.Name
+	})
+	return explanation
+}
+
+// ruleMatchesTraffic returns true if the rule matches the given traffic.
+func ruleMatchesTraffic(rule *renderer.ContivRule, traffic Traffic) bool {
+	if len(rule.SrcNetwork.IP) > 0 && !rule.SrcNetwork.Contains(traffic.SrcIP) {
+		return false
+	}
+	if len(rule.DestNetwork.IP) > 0 && !rule.DestNetwork.Contains(traffic.DstIP) {
+		return false
+	}
+	switch rule.Protocol {
+	case renderer.ANY:
+		return true
+	case renderer.OTHER:
+		return traffic.Protocol == renderer.OTHER && rule.ProtocolNumber == traffic.ProtocolNumber
+	}
+	if rule.Protocol != traffic.Protocol {
+		return false
+	}
+	lower, upper := rule.DestPortRange()
+	return lower == 0 || (traffic.DestPort >= lower && traffic.DestPort <= upper)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/americanbinary/vpp/plugins/policy/renderer/acl"
+)
+
+const (
+	// gauge with the number of packets matched by the rules of a policy
+	policyRuleHitsMetric = "policy_rule_hits"
+
+	nodeLabel      = "node"
+	namespaceLabel = "namespace"
+	policyLabel    = "policy"
+	directionLabel = "direction"
+)
+
+// policyHitsCollector exports hit counters of the rules of rendered policies
+// summed up per policy by the PolicyHitCounter.
+type policyHitsCollector struct {
+	policyHits *acl.PolicyHitCounter
+	desc       *prometheus.Desc
+}
+
+// registerPolicyMetrics exports hit counters of the policy rules through the stats
+// collector (if enabled by the configuration).
+func (p *Plugin) registerPolicyMetrics() {
+	if p.Stats == nil || p.policyHits == nil {
+		return
+	}
+	collector := &policyHitsCollector{
+		policyHits: p.policyHits,
+		desc: prometheus.NewDesc(policyRuleHitsMetric,
+			"Number of packets matched by the rules of a network policy in the given direction "+
+				"(namespace is empty for cluster policies)",
+			[]string{namespaceLabel, policyLabel, directionLabel},
+			prometheus.Labels{nodeLabel: p.ServiceLabel.GetAgentLabel()}),
+	}
+	if err := p.Stats.RegisterCollector(collector); err != nil {
+		p.Log.Warnf("Failed to register metrics of the policy rule hits: %v", err)
+	}
+}
+
+// Describe sends the descriptor of the policy rule hits metric.
+func (c *policyHitsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect reads the hit counters and sends one gauge for every rendered policy
+// and direction.
+func (c *policyHitsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, policyHits := range c.policyHits.GetPolicyHits() {
+		var namespace string
+		name := policyHits.Policy
+		if idx := strings.Index(name, "/"); idx >= 0 {
+			namespace, name = name[:idx], name[idx+1:]
+		}
+		direction := "ingress"
+		if policyHits.Egress {
+			direction = "egress"
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue,
+			float64(policyHits.Hits), namespace, name, direction)
+	}
+}
@@ -15,7 +15,14 @@
 package policy
 
 import (
+	"go.ligato.io/cn-infra/v2/config"
 	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
+	"go.ligato.io/vpp-agent/v3/plugins/vpp/aclplugin"
+
+	"github.com/americanbinary/vpp/plugins/statscollector"
 )
 
 // NewPlugin creates a new Plugin with the provides Options
@@ -23,6 +30,11 @@ func NewPlugin(opts ...Option) *Plugin {
 	p := &Plugin{}
 
 	p.PluginName = "policy"
+	p.GoVPP = &govppmux.DefaultPlugin
+	p.VPPACLPlugin = &aclplugin.DefaultPlugin
+	p.HTTPHandlers = &rest.DefaultPlugin
+	p.ServiceLabel = &servicelabel.DefaultPlugin
+	p.Stats = &statscollector.DefaultPlugin
 
 	for _, o := range opts {
 		o(p)
@@ -31,6 +43,9 @@ func NewPlugin(opts ...Option) *Plugin {
 	if p.Deps.Log == nil {
 		p.Deps.Log = logging.ForPlugin(p.String())
 	}
+	if p.Cfg == nil {
+		p.Cfg = config.ForPlugin(p.String())
+	}
 
 	return p
 }
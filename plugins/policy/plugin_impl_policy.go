@@ -15,21 +15,30 @@
 package policy
 
 import (
+	"time"
+
 	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
+	"go.ligato.io/cn-infra/v2/servicelabel"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	"github.com/americanbinary/vpp/plugins/ipam"
 	"github.com/americanbinary/vpp/plugins/ipnet"
+	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
 	"github.com/americanbinary/vpp/plugins/ksr/model/namespace"
 	"github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"github.com/americanbinary/vpp/plugins/ksr/model/policy"
+	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 	"github.com/americanbinary/vpp/plugins/podmanager"
 	"github.com/americanbinary/vpp/plugins/policy/cache"
+	"github.com/americanbinary/vpp/plugins/policy/config"
 	"github.com/americanbinary/vpp/plugins/policy/configurator"
 	"github.com/americanbinary/vpp/plugins/policy/processor"
 	"github.com/americanbinary/vpp/plugins/policy/renderer/acl"
 	"github.com/americanbinary/vpp/plugins/policy/renderer/iptables"
+	"github.com/americanbinary/vpp/plugins/statscollector"
 )
 
 // Plugin watches configuration of K8s resources (as reflected by KSR into ETCD)
@@ -38,6 +47,8 @@ import (
 type Plugin struct {
 	Deps
 
+	config *config.Config
+
 	// ongoing transaction
 	resyncTxn  controller.ResyncOperations
 	updateTxn  controller.UpdateOperations
@@ -58,6 +69,12 @@ type Plugin struct {
 	// Policy Renderers: layer 4
 	//  -> ACL Renderer
 	aclRenderer *acl.Renderer
+	//  -> counting of ACL rule hits (nil if neither audit nor metrics are enabled)
+	counterSwitch *acl.CounterSwitch
+	//  -> audit of ACL rule hits (nil if disabled)
+	auditor *acl.Auditor
+	//  -> ACL rule hits summed up per policy for metrics (nil if disabled)
+	policyHits *acl.PolicyHitCounter
 	//  -> iptables Renderer
 	iptablesRenderer *iptables.Renderer
 
@@ -67,14 +84,28 @@ type Plugin struct {
 // Deps defines dependencies of policy plugin.
 type Deps struct {
 	infra.PluginDeps
-	ContivConf contivconf.API
-	IPAM       ipam.API
-	IPNet      ipnet.API
-	PodManager podmanager.API
+	ContivConf   contivconf.API
+	IPAM         ipam.API
+	IPNet        ipnet.API
+	PodManager   podmanager.API
+	GoVPP        GoVPP             /* used to read ACL rule hits for the policy audit and metrics */
+	VPPACLPlugin VPPACLPlugin      /* used to obtain indexes of installed ACLs */
+	HTTPHandlers rest.HTTPHandlers /* used to expose the policy audit events */
+	ServiceLabel servicelabel.ReaderAPI
+	Stats        statscollector.API     /* used to export hit counters of the policy rules */
+	ServiceACL   acl.ExternalIngressACL /* ingress ACL of the service plugin (source ranges) */
 }
 
 // Init initializes policy layers and caches and starts watching ETCD for K8s configuration.
 func (p *Plugin) Init() error {
+	// load configuration
+	p.config = config.DefaultConfig()
+	_, err := p.Cfg.LoadValue(p.config)
+	if err != nil {
+		return err
+	}
+	p.Log.Infof("Policy plugin configuration: %+v", *p.config)
+
 	// Inject dependencies between layers.
 	p.policyCache = &cache.PolicyCache{
 		Deps: cache.Deps{
@@ -102,6 +133,43 @@ func (p *Plugin) Init() error {
 
 	if !p.ContivConf.GetIPAMConfig().UseIPv6 {
 
+		var aclObservers []acl.ACLObserver
+		ruleHits := &aclRuleHits{
+			goVPP:     p.GoVPP,
+			aclPlugin: p.VPPACLPlugin,
+		}
+		if p.config.AuditPeriod > 0 || p.config.PolicyHitMetrics {
+			p.counterSwitch = &acl.CounterSwitch{
+				Log:      p.Log.NewLogger("-aclCounters"),
+				RuleHits: ruleHits,
+			}
+		}
+		if p.config.AuditPeriod > 0 {
+			p.auditor = &acl.Auditor{
+				AuditorDeps: acl.AuditorDeps{
+					Log:            p.Log.NewLogger("-policyAudit"),
+					IPAM:           p.IPAM,
+					RuleHits:       ruleHits,
+					Counters:       p.counterSwitch,
+					LogFile:        p.config.AuditLogFile,
+					LogFileMaxSize: int64(p.config.AuditLogMaxSize) * 1024 * 1024,
+					Period:         time.Duration(p.config.AuditPeriod) * time.Second,
+					MaxEvents:      int(p.config.AuditBufferSize),
+				},
+			}
+			aclObservers = append(aclObservers, p.auditor)
+		}
+		if p.config.PolicyHitMetrics {
+			p.policyHits = &acl.PolicyHitCounter{
+				PolicyHitCounterDeps: acl.PolicyHitCounterDeps{
+					Log:      p.Log.NewLogger("-policyHits"),
+					RuleHits: ruleHits,
+					Counters: p.counterSwitch,
+				},
+			}
+			aclObservers = append(aclObservers, p.policyHits)
+		}
+
 		p.aclRenderer = &acl.Renderer{
 			Deps: acl.Deps{
 				Log:        p.Log.NewLogger("-aclRenderer"),
@@ -115,6 +183,8 @@ func (p *Plugin) Init() error {
 				ResyncTxnFactory: func() controller.ResyncOperations {
 					return p.resyncTxn
 				},
+				ACLObservers: aclObservers,
+				ExternalACL:  p.ServiceACL,
 			},
 		}
 	} else {
@@ -159,6 +229,14 @@ func (p *Plugin) Init() error {
 	p.configurator.Init(false) // Do not render in parallel while we do lot of debugging.
 
 	if !p.ContivConf.GetIPAMConfig().UseIPv6 {
+		if p.auditor != nil {
+			if err := p.auditor.Init(); err != nil {
+				return err
+			}
+		}
+		if p.policyHits != nil {
+			p.policyHits.Init()
+		}
 		p.aclRenderer.Init()
 		p.configurator.RegisterRenderer(p.aclRenderer)
 	} else {
@@ -168,6 +246,19 @@ func (p *Plugin) Init() error {
 	return nil
 }
 
+// AfterInit starts the policy audit and registers REST handlers and metrics.
+func (p *Plugin) AfterInit() error {
+	if p.auditor != nil {
+		p.auditor.Start()
+	}
+	if p.policyHits != nil {
+		p.policyHits.Start()
+	}
+	p.registerRESTHandlers()
+	p.registerPolicyMetrics()
+	return nil
+}
+
 // HandlesEvent selects DBResync and KubeStateChange for specific resources to handle.
 func (p *Plugin) HandlesEvent(event controller.Event) bool {
 	if event.Method() != controller.Update {
@@ -181,6 +272,11 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 			return true
 		case policy.PolicyKeyword:
 			return true
+		case policy.ClusterPolicyKeyword:
+			return true
+		case svcmodel.ServiceKeyword, epmodel.EndpointsKeyword, epslicemodel.EndpointSliceKeyword:
+			// may change the ingress ACL of the service plugin
+			return p.aclRenderer != nil && p.ServiceACL != nil
 		default:
 			// unhandled Kubernetes state change
 			return false
@@ -200,6 +296,18 @@ func (p *Plugin) Resync(event controller.Event, kubeStateData controller.KubeSta
 
 	p.resyncTxn = txn
 	p.updateTxn = nil
+	if p.auditor != nil {
+		auditModes := make(map[string]acl.AuditMode)
+		for _, nsProto := range kubeStateData[namespace.NamespaceKeyword] {
+			ns := nsProto.(*namespace.Namespace)
+			auditModes[ns.Name] = acl.ParseAuditMode(ns.Annotations[acl.AuditAnnotation])
+		}
+		p.auditor.ResyncNamespaceModes(auditModes)
+	}
+	if p.counterSwitch != nil {
+		// VPP may have been restarted
+		p.counterSwitch.Resync()
+	}
 	return p.policyCache.Resync(kubeStateData)
 }
 
@@ -209,19 +317,74 @@ func (p *Plugin) Update(event controller.Event, txn controller.UpdateOperations)
 	p.updateTxn = txn
 	p.withChange = false
 	kubeStateChange := event.(*controller.KubeStateChange)
-	err = p.policyCache.Update(kubeStateChange)
+	if isServiceChange(kubeStateChange) {
+		// re-render the reflective ACL if the service ingress ACL has moved
+		err = p.aclRenderer.NewTxn(false).Commit()
+	} else {
+		if p.auditor != nil && kubeStateChange.Resource == namespace.NamespaceKeyword {
+			p.updateAuditMode(kubeStateChange)
+		}
+		err = p.policyCache.Update(kubeStateChange)
+	}
 	if p.withChange {
 		changeDescription = "refresh policies"
 	}
 	return changeDescription, err
 }
 
-// Revert does nothing here - plugin handles only BestEffort events.
+// CanRevert returns true for all the handled Kubernetes state changes - the change
+// is reverted by applying the inverse change.
+func (p *Plugin) CanRevert(event controller.Event) bool {
+	ksChange, isKSChange := event.(*controller.KubeStateChange)
+	return isKSChange && !isServiceChange(ksChange) && p.HandlesEvent(event)
+}
+
+// isServiceChange returns true if the given Kubernetes state change relates
+// to services (handled only to follow changes of the service ingress ACL).
+func isServiceChange(ksChange *controller.KubeStateChange) bool {
+	switch ksChange.Resource {
+	case svcmodel.ServiceKeyword, epmodel.EndpointsKeyword, epslicemodel.EndpointSliceKeyword:
+		return true
+	}
+	return false
+}
+
+// Revert applies the inverse of the given Kubernetes state change (called after
+// the dry-run of the change). Policies are re-rendered into the transaction
+// of the dry-run, which is never committed.
+// Plugin handles only BestEffort events, Revert is not called otherwise.
 func (p *Plugin) Revert(event controller.Event) error {
-	return nil
+	ksChange, isKSChange := event.(*controller.KubeStateChange)
+	if !isKSChange {
+		return nil
+	}
+	inverseChange := &controller.KubeStateChange{
+		Resource:  ksChange.Resource,
+		Key:       ksChange.Key,
+		PrevValue: ksChange.NewValue,
+		NewValue:  ksChange.PrevValue,
+	}
+	if p.auditor != nil && inverseChange.Resource == namespace.NamespaceKeyword {
+		p.updateAuditMode(inverseChange)
+	}
+	return p.policyCache.Update(inverseChange)
 }
 
-// Close is NOOP.
+// updateAuditMode updates policy audit mode of a changed namespace.
+func (p *Plugin) updateAuditMode(change *controller.KubeStateChange) {
+	if change.NewValue == nil {
+		ns := change.PrevValue.(*namespace.Namespace)
+		p.auditor.SetNamespaceMode(ns.Name, acl.AuditOff)
+		return
+	}
+	ns := change.NewValue.(*namespace.Namespace)
+	p.auditor.SetNamespaceMode(ns.Name, acl.ParseAuditMode(ns.Annotations[acl.AuditAnnotation]))
+}
+
+// Close stops the policy audit.
 func (p *Plugin) Close() error {
+	if p.auditor != nil {
+		return p.auditor.Close()
+	}
 	return nil
 }
@@ -0,0 +1,103 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package processor
+
+import (
+	"net"
+
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/americanbinary/vpp/plugins/ksr/model/policy"
+	config "github.com/americanbinary/vpp/plugins/policy/configurator"
+)
+
+// clusterPolicyToContiv converts cluster policy into the ContivPolicy representation.
+// Unlike with K8s policies, the result does not depend on the pod the policy
+// is applied to (named ports are not supported).
+func (pp *PolicyProcessor) clusterPolicyToContiv(policyData *policymodel.ClusterPolicy) *config.ContivPolicy {
+	policy := &config.ContivPolicy{
+		ID:       policymodel.ID{Name: policyData.Name},
+		Cluster:  true,
+		Tier:     policyData.Tier,
+		Priority: policyData.Priority,
+	}
+	switch {
+	case len(policyData.IngressRule) > 0 && len(policyData.EgressRule) > 0:
+		policy.Type = config.PolicyAll
+	case len(policyData.EgressRule) > 0:
+		policy.Type = config.PolicyEgress
+	default:
+		policy.Type = config.PolicyIngress
+	}
+	for _, rule := range policyData.IngressRule {
+		policy.Matches = append(policy.Matches, pp.calculateClusterMatch(config.MatchIngress, rule))
+	}
+	for _, rule := range policyData.EgressRule {
+		policy.Matches = append(policy.Matches, pp.calculateClusterMatch(config.MatchEgress, rule))
+	}
+	return policy
+}
+
+// calculateClusterMatch translates a rule of a cluster policy into a Match.
+func (pp *PolicyProcessor) calculateClusterMatch(matchType config.MatchType,
+	rule *policymodel.ClusterPolicy_Rule) config.Match {
+
+	match := config.Match{Type: matchType}
+	switch rule.Action {
+	case policymodel.ClusterPolicy_DENY:
+		match.Action = config.MatchDeny
+	case policymodel.ClusterPolicy_PASS:
+		match.Action = config.MatchPass
+	default:
+		match.Action = config.MatchAllow
+	}
+
+	// no peers = match all sources(ingress) / destinations(egress)
+	if len(rule.Peer) > 0 {
+		match.Pods = []podmodel.ID{}
+		match.IPBlocks = []config.IPBlock{}
+	}
+	for _, peer := range rule.Peer {
+		if peer.IpBlock != nil {
+			_, cidr, err := net.ParseCIDR(peer.IpBlock.Cidr)
+			if err != nil {
+				pp.Log.Warnf("Invalid CIDR %s in the cluster policy rule: %v", peer.IpBlock.Cidr, err)
+				continue
+			}
+			block := config.IPBlock{Network: *cidr}
+			for _, except := range peer.IpBlock.Except {
+				if _, exceptNet, err := net.ParseCIDR(except); err == nil {
+					block.Except = append(block.Except, *exceptNet)
+				}
+			}
+			match.IPBlocks = append(match.IPBlocks, block)
+			continue
+		}
+		match.Pods = append(match.Pods, pp.Cache.LookupPodsByClusterSelector(peer.Namespaces, peer.Pods)...)
+	}
+
+	for _, rulePort := range rule.Port {
+		port := rulePortProtocol(rulePort)
+		if rulePort.Port != nil && port.Protocol != config.OTHER {
+			port.Number = uint16(rulePort.Port.Number)
+			if rulePort.EndPort > rulePort.Port.Number {
+				port.EndNumber = uint16(rulePort.EndPort)
+			}
+		}
+		match.Ports = append(match.Ports, port)
+	}
+	return match
+}
@@ -68,17 +68,19 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 
 			ingressRulePorts := ingressRule.Port
 			for _, ingressRulePort := range ingressRulePorts {
-				ingressPortProtocol := config.TCP
-				if ingressRulePort.Protocol == policymodel.Policy_Port_UDP {
-					ingressPortProtocol = config.UDP
+				ingressPort := rulePortProtocol(ingressRulePort)
+				if ingressRulePort.Port == nil || ingressPort.Protocol == config.OTHER {
+					// all ports of the protocol
+					ingressPorts = append(ingressPorts, ingressPort)
+					continue
 				}
 				// A port in kubernetes network policy is either a name (1) or a port number (0)
 				if ingressRulePort.Port.Type == 0 {
-					ingressPortNumber := uint16(ingressRulePort.Port.Number)
-					ingressPorts = append(ingressPorts, config.Port{
-						Protocol: ingressPortProtocol,
-						Number:   ingressPortNumber,
-					})
+					ingressPort.Number = uint16(ingressRulePort.Port.Number)
+					if ingressRulePort.EndPort > ingressRulePort.Port.Number {
+						ingressPort.EndNumber = uint16(ingressRulePort.EndPort)
+					}
+					ingressPorts = append(ingressPorts, ingressPort)
 				} else {
 					// Obtain data for pod that matches are calculated
 					_, podData := pp.Cache.LookupPod(podID)
@@ -86,11 +88,8 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 					for _, podContainer := range podData.Container {
 						for _, podPort := range podContainer.Port {
 							if podPort.Name == ingressRulePort.Port.Name {
-								ingressPortNumber := uint16(podPort.ContainerPort)
-								ingressPorts = append(ingressPorts, config.Port{
-									Protocol: ingressPortProtocol,
-									Number:   ingressPortNumber,
-								})
+								ingressPort.Number = uint16(podPort.ContainerPort)
+								ingressPorts = append(ingressPorts, ingressPort)
 							}
 						}
 					}
@@ -157,27 +156,29 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 			egressRulePorts := egressRule.Port
 			// Egress ports to appropriate type
 			for _, egressRulePort := range egressRulePorts {
-				egressPortProtocol := config.TCP
-				if egressRulePort.Protocol == policymodel.Policy_Port_UDP {
-					egressPortProtocol = config.UDP
+				egressPort := rulePortProtocol(egressRulePort)
+				if egressRulePort.Port == nil || egressPort.Protocol == config.OTHER {
+					// all ports of the protocol
+					egressPorts = append(egressPorts, egressPort)
+					continue
 				}
 
 				if egressRulePort.Port.Type == 0 {
-					egressPortNumber := uint16(egressRulePort.Port.Number)
-					egressPorts = append(egressPorts, config.Port{
-						Protocol: egressPortProtocol,
-						Number:   egressPortNumber,
-					})
+					egressPort.Number = uint16(egressRulePort.Port.Number)
+					if egressRulePort.EndPort > egressRulePort.Port.Number {
+						egressPort.EndNumber = uint16(egressRulePort.EndPort)
+					}
+					egressPorts = append(egressPorts, egressPort)
 				} else {
 					// if there are egressPods then map the portName to portNumber for every each one of them
 					// without adding IPBlocks, if not do the same for all running pods.
 					if len(egressPods) > 0 {
 						// For all egress pods, find the matching policy port name mapped to a port number
-						portNameMatches := pp.portNameToNumber(egressPods, egressPortProtocol, egressRulePort)
+						portNameMatches := pp.portNameToNumber(egressPods, egressPort, egressRulePort)
 						matches = append(matches, portNameMatches...)
 					} else {
 						newEgressPods := pp.Cache.ListAllPods()
-						portNameMatches := pp.portNameToNumber(newEgressPods, egressPortProtocol, egressRulePort)
+						portNameMatches := pp.portNameToNumber(newEgressPods, egressPort, egressRulePort)
 						matches = append(matches, portNameMatches...)
 					}
 				}
@@ -194,7 +195,7 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 	return matches
 }
 
-func (pp *PolicyProcessor) portNameToNumber(pods []podmodel.ID, portProtocol config.ProtocolType,
+func (pp *PolicyProcessor) portNameToNumber(pods []podmodel.ID, portProtocol config.Port,
 	rulePort *policymodel.Policy_Port) []config.Match {
 	matches := []config.Match{}
 	for _, pod := range pods {
@@ -202,11 +203,8 @@ func (pp *PolicyProcessor) portNameToNumber(pods []podmodel.ID, portProtocol con
 		for _, podContainer := range podData.Container {
 			for _, podPort := range podContainer.Port {
 				if podPort.Name == rulePort.Port.Name {
-					portNumber := uint16(podPort.ContainerPort)
-					port := config.Port{
-						Protocol: portProtocol,
-						Number:   portNumber,
-					}
+					port := portProtocol
+					port.Number = uint16(podPort.ContainerPort)
 					matches = append(matches, config.Match{
 						Type:     config.MatchEgress,
 						Pods:     []podmodel.ID{pod},
@@ -219,3 +217,17 @@ func (pp *PolicyProcessor) portNameToNumber(pods []podmodel.ID, portProtocol con
 	}
 	return matches
 }
+
+// rulePortProtocol returns port matching all ports of the protocol of the given
+// policy rule port.
+func rulePortProtocol(rulePort *policymodel.Policy_Port) config.Port {
+	switch rulePort.Protocol {
+	case policymodel.Policy_Port_UDP:
+		return config.Port{Protocol: config.UDP}
+	case policymodel.Policy_Port_SCTP:
+		return config.Port{Protocol: config.SCTP}
+	case policymodel.Policy_Port_OTHER:
+		return config.Port{Protocol: config.OTHER, ProtocolNumber: uint8(rulePort.ProtocolNumber)}
+	}
+	return config.Port{Protocol: config.TCP}
+}
@@ -58,9 +58,9 @@ type Deps struct {
 
 // IPAM interface lists IPAM methods needed by Policy Processor.
 type IPAM interface {
-	// PodSubnetThisNode returns POD network for the current node
+	// PodSubnetsThisNode returns all POD networks for the current node
 	// (given by nodeID allocated for this node).
-	PodSubnetThisNode(network string) *net.IPNet
+	PodSubnetsThisNode(network string) []*net.IPNet
 }
 
 // Init initializes the Policy Processor.
@@ -91,6 +91,7 @@ func (pp *PolicyProcessor) Process(resync bool, pods []podmodel.ID) error {
 
 	txn := pp.Configurator.NewTxn(resync)
 	processedPolicies := make(map[policymodel.ID]*config.ContivPolicy)
+	processedClusterPolicies := make(map[string]*config.ContivPolicy)
 	pp.Log.Debugf("Pods selected for policy pre-processing: %v", pods)
 
 	for _, pod := range pods {
@@ -98,7 +99,8 @@ func (pp *PolicyProcessor) Process(resync bool, pods []podmodel.ID) error {
 
 		// Find the policies the pod in the slice is associated with.
 		policiesByPod := pp.Cache.LookupPoliciesByPod(pod)
-		if len(policiesByPod) == 0 {
+		clusterPoliciesByPod := pp.Cache.LookupClusterPoliciesByPod(pod)
+		if len(policiesByPod) == 0 && len(clusterPoliciesByPod) == 0 {
 			txn.Configure(pod, policies)
 			continue
 		}
@@ -145,6 +147,19 @@ func (pp *PolicyProcessor) Process(resync bool, pods []podmodel.ID) error {
 			policies = append(policies, contivPolicy)
 		}
 
+		// Cluster policies are evaluated ahead of the K8s policies by the Configurator.
+		for _, policy := range clusterPoliciesByPod {
+			if contivPolicy, alreadyProcessed = processedClusterPolicies[policy]; !alreadyProcessed {
+				found, policyData := pp.Cache.LookupClusterPolicy(policy)
+				if !found {
+					continue
+				}
+				contivPolicy = pp.clusterPolicyToContiv(policyData)
+				processedClusterPolicies[policy] = contivPolicy
+			}
+			policies = append(policies, contivPolicy)
+		}
+
 		// Re-configure policies for the pod.
 		pp.Log.WithField("process-resync", resync).
 			Infof("Pod sent to Configurator: %+v, w/ Policies: %+v", pod, policies)
@@ -185,6 +200,7 @@ func (pp *PolicyProcessor) AddPod(podID podmodel.ID, pod *podmodel.Pod) error {
 	for _, policy := range podPolicies {
 		pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 	}
+	pods = append(pods, pp.getPodsAssignedToClusterPolicies()...)
 
 	// Update newly added pod as well.
 	pods = append(pods, podID)
@@ -200,6 +216,7 @@ func (pp *PolicyProcessor) DelPod(podID podmodel.ID, pod *podmodel.Pod) error {
 	for _, policy := range podPolicies {
 		pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 	}
+	pods = append(pods, pp.getPodsAssignedToClusterPolicies()...)
 
 	// Update deleted pod as well.
 	pods = append(pods, podID)
@@ -243,8 +260,12 @@ func (pp *PolicyProcessor) UpdatePod(podID podmodel.ID, oldPod, newPod *podmodel
 		}
 	}
 
-	// Process this pod also in case the IP address has changed.
-	if newPod.IpAddress != oldPod.IpAddress {
+	// Pod may have become (or ceased to be) a target or a peer of cluster policies.
+	pods = append(pods, pp.getPodsAssignedToClusterPolicies()...)
+
+	// Process this pod also in case the IP address has changed
+	// or if it could be affected by cluster policies.
+	if newPod.IpAddress != oldPod.IpAddress || len(pp.Cache.ListAllClusterPolicies()) > 0 {
 		pods = append(pods, podID)
 	}
 
@@ -303,6 +324,54 @@ func (pp *PolicyProcessor) UpdatePolicy(oldPolicy, newPolicy *policymodel.Policy
 	return pp.Process(false, pods)
 }
 
+// AddClusterPolicy processes the event of newly added cluster policy.
+// The list of pods with outdated policy configuration is determined and the
+// policy re-processing is triggered for each of them.
+func (pp *PolicyProcessor) AddClusterPolicy(policy *policymodel.ClusterPolicy) error {
+	if policy == nil {
+		pp.Log.WithField("policy", policy).Error("Error reading Cluster Policy")
+		return nil
+	}
+
+	// Find all the pods that match the newly added cluster policy.
+	pods := pp.getPodsAssignedToClusterPolicy(policy)
+	return pp.Process(false, pods)
+}
+
+// DelClusterPolicy processes the event of a removed cluster policy.
+// The list of pods with outdated policy configuration is determined and the
+// policy re-processing is triggered for each of them.
+func (pp *PolicyProcessor) DelClusterPolicy(policy *policymodel.ClusterPolicy) error {
+	if policy == nil {
+		pp.Log.WithField("policy", policy).Error("Error reading Cluster Policy")
+		return nil
+	}
+
+	// Find all the pods that used to match the removed cluster policy.
+	pods := pp.getPodsAssignedToClusterPolicy(policy)
+	return pp.Process(false, pods)
+}
+
+// UpdateClusterPolicy processes the event of changed cluster policy data.
+// The list of pods with outdated policy configuration is determined and the
+// policy re-processing is triggered for each of them.
+func (pp *PolicyProcessor) UpdateClusterPolicy(oldPolicy, newPolicy *policymodel.ClusterPolicy) error {
+	if oldPolicy == nil || newPolicy == nil {
+		pp.Log.WithFields(logging.Fields{
+			"old-policy": oldPolicy,
+			"new-policy": newPolicy,
+		}).Error("Error reading Cluster Policy")
+		return nil
+	}
+
+	// Get all matching pods before the change and now.
+	pods := []podmodel.ID{}
+	pods = append(pods, pp.getPodsAssignedToClusterPolicy(oldPolicy)...)
+	pods = append(pods, pp.getPodsAssignedToClusterPolicy(newPolicy)...)
+
+	return pp.Process(false, pods)
+}
+
 // AddNamespace processes the event of newly added namespace (no action needed).
 func (pp *PolicyProcessor) AddNamespace(ns *nsmodel.Namespace) error {
 	return nil
@@ -336,6 +405,12 @@ func (pp *PolicyProcessor) UpdateNamespace(oldNs, newNs *nsmodel.Namespace) erro
 		pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 	}
 
+	// Namespace labels are also evaluated by selectors of cluster policies.
+	if len(pp.Cache.ListAllClusterPolicies()) > 0 {
+		pods = append(pods, pp.getPodsAssignedToClusterPolicies()...)
+		pods = append(pods, pp.Cache.LookupPodsByNamespace(newNs.Name)...)
+	}
+
 	return pp.Process(false, pods)
 }
 
@@ -352,7 +427,7 @@ func (pp *PolicyProcessor) filterHostPods(pods []podmodel.ID) []podmodel.ID {
 		hadIP        bool
 		hostPods     []podmodel.ID
 	)
-	hostNetwork := pp.IPAM.PodSubnetThisNode(ipnet.DefaultPodNetworkName)
+	hostNetworks := pp.IPAM.PodSubnetsThisNode(ipnet.DefaultPodNetworkName)
 
 	for _, podID := range pods {
 		found, podData := pp.Cache.LookupPod(podID)
@@ -364,10 +439,12 @@ func (pp *PolicyProcessor) filterHostPods(pods []podmodel.ID) []podmodel.ID {
 		} else {
 			podIPAddress = net.ParseIP(podData.IpAddress)
 		}
-		if !hostNetwork.Contains(podIPAddress) {
-			continue
+		for _, hostNetwork := range hostNetworks {
+			if hostNetwork.Contains(podIPAddress) {
+				hostPods = append(hostPods, podID)
+				break
+			}
 		}
-		hostPods = append(hostPods, podID)
 	}
 	return hostPods
 }
@@ -380,6 +457,23 @@ func (pp *PolicyProcessor) getPodsAssignedToPolicy(policy *policymodel.Policy) (
 	return pods
 }
 
+// getPodsAssignedToClusterPolicy returns all pods that have the given cluster policy assigned.
+func (pp *PolicyProcessor) getPodsAssignedToClusterPolicy(policy *policymodel.ClusterPolicy) (pods []podmodel.ID) {
+	return pp.Cache.LookupPodsByClusterSelector(policy.Namespaces, policy.Pods)
+}
+
+// getPodsAssignedToClusterPolicies returns all pods that have at least one cluster
+// policy assigned. Since peers of cluster policies may come from any namespace,
+// changes in pods and namespaces are conservatively re-processed for all of them.
+func (pp *PolicyProcessor) getPodsAssignedToClusterPolicies() (pods []podmodel.ID) {
+	for _, policy := range pp.Cache.ListAllClusterPolicies() {
+		if found, policyData := pp.Cache.LookupClusterPolicy(policy); found {
+			pods = append(pods, pp.getPodsAssignedToClusterPolicy(policyData)...)
+		}
+	}
+	return pods
+}
+
 // getPoliciesAssignedToPod returns all policies currently assigned to a given pod.
 func (pp *PolicyProcessor) getPoliciesReferencingPod(pod *podmodel.Pod) (policies map[policymodel.ID]*policymodel.Policy) {
 	policies = make(map[policymodel.ID]*policymodel.Policy)
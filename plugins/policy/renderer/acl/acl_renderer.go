@@ -18,6 +18,7 @@ package acl
 
 import (
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
@@ -45,6 +46,9 @@ const (
 
 	ipv4AddrAny = "0.0.0.0/0"
 	ipv6AddrAny = "::/0"
+
+	// IANA-assigned IP protocol number of SCTP
+	sctpProtocolNumber = 132
 )
 
 // Renderer renders Contiv Rules into VPP ACLs.
@@ -55,6 +59,9 @@ type Renderer struct {
 
 	cache         *cache.RendererCache
 	podInterfaces PodInterfaces
+
+	// interfaces with external ingress ACL, excluded from the reflective ACL
+	externalIngressIfs []string
 }
 
 // Deps lists dependencies of Renderer.
@@ -65,6 +72,19 @@ type Deps struct {
 	ContivConf       ContivConf
 	UpdateTxnFactory func() (txn controller.UpdateOperations)
 	ResyncTxnFactory func() (txn controller.ResyncOperations)
+	ACLObservers     []ACLObserver      /* optional, e.g. Auditor */
+	ExternalACL      ExternalIngressACL /* optional, e.g. Service plugin */
+}
+
+// ExternalIngressACL is implemented by components which install their own ACL
+// on the ingress of some of the node output interfaces.
+// VPP evaluates ingress ACLs of an interface in the order of attachment,
+// which is not defined across plugins. To keep a single ingress ACL per
+// interface, the reflective ACL is not attached to these interfaces and the
+// external ACL is expected to reflect all the traffic it permits.
+type ExternalIngressACL interface {
+	// GetIngressACLInterfaces returns interfaces with the external ingress ACL.
+	GetIngressACLInterfaces() []string
 }
 
 // ContivConf interface lists methods from ContivConf plugin which are needed
@@ -152,9 +172,14 @@ func (art *RendererTxn) Commit() error {
 		hasReflectiveACL = true
 	}
 
+	// Check if the set of interfaces with external ingress ACL has changed.
+	externalIfs := art.getExternalIngressInterfaces()
+	externalIfsChanged := !sameInterfaces(externalIfs, art.renderer.externalIngressIfs)
+	art.renderer.externalIngressIfs = externalIfs
+
 	// Get the minimalistic diff to be rendered.
 	changes := art.cacheTxn.GetChanges()
-	if len(changes) == 0 {
+	if len(changes) == 0 && !externalIfsChanged {
 		// Still need to commit the configuration updates from the transaction.
 		return art.cacheTxn.Commit()
 	}
@@ -171,16 +196,19 @@ func (art *RendererTxn) Commit() error {
 			// New ACL
 			acl := art.renderACL(change.Table, false)
 			txn.Put(vpp_acl.Key(acl.Name), acl)
+			art.observeACL(acl.Name, change.Table)
 		} else if len(change.Table.Pods) != 0 {
 			// Changed interfaces
 			aclPrivCopy := proto.Clone(change.Table.Private.(*vpp_acl.ACL))
 			acl := aclPrivCopy.(*vpp_acl.ACL)
 			acl.Interfaces = art.renderInterfaces(change.Table.Pods, false)
 			txn.Put(vpp_acl.Key(acl.Name), acl)
+			art.observeACL(acl.Name, change.Table)
 		} else {
 			// Removed ACL
 			acl := change.Table.Private.(*vpp_acl.ACL)
 			txn.Delete(vpp_acl.Key(acl.Name))
+			art.observeACL(acl.Name, nil)
 		}
 	}
 
@@ -191,11 +219,13 @@ func (art *RendererTxn) Commit() error {
 		if globalTable.NumOfRules == 0 {
 			// Remove empty global table.
 			txn.Delete(vpp_acl.Key(globalACL.Name))
+			art.observeACL(globalACL.Name, nil)
 			gtAddedOrDeleted = true
 		} else {
 			// Update content of the global table.
 			globalACL.Interfaces.Egress = art.getNodeOutputInterfaces()
 			txn.Put(vpp_acl.Key(globalACL.Name), globalACL)
+			art.observeACL(globalACL.Name, globalTable)
 			if art.renderer.cache.GetGlobalTable().NumOfRules == 0 {
 				gtAddedOrDeleted = true
 			}
@@ -203,7 +233,8 @@ func (art *RendererTxn) Commit() error {
 	}
 
 	// Render the reflective ACL
-	if gtAddedOrDeleted || !art.cacheTxn.GetIsolatedPods().Equals(art.renderer.cache.GetIsolatedPods()) {
+	if gtAddedOrDeleted || externalIfsChanged ||
+		!art.cacheTxn.GetIsolatedPods().Equals(art.renderer.cache.GetIsolatedPods()) {
 		reflectiveACL := art.reflectiveACL()
 		if len(reflectiveACL.Interfaces.Ingress) == 0 {
 			if hasReflectiveACL {
@@ -225,6 +256,9 @@ func (art *RendererTxn) commitResync() error {
 	// reset the cache and the renderer internal state first
 	art.renderer.cache.Flush()
 	art.renderer.podInterfaces = make(PodInterfaces)
+	for _, observer := range art.renderer.ACLObservers {
+		observer.RemoveAllACLs()
+	}
 
 	// after the flush, changes == all newly created
 	changes := art.cacheTxn.GetChanges()
@@ -234,14 +268,17 @@ func (art *RendererTxn) commitResync() error {
 			globalACL := art.renderACL(change.Table, false)
 			globalACL.Interfaces.Egress = art.getNodeOutputInterfaces()
 			txn.Put(vpp_acl.Key(globalACL.Name), globalACL)
+			art.observeACL(globalACL.Name, change.Table)
 		} else {
 			// local table
 			localACL := art.renderACL(change.Table, false)
 			txn.Put(vpp_acl.Key(localACL.Name), localACL)
+			art.observeACL(localACL.Name, change.Table)
 		}
 	}
 
 	// reflective ACL at last
+	art.renderer.externalIngressIfs = art.getExternalIngressInterfaces()
 	reflectiveACL := art.reflectiveACL()
 	if len(reflectiveACL.Interfaces.Ingress) != 0 {
 		txn.Put(vpp_acl.Key(reflectiveACL.Name), reflectiveACL)
@@ -251,6 +288,17 @@ func (art *RendererTxn) commitResync() error {
 	return art.cacheTxn.Commit()
 }
 
+// observeACL passes rendered (or removed if <table> is nil) ACL to the ACL observers.
+func (art *RendererTxn) observeACL(aclName string, table *cache.ContivRuleTable) {
+	for _, observer := range art.renderer.ACLObservers {
+		if table == nil {
+			observer.RemoveACL(aclName)
+		} else {
+			observer.UpdateACL(aclName, table)
+		}
+	}
+}
+
 // reflectiveACL returns the configuration of the reflective ACL.
 func (art *RendererTxn) reflectiveACL() *vpp_acl.ACL {
 	// Prepare table to render the ACL from.
@@ -269,11 +317,28 @@ func (art *RendererTxn) reflectiveACL() *vpp_acl.ACL {
 	// Render the ACL.
 	acl := art.renderACL(table, true)
 	if art.cacheTxn.GetGlobalTable().NumOfRules > 0 {
-		acl.Interfaces.Ingress = append(acl.Interfaces.Ingress, art.getNodeOutputInterfaces()...)
+		for _, ifName := range art.getNodeOutputInterfaces() {
+			if hasInterface(art.renderer.externalIngressIfs, ifName) {
+				// traffic is reflected by the external ACL
+				continue
+			}
+			acl.Interfaces.Ingress = append(acl.Interfaces.Ingress, ifName)
+		}
 	}
 	return acl
 }
 
+// getExternalIngressInterfaces returns the (sorted) list of interfaces with
+// external ingress ACL.
+func (art *RendererTxn) getExternalIngressInterfaces() []string {
+	if art.renderer.ExternalACL == nil {
+		return nil
+	}
+	interfaces := append([]string{}, art.renderer.ExternalACL.GetIngressACLInterfaces()...)
+	sort.Strings(interfaces)
+	return interfaces
+}
+
 // getNodeOutputInterfaces returns the list of interfaces that connect this K8s node
 // with the outside world.
 func (art *RendererTxn) getNodeOutputInterfaces() []string {
@@ -293,6 +358,29 @@ func (art *RendererTxn) getNodeOutputInterfaces() []string {
 	return interfaces
 }
 
+// hasInterface returns true if <interfaces> include the given interface.
+func hasInterface(interfaces []string, ifName string) bool {
+	for _, iface := range interfaces {
+		if iface == ifName {
+			return true
+		}
+	}
+	return false
+}
+
+// sameInterfaces returns true if both (sorted) lists contain the same interfaces.
+func sameInterfaces(ifs1, ifs2 []string) bool {
+	if len(ifs1) != len(ifs2) {
+		return false
+	}
+	for i := range ifs1 {
+		if ifs1[i] != ifs2[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // anyAddrForIPversion returns any addr for the IP version defined by given argument
 func anyAddrForIPversion(ip string) string {
 	if strings.Contains(ip, ":") {
@@ -329,6 +417,16 @@ func expandAnyAddr(rule *vpp_acl.ACL_Rule) []*vpp_acl.ACL_Rule {
 
 }
 
+// isUnsupportedRule returns true for rules which are left out from the rendered ACL
+// because VPP could only apply them to more traffic than selected. VPP ACL plugin
+// matches L4 ports only for TCP and UDP - permitting only some SCTP ports would
+// permit all SCTP traffic, the rule is therefore skipped and the traffic falls
+// through to the following rules (fails closed).
+func isUnsupportedRule(rule *renderer.ContivRule) bool {
+	return rule.Protocol == renderer.SCTP && rule.Action != renderer.ActionDeny &&
+		(rule.SrcPort != 0 || rule.DestPort != 0)
+}
+
 // renderACL renders ContivRuleTable into the equivalent ACL configuration.
 func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL bool) *vpp_acl.ACL {
 	const maxPortNum = ^uint16(0)
@@ -342,6 +440,13 @@ func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL
 
 	for i := 0; i < table.NumOfRules; i++ {
 		rule := table.Rules[i]
+		if isUnsupportedRule(rule) {
+			art.renderer.Log.WithFields(logging.Fields{
+				"acl":  acl.Name,
+				"rule": rule,
+			}).Warn("VPP ACL cannot match SCTP ports, the rule permitting selected SCTP ports is left out")
+			continue
+		}
 		aclRule := &vpp_acl.ACL_Rule{}
 		if rule.Action == renderer.ActionDeny {
 			aclRule.Action = vpp_acl.ACL_Rule_DENY
@@ -368,11 +473,12 @@ func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL
 				aclRule.IpRule.Tcp.SourcePortRange.UpperPort = uint32(rule.SrcPort)
 			}
 			aclRule.IpRule.Tcp.DestinationPortRange = &vpp_acl.ACL_Rule_IpRule_PortRange{}
-			aclRule.IpRule.Tcp.DestinationPortRange.LowerPort = uint32(rule.DestPort)
-			if rule.DestPort == 0 {
+			dstLower, dstUpper := rule.DestPortRange()
+			aclRule.IpRule.Tcp.DestinationPortRange.LowerPort = uint32(dstLower)
+			if dstLower == 0 {
 				aclRule.IpRule.Tcp.DestinationPortRange.UpperPort = uint32(maxPortNum)
 			} else {
-				aclRule.IpRule.Tcp.DestinationPortRange.UpperPort = uint32(rule.DestPort)
+				aclRule.IpRule.Tcp.DestinationPortRange.UpperPort = uint32(dstUpper)
 			}
 		}
 		if rule.Protocol == renderer.UDP {
@@ -385,13 +491,28 @@ func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL
 				aclRule.IpRule.Udp.SourcePortRange.UpperPort = uint32(rule.SrcPort)
 			}
 			aclRule.IpRule.Udp.DestinationPortRange = &vpp_acl.ACL_Rule_IpRule_PortRange{}
-			aclRule.IpRule.Udp.DestinationPortRange.LowerPort = uint32(rule.DestPort)
-			if rule.DestPort == 0 {
+			dstLower, dstUpper := rule.DestPortRange()
+			aclRule.IpRule.Udp.DestinationPortRange.LowerPort = uint32(dstLower)
+			if dstLower == 0 {
 				aclRule.IpRule.Udp.DestinationPortRange.UpperPort = uint32(maxPortNum)
 			} else {
-				aclRule.IpRule.Udp.DestinationPortRange.UpperPort = uint32(rule.DestPort)
+				aclRule.IpRule.Udp.DestinationPortRange.UpperPort = uint32(dstUpper)
+			}
+		}
+		if rule.Protocol == renderer.SCTP {
+			// VPP ACL plugin matches L4 ports only for TCP and UDP,
+			// SCTP deny rules are therefore applied to all SCTP ports
+			aclRule.IpRule.Ip.Protocol = sctpProtocolNumber
+			if rule.SrcPort != 0 || rule.DestPort != 0 {
+				art.renderer.Log.WithFields(logging.Fields{
+					"acl":  acl.Name,
+					"rule": rule,
+				}).Warn("VPP ACL cannot match SCTP ports, the deny rule is applied to all SCTP ports")
 			}
 		}
+		if rule.Protocol == renderer.OTHER {
+			aclRule.IpRule.Ip.Protocol = uint32(rule.ProtocolNumber)
+		}
 		acl.Rules = append(acl.Rules, expandAnyAddr(aclRule)...)
 	}
 
@@ -18,7 +18,14 @@ package acl
 
 import (
 	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 
@@ -34,9 +41,11 @@ import (
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	"github.com/americanbinary/vpp/plugins/ipnet"
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	"github.com/americanbinary/vpp/plugins/policy/renderer"
 	"github.com/americanbinary/vpp/plugins/policy/renderer/cache"
 	. "github.com/americanbinary/vpp/plugins/policy/renderer/testdata"
+	"github.com/americanbinary/vpp/plugins/policy/restapi"
 	. "github.com/americanbinary/vpp/plugins/policy/utils"
 )
 
@@ -84,6 +93,24 @@ func (m *contivConfMock) GetOtherVPPInterfaces() contivconf.OtherInterfaces {
 	return interfaces
 }
 
+type ipamMock struct {
+	pods map[string]podmodel.ID
+}
+
+func (m *ipamMock) GetPodFromIP(podIP net.IP) (podID podmodel.ID, found bool) {
+	podID, found = m.pods[podIP.String()]
+	return podID, found
+}
+
+func (m *ipamMock) GetPodIP(podID podmodel.ID) *net.IPNet {
+	for ip, pod := range m.pods {
+		if pod == podID {
+			return GetOneHostSubnet(ip)
+		}
+	}
+	return nil
+}
+
 func commitTxn() error {
 	if vppTxn == nil {
 		return nil
@@ -1123,3 +1150,305 @@ func TestCombinedRulesWithRemovedPods(t *testing.T) {
 	verifyReflectiveACL(aclEngine, ipNet, contivConf, "", false, false)
 	verifyGlobalTable(aclEngine, ipNet, contivConf, false)
 }
+
+func TestSCTPRules(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestSCTPRules")
+
+	// Prepare ACL Renderer.
+	ipNet := NewMockIPNet()
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:        logger,
+			ContivConf: &contivConfMock{},
+			IPNet:      ipNet,
+		},
+	}
+	aclRenderer.Init()
+
+	// Prepare table with SCTP rules selecting ports.
+	sctpPermit := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  IpNetwork("10.10.0.0/16"),
+		DestNetwork: IpNetwork(""),
+		Protocol:    renderer.SCTP,
+		DestPort:    5000,
+	}
+	sctpDeny := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		SrcNetwork:  IpNetwork("10.20.0.0/16"),
+		DestNetwork: IpNetwork(""),
+		Protocol:    renderer.SCTP,
+		DestPort:    6000,
+	}
+	denyRest := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		SrcNetwork:  IpNetwork(""),
+		DestNetwork: IpNetwork(""),
+		Protocol:    renderer.ANY,
+	}
+	table := cache.NewContivRuleTable(cache.Local)
+	table.Pods = cache.NewPodSet(Pod1)
+	table.InsertRule(sctpPermit)
+	table.InsertRule(sctpDeny)
+	table.InsertRule(denyRest)
+
+	// Permit cannot be limited to the selected SCTP port and is left out,
+	// deny is applied to all SCTP ports.
+	txn := aclRenderer.NewTxn(false).(*RendererTxn)
+	acl := txn.renderACL(table, false)
+	gomega.Expect(acl.Interfaces.Egress).To(gomega.Equal([]string{Pod1IfName}))
+	gomega.Expect(acl.Rules).To(gomega.HaveLen(3))
+	for _, rule := range acl.Rules {
+		gomega.Expect(rule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_DENY))
+	}
+	gomega.Expect(acl.Rules[0].IpRule.Ip.SourceNetwork).To(gomega.Equal("10.20.0.0/16"))
+	gomega.Expect(acl.Rules[0].IpRule.Ip.Protocol).To(gomega.BeEquivalentTo(sctpProtocolNumber))
+	gomega.Expect(acl.Rules[1].IpRule.Ip.Protocol).To(gomega.BeEquivalentTo(0))
+	gomega.Expect(acl.Rules[2].IpRule.Ip.Protocol).To(gomega.BeEquivalentTo(0))
+
+	// Hit counters are tracked for the rendered rules only.
+	tracker := newACLHitsTracker()
+	tracker.updateACL(acl.Name, table)
+	gomega.Expect(tracker.acls[acl.Name].rules).To(gomega.Equal(
+		[]*renderer.ContivRule{sctpDeny, denyRest, denyRest}))
+}
+
+func TestAudit(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAudit")
+
+	// Prepare input data
+	denyUDP := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		SrcNetwork:  GetOneHostSubnet(Pod6IP),
+		DestNetwork: IpNetwork(""),
+		Protocol:    renderer.UDP,
+		Origins:     []*renderer.RuleOrigin{{Policy: "deny-udp", RuleIndex: 0}},
+	}
+	allowTCP := Ts5.Rule1.Copy()
+	allowTCP.Origins = []*renderer.RuleOrigin{{Policy: "default/allow-tcp", RuleIndex: 1}}
+	egress := []*renderer.ContivRule{denyUDP, allowTCP, DenyAll()}
+
+	// Prepare mocks.
+	//  -> ContivConf plugin
+	contivConf := &contivConfMock{}
+	contivConf.SetMainInterfaceName(mainIfName)
+
+	//  -> IPNet plugin
+	ipNet := NewMockIPNet()
+	ipNet.SetVxlanBVIIfName(vxlanIfName)
+	ipNet.SetHostInterconnectIfName(hostInterIfName)
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+
+	//  -> IPAM plugin (resolves only local pods)
+	ipam := &ipamMock{pods: map[string]podmodel.ID{Pod1IP: Pod1}}
+
+	// -> ACL engine
+	aclEngine := NewMockACLEngine(logger, ipNet, contivConf)
+	aclEngine.RegisterPod(Pod1, Pod1IP, false)
+	aclEngine.RegisterPod(Pod6, Pod6IP, true)
+
+	// -> localclient
+	txnTracker := localclient.NewTxnTracker(aclEngine.ApplyTxn)
+
+	// Prepare Auditor, PolicyHitCounter and ACL Renderer.
+	counters := &CounterSwitch{Log: logger, RuleHits: aclEngine}
+	auditor := &Auditor{
+		AuditorDeps: AuditorDeps{
+			Log:       logger,
+			IPAM:      ipam,
+			RuleHits:  aclEngine,
+			Counters:  counters,
+			MaxEvents: 10,
+		},
+	}
+	gomega.Expect(auditor.Init()).To(gomega.BeNil())
+	auditor.SetNamespaceMode(Pod1.Namespace, ParseAuditMode("deny"))
+	gomega.Expect(counters.IsEnabled()).To(gomega.BeTrue())
+
+	policyHits := &PolicyHitCounter{
+		PolicyHitCounterDeps: PolicyHitCounterDeps{
+			Log:      logger,
+			RuleHits: aclEngine,
+			Counters: counters,
+		},
+	}
+	policyHits.Init()
+
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:              logger,
+			ContivConf:       contivConf,
+			IPNet:            ipNet,
+			ResyncTxnFactory: resyncTxnFactory(txnTracker),
+			UpdateTxnFactory: updateTxnFactory(txnTracker),
+			ACLObservers:     []ACLObserver{auditor, policyHits},
+		},
+	}
+	aclRenderer.Init()
+
+	// Execute Renderer transaction.
+	err := aclRenderer.NewTxn(true).Render(Pod1, GetOneHostSubnet(Pod1IP), []*renderer.ContivRule{}, egress, false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(txnTracker.CommittedTxns).To(gomega.HaveLen(1))
+
+	// Test connections.
+	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.UDP, somePort, 53)).To(gomega.Equal(ConnActionDenySyn))
+	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.UDP, somePort, 161)).To(gomega.Equal(ConnActionDenySyn))
+	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionAllow))
+	gomega.Expect(aclEngine.ConnectionInternetToPod(googleDNS, Pod1, renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionDenySyn))
+
+	// Audit denied connections.
+	auditTime := time.Now()
+	auditor.Audit(auditTime)
+	events := auditor.GetEvents("", time.Time{})
+	gomega.Expect(events).To(gomega.HaveLen(2))
+	gomega.Expect(events[0].Time).To(gomega.Equal(auditTime))
+	for _, event := range events {
+		gomega.Expect(event.Action).To(gomega.Equal(renderer.ActionDeny.String()))
+		gomega.Expect(event.ACL).ToNot(gomega.BeEmpty())
+		gomega.Expect(event.RuleDestination.Pods).To(gomega.Equal([]podmodel.ID{Pod1}))
+		switch strings.Join(event.Origins, ",") {
+		case "deny-udp:ingress[0]":
+			gomega.Expect(event.RuleSource.Network).To(gomega.Equal(Pod6IP + "/32"))
+			gomega.Expect(event.RuleSource.Pods).To(gomega.BeEmpty()) /* Pod6 is not local */
+			gomega.Expect(event.Hits).To(gomega.BeEquivalentTo(2))
+		case "":
+			/* deny-the-rest */
+			gomega.Expect(event.RuleSource.Network).To(gomega.BeEmpty())
+			gomega.Expect(event.Hits).To(gomega.BeEquivalentTo(1))
+		default:
+			t.Fatalf("unexpected origins: %v", event.Origins)
+		}
+	}
+	gomega.Expect(auditor.GetEvents(Pod6.Namespace, time.Time{})).To(gomega.BeEmpty())
+
+	// Audit also allowed connections.
+	auditor.SetNamespaceMode(Pod1.Namespace, ParseAuditMode("all"))
+	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionAllow))
+	auditTime = auditTime.Add(time.Second)
+	auditor.Audit(auditTime)
+	events = auditor.GetEvents(Pod1.Namespace, auditTime)
+	gomega.Expect(events).To(gomega.HaveLen(1))
+	gomega.Expect(events[0].Action).To(gomega.Equal(renderer.ActionPermit.String()))
+	gomega.Expect(events[0].Origins).To(gomega.Equal([]string{"default/allow-tcp:ingress[1]"}))
+	gomega.Expect(events[0].Hits).To(gomega.BeEquivalentTo(1))
+
+	// Disable the audit, rule hits are still counted for the policy hit metrics.
+	policyHits.Start()
+	auditor.SetNamespaceMode(Pod1.Namespace, ParseAuditMode(""))
+	gomega.Expect(counters.IsEnabled()).To(gomega.BeTrue())
+	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.UDP, somePort, 53)).To(gomega.Equal(ConnActionDenySyn))
+	auditor.Audit(auditTime.Add(time.Second))
+	gomega.Expect(auditor.GetEvents("", time.Time{})).To(gomega.HaveLen(3))
+
+	// Hits are summed up per policy.
+	gomega.Expect(policyHits.GetPolicyHits()).To(gomega.Equal([]PolicyHits{
+		{Policy: "default/allow-tcp", Hits: 2},
+		{Policy: "deny-udp", Hits: 3},
+	}))
+
+	// Without users the rule hits are no longer counted.
+	counters.Enable(policyHitsCounterUser, false)
+	gomega.Expect(counters.IsEnabled()).To(gomega.BeFalse())
+	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.UDP, somePort, 53)).To(gomega.Equal(ConnActionDenySyn))
+	gomega.Expect(policyHits.GetPolicyHits()).To(gomega.Equal([]PolicyHits{
+		{Policy: "default/allow-tcp", Hits: 2},
+		{Policy: "deny-udp", Hits: 3},
+	}))
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAuditLogRotation")
+
+	dir, err := ioutil.TempDir("", "policy-audit")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "audit.log")
+
+	event := &restapi.AuditEvent{Action: renderer.ActionDeny.String(), Hits: 1}
+	eventData, err := json.Marshal(event)
+	gomega.Expect(err).To(gomega.BeNil())
+	eventSize := int64(len(eventData) + 1)
+
+	// Prepare Auditor with the log file fitting two events.
+	auditor := &Auditor{
+		AuditorDeps: AuditorDeps{
+			Log:            logger,
+			LogFile:        logFile,
+			LogFileMaxSize: 2 * eventSize,
+		},
+	}
+	gomega.Expect(auditor.Init()).To(gomega.BeNil())
+	for i := 0; i < 3; i++ {
+		auditor.reportEvent(event)
+	}
+	gomega.Expect(auditor.Close()).To(gomega.BeNil())
+
+	// The third event was written into a new file.
+	rotated, err := os.Stat(logFile + rotatedLogFileSuffix)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(rotated.Size()).To(gomega.Equal(2 * eventSize))
+	current, err := os.Stat(logFile)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(current.Size()).To(gomega.Equal(eventSize))
+}
+
+func TestAuditGlobalTable(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAuditGlobalTable")
+
+	// Prepare Auditor with local ACLs applied to Pod1 and Pod2.
+	ipam := &ipamMock{pods: map[string]podmodel.ID{Pod1IP: Pod1, Pod2IP: Pod2}}
+	auditor := &Auditor{
+		AuditorDeps: AuditorDeps{
+			Log:  logger,
+			IPAM: ipam,
+		},
+	}
+	gomega.Expect(auditor.Init()).To(gomega.BeNil())
+	auditor.nsModes[Pod1.Namespace] = AuditDenied
+	denyRest := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		SrcNetwork:  IpNetwork(""),
+		DestNetwork: IpNetwork(""),
+		Protocol:    renderer.ANY,
+	}
+	localTable := cache.NewContivRuleTable(cache.Local)
+	localTable.Pods = cache.NewPodSet(Pod1, Pod2)
+	localTable.InsertRule(denyRest)
+	auditor.UpdateACL("local", localTable)
+	globalTable := cache.NewContivRuleTable(cache.Global)
+	auditor.UpdateACL("global", globalTable)
+	now := time.Now()
+
+	// Deny-the-rest of a local table - destined to the pods with the ACL applied.
+	event := auditor.newEvent(now, "local", auditor.tracker.acls["local"], denyRest, 1)
+	gomega.Expect(event).ToNot(gomega.BeNil())
+	gomega.Expect(event.RuleSource.Pods).To(gomega.BeEmpty())
+	gomega.Expect(event.RuleDestination.Pods).To(gomega.Equal([]podmodel.ID{Pod1, Pod2}))
+
+	// Deny of the global table with any source - sent by one of the local pods.
+	event = auditor.newEvent(now, "global", auditor.tracker.acls["global"], denyRest, 1)
+	gomega.Expect(event).ToNot(gomega.BeNil())
+	gomega.Expect(event.RuleSource.Pods).To(gomega.Equal([]podmodel.ID{Pod1, Pod2}))
+	gomega.Expect(event.RuleDestination.Pods).To(gomega.BeEmpty())
+
+	// Deny of the global table with a source network not including any local pod.
+	denyNetwork := denyRest.Copy()
+	denyNetwork.SrcNetwork = IpNetwork("192.168.0.0/16")
+	event = auditor.newEvent(now, "global", auditor.tracker.acls["global"], denyNetwork, 1)
+	gomega.Expect(event).To(gomega.BeNil())
+}
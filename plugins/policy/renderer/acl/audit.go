@@ -0,0 +1,392 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+	"github.com/americanbinary/vpp/plugins/policy/renderer/cache"
+	"github.com/americanbinary/vpp/plugins/policy/restapi"
+)
+
+// AuditAnnotation is the namespace annotation used to enable the policy audit
+// for the traffic of pods from the namespace. Supported values are "deny"
+// (audit denied traffic) and "all" (audit also allowed traffic).
+const AuditAnnotation = "contivpp.io/policy-audit"
+
+// AuditMode selects which rule hits are audited.
+type AuditMode int
+
+const (
+	// AuditOff disables the audit.
+	AuditOff AuditMode = iota
+
+	// AuditDenied enables the audit of the denied traffic.
+	AuditDenied
+
+	// AuditAll enables the audit of both the denied and the allowed traffic.
+	AuditAll
+)
+
+// ParseAuditMode parses the value of the AuditAnnotation.
+func ParseAuditMode(value string) AuditMode {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "deny":
+		return AuditDenied
+	case "all":
+		return AuditAll
+	}
+	return AuditOff
+}
+
+// IPAM interface lists methods from IPAM plugin which are needed by Auditor.
+type IPAM interface {
+	// GetPodFromIP returns the pod information related to the allocated pod IP.
+	GetPodFromIP(podIP net.IP) (podID podmodel.ID, found bool)
+
+	// GetPodIP returns the allocated pod IP, together with the mask
+	// (nil if the pod has no IP allocated on this node).
+	GetPodIP(podID podmodel.ID) *net.IPNet
+}
+
+// auditCounterUser identifies the Auditor among the users of the CounterSwitch.
+const auditCounterUser = "policy-audit"
+
+// Auditor periodically reads hit counters of the rules of rendered ACLs and reports
+// hits of the rules applied to the traffic of audited namespaces as audit events,
+// attributed to the policy rules the ACL rules were derived from.
+// Events aggregate hits per rule, not per connection - the source and destination
+// of an event are given by the rule and by the pods the ACL is applied to.
+// Events are written into a log file and kept in memory for the REST API.
+// Counting of the rule hits is enabled (via CounterSwitch) only while some
+// namespace is audited.
+type Auditor struct {
+	AuditorDeps
+
+	sync.Mutex
+	tracker     *aclHitsTracker
+	nsModes     map[string]AuditMode  // by namespace, audited namespaces only
+	events      []*restapi.AuditEvent // ring buffer with recent events
+	nextEvent   int                   // index in <events> to write the next event into
+	logFile     *os.File              // nil if not enabled
+	logFileSize int64
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// AuditorDeps lists dependencies of Auditor.
+type AuditorDeps struct {
+	Log            logging.Logger
+	IPAM           IPAM
+	RuleHits       RuleHitCounters
+	Counters       *CounterSwitch
+	LogFile        string        // empty = do not write events into a file
+	LogFileMaxSize int64         // in bytes, the log file is rotated once reached, <= 0 = unlimited
+	Period         time.Duration // period of reading the hit counters
+	MaxEvents      int           // number of recent events kept in memory
+}
+
+// rotatedLogFileSuffix is appended to the path of the audit log file when it gets
+// rotated after reaching the maximum size.
+const rotatedLogFileSuffix = ".1"
+
+// Init initializes the Auditor.
+func (a *Auditor) Init() error {
+	a.tracker = newACLHitsTracker()
+	a.nsModes = make(map[string]AuditMode)
+	a.stopCh = make(chan struct{})
+	if a.LogFile != "" {
+		if err := os.MkdirAll(filepath.Dir(a.LogFile), 0755); err != nil {
+			return err
+		}
+		logFile, err := os.OpenFile(a.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		logFileInfo, err := logFile.Stat()
+		if err != nil {
+			logFile.Close()
+			return err
+		}
+		a.logFile = logFile
+		a.logFileSize = logFileInfo.Size()
+	}
+	return nil
+}
+
+// Start starts periodic reading of the rule hit counters.
+func (a *Auditor) Start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.Period)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				a.Audit(now)
+			case <-a.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the Auditor and closes the log file.
+func (a *Auditor) Close() error {
+	close(a.stopCh)
+	a.wg.Wait()
+	if a.logFile != nil {
+		return a.logFile.Close()
+	}
+	return nil
+}
+
+// SetNamespaceMode sets the audit mode for the given namespace.
+func (a *Auditor) SetNamespaceMode(namespace string, mode AuditMode) {
+	a.Lock()
+	defer a.Unlock()
+	if mode == AuditOff {
+		delete(a.nsModes, namespace)
+	} else {
+		a.nsModes[namespace] = mode
+	}
+	a.Counters.Enable(auditCounterUser, len(a.nsModes) > 0)
+}
+
+// ResyncNamespaceModes replaces audit modes of all namespaces.
+func (a *Auditor) ResyncNamespaceModes(modes map[string]AuditMode) {
+	a.Lock()
+	defer a.Unlock()
+	a.nsModes = make(map[string]AuditMode)
+	for namespace, mode := range modes {
+		if mode != AuditOff {
+			a.nsModes[namespace] = mode
+		}
+	}
+	a.Counters.Enable(auditCounterUser, len(a.nsModes) > 0)
+}
+
+// UpdateACL updates snapshot of the ACL rendered from the given table.
+func (a *Auditor) UpdateACL(aclName string, table *cache.ContivRuleTable) {
+	a.Lock()
+	defer a.Unlock()
+	a.tracker.updateACL(aclName, table)
+}
+
+// RemoveACL removes snapshot of the given ACL.
+func (a *Auditor) RemoveACL(aclName string) {
+	a.Lock()
+	defer a.Unlock()
+	a.tracker.removeACL(aclName)
+}
+
+// RemoveAllACLs removes snapshots of all ACLs (used with resync).
+func (a *Auditor) RemoveAllACLs() {
+	a.Lock()
+	defer a.Unlock()
+	a.tracker.removeAllACLs()
+}
+
+// Audit reads hit counters of all rendered ACLs and reports hits observed since
+// the last audit.
+func (a *Auditor) Audit(now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	if !a.Counters.IsEnabled() {
+		return
+	}
+	// counters are read even if no namespace is audited (enabled by another user),
+	// not to report older hits once the audit gets enabled
+	a.tracker.readHits(a.Log, a.RuleHits,
+		func(aclName string, acl *trackedACL, rule *renderer.ContivRule, hits uint64) {
+			if event := a.newEvent(now, aclName, acl, rule, hits); event != nil {
+				a.reportEvent(event)
+			}
+		})
+}
+
+// GetEvents returns recent audit events (ordered by time) related to the given
+// namespace (all if empty) and not older than <since>.
+func (a *Auditor) GetEvents(namespace string, since time.Time) (events []*restapi.AuditEvent) {
+	a.Lock()
+	defer a.Unlock()
+	events = []*restapi.AuditEvent{}
+	for i := 0; i < len(a.events); i++ {
+		event := a.events[(a.nextEvent+i)%len(a.events)]
+		if event.Time.Before(since) {
+			continue
+		}
+		if namespace != "" && !eventHasNamespace(event, namespace) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// newEvent creates audit event for hits of the given rule, returns nil if the rule
+// is not audited.
+func (a *Auditor) newEvent(now time.Time, aclName string, acl *trackedACL,
+	rule *renderer.ContivRule, hits uint64) *restapi.AuditEvent {
+
+	event := &restapi.AuditEvent{
+		Time:            now,
+		Action:          rule.Action.String(),
+		Origins:         rule.OriginStrings(),
+		Rule:            rule.String(),
+		ACL:             aclName,
+		RuleSource:      a.ruleEndpoint(rule.SrcNetwork),
+		RuleDestination: a.ruleEndpoint(rule.DestNetwork),
+		Hits:            hits,
+	}
+	if acl.isGlobal {
+		// global table is applied on the output side of the node interfaces,
+		// the traffic was sent by one of the local pods from the source network
+		if len(event.RuleSource.Pods) == 0 {
+			event.RuleSource.Pods = a.localPodsInNetwork(rule.SrcNetwork)
+		}
+	} else {
+		// local table is applied on the output side of the pod interfaces,
+		// the traffic was destined to one of the pods with the ACL applied
+		event.RuleDestination.Pods = acl.pods
+	}
+
+	// the most verbose mode among the namespaces of the source and destination pods applies
+	mode := AuditOff
+	for _, podID := range append(event.RuleSource.Pods, event.RuleDestination.Pods...) {
+		if nsMode := a.nsModes[podID.Namespace]; nsMode > mode {
+			mode = nsMode
+		}
+	}
+	if mode == AuditOff || (rule.Action == renderer.ActionPermit && mode != AuditAll) {
+		return nil
+	}
+	return event
+}
+
+// ruleEndpoint returns audit endpoint for the source/destination network of a rule.
+func (a *Auditor) ruleEndpoint(network *net.IPNet) (endpoint restapi.AuditRuleEndpoint) {
+	if len(network.IP) == 0 {
+		return endpoint
+	}
+	endpoint.Network = network.String()
+	if ones, bits := network.Mask.Size(); ones == bits {
+		if podID, found := a.IPAM.GetPodFromIP(network.IP); found {
+			endpoint.Pods = []podmodel.ID{podID}
+		}
+	}
+	return endpoint
+}
+
+// localPodsInNetwork returns pods with a local ACL applied (i.e. deployed on this node)
+// with IP address from the given network (all if the network is empty).
+func (a *Auditor) localPodsInNetwork(network *net.IPNet) (pods []podmodel.ID) {
+	seen := make(map[podmodel.ID]struct{})
+	for _, acl := range a.tracker.acls {
+		for _, podID := range acl.pods {
+			if _, duplicate := seen[podID]; duplicate {
+				continue
+			}
+			seen[podID] = struct{}{}
+			if len(network.IP) > 0 {
+				podIP := a.IPAM.GetPodIP(podID)
+				if podIP == nil || !network.Contains(podIP.IP) {
+					continue
+				}
+			}
+			pods = append(pods, podID)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].String() < pods[j].String()
+	})
+	return pods
+}
+
+// reportEvent stores the event into the buffer and writes it into the log file.
+func (a *Auditor) reportEvent(event *restapi.AuditEvent) {
+	if a.MaxEvents > 0 {
+		if len(a.events) < a.MaxEvents {
+			a.events = append(a.events, event)
+		} else {
+			a.events[a.nextEvent] = event
+			a.nextEvent = (a.nextEvent + 1) % a.MaxEvents
+		}
+	}
+	if a.logFile != nil {
+		if err := a.writeEvent(event); err != nil {
+			a.Log.Warnf("Failed to write policy audit event: %v", err)
+		}
+	}
+}
+
+// writeEvent appends the event into the log file, which is rotated first
+// if the maximum size would be exceeded.
+func (a *Auditor) writeEvent(event *restapi.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if a.LogFileMaxSize > 0 && a.logFileSize > 0 && a.logFileSize+int64(len(data)) > a.LogFileMaxSize {
+		if err = a.rotateLogFile(); err != nil {
+			return err
+		}
+	}
+	written, err := a.logFile.Write(data)
+	a.logFileSize += int64(written)
+	return err
+}
+
+// rotateLogFile renames the log file to "<path>.1" (replacing the previously
+// rotated file) and starts a new file.
+func (a *Auditor) rotateLogFile() error {
+	if err := a.logFile.Close(); err != nil {
+		return err
+	}
+	a.logFile = nil
+	if err := os.Rename(a.LogFile, a.LogFile+rotatedLogFileSuffix); err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(a.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	a.logFile = logFile
+	a.logFileSize = 0
+	return nil
+}
+
+// eventHasNamespace returns true if the event involves a pod from the given namespace.
+func eventHasNamespace(event *restapi.AuditEvent, namespace string) bool {
+	for _, podID := range append(event.RuleSource.Pods, event.RuleDestination.Pods...) {
+		if podID.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
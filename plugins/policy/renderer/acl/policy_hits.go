@@ -0,0 +1,140 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"sort"
+	"sync"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+	"github.com/americanbinary/vpp/plugins/policy/renderer/cache"
+)
+
+// policyHitsCounterUser identifies PolicyHitCounter among the users of the CounterSwitch.
+const policyHitsCounterUser = "policy-hits"
+
+// PolicyHits is the total number of packets matched by the rules derived from
+// the ingress or the egress rules of a policy.
+type PolicyHits struct {
+	Policy string // "namespace/name" of a K8s policy or the name of a cluster policy
+	Egress bool
+	Hits   uint64
+}
+
+// PolicyHitCounter sums up hits of the rules of rendered ACLs per policy and
+// direction, using the origins of the rules. Hits of a rule derived from rules
+// of multiple policies are counted for each of them.
+// Hit counters are read on demand (when the totals are requested), counting
+// of the rule hits is enabled (via CounterSwitch) once the PolicyHitCounter
+// is started.
+type PolicyHitCounter struct {
+	PolicyHitCounterDeps
+
+	sync.Mutex
+	tracker *aclHitsTracker
+	totals  map[policyDirection]uint64 // rendered policies only
+}
+
+// PolicyHitCounterDeps lists dependencies of PolicyHitCounter.
+type PolicyHitCounterDeps struct {
+	Log      logging.Logger
+	RuleHits RuleHitCounters
+	Counters *CounterSwitch
+}
+
+// policyDirection identifies ingress or egress rules of a policy.
+type policyDirection struct {
+	policy string
+	egress bool
+}
+
+// Init initializes the PolicyHitCounter.
+func (c *PolicyHitCounter) Init() {
+	c.tracker = newACLHitsTracker()
+	c.totals = make(map[policyDirection]uint64)
+}
+
+// Start enables counting of the rule hits.
+func (c *PolicyHitCounter) Start() {
+	c.Counters.Enable(policyHitsCounterUser, true)
+}
+
+// UpdateACL updates snapshot of the ACL rendered from the given table.
+func (c *PolicyHitCounter) UpdateACL(aclName string, table *cache.ContivRuleTable) {
+	c.Lock()
+	defer c.Unlock()
+	c.tracker.updateACL(aclName, table)
+}
+
+// RemoveACL removes snapshot of the given ACL.
+func (c *PolicyHitCounter) RemoveACL(aclName string) {
+	c.Lock()
+	defer c.Unlock()
+	c.tracker.removeACL(aclName)
+}
+
+// RemoveAllACLs removes snapshots of all ACLs (used with resync).
+func (c *PolicyHitCounter) RemoveAllACLs() {
+	c.Lock()
+	defer c.Unlock()
+	c.tracker.removeAllACLs()
+}
+
+// GetPolicyHits reads hit counters of all rendered ACLs and returns the total
+// number of packets matched by the rules of every rendered policy (ordered by
+// policy and direction), including policies with no hits so far.
+func (c *PolicyHitCounter) GetPolicyHits() (hits []PolicyHits) {
+	c.Lock()
+	defer c.Unlock()
+
+	rendered := make(map[policyDirection]uint64)
+	for _, acl := range c.tracker.acls {
+		for _, rule := range acl.rules {
+			for _, origin := range rule.Origins {
+				key := policyDirection{policy: origin.Policy, egress: origin.Egress}
+				rendered[key] = c.totals[key]
+			}
+		}
+	}
+	// forget policies which are no longer rendered
+	c.totals = rendered
+
+	if c.Counters.IsEnabled() {
+		c.tracker.readHits(c.Log, c.RuleHits,
+			func(aclName string, acl *trackedACL, rule *renderer.ContivRule, hits uint64) {
+				counted := make(map[policyDirection]struct{})
+				for _, origin := range rule.Origins {
+					key := policyDirection{policy: origin.Policy, egress: origin.Egress}
+					if _, isCounted := counted[key]; !isCounted {
+						c.totals[key] += hits
+						counted[key] = struct{}{}
+					}
+				}
+			})
+	}
+
+	for key, count := range c.totals {
+		hits = append(hits, PolicyHits{Policy: key.policy, Egress: key.egress, Hits: count})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Policy != hits[j].Policy {
+			return hits[i].Policy < hits[j].Policy
+		}
+		return !hits[i].Egress && hits[j].Egress
+	})
+	return hits
+}
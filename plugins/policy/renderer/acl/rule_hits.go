@@ -0,0 +1,211 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"sort"
+	"sync"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+	"github.com/americanbinary/vpp/plugins/policy/renderer/cache"
+)
+
+// RuleHitCounters provides hit counters of the rules of ACLs installed in VPP.
+type RuleHitCounters interface {
+	// EnableCounters enables or disables counting of the rule hits.
+	EnableCounters(enable bool) error
+
+	// GetACLRuleHits returns the number of packets matched by each rule
+	// of the given ACL (indexed the same as the rules of the rendered ACL).
+	GetACLRuleHits(aclName string) (hits []uint64, err error)
+}
+
+// ACLObserver is notified about ACLs rendered by the Renderer.
+type ACLObserver interface {
+	// UpdateACL is called with the table the given ACL was (re-)rendered from.
+	UpdateACL(aclName string, table *cache.ContivRuleTable)
+
+	// RemoveACL is called when the given ACL is removed.
+	RemoveACL(aclName string)
+
+	// RemoveAllACLs is called before all ACLs are re-rendered with resync.
+	RemoveAllACLs()
+}
+
+// CounterSwitch enables counting of the ACL rule hits in VPP, which slows down
+// the ACL processing, only while at least one of its users needs the counters.
+type CounterSwitch struct {
+	Log      logging.Logger
+	RuleHits RuleHitCounters
+
+	sync.Mutex
+	users   map[string]struct{}
+	enabled bool
+}
+
+// Enable enables (or disables) counting of the rule hits for the given user.
+func (cs *CounterSwitch) Enable(user string, enable bool) {
+	cs.Lock()
+	defer cs.Unlock()
+	if cs.users == nil {
+		cs.users = make(map[string]struct{})
+	}
+	if enable {
+		cs.users[user] = struct{}{}
+	} else {
+		delete(cs.users, user)
+	}
+	if enabled := len(cs.users) > 0; enabled != cs.enabled {
+		cs.apply(enabled)
+	}
+}
+
+// Resync re-applies the state of the counters (VPP may have been restarted).
+func (cs *CounterSwitch) Resync() {
+	cs.Lock()
+	defer cs.Unlock()
+	cs.apply(len(cs.users) > 0)
+}
+
+// IsEnabled returns true if counting of the rule hits is enabled.
+func (cs *CounterSwitch) IsEnabled() bool {
+	cs.Lock()
+	defer cs.Unlock()
+	return cs.enabled
+}
+
+// apply enables/disables counting of the rule hits in VPP.
+func (cs *CounterSwitch) apply(enable bool) {
+	if err := cs.RuleHits.EnableCounters(enable); err != nil {
+		cs.Log.Warnf("Failed to enable/disable (%t) counting of the ACL rule hits: %v", enable, err)
+		return
+	}
+	cs.enabled = enable
+}
+
+// aclHitsTracker keeps a snapshot of the rules of every rendered ACL together
+// with the hit counters read last time, to tell the hits observed since then.
+// The tracker is not thread-safe.
+type aclHitsTracker struct {
+	acls map[string]*trackedACL // by ACL name
+}
+
+// trackedACL is a snapshot of a rendered ACL.
+type trackedACL struct {
+	rules    []*renderer.ContivRule // rule from which each ACL rule was rendered
+	pods     []podmodel.ID          // pods with the ACL applied (nil for the global table)
+	isGlobal bool                   // true for the global table
+	hits     []uint64               // hit counters read last time
+}
+
+// newACLHitsTracker is a constructor for aclHitsTracker.
+func newACLHitsTracker() *aclHitsTracker {
+	return &aclHitsTracker{acls: make(map[string]*trackedACL)}
+}
+
+// updateACL updates snapshot of the ACL rendered from the given table.
+// Hit counters of the ACL are expected to restart from zero if the rules
+// have changed.
+func (t *aclHitsTracker) updateACL(aclName string, table *cache.ContivRuleTable) {
+	acl := &trackedACL{}
+	for i := 0; i < table.NumOfRules; i++ {
+		rule := table.Rules[i]
+		if isUnsupportedRule(rule) {
+			// not rendered (see renderACL)
+			continue
+		}
+		acl.rules = append(acl.rules, rule)
+		if len(rule.SrcNetwork.IP) == 0 && len(rule.DestNetwork.IP) == 0 {
+			// rendered for both IPv4 and IPv6 (see expandAnyAddr)
+			acl.rules = append(acl.rules, rule)
+		}
+	}
+	acl.isGlobal = table.Type == cache.Global
+	if table.Type == cache.Local {
+		for podID := range table.Pods {
+			acl.pods = append(acl.pods, podID)
+		}
+		sort.Slice(acl.pods, func(i, j int) bool {
+			return acl.pods[i].String() < acl.pods[j].String()
+		})
+	}
+	acl.hits = make([]uint64, len(acl.rules))
+	if prevACL, hasACL := t.acls[aclName]; hasACL && sameRules(prevACL.rules, acl.rules) {
+		acl.hits = prevACL.hits
+	}
+	t.acls[aclName] = acl
+}
+
+// removeACL removes snapshot of the given ACL.
+func (t *aclHitsTracker) removeACL(aclName string) {
+	delete(t.acls, aclName)
+}
+
+// removeAllACLs removes snapshots of all ACLs.
+func (t *aclHitsTracker) removeAllACLs() {
+	t.acls = make(map[string]*trackedACL)
+}
+
+// readHits reads hit counters of all tracked ACLs (ordered by name) and calls
+// <onHits> for every rule with hits observed since the last read.
+func (t *aclHitsTracker) readHits(log logging.Logger, ruleHits RuleHitCounters,
+	onHits func(aclName string, acl *trackedACL, rule *renderer.ContivRule, hits uint64)) {
+
+	var aclNames []string
+	for aclName := range t.acls {
+		aclNames = append(aclNames, aclName)
+	}
+	sort.Strings(aclNames)
+	for _, aclName := range aclNames {
+		acl := t.acls[aclName]
+		hits, err := ruleHits.GetACLRuleHits(aclName)
+		if err != nil {
+			log.Debugf("Failed to read hit counters of the ACL %s: %v", aclName, err)
+			continue
+		}
+		if len(hits) != len(acl.rules) {
+			log.Warnf("Unexpected number of hit counters for the ACL %s: %d (expected %d)",
+				aclName, len(hits), len(acl.rules))
+			continue
+		}
+		for i := 0; i < len(hits); i++ {
+			delta := hits[i]
+			if hits[i] >= acl.hits[i] {
+				// otherwise the counter was reset
+				delta -= acl.hits[i]
+			}
+			if delta > 0 {
+				onHits(aclName, acl, acl.rules[i], delta)
+			}
+		}
+		acl.hits = hits
+	}
+}
+
+// sameRules returns true if both lists contain the same rules in the same order.
+func sameRules(rules1, rules2 []*renderer.ContivRule) bool {
+	if len(rules1) != len(rules2) {
+		return false
+	}
+	for i := range rules1 {
+		if rules1[i].Compare(rules2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -66,14 +66,82 @@ type ContivRule struct {
 	// Action to perform when traffic matches.
 	Action ActionType
 
+	// Precedence of the rule, rules with higher precedence are evaluated first
+	// regardless of how specific they are. Rules of namespace-scoped policies
+	// have precedence 0, rules derived from cluster policies are assigned higher
+	// precedence by the configurator according to tiers and priorities.
+	// Rules with the same (non-zero) precedence always share the same action.
+	Precedence uint32
+
 	// L3
 	SrcNetwork  *net.IPNet // empty = match all
 	DestNetwork *net.IPNet // empty = match all
 
 	// L4
-	Protocol ProtocolType
-	SrcPort  uint16 // 0 = match all
-	DestPort uint16 // 0 = match all
+	Protocol       ProtocolType
+	ProtocolNumber uint8  // IP protocol number, used only with OTHER
+	SrcPort        uint16 // 0 = match all (not used with OTHER)
+	DestPort       uint16 // 0 = match all (not used with OTHER)
+	DestPortEnd    uint16 // end of the destination port range (inclusive), 0 = single port DestPort
+
+	// Origins of the rule - policy rules the rule was derived from (more than one
+	// if identical rules derived from different policy rules were merged), empty
+	// for rules not derived from policy rules (e.g. the deny-the-rest rule).
+	// Used only for auditing, origins are not considered when rules are compared.
+	Origins []*RuleOrigin
+}
+
+// RuleOrigin identifies the policy rule from which a Contiv rule was derived.
+type RuleOrigin struct {
+	// Policy is "namespace/name" of a K8s policy or the name of a cluster policy.
+	Policy string
+
+	// Egress is true if the origin is an egress rule (from the pod point of view).
+	Egress bool
+
+	// RuleIndex is the index of the rule among the ingress/egress rules of the policy.
+	RuleIndex int
+}
+
+// String converts RuleOrigin (pointer) into a human-readable string
+// representation.
+func (ro *RuleOrigin) String() string {
+	direction := "ingress"
+	if ro.Egress {
+		direction = "egress"
+	}
+	return fmt.Sprintf("%s:%s[%d]", ro.Policy, direction, ro.RuleIndex)
+}
+
+// AddOrigins adds origins not yet listed among the origins of the rule.
+// The list of origins is never modified in place, it may be shared with copies
+// of the rule.
+func (cr *ContivRule) AddOrigins(origins []*RuleOrigin) {
+	merged := cr.Origins
+	for _, origin := range origins {
+		if !hasOrigin(merged, origin) {
+			merged = append(merged[:len(merged):len(merged)], origin)
+		}
+	}
+	cr.Origins = merged
+}
+
+// OriginStrings returns origins of the rule converted to strings.
+func (cr *ContivRule) OriginStrings() (origins []string) {
+	for _, origin := range cr.Origins {
+		origins = append(origins, origin.String())
+	}
+	return origins
+}
+
+// hasOrigin returns true if the given origin is included in the list.
+func hasOrigin(origins []*RuleOrigin, origin *RuleOrigin) bool {
+	for _, listed := range origins {
+		if *listed == *origin {
+			return true
+		}
+	}
+	return false
 }
 
 // String converts Contiv Rule (pointer) into a human-readable string
@@ -93,11 +161,33 @@ func (cr *ContivRule) String() string {
 	if cr.SrcPort != 0 {
 		srcPort = strconv.Itoa(int(cr.SrcPort))
 	}
-	if cr.DestPort != 0 {
-		dstPort = strconv.Itoa(int(cr.DestPort))
+	if lower, upper := cr.DestPortRange(); lower != upper {
+		dstPort = strconv.Itoa(int(lower)) + "-" + strconv.Itoa(int(upper))
+	} else if lower != 0 {
+		dstPort = strconv.Itoa(int(lower))
+	}
+	protocol := cr.Protocol.String()
+	if cr.Protocol == OTHER {
+		protocol = fmt.Sprintf("%s(%d)", protocol, cr.ProtocolNumber)
+	}
+	action := cr.Action.String()
+	if cr.Precedence > 0 {
+		action = fmt.Sprintf("%s(%d)", action, cr.Precedence)
 	}
 	return fmt.Sprintf("Rule <%s %s[%s:%s] -> %s[%s:%s]>",
-		cr.Action, srcNet, cr.Protocol, srcPort, dstNet, cr.Protocol, dstPort)
+		action, srcNet, protocol, srcPort, dstNet, protocol, dstPort)
+}
+
+// DestPortRange returns the range of destination ports matched by the rule
+// (both bounds inclusive). For rule matching all ports, (0, 0) is returned.
+func (cr *ContivRule) DestPortRange() (lower, upper uint16) {
+	if cr.DestPort == 0 {
+		return 0, 0
+	}
+	if cr.DestPortEnd > cr.DestPort {
+		return cr.DestPort, cr.DestPortEnd
+	}
+	return cr.DestPort, cr.DestPort
 }
 
 // Copy creates a deep copy of the Contiv rule.
@@ -109,8 +199,13 @@ func (cr *ContivRule) Copy() *ContivRule {
 
 // Compare returns -1, 0, 1 if this<cr2 or this==cr2 or this>cr2, respectively.
 // Contiv rules have a total order defined on them.
-// It holds that if cr matches subset of the traffic matched by cr2, then cr<cr2.
+// It holds that if cr matches subset of the traffic matched by cr2, then cr<cr2,
+// unless cr2 has higher precedence (rules with higher precedence go first).
 func (cr *ContivRule) Compare(cr2 *ContivRule) int {
+	precedenceOrder := utils.CompareInts(int(cr2.Precedence), int(cr.Precedence))
+	if precedenceOrder != 0 {
+		return precedenceOrder
+	}
 	srcIPOrder := utils.CompareIPNets(cr.SrcNetwork, cr2.SrcNetwork)
 	if srcIPOrder != 0 {
 		return srcIPOrder
@@ -123,12 +218,19 @@ func (cr *ContivRule) Compare(cr2 *ContivRule) int {
 	if protocolOrder != 0 {
 		return protocolOrder
 	}
-	if cr.Protocol != ANY {
+	if cr.Protocol == OTHER {
+		protocolNumOrder := utils.CompareInts(int(cr.ProtocolNumber), int(cr2.ProtocolNumber))
+		if protocolNumOrder != 0 {
+			return protocolNumOrder
+		}
+	} else if cr.Protocol != ANY {
 		srcPortOrder := utils.ComparePorts(cr.SrcPort, cr2.SrcPort)
 		if srcPortOrder != 0 {
 			return srcPortOrder
 		}
-		dstPortOrder := utils.ComparePorts(cr.DestPort, cr2.DestPort)
+		dstLower, dstUpper := cr.DestPortRange()
+		dstLower2, dstUpper2 := cr2.DestPortRange()
+		dstPortOrder := utils.ComparePortRanges(dstLower, dstUpper, dstLower2, dstUpper2)
 		if dstPortOrder != 0 {
 			return dstPortOrder
 		}
@@ -158,7 +260,7 @@ func (at ActionType) String() string {
 	return "INVALID"
 }
 
-// ProtocolType is either TCP or UDP or OTHER.
+// ProtocolType is either TCP, UDP, SCTP or OTHER.
 type ProtocolType int
 
 const (
@@ -168,7 +270,11 @@ const (
 	// UDP protocol.
 	UDP
 
-	// OTHER is some NON-UDP, NON-TCP traffic (used ONLY in unit tests).
+	// SCTP protocol.
+	SCTP
+
+	// OTHER is an IP protocol given by number (ContivRule.ProtocolNumber),
+	// port numbers are ignored.
 	OTHER
 
 	// ANY L4 protocol or even pure L3 traffic (port numbers are ignored).
@@ -182,6 +288,8 @@ func (at ProtocolType) String() string {
 		return "TCP"
 	case UDP:
 		return "UDP"
+	case SCTP:
+		return "SCTP"
 	case OTHER:
 		return "OTHER"
 	case ANY:
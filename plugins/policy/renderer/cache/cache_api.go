@@ -264,10 +264,11 @@ func (crt *ContivRuleTable) GetID() string {
 
 // InsertRule inserts the rule into the table at the right order.
 // Returns *true* if the rule was inserted, *false* if the same rule is already
-// in the cache.
+// in the cache (origins of the rule are then added to the existing one).
 func (crt *ContivRuleTable) InsertRule(rule *renderer.ContivRule) bool {
 	idx, inserted := crt.getRuleIndex(rule)
 	if inserted {
+		crt.Rules[idx].AddOrigins(rule.Origins)
 		return false
 	}
 	if crt.NumOfRules == len(crt.Rules) {
@@ -518,29 +518,32 @@ func (rct *RendererCacheTxn) buildLocalTable(dstPodID podmodel.ID, dstPodCfg *Po
 // and the destination pod is maintained.
 func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPodCfg *PodConfig, srcPodCfg *PodConfig) {
 	// Determine the set of accessible ports from the source pod point of view.
-	var srcTCP, srcUDP Ports
-	var srcAny bool
+	var srcPorts L4Ports
+	var srcOthers bool
 	if rct.cache.orientation == EgressOrientation {
-		srcTCP, srcUDP, srcAny = getAllowedIngressPorts(dstPodCfg.PodIP, srcPodCfg.Ingress)
+		srcPorts, srcOthers = getAllowedIngressPorts(dstPodCfg.PodIP, srcPodCfg.Ingress)
 	} else {
-		srcTCP, srcUDP, srcAny = getAllowedEgressPorts(dstPodCfg.PodIP, srcPodCfg.Egress)
+		srcPorts, srcOthers = getAllowedEgressPorts(dstPodCfg.PodIP, srcPodCfg.Egress)
 	}
 
 	// Determine the set of accessible ports from the destination pod point of view.
-	var dstTCP, dstUDP Ports
-	var dstAny bool
+	var dstPorts L4Ports
+	var dstOthers bool
+	var dstRules []*renderer.ContivRule
 	if rct.cache.orientation == EgressOrientation {
-		dstTCP, dstUDP, dstAny = getAllowedEgressPorts(srcPodCfg.PodIP, dstPodCfg.Egress)
+		dstPorts, dstOthers = getAllowedEgressPorts(srcPodCfg.PodIP, dstPodCfg.Egress)
+		dstRules = dstPodCfg.Egress
 	} else {
-		dstTCP, dstUDP, dstAny = getAllowedIngressPorts(srcPodCfg.PodIP, dstPodCfg.Ingress)
+		dstPorts, dstOthers = getAllowedIngressPorts(srcPodCfg.PodIP, dstPodCfg.Ingress)
+		dstRules = dstPodCfg.Ingress
 	}
 
-	if srcAny {
+	if allAllowed(srcPorts, srcOthers) {
 		return
 	}
 
 	// Intersect allowed traffic
-	if dstAny || !dstTCP.IsSubsetOf(srcTCP) || !dstUDP.IsSubsetOf(srcUDP) {
+	if !isAllowedSubset(dstPorts, dstOthers, srcPorts, srcOthers) {
 		// cleanup rule subtree with the root node:
 		// 	(egress orientation)  srcIP:ANY:0 -> 0/0:ANY:0
 		// 	(ingress orientation) 0/0:ANY:0   -> srcIP:ANY:0
@@ -560,21 +563,32 @@ func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPod
 			}
 			return true
 		})
-		// Intersect TCP.
-		allowedTCP := dstTCP.Intersection(srcTCP)
-		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedTCP, renderer.TCP)
-		// Intersect UDP.
-		allowedUDP := dstUDP.Intersection(srcUDP)
-		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedUDP, renderer.UDP)
-		// Add the "deny-the-rest" rule.
+		// The combined rules have to take precedence over (cluster) rules
+		// of the destination pod.
+		var precedence uint32
+		for _, rule := range dstRules {
+			if rule.Precedence > 0 && rule.Precedence >= precedence {
+				precedence = rule.Precedence + 1
+			}
+		}
+		// Intersect ports of every protocol.
+		allowedPorts, allowOthers := intersectAllowed(dstPorts, dstOthers, srcPorts, srcOthers)
+		for protocol, ports := range allowedPorts {
+			rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, ports, protocol, allowOthers, precedence)
+		}
+		// Add the "deny-the-rest" (or "allow-other-protocols") rule.
 		newRule := &renderer.ContivRule{
 			Action:      renderer.ActionDeny,
+			Precedence:  precedence,
 			SrcNetwork:  &net.IPNet{},
 			DestNetwork: &net.IPNet{},
 			SrcPort:     AnyPort,
 			DestPort:    AnyPort,
 			Protocol:    renderer.ANY,
 		}
+		if allowOthers {
+			newRule.Action = renderer.ActionPermit
+		}
 		if rct.cache.orientation == EgressOrientation {
 			newRule.SrcNetwork = srcPodCfg.PodIP
 		} else {
@@ -587,14 +601,20 @@ func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPod
 // installAllowedPorts modifies the table content such that the source pod will
 // be able to communicate with the table owner only on the selected allowed ports
 // of a given protocol with the rest being blocked.
-func (rct *RendererCacheTxn) installAllowedPorts(dstTable *ContivRuleTable, srcPodIP *net.IPNet, allowedPorts Ports, protocol renderer.ProtocolType) {
+// If <blockRest> is false, the rest of the ports is blocked by the "deny-the-rest"
+// rule (installed by the caller). Rules are installed with the given precedence.
+func (rct *RendererCacheTxn) installAllowedPorts(dstTable *ContivRuleTable, srcPodIP *net.IPNet, allowedPorts Ports,
+	protocol L4Protocol, blockRest bool, precedence uint32) {
+
 	ruleTemplate := &renderer.ContivRule{
-		Action:      renderer.ActionPermit,
-		SrcNetwork:  &net.IPNet{},
-		DestNetwork: &net.IPNet{},
-		SrcPort:     AnyPort,
-		DestPort:    AnyPort,
-		Protocol:    protocol,
+		Action:         renderer.ActionPermit,
+		Precedence:     precedence,
+		SrcNetwork:     &net.IPNet{},
+		DestNetwork:    &net.IPNet{},
+		SrcPort:        AnyPort,
+		DestPort:       AnyPort,
+		Protocol:       protocol.Protocol,
+		ProtocolNumber: protocol.Number,
 	}
 	if rct.cache.orientation == EgressOrientation {
 		ruleTemplate.SrcNetwork = srcPodIP
@@ -608,13 +628,23 @@ func (rct *RendererCacheTxn) installAllowedPorts(dstTable *ContivRuleTable, srcP
 		return
 	}
 
-	// Add explicit rule for each allowed port from the intersection
+	// Add explicit rule for each allowed port (range) from the intersection
 	// of ingress with egress.
-	for port := range allowedPorts {
+	for portRange := range allowedPorts {
 		newRule := ruleTemplate.Copy()
-		newRule.DestPort = port
+		newRule.DestPort = portRange.Lower
+		if portRange.Upper > portRange.Lower {
+			newRule.DestPortEnd = portRange.Upper
+		}
 		dstTable.InsertRule(newRule)
 	}
+
+	if blockRest {
+		// Other protocols are allowed, block the rest of the ports of this one.
+		denyRule := ruleTemplate.Copy()
+		denyRule.Action = renderer.ActionDeny
+		dstTable.InsertRule(denyRule)
+	}
 }
 
 // rebuildGlobalTable rebuilds the content of the global table for the current state
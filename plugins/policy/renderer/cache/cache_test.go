@@ -1823,3 +1823,71 @@ func TestResyncIngressOrientation(t *testing.T) {
 	verifyCachedPods(ruleCache, pods, pods)
 	verifyGlobalTable(ruleCache.GetGlobalTable(), globalTableTxn2, globalTable, globalRulesTxn2)
 }
+
+func TestClusterRulesEgressOrientation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestClusterRulesEgressOrientation")
+
+	// Prepare test data
+	_, podNetwork, _ := net.ParseCIDR("10.10.0.0/16")
+	clusterDenySSH := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		Precedence:  1,
+		SrcNetwork:  podNetwork,
+		DestNetwork: &net.IPNet{},
+		SrcPort:     AnyPort,
+		DestPort:    22,
+		Protocol:    renderer.TCP,
+	}
+	pod1Cfg := &PodConfig{
+		PodIP:   GetOneHostSubnet(Pod1IP),
+		Ingress: []*renderer.ContivRule{},
+		Egress:  []*renderer.ContivRule{clusterDenySSH},
+		Removed: false,
+	}
+	pod2Cfg := &PodConfig{
+		PodIP:   GetOneHostSubnet(Pod2IP),
+		Ingress: []*renderer.ContivRule{allowPodIngress(Pod1IP, 80, renderer.TCP), DenyAll()},
+		Egress:  []*renderer.ContivRule{},
+		Removed: false,
+	}
+
+	// Expected output data:
+	// rules combined from both pods take precedence over the cluster rule of Pod1
+	allowHTTP := allowPodEgress(Pod2IP, 80, renderer.TCP)
+	allowHTTP.Precedence = 2
+	denyRest := blockPodEgress(Pod2IP)
+	denyRest.Precedence = 2
+	pod1LocalRules := []*renderer.ContivRule{
+		allowHTTP, denyRest,
+		clusterDenySSH,
+		AllowAll(),
+	}
+
+	// Create an instance of RendererCache
+	ruleCache := &RendererCache{
+		Deps: Deps{
+			Log: logger,
+		},
+	}
+	ruleCache.Init(EgressOrientation)
+
+	// Run transaction.
+	txn := ruleCache.NewTxn()
+	txn.Update(Pod1, pod1Cfg)
+	txn.Update(Pod2, pod2Cfg)
+	err := txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Verify cache content.
+	verifyPodLocalTable(ruleCache, Pod1, nil, pod1LocalRules, NewPodSet(Pod1))
+
+	// Without rules of Pod2, the cluster rule is left to apply.
+	txn = ruleCache.NewTxn()
+	txn.Update(Pod2, &PodConfig{PodIP: GetOneHostSubnet(Pod2IP), Removed: true})
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	verifyPodLocalTable(ruleCache, Pod1, nil, []*renderer.ContivRule{clusterDenySSH, AllowAll()}, NewPodSet(Pod1))
+}
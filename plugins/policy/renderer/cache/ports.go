@@ -16,17 +16,41 @@ package cache
 
 import (
 	"net"
+	"sort"
 
 	"fmt"
 	"github.com/americanbinary/vpp/plugins/policy/renderer"
 )
 
-// Ports is a set of port numbers.
-type Ports map[uint16]struct{}
+// PortRange is a range of port numbers (both bounds inclusive).
+// For a single port, Lower equals Upper.
+type PortRange struct {
+	Lower uint16
+	Upper uint16
+}
+
+// Contains returns true if the port is inside the range.
+func (pr PortRange) Contains(port uint16) bool {
+	return pr.Lower <= port && port <= pr.Upper
+}
+
+// String converts PortRange into a human-readable string representation.
+func (pr PortRange) String() string {
+	if pr.Lower == pr.Upper {
+		return fmt.Sprintf("%d", pr.Lower)
+	}
+	return fmt.Sprintf("%d-%d", pr.Lower, pr.Upper)
+}
+
+// Ports is a set of port numbers, stored as port ranges.
+type Ports map[PortRange]struct{}
 
 // AnyPort is a constant that represents any port.
 const AnyPort uint16 = 0
 
+// maxPort is the highest port number.
+const maxPort = ^uint16(0)
+
 // NewPorts is a constructor for Ports.
 func NewPorts(portNums ...uint16) Ports {
 	ports := make(Ports)
@@ -38,21 +62,94 @@ func NewPorts(portNums ...uint16) Ports {
 
 // Add port number into the set
 func (p Ports) Add(port uint16) {
-	p[port] = struct{}{}
+	p.AddRange(port, port)
+}
+
+// AddRange adds range of ports <lower>-<upper> into the set.
+// AnyPort as the lower bound represents all ports.
+func (p Ports) AddRange(lower, upper uint16) {
+	if lower == AnyPort || upper < lower {
+		upper = lower
+	}
+	p[PortRange{Lower: lower, Upper: upper}] = struct{}{}
 }
 
 // Has returns true if the given port is in the set.
 func (p Ports) Has(port uint16) bool {
-	return p.HasExplicit(0) || p.HasExplicit(port)
+	if p.HasExplicit(AnyPort) {
+		return true
+	}
+	for portRange := range p {
+		if portRange.Contains(port) {
+			return true
+		}
+	}
+	return false
 }
 
-// HasExplicit returns true if the given port is in the set regardless of AnyPort
-// presence.
+// HasExplicit returns true if the given port was added into the set as a single
+// port, regardless of AnyPort presence.
 func (p Ports) HasExplicit(port uint16) bool {
-	_, has := p[port]
+	_, has := p[PortRange{Lower: port, Upper: port}]
 	return has
 }
 
+// covers returns true if every port from the given range is in the set
+// (possibly in the union of multiple ranges).
+func (p Ports) covers(portRange PortRange) bool {
+	if p.HasExplicit(AnyPort) {
+		return true
+	}
+	if portRange.Lower == AnyPort {
+		return false
+	}
+	// extend the covered prefix of the range until no set range continues it
+	next := int(portRange.Lower)
+	for extended := true; extended; {
+		extended = false
+		for pr := range p {
+			if int(pr.Lower) <= next && next <= int(pr.Upper) {
+				next = int(pr.Upper) + 1
+				extended = true
+			}
+		}
+		if next > int(portRange.Upper) {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractRange removes range of ports <lower>-<upper> from the set.
+// AnyPort as the lower bound removes all ports.
+func (p Ports) subtractRange(lower, upper uint16) {
+	if lower == AnyPort {
+		for portRange := range p {
+			delete(p, portRange)
+		}
+		return
+	}
+	if upper < lower {
+		upper = lower
+	}
+	if p.HasExplicit(AnyPort) {
+		delete(p, PortRange{Lower: AnyPort, Upper: AnyPort})
+		p.AddRange(1, maxPort)
+	}
+	for portRange := range p {
+		if portRange.Upper < lower || portRange.Lower > upper {
+			continue
+		}
+		delete(p, portRange)
+		if portRange.Lower < lower {
+			p.AddRange(portRange.Lower, lower-1)
+		}
+		if portRange.Upper > upper {
+			p.AddRange(upper+1, portRange.Upper)
+		}
+	}
+}
+
 // IsSubsetOf returns true if this set is a subset of <p2>.
 func (p Ports) IsSubsetOf(p2 Ports) bool {
 	if p2.Has(AnyPort) {
@@ -61,8 +158,8 @@ func (p Ports) IsSubsetOf(p2 Ports) bool {
 	if p.Has(AnyPort) {
 		return false
 	}
-	for port := range p {
-		if !p2.Has(port) {
+	for portRange := range p {
+		if !p2.covers(portRange) {
 			return false
 		}
 	}
@@ -78,9 +175,18 @@ func (p Ports) Intersection(p2 Ports) Ports {
 		return p
 	}
 	intersection := NewPorts()
-	for port := range p {
-		if p2.Has(port) {
-			intersection.Add(port)
+	for pr := range p {
+		for pr2 := range p2 {
+			lower, upper := pr.Lower, pr.Upper
+			if pr2.Lower > lower {
+				lower = pr2.Lower
+			}
+			if pr2.Upper < upper {
+				upper = pr2.Upper
+			}
+			if lower <= upper {
+				intersection.AddRange(lower, upper)
+			}
 		}
 	}
 	return intersection
@@ -91,8 +197,8 @@ func (p Ports) Intersection(p2 Ports) Ports {
 func (p Ports) String() string {
 	ports := "{"
 	count := 0
-	for port := range p {
-		ports += fmt.Sprintf("%d", port)
+	for portRange := range p {
+		ports += portRange.String()
 		count++
 		if count < len(p) {
 			ports += ","
@@ -102,70 +208,220 @@ func (p Ports) String() string {
 	return ports
 }
 
-// getAllowedEgressPorts returns allowed destination UDP and TCP ports for a given
-// source pod IP wrt. egress rules.
-func getAllowedEgressPorts(srcIP *net.IPNet, egress []*renderer.ContivRule) (tcp, udp Ports, any bool) {
-	tcp = NewPorts()
-	udp = NewPorts()
+// L4Protocol identifies L4 protocol of a rule - TCP, UDP, SCTP or other
+// IP protocol given by number.
+type L4Protocol struct {
+	Protocol renderer.ProtocolType
+	Number   uint8 // IP protocol number, used only with renderer.OTHER
+}
+
+// L4Ports maps L4 protocols to the sets of ports.
+// For protocols without ports (renderer.OTHER), the set contains only AnyPort.
+type L4Ports map[L4Protocol]Ports
+
+// Add port number for the given protocol into the map.
+func (lp L4Ports) Add(protocol L4Protocol, port uint16) {
+	lp.AddRange(protocol, port, port)
+}
+
+// AddRange adds range of ports for the given protocol into the map.
+func (lp L4Ports) AddRange(protocol L4Protocol, lower, upper uint16) {
+	if _, hasProto := lp[protocol]; !hasProto {
+		lp[protocol] = NewPorts()
+	}
+	lp[protocol].AddRange(lower, upper)
+}
+
+// IsSubsetOf returns true if for every protocol, the set of ports is a subset
+// of the ports of the same protocol in <lp2>.
+func (lp L4Ports) IsSubsetOf(lp2 L4Ports) bool {
+	for protocol, ports := range lp {
+		ports2, hasProto := lp2[protocol]
+		if !hasProto {
+			ports2 = NewPorts()
+		}
+		if !ports.IsSubsetOf(ports2) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersection returns ports which are in this map and in <lp2> for the same protocol.
+func (lp L4Ports) Intersection(lp2 L4Ports) L4Ports {
+	intersection := make(L4Ports)
+	for protocol, ports := range lp {
+		if ports2, hasProto := lp2[protocol]; hasProto {
+			if ports = ports.Intersection(ports2); len(ports) > 0 {
+				intersection[protocol] = ports
+			}
+		}
+	}
+	return intersection
+}
+
+// ruleL4Protocol returns L4 protocol of the given rule.
+func ruleL4Protocol(rule *renderer.ContivRule) L4Protocol {
+	protocol := L4Protocol{Protocol: rule.Protocol}
+	if rule.Protocol == renderer.OTHER {
+		protocol.Number = rule.ProtocolNumber
+	}
+	return protocol
+}
+
+// getAllowedEgressPorts returns allowed destination ports for every L4 protocol
+// for a given source pod IP wrt. egress rules.
+// Protocols not included in the map are all allowed if <others> is true,
+// blocked otherwise. Everything is allowed if others is true and the map is empty.
+func getAllowedEgressPorts(srcIP *net.IPNet, egress []*renderer.ContivRule) (ports L4Ports, others bool) {
+	return getAllowedPorts(srcIP, egress, func(rule *renderer.ContivRule) *net.IPNet {
+		return rule.SrcNetwork
+	})
+}
+
+// getAllowedIngressPorts returns allowed destination ports for every L4 protocol
+// for a given destination pod IP wrt. ingress rules.
+// Protocols not included in the map are all allowed if <others> is true,
+// blocked otherwise. Everything is allowed if others is true and the map is empty.
+func getAllowedIngressPorts(dstIP *net.IPNet, ingress []*renderer.ContivRule) (ports L4Ports, others bool) {
+	return getAllowedPorts(dstIP, ingress, func(rule *renderer.ContivRule) *net.IPNet {
+		return rule.DestNetwork
+	})
+}
+
+// getAllowedPorts returns allowed destination ports for every L4 protocol
+// for a given peer IP wrt. the given rules, where <peerNetwork> selects
+// the network of a rule to match the peer against.
+func getAllowedPorts(peerIP *net.IPNet, rules []*renderer.ContivRule,
+	peerNetwork func(rule *renderer.ContivRule) *net.IPNet) (ports L4Ports, others bool) {
+
+	ports = make(L4Ports)
 	hasDeny := false
-	for _, rule := range egress {
+	var prioritized []*renderer.ContivRule
+	for _, rule := range rules {
+		if rule.Precedence > 0 {
+			prioritized = append(prioritized, rule)
+			continue
+		}
 		if rule.Action == renderer.ActionDeny {
-			// This implementation assumes there is only the single default deny-all rule (for ANY protocol),
-			// or no deny rule at all.
+			// This implementation assumes there is only the single default deny-all rule
+			// (for ANY protocol) among the rules of precedence 0, or no deny rule at all.
 			hasDeny = true
 			continue
 		}
-		if len(rule.SrcNetwork.IP) > 0 && !rule.SrcNetwork.Contains(srcIP.IP) {
+		if network := peerNetwork(rule); len(network.IP) > 0 && !network.Contains(peerIP.IP) {
 			continue
 		}
 		/* matching ALLOW rule */
-		switch rule.Protocol {
-		case renderer.TCP:
-			tcp.Add(rule.DestPort)
-		case renderer.UDP:
-			udp.Add(rule.DestPort)
-		case renderer.ANY:
-			tcp.Add(AnyPort)
-			udp.Add(AnyPort)
-			any = true
+		if rule.Protocol == renderer.ANY {
+			others = true
+			continue
 		}
+		lower, upper := rule.DestPortRange()
+		ports.AddRange(ruleL4Protocol(rule), lower, upper)
 	}
-	if !hasDeny {
-		return NewPorts(AnyPort), NewPorts(AnyPort), true
+	if !hasDeny || others {
+		ports = make(L4Ports)
+		others = true
 	}
-	return tcp, udp, any
-}
 
-// getAllowedIngressPorts returns allowed destination UDP and TCP ports for a given
-// destination pod IP wrt. ingress rules.
-func getAllowedIngressPorts(dstIP *net.IPNet, ingress []*renderer.ContivRule) (tcp, udp Ports, any bool) {
-	tcp = NewPorts()
-	udp = NewPorts()
-	hasDeny := false
-	for _, rule := range ingress {
-		if rule.Action == renderer.ActionDeny {
-			// This implementation assumes there is only the single default deny-all rule (for ANY protocol),
-			// or no deny rule at all.
-			hasDeny = true
+	// Rules with higher precedence (of cluster policies) override the result,
+	// apply them starting with the lowest precedence.
+	// Rules of the same precedence share the same action, hence their order is irrelevant.
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		return prioritized[i].Precedence < prioritized[j].Precedence
+	})
+	for _, rule := range prioritized {
+		if network := peerNetwork(rule); len(network.IP) > 0 && !network.Contains(peerIP.IP) {
 			continue
 		}
-		if len(rule.DestNetwork.IP) > 0 && !rule.DestNetwork.Contains(dstIP.IP) {
+		if rule.Protocol == renderer.ANY {
+			ports = make(L4Ports)
+			others = rule.Action == renderer.ActionPermit
 			continue
 		}
-		/* matching ALLOW rule */
-		switch rule.Protocol {
-		case renderer.TCP:
-			tcp.Add(rule.DestPort)
-		case renderer.UDP:
-			udp.Add(rule.DestPort)
-		case renderer.ANY:
-			tcp.Add(AnyPort)
-			udp.Add(AnyPort)
-			any = true
+		protocol := ruleL4Protocol(rule)
+		if _, hasProto := ports[protocol]; !hasProto {
+			ports[protocol] = NewPorts()
+			if others {
+				ports[protocol].Add(AnyPort)
+			}
 		}
+		lower, upper := rule.DestPortRange()
+		if rule.Action == renderer.ActionPermit {
+			ports[protocol].AddRange(lower, upper)
+		} else {
+			ports[protocol].subtractRange(lower, upper)
+		}
+	}
+
+	// Remove protocols with ports equal to the default.
+	for protocol, protoPorts := range ports {
+		if (others && protoPorts.HasExplicit(AnyPort)) || (!others && len(protoPorts) == 0) {
+			delete(ports, protocol)
+		}
+	}
+	return ports, others
+}
+
+// allAllowed returns true if ports together with <others> allow all traffic.
+func allAllowed(ports L4Ports, others bool) bool {
+	return others && len(ports) == 0
+}
+
+// isAllowedSubset returns true if traffic allowed by <ports> and <others> is a subset
+// of the traffic allowed by <ports2> and <others2> (see getAllowedPorts for
+// the semantics of the arguments).
+func isAllowedSubset(ports L4Ports, others bool, ports2 L4Ports, others2 bool) bool {
+	if others && !others2 {
+		return false
+	}
+	for protocol := range ports.protocols(ports2) {
+		if !ports.get(protocol, others).IsSubsetOf(ports2.get(protocol, others2)) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersectAllowed returns the intersection of the traffic allowed by <ports>
+// and <others> with the traffic allowed by <ports2> and <others2> (see getAllowedPorts
+// for the semantics of the arguments and of the return values).
+func intersectAllowed(ports L4Ports, others bool, ports2 L4Ports, others2 bool) (L4Ports, bool) {
+	intersection := make(L4Ports)
+	intersectionOthers := others && others2
+	for protocol := range ports.protocols(ports2) {
+		protoPorts := ports.get(protocol, others).Intersection(ports2.get(protocol, others2))
+		if intersectionOthers && protoPorts.HasExplicit(AnyPort) {
+			continue
+		}
+		if intersectionOthers || len(protoPorts) > 0 {
+			intersection[protocol] = protoPorts
+		}
+	}
+	return intersection, intersectionOthers
+}
+
+// get returns ports of the given protocol, with missing protocol evaluated
+// as all ports if <others> is true and as no ports otherwise.
+func (lp L4Ports) get(protocol L4Protocol, others bool) Ports {
+	if ports, hasProto := lp[protocol]; hasProto {
+		return ports
+	}
+	if others {
+		return NewPorts(AnyPort)
+	}
+	return NewPorts()
+}
+
+// protocols returns union of protocols from this map and from <lp2>.
+func (lp L4Ports) protocols(lp2 L4Ports) map[L4Protocol]struct{} {
+	protocols := make(map[L4Protocol]struct{})
+	for protocol := range lp {
+		protocols[protocol] = struct{}{}
 	}
-	if !hasDeny {
-		return NewPorts(AnyPort), NewPorts(AnyPort), true
+	for protocol := range lp2 {
+		protocols[protocol] = struct{}{}
 	}
-	return tcp, udp, any
+	return protocols
 }
@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"net"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+	. "github.com/americanbinary/vpp/plugins/policy/utils"
+)
+
+var (
+	tcp  = L4Protocol{Protocol: renderer.TCP}
+	sctp = L4Protocol{Protocol: renderer.SCTP}
+	gre  = L4Protocol{Protocol: renderer.OTHER, Number: 47}
+	esp  = L4Protocol{Protocol: renderer.OTHER, Number: 50}
+)
+
+func TestAllowedPortsWithSCTPAndOtherProtocols(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	const podIP = "10.1.1.1"
+	ingress := []*renderer.ContivRule{
+		allowPodIngress(podIP, 80, renderer.TCP),
+		allowPodIngress(podIP, 3868, renderer.SCTP),
+		{
+			Action:         renderer.ActionPermit,
+			SrcNetwork:     &net.IPNet{},
+			DestNetwork:    GetOneHostSubnet(podIP),
+			Protocol:       renderer.OTHER,
+			ProtocolNumber: 47,
+		},
+		blockPodIngress(podIP),
+	}
+
+	ports, any := getAllowedIngressPorts(GetOneHostSubnet(podIP), ingress)
+	gomega.Expect(any).To(gomega.BeFalse())
+	gomega.Expect(ports).To(gomega.HaveLen(3))
+	gomega.Expect(ports[tcp]).To(gomega.Equal(NewPorts(80)))
+	gomega.Expect(ports[sctp]).To(gomega.Equal(NewPorts(3868)))
+	gomega.Expect(ports[gre]).To(gomega.Equal(NewPorts(AnyPort)))
+
+	// rules of another pod do not apply
+	ports, any = getAllowedIngressPorts(GetOneHostSubnet("10.1.1.2"), ingress)
+	gomega.Expect(any).To(gomega.BeFalse())
+	gomega.Expect(ports).To(gomega.BeEmpty())
+
+	// protocol numbers of OTHER are distinguished
+	allowed := L4Ports{sctp: NewPorts(AnyPort), gre: NewPorts(AnyPort)}
+	gomega.Expect(L4Ports{sctp: NewPorts(3868)}.IsSubsetOf(allowed)).To(gomega.BeTrue())
+	gomega.Expect(L4Ports{gre: NewPorts(AnyPort)}.IsSubsetOf(allowed)).To(gomega.BeTrue())
+	gomega.Expect(L4Ports{esp: NewPorts(AnyPort)}.IsSubsetOf(allowed)).To(gomega.BeFalse())
+	gomega.Expect(L4Ports{tcp: NewPorts(80)}.IsSubsetOf(allowed)).To(gomega.BeFalse())
+
+	intersection := L4Ports{tcp: NewPorts(80), sctp: NewPorts(3868), esp: NewPorts(AnyPort)}.Intersection(allowed)
+	gomega.Expect(intersection).To(gomega.Equal(L4Ports{sctp: NewPorts(3868)}))
+}
+
+func TestAllowedPortRanges(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	const podIP = "10.1.1.1"
+	rangeRule := allowPodIngress(podIP, 8000, renderer.TCP)
+	rangeRule.DestPortEnd = 8080
+	ingress := []*renderer.ContivRule{
+		rangeRule,
+		allowPodIngress(podIP, 9000, renderer.TCP),
+		blockPodIngress(podIP),
+	}
+
+	ports, any := getAllowedIngressPorts(GetOneHostSubnet(podIP), ingress)
+	gomega.Expect(any).To(gomega.BeFalse())
+	expected := NewPorts(9000)
+	expected.AddRange(8000, 8080)
+	gomega.Expect(ports).To(gomega.Equal(L4Ports{tcp: expected}))
+	gomega.Expect(ports[tcp].Has(8042)).To(gomega.BeTrue())
+	gomega.Expect(ports[tcp].Has(8081)).To(gomega.BeFalse())
+
+	// subset of a range or of a union of adjacent ranges
+	gomega.Expect(NewPorts(8000, 8080).IsSubsetOf(expected)).To(gomega.BeTrue())
+	split := NewPorts()
+	split.AddRange(1000, 1999)
+	split.AddRange(2000, 2999)
+	wide := NewPorts()
+	wide.AddRange(1500, 2500)
+	gomega.Expect(wide.IsSubsetOf(split)).To(gomega.BeTrue())
+	gomega.Expect(split.IsSubsetOf(wide)).To(gomega.BeFalse())
+	gomega.Expect(wide.IsSubsetOf(NewPorts(AnyPort))).To(gomega.BeTrue())
+	gomega.Expect(NewPorts(AnyPort).IsSubsetOf(split)).To(gomega.BeFalse())
+
+	// intersection of overlapping ranges
+	intersection := expected.Intersection(wide)
+	gomega.Expect(intersection).To(gomega.BeEmpty())
+	wide.AddRange(8050, 9000)
+	intersection = expected.Intersection(wide)
+	overlap := NewPorts(9000)
+	overlap.AddRange(8050, 8080)
+	gomega.Expect(intersection).To(gomega.Equal(overlap))
+}
+
+func TestAllowedPortsWithPrecedence(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	const podIP = "10.1.1.1"
+	udp := L4Protocol{Protocol: renderer.UDP}
+	denyIngress := func(subnet string, port uint16, protocol renderer.ProtocolType, precedence uint32) *renderer.ContivRule {
+		_, network, _ := net.ParseCIDR(subnet)
+		return &renderer.ContivRule{
+			Action:      renderer.ActionDeny,
+			Precedence:  precedence,
+			SrcNetwork:  &net.IPNet{},
+			DestNetwork: network,
+			DestPort:    port,
+			Protocol:    protocol,
+		}
+	}
+
+	// cluster deny of a port range without namespace policies
+	ingress := []*renderer.ContivRule{
+		denyIngress("10.1.0.0/16", 8000, renderer.TCP, 1),
+	}
+	ingress[0].DestPortEnd = 8080
+	ports, others := getAllowedIngressPorts(GetOneHostSubnet(podIP), ingress)
+	gomega.Expect(others).To(gomega.BeTrue())
+	expected := NewPorts()
+	expected.AddRange(1, 7999)
+	expected.AddRange(8081, 65535)
+	gomega.Expect(ports).To(gomega.Equal(L4Ports{tcp: expected}))
+	gomega.Expect(allAllowed(ports, others)).To(gomega.BeFalse())
+
+	// pod outside of the denied network
+	ports, others = getAllowedIngressPorts(GetOneHostSubnet("10.2.1.1"), ingress)
+	gomega.Expect(allAllowed(ports, others)).To(gomega.BeTrue())
+
+	// cluster allow of DNS takes precedence over cluster deny-all,
+	// which in turn takes precedence over namespace policies
+	allowDNS := allowPodIngress(podIP, 53, renderer.UDP)
+	allowDNS.Precedence = 2
+	ingress = []*renderer.ContivRule{
+		allowDNS,
+		denyIngress("10.1.1.0/24", AnyPort, renderer.ANY, 1),
+		allowPodIngress(podIP, 80, renderer.TCP),
+		blockPodIngress(podIP),
+	}
+	ports, others = getAllowedIngressPorts(GetOneHostSubnet(podIP), ingress)
+	gomega.Expect(others).To(gomega.BeFalse())
+	gomega.Expect(ports).To(gomega.Equal(L4Ports{udp: NewPorts(53)}))
+
+	// subset & intersection with other protocols allowed
+	gomega.Expect(isAllowedSubset(ports, others, L4Ports{tcp: expected}, true)).To(gomega.BeTrue())
+	gomega.Expect(isAllowedSubset(L4Ports{tcp: expected}, true, ports, others)).To(gomega.BeFalse())
+	intersection, intersectionOthers := intersectAllowed(L4Ports{tcp: NewPorts(8080, 9000)}, true,
+		L4Ports{tcp: expected}, true)
+	gomega.Expect(intersectionOthers).To(gomega.BeTrue())
+	gomega.Expect(intersection).To(gomega.Equal(L4Ports{tcp: NewPorts(9000)}))
+}
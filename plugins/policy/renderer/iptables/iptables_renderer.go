@@ -31,6 +31,7 @@ import (
 	linux_namespace "go.ligato.io/vpp-agent/v3/proto/ligato/linux/namespace"
 
 	"net"
+	"strconv"
 	"strings"
 )
 
@@ -254,7 +255,7 @@ func (rt *RendererTxn) renderRuleChains(podID podmodel.ID, podIP *net.IPNet, ing
 func (rt *RendererTxn) contivRuleToIPtables(rule *renderer.ContivRule) string {
 	var parts []string
 	// protocol must listed before --dport argument
-	parts = append(parts, fmt.Sprintf("-p %v", rt.protocolToStr(rule.Protocol)))
+	parts = append(parts, fmt.Sprintf("-p %v", rt.protocolToStr(rule.Protocol, rule.ProtocolNumber)))
 
 	if rule.SrcNetwork != nil && len(rule.SrcNetwork.IP) > 0 {
 		parts = append(parts, fmt.Sprintf("-s %v", rule.SrcNetwork.String()))
@@ -265,8 +266,10 @@ func (rt *RendererTxn) contivRuleToIPtables(rule *renderer.ContivRule) string {
 	if rule.DestNetwork != nil && len(rule.DestNetwork.IP) > 0 {
 		parts = append(parts, fmt.Sprintf("-d %v", rule.DestNetwork.String()))
 	}
-	if rule.DestPort != 0 {
-		parts = append(parts, fmt.Sprintf("--dport %v", rule.DestPort))
+	if lower, upper := rule.DestPortRange(); lower != upper {
+		parts = append(parts, fmt.Sprintf("--dport %v:%v", lower, upper))
+	} else if lower != 0 {
+		parts = append(parts, fmt.Sprintf("--dport %v", lower))
 	}
 	parts = append(parts, fmt.Sprintf("-j %v", rt.actionToStr(rule.Action)))
 	return strings.Join(parts, " ")
@@ -284,12 +287,16 @@ func (rt *RendererTxn) chainName(pod podmodel.ID, podIP *net.IPNet, isIngress bo
 	return name
 }
 
-func (rt *RendererTxn) protocolToStr(p renderer.ProtocolType) string {
-	if p == renderer.TCP {
+func (rt *RendererTxn) protocolToStr(p renderer.ProtocolType, protocolNumber uint8) string {
+	switch p {
+	case renderer.TCP:
 		return "tcp"
-	}
-	if p == renderer.UDP {
+	case renderer.UDP:
 		return "udp"
+	case renderer.SCTP:
+		return "sctp"
+	case renderer.OTHER:
+		return strconv.Itoa(int(protocolNumber))
 	}
 	return "all"
 }
@@ -13,3 +13,55 @@
 // limitations under the License.
 
 package iptables
+
+import (
+	"net"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+)
+
+func TestContivRuleToIPtables(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	rt := &RendererTxn{}
+
+	_, podNet, _ := net.ParseCIDR("10.1.1.1/32")
+
+	sctpRule := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  podNet,
+		DestNetwork: &net.IPNet{},
+		Protocol:    renderer.SCTP,
+		DestPort:    3868,
+	}
+	gomega.Expect(rt.contivRuleToIPtables(sctpRule)).To(gomega.Equal("-p sctp -s 10.1.1.1/32 --dport 3868 -j ACCEPT"))
+
+	rangeRule := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  &net.IPNet{},
+		DestNetwork: podNet,
+		Protocol:    renderer.TCP,
+		DestPort:    30000,
+		DestPortEnd: 32767,
+	}
+	gomega.Expect(rt.contivRuleToIPtables(rangeRule)).To(gomega.Equal("-p tcp -d 10.1.1.1/32 --dport 30000:32767 -j ACCEPT"))
+
+	greRule := &renderer.ContivRule{
+		Action:         renderer.ActionDeny,
+		SrcNetwork:     &net.IPNet{},
+		DestNetwork:    podNet,
+		Protocol:       renderer.OTHER,
+		ProtocolNumber: 47,
+	}
+	gomega.Expect(rt.contivRuleToIPtables(greRule)).To(gomega.Equal("-p 47 -d 10.1.1.1/32 -j DROP"))
+
+	anyRule := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  &net.IPNet{},
+		DestNetwork: &net.IPNet{},
+		Protocol:    renderer.ANY,
+	}
+	gomega.Expect(rt.contivRuleToIPtables(anyRule)).To(gomega.Equal("-p all -j ACCEPT"))
+}
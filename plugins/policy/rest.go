@@ -0,0 +1,167 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unrolled/render"
+
+	"github.com/americanbinary/vpp/plugins/policy/configurator"
+	"github.com/americanbinary/vpp/plugins/policy/renderer"
+	"github.com/americanbinary/vpp/plugins/policy/restapi"
+)
+
+const (
+	namespaceArg = "namespace"
+	sinceArg     = "since"
+	srcArg       = "src"
+	dstArg       = "dst"
+	protocolArg  = "protocol"
+	portArg      = "port"
+)
+
+type errorString struct {
+	Error string
+}
+
+func (p *Plugin) registerRESTHandlers() {
+	if p.HTTPHandlers == nil {
+		p.Log.Warnf("No http handler provided, skipping registration of policy REST handlers")
+		return
+	}
+
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLPolicyExplain, p.explainGetHandler, "GET")
+	p.Log.Infof("Policy explain REST handler registered: GET %v", restapi.RestURLPolicyExplain)
+
+	if p.auditor == nil {
+		// policy audit is supported only with the ACL renderer
+		return
+	}
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLPolicyAudit, p.auditGetHandler, "GET")
+	p.Log.Infof("Policy audit REST handler registered: GET %v", restapi.RestURLPolicyAudit)
+}
+
+// auditGetHandler is the GET handler for "policy/audit" API.
+func (p *Plugin) auditGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		args := req.URL.Query()
+
+		var since time.Time
+		if sinceParam := args.Get(sinceArg); sinceParam != "" {
+			sec, err := strconv.ParseInt(sinceParam, 10, 64)
+			if err != nil {
+				formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+				return
+			}
+			since = time.Unix(sec, 0)
+		}
+
+		p.Log.Debug("Getting policy audit events")
+		formatter.JSON(w, http.StatusOK, p.auditor.GetEvents(args.Get(namespaceArg), since))
+	}
+}
+
+// explainGetHandler is the GET handler for "policy/explain" API.
+func (p *Plugin) explainGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		traffic, err := parseExplainArgs(req)
+		if err != nil {
+			formatter.JSON(w, http.StatusBadRequest, errorString{err.Error()})
+			return
+		}
+
+		p.Log.Debugf("Explaining policies for traffic: %+v", traffic)
+		egress, ingress := p.configurator.Explain(traffic)
+		formatter.JSON(w, http.StatusOK, restapi.PolicyExplanation{
+			Egress:  policyVerdict(egress),
+			Ingress: policyVerdict(ingress),
+		})
+	}
+}
+
+// parseExplainArgs parses traffic to explain from the query parameters.
+func parseExplainArgs(req *http.Request) (traffic configurator.Traffic, err error) {
+	args := req.URL.Query()
+
+	traffic.SrcIP = net.ParseIP(args.Get(srcArg))
+	if traffic.SrcIP == nil {
+		return traffic, fmt.Errorf("invalid source IP address: '%s'", args.Get(srcArg))
+	}
+	traffic.DstIP = net.ParseIP(args.Get(dstArg))
+	if traffic.DstIP == nil {
+		return traffic, fmt.Errorf("invalid destination IP address: '%s'", args.Get(dstArg))
+	}
+
+	protocol := strings.ToLower(args.Get(protocolArg))
+	switch protocol {
+	case "tcp", "6":
+		traffic.Protocol = renderer.TCP
+	case "udp", "17":
+		traffic.Protocol = renderer.UDP
+	case "sctp", "132":
+		traffic.Protocol = renderer.SCTP
+	default:
+		number, err := strconv.ParseUint(protocol, 10, 8)
+		if err != nil {
+			return traffic, fmt.Errorf("invalid protocol: '%s'", args.Get(protocolArg))
+		}
+		traffic.Protocol = renderer.OTHER
+		traffic.ProtocolNumber = uint8(number)
+		return traffic, nil
+	}
+
+	port, err := strconv.ParseUint(args.Get(portArg), 10, 16)
+	if err != nil || port == 0 {
+		return traffic, fmt.Errorf("invalid destination port: '%s'", args.Get(portArg))
+	}
+	traffic.DestPort = uint16(port)
+	return traffic, nil
+}
+
+// policyVerdict converts explanation from the configurator into the REST API
+// representation.
+func policyVerdict(explanation *configurator.Explanation) *restapi.PolicyVerdict {
+	if explanation == nil {
+		return nil
+	}
+	verdict := &restapi.PolicyVerdict{
+		Pod:      explanation.Pod,
+		Policies: []string{},
+		Rules:    []restapi.ExplainedRule{},
+		Allowed:  explanation.Allowed,
+	}
+	for _, policy := range explanation.Policies {
+		name := policy.ID.String()
+		if policy.Cluster {
+			name = policy.ID.Name
+		}
+		verdict.Policies = append(verdict.Policies, name)
+	}
+	for _, rule := range explanation.Rules {
+		explained := restapi.ExplainedRule{
+			Rule:    rule.String(),
+			Action:  rule.Action.String(),
+			Origins: rule.OriginStrings(),
+		}
+		verdict.Rules = append(verdict.Rules, explained)
+	}
+	return verdict
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restapi
+
+import (
+	"time"
+
+	"github.com/americanbinary/vpp/plugins/ksr/model/pod"
+)
+
+const (
+	// RESTPrefix is versioned prefix for REST urls.
+	RESTPrefix = "/contiv/v1/"
+
+	// RestURLPolicyAudit is versioned URL for the policy audit REST endpoint.
+	// Events can be filtered using the query parameters "namespace" and "since"
+	// (Unix timestamp in seconds).
+	RestURLPolicyAudit = RESTPrefix + "policy/audit"
+
+	// RestURLPolicyExplain is versioned URL for the policy explain REST endpoint.
+	// Traffic to explain is given by the query parameters "src" and "dst" (IP addresses),
+	// "protocol" (tcp, udp, sctp or protocol number) and "port" (destination port).
+	RestURLPolicyExplain = RESTPrefix + "policy/explain"
+)
+
+// AuditEvent represents hits of a single rendered policy rule observed during
+// one audit period. Hits are aggregated per rule, the event does not describe
+// individual connections.
+type AuditEvent struct {
+	Time            time.Time
+	Action          string   // DENY or PERMIT
+	Origins         []string // "<policy>:<ingress|egress>[<rule-index>]" of every policy rule the rule was derived from
+	Rule            string   // rendered rule
+	ACL             string   // name of the ACL with the rule
+	RuleSource      AuditRuleEndpoint
+	RuleDestination AuditRuleEndpoint
+	Hits            uint64 // number of packets matched by the rule during the audit period
+}
+
+// AuditRuleEndpoint represents source or destination of the traffic matched by a rule.
+// The endpoint is given by the rule, not by the matched connections - a network
+// wider than a single host does not tell which peer the traffic was from/to.
+type AuditRuleEndpoint struct {
+	Network string   // network of the rule, empty = any
+	Pods    []pod.ID // local pods the traffic may be from/to: resolved from a single-host network or given by the ACL interfaces
+}
+
+// PolicyExplanation explains why traffic between two endpoints is allowed
+// or denied by the policies of the source and the destination pod.
+type PolicyExplanation struct {
+	Egress  *PolicyVerdict // policies of the source pod, nil if not a pod deployed on this node
+	Ingress *PolicyVerdict // policies of the destination pod, nil if not a pod deployed on this node
+}
+
+// PolicyVerdict describes evaluation of the policies of one pod.
+type PolicyVerdict struct {
+	Pod      pod.ID
+	Policies []string        // policies of the matching rules (all applied if isolated) in the order of evaluation
+	Rules    []ExplainedRule // rules matching the traffic in the order of evaluation
+	Allowed  bool            // decided by the first matching rule
+}
+
+// ExplainedRule represents a rendered rule matching the explained traffic.
+type ExplainedRule struct {
+	Rule    string   // rendered rule
+	Action  string   // DENY or PERMIT
+	Origins []string // "<policy>:<ingress|egress>[<rule-index>]" of every policy rule the rule was derived from
+}
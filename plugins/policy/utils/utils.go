@@ -256,6 +256,28 @@ func ComparePorts(a, b uint16) int {
 	return 1
 }
 
+// ComparePortRanges is a comparison function for two port ranges (both bounds
+// inclusive). Range (0, 0) means "all-ports" and it is higher in the order than
+// any specific range. Narrower ranges precede wider ones, hence if range <a>
+// is a subset of range <b>, then a<=b. Ranges of the same width are ordered by
+// the lower bound (single ports are therefore ordered as by ComparePorts).
+func ComparePortRanges(aLower, aUpper, bLower, bUpper uint16) int {
+	if aLower == bLower && aUpper == bUpper {
+		return 0
+	}
+	if aLower == 0 {
+		return 1
+	}
+	if bLower == 0 {
+		return -1
+	}
+	widthOrder := CompareInts(int(aUpper-aLower), int(bUpper-bLower))
+	if widthOrder != 0 {
+		return widthOrder
+	}
+	return CompareInts(int(aLower), int(bLower))
+}
+
 // CompareIPNetsBytes returns an integer comparing two IP network addresses
 // represented as raw bytes lexicographically.
 func CompareIPNetsBytes(aPrefixLen uint8, aIP [16]byte, bPrefixLen uint8, bIP [16]byte) int {
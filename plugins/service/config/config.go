@@ -17,6 +17,13 @@ package config
 const (
 	// by default traffic is equally distributed between local and remote backends
 	defaultServiceLocalEndpointWeight = 1
+
+	// by default the topology of the cluster is not taken into account
+	defaultServiceZoneEndpointWeight   = 1
+	defaultServiceRegionEndpointWeight = 1
+
+	// by default NAT sessions of removed service backends are drained for up to one minute
+	defaultNATSessionDrainTimeout = 60
 )
 
 // Config holds the Service configuration.
@@ -33,6 +40,16 @@ type Config struct {
 	// how much locally deployed endpoints are more likely to receive a connection
 	ServiceLocalEndpointWeight uint8 `json:"serviceLocalEndpointWeight"`
 
+	// how much endpoints deployed in the same zone as this node are more likely to receive a connection
+	ServiceZoneEndpointWeight uint8 `json:"serviceZoneEndpointWeight"`
+
+	// how much endpoints deployed in the same region as this node are more likely to receive a connection
+	ServiceRegionEndpointWeight uint8 `json:"serviceRegionEndpointWeight"`
+
+	// timeout (in seconds) for draining NAT sessions of backends removed from a service,
+	// 0 = removed backends are deleted from NAT static mappings immediately
+	NATSessionDrainTimeout uint32 `json:"natSessionDrainTimeout"`
+
 	// if true, NAT plugin will drop fragmented packets
 	DisableNATVirtualReassembly bool `json:"disableNATVirtualReassembly"`
 }
@@ -40,6 +57,9 @@ type Config struct {
 // DefaultConfig returns configuration for service plugin with default values.
 func DefaultConfig() *Config {
 	return &Config{
-		ServiceLocalEndpointWeight: defaultServiceLocalEndpointWeight,
+		ServiceLocalEndpointWeight:  defaultServiceLocalEndpointWeight,
+		ServiceZoneEndpointWeight:   defaultServiceZoneEndpointWeight,
+		ServiceRegionEndpointWeight: defaultServiceRegionEndpointWeight,
+		NATSessionDrainTimeout:      defaultNATSessionDrainTimeout,
 	}
 }
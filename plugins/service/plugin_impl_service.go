@@ -16,12 +16,14 @@ package service
 
 import (
 	"strings"
+	"time"
 
 	"git.fd.io/govpp.git/api"
 	"github.com/americanbinary/vpp/plugins/ipam/ipalloc"
 
 	"github.com/americanbinary/vpp/plugins/statscollector"
 	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
 	"go.ligato.io/cn-infra/v2/servicelabel"
 
 	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
@@ -31,11 +33,14 @@ import (
 	"github.com/americanbinary/vpp/plugins/ipam"
 	"github.com/americanbinary/vpp/plugins/ipnet"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
+	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
 	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 	"github.com/americanbinary/vpp/plugins/nodesync"
 	"github.com/americanbinary/vpp/plugins/podmanager"
 	"github.com/americanbinary/vpp/plugins/service/config"
 	"github.com/americanbinary/vpp/plugins/service/processor"
+	"github.com/americanbinary/vpp/plugins/service/renderer/healthcheck"
 	"github.com/americanbinary/vpp/plugins/service/renderer/ipv6route"
 	"github.com/americanbinary/vpp/plugins/service/renderer/nat44"
 	"github.com/americanbinary/vpp/plugins/service/renderer/srv6"
@@ -55,10 +60,11 @@ type Plugin struct {
 	changes   []string
 
 	// layers of the service plugin
-	processor         *processor.ServiceProcessor
-	nat44Renderer     *nat44.Renderer
-	ipv6RouteRenderer *ipv6route.Renderer
-	srv6Renderer      *srv6.Renderer
+	processor           *processor.ServiceProcessor
+	nat44Renderer       *nat44.Renderer
+	ipv6RouteRenderer   *ipv6route.Renderer
+	srv6Renderer        *srv6.Renderer
+	healthCheckRenderer *healthcheck.Renderer
 }
 
 // Deps defines dependencies of the service plugin.
@@ -73,6 +79,8 @@ type Deps struct {
 	GoVPP           govppmux.API       /* used for direct NAT binary API calls */
 	Stats           statscollector.API /* used for exporting the statistics */
 	ConfigRetriever controller.ConfigRetriever
+	EventLoop       controller.EventLoop /* used by NAT44 renderer to trigger removal of drained backends */
+	HTTPHandlers    rest.HTTPHandlers    /* used to expose backends being drained */
 }
 
 func (p *Plugin) useNat44Renderer(goVppCh api.Channel) {
@@ -91,7 +99,8 @@ func (p *Plugin) useNat44Renderer(goVppCh api.Channel) {
 			ResyncTxnFactory: func() controller.ResyncOperations {
 				return p.resyncTxn
 			},
-			Stats: p.Stats,
+			Stats:     p.Stats,
+			EventLoop: p.EventLoop,
 		},
 	}
 
@@ -151,6 +160,18 @@ func (p *Plugin) useIPv6RouteRenderer() {
 	p.processor.RegisterRenderer(p.ipv6RouteRenderer)
 }
 
+func (p *Plugin) useHealthCheckRenderer() {
+	p.healthCheckRenderer = &healthcheck.Renderer{
+		Deps: healthcheck.Deps{
+			Log: p.Log.NewLogger("-healthCheckRenderer"),
+		},
+	}
+
+	p.healthCheckRenderer.Init()
+	// Register renderer.
+	p.processor.RegisterRenderer(p.healthCheckRenderer)
+}
+
 // Init initializes the service plugin and starts watching ETCD for K8s configuration.
 func (p *Plugin) Init() error {
 	var err error
@@ -171,6 +192,7 @@ func (p *Plugin) Init() error {
 	p.processor = &processor.ServiceProcessor{
 		Deps: processor.Deps{
 			Log:          p.Log.NewLogger("-serviceProcessor"),
+			Config:       p.config,
 			ServiceLabel: p.ServiceLabel,
 			ContivConf:   p.ContivConf,
 			IPAM:         p.IPAM,
@@ -181,7 +203,11 @@ func (p *Plugin) Init() error {
 	}
 	p.processor.Init()
 
-	if !p.ContivConf.GetIPAMConfig().UseIPv6 {
+	if p.ContivConf.GetIPAMConfig().DualStack && !p.ContivConf.GetRoutingConfig().UseSRv6ForServices {
+		// dual-stack: IPv4 services are rendered using NAT44, IPv6 services using static routes
+		p.useNat44Renderer(goVppCh)
+		p.useIPv6RouteRenderer()
+	} else if !p.ContivConf.GetIPAMConfig().UseIPv6 {
 		if p.ContivConf.GetRoutingConfig().UseSRv6ForServices {
 			// use SRv6 renderer
 			p.useSRv6Renderer()
@@ -197,24 +223,30 @@ func (p *Plugin) Init() error {
 		}
 	}
 
+	// health-check node ports are served regardless of the renderer used for services
+	p.useHealthCheckRenderer()
+
 	return nil
 }
 
 // AfterInit can be used by renderers to perform a second stage of initialization.
+// REST handlers are also registered here.
 func (p *Plugin) AfterInit() error {
 	p.processor.AfterInit()
 
 	if p.nat44Renderer != nil {
 		p.nat44Renderer.AfterInit()
 	}
+	p.registerRESTHandlers()
 	return nil
 }
 
 // HandlesEvent selects:
 //   - any resync event
-//   - KubeStateChange for service-related data
+//   - KubeStateChange for service-related data and nodes (topology)
 //   - AddPod & DeletePod
 //   - NodeUpdate event
+//   - BackendsDrained event (NAT44 renderer only)
 func (p *Plugin) HandlesEvent(event controller.Event) bool {
 	if event.Method() != controller.Update {
 		return true
@@ -223,10 +255,14 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 		switch ksChange.Resource {
 		case epmodel.EndpointsKeyword:
 			return true
+		case epslicemodel.EndpointSliceKeyword:
+			return true
 		case svcmodel.ServiceKeyword:
 			return true
 		case ipalloc.Keyword:
 			return true
+		case nodemodel.NodeKeyword:
+			return true
 		default:
 			// unhandled Kubernetes state change
 			return false
@@ -241,6 +277,9 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 	if _, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
 		return true
 	}
+	if _, isDrained := event.(*nat44.BackendsDrained); isDrained {
+		return p.nat44Renderer != nil
+	}
 
 	// unhandled event
 	return false
@@ -262,11 +301,16 @@ func (p *Plugin) Resync(event controller.Event, kubeStateData controller.KubeSta
 //   - KubeStateChange for service-related data
 //   - AddPod & DeletePod
 //   - NodeUpdate event
+//   - BackendsDrained event
 func (p *Plugin) Update(event controller.Event, txn controller.UpdateOperations) (changeDescription string, err error) {
 	p.resyncTxn = nil
 	p.updateTxn = txn
 	p.changes = []string{}
-	err = p.processor.Update(event)
+	if _, isDrained := event.(*nat44.BackendsDrained); isDrained {
+		err = p.nat44Renderer.RemoveDrainedBackends(time.Now())
+	} else {
+		err = p.processor.Update(event)
+	}
 	changeDescription = strings.Join(p.changes, ", ")
 	return changeDescription, err
 }
@@ -276,7 +320,22 @@ func (p *Plugin) Revert(event controller.Event) error {
 	return p.processor.Revert(event)
 }
 
-// Close is NOOP.
+// GetIngressACLInterfaces returns interfaces with the ingress ACL installed
+// by the service plugin (enforcing source ranges of LoadBalancer services).
+func (p *Plugin) GetIngressACLInterfaces() []string {
+	if p.nat44Renderer == nil {
+		return nil
+	}
+	return p.nat44Renderer.GetIngressACLInterfaces()
+}
+
+// Close stops health-check servers and checks of draining backends.
 func (p *Plugin) Close() error {
+	if p.nat44Renderer != nil {
+		p.nat44Renderer.Close()
+	}
+	if p.healthCheckRenderer != nil {
+		return p.healthCheckRenderer.Close()
+	}
 	return nil
 }
@@ -20,6 +20,8 @@ import (
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	"github.com/americanbinary/vpp/plugins/ipam/ipalloc"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
+	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
 	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 )
 
@@ -37,6 +39,17 @@ func (sp *ServiceProcessor) propagateDataChangeEv(event *controller.KubeStateCha
 		endpoints := event.PrevValue.(*epmodel.Endpoints)
 		return sp.processDeletedEndpoints(epmodel.GetID(endpoints))
 
+	case epslicemodel.EndpointSliceKeyword:
+		if event.NewValue != nil {
+			slice := event.NewValue.(*epslicemodel.EndpointSlice)
+			if event.PrevValue == nil {
+				return sp.processNewEndpointSlice(slice)
+			}
+			return sp.processUpdatedEndpointSlice(slice)
+		}
+		slice := event.PrevValue.(*epslicemodel.EndpointSlice)
+		return sp.processDeletedEndpointSlice(epslicemodel.GetID(slice))
+
 	case svcmodel.ServiceKeyword:
 		if event.NewValue != nil {
 			service := event.NewValue.(*svcmodel.Service)
@@ -53,6 +66,13 @@ func (sp *ServiceProcessor) propagateDataChangeEv(event *controller.KubeStateCha
 			return sp.processCustomIPAlloc(alloc)
 		}
 		// no-op for delete, handled in ProcessDeletingPod
+	case nodemodel.NodeKeyword:
+		if event.NewValue != nil {
+			node := event.NewValue.(*nodemodel.Node)
+			return sp.processNodeChange(node.Name, node)
+		}
+		node := event.PrevValue.(*nodemodel.Node)
+		return sp.processNodeChange(node.Name, nil)
 	}
 	return nil
 }
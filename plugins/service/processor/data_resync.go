@@ -22,6 +22,8 @@ import (
 
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
+	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 )
@@ -29,18 +31,22 @@ import (
 // ResyncEventData wraps an entire state of K8s services that should be reflected
 // into VPP.
 type ResyncEventData struct {
-	Pods          []podmodel.ID
-	Endpoints     []*epmodel.Endpoints
-	Services      []*svcmodel.Service
-	IPAllocations []*ipalloc.CustomIPAllocation
+	Pods           []podmodel.ID
+	Endpoints      []*epmodel.Endpoints
+	EndpointSlices []*epslicemodel.EndpointSlice
+	Services       []*svcmodel.Service
+	IPAllocations  []*ipalloc.CustomIPAllocation
+	Nodes          []*nodemodel.Node
 }
 
 // NewResyncEventData creates an empty instance of ResyncEventData.
 func NewResyncEventData() *ResyncEventData {
 	return &ResyncEventData{
-		Pods:      []podmodel.ID{},
-		Endpoints: []*epmodel.Endpoints{},
-		Services:  []*svcmodel.Service{},
+		Pods:           []podmodel.ID{},
+		Endpoints:      []*epmodel.Endpoints{},
+		EndpointSlices: []*epslicemodel.EndpointSlice{},
+		Services:       []*svcmodel.Service{},
+		Nodes:          []*nodemodel.Node{},
 	}
 }
 
@@ -85,6 +91,12 @@ func (sp *ServiceProcessor) parseResyncEv(kubeStateData controller.KubeStateData
 		event.Endpoints = append(event.Endpoints, endpoints)
 	}
 
+	// collect endpoint slices
+	for _, sliceProto := range kubeStateData[epslicemodel.EndpointSliceKeyword] {
+		slice := sliceProto.(*epslicemodel.EndpointSlice)
+		event.EndpointSlices = append(event.EndpointSlices, slice)
+	}
+
 	// collect services
 	for _, svcProto := range kubeStateData[svcmodel.ServiceKeyword] {
 		service := svcProto.(*svcmodel.Service)
@@ -97,5 +109,11 @@ func (sp *ServiceProcessor) parseResyncEv(kubeStateData controller.KubeStateData
 		event.IPAllocations = append(event.IPAllocations, alloc)
 	}
 
+	// collect nodes
+	for _, nodeProto := range kubeStateData[nodemodel.NodeKeyword] {
+		node := nodeProto.(*nodemodel.Node)
+		event.Nodes = append(event.Nodes, node)
+	}
+
 	return event
 }
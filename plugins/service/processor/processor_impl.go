@@ -29,10 +29,12 @@ import (
 	"github.com/americanbinary/vpp/plugins/ipam"
 	"github.com/americanbinary/vpp/plugins/ipnet"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 	"github.com/americanbinary/vpp/plugins/nodesync"
 	"github.com/americanbinary/vpp/plugins/podmanager"
+	"github.com/americanbinary/vpp/plugins/service/config"
 	"github.com/americanbinary/vpp/plugins/service/renderer"
 )
 
@@ -47,9 +49,11 @@ type ServiceProcessor struct {
 	renderers []renderer.ServiceRendererAPI
 
 	/* internal maps */
-	services    map[svcmodel.ID]*Service
-	localEps    map[podmodel.ID]*LocalEndpoint
-	epRedirects map[string]string
+	services     map[svcmodel.ID]*Service
+	localEps     map[podmodel.ID]*LocalEndpoint
+	epRedirects  map[string]string
+	epSlices     map[epslicemodel.ID]svcmodel.ID // endpoint slice -> service
+	nodeTopology map[string]nodeTopology         // node name -> zone & region
 
 	/* local frontend and backend interfaces */
 	frontendIfs renderer.Interfaces
@@ -59,6 +63,7 @@ type ServiceProcessor struct {
 // Deps lists dependencies of ServiceProcessor.
 type Deps struct {
 	Log          logging.Logger
+	Config       *config.Config /* optional, default configuration is used if not set */
 	ServiceLabel servicelabel.ReaderAPI
 	ContivConf   contivconf.API
 	NodeSync     nodesync.API
@@ -76,6 +81,9 @@ type LocalEndpoint struct {
 
 // Init initializes service processor.
 func (sp *ServiceProcessor) Init() error {
+	if sp.Config == nil {
+		sp.Config = config.DefaultConfig()
+	}
 	sp.reset()
 	return nil
 }
@@ -90,13 +98,15 @@ func (sp *ServiceProcessor) reset() error {
 	sp.services = make(map[svcmodel.ID]*Service)
 	sp.localEps = make(map[podmodel.ID]*LocalEndpoint)
 	sp.epRedirects = make(map[string]string)
+	sp.epSlices = make(map[epslicemodel.ID]svcmodel.ID)
+	sp.nodeTopology = make(map[string]nodeTopology)
 	sp.frontendIfs = renderer.NewInterfaces()
 	sp.backendIfs = renderer.NewInterfaces()
 	return nil
 }
 
 // Update is called for:
-//   - KubeStateChange for service-related data
+//   - KubeStateChange for service-related data and nodes (topology)
 //   - AddPod & DeletePod
 //   - NodeUpdate event
 func (sp *ServiceProcessor) Update(event controller.Event) error {
@@ -252,6 +262,56 @@ func (sp *ServiceProcessor) processDeletedEndpoints(epsID epmodel.ID) error {
 	return sp.renderService(svc, oldContivSvc, oldBackends)
 }
 
+func (sp *ServiceProcessor) processNewEndpointSlice(slice *epslicemodel.EndpointSlice) error {
+	sp.Log.WithFields(logging.Fields{
+		"slice": *slice,
+	}).Debug("ServiceProcessor - processNewEndpointSlice()")
+
+	if slice.ServiceName == "" {
+		// slice does not belong to any service
+		return nil
+	}
+	svcID := svcmodel.ID{Namespace: slice.Namespace, Name: slice.ServiceName}
+	sp.epSlices[epslicemodel.GetID(slice)] = svcID
+	svc := sp.getService(svcID)
+	oldContivSvc := svc.GetContivService()
+	oldBackends := svc.GetLocalBackends()
+	svc.SetEndpointSlice(slice)
+	return sp.renderService(svc, oldContivSvc, oldBackends)
+}
+
+func (sp *ServiceProcessor) processUpdatedEndpointSlice(slice *epslicemodel.EndpointSlice) error {
+	sp.Log.WithFields(logging.Fields{
+		"slice": *slice,
+	}).Debug("ServiceProcessor - processUpdatedEndpointSlice()")
+
+	sliceID := epslicemodel.GetID(slice)
+	if svcID, hasSvc := sp.epSlices[sliceID]; hasSvc && svcID.Name != slice.ServiceName {
+		// slice moved to another service
+		if err := sp.processDeletedEndpointSlice(sliceID); err != nil {
+			return err
+		}
+	}
+	return sp.processNewEndpointSlice(slice)
+}
+
+func (sp *ServiceProcessor) processDeletedEndpointSlice(sliceID epslicemodel.ID) error {
+	sp.Log.WithFields(logging.Fields{
+		"sliceID": sliceID,
+	}).Debug("ServiceProcessor - processDeletedEndpointSlice()")
+
+	svcID, hasSvc := sp.epSlices[sliceID]
+	if !hasSvc {
+		return nil
+	}
+	delete(sp.epSlices, sliceID)
+	svc := sp.getService(svcID)
+	oldContivSvc := svc.GetContivService()
+	oldBackends := svc.GetLocalBackends()
+	svc.DeleteEndpointSlice(sliceID.Name)
+	return sp.renderService(svc, oldContivSvc, oldBackends)
+}
+
 func (sp *ServiceProcessor) processCustomIPAlloc(alloc *ipalloc.CustomIPAllocation) error {
 
 	sp.Log.WithFields(logging.Fields{
@@ -605,12 +665,26 @@ func (sp *ServiceProcessor) processResyncEvent(resyncEv *ResyncEventData) error
 		sp.frontendIfs.Add(ifName)
 	}
 
+	// Learn the cluster topology (needed to compute weights of service backends).
+	for _, node := range resyncEv.Nodes {
+		sp.nodeTopology[node.Name] = getNodeTopology(node)
+	}
+
 	// Combine the service metadata with endpoints.
 	for _, eps := range resyncEv.Endpoints {
 		svcID := svcmodel.ID{Namespace: eps.Namespace, Name: eps.Name}
 		svc := sp.getService(svcID)
 		svc.SetEndpoints(eps)
 	}
+	for _, slice := range resyncEv.EndpointSlices {
+		if slice.ServiceName == "" {
+			continue
+		}
+		svcID := svcmodel.ID{Namespace: slice.Namespace, Name: slice.ServiceName}
+		sp.epSlices[epslicemodel.GetID(slice)] = svcID
+		svc := sp.getService(svcID)
+		svc.SetEndpointSlice(slice)
+	}
 	for _, service := range resyncEv.Services {
 		svcID := svcmodel.ID{Namespace: service.Namespace, Name: service.Name}
 		svc := sp.getService(svcID)
@@ -677,12 +751,8 @@ func (sp *ServiceProcessor) getServicesForBackend(pod *podmanager.Pod) []*Servic
 	services := make([]*Service, 0)
 
 	for _, service := range sp.services {
-		for _, ep := range service.endpoints.EndpointSubsets {
-			for _, addr := range ep.Addresses {
-				if addr.Ip == pod.IPAddress {
-					services = append(services, service)
-				}
-			}
+		if service.HasEndpointIP(pod.IPAddress) {
+			services = append(services, service)
 		}
 	}
 
@@ -18,31 +18,39 @@ package processor
 
 import (
 	"net"
+	"sort"
+	"strconv"
+	"strings"
 
 	"go.ligato.io/cn-infra/v2/logging"
 
 	"github.com/americanbinary/vpp/plugins/ipnet"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
 	podmodel "github.com/americanbinary/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 	"github.com/americanbinary/vpp/plugins/service/renderer"
 )
 
 // Service is used to combine data from the service model with the endpoints.
+// Endpoint slices take precedence over the endpoints if there are any reflected
+// for the service.
 type Service struct {
-	sp            *ServiceProcessor
-	meta          *svcmodel.Service
-	endpoints     *epmodel.Endpoints
-	contivSvc     *renderer.ContivService
-	localBackends []podmodel.ID
-	refreshed     bool
+	sp             *ServiceProcessor
+	meta           *svcmodel.Service
+	endpoints      *epmodel.Endpoints
+	endpointSlices map[string]*epslicemodel.EndpointSlice // slice name -> slice
+	contivSvc      *renderer.ContivService
+	localBackends  []podmodel.ID
+	refreshed      bool
 }
 
 // NewService is a constructor for Service.
 func NewService(sp *ServiceProcessor) *Service {
 	return &Service{
-		sp:            sp,
-		localBackends: []podmodel.ID{},
+		sp:             sp,
+		endpointSlices: make(map[string]*epslicemodel.EndpointSlice),
+		localBackends:  []podmodel.ID{},
 	}
 }
 
@@ -58,6 +66,43 @@ func (s *Service) SetEndpoints(endpoints *epmodel.Endpoints) {
 	s.refreshed = false
 }
 
+// SetEndpointSlice adds or changes one of the endpoint slices of the service.
+func (s *Service) SetEndpointSlice(slice *epslicemodel.EndpointSlice) {
+	s.endpointSlices[slice.Name] = slice
+	s.refreshed = false
+}
+
+// DeleteEndpointSlice removes endpoint slice from the service.
+func (s *Service) DeleteEndpointSlice(sliceName string) {
+	delete(s.endpointSlices, sliceName)
+	s.refreshed = false
+}
+
+// HasEndpointIP returns true if the given IP address is one of the service
+// endpoints (ready or not).
+func (s *Service) HasEndpointIP(ip string) bool {
+	if len(s.endpointSlices) > 0 {
+		for _, slice := range s.endpointSlices {
+			for _, ep := range slice.Endpoints {
+				for _, addr := range ep.Addresses {
+					if addr == ip {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	for _, epSubSet := range s.endpoints.GetEndpointSubsets() {
+		for _, addr := range epSubSet.GetAddresses() {
+			if addr.GetIp() == ip {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // GetContivService returns the service data represented as ContivService.
 // Returns nil if there are not enough available data.
 func (s *Service) GetContivService() *renderer.ContivService {
@@ -79,7 +124,7 @@ func (s *Service) GetLocalBackends() []podmodel.ID {
 // Refresh combines metadata with endpoints to get ContivService representation
 // and the list of local backends.
 func (s *Service) Refresh() {
-	if s.meta == nil || s.endpoints == nil {
+	if s.meta == nil || (s.endpoints == nil && len(s.endpointSlices) == 0) {
 		s.contivSvc = nil
 		s.localBackends = []podmodel.ID{}
 		s.refreshed = true
@@ -96,19 +141,31 @@ func (s *Service) Refresh() {
 		s.contivSvc.TrafficPolicy = renderer.ClusterWide
 	}
 
+	if s.contivSvc.TrafficPolicy == renderer.NodeLocal && s.meta.ServiceType == "LoadBalancer" {
+		s.contivSvc.HealthCheckNodePort = uint16(s.meta.HealthCheckNodePort)
+	}
+
 	if s.meta.SessionAffinity == "ClientIP" {
 		s.contivSvc.SessionAffinityTimeout = s.meta.SessionAffinityTimeout
 	}
 
-	// Collect all IP addresses on which the service should be exposed.
-	if s.meta.ClusterIp != "" && s.meta.ClusterIp != "None" {
-		clusterIP := net.ParseIP(s.meta.ClusterIp)
+	// Collect all IP addresses on which the service should be exposed
+	// (cluster IP of each IP family with dual-stack).
+	clusterIPs := s.meta.ClusterIps
+	if len(clusterIPs) == 0 {
+		clusterIPs = []string{s.meta.ClusterIp}
+	}
+	for _, clusterIPStr := range clusterIPs {
+		if clusterIPStr == "" || clusterIPStr == "None" {
+			continue
+		}
+		clusterIP := net.ParseIP(clusterIPStr)
 		if clusterIP != nil {
 			s.contivSvc.ClusterIPs.Add(clusterIP)
 		} else {
 			s.sp.Log.WithFields(logging.Fields{
 				"service":   s.contivSvc.ID,
-				"clusterIP": clusterIP,
+				"clusterIP": clusterIPStr,
 			}).Warn("Failed to parse clusterIP")
 		}
 	}
@@ -130,6 +187,7 @@ func (s *Service) Refresh() {
 			lbIngressIP := net.ParseIP(lbIngressIPStr)
 			if lbIngressIP != nil {
 				s.contivSvc.ExternalIPs.Add(lbIngressIP)
+				s.contivSvc.LBIngressIPs.Add(lbIngressIP)
 			} else {
 				s.sp.Log.WithFields(logging.Fields{
 					"service":     s.contivSvc.ID,
@@ -137,6 +195,17 @@ func (s *Service) Refresh() {
 				}).Warn("Failed to parse LB Ingress IP")
 			}
 		}
+		for _, sourceRangeStr := range s.meta.LoadbalancerSourceRanges {
+			_, sourceRange, err := net.ParseCIDR(strings.TrimSpace(sourceRangeStr))
+			if err == nil {
+				s.contivSvc.SourceRanges = append(s.contivSvc.SourceRanges, sourceRange)
+			} else {
+				s.sp.Log.WithFields(logging.Fields{
+					"service":     s.contivSvc.ID,
+					"sourceRange": sourceRangeStr,
+				}).Warn("Failed to parse LB source range")
+			}
+		}
 	}
 
 	// Fill up the map of service ports.
@@ -157,56 +226,151 @@ func (s *Service) Refresh() {
 	for port := range s.contivSvc.Ports {
 		s.contivSvc.Backends[port] = []*renderer.ServiceBackend{}
 	}
+	weights := s.sp.getEndpointWeights(s.meta)
+	if len(s.endpointSlices) > 0 {
+		s.addSliceBackends(weights)
+	} else {
+		s.addEndpointsBackends(weights)
+	}
+
+	s.refreshed = true
+}
+
+// addEndpointsBackends fills up the service backends from the endpoints
+// (only ready addresses are used).
+func (s *Service) addEndpointsBackends(weights endpointWeights) {
 	for _, epSubSet := range s.endpoints.GetEndpointSubsets() {
 		epPorts := epSubSet.GetPorts()
-		epAddrs := epSubSet.GetAddresses()
-		for _, epAddr := range epAddrs {
-			var local bool
-			var hostNetwork bool
-			epIP := net.ParseIP(epAddr.GetIp())
-			if epIP == nil {
-				s.sp.Log.WithFields(logging.Fields{
-					"service":    s.contivSvc.ID,
-					"endpointIP": epAddr.GetIp(),
-				}).Warn("Failed to parse endpoint IP")
+		for _, epAddr := range epSubSet.GetAddresses() {
+			template, ok := s.newBackend(epAddr.GetIp(), epAddr.GetNodeName(), weights)
+			if !ok {
 				continue
 			}
-			if redirIP, isRedirected := s.sp.epRedirects[epAddr.GetIp()]; isRedirected {
-				epIP = net.ParseIP(redirIP)
-			}
-			if s.sp.IPAM.PodSubnetThisNode(ipnet.DefaultPodNetworkName).Contains(epIP) {
-				local = true
-			}
-			if !s.sp.IPAM.PodSubnetAllNodes(ipnet.DefaultPodNetworkName).Contains(epIP) {
-				hostNetwork = true
-				if s.isLocalNodeOrHostIP(epIP) {
-					local = true
-				}
-			}
-
 			for _, epPort := range epPorts {
 				port := epPort.GetName()
 				if _, exposedPort := s.contivSvc.Ports[port]; exposedPort {
-					sb := &renderer.ServiceBackend{}
-					sb.IP = epIP
+					sb := *template
 					sb.Port = uint16(epPort.GetPort())
-					sb.Local = local
-					sb.HostNetwork = hostNetwork
-					s.contivSvc.Backends[port] = append(s.contivSvc.Backends[port], sb)
+					s.contivSvc.Backends[port] = append(s.contivSvc.Backends[port], &sb)
 				}
 			}
-			if local {
-				// Get target pod and add it to the set of local backends.
-				targetPod := epAddr.GetTargetRef()
-				if targetPod.GetKind() == "Pod" {
-					s.localBackends = append(s.localBackends,
-						podmodel.ID{Name: targetPod.GetName(), Namespace: targetPod.GetNamespace()})
+			if template.Local {
+				s.addLocalBackend(epAddr.GetTargetRef().GetKind(),
+					epAddr.GetTargetRef().GetName(), epAddr.GetTargetRef().GetNamespace())
+			}
+		}
+	}
+}
+
+// addSliceBackends fills up the service backends from the endpoint slices.
+// Ready endpoints are used as normal backends. Endpoints which are terminating
+// but still serving are used as normal backends only if there are no ready
+// endpoints for the given port, otherwise they are added as terminating
+// (drained) backends, which only keep existing connections. Endpoints that
+// are neither ready nor terminating are not used at all.
+func (s *Service) addSliceBackends(weights endpointWeights) {
+	terminating := make(map[string][]*renderer.ServiceBackend) // port name -> terminating backends
+	seen := make(map[string]struct{})                          // port name + backend IP:port
+	sliceNames := make([]string, 0, len(s.endpointSlices))
+	for sliceName := range s.endpointSlices {
+		sliceNames = append(sliceNames, sliceName)
+	}
+	sort.Strings(sliceNames) // for deterministic order of backends
+	for _, sliceName := range sliceNames {
+		slice := s.endpointSlices[sliceName]
+		if slice.AddressType == "FQDN" {
+			// not supported
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if len(ep.Addresses) == 0 {
+				continue
+			}
+			conditions := ep.GetConditions()
+			if !conditions.GetReady() && !(conditions.GetServing() && conditions.GetTerminating()) {
+				continue
+			}
+			// only the first address is used, the others are considered interchangeable
+			template, ok := s.newBackend(ep.Addresses[0], ep.NodeName, weights)
+			if !ok {
+				continue
+			}
+			added := false
+			for _, epPort := range slice.Ports {
+				port := epPort.GetName()
+				if _, exposedPort := s.contivSvc.Ports[port]; !exposedPort {
+					continue
+				}
+				sb := *template
+				sb.Port = uint16(epPort.GetPort())
+				key := port + "/" + net.JoinHostPort(sb.IP.String(), strconv.Itoa(int(sb.Port)))
+				if _, duplicate := seen[key]; duplicate {
+					continue
 				}
+				seen[key] = struct{}{}
+				added = true
+				if conditions.GetReady() {
+					s.contivSvc.Backends[port] = append(s.contivSvc.Backends[port], &sb)
+				} else {
+					terminating[port] = append(terminating[port], &sb)
+				}
+			}
+			if added && template.Local {
+				s.addLocalBackend(ep.GetTargetRef().GetKind(),
+					ep.GetTargetRef().GetName(), ep.GetTargetRef().GetNamespace())
 			}
 		}
 	}
+	for port, backends := range terminating {
+		drain := len(s.contivSvc.Backends[port]) > 0
+		for _, sb := range backends {
+			sb.Terminating = drain
+			s.contivSvc.Backends[port] = append(s.contivSvc.Backends[port], sb)
+		}
+	}
+}
 
-	s.refreshed = true
+// newBackend returns service backend (without port) for the given endpoint
+// IP address. Returns false if the address is not valid.
+func (s *Service) newBackend(ip string, nodeName string, weights endpointWeights) (*renderer.ServiceBackend, bool) {
+	var local bool
+	var hostNetwork bool
+	epIP := net.ParseIP(ip)
+	if epIP == nil {
+		s.sp.Log.WithFields(logging.Fields{
+			"service":    s.contivSvc.ID,
+			"endpointIP": ip,
+		}).Warn("Failed to parse endpoint IP")
+		return nil, false
+	}
+	if redirIP, isRedirected := s.sp.epRedirects[ip]; isRedirected {
+		epIP = net.ParseIP(redirIP)
+	}
+	if subnetsContain(s.sp.IPAM.PodSubnetsThisNode(ipnet.DefaultPodNetworkName), epIP) {
+		local = true
+	}
+	if !subnetsContain(s.sp.IPAM.PodSubnetsAllNodes(ipnet.DefaultPodNetworkName), epIP) {
+		hostNetwork = true
+		if s.isLocalNodeOrHostIP(epIP) {
+			local = true
+		}
+	}
+	thisNode := s.sp.nodeTopology[s.sp.ServiceLabel.GetAgentLabel()]
+	return &renderer.ServiceBackend{
+		IP:          epIP,
+		Local:       local,
+		HostNetwork: hostNetwork,
+		Weight:      weights.backendWeight(local, thisNode, s.sp.nodeTopology[nodeName]),
+	}, true
+}
+
+// addLocalBackend adds the target of a local endpoint into the set of local
+// backends (if it is a pod).
+func (s *Service) addLocalBackend(targetKind, targetName, targetNamespace string) {
+	if targetKind == "Pod" {
+		s.localBackends = append(s.localBackends,
+			podmodel.ID{Name: targetName, Namespace: targetNamespace})
+	}
 }
 
 // isLocalNodeOrHostIP returns true if the given IP is current node's node (VPP) or host (mgmt) IP, false otherwise.
@@ -222,3 +386,13 @@ func (s *Service) isLocalNodeOrHostIP(ip net.IP) bool {
 	}
 	return false
 }
+
+// subnetsContain returns true if the given IP belongs to any of the subnets.
+func subnetsContain(subnets []*net.IPNet, ip net.IP) bool {
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
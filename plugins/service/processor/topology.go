@@ -0,0 +1,154 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
+	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
+)
+
+const (
+	contivAnnotationPrefix         = "contivpp.io/"
+	localEndpointWeightAnnotation  = contivAnnotationPrefix + "local-endpoint-weight"  // k8s annotation used to override weight of node-local endpoints of a service
+	zoneEndpointWeightAnnotation   = contivAnnotationPrefix + "zone-endpoint-weight"   // k8s annotation used to override weight of same-zone endpoints of a service
+	regionEndpointWeightAnnotation = contivAnnotationPrefix + "region-endpoint-weight" // k8s annotation used to override weight of same-region endpoints of a service
+
+	zoneLabel         = "topology.kubernetes.io/zone"              // well-known node label with the zone of the node
+	regionLabel       = "topology.kubernetes.io/region"            // well-known node label with the region of the node
+	legacyZoneLabel   = "failure-domain.beta.kubernetes.io/zone"   // deprecated variant of zoneLabel
+	legacyRegionLabel = "failure-domain.beta.kubernetes.io/region" // deprecated variant of regionLabel
+)
+
+// nodeTopology is the location of a node in the cluster topology.
+// Empty string means that the zone/region is not known.
+type nodeTopology struct {
+	zone   string
+	region string
+}
+
+// endpointWeights are load-balancing weights of service endpoints based on
+// their location relative to this node.
+type endpointWeights struct {
+	local  uint8
+	zone   uint8
+	region uint8
+}
+
+// getNodeTopology reads zone and region of the node from its topology labels.
+func getNodeTopology(node *nodemodel.Node) nodeTopology {
+	labels := node.GetLabels()
+	topology := nodeTopology{
+		zone:   labels[zoneLabel],
+		region: labels[regionLabel],
+	}
+	if topology.zone == "" {
+		topology.zone = labels[legacyZoneLabel]
+	}
+	if topology.region == "" {
+		topology.region = labels[legacyRegionLabel]
+	}
+	return topology
+}
+
+// processNodeChange updates the topology of the given node and re-renders
+// all services if it has changed (weights of backends may have changed).
+// <node> is nil if the node was removed.
+func (sp *ServiceProcessor) processNodeChange(nodeName string, node *nodemodel.Node) error {
+	var topology nodeTopology
+	if node != nil {
+		topology = getNodeTopology(node)
+	}
+	if sp.nodeTopology[nodeName] == topology {
+		return nil
+	}
+	sp.Log.WithFields(logging.Fields{
+		"node":   nodeName,
+		"zone":   topology.zone,
+		"region": topology.region,
+	}).Debug("ServiceProcessor - node topology has changed")
+
+	if node == nil {
+		delete(sp.nodeTopology, nodeName)
+	} else {
+		sp.nodeTopology[nodeName] = topology
+	}
+
+	for _, svc := range sp.services {
+		oldContivSvc := svc.GetContivService()
+		oldBackends := svc.GetLocalBackends()
+		svc.refreshed = false
+		err := sp.renderService(svc, oldContivSvc, oldBackends)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getEndpointWeights returns load-balancing weights to use for endpoints
+// of the given service - taken from the configuration, optionally overridden
+// by the service annotations.
+func (sp *ServiceProcessor) getEndpointWeights(service *svcmodel.Service) endpointWeights {
+	weights := endpointWeights{
+		local:  sp.Config.ServiceLocalEndpointWeight,
+		zone:   sp.Config.ServiceZoneEndpointWeight,
+		region: sp.Config.ServiceRegionEndpointWeight,
+	}
+	annotations := map[string]*uint8{
+		localEndpointWeightAnnotation:  &weights.local,
+		zoneEndpointWeightAnnotation:   &weights.zone,
+		regionEndpointWeightAnnotation: &weights.region,
+	}
+	for annotation, weight := range annotations {
+		value, hasAnnotation := service.GetAnnotations()[annotation]
+		if !hasAnnotation {
+			continue
+		}
+		parsed, err := strconv.ParseUint(strings.TrimSpace(value), 10, 8)
+		if err != nil || parsed == 0 {
+			sp.Log.WithFields(logging.Fields{
+				"service":    svcmodel.GetID(service),
+				"annotation": annotation,
+				"value":      value,
+			}).Warn("Invalid endpoint weight (expected integer between 1 and 255), using the configured one")
+			continue
+		}
+		*weight = uint8(parsed)
+	}
+	return weights
+}
+
+// backendWeight returns load-balancing weight for a backend deployed on a node
+// with the given topology. Backend gets the highest of the weights it qualifies for.
+func (w endpointWeights) backendWeight(local bool, thisNode, backendNode nodeTopology) uint8 {
+	weight := uint8(1)
+	if thisNode.region != "" && backendNode.region == thisNode.region && w.region > weight {
+		weight = w.region
+	}
+	if thisNode.zone != "" && backendNode.zone == thisNode.zone && w.zone > weight {
+		weight = w.zone
+	}
+	if local && w.local > weight {
+		weight = w.local
+	}
+	return weight
+}
@@ -133,6 +133,20 @@ type ContivService struct {
 	// should be exposed outside of the cluster (e.g. external load-balancer IPs).
 	ExternalIPs *IPAddresses
 
+	// LBIngressIPs is a subset of ExternalIPs assigned to the service by an external
+	// load-balancer (LoadBalancer services only).
+	LBIngressIPs *IPAddresses
+
+	// SourceRanges restricts clients allowed to access the service via LBIngressIPs
+	// (K8s loadBalancerSourceRanges). Empty list means that access is not restricted.
+	// External IPs from the service spec are not restricted.
+	SourceRanges []*net.IPNet
+
+	// HealthCheckNodePort is a node port on which the availability of node-local
+	// backends is reported to external load-balancers (NodeLocal LoadBalancer
+	// services only, 0 if none).
+	HealthCheckNodePort uint16
+
 	// Ports is a map of all ports exposed for this service.
 	Ports map[string] /* service port name */ *ServicePort
 
@@ -154,10 +168,11 @@ const (
 // NewContivService is a constructor for ContivService.
 func NewContivService() *ContivService {
 	return &ContivService{
-		ClusterIPs:  NewIPAddresses(),
-		ExternalIPs: NewIPAddresses(),
-		Ports:       make(map[string]*ServicePort),
-		Backends:    make(map[string][]*ServiceBackend),
+		ClusterIPs:   NewIPAddresses(),
+		ExternalIPs:  NewIPAddresses(),
+		LBIngressIPs: NewIPAddresses(),
+		Ports:        make(map[string]*ServicePort),
+		Backends:     make(map[string][]*ServiceBackend),
 	}
 }
 
@@ -177,6 +192,13 @@ func (cs ContivService) String() string {
 			externalIPs += ", "
 		}
 	}
+	sourceRanges := ""
+	for idx, ipNet := range cs.SourceRanges {
+		sourceRanges += ipNet.String()
+		if idx < len(cs.SourceRanges)-1 {
+			sourceRanges += ", "
+		}
+	}
 	allBackends := ""
 	idx := 0
 	for port, svcBackends := range cs.Backends {
@@ -193,8 +215,8 @@ func (cs ContivService) String() string {
 		}
 		idx++
 	}
-	return fmt.Sprintf("ContivService %s <Traffic-Policy:%s ClusterIPs:[%s] ExternalIPs:[%s] Backends:{%s}>",
-		cs.ID.String(), cs.TrafficPolicy.String(), clusterIPs, externalIPs, allBackends)
+	return fmt.Sprintf("ContivService %s <Traffic-Policy:%s ClusterIPs:[%s] ExternalIPs:[%s] SourceRanges:[%s] Backends:{%s}>",
+		cs.ID.String(), cs.TrafficPolicy.String(), clusterIPs, externalIPs, sourceRanges, allBackends)
 }
 
 // String converts TrafficPolicyType into a human-readable string.
@@ -261,11 +283,14 @@ type ServiceBackend struct {
 	Port        uint16 /* backend-local port on which the service listens */
 	Local       bool   /* true if the backend is deployed on this node (can be leveraged for smart load-balancing) */
 	HostNetwork bool   /* true if the backend uses host networking */
+	Weight      uint8  /* load-balancing weight of the backend relative to other backends of the same port (>= 1) */
+	Terminating bool   /* true if the backend is being drained - should keep existing connections, but not get new ones */
 }
 
 // String converts Backend into a human-readable string.
 func (sb ServiceBackend) String() string {
-	return fmt.Sprintf("<IP:%s Port:%d, Local:%t>", sb.IP, sb.Port, sb.Local)
+	return fmt.Sprintf("<IP:%s Port:%d, Local:%t, Weight:%d, Terminating:%t>",
+		sb.IP, sb.Port, sb.Local, sb.Weight, sb.Terminating)
 }
 
 // IPAddresses is a set of IP addresses.
@@ -0,0 +1,271 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
+	"github.com/americanbinary/vpp/plugins/service/renderer"
+)
+
+// Renderer implements health-check node ports of LoadBalancer services with
+// the NodeLocal traffic policy.
+//
+// For every such service, the renderer runs an HTTP server listening on the
+// service health-check node port. The server answers with 200 (OK) if there
+// is at least one backend of the service deployed on this node, and with 503
+// (Service Unavailable) otherwise. External load-balancers can therefore avoid
+// sending traffic to nodes without local backends, which would otherwise drop it.
+// The response body is compatible with the one returned by kube-proxy.
+//
+// The servers listen in the host network stack. Health-check requests sent
+// to the node IP, which is owned by VPP, are forwarded to the host by a static
+// mapping of the NAT44 renderer. With other renderers the servers are reachable
+// only via host IPs. The renderer itself does not configure the vswitch, hence
+// it ignores updates of frontends, backends and node IPs.
+type Renderer struct {
+	Deps
+
+	sync.Mutex
+	servers map[uint16]*healthCheckServer // health-check node port -> server
+}
+
+// Deps lists dependencies of the Renderer.
+type Deps struct {
+	Log logging.Logger
+}
+
+// healthCheckServer reports the number of local backends of a single service.
+type healthCheckServer struct {
+	log            logging.Logger
+	service        svcmodel.ID
+	localEndpoints int
+	server         *http.Server
+}
+
+// healthCheckResponse is the body of the health-check response
+// (the same as returned by kube-proxy).
+type healthCheckResponse struct {
+	Service        healthCheckServiceID `json:"service"`
+	LocalEndpoints int                  `json:"localEndpoints"`
+}
+
+// healthCheckServiceID identifies the service in the health-check response.
+type healthCheckServiceID struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Init initializes the renderer.
+func (rndr *Renderer) Init() error {
+	rndr.servers = make(map[uint16]*healthCheckServer)
+	return nil
+}
+
+// AddService starts health-check server for a newly added service.
+func (rndr *Renderer) AddService(service *renderer.ContivService) error {
+	rndr.Lock()
+	defer rndr.Unlock()
+
+	rndr.syncService(nil, service)
+	return nil
+}
+
+// UpdateService updates the number of local backends reported for a changed
+// service, or starts/stops/moves its health-check server.
+func (rndr *Renderer) UpdateService(oldService, newService *renderer.ContivService, otherExistingServices []*renderer.ContivService) error {
+	rndr.Lock()
+	defer rndr.Unlock()
+
+	rndr.syncService(oldService, newService)
+	return nil
+}
+
+// DeleteService stops health-check server of a removed service.
+func (rndr *Renderer) DeleteService(service *renderer.ContivService, otherExistingServices []*renderer.ContivService) error {
+	rndr.Lock()
+	defer rndr.Unlock()
+
+	rndr.syncService(service, nil)
+	return nil
+}
+
+// UpdateNodePortServices is NOOP.
+func (rndr *Renderer) UpdateNodePortServices(nodeIPs *renderer.IPAddresses, npServices []*renderer.ContivService) error {
+	return nil
+}
+
+// UpdateLocalFrontendIfs is NOOP.
+func (rndr *Renderer) UpdateLocalFrontendIfs(oldIfNames, newIfNames renderer.Interfaces) error {
+	return nil
+}
+
+// UpdateLocalBackendIfs is NOOP.
+func (rndr *Renderer) UpdateLocalBackendIfs(oldIfNames, newIfNames renderer.Interfaces) error {
+	return nil
+}
+
+// Resync starts/stops health-check servers to match the current state of services.
+func (rndr *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
+	rndr.Lock()
+	defer rndr.Unlock()
+
+	services := make(map[uint16]*renderer.ContivService)
+	for _, service := range resyncEv.Services {
+		if service.HealthCheckNodePort != 0 {
+			services[service.HealthCheckNodePort] = service
+		}
+	}
+	for port, server := range rndr.servers {
+		if service, exists := services[port]; !exists || service.ID != server.service {
+			rndr.stopServer(port)
+		}
+	}
+	for port, service := range services {
+		rndr.startOrUpdateServer(port, service)
+	}
+	return nil
+}
+
+// Close stops all health-check servers.
+func (rndr *Renderer) Close() error {
+	rndr.Lock()
+	defer rndr.Unlock()
+
+	for port := range rndr.servers {
+		rndr.stopServer(port)
+	}
+	return nil
+}
+
+// syncService starts, updates or stops health-check server of a changed service.
+func (rndr *Renderer) syncService(oldService, newService *renderer.ContivService) {
+	if oldService != nil && oldService.HealthCheckNodePort != 0 &&
+		(newService == nil || newService.HealthCheckNodePort != oldService.HealthCheckNodePort) {
+		rndr.stopServer(oldService.HealthCheckNodePort)
+	}
+	if newService != nil && newService.HealthCheckNodePort != 0 {
+		rndr.startOrUpdateServer(newService.HealthCheckNodePort, newService)
+	}
+}
+
+// startOrUpdateServer starts health-check server for the given service,
+// or only updates the number of local endpoints if it is already running.
+func (rndr *Renderer) startOrUpdateServer(port uint16, service *renderer.ContivService) {
+	localEndpoints := countLocalEndpoints(service)
+	if server, running := rndr.servers[port]; running {
+		server.service = service.ID
+		server.localEndpoints = localEndpoints
+		return
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		// will be retried with the next update of the service
+		rndr.Log.WithFields(logging.Fields{
+			"service": service.ID,
+			"port":    port,
+		}).Errorf("Failed to start health-check server: %v", err)
+		return
+	}
+	server := &healthCheckServer{
+		log:            rndr.Log,
+		service:        service.ID,
+		localEndpoints: localEndpoints,
+	}
+	server.server = &http.Server{Handler: &healthCheckHandler{rndr: rndr, port: port}}
+	rndr.servers[port] = server
+	go func(service svcmodel.ID) {
+		if err := server.server.Serve(listener); err != http.ErrServerClosed {
+			server.log.Errorf("Health-check server for service %v failed: %v", service, err)
+		}
+	}(service.ID)
+	rndr.Log.Infof("Started health-check server for service %v on port %d", service.ID, port)
+}
+
+// stopServer stops health-check server running on the given port.
+func (rndr *Renderer) stopServer(port uint16) {
+	server, running := rndr.servers[port]
+	if !running {
+		return
+	}
+	if err := server.server.Close(); err != nil {
+		rndr.Log.Warnf("Failed to close health-check server on port %d: %v", port, err)
+	}
+	delete(rndr.servers, port)
+	rndr.Log.Infof("Stopped health-check server for service %v on port %d", server.service, port)
+}
+
+// healthCheckHandler answers health-check requests received on the given port.
+type healthCheckHandler struct {
+	rndr *Renderer
+	port uint16
+}
+
+// ServeHTTP reports the number of local endpoints of the service.
+func (h *healthCheckHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.rndr.Lock()
+	server, running := h.rndr.servers[h.port]
+	if !running {
+		h.rndr.Unlock()
+		http.Error(w, "service not found", http.StatusServiceUnavailable)
+		return
+	}
+	response := healthCheckResponse{
+		Service: healthCheckServiceID{
+			Namespace: server.service.Namespace,
+			Name:      server.service.Name,
+		},
+		LocalEndpoints: server.localEndpoints,
+	}
+	h.rndr.Unlock()
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if response.LocalEndpoints == 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.Write(body)
+}
+
+// countLocalEndpoints returns the number of distinct node-local backends of the service
+// (terminating backends, which are being drained, are not counted).
+func countLocalEndpoints(service *renderer.ContivService) int {
+	endpoints := make(map[string]struct{})
+	for _, backends := range service.Backends {
+		for _, backend := range backends {
+			if backend.Local && !backend.Terminating {
+				endpoints[backend.IP.String()] = struct{}{}
+			}
+		}
+	}
+	return len(endpoints)
+}
@@ -0,0 +1,130 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
+	"github.com/americanbinary/vpp/plugins/service/renderer"
+)
+
+func TestHealthCheckNodePort(t *testing.T) {
+	RegisterTestingT(t)
+
+	rndr := &Renderer{Deps: Deps{Log: logrus.DefaultLogger()}}
+	Expect(rndr.Init()).To(BeNil())
+
+	// service with one local and one remote backend
+	port := getFreePort()
+	service := renderer.NewContivService()
+	service.ID = svcmodel.ID{Namespace: "default", Name: "lb-service"}
+	service.TrafficPolicy = renderer.NodeLocal
+	service.HealthCheckNodePort = port
+	service.Backends["http"] = []*renderer.ServiceBackend{
+		{IP: net.ParseIP("10.1.1.3"), Port: 8080, Local: true},
+		{IP: net.ParseIP("10.1.2.3"), Port: 8080, Local: false},
+	}
+	service.Backends["https"] = []*renderer.ServiceBackend{
+		{IP: net.ParseIP("10.1.1.3"), Port: 8443, Local: true},
+		{IP: net.ParseIP("10.1.2.3"), Port: 8443, Local: false},
+	}
+	Expect(rndr.AddService(service)).To(BeNil())
+
+	status, response := healthCheck(port)
+	Expect(status).To(Equal(http.StatusOK))
+	Expect(response.Service.Namespace).To(Equal("default"))
+	Expect(response.Service.Name).To(Equal("lb-service"))
+	Expect(response.LocalEndpoints).To(Equal(1))
+
+	// local backend is being drained
+	drainedService := renderer.NewContivService()
+	drainedService.ID = service.ID
+	drainedService.TrafficPolicy = renderer.NodeLocal
+	drainedService.HealthCheckNodePort = port
+	drainedService.Backends["http"] = []*renderer.ServiceBackend{
+		{IP: net.ParseIP("10.1.1.3"), Port: 8080, Local: true, Terminating: true},
+		{IP: net.ParseIP("10.1.2.3"), Port: 8080, Local: false},
+	}
+	Expect(rndr.UpdateService(service, drainedService, nil)).To(BeNil())
+
+	status, response = healthCheck(port)
+	Expect(status).To(Equal(http.StatusServiceUnavailable))
+	Expect(response.LocalEndpoints).To(Equal(0))
+
+	// local backend removed
+	service2 := renderer.NewContivService()
+	service2.ID = service.ID
+	service2.TrafficPolicy = renderer.NodeLocal
+	service2.HealthCheckNodePort = port
+	service2.Backends["http"] = []*renderer.ServiceBackend{
+		{IP: net.ParseIP("10.1.2.3"), Port: 8080, Local: false},
+	}
+	Expect(rndr.UpdateService(drainedService, service2, nil)).To(BeNil())
+
+	status, response = healthCheck(port)
+	Expect(status).To(Equal(http.StatusServiceUnavailable))
+	Expect(response.LocalEndpoints).To(Equal(0))
+
+	// service removed
+	Expect(rndr.DeleteService(service2, nil)).To(BeNil())
+	_, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+	Expect(err).ToNot(BeNil())
+
+	// resync with the original service
+	resyncEv := renderer.NewResyncEventData()
+	resyncEv.Services = append(resyncEv.Services, service)
+	Expect(rndr.Resync(resyncEv)).To(BeNil())
+
+	status, response = healthCheck(port)
+	Expect(status).To(Equal(http.StatusOK))
+	Expect(response.LocalEndpoints).To(Equal(1))
+
+	// resync without services
+	Expect(rndr.Resync(renderer.NewResyncEventData())).To(BeNil())
+	_, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+	Expect(err).ToNot(BeNil())
+
+	Expect(rndr.Close()).To(BeNil())
+}
+
+// healthCheck sends health-check request to the given port.
+func healthCheck(port uint16) (status int, response *healthCheckResponse) {
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+	Expect(err).To(BeNil())
+	defer resp.Body.Close()
+	Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+
+	response = &healthCheckResponse{}
+	Expect(json.NewDecoder(resp.Body).Decode(response)).To(BeNil())
+	return resp.StatusCode, response
+}
+
+// getFreePort returns a port which is currently not in use.
+func getFreePort() uint16 {
+	listener, err := net.Listen("tcp", ":0")
+	Expect(err).To(BeNil())
+	defer listener.Close()
+	return uint16(listener.Addr().(*net.TCPAddr).Port)
+}
@@ -19,6 +19,7 @@ package ipv6route
 import (
 	"fmt"
 	"net"
+	"sort"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	controller "github.com/americanbinary/vpp/plugins/controller/api"
@@ -82,6 +83,7 @@ type portForward struct {
 // localBackend holds information about a node-local service backend.
 type localBackend struct {
 	ip           net.IP
+	weight       uint8
 	portForwards []*portForward
 }
 
@@ -94,7 +96,7 @@ func (lb localBackend) String() string {
 			portForwards += ", "
 		}
 	}
-	return fmt.Sprintf("localBackend IP: %s portForwards: %s", lb.ip.String(), portForwards)
+	return fmt.Sprintf("localBackend IP: %s weight: %d portForwards: %s", lb.ip.String(), lb.weight, portForwards)
 }
 
 // Init initializes the renderer.
@@ -214,19 +216,42 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 
 	addDelConfig = make(controller.KeyValuePairs)
 	updateConfig = make(controller.KeyValuePairs)
+
+	// with dual-stack, IPv4 services are rendered by the NAT44 renderer
+	var serviceIPs []net.IP
+	for _, serviceIP := range append(service.ClusterIPs.List(), service.ExternalIPs.List()...) {
+		if serviceIP.To4() == nil {
+			serviceIPs = append(serviceIPs, serviceIP)
+		}
+	}
+	if len(serviceIPs) == 0 {
+		return addDelConfig, updateConfig
+	}
+
 	localBackends := make([]*localBackend, 0)
 	hasHostNetworkLocalBackend := false
-	remoteBackendNodes := make(map[uint32]bool)
+	remoteBackendNodes := make(map[uint32]uint32) // node ID -> sum of backend weights
+	remoteBackends := make(map[string]struct{})   // remote backend IPs already counted
 
 	// collect info about the backends
 	for servicePortName, servicePort := range service.Ports {
+		useTerminating := !hasActiveBackend(service, servicePortName)
 		for _, backend := range service.Backends[servicePortName] {
+			if backend.Terminating && !useTerminating {
+				// draining of established connections is not supported, skip
+				// (unless there is no active backend left for the port)
+				continue
+			}
+			if backend.IP.To4() != nil {
+				// with dual-stack, IPv4 backends are handled by the NAT44 renderer
+				continue
+			}
 			if backend.Local {
 				// collect local backend info
 				if backend.HostNetwork {
 					hasHostNetworkLocalBackend = true
 				} else {
-					lb := &localBackend{ip: backend.IP}
+					lb := &localBackend{ip: backend.IP, weight: backend.Weight}
 					if servicePort.Port != backend.Port {
 						lb.portForwards = append(lb.portForwards, &portForward{
 							proto: servicePort.Protocol,
@@ -237,20 +262,24 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 					localBackends = append(localBackends, lb)
 				}
 			} else {
-				// collect remote backend info
+				// collect remote backend info (every backend counted once, not per port)
+				if _, counted := remoteBackends[backend.IP.String()]; counted {
+					continue
+				}
+				remoteBackends[backend.IP.String()] = struct{}{}
 				if backend.HostNetwork {
 					nodeID, err := rndr.nodeIDFromNodeOrHostIP(backend.IP)
 					if err != nil {
 						rndr.Log.Warnf("Error by extracting node ID from host IP: %v", err)
 					} else {
-						remoteBackendNodes[nodeID] = true
+						remoteBackendNodes[nodeID] += uint32(backend.Weight)
 					}
 				} else {
 					nodeID, err := rndr.IPAM.NodeIDFromPodIP(backend.IP)
 					if err != nil {
 						rndr.Log.Warnf("Error by extracting node ID from pod IP: %v", err)
 					} else {
-						remoteBackendNodes[nodeID] = true
+						remoteBackendNodes[nodeID] += uint32(backend.Weight)
 					}
 				}
 			}
@@ -264,8 +293,6 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 		"remoteBackendNodes":         remoteBackendNodes,
 	}).Debugf("Processing service backends")
 
-	serviceIPs := append(service.ClusterIPs.List(), service.ExternalIPs.List()...)
-
 	// for local backends (with non-hostNetwork), route serviceIPs towards the pods
 	for _, backend := range localBackends {
 		// connect local backend
@@ -280,12 +307,13 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 			continue
 		}
 		for _, serviceIP := range serviceIPs {
-			// route serviceIP to pod
+			// route serviceIP to pod (weighted multipath with multiple local backends)
 			route := &vpp_l3.Route{
 				DstNetwork:        serviceIP.String() + ipv6HostPrefix,
 				NextHopAddr:       backend.ip.String(),
 				OutgoingInterface: vppIfName,
 				VrfId:             rndr.ContivConf.GetRoutingConfig().PodVRFID,
+				Weight:            uint32(backend.weight),
 			}
 			key := models.Key(route)
 			addDelConfig[key] = route
@@ -312,6 +340,20 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 				rndr.Log.Warnf("pod %v not found in local pods list", podID)
 				continue
 			}
+			if len(service.SourceRanges) > 0 && service.LBIngressIPs.Has(serviceIP) {
+				// drop traffic to the LB ingress IP from sources outside of the allowed ranges
+				// (raw PREROUTING chain is traversed before the port forwarding)
+				srcRangesCh := rndr.getPodSourceRangesRuleChain(pod, updateConfig)
+				for _, rule := range rndr.getServiceSourceRangesRules(service, serviceIP) {
+					if oper == serviceAdd {
+						srcRangesCh.Rules = sliceAddIfNotExists(srcRangesCh.Rules, rule)
+					} else {
+						srcRangesCh.Rules = sliceRemove(srcRangesCh.Rules, rule)
+					}
+				}
+				key = linux_iptables.RuleChainKey(srcRangesCh.Name)
+				updateConfig[key] = srcRangesCh
+			}
 			for _, pf := range backend.portForwards {
 				// add / del an iptables rule into pod's PREROUTING chain (external traffic)
 				// and OUTPUT chain (local, pod-to-itself traffic)
@@ -365,8 +407,14 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 
 	// (only) in case of no local backends, route serviceIPs towards the nodes with some backend
 	if service.TrafficPolicy == renderer.ClusterWide && len(localBackends) == 0 && !hasHostNetworkLocalBackend {
+		if rndr.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.SRv6Transport &&
+			len(remoteBackendNodes) > 1 {
+			// SRv6 steerings cannot be weighted
+			rndr.Log.Warnf("Backend weights of service %v are not applied with the SRv6 node-to-node transport",
+				service.ID)
+		}
 		for _, serviceIP := range serviceIPs {
-			for nodeID := range remoteBackendNodes {
+			for nodeID, weight := range remoteBackendNodes {
 				switch rndr.ContivConf.GetRoutingConfig().NodeToNodeTransport {
 				case contivconf.VXLANTransport:
 					// route via the VXLAN
@@ -376,6 +424,7 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 						NextHopAddr:       nextHop.String(),
 						OutgoingInterface: rndr.IPNet.GetVxlanBVIIfName(),
 						VrfId:             rndr.ContivConf.GetRoutingConfig().PodVRFID,
+						Weight:            weight,
 					}
 					key := models.Key(route)
 					addDelConfig[key] = route
@@ -397,6 +446,7 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 						DstNetwork:  serviceIP.String() + ipv6HostPrefix,
 						NextHopAddr: nextHop.String(),
 						VrfId:       rndr.ContivConf.GetRoutingConfig().MainVRFID,
+						Weight:      weight,
 					}
 					key = models.Key(route)
 					addDelConfig[key] = route
@@ -439,6 +489,18 @@ func (rndr *Renderer) renderService(service *renderer.ContivService, oper operat
 	return addDelConfig, updateConfig
 }
 
+// hasActiveBackend returns true if the given service port has at least one
+// non-terminating IPv6 backend that can be routed to from this node.
+func hasActiveBackend(service *renderer.ContivService, portName string) bool {
+	for _, backend := range service.Backends[portName] {
+		if !backend.Terminating && backend.IP.To4() == nil &&
+			(service.TrafficPolicy == renderer.ClusterWide || backend.Local) {
+			return true
+		}
+	}
+	return false
+}
+
 // nodeIDFromNodeOrHostIP returns node ID matching with the provided node (VPP) or host (mgmt) IP.
 // If no match is found for provided IP, error is returned.
 func (rndr *Renderer) nodeIDFromNodeOrHostIP(ip net.IP) (uint32, error) {
@@ -516,6 +578,71 @@ func (rndr *Renderer) getServicePortForwardRule(serviceIP net.IP, pf *portForwar
 		serviceIP.String()+ipv6HostPrefix, proto, proto, pf.from, pf.to)
 }
 
+// getPodSourceRangesRuleChain returns the config of the pod-local iptables rule chain
+// enforcing source ranges of services - looked up the same way as by getPodPFRuleChain.
+func (rndr *Renderer) getPodSourceRangesRuleChain(
+	pod *podmanager.LocalPod, currentConfig controller.KeyValuePairs) *linux_iptables.RuleChain {
+
+	rchName := fmt.Sprintf("source-ranges-%s-%s", pod.ContainerID, linux_iptables.RuleChain_IPV6.String())
+	key := linux_iptables.RuleChainKey(rchName)
+
+	val, exists := currentConfig[key]
+	if exists && val != nil {
+		return val.(*linux_iptables.RuleChain)
+	}
+
+	val = rndr.ConfigRetriever.GetConfig(key)
+	if val != nil {
+		return val.(*linux_iptables.RuleChain)
+	}
+
+	ruleChain := &linux_iptables.RuleChain{
+		Name: rchName,
+		Namespace: &linux_namespace.NetNamespace{
+			Type:      linux_namespace.NetNamespace_FD,
+			Reference: pod.NetworkNamespace,
+		},
+		Protocol:  linux_iptables.RuleChain_IPV6,
+		Table:     linux_iptables.RuleChain_RAW,
+		ChainType: linux_iptables.RuleChain_PREROUTING,
+	}
+	return ruleChain
+}
+
+// getServiceSourceRangesRules returns iptables rules accepting traffic to the given
+// LB ingress IP of the service from the allowed source ranges and dropping the rest.
+// Ports with protocols other than TCP and UDP are not restricted.
+func (rndr *Renderer) getServiceSourceRangesRules(service *renderer.ContivService, serviceIP net.IP) (rules []string) {
+	var portNames []string
+	for portName := range service.Ports {
+		portNames = append(portNames, portName)
+	}
+	sort.Strings(portNames)
+
+	for _, portName := range portNames {
+		port := service.Ports[portName]
+		var proto string
+		switch port.Protocol {
+		case renderer.TCP:
+			proto = "tcp"
+		case renderer.UDP:
+			proto = "udp"
+		default:
+			continue
+		}
+		match := fmt.Sprintf("-d %s -p %s -m %s --dport %d",
+			serviceIP.String()+ipv6HostPrefix, proto, proto, port.Port)
+		for _, sourceRange := range service.SourceRanges {
+			if sourceRange.IP.To4() != nil {
+				continue
+			}
+			rules = append(rules, fmt.Sprintf("%s -s %s -j ACCEPT", match, sourceRange.String()))
+		}
+		rules = append(rules, match+" -j DROP")
+	}
+	return rules
+}
+
 // sliceContains returns true if provided slice contains provided value, false otherwise.
 func sliceContains(slice []string, value string) bool {
 	for _, i := range slice {
@@ -0,0 +1,395 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat44
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	vpp_nat "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/nat"
+
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
+	"github.com/americanbinary/vpp/plugins/service/renderer"
+	"github.com/americanbinary/vpp/plugins/service/restapi"
+)
+
+const (
+	// how often are NAT sessions of draining backends checked
+	drainCheckPeriod = 5 * time.Second
+
+	// NAT session without any traffic for this long is not considered
+	// as active by the draining
+	drainIdleTimeout = 30 * time.Second
+)
+
+// BackendsDrained is triggered by the NAT44 renderer when some of the backends
+// removed from services are left without active NAT sessions or their drain
+// timeout has expired, i.e. they can be removed from the static mappings.
+type BackendsDrained struct {
+}
+
+// GetName returns name of the BackendsDrained event.
+func (ev *BackendsDrained) GetName() string {
+	return "Service Backends Drained"
+}
+
+// String describes BackendsDrained event.
+func (ev *BackendsDrained) String() string {
+	return ev.GetName()
+}
+
+// Method is Update.
+func (ev *BackendsDrained) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *BackendsDrained) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *BackendsDrained) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *BackendsDrained) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *BackendsDrained) Done(error) {
+	return
+}
+
+// IsDuplicateOf returns true if the given event is also BackendsDrained.
+func (ev *BackendsDrained) IsDuplicateOf(event controller.Event) bool {
+	_, isDrained := event.(*BackendsDrained)
+	return isDrained
+}
+
+// drainingService holds backends removed from a service, which are still kept
+// in the static mappings of the service (with zero probability) to not break
+// established NAT sessions.
+type drainingService struct {
+	service  *renderer.ContivService     // the last rendered version of the service
+	backends map[string]*drainingBackend // key = <port-name>/<IP>:<port>
+}
+
+// drainingBackend is a single service backend being drained.
+type drainingBackend struct {
+	portName string
+	backend  *renderer.ServiceBackend // copy of the removed backend marked as terminating
+	since    time.Time
+	deadline time.Time
+	sessions int // active NAT sessions as of the last check, -1 if not checked yet
+}
+
+// isDrained returns true if the backend can be removed from the static mappings.
+func (db *drainingBackend) isDrained(now time.Time) bool {
+	return db.sessions == 0 || !now.Before(db.deadline)
+}
+
+// drainTimeout returns the configured timeout for draining NAT sessions
+// of removed backends (0 = draining is disabled).
+func (rndr *Renderer) drainTimeout() time.Duration {
+	return time.Duration(rndr.Config.NATSessionDrainTimeout) * time.Second
+}
+
+// updateDrainingBackends starts draining of backends removed from the service
+// and stops draining of those which were added back or which can no longer
+// be drained.
+func (rndr *Renderer) updateDrainingBackends(oldService, newService *renderer.ContivService) {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	svcID := newService.ID.String()
+	ds, isDraining := rndr.drainingServices[svcID]
+	if !isDraining {
+		ds = &drainingService{backends: make(map[string]*drainingBackend)}
+	}
+	ds.service = newService
+
+	if timeout := rndr.drainTimeout(); timeout > 0 && oldService != nil {
+		now := time.Now()
+		for portName, backends := range oldService.Backends {
+			if !hasActiveBackend(newService, portName) {
+				// a lone backend with zero probability would get all the new sessions
+				continue
+			}
+			for _, backend := range backends {
+				if oldService.TrafficPolicy != renderer.ClusterWide && !backend.Local {
+					// remote backends of node-local services are not NATed by this node
+					continue
+				}
+				key := drainingBackendKey(portName, backend)
+				if _, known := ds.backends[key]; known || hasBackend(newService, portName, backend) {
+					continue
+				}
+				drained := *backend
+				drained.Terminating = true
+				ds.backends[key] = &drainingBackend{
+					portName: portName,
+					backend:  &drained,
+					since:    now,
+					deadline: now.Add(timeout),
+					sessions: -1,
+				}
+				rndr.Log.Infof("Draining NAT sessions of backend %s removed from service '%v'",
+					key, newService.ID)
+			}
+		}
+	}
+
+	rndr.pruneDrainingBackends(ds)
+	if len(ds.backends) == 0 {
+		delete(rndr.drainingServices, svcID)
+		return
+	}
+	rndr.drainingServices[svcID] = ds
+}
+
+// pruneDrainingBackends stops draining of backends which were added back into
+// the service, or whose service port was removed or is left without active
+// backends (a lone backend with zero probability would get all the new sessions).
+// The method expects drainMutex to be locked.
+func (rndr *Renderer) pruneDrainingBackends(ds *drainingService) {
+	for key, db := range ds.backends {
+		if hasBackend(ds.service, db.portName, db.backend) {
+			delete(ds.backends, key)
+			continue
+		}
+		if !hasActiveBackend(ds.service, db.portName) {
+			rndr.Log.Infof("Backend %s of service '%v' cannot be drained - no active backend left for port %s",
+				key, ds.service.ID, db.portName)
+			delete(ds.backends, key)
+		}
+	}
+}
+
+// resyncDrainingBackends keeps draining only backends of services that are still
+// deployed after the resync.
+func (rndr *Renderer) resyncDrainingBackends(services []*renderer.ContivService) {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	drainingServices := make(map[string]*drainingService)
+	for _, service := range services {
+		svcID := service.ID.String()
+		ds, isDraining := rndr.drainingServices[svcID]
+		if !isDraining {
+			continue
+		}
+		ds.service = service
+		rndr.pruneDrainingBackends(ds)
+		if len(ds.backends) > 0 {
+			drainingServices[svcID] = ds
+		}
+	}
+	rndr.drainingServices = drainingServices
+}
+
+// forgetDrainingBackends stops draining of all backends of the given service.
+func (rndr *Renderer) forgetDrainingBackends(service *renderer.ContivService) {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	delete(rndr.drainingServices, service.ID.String())
+}
+
+// withDrainingBackends returns the service with backends being drained added
+// back as terminating (i.e. not receiving new connections).
+func (rndr *Renderer) withDrainingBackends(service *renderer.ContivService) *renderer.ContivService {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	ds, isDraining := rndr.drainingServices[service.ID.String()]
+	if !isDraining {
+		return service
+	}
+	ds.service = service
+
+	withDraining := *service
+	withDraining.Backends = make(map[string][]*renderer.ServiceBackend)
+	for portName, backends := range service.Backends {
+		withDraining.Backends[portName] = append([]*renderer.ServiceBackend{}, backends...)
+	}
+	for _, key := range sortedDrainingBackends(ds) {
+		db := ds.backends[key]
+		withDraining.Backends[db.portName] = append(withDraining.Backends[db.portName], db.backend)
+	}
+	return &withDraining
+}
+
+// RemoveDrainedBackends removes backends which are left without active NAT
+// sessions or whose drain timeout has expired from the static mappings
+// of their services.
+func (rndr *Renderer) RemoveDrainedBackends(now time.Time) error {
+	if rndr.snatOnly {
+		return nil
+	}
+
+	var services []*renderer.ContivService
+	rndr.drainMutex.Lock()
+	for svcID, ds := range rndr.drainingServices {
+		removed := false
+		for key, db := range ds.backends {
+			if !db.isDrained(now) {
+				continue
+			}
+			if db.sessions == 0 {
+				rndr.Log.Infof("Backend %s of service '%v' was drained", key, ds.service.ID)
+			} else {
+				rndr.Log.Warnf("Drain timeout expired for backend %s of service '%v' (remaining sessions: %d)",
+					key, ds.service.ID, db.sessions)
+			}
+			delete(ds.backends, key)
+			removed = true
+		}
+		if removed {
+			services = append(services, ds.service)
+		}
+		if len(ds.backends) == 0 {
+			delete(rndr.drainingServices, svcID)
+		}
+	}
+	rndr.drainMutex.Unlock()
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ID.String() < services[j].ID.String()
+	})
+	for _, service := range services {
+		dnat := rndr.contivServiceToDNat(rndr.withDrainingBackends(service))
+		txn := rndr.UpdateTxnFactory(fmt.Sprintf("remove drained backends of service '%v'", service.ID))
+		txn.Put(vpp_nat.DNAT44Key(dnat.Label), dnat)
+	}
+	return nil
+}
+
+// GetDrainingBackends returns backends of all services which are being drained.
+func (rndr *Renderer) GetDrainingBackends() []restapi.DrainingService {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	services := []restapi.DrainingService{}
+	for _, ds := range rndr.drainingServices {
+		service := restapi.DrainingService{ServiceID: ds.service.ID}
+		for _, key := range sortedDrainingBackends(ds) {
+			db := ds.backends[key]
+			service.Backends = append(service.Backends, restapi.DrainingBackend{
+				ServicePort: db.portName,
+				IP:          db.backend.IP,
+				Port:        db.backend.Port,
+				Since:       db.since,
+				Deadline:    db.deadline,
+				Sessions:    db.sessions,
+			})
+		}
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceID.String() < services[j].ServiceID.String()
+	})
+	return services
+}
+
+// drainingBackendsCheck runs in a separate go routine and periodically counts
+// active NAT sessions of the backends being drained. Removal of drained backends
+// is triggered through the event loop.
+func (rndr *Renderer) drainingBackendsCheck() {
+	defer rndr.wg.Done()
+
+	rndr.Log.Infof("NAT session draining enabled, drain timeout=%v.", rndr.drainTimeout())
+
+	ticker := time.NewTicker(drainCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !rndr.hasDrainingBackends() {
+				continue
+			}
+			now := time.Now()
+			sessions := make(map[string]int) // key = <IP>:<port>
+			for _, session := range rndr.dumpNATSessions() {
+				if now.Sub(session.lastHeard) > drainIdleTimeout {
+					continue
+				}
+				ip := net.IP(session.InsideIPAddress[:])
+				sessions[fmt.Sprintf("%s:%d", ip, session.InsidePort)]++
+			}
+			if rndr.updateDrainingSessions(sessions, now) {
+				if err := rndr.EventLoop.PushEvent(&BackendsDrained{}); err != nil {
+					rndr.Log.Warnf("Failed to trigger removal of drained backends: %v", err)
+				}
+			}
+		case <-rndr.stopDrain:
+			return
+		}
+	}
+}
+
+// hasDrainingBackends returns true if there is at least one backend being drained.
+func (rndr *Renderer) hasDrainingBackends() bool {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	return len(rndr.drainingServices) > 0
+}
+
+// updateDrainingSessions updates the counts of active NAT sessions of draining
+// backends (indexed by <IP>:<port>). Returns true if any backend can be removed.
+func (rndr *Renderer) updateDrainingSessions(sessions map[string]int, now time.Time) (drained bool) {
+	rndr.drainMutex.Lock()
+	defer rndr.drainMutex.Unlock()
+
+	for _, ds := range rndr.drainingServices {
+		for _, db := range ds.backends {
+			db.sessions = sessions[fmt.Sprintf("%s:%d", db.backend.IP, db.backend.Port)]
+			if db.isDrained(now) {
+				drained = true
+			}
+		}
+	}
+	return drained
+}
+
+// sortedDrainingBackends returns keys of the backends being drained in a sorted order.
+func sortedDrainingBackends(ds *drainingService) (keys []string) {
+	for key := range ds.backends {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// drainingBackendKey returns key identifying the given backend of a service port.
+func drainingBackendKey(portName string, backend *renderer.ServiceBackend) string {
+	return fmt.Sprintf("%s/%s:%d", portName, backend.IP, backend.Port)
+}
+
+// hasBackend returns true if the given service port has backend with the same
+// IP address and port as the given one.
+func hasBackend(service *renderer.ContivService, portName string, backend *renderer.ServiceBackend) bool {
+	for _, other := range service.Backends[portName] {
+		if other.IP.Equal(backend.IP) && other.Port == backend.Port {
+			return true
+		}
+	}
+	return false
+}
@@ -19,6 +19,8 @@ package nat44
 import (
 	"fmt"
 	"net"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"go.ligato.io/cn-infra/v2/logging"
 	nat_api "go.ligato.io/vpp-agent/v3/plugins/vpp/binapi/vpp1908/nat"
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
 	vpp_nat "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/nat"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
@@ -43,6 +46,13 @@ const (
 	identityDNATLabel = "DNAT-identities"
 
 	vxlanPort = 4789 // port used byt VXLAN
+
+	// Name of the ACL dropping traffic destined to external IPs of services
+	// from sources outside of the allowed source ranges.
+	sourceRangesACLName = "service-source-ranges"
+
+	ipv4AddrAny = "0.0.0.0/0"
+	ipv6AddrAny = "::/0"
 )
 
 const (
@@ -87,6 +97,11 @@ var (
 // Until VPP supports timing-out of NAT sessions, the renderer also performs
 // periodic cleanup of inactive NAT sessions.
 //
+// Backends removed from a service are not deleted from the static mappings
+// immediately. Instead, they are kept with zero probability, i.e. without
+// receiving new connections, until their NAT sessions become idle or the drain
+// timeout expires, so that established connections are not broken.
+//
 // An extra feature of the renderer, outside the scope of services, is a management
 // of the dynamic source-NAT for node-outbound traffic, configured to enable
 // Internet access even for pods with private IPv4 addresses.
@@ -95,6 +110,12 @@ var (
 // the NAT main address pool and the interface itself is switched into
 // the post-routing NAT mode (`output` feature) - both during Resync.
 //
+// Access to load-balancer ingress IPs of LoadBalancer services with source ranges
+// defined is restricted by the ACL `service-source-ranges`, applied on the ingress
+// of the node interfaces facing the outside world. ACLs are evaluated before
+// the NAT, i.e. traffic from sources outside of the allowed ranges gets dropped
+// before DNAT.
+//
 // For more implementation details, please study the developer's guide for
 // services: `docs/dev-guide/SERVICES.md` from the top directory.
 type Renderer struct {
@@ -104,9 +125,23 @@ type Renderer struct {
 	natGlobalCfg *vpp_nat.Nat44Global
 	nodeIPs      *renderer.IPAddresses
 
+	/* services with restricted source ranges (by service ID) */
+	restrictedServices map[string]*renderer.ContivService
+	hasSourceRangesACL bool
+
 	/* dynamic SNAT */
 	defaultIfName string
 	defaultIfIP   net.IP
+
+	/* backends removed from services with NAT sessions being drained (by service ID) */
+	drainMutex       sync.Mutex
+	drainingServices map[string]*drainingService
+	stopDrain        chan struct{}
+	wg               sync.WaitGroup
+
+	/* NAT session dumps */
+	vppMutex     sync.Mutex // GoVPPChan is shared by the session cleanup and the draining
+	vppStartTime time.Time  // VPP counts the time of NAT sessions since its start
 }
 
 // Deps lists dependencies of the Renderer.
@@ -118,8 +153,9 @@ type Deps struct {
 	IPNet            ipnet.API
 	UpdateTxnFactory func(change string) (txn controller.UpdateOperations)
 	ResyncTxnFactory func() (txn controller.ResyncOperations)
-	GoVPPChan        govpp.Channel      /* used for direct NAT binary API calls */
-	Stats            statscollector.API /* used for exporting the statistics */
+	GoVPPChan        govpp.Channel        /* used for direct NAT binary API calls */
+	Stats            statscollector.API   /* used for exporting the statistics */
+	EventLoop        controller.EventLoop /* used to trigger removal of drained backends */
 }
 
 // Init initializes the renderer.
@@ -127,6 +163,9 @@ type Deps struct {
 // services to another renderer.
 func (rndr *Renderer) Init(snatOnly bool) error {
 	rndr.snatOnly = snatOnly
+	rndr.restrictedServices = make(map[string]*renderer.ContivService)
+	rndr.drainingServices = make(map[string]*drainingService)
+	rndr.stopDrain = make(chan struct{})
 	rndr.natGlobalCfg = &vpp_nat.Nat44Global{
 		Forwarding: true,
 	}
@@ -136,10 +175,20 @@ func (rndr *Renderer) Init(snatOnly bool) error {
 	return nil
 }
 
-// AfterInit starts asynchronous NAT session cleanup.
+// AfterInit starts asynchronous NAT session cleanup and checks of draining backends.
 func (rndr *Renderer) AfterInit() error {
+	// VPP counts the time from 0 since its start. Let's assume it is now
+	// (it shouldn't be more than few seconds since its start).
+	rndr.vppStartTime = time.Now()
+
 	// run async NAT session cleanup routine
 	go rndr.idleNATSessionCleanup()
+
+	// run async checks of NAT sessions of draining backends
+	if !rndr.snatOnly && rndr.drainTimeout() > 0 {
+		rndr.wg.Add(1)
+		go rndr.drainingBackendsCheck()
+	}
 	return nil
 }
 
@@ -152,6 +201,7 @@ func (rndr *Renderer) AddService(service *renderer.ContivService) error {
 	dnat := rndr.contivServiceToDNat(service)
 	txn := rndr.UpdateTxnFactory(fmt.Sprintf("add service '%v'", service.ID))
 	txn.Put(vpp_nat.DNAT44Key(dnat.Label), dnat)
+	rndr.updateSourceRanges(txn, nil, service)
 	return nil
 }
 
@@ -160,9 +210,11 @@ func (rndr *Renderer) UpdateService(oldService, newService *renderer.ContivServi
 	if rndr.snatOnly {
 		return nil
 	}
-	newDNAT := rndr.contivServiceToDNat(newService)
+	rndr.updateDrainingBackends(oldService, newService)
+	newDNAT := rndr.contivServiceToDNat(rndr.withDrainingBackends(newService))
 	txn := rndr.UpdateTxnFactory(fmt.Sprintf("update service '%v'", newService.ID))
 	txn.Put(vpp_nat.DNAT44Key(newDNAT.Label), newDNAT)
+	rndr.updateSourceRanges(txn, oldService, newService)
 	return nil
 }
 
@@ -173,8 +225,10 @@ func (rndr *Renderer) DeleteService(service *renderer.ContivService, otherExisti
 		return nil
 	}
 
+	rndr.forgetDrainingBackends(service)
 	txn := rndr.UpdateTxnFactory(fmt.Sprintf("delete service '%v'", service.ID))
 	txn.Delete(vpp_nat.DNAT44Key(service.ID.String()))
+	rndr.updateSourceRanges(txn, service, nil)
 	return nil
 }
 
@@ -192,7 +246,7 @@ func (rndr *Renderer) UpdateNodePortServices(nodeIPs *renderer.IPAddresses,
 	// Update DNAT of all node-port services via ligato/vpp-agent.
 	txn := rndr.UpdateTxnFactory("update nodeport services")
 	for _, npService := range npServices {
-		newDNAT := rndr.contivServiceToDNat(npService)
+		newDNAT := rndr.contivServiceToDNat(rndr.withDrainingBackends(npService))
 		txn.Put(vpp_nat.DNAT44Key(newDNAT.Label), newDNAT)
 	}
 	return nil
@@ -311,13 +365,27 @@ func (rndr *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
 	rndr.nodeIPs = resyncEv.NodeIPs
 
 	// Resync DNAT configuration.
+	rndr.resyncDrainingBackends(resyncEv.Services)
 	for _, service := range resyncEv.Services {
-		dnat := rndr.contivServiceToDNat(service)
+		dnat := rndr.contivServiceToDNat(rndr.withDrainingBackends(service))
 		txn.Put(vpp_nat.DNAT44Key(dnat.Label), dnat)
 	}
 	dnat := rndr.exportIdentityMappings()
 	txn.Put(vpp_nat.DNAT44Key(dnat.Label), dnat)
 
+	// Resync ACL enforcing source ranges.
+	rndr.restrictedServices = make(map[string]*renderer.ContivService)
+	for _, service := range resyncEv.Services {
+		if hasSourceRanges(service) {
+			rndr.restrictedServices[service.ID.String()] = service
+		}
+	}
+	sourceRangesACL := rndr.renderSourceRangesACL()
+	rndr.hasSourceRangesACL = sourceRangesACL != nil
+	if rndr.hasSourceRangesACL {
+		txn.Put(vpp_acl.Key(sourceRangesACL.Name), sourceRangesACL)
+	}
+
 	// Re-build the global NAT config.
 	rndr.natGlobalCfg = &vpp_nat.Nat44Global{
 		Forwarding: true,
@@ -407,6 +475,157 @@ func (rndr *Renderer) getDefaultInterface() (ifName string, ifAddress net.IP) {
 	return "", nil
 }
 
+// updateSourceRanges updates the set of services with restricted source ranges
+// and re-renders the ACL enforcing the ranges if needed.
+func (rndr *Renderer) updateSourceRanges(txn controller.UpdateOperations, oldService, newService *renderer.ContivService) {
+	if !hasSourceRanges(oldService) && !hasSourceRanges(newService) {
+		return
+	}
+	if oldService != nil {
+		delete(rndr.restrictedServices, oldService.ID.String())
+	}
+	if hasSourceRanges(newService) {
+		rndr.restrictedServices[newService.ID.String()] = newService
+	}
+
+	acl := rndr.renderSourceRangesACL()
+	if acl != nil {
+		txn.Put(vpp_acl.Key(acl.Name), acl)
+	} else if rndr.hasSourceRangesACL {
+		txn.Delete(vpp_acl.Key(sourceRangesACLName))
+	}
+	rndr.hasSourceRangesACL = acl != nil
+}
+
+// renderSourceRangesACL renders ACL which drops traffic destined to load-balancer
+// ingress IPs of restricted services from sources outside of the allowed ranges.
+// Ports with protocols not supported by the ACL rules are not restricted.
+// Permitted traffic is reflected, so that the ACL can replace the reflective ACL
+// of the policy renderer on the same interfaces.
+// Returns nil if there is nothing to restrict.
+func (rndr *Renderer) renderSourceRangesACL() *vpp_acl.ACL {
+	acl := &vpp_acl.ACL{Name: sourceRangesACLName}
+
+	// sort services and ports to get deterministic order of rules
+	var svcIDs []string
+	for svcID := range rndr.restrictedServices {
+		svcIDs = append(svcIDs, svcID)
+	}
+	sort.Strings(svcIDs)
+	for _, svcID := range svcIDs {
+		service := rndr.restrictedServices[svcID]
+		var portNames []string
+		for portName, port := range service.Ports {
+			if port.Protocol != renderer.TCP && port.Protocol != renderer.UDP {
+				continue
+			}
+			portNames = append(portNames, portName)
+		}
+		sort.Strings(portNames)
+
+		for _, lbIngressIP := range service.LBIngressIPs.List() {
+			if lbIngressIP.To4() == nil {
+				// IPv6 is not handled by this renderer
+				continue
+			}
+			dstNet := &net.IPNet{IP: lbIngressIP.To4(), Mask: net.CIDRMask(net.IPv4len*8, net.IPv4len*8)}
+			for _, portName := range portNames {
+				port := service.Ports[portName]
+				for _, sourceRange := range service.SourceRanges {
+					if sourceRange.IP.To4() == nil {
+						continue
+					}
+					acl.Rules = append(acl.Rules,
+						sourceRangeACLRule(vpp_acl.ACL_Rule_REFLECT, sourceRange.String(), dstNet.String(), port))
+				}
+				acl.Rules = append(acl.Rules,
+					sourceRangeACLRule(vpp_acl.ACL_Rule_DENY, ipv4AddrAny, dstNet.String(), port))
+			}
+		}
+	}
+	if len(acl.Rules) == 0 {
+		return nil
+	}
+
+	// the rest of the traffic is not affected
+	acl.Rules = append(acl.Rules,
+		sourceRangeACLRule(vpp_acl.ACL_Rule_REFLECT, ipv4AddrAny, ipv4AddrAny, nil),
+		sourceRangeACLRule(vpp_acl.ACL_Rule_REFLECT, ipv6AddrAny, ipv6AddrAny, nil))
+
+	// apply on interfaces facing the outside world
+	acl.Interfaces = &vpp_acl.ACL_Interfaces{
+		Ingress: rndr.sourceRangesACLInterfaces(),
+	}
+	return acl
+}
+
+// sourceRangesACLInterfaces returns interfaces with the ACL enforcing source ranges.
+func (rndr *Renderer) sourceRangesACLInterfaces() (ifNames []string) {
+	if mainIfName := rndr.ContivConf.GetMainInterfaceName(); mainIfName != "" {
+		ifNames = append(ifNames, mainIfName)
+	}
+	for _, otherIf := range rndr.ContivConf.GetOtherVPPInterfaces() {
+		ifNames = append(ifNames, otherIf.InterfaceName)
+	}
+	return ifNames
+}
+
+// GetIngressACLInterfaces returns interfaces with the ACL enforcing source ranges
+// installed on the ingress. The policy renderer does not attach its reflective ACL
+// to these interfaces - permitted traffic is reflected by the source-ranges ACL
+// instead, keeping a single ingress ACL per interface.
+func (rndr *Renderer) GetIngressACLInterfaces() []string {
+	if !rndr.hasSourceRangesACL {
+		return nil
+	}
+	return rndr.sourceRangesACLInterfaces()
+}
+
+// sourceRangeACLRule returns ACL rule matching traffic from <srcNet> to <dstNet>
+// and the given service port (nil = any protocol and port).
+func sourceRangeACLRule(action vpp_acl.ACL_Rule_Action, srcNet, dstNet string, port *renderer.ServicePort) *vpp_acl.ACL_Rule {
+	const maxPortNum = ^uint16(0)
+	rule := &vpp_acl.ACL_Rule{
+		Action: action,
+		IpRule: &vpp_acl.ACL_Rule_IpRule{
+			Ip: &vpp_acl.ACL_Rule_IpRule_Ip{
+				SourceNetwork:      srcNet,
+				DestinationNetwork: dstNet,
+			},
+		},
+	}
+	if port == nil {
+		return rule
+	}
+	srcPortRange := &vpp_acl.ACL_Rule_IpRule_PortRange{
+		LowerPort: 0,
+		UpperPort: uint32(maxPortNum),
+	}
+	dstPortRange := &vpp_acl.ACL_Rule_IpRule_PortRange{
+		LowerPort: uint32(port.Port),
+		UpperPort: uint32(port.Port),
+	}
+	switch port.Protocol {
+	case renderer.TCP:
+		rule.IpRule.Tcp = &vpp_acl.ACL_Rule_IpRule_Tcp{
+			SourcePortRange:      srcPortRange,
+			DestinationPortRange: dstPortRange,
+		}
+	case renderer.UDP:
+		rule.IpRule.Udp = &vpp_acl.ACL_Rule_IpRule_Udp{
+			SourcePortRange:      srcPortRange,
+			DestinationPortRange: dstPortRange,
+		}
+	}
+	return rule
+}
+
+// hasSourceRanges returns true if access to load-balancer ingress IPs of the service
+// is restricted by source ranges.
+func hasSourceRanges(service *renderer.ContivService) bool {
+	return service != nil && len(service.SourceRanges) > 0 && len(service.LBIngressIPs.List()) > 0
+}
+
 // contivServiceToDNat returns DNAT configuration corresponding to a given service.
 func (rndr *Renderer) contivServiceToDNat(service *renderer.ContivService) *vpp_nat.DNat44 {
 	dnat := &vpp_nat.DNat44{}
@@ -428,9 +647,51 @@ func (rndr *Renderer) exportDNATMappings(service *renderer.ContivService) []*vpp
 	mappings = append(mappings, rndr.exportServiceIPMappings(service, service.ClusterIPs, clusterIP)...)
 	mappings = append(mappings, rndr.exportServiceIPMappings(service, service.ExternalIPs, externalIP)...)
 
+	// Forward health-check node port to the health-check server.
+	if mapping := rndr.exportHealthCheckMapping(service); mapping != nil {
+		mappings = append(mappings, mapping)
+	}
+
 	return mappings
 }
 
+// exportHealthCheckMapping exports D-NAT mapping which forwards health-check
+// node port of the service from the node IP to the host network stack,
+// where the health-check server listens. Returns nil if the service has
+// no health-check node port or if the node or host IPv4 address is not known.
+func (rndr *Renderer) exportHealthCheckMapping(service *renderer.ContivService) *vpp_nat.DNat44_StaticMapping {
+	if service.HealthCheckNodePort == 0 {
+		return nil
+	}
+	nodeIP, _ := rndr.IPNet.GetNodeIP()
+	if nodeIP == nil || nodeIP.To4() == nil {
+		return nil
+	}
+	var hostIP net.IP
+	for _, ip := range rndr.IPNet.GetHostIPs() {
+		if ip.To4() != nil {
+			hostIP = ip.To4()
+			break
+		}
+	}
+	if hostIP == nil {
+		return nil
+	}
+	return &vpp_nat.DNat44_StaticMapping{
+		ExternalIp:   nodeIP.To4().String(),
+		ExternalPort: uint32(service.HealthCheckNodePort),
+		Protocol:     vpp_nat.DNat44_TCP,
+		TwiceNat:     vpp_nat.DNat44_StaticMapping_SELF,
+		LocalIps: []*vpp_nat.DNat44_StaticMapping_LocalIP{
+			{
+				LocalIp:   hostIP.String(),
+				LocalPort: uint32(service.HealthCheckNodePort),
+				VrfId:     rndr.ContivConf.GetRoutingConfig().MainVRFID,
+			},
+		},
+	}
+}
+
 // exportServiceIPMappings exports the corresponding list of D-NAT mappings from a list of service IPs of the given service.
 func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
 	serviceIPs *renderer.IPAddresses, ipType serviceIPType) (mappings []*vpp_nat.DNat44_StaticMapping) {
@@ -468,19 +729,25 @@ func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
 			case renderer.UDP:
 				mapping.Protocol = vpp_nat.DNat44_UDP
 			}
+			drain := hasActiveBackend(service, portName)
 			for _, backend := range service.Backends[portName] {
 				if service.TrafficPolicy != renderer.ClusterWide && !backend.Local {
 					// Do not NAT+LB remote backends.
 					continue
 				}
+				if backend.IP.To4() == nil {
+					// IPv6 backends (dual-stack) are handled by the IPv6 renderer
+					continue
+				}
 				local := &vpp_nat.DNat44_StaticMapping_LocalIP{
-					LocalIp:   backend.IP.String(),
-					LocalPort: uint32(backend.Port),
+					LocalIp:     backend.IP.String(),
+					LocalPort:   uint32(backend.Port),
+					Probability: uint32(backend.Weight),
 				}
-				if backend.Local {
-					local.Probability = uint32(rndr.Config.ServiceLocalEndpointWeight)
-				} else {
-					local.Probability = 1
+				if backend.Terminating && drain {
+					// Keep the backend for established sessions,
+					// but do not load-balance new connections to it.
+					local.Probability = 0
 				}
 				if rndr.isThisNodeOrHostIP(backend.IP) {
 					local.VrfId = routingCfg.MainVRFID
@@ -513,6 +780,18 @@ func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
 	return mappings
 }
 
+// hasActiveBackend returns true if the given service port has at least one
+// non-terminating IPv4 backend that can be NATed on this node.
+func hasActiveBackend(service *renderer.ContivService, portName string) bool {
+	for _, backend := range service.Backends[portName] {
+		if !backend.Terminating && backend.IP.To4() != nil &&
+			(service.TrafficPolicy == renderer.ClusterWide || backend.Local) {
+			return true
+		}
+	}
+	return false
+}
+
 // isThisNodeOrHostIP returns true if the given IP is current node's node (VPP) or host (mgmt) IP, false otherwise.
 func (rndr *Renderer) isThisNodeOrHostIP(ip net.IP) bool {
 	nodeIP, _ := rndr.IPNet.GetNodeIP()
@@ -529,7 +808,12 @@ func (rndr *Renderer) isThisNodeOrHostIP(ip net.IP) bool {
 
 // isLocalPodIP returns true if the given IP is this node's local POD IP.
 func (rndr *Renderer) isLocalPodIP(ip net.IP) bool {
-	return rndr.IPAM.PodSubnetThisNode(ipnet.DefaultPodNetworkName).Contains(ip)
+	for _, podSubnet := range rndr.IPAM.PodSubnetsThisNode(ipnet.DefaultPodNetworkName) {
+		if podSubnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
 
 // exportIdentityMappings returns DNAT configuration with identities to exclude
@@ -569,8 +853,12 @@ func (rndr *Renderer) exportIdentityMappings() *vpp_nat.DNat44 {
 	return idNat
 }
 
-// Close deallocates resources held by the renderer.
+// Close stops the checks of draining backends.
 func (rndr *Renderer) Close() error {
+	if rndr.stopDrain != nil {
+		close(rndr.stopDrain)
+	}
+	rndr.wg.Wait()
 	return nil
 }
 
@@ -600,87 +888,48 @@ func (rndr *Renderer) idleNATSessionCleanup() {
 	rndr.Stats.RegisterGaugeFunc("deletedOtherNatSessions", "Total count of deleted non-TCP NAT sessions", deletedOtherNatSessionsGauge)
 	rndr.Stats.RegisterGaugeFunc("natSessionDeleteErrors", "Count of errors by NAT session delete", natSessionDeleteErrorsGauge)
 
-	// VPP counts the time from 0 since its start. Let's assume it is now
-	// (it shouldn't be more than few seconds since its start).
-	zeroTime := time.Now()
-
 	for {
 		<-time.After(otherTimeout)
 
 		rndr.Log.Debugf("NAT session cleanup started.")
 
-		natUsers := make([]*nat_api.Nat44UserDetails, 0)
 		delRules := make([]*nat_api.Nat44DelSession, 0)
 		var tcpCount uint64
 		var otherCount uint64
 
-		// dump NAT users
-		req1 := &nat_api.Nat44UserDump{}
-		reqCtx1 := rndr.GoVPPChan.SendMultiRequest(req1)
-		for {
-			msg := &nat_api.Nat44UserDetails{}
-			stop, err := reqCtx1.ReceiveReply(msg)
-			if stop {
-				break // break out of the loop
-			}
-			if err != nil {
-				rndr.Log.Errorf("Error by dumping NAT users: %v", err)
-			}
-			natUsers = append(natUsers, msg)
-		}
-
-		// dump NAT sessions per user
-		for _, natUser := range natUsers {
-			req2 := &nat_api.Nat44UserSessionDump{
-				IPAddress: natUser.IPAddress,
-				VrfID:     natUser.VrfID,
+		for _, session := range rndr.dumpNATSessions() {
+			if session.Protocol == 6 {
+				tcpCount++
+			} else {
+				otherCount++
 			}
-			reqCtx2 := rndr.GoVPPChan.SendMultiRequest(req2)
 
-			for {
-				msg := &nat_api.Nat44UserSessionDetails{}
-				stop, err := reqCtx2.ReceiveReply(msg)
-				if stop {
-					break // break out of the loop
-				}
-				if err != nil {
-					rndr.Log.Errorf("Error by dumping NAT sessions: %v", err)
-				}
-				if msg.Protocol == 6 {
-					tcpCount++
-				} else {
-					otherCount++
-				}
-
-				lastHeard := zeroTime.Add(time.Duration(msg.LastHeard) * time.Second)
-				if lastHeard.Before(time.Now()) {
-					if (msg.Protocol == 6 && time.Since(lastHeard) > tcpTimeout) ||
-						(msg.Protocol != 6 && time.Since(lastHeard) > otherTimeout) {
-						// inactive session
-						delRule := &nat_api.Nat44DelSession{
-							Flags:    nat_api.NAT_IS_INSIDE,
-							Address:  msg.InsideIPAddress,
-							Port:     msg.InsidePort,
-							Protocol: uint8(msg.Protocol),
-							VrfID:    natUser.VrfID,
-						}
-						if msg.Flags&nat_api.NAT_IS_EXT_HOST_VALID != 0 {
-							delRule.Flags |= nat_api.NAT_IS_EXT_HOST_VALID
-
-							if msg.Flags&nat_api.NAT_IS_TWICE_NAT != 0 {
-								delRule.ExtHostAddress = msg.ExtHostNatAddress
-								delRule.ExtHostPort = msg.ExtHostNatPort
-							} else {
-								delRule.ExtHostAddress = msg.ExtHostAddress
-								delRule.ExtHostPort = msg.ExtHostPort
-							}
+			if session.lastHeard.Before(time.Now()) {
+				if (session.Protocol == 6 && time.Since(session.lastHeard) > tcpTimeout) ||
+					(session.Protocol != 6 && time.Since(session.lastHeard) > otherTimeout) {
+					// inactive session
+					delRule := &nat_api.Nat44DelSession{
+						Flags:    nat_api.NAT_IS_INSIDE,
+						Address:  session.InsideIPAddress,
+						Port:     session.InsidePort,
+						Protocol: uint8(session.Protocol),
+						VrfID:    session.vrfID,
+					}
+					if session.Flags&nat_api.NAT_IS_EXT_HOST_VALID != 0 {
+						delRule.Flags |= nat_api.NAT_IS_EXT_HOST_VALID
+
+						if session.Flags&nat_api.NAT_IS_TWICE_NAT != 0 {
+							delRule.ExtHostAddress = session.ExtHostNatAddress
+							delRule.ExtHostPort = session.ExtHostNatPort
+						} else {
+							delRule.ExtHostAddress = session.ExtHostAddress
+							delRule.ExtHostPort = session.ExtHostPort
 						}
-
-						delRules = append(delRules, delRule)
 					}
+
+					delRules = append(delRules, delRule)
 				}
 			}
-
 		}
 
 		rndr.Log.Debugf("There are %d TCP / %d other NAT sessions, %d will be deleted", tcpCount, otherCount, len(delRules))
@@ -690,7 +939,9 @@ func (rndr *Renderer) idleNATSessionCleanup() {
 		// delete the old sessions
 		for _, r := range delRules {
 			msg := &nat_api.Nat44DelSessionReply{}
+			rndr.vppMutex.Lock()
 			err := rndr.GoVPPChan.SendRequest(r).ReceiveReply(msg)
+			rndr.vppMutex.Unlock()
 			if err != nil || msg.Retval != 0 {
 				rndr.Log.Warnf("Error by deleting NAT session: %v, retval=%d, req: %v", err, msg.Retval, r)
 				atomic.AddUint64(&natSessionDeleteErrorCount, 1)
@@ -707,6 +958,62 @@ func (rndr *Renderer) idleNATSessionCleanup() {
 	}
 }
 
+// natSession is a NAT session dumped from VPP.
+type natSession struct {
+	*nat_api.Nat44UserSessionDetails
+	vrfID     uint32    // VRF of the NAT user
+	lastHeard time.Time // LastHeard converted to the wall-clock time
+}
+
+// dumpNATSessions dumps NAT sessions of all NAT users from VPP.
+func (rndr *Renderer) dumpNATSessions() (sessions []*natSession) {
+	rndr.vppMutex.Lock()
+	defer rndr.vppMutex.Unlock()
+
+	natUsers := make([]*nat_api.Nat44UserDetails, 0)
+
+	// dump NAT users
+	req1 := &nat_api.Nat44UserDump{}
+	reqCtx1 := rndr.GoVPPChan.SendMultiRequest(req1)
+	for {
+		msg := &nat_api.Nat44UserDetails{}
+		stop, err := reqCtx1.ReceiveReply(msg)
+		if stop {
+			break // break out of the loop
+		}
+		if err != nil {
+			rndr.Log.Errorf("Error by dumping NAT users: %v", err)
+		}
+		natUsers = append(natUsers, msg)
+	}
+
+	// dump NAT sessions per user
+	for _, natUser := range natUsers {
+		req2 := &nat_api.Nat44UserSessionDump{
+			IPAddress: natUser.IPAddress,
+			VrfID:     natUser.VrfID,
+		}
+		reqCtx2 := rndr.GoVPPChan.SendMultiRequest(req2)
+
+		for {
+			msg := &nat_api.Nat44UserSessionDetails{}
+			stop, err := reqCtx2.ReceiveReply(msg)
+			if stop {
+				break // break out of the loop
+			}
+			if err != nil {
+				rndr.Log.Errorf("Error by dumping NAT sessions: %v", err)
+			}
+			sessions = append(sessions, &natSession{
+				Nat44UserSessionDetails: msg,
+				vrfID:                   natUser.VrfID,
+				lastHeard:               rndr.vppStartTime.Add(time.Duration(msg.LastHeard) * time.Second),
+			})
+		}
+	}
+	return sessions
+}
+
 func tcpNatSessionsGauge() float64 {
 	return float64(atomic.LoadUint64(&tcpNatSessionCount))
 }
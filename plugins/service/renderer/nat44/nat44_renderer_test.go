@@ -17,19 +17,26 @@ package nat44_test
 import (
 	"net"
 	"testing"
+	"time"
 
 	. "github.com/americanbinary/vpp/mock/natplugin"
 	. "github.com/onsi/gomega"
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
 
 	"github.com/americanbinary/vpp/mock/ipnet"
 	"github.com/americanbinary/vpp/mock/localclient"
 	"github.com/americanbinary/vpp/plugins/contivconf"
 	"github.com/americanbinary/vpp/plugins/contivconf/config"
+	controller "github.com/americanbinary/vpp/plugins/controller/api"
 	nodeconfigcrd "github.com/americanbinary/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
 	epmodel "github.com/americanbinary/vpp/plugins/ksr/model/endpoints"
+	epslicemodel "github.com/americanbinary/vpp/plugins/ksr/model/endpointslice"
+	nodemodel "github.com/americanbinary/vpp/plugins/ksr/model/node"
 	svcmodel "github.com/americanbinary/vpp/plugins/ksr/model/service"
 	"github.com/americanbinary/vpp/plugins/nodesync"
 	"github.com/americanbinary/vpp/plugins/podmanager"
+	policy_renderer "github.com/americanbinary/vpp/plugins/policy/renderer"
+	policy_acl "github.com/americanbinary/vpp/plugins/policy/renderer/acl"
 	svc_config "github.com/americanbinary/vpp/plugins/service/config"
 	svc_processor "github.com/americanbinary/vpp/plugins/service/processor"
 	svc_renderer "github.com/americanbinary/vpp/plugins/service/renderer"
@@ -47,6 +54,9 @@ const (
 
 	// extected NAT loopback IP for master based on default config
 	natLoopbackIP = "10.1.1.254"
+
+	// name of the ACL enforcing source ranges of LoadBalancer services
+	sourceRangesACL = "service-source-ranges"
 )
 
 var (
@@ -126,11 +136,15 @@ func initTest(testName string, config *config.Config, localEndpointWeight uint8,
 	// transactions
 	data.txnTracker = localclient.NewTxnTracker(data.natPlugin.ApplyTxn)
 
+	// Service configuration shared by the processor and the renderer.
+	svcConfig := &svc_config.Config{ServiceLocalEndpointWeight: localEndpointWeight}
+	data.SVCProcessor.Config = svcConfig
+
 	// Prepare NAT44 Renderer.
 	data.renderer = &nat44.Renderer{
 		Deps: nat44.Deps{
 			Log:              data.Logger,
-			Config:           &svc_config.Config{ServiceLocalEndpointWeight: localEndpointWeight},
+			Config:           svcConfig,
 			ContivConf:       data.ContivConf,
 			IPAM:             data.IPAM,
 			IPNet:            data.IPNet,
@@ -959,6 +973,7 @@ func TestMultipleServicesWithMultiplePortsAndResync(t *testing.T) {
 	data.SVCProcessor = &svc_processor.ServiceProcessor{
 		Deps: svc_processor.Deps{
 			Log:          data.Logger,
+			Config:       &svc_config.Config{ServiceLocalEndpointWeight: localEndpointWeight},
 			ServiceLabel: data.ServiceLabel,
 			ContivConf:   data.ContivConf,
 			IPAM:         data.IPAM,
@@ -1933,3 +1948,809 @@ func TestLocalServicePolicy(t *testing.T) {
 	Expect(data.SVCProcessor.Close()).To(BeNil())
 	Expect(data.renderer.Close()).To(BeNil())
 }
+
+func TestLoadBalancerSourceRanges(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(true)
+	data := initTest("TestLoadBalancerSourceRanges", config, localEndpointWeight, false)
+
+	// Startup resync.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.GetACL(sourceRangesACL)).To(BeNil())
+
+	// Add LoadBalancer service with source ranges.
+	service1 := &svcmodel.Service{
+		Name:                     "service1",
+		Namespace:                renderer_testing.Namespace1,
+		ServiceType:              "LoadBalancer",
+		ExternalTrafficPolicy:    "Cluster",
+		ClusterIp:                "10.96.0.1",
+		ExternalIps:              []string{"20.20.20.20"},
+		LbIngressIps:             []string{"30.30.30.30"},
+		LoadbalancerSourceRanges: []string{"192.168.100.0/24", "2001:db8::/64", "not-a-cidr", " 10.10.0.0/16"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+				NodePort: 30080,
+			},
+		},
+	}
+
+	updateEv1 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add endpoints.
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod3IP.String(),
+						NodeName: renderer_testing.WorkerLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: renderer_testing.Pod3.Namespace,
+							Name:      renderer_testing.Pod3.Name,
+						},
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+
+	updateEv2 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Check the ACL enforcing the source ranges.
+	checkSourceRangesACL := func() {
+		acl := data.natPlugin.GetACL(sourceRangesACL)
+		Expect(acl).ToNot(BeNil())
+		Expect(acl.Interfaces.Ingress).To(Equal([]string{mainIfName, OtherIfName, OtherIfName2}))
+		Expect(acl.Interfaces.Egress).To(BeEmpty())
+		Expect(acl.Rules).To(HaveLen(5))
+		// only the LB ingress IP is restricted, not the external IP from the spec
+		for ruleIdx, srcNet := range []string{"192.168.100.0/24", "10.10.0.0/16", "0.0.0.0/0"} {
+			rule := acl.Rules[ruleIdx]
+			if ruleIdx < 2 {
+				Expect(rule.Action).To(Equal(vpp_acl.ACL_Rule_REFLECT))
+			} else {
+				Expect(rule.Action).To(Equal(vpp_acl.ACL_Rule_DENY))
+			}
+			Expect(rule.IpRule.Ip.SourceNetwork).To(Equal(srcNet))
+			Expect(rule.IpRule.Ip.DestinationNetwork).To(Equal("30.30.30.30/32"))
+			Expect(rule.IpRule.Tcp).ToNot(BeNil())
+			Expect(rule.IpRule.Tcp.DestinationPortRange.LowerPort).To(BeEquivalentTo(80))
+			Expect(rule.IpRule.Tcp.DestinationPortRange.UpperPort).To(BeEquivalentTo(80))
+			Expect(rule.IpRule.Udp).To(BeNil())
+		}
+		// the rest of the traffic is permitted (and reflected)
+		Expect(acl.Rules[3].Action).To(Equal(vpp_acl.ACL_Rule_REFLECT))
+		Expect(acl.Rules[3].IpRule.Ip.SourceNetwork).To(Equal("0.0.0.0/0"))
+		Expect(acl.Rules[3].IpRule.Ip.DestinationNetwork).To(Equal("0.0.0.0/0"))
+		Expect(acl.Rules[3].IpRule.Tcp).To(BeNil())
+		Expect(acl.Rules[4].Action).To(Equal(vpp_acl.ACL_Rule_REFLECT))
+		Expect(acl.Rules[4].IpRule.Ip.SourceNetwork).To(Equal("::/0"))
+		Expect(acl.Rules[4].IpRule.Ip.DestinationNetwork).To(Equal("::/0"))
+	}
+	checkSourceRangesACL()
+
+	// Resync should render the same ACL.
+	resyncEv2, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	checkSourceRangesACL()
+
+	// Source ranges are ignored for non-LoadBalancer services.
+	service1.ServiceType = "ClusterIP"
+	updateEv3 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.GetACL(sourceRangesACL)).To(BeNil())
+
+	// Restore the LoadBalancer type.
+	service1.ServiceType = "LoadBalancer"
+	updateEv4 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	checkSourceRangesACL()
+
+	// Remove the service.
+	updateEv5 := data.Datasync.DeleteEvent(svcmodel.Key(service1.Name, service1.Namespace))
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.GetACL(sourceRangesACL)).To(BeNil())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestLoadBalancerSourceRangesWithPolicies(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(true)
+	data := initTest("TestLoadBalancerSourceRangesWithPolicies", config, localEndpointWeight, false)
+
+	// Prepare ACL Renderer of the policy plugin, sharing transactions with the NAT44 Renderer.
+	updateTxnFactory := data.Txn.UpdateFactory(data.txnTracker)
+	aclRenderer := &policy_acl.Renderer{
+		Deps: policy_acl.Deps{
+			Log:              data.Logger,
+			ContivConf:       data.ContivConf,
+			IPNet:            data.IPNet,
+			ResyncTxnFactory: data.Txn.ResyncFactory(data.txnTracker),
+			UpdateTxnFactory: func() controller.UpdateOperations {
+				return updateTxnFactory("")
+			},
+			ExternalACL: data.renderer,
+		},
+	}
+	Expect(aclRenderer.Init()).To(BeNil())
+
+	// Pod1 with ingress policy - makes the global table non-empty.
+	_, anyNet, _ := net.ParseCIDR("0.0.0.0/0")
+	_, allowedNet, _ := net.ParseCIDR("10.10.0.0/16")
+	ingress := []*policy_renderer.ContivRule{
+		{
+			Action:      policy_renderer.ActionPermit,
+			SrcNetwork:  anyNet,
+			DestNetwork: allowedNet,
+			Protocol:    policy_renderer.TCP,
+		},
+		{
+			Action:      policy_renderer.ActionDeny,
+			SrcNetwork:  anyNet,
+			DestNetwork: anyNet,
+			Protocol:    policy_renderer.ANY,
+		},
+	}
+	pod1Net := &net.IPNet{IP: pod1IP, Mask: net.CIDRMask(32, 32)}
+
+	// Both renderers react to every event (services before policies).
+	checkIngressACLs := func(sourceRangesEnforced bool) {
+		reflectiveACL := policy_acl.ACLNamePrefix + policy_acl.ReflectiveACLName
+		for _, ifName := range []string{mainIfName, OtherIfName, OtherIfName2} {
+			if sourceRangesEnforced {
+				Expect(data.natPlugin.GetIngressACLs(ifName)).To(Equal([]string{sourceRangesACL}))
+			} else {
+				Expect(data.natPlugin.GetIngressACLs(ifName)).To(Equal([]string{reflectiveACL}))
+			}
+		}
+		for _, ifName := range []string{vxlanIfName, hostInterIfName, renderer_testing.Pod1If} {
+			Expect(data.natPlugin.GetIngressACLs(ifName)).To(Equal([]string{reflectiveACL}))
+		}
+	}
+	update := func(event controller.Event) {
+		Expect(data.SVCProcessor.Update(event)).To(BeNil())
+		Expect(aclRenderer.NewTxn(false).Commit()).To(BeNil())
+		Expect(data.Txn.Commit()).To(BeNil())
+	}
+	resync := func() {
+		resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+		Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+		Expect(aclRenderer.NewTxn(true).Render(renderer_testing.Pod1, pod1Net, ingress, nil, false).Commit()).To(BeNil())
+		Expect(data.Txn.Commit()).To(BeNil())
+	}
+
+	// Startup resync.
+	resync()
+	checkIngressACLs(false)
+
+	// Add LoadBalancer service with source ranges.
+	service1 := &svcmodel.Service{
+		Name:                     "service1",
+		Namespace:                renderer_testing.Namespace1,
+		ServiceType:              "LoadBalancer",
+		ExternalTrafficPolicy:    "Cluster",
+		ClusterIp:                "10.96.0.1",
+		LbIngressIps:             []string{"30.30.30.30"},
+		LoadbalancerSourceRanges: []string{"192.168.100.0/24"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+				NodePort: 30080,
+			},
+		},
+	}
+	update(data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1))
+
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod3IP.String(),
+						NodeName: renderer_testing.WorkerLabel,
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	update(data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1))
+
+	// The source-ranges ACL replaces the reflective ACL on the main and other interfaces.
+	checkIngressACLs(true)
+	acl := data.natPlugin.GetACL(sourceRangesACL)
+	Expect(acl).ToNot(BeNil())
+	for _, rule := range acl.Rules {
+		// nothing is permitted without reflection
+		Expect(rule.Action).ToNot(Equal(vpp_acl.ACL_Rule_PERMIT))
+	}
+
+	// Resync should render the same ACLs.
+	resync()
+	checkIngressACLs(true)
+
+	// Remove the service - the reflective ACL returns to the main and other interfaces.
+	update(data.Datasync.DeleteEvent(svcmodel.Key(service1.Name, service1.Namespace)))
+	checkIngressACLs(false)
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestHealthCheckNodePort(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(false)
+	data := initTest("TestHealthCheckNodePort", config, localEndpointWeight, false)
+
+	// Startup resync.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add LoadBalancer service with the NodeLocal traffic policy.
+	service1 := &svcmodel.Service{
+		Name:                  "service1",
+		Namespace:             renderer_testing.Namespace1,
+		ServiceType:           "LoadBalancer",
+		ExternalTrafficPolicy: "Local",
+		ClusterIp:             "10.96.0.1",
+		LbIngressIps:          []string{"30.30.30.30"},
+		HealthCheckNodePort:   32000,
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+				NodePort: 30080,
+			},
+		},
+	}
+	updateEv1 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod1IP.String(),
+						NodeName: renderer_testing.MasterLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: renderer_testing.Pod1.Namespace,
+							Name:      renderer_testing.Pod1.Name,
+						},
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	updateEv2 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Health-check node port is forwarded from the node IP to the host stack.
+	healthCheckMapping := &StaticMapping{
+		ExternalIP:   nodeIP.IP,
+		ExternalPort: 32000,
+		Protocol:     svc_renderer.TCP,
+		Locals: []*Local{
+			{
+				VrfID:       renderer_testing.MainVrfID,
+				IP:          mgmtIP,
+				Port:        32000,
+				Probability: 0,
+			},
+		},
+	}
+	Expect(data.natPlugin.HasStaticMapping(healthCheckMapping)).To(BeTrue())
+
+	// Resync should render the same mapping.
+	resyncEv2, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.HasStaticMapping(healthCheckMapping)).To(BeTrue())
+
+	// The health-check node port is not used with the Cluster traffic policy.
+	service1.ExternalTrafficPolicy = "Cluster"
+	updateEv3 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.HasStaticMapping(healthCheckMapping)).To(BeFalse())
+
+	// Restore the NodeLocal traffic policy and remove the service.
+	service1.ExternalTrafficPolicy = "Local"
+	updateEv4 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.HasStaticMapping(healthCheckMapping)).To(BeTrue())
+	updateEv5 := data.Datasync.DeleteEvent(svcmodel.Key(service1.Name, service1.Namespace))
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.HasStaticMapping(healthCheckMapping)).To(BeFalse())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestTopologyAwareWeights(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 8
+	config := defaultConfig(true)
+	data := initTest("TestTopologyAwareWeights", config, localEndpointWeight, false)
+	data.SVCProcessor.Config.ServiceZoneEndpointWeight = 4
+	data.SVCProcessor.Config.ServiceRegionEndpointWeight = 2
+
+	const (
+		worker2Label = "worker2"
+		worker3Label = "worker3"
+	)
+	var (
+		pod4IP = net.ParseIP("10.3.1.1")
+		pod5IP = net.ParseIP("10.4.1.1")
+	)
+	topologyKeyPrefixes := []string{epmodel.KeyPrefix(), svcmodel.KeyPrefix(), nodemodel.KeyPrefix()}
+
+	// Nodes with topology labels.
+	newNode := func(name, zone, region string) *nodemodel.Node {
+		return &nodemodel.Node{
+			Name: name,
+			Labels: map[string]string{
+				"topology.kubernetes.io/zone":   zone,
+				"topology.kubernetes.io/region": region,
+			},
+		}
+	}
+	for _, node := range []*nodemodel.Node{
+		newNode(renderer_testing.MasterLabel, "zone1", "region1"),
+		newNode(renderer_testing.WorkerLabel, "zone1", "region1"),
+		newNode(worker2Label, "zone2", "region1"),
+		newNode(worker3Label, "zone3", "region2"),
+	} {
+		data.Datasync.Put(nodemodel.Key(node.Name), node)
+	}
+
+	// Service with backends in different parts of the cluster topology.
+	service1 := &svcmodel.Service{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		ClusterIp: "10.96.0.1",
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+		},
+	}
+	data.Datasync.Put(svcmodel.Key(service1.Name, service1.Namespace), service1)
+
+	endpointAddr := func(ip net.IP, nodeName string) *epmodel.EndpointSubset_EndpointAddress {
+		return &epmodel.EndpointSubset_EndpointAddress{
+			Ip:       ip.String(),
+			NodeName: nodeName,
+		}
+	}
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					endpointAddr(pod1IP, renderer_testing.MasterLabel),
+					endpointAddr(pod3IP, renderer_testing.WorkerLabel),
+					endpointAddr(pod4IP, worker2Label),
+					endpointAddr(pod5IP, worker3Label),
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	data.Datasync.Put(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+
+	// Startup resync.
+	resyncEv, _ := data.Datasync.ResyncEvent(topologyKeyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	staticMapping := func(localWeight, sameZoneWeight, otherZoneWeight, otherRegionWeight uint8) *StaticMapping {
+		return &StaticMapping{
+			ExternalIP:   net.ParseIP("10.96.0.1"),
+			ExternalPort: 80,
+			Protocol:     svc_renderer.TCP,
+			Locals: []*Local{
+				{
+					VrfID:       renderer_testing.PodVrfID,
+					IP:          pod1IP,
+					Port:        8080,
+					Probability: localWeight,
+				},
+				{
+					VrfID:       renderer_testing.PodVrfID,
+					IP:          pod3IP,
+					Port:        8080,
+					Probability: sameZoneWeight,
+				},
+				{
+					VrfID:       renderer_testing.PodVrfID,
+					IP:          pod4IP,
+					Port:        8080,
+					Probability: otherZoneWeight,
+				},
+				{
+					VrfID:       renderer_testing.PodVrfID,
+					IP:          pod5IP,
+					Port:        8080,
+					Probability: otherRegionWeight,
+				},
+			},
+		}
+	}
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(8, 4, 2, 1))).To(BeTrue())
+
+	// Override weights via service annotations (invalid values are ignored).
+	service1.Annotations = map[string]string{
+		"contivpp.io/zone-endpoint-weight":  "3",
+		"contivpp.io/local-endpoint-weight": "0",
+	}
+	updateEv1 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(8, 3, 2, 1))).To(BeTrue())
+
+	// Move worker2 into the same zone as this node.
+	updateEv2 := data.Datasync.PutEvent(nodemodel.Key(worker2Label), newNode(worker2Label, "zone1", "region1"))
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(8, 3, 3, 1))).To(BeTrue())
+
+	// Resync should render the same weights.
+	resyncEv2, _ := data.Datasync.ResyncEvent(topologyKeyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(8, 3, 3, 1))).To(BeTrue())
+
+	// Without topology of this node only the local backend is preferred.
+	updateEv3 := data.Datasync.DeleteEvent(nodemodel.Key(renderer_testing.MasterLabel))
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(8, 1, 1, 1))).To(BeTrue())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestTerminatingBackends(t *testing.T) {
+	RegisterTestingT(t)
+	config := defaultConfig(true)
+	data := initTest("TestTerminatingBackends", config, 1, false)
+
+	keyPrefixes := []string{epmodel.KeyPrefix(), epslicemodel.KeyPrefix(), svcmodel.KeyPrefix()}
+
+	// Service with one backend in the Endpoints and three in the EndpointSlice.
+	service1 := &svcmodel.Service{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		ClusterIp: "10.96.0.1",
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+		},
+	}
+	data.Datasync.Put(svcmodel.Key(service1.Name, service1.Namespace), service1)
+
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{Ip: pod1IP.String()},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	data.Datasync.Put(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+
+	endpoint := func(ip net.IP, ready, serving, terminating bool) *epslicemodel.Endpoint {
+		return &epslicemodel.Endpoint{
+			Addresses: []string{ip.String()},
+			Conditions: &epslicemodel.EndpointConditions{
+				Ready:       ready,
+				Serving:     serving,
+				Terminating: terminating,
+			},
+		}
+	}
+	slice1 := &epslicemodel.EndpointSlice{
+		Name:        "service1-abcde",
+		Namespace:   renderer_testing.Namespace1,
+		ServiceName: "service1",
+		AddressType: "IPv4",
+		Endpoints: []*epslicemodel.Endpoint{
+			endpoint(pod1IP, true, true, false),   // ready
+			endpoint(pod2IP, false, true, true),   // terminating
+			endpoint(pod3IP, false, false, false), // not ready
+		},
+		Ports: []*epslicemodel.EndpointPort{
+			{
+				Name:     "http",
+				Port:     8080,
+				Protocol: "TCP",
+			},
+		},
+	}
+	data.Datasync.Put(epslicemodel.Key(slice1.Name, slice1.Namespace), slice1)
+
+	// Startup resync.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	local := func(ip net.IP, probability uint8) *Local {
+		return &Local{
+			VrfID:       renderer_testing.PodVrfID,
+			IP:          ip,
+			Port:        8080,
+			Probability: probability,
+		}
+	}
+	staticMapping := func(locals ...*Local) *StaticMapping {
+		return &StaticMapping{
+			ExternalIP:   net.ParseIP("10.96.0.1"),
+			ExternalPort: 80,
+			Protocol:     svc_renderer.TCP,
+			Locals:       locals,
+		}
+	}
+
+	// Terminating backend is drained, not-ready backend is not used.
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 1), local(pod2IP, 0)))).To(BeTrue())
+
+	// No ready backends left - terminating backends are used for new connections.
+	slice2 := &epslicemodel.EndpointSlice{
+		Name:        slice1.Name,
+		Namespace:   slice1.Namespace,
+		ServiceName: slice1.ServiceName,
+		AddressType: slice1.AddressType,
+		Endpoints: []*epslicemodel.Endpoint{
+			endpoint(pod1IP, false, false, false),
+			endpoint(pod2IP, false, true, true),
+			endpoint(pod3IP, false, true, true),
+		},
+		Ports: slice1.Ports,
+	}
+	updateEv1 := data.Datasync.PutEvent(epslicemodel.Key(slice2.Name, slice2.Namespace), slice2)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod2IP, 1), local(pod3IP, 1)))).To(BeTrue())
+
+	// Resync should render the same configuration.
+	resyncEv2, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod2IP, 1), local(pod3IP, 1)))).To(BeTrue())
+
+	// Without endpoint slices the Endpoints are used.
+	updateEv2 := data.Datasync.DeleteEvent(epslicemodel.Key(slice2.Name, slice2.Namespace))
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 0)))).To(BeTrue())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+// TestDrainingBackends tests draining of NAT sessions of backends removed from a service.
+func TestDrainingBackends(t *testing.T) {
+	RegisterTestingT(t)
+	config := defaultConfig(true)
+	data := initTest("TestDrainingBackends", config, 1, false)
+	data.renderer.Config.NATSessionDrainTimeout = 60
+
+	// Service with two backends.
+	service1 := &svcmodel.Service{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		ClusterIp: "10.96.0.1",
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+		},
+	}
+	data.Datasync.Put(svcmodel.Key(service1.Name, service1.Namespace), service1)
+
+	endpoints := func(ips ...net.IP) *epmodel.Endpoints {
+		eps := &epmodel.Endpoints{
+			Name:      "service1",
+			Namespace: renderer_testing.Namespace1,
+			EndpointSubsets: []*epmodel.EndpointSubset{
+				{
+					Ports: []*epmodel.EndpointSubset_EndpointPort{
+						{
+							Name:     "http",
+							Port:     8080,
+							Protocol: "TCP",
+						},
+					},
+				},
+			},
+		}
+		for _, ip := range ips {
+			eps.EndpointSubsets[0].Addresses = append(eps.EndpointSubsets[0].Addresses,
+				&epmodel.EndpointSubset_EndpointAddress{Ip: ip.String()})
+		}
+		return eps
+	}
+	eps1 := endpoints(pod1IP, pod2IP)
+	data.Datasync.Put(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+
+	// Startup resync.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	local := func(ip net.IP, probability uint8) *Local {
+		return &Local{
+			VrfID:       renderer_testing.PodVrfID,
+			IP:          ip,
+			Port:        8080,
+			Probability: probability,
+		}
+	}
+	staticMapping := func(locals ...*Local) *StaticMapping {
+		return &StaticMapping{
+			ExternalIP:   net.ParseIP("10.96.0.1"),
+			ExternalPort: 80,
+			Protocol:     svc_renderer.TCP,
+			Locals:       locals,
+		}
+	}
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 1), local(pod2IP, 1)))).To(BeTrue())
+	Expect(data.renderer.GetDrainingBackends()).To(BeEmpty())
+
+	// Removed backend is kept for established sessions.
+	eps2 := endpoints(pod1IP)
+	updateEv1 := data.Datasync.PutEvent(epmodel.Key(eps2.Name, eps2.Namespace), eps2)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 1), local(pod2IP, 0)))).To(BeTrue())
+
+	draining := data.renderer.GetDrainingBackends()
+	Expect(draining).To(HaveLen(1))
+	Expect(draining[0].ServiceID).To(Equal(svcmodel.ID{Name: service1.Name, Namespace: service1.Namespace}))
+	Expect(draining[0].Backends).To(HaveLen(1))
+	Expect(draining[0].Backends[0].ServicePort).To(Equal("http"))
+	Expect(draining[0].Backends[0].IP.Equal(pod2IP)).To(BeTrue())
+	Expect(draining[0].Backends[0].Port).To(BeEquivalentTo(8080))
+	Expect(draining[0].Backends[0].Sessions).To(Equal(-1))
+	Expect(draining[0].Backends[0].Deadline.Sub(draining[0].Backends[0].Since)).To(Equal(time.Minute))
+
+	// Backend is not removed before the drain timeout expires.
+	Expect(data.renderer.RemoveDrainedBackends(time.Now())).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 1), local(pod2IP, 0)))).To(BeTrue())
+	Expect(data.renderer.GetDrainingBackends()).To(HaveLen(1))
+
+	// Backend added back is no longer drained.
+	updateEv2 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 1), local(pod2IP, 1)))).To(BeTrue())
+	Expect(data.renderer.GetDrainingBackends()).To(BeEmpty())
+
+	// Remove the backend again - draining survives resync.
+	updateEv3 := data.Datasync.PutEvent(epmodel.Key(eps2.Name, eps2.Namespace), eps2)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	resyncEv2, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 1), local(pod2IP, 0)))).To(BeTrue())
+	Expect(data.renderer.GetDrainingBackends()).To(HaveLen(1))
+
+	// Backend is removed once the drain timeout expires.
+	Expect(data.renderer.RemoveDrainedBackends(time.Now().Add(2 * time.Minute))).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+	Expect(data.natPlugin.HasStaticMapping(staticMapping(local(pod1IP, 0)))).To(BeTrue())
+	Expect(data.renderer.GetDrainingBackends()).To(BeEmpty())
+
+	// Without active backends left, there is nothing to drain.
+	eps3 := endpoints()
+	updateEv4 := data.Datasync.PutEvent(epmodel.Key(eps3.Name, eps3.Namespace), eps3)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(0))
+	Expect(data.renderer.GetDrainingBackends()).To(BeEmpty())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/americanbinary/vpp/plugins/contivconf"
@@ -87,6 +88,7 @@ type localBackend struct {
 	ip             net.IP
 	portForwards   []*portForward
 	useHostNetwork bool
+	weight         uint8
 }
 
 // remoteBackend holds information about a service backend located on remote node.
@@ -95,6 +97,7 @@ type remoteBackend struct {
 	nodeID         uint32
 	nodeIP         net.IP
 	useHostNetwork bool
+	weight         uint8
 }
 
 type localBackendKey [16]byte  // 16-byte IP
@@ -314,7 +317,7 @@ func (r *Renderer) renderService(service *renderer.ContivService, oper operation
 		}
 		segmentLists = append(segmentLists,
 			&vpp_srv6.Policy_SegmentList{
-				Weight:   1,
+				Weight:   uint32(localBackend.weight),
 				Segments: segments,
 			})
 	}
@@ -328,7 +331,7 @@ func (r *Renderer) renderService(service *renderer.ContivService, oper operation
 			}
 			segmentLists = append(segmentLists,
 				&vpp_srv6.Policy_SegmentList{
-					Weight:   1,
+					Weight:   uint32(remoteBackend.weight),
 					Segments: segments,
 				})
 		}
@@ -421,6 +424,20 @@ func (r *Renderer) renderService(service *renderer.ContivService, oper operation
 				r.Log.Warnf("pod %v not found in local pods list", podID)
 				continue
 			}
+			if len(service.SourceRanges) > 0 && service.LBIngressIPs.Has(serviceIP) {
+				// drop traffic to the LB ingress IP from sources outside of the allowed ranges
+				// (raw PREROUTING chain is traversed before the port forwarding)
+				srcRangesCh := r.getPodSourceRangesRuleChain(pod, serviceIP, updateConfig)
+				for _, rule := range r.getServiceSourceRangesRules(service, serviceIP) {
+					if oper == serviceAdd {
+						srcRangesCh.Rules = sliceAddIfNotExists(srcRangesCh.Rules, rule)
+					} else {
+						srcRangesCh.Rules = sliceRemove(srcRangesCh.Rules, rule)
+					}
+				}
+				key = linux_iptables.RuleChainKey(srcRangesCh.Name)
+				updateConfig[key] = srcRangesCh
+			}
 			for _, pf := range backend.portForwards {
 				// add / del an iptables rule into pod's PREROUTING chain (external traffic)
 				// and OUTPUT chain (local, pod-to-itself traffic)
@@ -484,6 +501,10 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 
 	for servicePortName, servicePort := range service.Ports {
 		for _, backend := range service.Backends[servicePortName] {
+			if backend.Terminating {
+				// draining of established connections is not supported, skip
+				continue
+			}
 			if backend.Local {
 				// collect local backend info
 				if backend.HostNetwork {
@@ -491,10 +512,11 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 					lb := &localBackend{
 						useHostNetwork: true,
 						ip:             backend.IP,
+						weight:         backend.Weight,
 					}
 					localBackends[lb.Key()] = lb
 				} else {
-					lb := &localBackend{ip: backend.IP}
+					lb := &localBackend{ip: backend.IP, weight: backend.Weight}
 					if servicePort.Port != backend.Port {
 						previousForwards := lb.portForwards
 						if previousLB, exists := localBackends[lb.Key()]; exists {
@@ -534,6 +556,7 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 						nodeID:         nodeID,
 						nodeIP:         nodeIP,
 						useHostNetwork: backend.HostNetwork,
+						weight:         backend.Weight,
 					}
 					remoteBackends[rb.Key()] = rb
 				}
@@ -588,6 +611,76 @@ func (r *Renderer) getServicePortForwardRule(serviceIP net.IP, pf *portForward)
 		serviceIP.String()+getHostPrefix(serviceIP), proto, proto, pf.from, pf.to)
 }
 
+// getPodSourceRangesRuleChain returns the config of the pod-local iptables rule chain
+// enforcing source ranges of services with external IPs of the same IP version as <serviceIP>.
+// The chain is looked up the same way as by getPodPFRuleChain.
+func (r *Renderer) getPodSourceRangesRuleChain(
+	pod *podmanager.LocalPod, serviceIP net.IP, currentConfig controller.KeyValuePairs) *linux_iptables.RuleChain {
+
+	protocol := linux_iptables.RuleChain_IPV4
+	if isIPv6(serviceIP) {
+		protocol = linux_iptables.RuleChain_IPV6
+	}
+	rchName := fmt.Sprintf("source-ranges-%s-%s", pod.ContainerID, protocol.String())
+	key := linux_iptables.RuleChainKey(rchName)
+
+	val, exists := currentConfig[key]
+	if exists && val != nil {
+		return val.(*linux_iptables.RuleChain)
+	}
+
+	val = r.ConfigRetriever.GetConfig(key)
+	if val != nil {
+		return val.(*linux_iptables.RuleChain)
+	}
+
+	ruleChain := &linux_iptables.RuleChain{
+		Name: rchName,
+		Namespace: &linux_namespace.NetNamespace{
+			Type:      linux_namespace.NetNamespace_FD,
+			Reference: pod.NetworkNamespace,
+		},
+		Protocol:  protocol,
+		Table:     linux_iptables.RuleChain_RAW,
+		ChainType: linux_iptables.RuleChain_PREROUTING,
+	}
+	return ruleChain
+}
+
+// getServiceSourceRangesRules returns iptables rules accepting traffic to the given
+// LB ingress IP of the service from the allowed source ranges and dropping the rest.
+// Ports with protocols other than TCP and UDP are not restricted.
+func (r *Renderer) getServiceSourceRangesRules(service *renderer.ContivService, serviceIP net.IP) (rules []string) {
+	var portNames []string
+	for portName := range service.Ports {
+		portNames = append(portNames, portName)
+	}
+	sort.Strings(portNames)
+
+	for _, portName := range portNames {
+		port := service.Ports[portName]
+		var proto string
+		switch port.Protocol {
+		case renderer.TCP:
+			proto = "tcp"
+		case renderer.UDP:
+			proto = "udp"
+		default:
+			continue
+		}
+		match := fmt.Sprintf("-d %s -p %s -m %s --dport %d",
+			serviceIP.String()+getHostPrefix(serviceIP), proto, proto, port.Port)
+		for _, sourceRange := range service.SourceRanges {
+			if isIPv6(sourceRange.IP) != isIPv6(serviceIP) {
+				continue
+			}
+			rules = append(rules, fmt.Sprintf("%s -s %s -j ACCEPT", match, sourceRange.String()))
+		}
+		rules = append(rules, match+" -j DROP")
+	}
+	return rules
+}
+
 // nodeIDFromNodeOrHostIP returns node ID matching with the provided node (VPP) or host (mgmt) IP.
 // If no match is found for provided IP, error is returned.
 func (r *Renderer) nodeIDFromNodeOrHostIP(ip net.IP) (uint32, error) {
@@ -288,6 +288,64 @@ func TestServiceWithBackendPortForwarding(t *testing.T) {
 	closeResources(data)
 }
 
+func TestLoadBalancerServiceWithSourceRanges(t *testing.T) {
+	RegisterTestingT(t)
+	retriever := configRetriever.NewMockConfigRetriever()
+	data := initTest("TestLoadBalancerServiceWithSourceRanges", defaultConfig(false), retriever, false)
+
+	// setup service
+	emptyResync(data)
+	service1 := &svcmodel.Service{
+		Name:                     service1Name,
+		Namespace:                renderer_testing.Namespace1,
+		ServiceType:              "LoadBalancer",
+		ExternalTrafficPolicy:    "Cluster",
+		ClusterIp:                "2096::eef9",
+		ExternalIps:              []string{"2096::2"},
+		LbIngressIps:             []string{"2096::1"},
+		LoadbalancerSourceRanges: []string{"2001:db8::/64", "10.0.0.0/8"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     defaultPort,
+			},
+		},
+	}
+	updateEv := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	pod1IP := addLocalPod(renderer_testing.Pod1, data, retriever)
+	addServiceEndpoints(service1.Name,
+		[]podEndPoint{
+			newPodEndPoint(renderer_testing.Pod1, pod1IP, renderer_testing.MasterLabel),
+		},
+		defaultPort, data,
+	)
+
+	// check filtering of the LB IP (external IPs from the spec are not filtered)
+	match := fmt.Sprintf("-d 2096::1/128 -p tcp -m tcp --dport %d", defaultPort)
+	ruleChain := &linux_iptables.RuleChain{
+		Name: "source-ranges--IPV6",
+		Namespace: &linux_namespace.NetNamespace{
+			Type: linux_namespace.NetNamespace_FD,
+		},
+		Protocol:  linux_iptables.RuleChain_IPV6,
+		Table:     linux_iptables.RuleChain_RAW,
+		ChainType: linux_iptables.RuleChain_PREROUTING,
+		Rules: []string{
+			match + " -s 2001:db8::/64 -j ACCEPT",
+			match + " -j DROP",
+		},
+	}
+	Expect(data.ruleChainHandler.RuleChains).To(ContainElement(ruleChain))
+	Expect(data.ruleChainHandler.RuleChains).To(HaveLen(1))
+
+	// cleanup
+	removeService(data, service1)
+	closeResources(data)
+}
+
 func TestServiceWithHostLocalBackend(t *testing.T) {
 	RegisterTestingT(t)
 	retriever := configRetriever.NewMockConfigRetriever()
@@ -532,6 +590,7 @@ func addServiceEndpoints(serviceName string, pods []podEndPoint, port int32, dat
 
 func addLocalPod(pod podmodel.ID, data *data, retriever *configRetriever.MockConfigRetriever) net.IP {
 	// add pod
+	data.PodManager.AddRemotePod(&podmanager.Pod{ID: pod})
 	updateEv := data.PodManager.AddPod(&podmanager.LocalPod{ID: pod})
 	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
 	Expect(data.Txn.Commit()).To(BeNil())
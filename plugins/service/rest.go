@@ -0,0 +1,47 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net/http"
+
+	"github.com/unrolled/render"
+
+	"github.com/americanbinary/vpp/plugins/service/restapi"
+)
+
+func (p *Plugin) registerRESTHandlers() {
+	if p.HTTPHandlers == nil {
+		p.Log.Warnf("No http handler provided, skipping registration of service REST handlers")
+		return
+	}
+
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLServiceDraining, p.drainingGetHandler, "GET")
+	p.Log.Infof("Service draining REST handler registered: GET %v", restapi.RestURLServiceDraining)
+}
+
+// drainingGetHandler is the GET handler for "service/draining" API.
+func (p *Plugin) drainingGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p.Log.Debug("Getting draining service backends")
+
+		draining := []restapi.DrainingService{}
+		if p.nat44Renderer != nil {
+			// draining is implemented only by the NAT44 renderer
+			draining = p.nat44Renderer.GetDrainingBackends()
+		}
+		formatter.JSON(w, http.StatusOK, draining)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restapi
+
+import (
+	"net"
+	"time"
+
+	"github.com/americanbinary/vpp/plugins/ksr/model/service"
+)
+
+const (
+	// RESTPrefix is versioned prefix for REST urls.
+	RESTPrefix = "/contiv/v1/"
+
+	// RestURLServiceDraining is versioned URL for the REST endpoint listing backends
+	// removed from services, whose NAT sessions are being drained.
+	RestURLServiceDraining = RESTPrefix + "service/draining"
+)
+
+// DrainingService lists backends removed from a service, which are kept
+// in the NAT static mappings of the service until their sessions are drained.
+type DrainingService struct {
+	ServiceID service.ID
+	Backends  []DrainingBackend
+}
+
+// DrainingBackend represents a single service backend being drained.
+type DrainingBackend struct {
+	ServicePort string // name of the service port
+	IP          net.IP
+	Port        uint16
+	Since       time.Time // when the backend was removed from the service
+	Deadline    time.Time // when the backend is removed regardless of the remaining sessions
+	Sessions    int       // active NAT sessions as of the last check, -1 if not checked yet
+}
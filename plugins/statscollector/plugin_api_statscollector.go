@@ -1,7 +1,14 @@
 package statscollector
 
-// API defines API of the stats collector plugin. Currently it only allows registering of a gauge.
+import "github.com/prometheus/client_golang/prometheus"
+
+// API defines API of the stats collector plugin. It allows registering of gauges
+// and of custom collectors.
 type API interface {
 	// RegisterGaugeFunc registers a new gauge with specific name, help string and valueFunc to report status when invoked.
 	RegisterGaugeFunc(name string, help string, valueFunc func() float64)
+
+	// RegisterCollector registers a custom collector (e.g. histogram vector) to be exposed
+	// together with the other statistics.
+	RegisterCollector(collector prometheus.Collector) error
 }
@@ -260,6 +260,17 @@ func (p *Plugin) RegisterGaugeFunc(name string, help string, valueFunc func() fl
 	}
 }
 
+// RegisterCollector registers a custom collector to be exposed together with the other statistics.
+func (p *Plugin) RegisterCollector(collector prometheus.Collector) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.Prometheus != nil {
+		return p.Prometheus.Register(prometheusStatsPath, collector)
+	}
+	return nil
+}
+
 func (p *Plugin) addNewEntry(key string, data *vpp_interfaces.InterfaceState) (newEntry *stats, created bool) {
 	var (
 		err            error